@@ -0,0 +1,37 @@
+// Package clipboard provides cross-platform access to the system clipboard
+// as plain text.
+//
+// Platform behavior:
+//   - Windows: the Win32 clipboard (CF_UNICODETEXT), called directly.
+//   - macOS: the general pasteboard through pbcopy and pbpaste, which
+//     ship with the OS.
+//   - Linux and BSD: wl-copy/wl-paste under Wayland, otherwise xclip or
+//     xsel under X11, and clip.exe/PowerShell under WSL without a display
+//     server. One of these tools must be installed.
+//
+// Headless environments (SSH sessions, containers, CI) usually have no
+// clipboard; Available reports this up front so callers can fall back to
+// printing.
+package clipboard
+
+import "errors"
+
+// ErrUnavailable is returned when there is no clipboard: no display
+// server, or no supported clipboard tool installed.
+var ErrUnavailable = errors.New("oscompat/clipboard: no clipboard available")
+
+// Available reports whether the clipboard can be used.
+func Available() bool {
+	return available()
+}
+
+// ReadText returns the text on the clipboard, or "" if the clipboard is
+// empty or holds no text.
+func ReadText() (string, error) {
+	return readText()
+}
+
+// WriteText replaces the clipboard contents with s.
+func WriteText(s string) error {
+	return writeText(s)
+}
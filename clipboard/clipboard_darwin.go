@@ -0,0 +1,52 @@
+//go:build darwin
+
+package clipboard
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// available reports whether pbcopy exists; it is missing only in unusual
+// environments such as minimal sandboxes.
+func available() bool {
+	_, err := exec.LookPath("pbcopy")
+	return err == nil
+}
+
+// readText runs pbpaste.
+func readText() (string, error) {
+	if !available() {
+		return "", ErrUnavailable
+	}
+	cmd := exec.Command("pbpaste", "-Prefer", "txt")
+	cmd.Env = utf8Env()
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("oscompat/clipboard: pbpaste: %w", err)
+	}
+	return string(out), nil
+}
+
+// writeText runs pbcopy.
+func writeText(s string) error {
+	if !available() {
+		return ErrUnavailable
+	}
+	cmd := exec.Command("pbcopy")
+	cmd.Env = utf8Env()
+	cmd.Stdin = strings.NewReader(s)
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("oscompat/clipboard: pbcopy: %w", err)
+	}
+	return nil
+}
+
+// utf8Env returns the environment with a UTF-8 locale; pbcopy and
+// pbpaste otherwise mangle non-ASCII text when LANG is unset, as it is
+// under launchd.
+func utf8Env() []string {
+	return append(os.Environ(), "LC_CTYPE=UTF-8")
+}
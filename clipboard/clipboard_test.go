@@ -0,0 +1,44 @@
+package clipboard_test
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/grokify/oscompat/clipboard"
+)
+
+func TestUnavailable(t *testing.T) {
+	if clipboard.Available() {
+		t.Skip("clipboard is available")
+	}
+	if _, err := clipboard.ReadText(); !errors.Is(err, clipboard.ErrUnavailable) {
+		t.Errorf("ReadText() error = %v, want ErrUnavailable", err)
+	}
+	if err := clipboard.WriteText("x"); !errors.Is(err, clipboard.ErrUnavailable) {
+		t.Errorf("WriteText() error = %v, want ErrUnavailable", err)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	// Overwriting the clipboard of a developer's machine is rude; run
+	// only when asked.
+	if os.Getenv("OSCOMPAT_TEST_CLIPBOARD") != "1" {
+		t.Skip("set OSCOMPAT_TEST_CLIPBOARD=1 to test the real clipboard")
+	}
+	if !clipboard.Available() {
+		t.Skip("no clipboard")
+	}
+
+	const want = "oscompat héllo 世界\nsecond line"
+	if err := clipboard.WriteText(want); err != nil {
+		t.Fatalf("WriteText() error: %v", err)
+	}
+	got, err := clipboard.ReadText()
+	if err != nil {
+		t.Fatalf("ReadText() error: %v", err)
+	}
+	if got != want {
+		t.Errorf("ReadText() = %q, want %q", got, want)
+	}
+}
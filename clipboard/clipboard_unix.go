@@ -0,0 +1,95 @@
+//go:build !windows && !darwin
+
+package clipboard
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// tool is a pair of commands that copy stdin to the clipboard and paste
+// the clipboard to stdout.
+type tool struct {
+	copy  []string
+	paste []string
+	// env is the variable that must be set for the tool to work.
+	env string
+}
+
+// tools are tried in order.
+var tools = []tool{
+	{[]string{"wl-copy"}, []string{"wl-paste", "--no-newline", "--type", "text/plain"}, "WAYLAND_DISPLAY"},
+	{[]string{"xclip", "-selection", "clipboard", "-in"}, []string{"xclip", "-selection", "clipboard", "-out"}, "DISPLAY"},
+	{[]string{"xsel", "--clipboard", "--input"}, []string{"xsel", "--clipboard", "--output"}, "DISPLAY"},
+	// WSL without WSLg: the Windows clipboard.
+	{[]string{"clip.exe"}, []string{"powershell.exe", "-NoProfile", "-Command", "[Console]::OutputEncoding = [Text.Encoding]::UTF8; Get-Clipboard -Raw"}, ""},
+}
+
+// findTool returns the first usable tool.
+func findTool() (*tool, bool) {
+	for i := range tools {
+		t := &tools[i]
+		if t.env != "" && os.Getenv(t.env) == "" {
+			continue
+		}
+		if _, err := exec.LookPath(t.copy[0]); err != nil {
+			continue
+		}
+		if _, err := exec.LookPath(t.paste[0]); err != nil {
+			continue
+		}
+		return t, true
+	}
+	return nil, false
+}
+
+// available reports whether a tool is usable.
+func available() bool {
+	_, ok := findTool()
+	return ok
+}
+
+// readText runs the paste command.
+func readText() (string, error) {
+	t, ok := findTool()
+	if !ok {
+		return "", ErrUnavailable
+	}
+	var stderr bytes.Buffer
+	cmd := exec.Command(t.paste[0], t.paste[1:]...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		// wl-paste and xclip fail when the clipboard is empty.
+		if strings.Contains(stderr.String(), "No selection") ||
+			strings.Contains(stderr.String(), "target STRING not available") ||
+			strings.Contains(stderr.String(), "Nothing is copied") {
+			return "", nil
+		}
+		return "", fmt.Errorf("oscompat/clipboard: %s: %w: %s", t.paste[0], err, strings.TrimSpace(stderr.String()))
+	}
+	s := string(out)
+	if t.copy[0] == "clip.exe" {
+		s = strings.TrimSuffix(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	}
+	return s, nil
+}
+
+// writeText runs the copy command.
+func writeText(s string) error {
+	t, ok := findTool()
+	if !ok {
+		return ErrUnavailable
+	}
+	var stderr bytes.Buffer
+	cmd := exec.Command(t.copy[0], t.copy[1:]...)
+	cmd.Stdin = strings.NewReader(s)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("oscompat/clipboard: %s: %w: %s", t.copy[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
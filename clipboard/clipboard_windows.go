@@ -0,0 +1,137 @@
+//go:build windows
+
+package clipboard
+
+import (
+	"runtime"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+var (
+	user32                     = syscall.NewLazyDLL("user32.dll")
+	kernel32                   = syscall.NewLazyDLL("kernel32.dll")
+	procOpenClipboard          = user32.NewProc("OpenClipboard")
+	procCloseClipboard         = user32.NewProc("CloseClipboard")
+	procEmptyClipboard         = user32.NewProc("EmptyClipboard")
+	procGetClipboardData       = user32.NewProc("GetClipboardData")
+	procSetClipboardData       = user32.NewProc("SetClipboardData")
+	procIsClipboardFormatAvail = user32.NewProc("IsClipboardFormatAvailable")
+	procGlobalAlloc            = kernel32.NewProc("GlobalAlloc")
+	procGlobalFree             = kernel32.NewProc("GlobalFree")
+	procGlobalLock             = kernel32.NewProc("GlobalLock")
+	procGlobalUnlock           = kernel32.NewProc("GlobalUnlock")
+	procLstrlenW               = kernel32.NewProc("lstrlenW")
+	procRtlMoveMemory          = kernel32.NewProc("RtlMoveMemory")
+)
+
+const (
+	cfUnicodeText = 13
+	gmemMoveable  = 0x0002
+
+	// openTimeout bounds how long to wait for another program to release
+	// the clipboard.
+	openTimeout = time.Second
+)
+
+// available reports whether the clipboard can be opened. Services and
+// other sessions without a window station cannot.
+func available() bool {
+	release, err := open()
+	if err != nil {
+		return false
+	}
+	release()
+	return true
+}
+
+// open opens the clipboard, retrying while another program holds it. The
+// calling goroutine stays on its OS thread until the returned function
+// closes the clipboard.
+func open() (func(), error) {
+	runtime.LockOSThread()
+	deadline := time.Now().Add(openTimeout)
+	for {
+		r, _, err := procOpenClipboard.Call(0)
+		if r != 0 {
+			return func() {
+				_, _, _ = procCloseClipboard.Call()
+				runtime.UnlockOSThread()
+			}, nil
+		}
+		if time.Now().After(deadline) {
+			runtime.UnlockOSThread()
+			return nil, err
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// readText reads CF_UNICODETEXT.
+func readText() (string, error) {
+	release, err := open()
+	if err != nil {
+		return "", ErrUnavailable
+	}
+	defer release()
+
+	if r, _, _ := procIsClipboardFormatAvail.Call(cfUnicodeText); r == 0 {
+		return "", nil
+	}
+	h, _, err := procGetClipboardData.Call(cfUnicodeText)
+	if h == 0 {
+		return "", err
+	}
+	p, _, err := procGlobalLock.Call(h)
+	if p == 0 {
+		return "", err
+	}
+	defer func() { _, _, _ = procGlobalUnlock.Call(h) }()
+
+	// The locked memory is not Go memory; copy it out rather than
+	// converting the address to a pointer.
+	n, _, _ := procLstrlenW.Call(p)
+	if n == 0 {
+		return "", nil
+	}
+	text := make([]uint16, n)
+	_, _, _ = procRtlMoveMemory.Call(uintptr(unsafe.Pointer(&text[0])), p, n*2)
+	return syscall.UTF16ToString(text), nil
+}
+
+// writeText stores s as CF_UNICODETEXT.
+func writeText(s string) error {
+	text, err := syscall.UTF16FromString(s)
+	if err != nil {
+		return err
+	}
+	release, err := open()
+	if err != nil {
+		return ErrUnavailable
+	}
+	defer release()
+
+	if r, _, err := procEmptyClipboard.Call(); r == 0 {
+		return err
+	}
+	size := uintptr(len(text)) * 2
+	h, _, err := procGlobalAlloc.Call(gmemMoveable, size)
+	if h == 0 {
+		return err
+	}
+	p, _, err := procGlobalLock.Call(h)
+	if p == 0 {
+		_, _, _ = procGlobalFree.Call(h)
+		return err
+	}
+	_, _, _ = procRtlMoveMemory.Call(p, uintptr(unsafe.Pointer(&text[0])), size)
+	_, _, _ = procGlobalUnlock.Call(h)
+
+	// On success the system owns the memory.
+	if r, _, err := procSetClipboardData.Call(cfUnicodeText, h); r == 0 {
+		_, _, _ = procGlobalFree.Call(h)
+		return err
+	}
+	return nil
+}
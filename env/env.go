@@ -0,0 +1,115 @@
+// Package env provides cross-platform environment variable access.
+//
+// Environment variable names are case-insensitive on Windows (PATH, Path,
+// and path are the same variable) and case-sensitive elsewhere. The os
+// package follows the platform for the process environment, but code that
+// manipulates environment slices, such as exec.Cmd.Env, or compares names
+// directly easily gets this wrong. The functions in this package apply the
+// platform rule everywhere.
+package env
+
+import (
+	"os"
+	"strings"
+)
+
+// Get returns the value of the variable name, or "" if it is not set.
+// On Windows name is matched case-insensitively.
+func Get(name string) string {
+	v, _ := Lookup(name)
+	return v
+}
+
+// Lookup returns the value of the variable name and whether it is set.
+// On Windows name is matched case-insensitively.
+func Lookup(name string) (string, bool) {
+	return os.LookupEnv(name)
+}
+
+// Set sets the variable name to value. On Windows this replaces a
+// variable whose name differs only in case.
+func Set(name, value string) error {
+	return os.Setenv(name, value)
+}
+
+// Unset removes the variable name. On Windows this removes a variable
+// whose name differs only in case.
+func Unset(name string) error {
+	return os.Unsetenv(name)
+}
+
+// Map returns the process environment as a map keyed by normalized name
+// (see Normalize), so lookups in the map follow the platform rule.
+func Map() map[string]string {
+	return MapOf(os.Environ())
+}
+
+// MapOf converts an environment slice of "name=value" entries to a map
+// keyed by normalized name. If a name occurs more than once, the last
+// value wins, as it does for exec.Cmd.Env.
+func MapOf(environ []string) map[string]string {
+	m := make(map[string]string, len(environ))
+	for _, kv := range environ {
+		name, value, ok := split(kv)
+		if ok {
+			m[Normalize(name)] = value
+		}
+	}
+	return m
+}
+
+// Equal reports whether a and b name the same variable on this platform.
+func Equal(a, b string) bool {
+	return Normalize(a) == Normalize(b)
+}
+
+// LookupIn returns the value of the variable name in an environment slice
+// such as exec.Cmd.Env, using the platform's name matching. The last
+// matching entry wins.
+func LookupIn(environ []string, name string) (string, bool) {
+	for i := len(environ) - 1; i >= 0; i-- {
+		n, value, ok := split(environ[i])
+		if ok && Equal(n, name) {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// SetIn returns environ with the variable name set to value. Existing
+// entries for name, in any casing the platform treats as equal, are
+// removed, so the result never carries both PATH and Path on Windows.
+// environ itself is not modified.
+func SetIn(environ []string, name, value string) []string {
+	out := UnsetIn(environ, name)
+	return append(out, name+"="+value)
+}
+
+// UnsetIn returns environ without entries for the variable name, using
+// the platform's name matching. environ itself is not modified.
+func UnsetIn(environ []string, name string) []string {
+	out := make([]string, 0, len(environ)+1)
+	for _, kv := range environ {
+		if n, _, ok := split(kv); ok && Equal(n, name) {
+			continue
+		}
+		out = append(out, kv)
+	}
+	return out
+}
+
+// split splits a "name=value" entry. Windows keeps per-drive working
+// directories in entries such as "=C:=C:\dir", so a leading "=" belongs
+// to the name.
+func split(kv string) (name, value string, ok bool) {
+	start := 0
+	if strings.HasPrefix(kv, "=") {
+		start = 1
+	}
+	i := strings.IndexByte(kv[start:], '=')
+	if i < 0 {
+		return "", "", false
+	}
+	i += start
+	return kv[:i], kv[i+1:], true
+}
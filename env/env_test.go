@@ -0,0 +1,91 @@
+package env_test
+
+import (
+	"runtime"
+	"slices"
+	"testing"
+
+	"github.com/grokify/oscompat/env"
+)
+
+func TestGetSetLookup(t *testing.T) {
+	t.Setenv("OSCOMPAT_ENV_TEST", "")
+	if err := env.Set("OSCOMPAT_ENV_TEST", "value"); err != nil {
+		t.Fatalf("Set() error: %v", err)
+	}
+	if got := env.Get("OSCOMPAT_ENV_TEST"); got != "value" {
+		t.Errorf("Get() = %q, want value", got)
+	}
+
+	v, ok := env.Lookup("oscompat_env_test")
+	if runtime.GOOS == "windows" {
+		if !ok || v != "value" {
+			t.Errorf("Lookup(lowercase) = %q, %v; want value, true", v, ok)
+		}
+	} else if ok {
+		t.Errorf("Lookup(lowercase) = %q, true; want not set", v)
+	}
+
+	if err := env.Unset("OSCOMPAT_ENV_TEST"); err != nil {
+		t.Fatalf("Unset() error: %v", err)
+	}
+	if _, ok := env.Lookup("OSCOMPAT_ENV_TEST"); ok {
+		t.Error("Lookup() after Unset() reports set")
+	}
+}
+
+func TestMap(t *testing.T) {
+	t.Setenv("OSCOMPAT_ENV_MAP", "x")
+	m := env.Map()
+	if m[env.Normalize("OSCOMPAT_ENV_MAP")] != "x" {
+		t.Errorf("Map() missing OSCOMPAT_ENV_MAP")
+	}
+}
+
+func TestMapOf(t *testing.T) {
+	m := env.MapOf([]string{"A=1", "B=x=y", "=C:=C:\\dir", "bogus", "A=2"})
+	if m["A"] != "2" {
+		t.Errorf("MapOf()[A] = %q, want last value 2", m["A"])
+	}
+	if m["B"] != "x=y" {
+		t.Errorf("MapOf()[B] = %q, want x=y", m["B"])
+	}
+	if m[env.Normalize("=C:")] != `C:\dir` {
+		t.Errorf("MapOf() did not keep drive entry: %q", m)
+	}
+	if len(m) != 3 {
+		t.Errorf("MapOf() = %q, want 3 entries", m)
+	}
+}
+
+func TestSetIn(t *testing.T) {
+	environ := []string{"Path=/old", "HOME=/home/u"}
+	got := env.SetIn(environ, "PATH", "/new")
+
+	var want []string
+	if runtime.GOOS == "windows" {
+		want = []string{"HOME=/home/u", "PATH=/new"}
+	} else {
+		want = []string{"Path=/old", "HOME=/home/u", "PATH=/new"}
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("SetIn() = %q, want %q", got, want)
+	}
+	if environ[0] != "Path=/old" {
+		t.Error("SetIn() modified its input")
+	}
+
+	v, ok := env.LookupIn(got, "PATH")
+	if !ok || v != "/new" {
+		t.Errorf("LookupIn(PATH) = %q, %v; want /new, true", v, ok)
+	}
+}
+
+func TestEqual(t *testing.T) {
+	if !env.Equal("PATH", "PATH") {
+		t.Error("Equal(PATH, PATH) = false")
+	}
+	if got, want := env.Equal("PATH", "Path"), runtime.GOOS == "windows"; got != want {
+		t.Errorf("Equal(PATH, Path) = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !windows
+
+package env
+
+// Normalize returns the canonical form of a variable name. Names are
+// case-sensitive on this platform, so name is returned unchanged.
+func Normalize(name string) string {
+	return name
+}
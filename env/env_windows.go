@@ -0,0 +1,11 @@
+//go:build windows
+
+package env
+
+import "strings"
+
+// Normalize returns the canonical form of a variable name. Names are
+// case-insensitive on Windows, so name is returned in upper case.
+func Normalize(name string) string {
+	return strings.ToUpper(name)
+}
@@ -0,0 +1,110 @@
+package fs
+
+import (
+	"os"
+	"sync"
+)
+
+// AppendOptions controls OpenAppend.
+type AppendOptions struct {
+	// Perm is the permission used when creating the file. Zero means
+	// DefaultFilePerm.
+	Perm os.FileMode
+
+	// Lock takes an exclusive lock on the file around each Write, so that
+	// writes from cooperating processes never interleave, however large.
+	// The lock is advisory: it only excludes other writers that also
+	// lock, such as other Appenders.
+	Lock bool
+}
+
+// Appender appends to a file. It is safe for concurrent use.
+//
+// Every Write lands at the end of the file, even when other processes
+// append to it too:
+//   - Unix: the file is opened with O_APPEND, so the kernel moves to the
+//     end and writes in one step. Writes by separate processes do not
+//     overwrite each other, but a large write that the kernel splits
+//     may interleave with another. NFS does not honor O_APPEND across
+//     clients.
+//   - Windows: the file is opened with FILE_APPEND_DATA access only, so
+//     every WriteFile is positioned at the end atomically, with the same
+//     caveat for split writes and SMB shares.
+//
+// Set AppendOptions.Lock when entries may be large or must never
+// interleave, as with several processes sharing one log file.
+type Appender struct {
+	mu   sync.Mutex
+	f    *os.File
+	lock bool
+}
+
+// OpenAppend opens name for appending, creating it if needed. opts may be
+// nil.
+func OpenAppend(name string, opts *AppendOptions) (*Appender, error) {
+	var o AppendOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Perm == 0 {
+		o.Perm = DefaultFilePerm
+	}
+	f, err := os.OpenFile(name, os.O_WRONLY|os.O_APPEND|os.O_CREATE, o.Perm)
+	if err != nil {
+		return nil, err
+	}
+	return &Appender{f: f, lock: o.Lock}, nil
+}
+
+// Write appends p to the file in a single write where the platform
+// allows.
+func (a *Appender) Write(p []byte) (int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !a.lock {
+		return a.f.Write(p)
+	}
+	if err := lockAppend(a.f); err != nil {
+		return 0, err
+	}
+	n, err := a.f.Write(p)
+	if unlockErr := unlockAppend(a.f); err == nil {
+		err = unlockErr
+	}
+	return n, err
+}
+
+// WriteString appends s to the file.
+func (a *Appender) WriteString(s string) (int, error) {
+	return a.Write([]byte(s))
+}
+
+// Sync commits the file to stable storage.
+func (a *Appender) Sync() error {
+	return a.f.Sync()
+}
+
+// Close closes the file.
+func (a *Appender) Close() error {
+	return a.f.Close()
+}
+
+// Name returns the name of the file as passed to OpenAppend.
+func (a *Appender) Name() string {
+	return a.f.Name()
+}
+
+// AppendFile appends data to the named file in one write, creating the
+// file with perm (DefaultFilePerm if 0) if needed. See Appender for the
+// atomicity guarantees of each platform.
+func AppendFile(name string, data []byte, perm os.FileMode) error {
+	a, err := OpenAppend(name, &AppendOptions{Perm: perm})
+	if err != nil {
+		return err
+	}
+	_, err = a.Write(data)
+	if closeErr := a.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
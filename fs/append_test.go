@@ -0,0 +1,74 @@
+package fs_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/grokify/oscompat/fs"
+)
+
+func TestAppendFile(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "log.txt")
+	for _, line := range []string{"one\n", "two\n"} {
+		if err := fs.AppendFile(name, []byte(line), 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "one\ntwo\n" {
+		t.Errorf("content = %q", got)
+	}
+}
+
+func TestOpenAppendConcurrent(t *testing.T) {
+	for _, lock := range []bool{false, true} {
+		name := filepath.Join(t.TempDir(), "log.txt")
+		const writers, lines = 4, 50
+		var wg sync.WaitGroup
+		for w := range writers {
+			// Separate Appenders stand in for separate processes.
+			a, err := fs.OpenAppend(name, &fs.AppendOptions{Lock: lock})
+			if err != nil {
+				t.Fatal(err)
+			}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() {
+					if err := a.Close(); err != nil {
+						t.Error(err)
+					}
+				}()
+				entry := strings.Repeat(string(rune('a'+w)), 1000) + "\n"
+				for range lines {
+					if _, err := a.WriteString(entry); err != nil {
+						t.Error(err)
+						return
+					}
+				}
+			}()
+		}
+		wg.Wait()
+
+		got, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entries := bytes.Split(bytes.TrimSuffix(got, []byte("\n")), []byte("\n"))
+		if len(entries) != writers*lines {
+			t.Fatalf("lock=%v: %d entries, want %d", lock, len(entries), writers*lines)
+		}
+		for _, e := range entries {
+			if len(e) != 1000 || strings.Trim(string(e), string(e[:1])) != "" {
+				t.Fatalf("lock=%v: interleaved entry %q", lock, e[:min(len(e), 40)])
+			}
+		}
+	}
+}
@@ -0,0 +1,39 @@
+//go:build !windows
+
+package fs
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockAppend takes an exclusive flock on f, waiting for other holders.
+func lockAppend(f *os.File) error {
+	return flock(f, syscall.LOCK_EX)
+}
+
+// unlockAppend releases the flock on f.
+func unlockAppend(f *os.File) error {
+	return flock(f, syscall.LOCK_UN)
+}
+
+// flock applies a flock operation, retrying when interrupted by a signal.
+func flock(f *os.File, how int) error {
+	conn, err := f.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var opErr error
+	err = conn.Control(func(fd uintptr) {
+		for {
+			opErr = syscall.Flock(int(fd), how)
+			if opErr != syscall.EINTR {
+				return
+			}
+		}
+	})
+	if err != nil {
+		return err
+	}
+	return opErr
+}
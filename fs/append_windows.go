@@ -0,0 +1,51 @@
+//go:build windows
+
+package fs
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	kernel32         = syscall.NewLazyDLL("kernel32.dll")
+	procLockFileEx   = kernel32.NewProc("LockFileEx")
+	procUnlockFileEx = kernel32.NewProc("UnlockFileEx")
+)
+
+const lockfileExclusiveLock = 0x2
+
+// appendLockOffset is where the lock byte lives. Windows byte-range locks
+// are mandatory, so locking the data would block readers such as log
+// tailers; a byte far beyond any real file size serves as a mutex
+// instead.
+const appendLockOffset = 1 << 62
+
+// lockAppend locks the sentinel byte of f, waiting for other holders.
+func lockAppend(f *os.File) error {
+	return lockRange(f, procLockFileEx, lockfileExclusiveLock)
+}
+
+// unlockAppend unlocks the sentinel byte of f.
+func unlockAppend(f *os.File) error {
+	return lockRange(f, procUnlockFileEx, 0)
+}
+
+// lockRange calls LockFileEx or UnlockFileEx on the sentinel byte.
+func lockRange(f *os.File, proc *syscall.LazyProc, flags uintptr) error {
+	ol := syscall.Overlapped{
+		Offset:     uint32(appendLockOffset & 0xffffffff),
+		OffsetHigh: uint32(appendLockOffset >> 32),
+	}
+	args := []uintptr{f.Fd()}
+	if proc == procLockFileEx {
+		args = append(args, flags)
+	}
+	args = append(args, 0, 1, 0, uintptr(unsafe.Pointer(&ol)))
+	r, _, err := proc.Call(args...)
+	if r == 0 {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package fs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrBackupPrivilege is returned, wrapped together with the
+// os.ErrPermission error of the open, when OpenBackup is denied access
+// and the process lacks the privilege that would bypass file
+// permissions: SeBackupPrivilege on Windows, CAP_DAC_READ_SEARCH on
+// Linux, or root elsewhere.
+var ErrBackupPrivilege = errors.New("oscompat/fs: backup privilege not held")
+
+// OpenBackup opens path for reading the way a backup agent needs to,
+// bypassing file permissions where the process holds the privilege for
+// it:
+//   - Windows: SeBackupPrivilege is enabled in the process token (it is
+//     held, but disabled, by administrators and the Backup Operators
+//     group) and the file is opened with FILE_FLAG_BACKUP_SEMANTICS, which
+//     also allows opening directories.
+//   - Linux: permissions are bypassed with CAP_DAC_READ_SEARCH, as for
+//     root. O_NOATIME is used where allowed, so backups do not update
+//     access times.
+//   - Elsewhere: root bypasses permissions.
+//
+// If access is denied without the privilege, the error matches both
+// os.ErrPermission and ErrBackupPrivilege, so the caller can tell the
+// user which capability to grant.
+func OpenBackup(path string) (*os.File, error) {
+	f, err := openBackup(path)
+	if errors.Is(err, os.ErrPermission) && !hasBackupPrivilege() {
+		return nil, fmt.Errorf("%w: %w", ErrBackupPrivilege, err)
+	}
+	return f, err
+}
@@ -0,0 +1,15 @@
+//go:build !linux && !windows
+
+package fs
+
+import "os"
+
+// openBackup opens path normally; only root bypasses permissions here.
+func openBackup(path string) (*os.File, error) {
+	return os.Open(path)
+}
+
+// hasBackupPrivilege reports whether the process runs as root.
+func hasBackupPrivilege() bool {
+	return os.Geteuid() == 0
+}
@@ -0,0 +1,47 @@
+package fs_test
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/grokify/oscompat/fs"
+)
+
+func TestOpenBackup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data")
+	if err := os.WriteFile(path, []byte("backup me"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := fs.OpenBackup(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(f)
+	_ = f.Close()
+	if err != nil || string(data) != "backup me" {
+		t.Errorf("read %q, %v", data, err)
+	}
+
+	if _, err := fs.OpenBackup(path + ".missing"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("missing file: err = %v, want ErrNotExist", err)
+	}
+
+	if runtime.GOOS == "windows" {
+		return
+	}
+	if err := os.Chmod(path, 0); err != nil {
+		t.Fatal(err)
+	}
+	f, err = fs.OpenBackup(path)
+	switch {
+	case err == nil:
+		// Privileged, as when tests run as root.
+		_ = f.Close()
+	case !errors.Is(err, os.ErrPermission) || !errors.Is(err, fs.ErrBackupPrivilege):
+		t.Errorf("unreadable file: err = %v, want ErrPermission and ErrBackupPrivilege", err)
+	}
+}
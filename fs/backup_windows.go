@@ -0,0 +1,73 @@
+//go:build windows
+
+package fs
+
+import (
+	"os"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	procLookupPrivilegeValueW = advapi32.NewProc("LookupPrivilegeValueW")
+	procAdjustTokenPrivileges = advapi32.NewProc("AdjustTokenPrivileges")
+)
+
+const (
+	sePrivilegeEnabled   = 0x00000002
+	fileFlagSequential   = 0x08000000
+	errorNotAllAssigned  = syscall.Errno(1300)
+	tokenAdjustPrivilege = 0x0020
+)
+
+// tokenPrivileges mirrors TOKEN_PRIVILEGES with one entry.
+type tokenPrivileges struct {
+	PrivilegeCount uint32
+	Luid           struct{ LowPart, HighPart uint32 }
+	Attributes     uint32
+}
+
+// backupPrivilege enables SeBackupPrivilege in the process token, once.
+var backupPrivilege = sync.OnceValue(func() bool {
+	proc, err := syscall.GetCurrentProcess()
+	if err != nil {
+		return false
+	}
+	var token syscall.Token
+	if err := syscall.OpenProcessToken(proc, tokenAdjustPrivilege|syscall.TOKEN_QUERY, &token); err != nil {
+		return false
+	}
+	defer func() { _ = token.Close() }()
+	name, _ := syscall.UTF16PtrFromString("SeBackupPrivilege")
+	tp := tokenPrivileges{PrivilegeCount: 1, Attributes: sePrivilegeEnabled}
+	if r, _, _ := procLookupPrivilegeValueW.Call(0, uintptr(unsafe.Pointer(name)), uintptr(unsafe.Pointer(&tp.Luid))); r == 0 {
+		return false
+	}
+	// AdjustTokenPrivileges succeeds without enabling privileges the
+	// token does not hold, and reports that as ERROR_NOT_ALL_ASSIGNED.
+	r, _, err := procAdjustTokenPrivileges.Call(uintptr(token), 0, uintptr(unsafe.Pointer(&tp)), 0, 0, 0)
+	return r != 0 && err != errorNotAllAssigned
+})
+
+// openBackup enables SeBackupPrivilege and opens path with backup
+// semantics, which check that privilege instead of the file's DACL.
+func openBackup(path string) (*os.File, error) {
+	backupPrivilege()
+	p, err := syscall.UTF16PtrFromString(longPath(path))
+	if err != nil {
+		return nil, &os.PathError{Op: "open", Path: path, Err: err}
+	}
+	h, err := syscall.CreateFile(p, syscall.GENERIC_READ,
+		syscall.FILE_SHARE_READ|syscall.FILE_SHARE_WRITE|syscall.FILE_SHARE_DELETE, nil,
+		syscall.OPEN_EXISTING, syscall.FILE_FLAG_BACKUP_SEMANTICS|fileFlagSequential, 0)
+	if err != nil {
+		return nil, &os.PathError{Op: "open", Path: path, Err: err}
+	}
+	return os.NewFile(uintptr(h), path), nil
+}
+
+// hasBackupPrivilege reports whether SeBackupPrivilege is enabled.
+func hasBackupPrivilege() bool {
+	return backupPrivilege()
+}
@@ -0,0 +1,163 @@
+package fs
+
+import (
+	"errors"
+	"io"
+	"math/bits"
+)
+
+// Default chunk sizes of ChunkReader.
+const (
+	DefaultChunkMinSize = 16 << 10
+	DefaultChunkAvgSize = 64 << 10
+	DefaultChunkMaxSize = 256 << 10
+)
+
+// ChunkOptions controls NewChunkReader. Zero fields take the defaults.
+type ChunkOptions struct {
+	// MinSize is the smallest chunk cut, except for the last one.
+	MinSize int
+
+	// AvgSize is the typical chunk size. It is rounded down to a power
+	// of two.
+	AvgSize int
+
+	// MaxSize is the largest chunk; data without a cut point is split
+	// at this size.
+	MaxSize int
+}
+
+// Chunk is a piece of the stream read by a ChunkReader.
+type Chunk struct {
+	// Offset is the position of the chunk in the stream.
+	Offset int64
+
+	// Data holds the chunk's bytes. It is only valid until the next
+	// call to Next.
+	Data []byte
+}
+
+// ChunkReader splits a stream into content-defined chunks with FastCDC,
+// a gear rolling hash with normalized chunking. Cut points depend only on
+// nearby content, so inserting or removing bytes changes the chunks
+// around the edit while the rest stay identical, which makes the chunks
+// suitable for deduplicating backups and delta sync. Cut points are
+// stable across releases of this package and across platforms.
+//
+// The reader reads ahead in large blocks sized for the platform, so it
+// needs no extra buffering.
+type ChunkReader struct {
+	r            io.Reader
+	min, avg     int
+	max          int
+	maskS, maskL uint64
+	buf          []byte
+	start, end   int   // unread bytes in buf
+	off          int64 // stream offset of buf[start]
+	eof          bool
+}
+
+// NewChunkReader returns a ChunkReader reading from r. opts may be nil.
+func NewChunkReader(r io.Reader, opts *ChunkOptions) *ChunkReader {
+	var o ChunkOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.AvgSize <= 0 {
+		o.AvgSize = DefaultChunkAvgSize
+	}
+	if o.MinSize <= 0 {
+		o.MinSize = min(DefaultChunkMinSize, o.AvgSize/4)
+	}
+	if o.MaxSize <= 0 {
+		o.MaxSize = max(DefaultChunkMaxSize, o.AvgSize*4)
+	}
+	avgBits := bits.Len(uint(o.AvgSize)) - 1
+	c := &ChunkReader{
+		r:   r,
+		avg: 1 << avgBits,
+	}
+	c.min = min(o.MinSize, c.avg)
+	c.max = max(o.MaxSize, c.avg)
+	// Normalized chunking: a stricter mask before the average size and
+	// a looser one after it pull chunk sizes toward the average.
+	c.maskS = highBits(avgBits + 2)
+	c.maskL = highBits(max(avgBits-2, 1))
+	c.buf = make([]byte, c.max+readBufferSize)
+	return c
+}
+
+// highBits returns a mask of the n high bits, which depend on the most
+// bytes of the gear hash.
+func highBits(n int) uint64 {
+	return ^uint64(0) << (64 - min(n, 64))
+}
+
+// Next returns the next chunk, or io.EOF after the last one.
+func (c *ChunkReader) Next() (Chunk, error) {
+	if c.end-c.start < c.max && !c.eof {
+		if err := c.fill(); err != nil {
+			return Chunk{}, err
+		}
+	}
+	if c.start == c.end {
+		return Chunk{}, io.EOF
+	}
+	n := c.cut(c.buf[c.start:c.end])
+	ch := Chunk{Offset: c.off, Data: c.buf[c.start : c.start+n]}
+	c.start += n
+	c.off += int64(n)
+	return ch, nil
+}
+
+// fill moves the unread bytes to the front of buf and reads to fill it.
+func (c *ChunkReader) fill() error {
+	c.end = copy(c.buf, c.buf[c.start:c.end])
+	c.start = 0
+	n, err := io.ReadFull(c.r, c.buf[c.end:])
+	c.end += n
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		c.eof = true
+		return nil
+	}
+	return err
+}
+
+// cut returns the length of the chunk at the start of data.
+func (c *ChunkReader) cut(data []byte) int {
+	n := len(data)
+	if n <= c.min {
+		return n
+	}
+	n = min(n, c.max)
+	normal := min(c.avg, n)
+	var h uint64
+	i := c.min
+	for ; i < normal; i++ {
+		h = h<<1 + gear[data[i]]
+		if h&c.maskS == 0 {
+			return i + 1
+		}
+	}
+	for ; i < n; i++ {
+		h = h<<1 + gear[data[i]]
+		if h&c.maskL == 0 {
+			return i + 1
+		}
+	}
+	return n
+}
+
+// gear maps each byte to a random value for the rolling hash. It is
+// generated with SplitMix64 from a fixed seed, so it never changes.
+var gear = func() (t [256]uint64) {
+	x := uint64(0x6f73636f6d706174) // "oscompat"
+	for i := range t {
+		x += 0x9e3779b97f4a7c15
+		z := x
+		z = (z ^ z>>30) * 0xbf58476d1ce4e5b9
+		z = (z ^ z>>27) * 0x94d049bb133111eb
+		t[i] = z ^ z>>31
+	}
+	return t
+}()
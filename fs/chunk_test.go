@@ -0,0 +1,90 @@
+package fs_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/grokify/oscompat/fs"
+)
+
+// chunkAll returns the chunks of data, checking offsets and sizes.
+func chunkAll(t *testing.T, data []byte, opts *fs.ChunkOptions) [][]byte {
+	t.Helper()
+	r := fs.NewChunkReader(bytes.NewReader(data), opts)
+	var chunks [][]byte
+	var off int64
+	for {
+		ch, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ch.Offset != off {
+			t.Fatalf("chunk offset = %d, want %d", ch.Offset, off)
+		}
+		off += int64(len(ch.Data))
+		chunks = append(chunks, bytes.Clone(ch.Data))
+	}
+	if off != int64(len(data)) {
+		t.Fatalf("chunked %d bytes, want %d", off, len(data))
+	}
+	return chunks
+}
+
+func TestChunkReader(t *testing.T) {
+	data := make([]byte, 4<<20)
+	rng := rand.New(rand.NewPCG(1, 2))
+	for i := range data {
+		data[i] = byte(rng.Uint32())
+	}
+
+	chunks := chunkAll(t, data, nil)
+	if !bytes.Equal(bytes.Join(chunks, nil), data) {
+		t.Fatal("chunks do not reassemble the input")
+	}
+	for i, ch := range chunks {
+		if len(ch) > fs.DefaultChunkMaxSize || (len(ch) < fs.DefaultChunkMinSize && i != len(chunks)-1) {
+			t.Errorf("chunk %d has size %d", i, len(ch))
+		}
+	}
+	if avg := len(data) / len(chunks); avg < fs.DefaultChunkAvgSize/2 || avg > fs.DefaultChunkAvgSize*2 {
+		t.Errorf("average chunk size = %d", avg)
+	}
+
+	// Inserting bytes near the start only changes the first chunks.
+	shifted := append([]byte("inserted"), data...)
+	seen := map[[32]byte]bool{}
+	for _, ch := range chunks {
+		seen[sha256.Sum256(ch)] = true
+	}
+	var shared int
+	for _, ch := range chunkAll(t, shifted, nil) {
+		if seen[sha256.Sum256(ch)] {
+			shared++
+		}
+	}
+	if shared < len(chunks)-2 {
+		t.Errorf("%d of %d chunks survived an insertion", shared, len(chunks))
+	}
+
+	t.Run("small options", func(t *testing.T) {
+		opts := &fs.ChunkOptions{MinSize: 256, AvgSize: 1000, MaxSize: 4096}
+		for _, ch := range chunkAll(t, data[:1<<20], opts) {
+			if len(ch) > 4096 {
+				t.Fatalf("chunk size %d exceeds MaxSize", len(ch))
+			}
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		if chunks := chunkAll(t, nil, nil); len(chunks) != 0 {
+			t.Errorf("got %d chunks", len(chunks))
+		}
+	})
+}
@@ -0,0 +1,60 @@
+package fs
+
+import (
+	"fmt"
+	"os"
+	"slices"
+)
+
+// extent is a run of a file's data on disk: length bytes (or clusters) at
+// logical offset in the file, stored at physical offset on the volume.
+type extent struct {
+	logical, physical, length uint64
+}
+
+// AreClones reports whether the regular files a and b share all of their
+// data on disk, as copy-on-write clones made with reflink, cp
+// --reflink, or block cloning do, so that a deduplication tool can skip
+// them. Hard links to the same file are clones too. Empty files, and
+// files whose layout cannot be read exactly, such as data still buffered
+// in memory or stored inline, are reported as not clones.
+//
+// Platform behavior:
+//   - Linux: compares the extent maps from the FIEMAP ioctl, as filefrag
+//     does; Btrfs, XFS, and bcachefs support it, while tmpfs and most
+//     network filesystems return ErrUnsupported.
+//   - Windows: compares the cluster runs from
+//     FSCTL_GET_RETRIEVAL_POINTERS, which shows ReFS block clones.
+//   - Elsewhere, including APFS on macOS, which exposes clone IDs only
+//     through getattrlist: ErrUnsupported.
+func AreClones(a, b string) (bool, error) {
+	ia, err := os.Stat(a)
+	if err != nil {
+		return false, err
+	}
+	ib, err := os.Stat(b)
+	if err != nil {
+		return false, err
+	}
+	for _, info := range []os.FileInfo{ia, ib} {
+		if !info.Mode().IsRegular() {
+			return false, fmt.Errorf("oscompat/fs: %s is not a regular file", info.Name())
+		}
+	}
+	if os.SameFile(ia, ib) {
+		return true, nil
+	}
+	if ia.Size() != ib.Size() || ia.Size() == 0 {
+		return false, nil
+	}
+
+	va, ea, err := fileExtents(a)
+	if err != nil {
+		return false, err
+	}
+	vb, eb, err := fileExtents(b)
+	if err != nil {
+		return false, err
+	}
+	return va == vb && len(ea) > 0 && slices.Equal(ea, eb), nil
+}
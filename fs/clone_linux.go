@@ -0,0 +1,84 @@
+//go:build linux
+
+package fs
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	fsIocFiemap    = 0xC020660B
+	fiemapFlagSync = 0x1
+
+	fiemapExtentLast = 0x1
+	// Extents whose physical location is not exact: unknown, delayed
+	// allocation, encoded, encrypted, inline, or tail-packed data.
+	fiemapExtentInexact = 0x2 | 0x4 | 0x8 | 0x80 | 0x200 | 0x400
+
+	fiemapBatch = 64
+)
+
+// fiemapExtentData mirrors struct fiemap_extent.
+type fiemapExtentData struct {
+	Logical  uint64
+	Physical uint64
+	Length   uint64
+	_        [2]uint64
+	Flags    uint32
+	_        [3]uint32
+}
+
+// fiemapData mirrors struct fiemap with room for fiemapBatch extents.
+type fiemapData struct {
+	Start         uint64
+	Length        uint64
+	Flags         uint32
+	MappedExtents uint32
+	ExtentCount   uint32
+	_             uint32
+	Extents       [fiemapBatch]fiemapExtentData
+}
+
+// fileExtents returns the device of path and its extents from FIEMAP,
+// or no extents if any of them is inexact.
+func fileExtents(path string) (uint64, []extent, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer func() { _ = f.Close() }()
+	var st syscall.Stat_t
+	if err := syscall.Fstat(int(f.Fd()), &st); err != nil {
+		return 0, nil, err
+	}
+
+	var exts []extent
+	fm := &fiemapData{Length: ^uint64(0), Flags: fiemapFlagSync, ExtentCount: fiemapBatch}
+	for {
+		_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), fsIocFiemap, uintptr(unsafe.Pointer(fm)))
+		switch errno {
+		case 0:
+		case syscall.ENOTTY, syscall.EOPNOTSUPP:
+			return 0, nil, ErrUnsupported
+		default:
+			return 0, nil, fmt.Errorf("oscompat/fs: FIEMAP %s: %w", path, errno)
+		}
+		if fm.MappedExtents == 0 {
+			return st.Dev, exts, nil
+		}
+		for _, e := range fm.Extents[:fm.MappedExtents] {
+			if e.Flags&fiemapExtentInexact != 0 {
+				return st.Dev, nil, nil
+			}
+			exts = append(exts, extent{e.Logical, e.Physical, e.Length})
+			if e.Flags&fiemapExtentLast != 0 {
+				return st.Dev, exts, nil
+			}
+		}
+		last := fm.Extents[fm.MappedExtents-1]
+		*fm = fiemapData{Start: last.Logical + last.Length, Length: ^uint64(0), Flags: fiemapFlagSync, ExtentCount: fiemapBatch}
+	}
+}
@@ -0,0 +1,7 @@
+//go:build !linux && !windows
+
+package fs
+
+func fileExtents(string) (uint64, []extent, error) {
+	return 0, nil, ErrUnsupported
+}
@@ -0,0 +1,53 @@
+package fs_test
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/grokify/oscompat/fs"
+)
+
+func TestAreClones(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a")
+	data := make([]byte, 64<<10)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	if err := os.WriteFile(a, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	link := filepath.Join(dir, "link")
+	if err := os.Link(a, link); err == nil {
+		if ok, err := fs.AreClones(a, link); err != nil || !ok {
+			t.Errorf("AreClones(hard link) = %v, %v, want true", ok, err)
+		}
+	}
+	if _, err := fs.AreClones(a, dir); err == nil {
+		t.Error("AreClones(file, dir) should fail")
+	}
+
+	copied := filepath.Join(dir, "copy")
+	if err := os.WriteFile(copied, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	ok, err := fs.AreClones(a, copied)
+	if errors.Is(err, fs.ErrUnsupported) {
+		t.Skipf("extent maps unavailable here: %v", err)
+	}
+	if err != nil || ok {
+		t.Errorf("AreClones(independent copy) = %v, %v, want false", ok, err)
+	}
+
+	clone := filepath.Join(dir, "clone")
+	if exec.Command("cp", "--reflink=always", a, clone).Run() != nil {
+		t.Skip("filesystem cannot make reflink clones")
+	}
+	if ok, err := fs.AreClones(a, clone); err != nil || !ok {
+		t.Errorf("AreClones(reflink clone) = %v, %v, want true", ok, err)
+	}
+}
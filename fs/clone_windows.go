@@ -0,0 +1,73 @@
+//go:build windows
+
+package fs
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	fsctlGetRetrievalPointers = 0x00090073
+
+	errorMoreData  syscall.Errno = 234
+	errorHandleEOF syscall.Errno = 38
+
+	retrievalBatch = 64
+)
+
+// retrievalPointers mirrors RETRIEVAL_POINTERS_BUFFER with room for
+// retrievalBatch extents.
+type retrievalPointers struct {
+	ExtentCount uint32
+	_           uint32
+	StartingVcn int64
+	Extents     [retrievalBatch]struct{ NextVcn, Lcn int64 }
+}
+
+// fileExtents returns the volume serial number of path and its cluster
+// runs. Runs with no clusters, in sparse or compressed files, are left
+// out.
+func fileExtents(path string) (uint64, []extent, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer func() { _ = f.Close() }()
+	h := syscall.Handle(f.Fd())
+	var info syscall.ByHandleFileInformation
+	if err := syscall.GetFileInformationByHandle(h, &info); err != nil {
+		return 0, nil, err
+	}
+	vol := uint64(info.VolumeSerialNumber)
+
+	var exts []extent
+	var start int64
+	for {
+		var out retrievalPointers
+		var n uint32
+		err := syscall.DeviceIoControl(h, fsctlGetRetrievalPointers,
+			(*byte)(unsafe.Pointer(&start)), uint32(unsafe.Sizeof(start)),
+			(*byte)(unsafe.Pointer(&out)), uint32(unsafe.Sizeof(out)), &n, nil)
+		switch err {
+		case nil, errorMoreData:
+		case errorHandleEOF:
+			// Small files live in the MFT record and have no clusters.
+			return vol, exts, nil
+		default:
+			return 0, nil, err
+		}
+		vcn := out.StartingVcn
+		for _, e := range out.Extents[:out.ExtentCount] {
+			if e.Lcn >= 0 {
+				exts = append(exts, extent{uint64(vcn), uint64(e.Lcn), uint64(e.NextVcn - vcn)})
+			}
+			vcn = e.NextVcn
+		}
+		if err == nil || out.ExtentCount == 0 {
+			return vol, exts, nil
+		}
+		start = vcn
+	}
+}
@@ -0,0 +1,214 @@
+package fs
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// sniffLen is how much of a file ContentType and IsTextFile read.
+const sniffLen = 8192
+
+// extTypes maps lower-case extensions to media types. It is built in,
+// rather than read from mime.types or the Windows registry as the mime
+// package does, so that every platform gives the same answer.
+var extTypes = map[string]string{
+	".7z":   "application/x-7z-compressed",
+	".bz2":  "application/x-bzip2",
+	".c":    "text/x-c",
+	".cpp":  "text/x-c++",
+	".css":  "text/css; charset=utf-8",
+	".csv":  "text/csv; charset=utf-8",
+	".doc":  "application/msword",
+	".docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+	".epub": "application/epub+zip",
+	".gif":  "image/gif",
+	".go":   "text/x-go; charset=utf-8",
+	".gz":   "application/gzip",
+	".h":    "text/x-c",
+	".htm":  "text/html; charset=utf-8",
+	".html": "text/html; charset=utf-8",
+	".ico":  "image/vnd.microsoft.icon",
+	".ini":  "text/plain; charset=utf-8",
+	".jar":  "application/java-archive",
+	".java": "text/x-java",
+	".jpeg": "image/jpeg",
+	".jpg":  "image/jpeg",
+	".js":   "text/javascript; charset=utf-8",
+	".json": "application/json",
+	".log":  "text/plain; charset=utf-8",
+	".md":   "text/markdown; charset=utf-8",
+	".mjs":  "text/javascript; charset=utf-8",
+	".mp3":  "audio/mpeg",
+	".mp4":  "video/mp4",
+	".odt":  "application/vnd.oasis.opendocument.text",
+	".ods":  "application/vnd.oasis.opendocument.spreadsheet",
+	".pdf":  "application/pdf",
+	".png":  "image/png",
+	".ppt":  "application/vnd.ms-powerpoint",
+	".pptx": "application/vnd.openxmlformats-officedocument.presentationml.presentation",
+	".ps1":  "text/plain; charset=utf-8",
+	".py":   "text/x-python; charset=utf-8",
+	".rs":   "text/x-rust; charset=utf-8",
+	".sh":   "application/x-sh",
+	".svg":  "image/svg+xml",
+	".tar":  "application/x-tar",
+	".toml": "application/toml",
+	".ts":   "text/typescript; charset=utf-8",
+	".tsv":  "text/tab-separated-values; charset=utf-8",
+	".txt":  "text/plain; charset=utf-8",
+	".wasm": "application/wasm",
+	".wav":  "audio/wav",
+	".webm": "video/webm",
+	".webp": "image/webp",
+	".xls":  "application/vnd.ms-excel",
+	".xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	".xml":  "application/xml",
+	".yaml": "application/yaml",
+	".yml":  "application/yaml",
+	".zip":  "application/zip",
+}
+
+// ContentType returns the media type of the named file, such as
+// "image/png" or "text/plain; charset=utf-16le", the same way on every
+// platform, including Windows, which lacks the file command.
+//
+// The content is sniffed with http.DetectContentType first, since it
+// cannot lie the way a name can. The extension decides when sniffing only
+// finds generic text or a container format, so "data.json" is
+// "application/json" rather than "text/plain" and "report.docx" is a Word
+// document rather than "application/zip". Unknown binary content is
+// "application/octet-stream", and a directory is "inode/directory".
+func ContentType(path string) (string, error) {
+	head, dir, err := readHead(path)
+	if err != nil {
+		return "", err
+	}
+	if dir {
+		return "inode/directory", nil
+	}
+
+	sniffed := http.DetectContentType(head)
+	byExt, known := extTypes[strings.ToLower(filepath.Ext(path))]
+	switch {
+	case known && sniffed == "application/zip" && zipBased[byExt],
+		known && sniffed == "text/plain; charset=utf-8",
+		known && sniffed == "application/octet-stream" && (isText(head) || !strings.HasPrefix(byExt, "text/")):
+		return byExt, nil
+	case sniffed == "application/octet-stream" && isText(head):
+		return "text/plain; charset=" + textCharset(head), nil
+	}
+	return sniffed, nil
+}
+
+// zipBased are the types in extTypes stored as zip archives.
+var zipBased = map[string]bool{
+	extTypes[".docx"]: true, extTypes[".xlsx"]: true, extTypes[".pptx"]: true,
+	extTypes[".odt"]: true, extTypes[".ods"]: true, extTypes[".epub"]: true,
+	extTypes[".jar"]: true,
+}
+
+// IsTextFile reports whether the named file looks like text, judging by
+// its first few kilobytes. UTF-8, UTF-16 with or without a byte order
+// mark, and legacy 8-bit encodings count as text, with any mix of LF,
+// CRLF, and CR line endings and a trailing DOS end-of-file marker
+// (Ctrl+Z). Files with other control characters, such as NUL bytes
+// outside UTF-16, do not. An empty file is text.
+func IsTextFile(path string) (bool, error) {
+	head, dir, err := readHead(path)
+	if err != nil || dir {
+		return false, err
+	}
+	return isText(head), nil
+}
+
+// readHead reads the start of the named file.
+func readHead(path string) (head []byte, dir bool, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, false, err
+	}
+	defer func() { _ = f.Close() }()
+	if info, err := f.Stat(); err != nil {
+		return nil, false, err
+	} else if info.IsDir() {
+		return nil, true, nil
+	}
+	head = make([]byte, sniffLen)
+	n, err := io.ReadFull(f, head)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		err = nil
+	}
+	return head[:n], false, err
+}
+
+// isText reports whether b looks like the start of a text file.
+func isText(b []byte) bool {
+	switch {
+	case bytes.HasPrefix(b, []byte{0xEF, 0xBB, 0xBF}):
+		return isText8(b[3:])
+	case bytes.HasPrefix(b, []byte{0xFF, 0xFE}):
+		return isText16(b[2:], 1)
+	case bytes.HasPrefix(b, []byte{0xFE, 0xFF}):
+		return isText16(b[2:], 0)
+	}
+	if bytes.IndexByte(b, 0) < 0 {
+		return isText8(b)
+	}
+	// Without a byte order mark, mostly-ASCII UTF-16 shows as a zero in
+	// every other byte.
+	return isText16(b, 0) || isText16(b, 1)
+}
+
+// textCharset names the encoding of text b, which has no byte order mark.
+func textCharset(b []byte) string {
+	switch {
+	case bytes.IndexByte(b, 0) < 0:
+		return "utf-8"
+	case isText16(b, 1):
+		return "utf-16le"
+	}
+	return "utf-16be"
+}
+
+// isText8 reports whether b is free of control characters other than
+// whitespace, backspace, escape, and Ctrl+Z.
+func isText8(b []byte) bool {
+	for _, c := range b {
+		if c < 0x20 && !isTextControl(c) || c == 0x7F {
+			return false
+		}
+	}
+	return true
+}
+
+// isText16 reports whether b is UTF-16 text, with the high byte of each
+// code unit at offset hi (0 for big-endian, 1 for little-endian) of each
+// pair. Units with a zero high byte must be text characters, and at least
+// half of them must have one, as Latin text does.
+func isText16(b []byte, hi int) bool {
+	ascii, units := 0, len(b)/2
+	for i := 0; i+1 < len(b); i += 2 {
+		if b[i+hi] != 0 {
+			continue
+		}
+		c := b[i+1-hi]
+		if c == 0 || c < 0x20 && !isTextControl(c) {
+			return false
+		}
+		ascii++
+	}
+	return units == 0 || ascii*2 >= units
+}
+
+// isTextControl reports whether control character c appears in text.
+func isTextControl(c byte) bool {
+	switch c {
+	case '\t', '\n', '\v', '\f', '\r', '\b', 0x1A, 0x1B:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package fs_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/grokify/oscompat/fs"
+)
+
+func TestContentTypeAndIsTextFile(t *testing.T) {
+	dir := t.TempDir()
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	zip := []byte("PK\x03\x04\x14\x00\x00\x00")
+	tests := []struct {
+		name   string
+		data   []byte
+		want   string
+		isText bool
+	}{
+		{"image.png", png, "image/png", false},
+		{"image.txt", png, "image/png", false}, // content wins over the name
+		{"data.json", []byte(`{"a": 1}`), "application/json", true},
+		{"notes", []byte("line one\r\nline two\r\n"), "text/plain; charset=utf-8", true},
+		{"old-mac.txt", []byte("one\rtwo\r"), "text/plain; charset=utf-8", true},
+		{"dos.txt", []byte("text\r\n\x1a"), "text/plain; charset=utf-8", true},
+		{"utf16bom", []byte("\xff\xfeh\x00i\x00\r\x00\n\x00"), "text/plain; charset=utf-16le", true},
+		{"utf16le", []byte("h\x00e\x00l\x00l\x00o\x00\r\x00\n\x00"), "text/plain; charset=utf-16le", true},
+		{"utf16be", []byte("\x00h\x00e\x00l\x00l\x00o"), "text/plain; charset=utf-16be", true},
+		{"latin1.txt", []byte("caf\xe9\n"), "text/plain; charset=utf-8", true},
+		{"report.docx", zip, "application/vnd.openxmlformats-officedocument.wordprocessingml.document", false},
+		{"archive.bin", zip, "application/zip", false},
+		{"blob", []byte{0x00, 0x01, 0x02, 0x03, 0xff, 0x00, 0x10}, "application/octet-stream", false},
+		{"empty", nil, "text/plain; charset=utf-8", true},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(dir, tt.name)
+		if err := os.WriteFile(path, tt.data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		got, err := fs.ContentType(path)
+		if err != nil {
+			t.Fatalf("ContentType(%s) error: %v", tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("ContentType(%s) = %q, want %q", tt.name, got, tt.want)
+		}
+		isText, err := fs.IsTextFile(path)
+		if err != nil {
+			t.Fatalf("IsTextFile(%s) error: %v", tt.name, err)
+		}
+		if isText != tt.isText {
+			t.Errorf("IsTextFile(%s) = %v, want %v", tt.name, isText, tt.isText)
+		}
+	}
+
+	if got, _ := fs.ContentType(dir); got != "inode/directory" {
+		t.Errorf("ContentType(dir) = %q", got)
+	}
+	if _, err := fs.ContentType(filepath.Join(dir, "missing")); !os.IsNotExist(err) {
+		t.Errorf("ContentType(missing) error = %v", err)
+	}
+}
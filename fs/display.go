@@ -0,0 +1,111 @@
+package fs
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"unicode/utf8"
+)
+
+// ellipsis marks the part of a path that DisplayPathWidth left out.
+const ellipsis = "…"
+
+// DisplayPath formats p for showing to a user: it is cleaned, uses the
+// native separator, and has the home directory collapsed to "~", as in
+// "~/src/app" or "~\Documents". p is not required to exist.
+func DisplayPath(p string) string {
+	if p == "" {
+		return ""
+	}
+	p = filepath.Clean(filepath.FromSlash(p))
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return p
+	}
+	home = filepath.Clean(home)
+	if len(p) >= len(home) && PathHasPrefix(p, home) {
+		return "~" + p[len(home):]
+	}
+	return p
+}
+
+// DisplayPathWidth is DisplayPath for fixed-width UIs: if the result is
+// longer than width runes, leading directories are replaced with "…" so
+// that the first element and as many trailing elements as possible remain,
+// as in "~/…/app/main.go". A base name too long on its own is cut in the
+// middle. A width of 0 or less means no limit.
+func DisplayPathWidth(p string, width int) string {
+	d := DisplayPath(p)
+	if width <= 0 || utf8.RuneCountInString(d) <= width {
+		return d
+	}
+	sep := string(filepath.Separator)
+	parts := strings.Split(d, sep)
+	prefix := parts[0] + sep + ellipsis
+	tail := ""
+	for i := len(parts) - 1; i >= 1; i-- {
+		next := sep + parts[i] + tail
+		if utf8.RuneCountInString(prefix+next) > width {
+			break
+		}
+		tail = next
+	}
+	if tail != "" {
+		return prefix + tail
+	}
+	return truncateMiddle(d, width)
+}
+
+// truncateMiddle shortens s to width runes by replacing its middle with
+// an ellipsis, keeping slightly more of the end, where the extension is.
+func truncateMiddle(s string, width int) string {
+	r := []rune(s)
+	if width <= 1 {
+		return ellipsis
+	}
+	front := (width - 1) / 2
+	back := width - 1 - front
+	return string(r[:front]) + ellipsis + string(r[len(r)-back:])
+}
+
+// ShellQuotePath quotes p for pasting into the platform's shell as a single
+// argument: POSIX single quotes on Unix, and double quotes on Windows,
+// which both cmd.exe and PowerShell accept. Paths that need no quoting are
+// returned unchanged, and a relative path starting with "-" gets a "./"
+// or ".\" prefix so it is not taken for an option.
+//
+// Inside double quotes cmd.exe still expands %VAR% and PowerShell still
+// expands $var and backticks; Windows paths containing those are rare
+// but cannot be quoted for both shells at once.
+func ShellQuotePath(p string) string {
+	if p == "" {
+		if runtime.GOOS == "windows" {
+			return `""`
+		}
+		return "''"
+	}
+	if strings.HasPrefix(p, "-") {
+		p = "." + string(filepath.Separator) + p
+	}
+	if strings.IndexFunc(p, func(r rune) bool { return !isPathShellSafe(r) }) < 0 {
+		return p
+	}
+	if runtime.GOOS == "windows" {
+		return `"` + p + `"`
+	}
+	return "'" + strings.ReplaceAll(p, "'", `'\''`) + "'"
+}
+
+// isPathShellSafe reports whether r never needs quoting in a path.
+func isPathShellSafe(r rune) bool {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		return true
+	case r == '\\':
+		return runtime.GOOS == "windows"
+	case r == '%':
+		return runtime.GOOS != "windows"
+	}
+	return strings.ContainsRune("-_./:@+,", r)
+}
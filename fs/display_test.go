@@ -0,0 +1,77 @@
+package fs_test
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/grokify/oscompat/fs"
+)
+
+func TestDisplayPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	tests := map[string]string{
+		filepath.Join(home, "src", "app"): filepath.Join("~", "src", "app"),
+		home:                              "~",
+		home + "x":                        home + "x",
+		"relative/dir/":                   filepath.Join("relative", "dir"),
+		"":                                "",
+	}
+	for in, want := range tests {
+		if got := fs.DisplayPath(in); got != want {
+			t.Errorf("DisplayPath(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestDisplayPathWidth(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("USERPROFILE", t.TempDir())
+	p := filepath.Join("projects", "client", "website", "src", "main.go")
+	tests := []struct {
+		width int
+		want  string
+	}{
+		{0, p},
+		{100, p},
+		{30, filepath.Join("projects", "…", "website", "src", "main.go")},
+		{24, filepath.Join("projects", "…", "src", "main.go")},
+		{20, filepath.Join("projects", "…", "main.go")},
+		{9, "proj…n.go"},
+	}
+	for _, tt := range tests {
+		got := fs.DisplayPathWidth(p, tt.width)
+		if got != tt.want {
+			t.Errorf("DisplayPathWidth(%d) = %q, want %q", tt.width, got, tt.want)
+		}
+		if tt.width > 0 && utf8.RuneCountInString(got) > tt.width {
+			t.Errorf("DisplayPathWidth(%d) = %q is too long", tt.width, got)
+		}
+	}
+}
+
+func TestShellQuotePath(t *testing.T) {
+	tests := map[string]string{
+		"plain/file.txt": "plain/file.txt",
+		"-rf":            "." + string(filepath.Separator) + "-rf",
+	}
+	if runtime.GOOS == "windows" {
+		tests[`C:\Program Files\app`] = `"C:\Program Files\app"`
+		tests[`C:\tools\app.exe`] = `C:\tools\app.exe`
+		tests[""] = `""`
+	} else {
+		tests["/tmp/my file"] = "'/tmp/my file'"
+		tests["/tmp/it's"] = `'/tmp/it'\''s'`
+		tests["~/x"] = "'~/x'"
+		tests[""] = "''"
+	}
+	for in, want := range tests {
+		if got := fs.ShellQuotePath(in); got != want {
+			t.Errorf("ShellQuotePath(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
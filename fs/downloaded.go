@@ -0,0 +1,49 @@
+package fs
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// SetDownloadedMark records that the file at path was downloaded from
+// sourceURL, the way browsers do, so the operating system applies its
+// usual checks before the file is opened or run. Download managers should
+// call it after the file is complete; sourceURL may be empty.
+//
+// Platform behavior:
+//   - macOS: sets the com.apple.quarantine extended attribute, which
+//     makes Gatekeeper verify the file on first open.
+//   - Windows: writes the Zone.Identifier alternate data stream with
+//     ZoneId=3 (Internet), the Mark of the Web.
+//   - Linux: sets the user.xdg.origin.url extended attribute, as Chromium
+//     and wget do; it is informational only.
+//   - Elsewhere: ErrUnsupported.
+//
+// Filesystems without extended attributes or streams, such as FAT, return
+// an error wrapping ErrUnsupported.
+func SetDownloadedMark(path, sourceURL string) error {
+	if _, err := os.Stat(path); err != nil {
+		return err
+	}
+	return setDownloadedMark(path, sourceURL, filepath.Base(os.Args[0]))
+}
+
+// ClearDownloadedMark removes the mark set by SetDownloadedMark, or by a
+// browser, from the file at path. Updaters call it on files they have
+// verified themselves, such as a signed update unpacked into place. It is
+// not an error if path has no mark.
+func ClearDownloadedMark(path string) error {
+	if _, err := os.Stat(path); err != nil {
+		return err
+	}
+	return clearDownloadedMark(path)
+}
+
+// HasDownloadedMark reports whether the file at path carries a download
+// mark. It always reports false on platforms without one.
+func HasDownloadedMark(path string) (bool, error) {
+	if _, err := os.Stat(path); err != nil {
+		return false, err
+	}
+	return hasDownloadedMark(path)
+}
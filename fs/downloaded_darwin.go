@@ -0,0 +1,50 @@
+//go:build darwin
+
+package fs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"time"
+)
+
+// quarantineAttr is read by Gatekeeper. The syscall package does not
+// expose xattr calls on macOS, so the xattr tool manages it.
+const quarantineAttr = "com.apple.quarantine"
+
+// setDownloadedMark writes the quarantine value the way browsers do:
+// flags (0x0081: downloaded, not yet approved), the download time as hex
+// seconds, and the downloading application. Gatekeeper looks up the
+// source URL in its own database, so sourceURL is not stored.
+func setDownloadedMark(path, _, agent string) error {
+	value := fmt.Sprintf("0081;%08x;%s;", time.Now().Unix(), agent)
+	return xattrTool("-w", quarantineAttr, value, path)
+}
+
+func clearDownloadedMark(path string) error {
+	ok, err := hasDownloadedMark(path)
+	if err != nil || !ok {
+		return err
+	}
+	return xattrTool("-d", quarantineAttr, path)
+}
+
+func hasDownloadedMark(path string) (bool, error) {
+	err := exec.Command("xattr", "-p", quarantineAttr, path).Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		// xattr exits with status 1 when the attribute is missing.
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func xattrTool(args ...string) error {
+	out, err := exec.Command("xattr", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("oscompat/fs: xattr %s: %w: %s", args[0], err, bytes.TrimSpace(out))
+	}
+	return nil
+}
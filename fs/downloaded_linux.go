@@ -0,0 +1,48 @@
+//go:build linux
+
+package fs
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// originAttr is the freedesktop.org common extended attribute for the URL
+// a file was downloaded from.
+const originAttr = "user.xdg.origin.url"
+
+func setDownloadedMark(path, sourceURL, _ string) error {
+	err := syscall.Setxattr(path, originAttr, []byte(sourceURL), 0)
+	return xattrError("setxattr", path, err)
+}
+
+func clearDownloadedMark(path string) error {
+	err := syscall.Removexattr(path, originAttr)
+	if err == syscall.ENODATA {
+		return nil
+	}
+	return xattrError("removexattr", path, err)
+}
+
+func hasDownloadedMark(path string) (bool, error) {
+	_, err := syscall.Getxattr(path, originAttr, nil)
+	switch err {
+	case nil:
+		return true, nil
+	case syscall.ENODATA, syscall.ENOTSUP:
+		return false, nil
+	}
+	return false, xattrError("getxattr", path, err)
+}
+
+// xattrError maps ENOTSUP, returned by filesystems without user extended
+// attributes, to ErrUnsupported.
+func xattrError(op, path string, err error) error {
+	switch err {
+	case nil:
+		return nil
+	case syscall.ENOTSUP:
+		return fmt.Errorf("%w: %s: extended attributes not supported on %s", ErrUnsupported, op, path)
+	}
+	return fmt.Errorf("oscompat/fs: %s %s: %w", op, path, err)
+}
@@ -0,0 +1,15 @@
+//go:build !linux && !darwin && !windows
+
+package fs
+
+func setDownloadedMark(_, _, _ string) error {
+	return ErrUnsupported
+}
+
+func clearDownloadedMark(string) error {
+	return nil
+}
+
+func hasDownloadedMark(string) (bool, error) {
+	return false, nil
+}
@@ -0,0 +1,45 @@
+package fs_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/grokify/oscompat/fs"
+)
+
+func TestDownloadedMark(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "setup.bin")
+	if err := os.WriteFile(path, []byte("payload"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := fs.HasDownloadedMark(path); err != nil || ok {
+		t.Fatalf("HasDownloadedMark() on new file = %v, %v", ok, err)
+	}
+	err := fs.SetDownloadedMark(path, "https://example.com/setup.bin")
+	if errors.Is(err, fs.ErrUnsupported) {
+		t.Skipf("download marks unsupported here: %v", err)
+	}
+	if err != nil {
+		t.Fatalf("SetDownloadedMark() error: %v", err)
+	}
+	if ok, err := fs.HasDownloadedMark(path); err != nil || !ok {
+		t.Errorf("HasDownloadedMark() after set = %v, %v", ok, err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "payload" {
+		t.Errorf("content changed to %q", data)
+	}
+	for range 2 {
+		if err := fs.ClearDownloadedMark(path); err != nil {
+			t.Errorf("ClearDownloadedMark() error: %v", err)
+		}
+	}
+	if ok, err := fs.HasDownloadedMark(path); err != nil || ok {
+		t.Errorf("HasDownloadedMark() after clear = %v, %v", ok, err)
+	}
+
+	if err := fs.SetDownloadedMark(filepath.Join(t.TempDir(), "missing"), ""); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("SetDownloadedMark() on missing file error = %v", err)
+	}
+}
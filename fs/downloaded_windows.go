@@ -0,0 +1,46 @@
+//go:build windows
+
+package fs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// zoneStream is the alternate data stream Windows checks for the Mark of
+// the Web.
+const zoneStream = ":Zone.Identifier"
+
+func setDownloadedMark(path, sourceURL, _ string) error {
+	var b strings.Builder
+	// Zone 3 is URLZONE_INTERNET.
+	b.WriteString("[ZoneTransfer]\r\nZoneId=3\r\n")
+	if sourceURL != "" {
+		b.WriteString("HostUrl=" + sourceURL + "\r\n")
+	}
+	err := os.WriteFile(path+zoneStream, []byte(b.String()), 0o644)
+	if errors.Is(err, os.ErrNotExist) {
+		// Opening a stream on FAT or exFAT fails as if the file were
+		// missing; path itself was checked by the caller.
+		return fmt.Errorf("%w: %v", ErrUnsupported, err)
+	}
+	return err
+}
+
+func clearDownloadedMark(path string) error {
+	err := os.Remove(path + zoneStream)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func hasDownloadedMark(path string) (bool, error) {
+	_, err := os.Stat(path + zoneStream)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
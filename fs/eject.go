@@ -0,0 +1,73 @@
+package fs
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+)
+
+var (
+	// ErrNotRemovable is returned by Eject for a volume that is not
+	// removable, so that a wrong path cannot unmount a system disk.
+	ErrNotRemovable = errors.New("oscompat/fs: volume is not removable")
+
+	// ErrBusy is returned by Eject when files on the volume are in use.
+	ErrBusy = errors.New("oscompat/fs: volume is in use")
+)
+
+// IsRemovable reports whether the volume holding path, which may be its
+// mount point or any file on it, is removable media such as a USB drive
+// or memory card. See ListVolumes for how removable media are detected.
+func IsRemovable(path string) (bool, error) {
+	v, err := volumeOf(path)
+	if err != nil {
+		return false, err
+	}
+	return v.Removable, nil
+}
+
+// Eject flushes pending writes to the removable volume holding path,
+// unmounts it, and, where the hardware allows, ejects or powers it off so
+// it can be unplugged safely. It returns ErrNotRemovable for other
+// volumes and ErrBusy if files on it are still open.
+//
+// Platform behavior:
+//   - Linux: udisksctl unmount and power-off, or the eject command when
+//     udisks is not installed.
+//   - macOS: diskutil eject.
+//   - Windows: locks and dismounts the volume, then sends
+//     IOCTL_STORAGE_EJECT_MEDIA with DeviceIoControl.
+//   - Elsewhere: ErrUnsupported.
+func Eject(path string) error {
+	v, err := volumeOf(path)
+	if err != nil {
+		return err
+	}
+	if !v.Removable {
+		return fmt.Errorf("%w: %s", ErrNotRemovable, v.Path)
+	}
+	return eject(v)
+}
+
+// volumeOf returns the volume with the longest mount point containing
+// path.
+func volumeOf(path string) (Volume, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return Volume{}, err
+	}
+	vols, err := ListVolumes()
+	if err != nil {
+		return Volume{}, err
+	}
+	best := -1
+	for i, v := range vols {
+		if PathHasPrefix(abs, v.Path) && (best < 0 || len(v.Path) > len(vols[best].Path)) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return Volume{}, fmt.Errorf("oscompat/fs: no volume contains %s", abs)
+	}
+	return vols[best], nil
+}
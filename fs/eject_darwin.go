@@ -0,0 +1,23 @@
+//go:build darwin
+
+package fs
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+)
+
+// eject runs diskutil eject, which flushes, unmounts every volume on the
+// disk, and ejects it.
+func eject(v Volume) error {
+	out, err := exec.Command("diskutil", "eject", v.Path).CombinedOutput()
+	if err == nil {
+		return nil
+	}
+	// diskutil reports open files as a dissenter.
+	if bytes.Contains(out, []byte("dissent")) || bytes.Contains(out, []byte("busy")) {
+		return fmt.Errorf("%w: %s", ErrBusy, bytes.TrimSpace(out))
+	}
+	return fmt.Errorf("oscompat/fs: diskutil eject: %w: %s", err, bytes.TrimSpace(out))
+}
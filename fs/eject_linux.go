@@ -0,0 +1,41 @@
+//go:build linux
+
+package fs
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"syscall"
+)
+
+// eject unmounts v through udisks, which needs no root for removable
+// media, and powers the drive off.
+func eject(v Volume) error {
+	syscall.Sync()
+	if _, err := exec.LookPath("udisksctl"); err == nil {
+		if err := runEject("udisksctl", "unmount", "--block-device", v.Device); err != nil {
+			return err
+		}
+		// Optical drives and card readers cannot be powered off; the
+		// volume is unmounted and safe to remove either way.
+		_ = runEject("udisksctl", "power-off", "--block-device", v.Device)
+		return nil
+	}
+	if _, err := exec.LookPath("eject"); err == nil {
+		return runEject("eject", v.Path)
+	}
+	return ErrUnsupported
+}
+
+// runEject runs an unmount command, recognizing a busy volume.
+func runEject(name string, args ...string) error {
+	out, err := exec.Command(name, args...).CombinedOutput()
+	if err == nil {
+		return nil
+	}
+	if bytes.Contains(bytes.ToLower(out), []byte("busy")) {
+		return fmt.Errorf("%w: %s", ErrBusy, bytes.TrimSpace(out))
+	}
+	return fmt.Errorf("oscompat/fs: %s: %w: %s", name, err, bytes.TrimSpace(out))
+}
@@ -0,0 +1,7 @@
+//go:build !linux && !darwin && !windows
+
+package fs
+
+func eject(Volume) error {
+	return ErrUnsupported
+}
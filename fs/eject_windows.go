@@ -0,0 +1,71 @@
+//go:build windows
+
+package fs
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+	"time"
+)
+
+const (
+	fsctlLockVolume          = 0x00090018
+	fsctlDismountVolume      = 0x00090020
+	ioctlStorageMediaRemoval = 0x002D4804
+	ioctlStorageEjectMedia   = 0x002D4808
+
+	// lockRetries and lockBackoff give the shell and indexers time to let
+	// go of a volume that was just written to.
+	lockRetries = 10
+	lockBackoff = 100 * time.Millisecond
+)
+
+// eject performs the sequence Explorer's "Eject" uses: flush, lock,
+// dismount, allow removal, eject.
+func eject(v Volume) error {
+	name, err := syscall.UTF16PtrFromString(`\\.\` + v.Path[:2])
+	if err != nil {
+		return err
+	}
+	h, err := syscall.CreateFile(name, syscall.GENERIC_READ|syscall.GENERIC_WRITE,
+		syscall.FILE_SHARE_READ|syscall.FILE_SHARE_WRITE, nil, syscall.OPEN_EXISTING, 0, 0)
+	if err != nil {
+		return fmt.Errorf("oscompat/fs: open volume: %w", err)
+	}
+	defer func() { _ = syscall.CloseHandle(h) }()
+
+	if err := syscall.FlushFileBuffers(h); err != nil {
+		return fmt.Errorf("oscompat/fs: flush volume: %w", err)
+	}
+	if err := lockVolume(h); err != nil {
+		return err
+	}
+	var n uint32
+	if err := syscall.DeviceIoControl(h, fsctlDismountVolume, nil, 0, nil, 0, &n, nil); err != nil {
+		return fmt.Errorf("oscompat/fs: dismount volume: %w", err)
+	}
+	prevent := byte(0) // PREVENT_MEDIA_REMOVAL{PreventMediaRemoval: FALSE}
+	_ = syscall.DeviceIoControl(h, ioctlStorageMediaRemoval, &prevent, 1, nil, 0, &n, nil)
+	if err := syscall.DeviceIoControl(h, ioctlStorageEjectMedia, nil, 0, nil, 0, &n, nil); err != nil {
+		return fmt.Errorf("oscompat/fs: eject media: %w", err)
+	}
+	return nil
+}
+
+// lockVolume takes the exclusive volume lock, which fails while any file
+// on the volume is open.
+func lockVolume(h syscall.Handle) error {
+	var n uint32
+	var err error
+	for i := 0; i < lockRetries; i++ {
+		if err = syscall.DeviceIoControl(h, fsctlLockVolume, nil, 0, nil, 0, &n, nil); err == nil {
+			return nil
+		}
+		if !errors.Is(err, syscall.ERROR_ACCESS_DENIED) && !errors.Is(err, errorSharingViolation) {
+			break
+		}
+		time.Sleep(lockBackoff)
+	}
+	return fmt.Errorf("%w: %v", ErrBusy, err)
+}
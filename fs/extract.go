@@ -0,0 +1,439 @@
+package fs
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/grokify/oscompat/tsync"
+)
+
+// Extraction errors.
+var (
+	// ErrArchiveTooLarge is returned when extraction would exceed
+	// ExtractOptions.MaxBytes or MaxFiles.
+	ErrArchiveTooLarge = errors.New("oscompat/fs: archive exceeds extraction limits")
+
+	// ErrUnsafeLink is returned for a symbolic link that points outside
+	// the destination, or for an entry that would be written through a
+	// symbolic link.
+	ErrUnsafeLink = errors.New("oscompat/fs: unsafe link in archive")
+
+	// ErrInvalidName is returned for entry names that cannot be created
+	// on this platform.
+	ErrInvalidName = errors.New("oscompat/fs: invalid name for this platform")
+)
+
+// SymlinkPolicy controls what extraction does with symbolic links.
+type SymlinkPolicy int
+
+const (
+	// SymlinkSkip ignores symbolic link entries. It is the default.
+	SymlinkSkip SymlinkPolicy = iota
+
+	// SymlinkWithinRoot creates symbolic links whose targets are
+	// relative and stay inside the destination without passing through
+	// another link, and fails on others with ErrUnsafeLink. Creating links on Windows needs Developer Mode or
+	// administrator rights.
+	SymlinkWithinRoot
+
+	// SymlinkError fails on any symbolic link entry with ErrUnsafeLink.
+	SymlinkError
+)
+
+// ExtractOptions controls ExtractZip and ExtractTar. The zero value, or a
+// nil pointer, extracts regular files and directories with the
+// archive's permission bits, skips symbolic links, and refuses to
+// overwrite existing files.
+type ExtractOptions struct {
+	// Sanitize rewrites each path component with SanitizeName, so that
+	// names invalid on Windows extract the same way on every platform.
+	// Without it, such names fail on Windows.
+	Sanitize bool
+
+	// Symlinks is the policy for symbolic link entries.
+	Symlinks SymlinkPolicy
+
+	// Overwrite replaces existing files instead of failing.
+	Overwrite bool
+
+	// PreserveTimes restores modification times, truncated to
+	// TimePrecision.
+	PreserveTimes bool
+
+	// TimePrecision is the precision modification times are truncated
+	// to, so that extracted trees compare equal with tsync regardless of
+	// the destination filesystem. Zero means time.Second; use
+	// tsync.FAT32Tolerance for FAT32 destinations.
+	TimePrecision time.Duration
+
+	// MaxBytes and MaxFiles limit the total size and number of entries
+	// written, guarding against decompression bombs. Zero means no limit.
+	MaxBytes int64
+	MaxFiles int
+}
+
+// ExtractSafePath returns the path under destRoot where an archive entry
+// named entryName should be written. It fails with ErrPathTraversal or
+// ErrAbsolutePath for names that would land outside destRoot ("Zip
+// Slip"), whether they use / or \ separators, and with ErrInvalidName for
+// names containing ':' on Windows.
+func ExtractSafePath(destRoot, entryName string) (string, error) {
+	name := strings.TrimRight(NormalizePath(entryName), "/")
+	if err := ValidatePath(name); err != nil {
+		return "", fmt.Errorf("%w: %q", err, entryName)
+	}
+	// Reject the "\\server\share" form that survives normalization.
+	if strings.HasPrefix(name, "/") {
+		return "", fmt.Errorf("%w: %q", ErrAbsolutePath, entryName)
+	}
+	// On Windows, "name:stream" writes an alternate data stream.
+	if runtime.GOOS == "windows" && strings.Contains(name, ":") {
+		return "", fmt.Errorf("%w: %q", ErrInvalidName, entryName)
+	}
+	return SafeJoin(destRoot, name)
+}
+
+// ExtractZip extracts a zip archive into destRoot, creating it if needed.
+func ExtractZip(r *zip.Reader, destRoot string, opts *ExtractOptions) error {
+	x, err := newExtractor(destRoot, opts)
+	if err != nil {
+		return err
+	}
+	for _, f := range r.File {
+		mode := f.Mode()
+		switch {
+		case mode.IsDir():
+			err = x.dir(f.Name, mode, f.Modified)
+		case mode&fs.ModeSymlink != 0:
+			err = x.zipSymlink(f)
+		case mode.IsRegular():
+			err = x.zipFile(f)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return x.finish()
+}
+
+func (x *extractor) zipFile(f *zip.File) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rc.Close() }()
+	return x.file(f.Name, f.Mode(), f.Modified, rc)
+}
+
+func (x *extractor) zipSymlink(f *zip.File) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rc.Close() }()
+	target, err := io.ReadAll(io.LimitReader(rc, 4096))
+	if err != nil {
+		return err
+	}
+	return x.symlink(f.Name, string(target))
+}
+
+// ExtractTar extracts a tar stream into destRoot, creating it if needed.
+// Decompress the stream first, for example with gzip.NewReader. Hard
+// links are created only to entries inside destRoot; device, FIFO, and
+// other special entries are skipped.
+func ExtractTar(r io.Reader, destRoot string, opts *ExtractOptions) error {
+	x, err := newExtractor(destRoot, opts)
+	if err != nil {
+		return err
+	}
+	tr := tar.NewReader(r)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		mode := h.FileInfo().Mode()
+		switch h.Typeflag {
+		case tar.TypeDir:
+			err = x.dir(h.Name, mode, h.ModTime)
+		case tar.TypeReg, tar.TypeRegA:
+			err = x.file(h.Name, mode, h.ModTime, tr)
+		case tar.TypeSymlink:
+			err = x.symlink(h.Name, h.Linkname)
+		case tar.TypeLink:
+			err = x.hardlink(h.Name, h.Linkname)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return x.finish()
+}
+
+// extractor writes archive entries under root.
+type extractor struct {
+	root     string
+	opts     ExtractOptions
+	written  int64
+	files    int
+	dirTimes map[string]time.Time
+}
+
+func newExtractor(destRoot string, opts *ExtractOptions) (*extractor, error) {
+	x := &extractor{root: destRoot, dirTimes: make(map[string]time.Time)}
+	if opts != nil {
+		x.opts = *opts
+	}
+	if x.opts.TimePrecision <= 0 {
+		x.opts.TimePrecision = time.Second
+	}
+	if err := MkdirAll(destRoot, 0); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// target validates an entry name and returns its destination, after
+// checking that no existing parent directory is a symbolic link.
+func (x *extractor) target(name string) (string, error) {
+	if x.opts.Sanitize {
+		parts := strings.Split(strings.Trim(NormalizePath(name), "/"), "/")
+		for i, p := range parts {
+			if p != "." && p != ".." {
+				parts[i] = SanitizeName(p)
+			}
+		}
+		name = strings.Join(parts, "/")
+	}
+	dest, err := ExtractSafePath(x.root, name)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(x.root, dest)
+	if err != nil {
+		return "", err
+	}
+	p := x.root
+	parts := strings.Split(rel, string(filepath.Separator))
+	for _, part := range parts[:len(parts)-1] {
+		p = filepath.Join(p, part)
+		if info, err := os.Lstat(p); err == nil && info.Mode()&fs.ModeSymlink != 0 {
+			return "", fmt.Errorf("%w: %q is written through a link", ErrUnsafeLink, name)
+		}
+	}
+	return dest, nil
+}
+
+// count applies MaxFiles.
+func (x *extractor) count() error {
+	x.files++
+	if x.opts.MaxFiles > 0 && x.files > x.opts.MaxFiles {
+		return ErrArchiveTooLarge
+	}
+	return nil
+}
+
+func (x *extractor) dir(name string, mode fs.FileMode, mtime time.Time) error {
+	dest, err := x.target(name)
+	if err != nil {
+		return err
+	}
+	if err := x.count(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(LongPath(dest), dirPerm(mode)); err != nil {
+		return err
+	}
+	if x.opts.PreserveTimes && !mtime.IsZero() {
+		x.dirTimes[dest] = mtime
+	}
+	return nil
+}
+
+func (x *extractor) file(name string, mode fs.FileMode, mtime time.Time, r io.Reader) error {
+	dest, err := x.target(name)
+	if err != nil {
+		return err
+	}
+	if err := x.count(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(LongPath(dest)), DefaultDirPerm); err != nil {
+		return err
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if x.opts.Overwrite {
+		// Remove rather than truncate, so that an existing link is
+		// replaced instead of followed.
+		if err := os.Remove(LongPath(dest)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	f, err := os.OpenFile(LongPath(dest), flags, filePerm(mode))
+	if err != nil {
+		return err
+	}
+	if x.opts.MaxBytes > 0 {
+		r = io.LimitReader(r, x.opts.MaxBytes-x.written+1)
+	}
+	n, err := io.Copy(f, r)
+	x.written += n
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if x.opts.MaxBytes > 0 && x.written > x.opts.MaxBytes {
+		return ErrArchiveTooLarge
+	}
+	if x.opts.PreserveTimes && !mtime.IsZero() {
+		mtime = tsync.Truncate(mtime, x.opts.TimePrecision)
+		return os.Chtimes(LongPath(dest), mtime, mtime)
+	}
+	return nil
+}
+
+func (x *extractor) symlink(name, linkTarget string) error {
+	switch x.opts.Symlinks {
+	case SymlinkSkip:
+		return nil
+	case SymlinkError:
+		return fmt.Errorf("%w: %q", ErrUnsafeLink, name)
+	}
+	dest, err := x.target(name)
+	if err != nil {
+		return err
+	}
+	// The target is resolved from the link's directory and must stay
+	// inside the root, both lexically and through what is already on disk.
+	t := NormalizePath(linkTarget)
+	if linkTarget == "" || strings.HasPrefix(t, "/") || strings.Contains(t, ":") ||
+		ValidatePath(path.Join(path.Dir(NormalizePath(name)), t)) != nil ||
+		!x.linkWithinRoot(dest, strings.ReplaceAll(linkTarget, "\\", "/")) {
+		return fmt.Errorf("%w: %q -> %q", ErrUnsafeLink, name, linkTarget)
+	}
+	if err := x.count(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), DefaultDirPerm); err != nil {
+		return err
+	}
+	if x.opts.Overwrite {
+		if err := os.Remove(dest); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return os.Symlink(filepath.FromSlash(t), dest)
+}
+
+// linkWithinRoot resolves target, as written in the archive rather than
+// cleaned, one component at a time from the directory of the link at
+// dest, and reports whether it stays inside the root. A component that is
+// already a link on disk is refused: an earlier
+// entry such as "s/b -> .." would otherwise let "s/e -> b/.." clean to "s"
+// lexically while resolving to the parent of the root.
+func (x *extractor) linkWithinRoot(dest, target string) bool {
+	rel, err := filepath.Rel(x.root, filepath.Dir(dest))
+	if err != nil {
+		return false
+	}
+	var parts []string
+	if rel != "." {
+		parts = strings.Split(rel, string(filepath.Separator))
+	}
+	for _, part := range strings.Split(target, "/") {
+		switch part {
+		case "", ".":
+			continue
+		case "..":
+			if len(parts) == 0 {
+				return false
+			}
+			parts = parts[:len(parts)-1]
+			continue
+		}
+		parts = append(parts, part)
+		info, err := os.Lstat(filepath.Join(x.root, filepath.Join(parts...)))
+		if err == nil && info.Mode()&fs.ModeSymlink != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (x *extractor) hardlink(name, linkTarget string) error {
+	dest, err := x.target(name)
+	if err != nil {
+		return err
+	}
+	src, err := x.target(linkTarget)
+	if err != nil {
+		return fmt.Errorf("%w: %q -> %q", ErrUnsafeLink, name, linkTarget)
+	}
+	if err := x.count(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), DefaultDirPerm); err != nil {
+		return err
+	}
+	if x.opts.Overwrite {
+		if err := os.Remove(dest); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+	}
+	return os.Link(src, dest)
+}
+
+// finish sets directory times, deepest first, since writing into a
+// directory updates its time.
+func (x *extractor) finish() error {
+	dirs := make([]string, 0, len(x.dirTimes))
+	for d := range x.dirTimes {
+		dirs = append(dirs, d)
+	}
+	slices.SortFunc(dirs, func(a, b string) int {
+		return len(b) - len(a)
+	})
+	for _, d := range dirs {
+		mtime := tsync.Truncate(x.dirTimes[d], x.opts.TimePrecision)
+		if err := os.Chtimes(LongPath(d), mtime, mtime); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// filePerm returns the permission bits for an extracted file, without
+// setuid, setgid, and sticky bits.
+func filePerm(mode fs.FileMode) fs.FileMode {
+	if perm := mode.Perm(); perm != 0 {
+		return perm
+	}
+	return DefaultFilePerm
+}
+
+// dirPerm returns the permission bits for an extracted directory, which
+// must stay writable and searchable by the owner.
+func dirPerm(mode fs.FileMode) fs.FileMode {
+	if perm := mode.Perm(); perm != 0 {
+		return perm | 0o700
+	}
+	return DefaultDirPerm
+}
@@ -0,0 +1,257 @@
+package fs_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/grokify/oscompat/fs"
+)
+
+func TestExtractSafePath(t *testing.T) {
+	root := t.TempDir()
+	good := map[string]string{
+		"a.txt":        "a.txt",
+		"dir/b.txt":    "dir/b.txt",
+		`dir\c.txt`:    "dir/c.txt",
+		"./dir/d.txt":  "dir/d.txt",
+		"dir/../e.txt": "e.txt",
+		"dir/sub/":     "dir/sub",
+	}
+	for name, want := range good {
+		got, err := fs.ExtractSafePath(root, name)
+		if err != nil {
+			t.Errorf("ExtractSafePath(%q) error: %v", name, err)
+			continue
+		}
+		if got != filepath.Join(root, filepath.FromSlash(want)) {
+			t.Errorf("ExtractSafePath(%q) = %q, want %q", name, got, want)
+		}
+	}
+
+	bad := []string{"", "../evil", `..\evil`, "dir/../../evil", "/etc/passwd", `C:\evil`, `\\server\share\evil`}
+	for _, name := range bad {
+		if _, err := fs.ExtractSafePath(root, name); err == nil {
+			t.Errorf("ExtractSafePath(%q) should fail", name)
+		}
+	}
+}
+
+func TestSanitizeName(t *testing.T) {
+	tests := map[string]string{
+		"report.pdf":   "report.pdf",
+		"a:b?c*.txt":   "a_b_c_.txt",
+		"CON":          "CON_",
+		"com1.txt":     "com1_.txt",
+		"trailing. . ": "trailing",
+		"..":           "_",
+		"tab\there":    "tab_here",
+		"über":         "über",
+	}
+	for in, want := range tests {
+		if got := fs.SanitizeName(in); got != want {
+			t.Errorf("SanitizeName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+// zipArchive builds a zip with the given files; names ending in "@"
+// become symbolic links to their content.
+func zipArchive(t *testing.T, files [][2]string) *zip.Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	mtime := time.Date(2020, 5, 17, 10, 30, 15, 0, time.UTC)
+	for _, f := range files {
+		name, content := f[0], f[1]
+		h := &zip.FileHeader{Name: name, Method: zip.Deflate, Modified: mtime}
+		if n := len(name); n > 0 && name[n-1] == '@' {
+			h.Name = name[:n-1]
+			h.SetMode(os.ModeSymlink | 0o777)
+		} else {
+			h.SetMode(0o644)
+		}
+		fw, err := w.CreateHeader(h)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := fw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestExtractZip(t *testing.T) {
+	root := t.TempDir()
+	r := zipArchive(t, [][2]string{{"dir/a.txt", "hello"}, {"b.txt", "world"}})
+	err := fs.ExtractZip(r, root, &fs.ExtractOptions{PreserveTimes: true})
+	if err != nil {
+		t.Fatalf("ExtractZip() error: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(root, "dir", "a.txt"))
+	if err != nil || string(data) != "hello" {
+		t.Errorf("dir/a.txt = %q, %v", data, err)
+	}
+	info, err := os.Stat(filepath.Join(root, "b.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2020, 5, 17, 10, 30, 15, 0, time.UTC); !info.ModTime().Equal(want) {
+		t.Errorf("b.txt mtime = %v, want %v", info.ModTime(), want)
+	}
+
+	// Existing files are not overwritten by default.
+	if err := fs.ExtractZip(r, root, nil); !errors.Is(err, os.ErrExist) {
+		t.Errorf("second ExtractZip() error = %v, want ErrExist", err)
+	}
+	if err := fs.ExtractZip(r, root, &fs.ExtractOptions{Overwrite: true}); err != nil {
+		t.Errorf("ExtractZip(Overwrite) error: %v", err)
+	}
+}
+
+func TestExtractZipSlip(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "dest")
+	r := zipArchive(t, [][2]string{{"../evil.txt", "x"}})
+	if err := fs.ExtractZip(r, root, nil); !errors.Is(err, fs.ErrPathTraversal) {
+		t.Errorf("ExtractZip() error = %v, want ErrPathTraversal", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "..", "evil.txt")); err == nil {
+		t.Error("entry written outside the destination")
+	}
+}
+
+func TestExtractSanitize(t *testing.T) {
+	root := t.TempDir()
+	r := zipArchive(t, [][2]string{{"what?/aux.txt", "x"}})
+	if err := fs.ExtractZip(r, root, &fs.ExtractOptions{Sanitize: true}); err != nil {
+		t.Fatalf("ExtractZip() error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "what_", "aux_.txt")); err != nil {
+		t.Errorf("sanitized file missing: %v", err)
+	}
+}
+
+func TestExtractSymlinks(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("creating symbolic links needs privileges on Windows")
+	}
+	files := [][2]string{{"dir/real.txt", "data"}, {"dir/link@", "real.txt"}}
+
+	root := t.TempDir()
+	if err := fs.ExtractZip(zipArchive(t, files), root, nil); err != nil {
+		t.Fatalf("ExtractZip() error: %v", err)
+	}
+	if _, err := os.Lstat(filepath.Join(root, "dir", "link")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("link extracted under SymlinkSkip: %v", err)
+	}
+
+	root = t.TempDir()
+	opts := &fs.ExtractOptions{Symlinks: fs.SymlinkWithinRoot}
+	if err := fs.ExtractZip(zipArchive(t, files), root, opts); err != nil {
+		t.Fatalf("ExtractZip() error: %v", err)
+	}
+	if target, err := os.Readlink(filepath.Join(root, "dir", "link")); err != nil || target != "real.txt" {
+		t.Errorf("Readlink() = %q, %v", target, err)
+	}
+
+	for _, evil := range [][][2]string{
+		{{"link@", "../outside"}},
+		{{"link@", "/etc"}},
+		{{"dir/link@", "../.."}},
+		// Each link is inside the root lexically, but e resolves through
+		// b to the parent of the root.
+		{{"s/b@", ".."}, {"s/e@", "b/.."}},
+	} {
+		err := fs.ExtractZip(zipArchive(t, evil), t.TempDir(), opts)
+		if !errors.Is(err, fs.ErrUnsafeLink) {
+			t.Errorf("ExtractZip(%v) error = %v, want ErrUnsafeLink", evil, err)
+		}
+	}
+
+	// An entry must not be written through a link, even one inside the
+	// root that was already there.
+	root = t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, "real"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("real", filepath.Join(root, "link")); err != nil {
+		t.Fatal(err)
+	}
+	err := fs.ExtractZip(zipArchive(t, [][2]string{{"link/x.txt", "x"}}), root, opts)
+	if !errors.Is(err, fs.ErrUnsafeLink) {
+		t.Errorf("write through link error = %v, want ErrUnsafeLink", err)
+	}
+}
+
+func TestExtractTar(t *testing.T) {
+	var buf bytes.Buffer
+	w := tar.NewWriter(&buf)
+	mtime := time.Date(2021, 1, 2, 3, 4, 5, 600_000_000, time.UTC)
+	entries := []struct {
+		h    *tar.Header
+		data string
+	}{
+		{&tar.Header{Typeflag: tar.TypeDir, Name: "pkg/", Mode: 0o755, ModTime: mtime, Format: tar.FormatPAX}, ""},
+		{&tar.Header{Typeflag: tar.TypeReg, Name: "pkg/run.sh", Mode: 0o755, Size: 2, ModTime: mtime, Format: tar.FormatPAX}, "ok"},
+		{&tar.Header{Typeflag: tar.TypeLink, Name: "pkg/again.sh", Linkname: "pkg/run.sh"}, ""},
+		{&tar.Header{Typeflag: tar.TypeChar, Name: "pkg/dev", Mode: 0o644}, ""},
+	}
+	for _, e := range entries {
+		if err := w.WriteHeader(e.h); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(e.data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	root := t.TempDir()
+	opts := &fs.ExtractOptions{PreserveTimes: true}
+	if err := fs.ExtractTar(&buf, root, opts); err != nil {
+		t.Fatalf("ExtractTar() error: %v", err)
+	}
+	for _, name := range []string{"pkg", "pkg/run.sh"} {
+		info, err := os.Stat(filepath.Join(root, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := mtime.Truncate(time.Second); !info.ModTime().Equal(want) {
+			t.Errorf("%s mtime = %v, want %v", name, info.ModTime(), want)
+		}
+	}
+	if data, err := os.ReadFile(filepath.Join(root, "pkg", "again.sh")); err != nil || string(data) != "ok" {
+		t.Errorf("hard link = %q, %v", data, err)
+	}
+	if _, err := os.Lstat(filepath.Join(root, "pkg", "dev")); err == nil {
+		t.Error("device entry extracted")
+	}
+}
+
+func TestExtractLimits(t *testing.T) {
+	r := zipArchive(t, [][2]string{{"a", "12345"}, {"b", "67890"}})
+	err := fs.ExtractZip(r, t.TempDir(), &fs.ExtractOptions{MaxBytes: 8})
+	if !errors.Is(err, fs.ErrArchiveTooLarge) {
+		t.Errorf("MaxBytes error = %v, want ErrArchiveTooLarge", err)
+	}
+	err = fs.ExtractZip(r, t.TempDir(), &fs.ExtractOptions{MaxFiles: 1})
+	if !errors.Is(err, fs.ErrArchiveTooLarge) {
+		t.Errorf("MaxFiles error = %v, want ErrArchiveTooLarge", err)
+	}
+}
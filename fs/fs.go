@@ -12,6 +12,8 @@ import (
 	"path"
 	"path/filepath"
 	"strings"
+
+	"github.com/grokify/oscompat/text"
 )
 
 // Common errors.
@@ -198,6 +200,15 @@ func WriteFilePrivate(filename string, data []byte) error {
 	return os.WriteFile(filename, data, PrivateFilePerm)
 }
 
+// WriteTextFile writes data to a file with every line ending converted to
+// eol, or to the platform-native line ending (CRLF on Windows, LF
+// elsewhere) if eol is empty. Use it for configuration files and scripts
+// meant for the local platform's tools, and text.LF for shell scripts that
+// may be copied to Unix. It uses DefaultFilePerm if perm is 0.
+func WriteTextFile(filename string, data []byte, perm os.FileMode, eol text.EOL) error {
+	return WriteFile(filename, text.Convert(data, eol), perm)
+}
+
 // IsCaseSensitive returns whether the current OS has case-sensitive file paths.
 // Returns false on Windows (case-insensitive), true on Unix/macOS (case-sensitive).
 // Note: macOS HFS+ is case-insensitive by default, but APFS can be either.
@@ -1,6 +1,7 @@
 package fs_test
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -8,6 +9,7 @@ import (
 	"time"
 
 	"github.com/grokify/oscompat/fs"
+	"github.com/grokify/oscompat/text"
 )
 
 func TestValidatePath(t *testing.T) {
@@ -280,6 +282,33 @@ func TestWriteFilePrivate(t *testing.T) {
 	}
 }
 
+func TestWriteTextFile(t *testing.T) {
+	base := t.TempDir()
+	data := []byte("line1\r\nline2\n")
+
+	tests := []struct {
+		eol  text.EOL
+		want string
+	}{
+		{text.LF, "line1\nline2\n"},
+		{text.CRLF, "line1\r\nline2\r\n"},
+		{"", string(text.Convert(data, text.NativeEOL()))},
+	}
+	for i, tt := range tests {
+		file := filepath.Join(base, fmt.Sprintf("text%d.txt", i))
+		if err := fs.WriteTextFile(file, data, 0, tt.eol); err != nil {
+			t.Fatalf("WriteTextFile() error: %v", err)
+		}
+		got, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("ReadFile() error: %v", err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("WriteTextFile(%q) content = %q, want %q", tt.eol, got, tt.want)
+		}
+	}
+}
+
 func TestPermissionConstants(t *testing.T) {
 	// Verify permission constants have expected values
 	if fs.DefaultDirPerm != 0755 {
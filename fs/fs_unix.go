@@ -6,3 +6,13 @@ package fs
 // Unix and macOS are typically case-sensitive (though macOS HFS+ is case-insensitive
 // by default, APFS can be either - we use the stricter case-sensitive assumption).
 const isCaseSensitive = true
+
+// longPath returns p unchanged; only Windows limits path length this way.
+func longPath(p string) string {
+	return p
+}
+
+// readBufferSize is the size of sequential reads that stream whole
+// files, such as ChunkReader's. Larger reads gain little on Unix, where
+// the page cache reads ahead.
+const readBufferSize = 256 << 10
@@ -2,6 +2,33 @@
 
 package fs
 
+import (
+	"path/filepath"
+	"strings"
+)
+
 // isCaseSensitive indicates whether file paths are case-sensitive on this platform.
 // Windows NTFS is case-insensitive by default.
 const isCaseSensitive = false
+
+// maxPath is MAX_PATH less the terminating NUL.
+const maxPath = 259
+
+// readBufferSize is the size of sequential reads that stream whole
+// files, such as ChunkReader's. Each ReadFile call has a noticeable
+// fixed cost on Windows, above all on SMB shares, so reads are larger
+// than on Unix; 1 MiB is what Explorer's copy engine uses.
+const readBufferSize = 1 << 20
+
+// longPath prefixes long absolute paths with \\?\, which also turns off
+// the normalization of / and . and .. components, so p is cleaned first.
+func longPath(p string) string {
+	if len(p) <= maxPath || strings.HasPrefix(p, `\\?\`) || !filepath.IsAbs(p) {
+		return p
+	}
+	p = filepath.Clean(p)
+	if strings.HasPrefix(p, `\\`) {
+		return `\\?\UNC\` + p[2:]
+	}
+	return `\\?\` + p
+}
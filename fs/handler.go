@@ -0,0 +1,46 @@
+package fs
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrNoHandler is returned by DefaultHandler when no application is
+// registered to open a file type.
+var ErrNoHandler = errors.New("oscompat/fs: no application registered for this file type")
+
+// Handler describes the application registered to open a file type.
+type Handler struct {
+	// ID identifies the application to the system: a bundle identifier
+	// on macOS, a ProgID on Windows, or a desktop file ID such as
+	// "org.gnome.TextEditor.desktop" on Linux.
+	ID string
+
+	// Name is the application's display name, such as "TextEdit".
+	Name string
+
+	// Path is the executable, or the application bundle on macOS, when
+	// it can be determined.
+	Path string
+}
+
+// DefaultHandler returns the application that opens files with extension
+// ext, with or without the leading dot, when the user double-clicks them.
+// It returns ErrNoHandler if there is none. Use it to show which
+// application "Open with default editor" will launch; open.File does the
+// launching.
+//
+// Platform behavior:
+//   - Windows: AssocQueryString, which honors the per-user UserChoice key
+//     before the HKEY_CLASSES_ROOT association.
+//   - macOS: LaunchServices, queried through NSWorkspace with osascript.
+//   - Linux and BSD: xdg-mime query default, for the MIME type the system
+//     database gives the extension; Name and Path come from the
+//     application's desktop file.
+func DefaultHandler(ext string) (Handler, error) {
+	ext = strings.ToLower(strings.TrimPrefix(ext, "."))
+	if ext == "" || strings.ContainsAny(ext, `/\`) {
+		return Handler{}, errors.New("oscompat/fs: invalid extension")
+	}
+	return defaultHandler("." + ext)
+}
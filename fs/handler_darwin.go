@@ -0,0 +1,58 @@
+//go:build darwin
+
+package fs
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// handlerScript asks LaunchServices, through NSWorkspace, for the
+// application that opens the file in argv[0], and prints its bundle
+// identifier, display name, and path on separate lines.
+const handlerScript = `ObjC.import('AppKit');
+function run(argv) {
+	const ws = $.NSWorkspace.sharedWorkspace;
+	const app = ws.URLForApplicationToOpenURL($.NSURL.fileURLWithPath(argv[0]));
+	if (app.isNil()) return '';
+	const bundle = $.NSBundle.bundleWithURL(app);
+	const id = bundle.isNil() ? '' : bundle.bundleIdentifier.js;
+	const name = $.NSFileManager.defaultManager.displayNameAtPath(app.path).js;
+	return [id, name, app.path.js].join('\n');
+}`
+
+// defaultHandler asks about an empty temporary file with the extension,
+// since LaunchServices looks up types by file.
+func defaultHandler(ext string) (Handler, error) {
+	f, err := os.CreateTemp("", "oscompat-*"+ext)
+	if err != nil {
+		return Handler{}, err
+	}
+	defer func() { _ = os.Remove(f.Name()) }()
+	if err := f.Close(); err != nil {
+		return Handler{}, err
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.Command("osascript", "-l", "JavaScript", "-e", handlerScript, f.Name())
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return Handler{}, fmt.Errorf("oscompat/fs: osascript: %s", msg)
+		}
+		return Handler{}, err
+	}
+	fields := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if len(fields) != 3 {
+		return Handler{}, fmt.Errorf("%w: %s", ErrNoHandler, ext)
+	}
+	return Handler{
+		ID:   fields[0],
+		Name: strings.TrimSuffix(fields[1], ".app"),
+		Path: fields[2],
+	}, nil
+}
@@ -0,0 +1,32 @@
+package fs_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/grokify/oscompat/fs"
+)
+
+func TestDefaultHandler(t *testing.T) {
+	for _, ext := range []string{"", ".", "a/b"} {
+		if _, err := fs.DefaultHandler(ext); err == nil {
+			t.Errorf("DefaultHandler(%q) should fail", ext)
+		}
+	}
+
+	_, err := fs.DefaultHandler(".oscompat-no-such-type")
+	if !errors.Is(err, fs.ErrNoHandler) && !errors.Is(err, fs.ErrUnsupported) {
+		t.Errorf("DefaultHandler(unknown) error = %v, want ErrNoHandler", err)
+	}
+
+	h, err := fs.DefaultHandler("txt")
+	if errors.Is(err, fs.ErrNoHandler) || errors.Is(err, fs.ErrUnsupported) {
+		t.Skipf("no handler for .txt here: %v", err)
+	}
+	if err != nil {
+		t.Fatalf("DefaultHandler(txt) error: %v", err)
+	}
+	if h.ID == "" && h.Path == "" {
+		t.Errorf("DefaultHandler(txt) = %+v, want an ID or Path", h)
+	}
+}
@@ -0,0 +1,116 @@
+//go:build !windows && !darwin
+
+package fs
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"mime"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+func defaultHandler(ext string) (Handler, error) {
+	// The mime package reads the shared-mime-info globs that xdg-mime
+	// itself consults.
+	typ := mime.TypeByExtension(ext)
+	if typ == "" {
+		typ = extTypes[ext]
+	}
+	typ, _, _ = strings.Cut(typ, ";")
+	if typ == "" {
+		return Handler{}, fmt.Errorf("%w: %s", ErrNoHandler, ext)
+	}
+	if _, err := exec.LookPath("xdg-mime"); err != nil {
+		return Handler{}, ErrUnsupported
+	}
+	out, err := exec.Command("xdg-mime", "query", "default", typ).Output()
+	if err != nil {
+		return Handler{}, fmt.Errorf("oscompat/fs: xdg-mime: %w", err)
+	}
+	id := string(bytes.TrimSpace(out))
+	if id == "" {
+		return Handler{}, fmt.Errorf("%w: %s", ErrNoHandler, ext)
+	}
+	h := Handler{ID: id}
+	for _, dir := range applicationDirs() {
+		if name, cmd, ok := readDesktopEntry(filepath.Join(dir, id)); ok {
+			h.Name = name
+			h.Path = execPath(cmd)
+			break
+		}
+	}
+	return h, nil
+}
+
+// applicationDirs returns the directories holding desktop files, in
+// precedence order, per the XDG Base Directory specification.
+func applicationDirs() []string {
+	var dirs []string
+	if d := os.Getenv("XDG_DATA_HOME"); d != "" {
+		dirs = append(dirs, d)
+	} else if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(home, ".local", "share"))
+	}
+	system := os.Getenv("XDG_DATA_DIRS")
+	if system == "" {
+		system = "/usr/local/share:/usr/share"
+	}
+	dirs = append(dirs, filepath.SplitList(system)...)
+	for i, d := range dirs {
+		dirs[i] = filepath.Join(d, "applications")
+	}
+	return dirs
+}
+
+// readDesktopEntry returns the untranslated Name and the Exec line of the
+// [Desktop Entry] group of a desktop file.
+func readDesktopEntry(path string) (name, cmd string, ok bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", "", false
+	}
+	defer func() { _ = f.Close() }()
+	inEntry := false
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if strings.HasPrefix(line, "[") {
+			inEntry = line == "[Desktop Entry]"
+			continue
+		}
+		key, value, found := strings.Cut(line, "=")
+		if !inEntry || !found {
+			continue
+		}
+		switch strings.TrimSpace(key) {
+		case "Name":
+			name = strings.TrimSpace(value)
+		case "Exec":
+			cmd = strings.TrimSpace(value)
+		}
+	}
+	return name, cmd, true
+}
+
+// execPath returns the program of a desktop file Exec line, resolved
+// against PATH, or "" if it cannot be found.
+func execPath(cmd string) string {
+	var prog string
+	if strings.HasPrefix(cmd, `"`) {
+		prog, _, _ = strings.Cut(cmd[1:], `"`)
+	} else {
+		prog, _, _ = strings.Cut(cmd, " ")
+	}
+	if prog == "" {
+		return ""
+	}
+	path, err := exec.LookPath(prog)
+	if err != nil {
+		return ""
+	}
+	return path
+}
@@ -0,0 +1,59 @@
+//go:build windows
+
+package fs
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	shlwapi               = syscall.NewLazyDLL("shlwapi.dll")
+	procAssocQueryStringW = shlwapi.NewProc("AssocQueryStringW")
+)
+
+const (
+	assocfInitIgnoreUnknown = 0x400
+
+	assocstrExecutable      = 2
+	assocstrFriendlyAppName = 4
+	assocstrProgID          = 20
+
+	// hresultNoAssociation is HRESULT_FROM_WIN32(ERROR_NO_ASSOCIATION).
+	hresultNoAssociation = 0x80070483
+)
+
+func defaultHandler(ext string) (Handler, error) {
+	exe, err := assocQuery(ext, assocstrExecutable)
+	if err != nil {
+		return Handler{}, err
+	}
+	h := Handler{Path: exe}
+	// Some associations, such as those of UWP apps, have no ProgID or
+	// friendly name; the executable is enough to report them.
+	h.ID, _ = assocQuery(ext, assocstrProgID)
+	h.Name, _ = assocQuery(ext, assocstrFriendlyAppName)
+	return h, nil
+}
+
+// assocQuery returns one string of the association for ext. The
+// IGNOREUNKNOWN flag keeps it from reporting the "Open with" dialog for
+// unregistered types.
+func assocQuery(ext string, str uint32) (string, error) {
+	p, err := syscall.UTF16PtrFromString(ext)
+	if err != nil {
+		return "", err
+	}
+	buf := make([]uint16, syscall.MAX_LONG_PATH)
+	n := uint32(len(buf))
+	hr, _, _ := procAssocQueryStringW.Call(assocfInitIgnoreUnknown, uintptr(str),
+		uintptr(unsafe.Pointer(p)), 0, uintptr(unsafe.Pointer(&buf[0])), uintptr(unsafe.Pointer(&n)))
+	switch uint32(hr) {
+	case 0:
+		return syscall.UTF16ToString(buf), nil
+	case hresultNoAssociation:
+		return "", fmt.Errorf("%w: %s", ErrNoHandler, ext)
+	}
+	return "", fmt.Errorf("oscompat/fs: AssocQueryString: HRESULT %#x", uint32(hr))
+}
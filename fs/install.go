@@ -0,0 +1,160 @@
+package fs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"syscall"
+
+	"github.com/grokify/oscompat/paths"
+	"github.com/grokify/oscompat/process"
+)
+
+// InstallScope says where an InstallPlan places a binary.
+type InstallScope int
+
+const (
+	// InstallInPlace replaces the binary where it is now.
+	InstallInPlace InstallScope = iota
+
+	// InstallUser places the binary in the per-user bin directory.
+	InstallUser
+)
+
+// String returns the name of the scope.
+func (s InstallScope) String() string {
+	switch s {
+	case InstallInPlace:
+		return "in-place"
+	case InstallUser:
+		return "user"
+	default:
+		return fmt.Sprintf("InstallScope(%d)", int(s))
+	}
+}
+
+// InstallOptions configures InstallTarget.
+type InstallOptions struct {
+	// Current is the path of the installed binary. The default is the
+	// running executable, with symbolic links resolved.
+	Current string
+
+	// Name is the file name to install under in the per-user bin
+	// directory. The default is the base name of Current.
+	Name string
+
+	// PreferUser moves the binary to the per-user bin directory rather
+	// than requiring elevation when its current directory is not
+	// writable. When false, such a plan has NeedsElevation set instead.
+	PreferUser bool
+}
+
+// InstallPlan is the decision made by InstallTarget.
+type InstallPlan struct {
+	Scope InstallScope
+
+	// Path is where the new binary should be written, and Dir its
+	// directory.
+	Path string
+	Dir  string
+
+	// NeedsElevation is set when writing to Dir requires administrator
+	// or root privileges the process does not have; relaunch elevated
+	// with process.RunElevated before installing.
+	NeedsElevation bool
+
+	// OnPath reports whether Dir is listed in PATH. A per-user bin
+	// directory often is not, and the user should be told to add it.
+	OnPath bool
+
+	// Reason explains the decision for logs and messages.
+	Reason string
+}
+
+// InstallTarget decides where a self-updating binary should place its
+// new version. It keeps the binary where it is when that directory is
+// writable; otherwise it plans an elevated in-place update or, with
+// PreferUser, a move to paths.UserBin. The plan is only a decision:
+// nothing is written, though the directory is probed for writability.
+func InstallTarget(opts *InstallOptions) (InstallPlan, error) {
+	var o InstallOptions
+	if opts != nil {
+		o = *opts
+	}
+	current := o.Current
+	if current == "" {
+		exe, err := os.Executable()
+		if err != nil {
+			return InstallPlan{}, err
+		}
+		if current, err = filepath.EvalSymlinks(exe); err != nil {
+			return InstallPlan{}, err
+		}
+	}
+	current, err := filepath.Abs(current)
+	if err != nil {
+		return InstallPlan{}, err
+	}
+	name := o.Name
+	if name == "" {
+		name = filepath.Base(current)
+	}
+
+	dir := filepath.Dir(current)
+	plan := InstallPlan{Scope: InstallInPlace, Path: current, Dir: dir}
+	writable, err := dirWritable(dir)
+	switch {
+	case err != nil:
+		return InstallPlan{}, err
+	case writable:
+		plan.Reason = "current location is writable"
+	case !o.PreferUser && !process.IsElevated():
+		plan.NeedsElevation = true
+		plan.Reason = "current location requires elevation"
+	default:
+		userBin, err := paths.UserBin()
+		if err != nil {
+			return InstallPlan{}, err
+		}
+		plan = InstallPlan{
+			Scope:  InstallUser,
+			Path:   filepath.Join(userBin, name),
+			Dir:    userBin,
+			Reason: "current location is not writable",
+		}
+	}
+	plan.OnPath = onPath(plan.Dir)
+	return plan, nil
+}
+
+// dirWritable reports whether a file can be created in dir, by creating
+// one; permission bits alone miss ACLs, read-only mounts, and root.
+func dirWritable(dir string) (bool, error) {
+	f, err := os.CreateTemp(dir, ".oscompat-probe-*")
+	if err != nil {
+		if errors.Is(err, os.ErrPermission) || errors.Is(err, syscall.EROFS) {
+			return false, nil
+		}
+		return false, err
+	}
+	name := f.Name()
+	if err := errors.Join(f.Close(), os.Remove(name)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// onPath reports whether dir is listed in PATH.
+func onPath(dir string) bool {
+	return slices.ContainsFunc(filepath.SplitList(os.Getenv("PATH")), func(p string) bool {
+		p = filepath.Clean(p)
+		if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+			return strings.EqualFold(p, dir)
+		}
+		return p == dir
+	})
+}
@@ -0,0 +1,62 @@
+package fs_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/grokify/oscompat/fs"
+	"github.com/grokify/oscompat/process"
+)
+
+func TestInstallTargetInPlace(t *testing.T) {
+	dir := t.TempDir()
+	current := filepath.Join(dir, "tool")
+	t.Setenv("PATH", dir)
+
+	plan, err := fs.InstallTarget(&fs.InstallOptions{Current: current})
+	if err != nil {
+		t.Fatalf("InstallTarget() error: %v", err)
+	}
+	if plan.Scope != fs.InstallInPlace || plan.Path != current || plan.NeedsElevation {
+		t.Errorf("InstallTarget() = %+v, want in-place without elevation", plan)
+	}
+	if !plan.OnPath {
+		t.Error("OnPath = false for a directory in PATH")
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("probe left %d files behind", len(entries))
+	}
+}
+
+func TestInstallTargetReadOnly(t *testing.T) {
+	if runtime.GOOS == "windows" || process.IsElevated() {
+		t.Skip("needs an unprivileged Unix user to make a directory unwritable")
+	}
+	dir := t.TempDir()
+	if err := os.Chmod(dir, 0o555); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chmod(dir, 0o755) })
+	current := filepath.Join(dir, "tool")
+	bin := t.TempDir()
+	t.Setenv("XDG_BIN_HOME", bin)
+	t.Setenv("PATH", "")
+
+	plan, err := fs.InstallTarget(&fs.InstallOptions{Current: current})
+	if err != nil {
+		t.Fatalf("InstallTarget() error: %v", err)
+	}
+	if plan.Scope != fs.InstallInPlace || !plan.NeedsElevation {
+		t.Errorf("InstallTarget() = %+v, want in-place needing elevation", plan)
+	}
+
+	plan, err = fs.InstallTarget(&fs.InstallOptions{Current: current, Name: "tool2", PreferUser: true})
+	if err != nil {
+		t.Fatalf("InstallTarget(PreferUser) error: %v", err)
+	}
+	if plan.Scope != fs.InstallUser || plan.Path != filepath.Join(bin, "tool2") || plan.OnPath {
+		t.Errorf("InstallTarget(PreferUser) = %+v", plan)
+	}
+}
@@ -0,0 +1,191 @@
+// Package journal reports what changed under a directory since a saved
+// cursor, from the filesystem's own change records, so that sync and
+// indexing tools can rescan only those paths instead of walking the
+// whole tree.
+//
+// Each platform uses its own source:
+//   - Windows: the NTFS or ReFS update sequence number (USN) change
+//     journal of the volume. Cursors persist across restarts and reboots
+//     until the journal wraps. Opening the volume requires administrator
+//     privileges.
+//   - Linux: fanotify with a filesystem mark (kernel 5.9 or later, 64-bit
+//     platforms), which requires CAP_SYS_ADMIN and CAP_DAC_READ_SEARCH.
+//     The kernel keeps no history, so changes are only recorded while the
+//     Journal is open and cursors are only valid for the Journal that
+//     issued them.
+//   - Elsewhere: ErrUnsupported.
+//
+// Whenever Since returns ErrCursorExpired, some changes were lost and the
+// caller must fall back to a full scan, then continue from a fresh Cursor.
+package journal
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+var (
+	// ErrUnsupported is returned by Open when the platform or filesystem
+	// has no change journal usable by this package. It matches
+	// errors.ErrUnsupported.
+	ErrUnsupported = fmt.Errorf("oscompat/fs/journal: %w", errors.ErrUnsupported)
+
+	// ErrCursorExpired is returned by Since when changes after the cursor
+	// are no longer known: the journal was recreated or wrapped, its
+	// event queue overflowed, or the cursor came from another Journal.
+	ErrCursorExpired = errors.New("oscompat/fs/journal: cursor expired, full rescan required")
+
+	// ErrInvalidCursor is returned by Since for a string that is not a
+	// cursor issued by this package.
+	ErrInvalidCursor = errors.New("oscompat/fs/journal: invalid cursor")
+)
+
+// Cursor marks a position in a journal. It is an opaque string that can
+// be stored and, where Journal.Persistent is true, used after a restart.
+type Cursor string
+
+// Op describes what happened to a path. A Change may combine several.
+type Op uint32
+
+const (
+	Created     Op = 1 << iota // the path was created
+	Removed                    // the path was deleted
+	Modified                   // the file's data changed
+	Metadata                   // attributes, permissions, or times changed
+	RenamedFrom                // the path was renamed away
+	RenamedTo                  // another path was renamed to this one
+)
+
+// String returns the names of the operations joined by "|", such as
+// "created|modified".
+func (op Op) String() string {
+	names := []string{"created", "removed", "modified", "metadata", "renamed-from", "renamed-to"}
+	var parts []string
+	for i, name := range names {
+		if op&(1<<i) != 0 {
+			parts = append(parts, name)
+		}
+	}
+	if len(parts) == 0 {
+		return fmt.Sprintf("Op(%d)", uint32(op))
+	}
+	return strings.Join(parts, "|")
+}
+
+// Change is a path that changed and how. Since reports each path once,
+// with every operation seen for it, in the order the paths first changed.
+// A path's current state should be read from the filesystem: a path that
+// was created and then removed is reported with both operations.
+type Change struct {
+	Path string
+	Op   Op
+}
+
+// Journal reads the change records for one directory tree.
+type Journal struct {
+	root string
+	b    backend
+}
+
+// backend is the platform change source.
+type backend interface {
+	// cursor returns the current position.
+	cursor() (Cursor, error)
+	// since returns the changes after c, unfiltered and uncoalesced, and
+	// the position after them.
+	since(c Cursor) ([]Change, Cursor, error)
+	// persistent reports whether cursors survive the process.
+	persistent() bool
+	close() error
+}
+
+// Open opens the change journal for the directory root. Changes outside
+// root are not reported.
+func Open(root string) (*Journal, error) {
+	abs, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	abs, err = filepath.EvalSymlinks(abs)
+	if err != nil {
+		return nil, err
+	}
+	b, err := openBackend(abs)
+	if err != nil {
+		return nil, err
+	}
+	return &Journal{root: abs, b: b}, nil
+}
+
+// Root returns the directory the journal reports on, made absolute with
+// symbolic links resolved.
+func (j *Journal) Root() string {
+	return j.root
+}
+
+// Persistent reports whether cursors remain valid after the Journal is
+// closed, across process restarts and reboots. It is true for the USN
+// journal and false for fanotify.
+func (j *Journal) Persistent() bool {
+	return j.b.persistent()
+}
+
+// Cursor returns the current position. Changes made after it are
+// reported by Since.
+func (j *Journal) Cursor() (Cursor, error) {
+	return j.b.cursor()
+}
+
+// Since returns the changes under the root made after c, and a cursor to
+// pass to the next call. It returns ErrCursorExpired when the journal no
+// longer covers c; rescan the tree and start again from Cursor.
+func (j *Journal) Since(c Cursor) ([]Change, Cursor, error) {
+	raw, next, err := j.b.since(c)
+	if err != nil {
+		return nil, c, err
+	}
+	return coalesce(j.root, raw), next, nil
+}
+
+// Close releases the journal. On Linux it stops recording changes.
+func (j *Journal) Close() error {
+	return j.b.close()
+}
+
+// coalesce keeps the changes under root and merges those for the same
+// path, keeping the order in which paths first appear.
+func coalesce(root string, raw []Change) []Change {
+	var out []Change
+	index := map[string]int{}
+	for _, c := range raw {
+		if !under(root, c.Path) {
+			continue
+		}
+		key := c.Path
+		if runtime.GOOS == "windows" {
+			key = strings.ToLower(key)
+		}
+		if i, ok := index[key]; ok {
+			out[i].Op |= c.Op
+			continue
+		}
+		index[key] = len(out)
+		out = append(out, c)
+	}
+	return out
+}
+
+// under reports whether path is root or inside it.
+func under(root, path string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
+	}
+	if runtime.GOOS == "windows" && !strings.EqualFold(filepath.VolumeName(root), filepath.VolumeName(path)) {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
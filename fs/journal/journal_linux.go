@@ -0,0 +1,289 @@
+//go:build linux
+
+package journal
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"unsafe"
+
+	"github.com/grokify/oscompat/id"
+)
+
+const (
+	fanClassNotif     = 0x0
+	fanCloexec        = 0x1
+	fanNonblock       = 0x2
+	fanReportDFIDName = 0x400 | 0x800 // FAN_REPORT_DIR_FID | FAN_REPORT_NAME
+
+	fanMarkAdd        = 0x1
+	fanMarkFilesystem = 0x100
+
+	fanModify    = 0x2
+	fanAttrib    = 0x4
+	fanMovedFrom = 0x40
+	fanMovedTo   = 0x80
+	fanCreate    = 0x100
+	fanDelete    = 0x200
+	fanQOverflow = 0x4000
+	fanOnDir     = 0x40000000
+
+	fanEventInfoDFIDName = 2
+	fanEventInfoDFID     = 3
+
+	atFDCWD = -100
+	oPath   = 0x200000
+
+	// maxLog bounds the changes kept for cursors that have not caught
+	// up; older ones are dropped and their cursors expire.
+	maxLog = 1 << 20
+)
+
+// fanotifySyscalls holds fanotify_init, fanotify_mark, and
+// open_by_handle_at, which the syscall package does not export on every
+// architecture. Only 64-bit platforms are listed, where fanotify_mark
+// takes its 64-bit mask in one register.
+var fanotifySyscalls = map[string][3]uintptr{
+	"amd64":   {300, 301, 304},
+	"arm64":   {262, 263, 265},
+	"loong64": {262, 263, 265},
+	"riscv64": {262, 263, 265},
+}
+
+// fanotify records the events of a filesystem mark in memory, numbered
+// from the start of the session.
+type fanotify struct {
+	fd      int
+	mountFD int
+	session string
+	trap    [3]uintptr
+
+	mu   sync.Mutex
+	seq  uint64   // changes recorded so far
+	base uint64   // sequence number of log[0]
+	lost uint64   // cursors below this missed changes
+	log  []Change // changes base+1 ... seq
+	buf  []byte
+}
+
+func openBackend(root string) (backend, error) {
+	trap, ok := fanotifySyscalls[runtime.GOARCH]
+	if !ok {
+		return nil, ErrUnsupported
+	}
+	fd, _, errno := syscall.Syscall(trap[0], fanClassNotif|fanCloexec|fanNonblock|fanReportDFIDName,
+		uintptr(syscall.O_RDONLY|syscall.O_CLOEXEC), 0)
+	switch errno {
+	case 0:
+	case syscall.EINVAL, syscall.ENOSYS:
+		// Kernels before 5.9 reject FAN_REPORT_DFID_NAME.
+		return nil, ErrUnsupported
+	case syscall.EPERM:
+		return nil, fmt.Errorf("oscompat/fs/journal: fanotify requires CAP_SYS_ADMIN: %w", os.ErrPermission)
+	default:
+		return nil, fmt.Errorf("oscompat/fs/journal: fanotify_init: %w", errno)
+	}
+	f := &fanotify{fd: int(fd), mountFD: -1, trap: trap, buf: make([]byte, 64<<10)}
+
+	path, err := syscall.BytePtrFromString(root)
+	if err != nil {
+		_ = f.close()
+		return nil, err
+	}
+	mask := uintptr(fanCreate | fanDelete | fanMovedFrom | fanMovedTo | fanModify | fanAttrib | fanOnDir)
+	dirFD := atFDCWD
+	_, _, errno = syscall.Syscall6(trap[1], fd, fanMarkAdd|fanMarkFilesystem, mask,
+		uintptr(dirFD), uintptr(unsafe.Pointer(path)), 0)
+	switch errno {
+	case 0:
+	case syscall.EXDEV, syscall.ENODEV, syscall.EOPNOTSUPP, syscall.EINVAL:
+		// The filesystem cannot report file handles, as with some FUSE
+		// and network filesystems.
+		_ = f.close()
+		return nil, ErrUnsupported
+	default:
+		_ = f.close()
+		return nil, fmt.Errorf("oscompat/fs/journal: fanotify_mark %s: %w", root, errno)
+	}
+
+	if f.mountFD, err = syscall.Open(root, syscall.O_RDONLY|syscall.O_DIRECTORY|syscall.O_CLOEXEC, 0); err != nil {
+		_ = f.close()
+		return nil, err
+	}
+	if f.session, err = id.GenerateE(8); err != nil {
+		_ = f.close()
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *fanotify) persistent() bool { return false }
+
+func (f *fanotify) cursor() (Cursor, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.drain(); err != nil {
+		return "", err
+	}
+	return f.cursorAt(f.seq), nil
+}
+
+func (f *fanotify) cursorAt(seq uint64) Cursor {
+	return Cursor("fanotify:" + f.session + ":" + strconv.FormatUint(seq, 10))
+}
+
+func (f *fanotify) since(c Cursor) ([]Change, Cursor, error) {
+	rest, ok := strings.CutPrefix(string(c), "fanotify:")
+	session, num, ok2 := strings.Cut(rest, ":")
+	n, err := strconv.ParseUint(num, 10, 64)
+	if !ok || !ok2 || err != nil {
+		return nil, "", ErrInvalidCursor
+	}
+	if session != f.session {
+		return nil, "", ErrCursorExpired
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.drain(); err != nil {
+		return nil, "", err
+	}
+	switch {
+	case n > f.seq:
+		return nil, "", ErrInvalidCursor
+	case n < f.lost || n < f.base:
+		return nil, "", ErrCursorExpired
+	}
+	// Changes up to n have been consumed; drop them.
+	f.log = f.log[n-f.base:]
+	f.base = n
+	return append([]Change(nil), f.log...), f.cursorAt(f.seq), nil
+}
+
+func (f *fanotify) close() error {
+	err := syscall.Close(f.fd)
+	if f.mountFD >= 0 {
+		if mountErr := syscall.Close(f.mountFD); err == nil {
+			err = mountErr
+		}
+	}
+	return err
+}
+
+// drain reads every queued event into the log.
+func (f *fanotify) drain() error {
+	for {
+		n, err := syscall.Read(f.fd, f.buf)
+		if err == syscall.EINTR {
+			continue
+		}
+		if err == syscall.EAGAIN {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("oscompat/fs/journal: read fanotify: %w", err)
+		}
+		if n <= 0 {
+			return nil
+		}
+		f.parse(f.buf[:n])
+	}
+}
+
+// parse decodes struct fanotify_event_metadata records and their info
+// records.
+func (f *fanotify) parse(buf []byte) {
+	ne := binary.NativeEndian
+	for len(buf) >= 24 {
+		eventLen := int(ne.Uint32(buf[0:]))
+		metaLen := int(ne.Uint16(buf[6:]))
+		mask := ne.Uint64(buf[8:])
+		if eventLen < 24 || eventLen > len(buf) {
+			return
+		}
+		event := buf[:eventLen]
+		buf = buf[eventLen:]
+
+		if mask&fanQOverflow != 0 {
+			f.seq++
+			f.lost = f.seq
+			continue
+		}
+		op := maskOp(mask)
+		for info := event[metaLen:]; len(info) >= 4; {
+			infoType, infoLen := info[0], int(ne.Uint16(info[2:]))
+			if infoLen < 4 || infoLen > len(info) {
+				break
+			}
+			if infoType == fanEventInfoDFIDName || infoType == fanEventInfoDFID {
+				if path, ok := f.resolve(info[:infoLen], infoType == fanEventInfoDFIDName); ok && op != 0 {
+					f.record(Change{Path: path, Op: op})
+				}
+			}
+			info = info[infoLen:]
+		}
+	}
+}
+
+// resolve turns a directory file handle and entry name into a path. It
+// fails for directories deleted since the event.
+func (f *fanotify) resolve(info []byte, hasName bool) (string, bool) {
+	// Header (4 bytes), fsid (8 bytes), then struct file_handle.
+	if len(info) < 20 {
+		return "", false
+	}
+	handleLen := int(binary.NativeEndian.Uint32(info[12:]))
+	end := 20 + handleLen
+	if end > len(info) {
+		return "", false
+	}
+	handle := append([]byte(nil), info[12:end]...)
+	fd, _, errno := syscall.Syscall(f.trap[2], uintptr(f.mountFD), uintptr(unsafe.Pointer(&handle[0])), oPath|syscall.O_CLOEXEC)
+	if errno != 0 {
+		return "", false
+	}
+	dir, err := os.Readlink("/proc/self/fd/" + strconv.Itoa(int(fd)))
+	_ = syscall.Close(int(fd))
+	if err != nil || strings.HasSuffix(dir, " (deleted)") {
+		return "", false
+	}
+	if !hasName {
+		return dir, true
+	}
+	name, _, _ := strings.Cut(string(info[end:]), "\x00")
+	if name == "" || name == "." {
+		return dir, true
+	}
+	return dir + "/" + name, true
+}
+
+// record appends a change, dropping the oldest beyond maxLog.
+func (f *fanotify) record(c Change) {
+	f.seq++
+	f.log = append(f.log, c)
+	if len(f.log) > maxLog {
+		drop := len(f.log) - maxLog/2
+		f.log = append([]Change(nil), f.log[drop:]...)
+		f.base += uint64(drop)
+		f.lost = max(f.lost, f.base)
+	}
+}
+
+func maskOp(mask uint64) Op {
+	var op Op
+	for bit, o := range map[uint64]Op{
+		fanCreate: Created, fanDelete: Removed, fanModify: Modified,
+		fanAttrib: Metadata, fanMovedFrom: RenamedFrom, fanMovedTo: RenamedTo,
+	} {
+		if mask&bit != 0 {
+			op |= o
+		}
+	}
+	return op
+}
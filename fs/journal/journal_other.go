@@ -0,0 +1,7 @@
+//go:build !linux && !windows
+
+package journal
+
+func openBackend(string) (backend, error) {
+	return nil, ErrUnsupported
+}
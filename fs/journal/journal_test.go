@@ -0,0 +1,71 @@
+package journal_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/grokify/oscompat/fs/journal"
+)
+
+func TestOpString(t *testing.T) {
+	if got := (journal.Created | journal.Modified).String(); got != "created|modified" {
+		t.Errorf("String() = %q", got)
+	}
+	if got := journal.Op(0).String(); got != "Op(0)" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestJournal(t *testing.T) {
+	root := t.TempDir()
+	j, err := journal.Open(root)
+	if errors.Is(err, journal.ErrUnsupported) || errors.Is(err, os.ErrPermission) {
+		t.Skipf("change journal unavailable: %v", err)
+	}
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	defer func() { _ = j.Close() }()
+
+	start, err := j.Cursor()
+	if err != nil {
+		t.Fatalf("Cursor() error: %v", err)
+	}
+	outside := filepath.Join(t.TempDir(), "outside.txt")
+	if err := os.WriteFile(outside, []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	created := filepath.Join(j.Root(), "new.txt")
+	if err := os.WriteFile(created, []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(created); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, next, err := j.Since(start)
+	if err != nil {
+		t.Fatalf("Since() error: %v", err)
+	}
+	var found journal.Op
+	for _, c := range changes {
+		if c.Path == outside {
+			t.Errorf("change outside root reported: %v", c)
+		}
+		if c.Path == created {
+			found |= c.Op
+		}
+	}
+	if found&journal.Created == 0 || found&journal.Removed == 0 {
+		t.Errorf("Since() = %v, want %s created and removed", changes, created)
+	}
+
+	if changes, _, err := j.Since(next); err != nil || len(changes) != 0 {
+		t.Errorf("Since(next) = %v, %v, want no changes", changes, err)
+	}
+	if _, _, err := j.Since("bogus"); !errors.Is(err, journal.ErrInvalidCursor) && !errors.Is(err, journal.ErrCursorExpired) {
+		t.Errorf("Since(bogus) error = %v", err)
+	}
+}
@@ -0,0 +1,308 @@
+//go:build windows
+
+package journal
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	kernel32                              = syscall.NewLazyDLL("kernel32.dll")
+	procGetVolumePathNameW                = kernel32.NewProc("GetVolumePathNameW")
+	procGetVolumeNameForVolumeMountPointW = kernel32.NewProc("GetVolumeNameForVolumeMountPointW")
+	procOpenFileById                      = kernel32.NewProc("OpenFileById")
+	procGetFinalPathNameByHandleW         = kernel32.NewProc("GetFinalPathNameByHandleW")
+)
+
+const (
+	fsctlQueryUsnJournal = 0x000900f4
+	fsctlReadUsnJournal  = 0x000900bb
+
+	errorInvalidFunction     syscall.Errno = 1
+	errorJournalNotActive    syscall.Errno = 1179
+	errorJournalEntryDeleted syscall.Errno = 1181
+
+	fileFlagBackupSemantics = 0x02000000
+	fileIDType              = 0
+	extendedFileIDType      = 2
+
+	usnReasonData = 0x1 | 0x2 | 0x4 | 0x10 | 0x20 | 0x40 // overwrite, extend, truncation, named streams
+	usnReasonMeta = 0x400 | 0x800 | 0x8000 | 0x10000 | 0x20000 | 0x40000 | 0x80000 |
+		0x100000 | 0x200000 | 0x400000 | 0x800000 // EA, security, basic info, links, index, compression, encryption, object ID, reparse, stream, integrity
+	usnReasonCreate  = 0x100
+	usnReasonDelete  = 0x200
+	usnReasonOldName = 0x1000
+	usnReasonNewName = 0x2000
+)
+
+// usnJournalData mirrors USN_JOURNAL_DATA_V0.
+type usnJournalData struct {
+	UsnJournalID    uint64
+	FirstUsn        int64
+	NextUsn         int64
+	LowestValidUsn  int64
+	MaxUsn          int64
+	MaximumSize     uint64
+	AllocationDelta uint64
+}
+
+// readUsnJournalData mirrors READ_USN_JOURNAL_DATA_V0.
+type readUsnJournalData struct {
+	StartUsn          int64
+	ReasonMask        uint32
+	ReturnOnlyOnClose uint32
+	Timeout           uint64
+	BytesToWaitFor    uint64
+	UsnJournalID      uint64
+}
+
+// fileIDDescriptor mirrors FILE_ID_DESCRIPTOR.
+type fileIDDescriptor struct {
+	Size uint32
+	Type uint32
+	ID   [16]byte
+}
+
+// usn reads the change journal of the volume holding the root.
+type usn struct {
+	vol  syscall.Handle // the volume, for the journal
+	hint syscall.Handle // the root directory, for OpenFileById
+}
+
+func openBackend(root string) (backend, error) {
+	volume, err := volumeDevice(root)
+	if err != nil {
+		return nil, err
+	}
+	vp, err := syscall.UTF16PtrFromString(volume)
+	if err != nil {
+		return nil, err
+	}
+	vol, err := syscall.CreateFile(vp, syscall.GENERIC_READ,
+		syscall.FILE_SHARE_READ|syscall.FILE_SHARE_WRITE|syscall.FILE_SHARE_DELETE, nil, syscall.OPEN_EXISTING, 0, 0)
+	if err != nil {
+		// Opening a volume requires administrator privileges, so this
+		// matches os.ErrPermission for ordinary users.
+		return nil, fmt.Errorf("oscompat/fs/journal: open volume %s: %w", volume, err)
+	}
+	u := &usn{vol: vol}
+	if _, err := u.query(); err != nil {
+		_ = u.close()
+		return nil, err
+	}
+	rp, err := syscall.UTF16PtrFromString(root)
+	if err != nil {
+		_ = u.close()
+		return nil, err
+	}
+	u.hint, err = syscall.CreateFile(rp, 0,
+		syscall.FILE_SHARE_READ|syscall.FILE_SHARE_WRITE|syscall.FILE_SHARE_DELETE, nil, syscall.OPEN_EXISTING, fileFlagBackupSemantics, 0)
+	if err != nil {
+		_ = u.close()
+		return nil, err
+	}
+	return u, nil
+}
+
+// volumeDevice returns the device path, such as `\\?\Volume{...}`, of
+// the volume holding path, including volumes mounted on folders.
+func volumeDevice(path string) (string, error) {
+	p, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return "", err
+	}
+	mount := make([]uint16, syscall.MAX_PATH)
+	if r, _, e := procGetVolumePathNameW.Call(uintptr(unsafe.Pointer(p)), uintptr(unsafe.Pointer(&mount[0])), uintptr(len(mount))); r == 0 {
+		return "", fmt.Errorf("oscompat/fs/journal: GetVolumePathName: %w", e)
+	}
+	name := make([]uint16, 64)
+	if r, _, _ := procGetVolumeNameForVolumeMountPointW.Call(uintptr(unsafe.Pointer(&mount[0])), uintptr(unsafe.Pointer(&name[0])), uintptr(len(name))); r == 0 {
+		// Network shares have no volume GUID and no journal.
+		return "", ErrUnsupported
+	}
+	return strings.TrimSuffix(syscall.UTF16ToString(name), `\`), nil
+}
+
+func (u *usn) persistent() bool { return true }
+
+func (u *usn) query() (usnJournalData, error) {
+	var data usnJournalData
+	var n uint32
+	err := syscall.DeviceIoControl(u.vol, fsctlQueryUsnJournal, nil, 0,
+		(*byte)(unsafe.Pointer(&data)), uint32(unsafe.Sizeof(data)), &n, nil)
+	switch err {
+	case nil:
+		return data, nil
+	case errorJournalNotActive, errorInvalidFunction:
+		// FAT volumes have no journal, and on NTFS it may be disabled.
+		return data, ErrUnsupported
+	}
+	return data, fmt.Errorf("oscompat/fs/journal: query USN journal: %w", err)
+}
+
+func (u *usn) cursor() (Cursor, error) {
+	data, err := u.query()
+	if err != nil {
+		return "", err
+	}
+	return usnCursor(data.UsnJournalID, data.NextUsn), nil
+}
+
+func usnCursor(journalID uint64, next int64) Cursor {
+	return Cursor("usn:" + strconv.FormatUint(journalID, 16) + ":" + strconv.FormatInt(next, 10))
+}
+
+func (u *usn) since(c Cursor) ([]Change, Cursor, error) {
+	rest, ok := strings.CutPrefix(string(c), "usn:")
+	jid, num, ok2 := strings.Cut(rest, ":")
+	journalID, err1 := strconv.ParseUint(jid, 16, 64)
+	start, err2 := strconv.ParseInt(num, 10, 64)
+	if !ok || !ok2 || err1 != nil || err2 != nil {
+		return nil, "", ErrInvalidCursor
+	}
+	data, err := u.query()
+	if err != nil {
+		return nil, "", err
+	}
+	if journalID != data.UsnJournalID || start < data.LowestValidUsn || start < data.FirstUsn {
+		return nil, "", ErrCursorExpired
+	}
+	if start > data.NextUsn {
+		return nil, "", ErrInvalidCursor
+	}
+
+	var changes []Change
+	dirs := map[[16]byte]string{}
+	in := readUsnJournalData{StartUsn: start, ReasonMask: 0xFFFFFFFF, UsnJournalID: journalID}
+	buf := make([]byte, 64<<10)
+	for in.StartUsn < data.NextUsn {
+		var n uint32
+		err := syscall.DeviceIoControl(u.vol, fsctlReadUsnJournal,
+			(*byte)(unsafe.Pointer(&in)), uint32(unsafe.Sizeof(in)), &buf[0], uint32(len(buf)), &n, nil)
+		if err == errorJournalEntryDeleted {
+			return nil, "", ErrCursorExpired
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("oscompat/fs/journal: read USN journal: %w", err)
+		}
+		if n <= 8 {
+			break
+		}
+		in.StartUsn = int64(binary.LittleEndian.Uint64(buf))
+		changes = u.parse(buf[8:n], dirs, changes)
+	}
+	return changes, usnCursor(journalID, max(in.StartUsn, data.NextUsn)), nil
+}
+
+// parse decodes USN_RECORD_V2 and USN_RECORD_V3 records.
+func (u *usn) parse(buf []byte, dirs map[[16]byte]string, changes []Change) []Change {
+	le := binary.LittleEndian
+	for len(buf) >= 60 {
+		recLen := int(le.Uint32(buf))
+		if recLen < 60 || recLen > len(buf) {
+			break
+		}
+		rec := buf[:recLen]
+		buf = buf[recLen:]
+
+		var parent [16]byte
+		var idType uint32
+		var reason uint32
+		var nameLen, nameOff int
+		switch le.Uint16(rec[4:]) {
+		case 2:
+			copy(parent[:], rec[16:24])
+			idType = fileIDType
+			reason = le.Uint32(rec[40:])
+			nameLen, nameOff = int(le.Uint16(rec[56:])), int(le.Uint16(rec[58:]))
+		case 3:
+			if recLen < 76 {
+				continue
+			}
+			copy(parent[:], rec[24:40])
+			idType = extendedFileIDType
+			reason = le.Uint32(rec[56:])
+			nameLen, nameOff = int(le.Uint16(rec[72:])), int(le.Uint16(rec[74:]))
+		default:
+			continue
+		}
+		op := reasonOp(reason)
+		if op == 0 || nameOff+nameLen > recLen {
+			continue
+		}
+		dir, ok := dirs[parent]
+		if !ok {
+			dir = u.pathByID(parent, idType)
+			dirs[parent] = dir
+		}
+		if dir == "" {
+			continue
+		}
+		name := make([]uint16, nameLen/2)
+		for i := range name {
+			name[i] = le.Uint16(rec[nameOff+2*i:])
+		}
+		changes = append(changes, Change{Path: dir + `\` + syscall.UTF16ToString(name), Op: op})
+	}
+	return changes
+}
+
+// pathByID returns the path of the directory with the given file ID, or
+// "" if it no longer exists.
+func (u *usn) pathByID(id [16]byte, idType uint32) string {
+	desc := fileIDDescriptor{Size: uint32(unsafe.Sizeof(fileIDDescriptor{})), Type: idType, ID: id}
+	h, _, _ := procOpenFileById.Call(uintptr(u.hint), uintptr(unsafe.Pointer(&desc)), 0,
+		syscall.FILE_SHARE_READ|syscall.FILE_SHARE_WRITE|syscall.FILE_SHARE_DELETE, 0, fileFlagBackupSemantics)
+	if syscall.Handle(h) == syscall.InvalidHandle {
+		return ""
+	}
+	defer func() { _ = syscall.CloseHandle(syscall.Handle(h)) }()
+	buf := make([]uint16, syscall.MAX_LONG_PATH)
+	n, _, _ := procGetFinalPathNameByHandleW.Call(h, uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf)), 0)
+	if n == 0 || n > uintptr(len(buf)) {
+		return ""
+	}
+	path := syscall.UTF16ToString(buf[:n])
+	if rest, ok := strings.CutPrefix(path, `\\?\UNC\`); ok {
+		return `\\` + rest
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(path, `\\?\`), `\`)
+}
+
+func (u *usn) close() error {
+	err := syscall.CloseHandle(u.vol)
+	if u.hint != 0 {
+		if hintErr := syscall.CloseHandle(u.hint); err == nil {
+			err = hintErr
+		}
+	}
+	return err
+}
+
+func reasonOp(reason uint32) Op {
+	var op Op
+	if reason&usnReasonCreate != 0 {
+		op |= Created
+	}
+	if reason&usnReasonDelete != 0 {
+		op |= Removed
+	}
+	if reason&usnReasonData != 0 {
+		op |= Modified
+	}
+	if reason&usnReasonMeta != 0 {
+		op |= Metadata
+	}
+	if reason&usnReasonOldName != 0 {
+		op |= RenamedFrom
+	}
+	if reason&usnReasonNewName != 0 {
+		op |= RenamedTo
+	}
+	return op
+}
@@ -0,0 +1,73 @@
+package fs
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// Hangul syllable composition constants from the Unicode standard,
+// section 3.12.
+const (
+	hangulSBase  = 0xAC00
+	hangulLBase  = 0x1100
+	hangulVBase  = 0x1161
+	hangulTBase  = 0x11A7
+	hangulLCount = 19
+	hangulVCount = 21
+	hangulTCount = 28
+	hangulSCount = hangulLCount * hangulVCount * hangulTCount
+)
+
+// NormalizeName returns name in Unicode Normalization Form C, composing
+// base letters and combining marks into precomposed characters. macOS
+// stores names decomposed on HFS+ and passes through whatever form a
+// program used on APFS, while Windows and Linux programs almost always
+// use composed names, so the same "café" can arrive as different byte
+// strings. Compare names after NormalizeName.
+//
+// It composes canonically ordered input, such as names from HFS+, exactly
+// as NFC does; it does not reorder combining marks that arrive out of
+// canonical order.
+func NormalizeName(name string) string {
+	ascii := true
+	for i := 0; i < len(name); i++ {
+		if name[i] >= utf8.RuneSelf {
+			ascii = false
+			break
+		}
+	}
+	if ascii {
+		return name
+	}
+
+	out := make([]rune, 0, len(name))
+	starter := -1 // index in out of the last character a mark can join
+	for _, r := range name {
+		if starter >= 0 {
+			adjacent := starter == len(out)-1
+			if c, ok := compose(out[starter], r); ok && (adjacent || unicode.Is(unicode.Mn, r)) {
+				out[starter] = c
+				continue
+			}
+		}
+		out = append(out, r)
+		if !unicode.Is(unicode.Mn, r) {
+			starter = len(out) - 1
+		}
+	}
+	return string(out)
+}
+
+// compose returns the canonical composition of a and b, if any.
+func compose(a, b rune) (rune, bool) {
+	switch {
+	case a >= hangulLBase && a < hangulLBase+hangulLCount &&
+		b >= hangulVBase && b < hangulVBase+hangulVCount:
+		return hangulSBase + ((a-hangulLBase)*hangulVCount+(b-hangulVBase))*hangulTCount, true
+	case a >= hangulSBase && a < hangulSBase+hangulSCount && (a-hangulSBase)%hangulTCount == 0 &&
+		b > hangulTBase && b < hangulTBase+hangulTCount:
+		return a + (b - hangulTBase), true
+	}
+	c, ok := nfcPairs[[2]rune{a, b}]
+	return c, ok
+}
@@ -0,0 +1,97 @@
+package fs
+
+import (
+	"os"
+	"strings"
+)
+
+// PermReport is a portable description of who may access a file, for
+// doctor commands and security audits that should run the same checks on
+// every platform.
+type PermReport struct {
+	// OwnerOnly reports that no one but the owner has access, not
+	// counting SYSTEM and Administrators on Windows.
+	OwnerOnly bool
+
+	// GroupReadable and GroupWritable report access by the file's group
+	// on Unix, or by any other specific user or group on Windows.
+	GroupReadable bool
+	GroupWritable bool
+
+	// WorldReadable and WorldWritable report access by every user: the
+	// "other" bits on Unix, or Everyone, Authenticated Users or Users on
+	// Windows.
+	WorldReadable bool
+	WorldWritable bool
+
+	// ReadOnly reports that the owner cannot write the file: no owner
+	// write bit on Unix, or the read-only attribute on Windows.
+	ReadOnly bool
+
+	// Inherited reports that the file's Windows ACL inherits entries from
+	// its parent directory, so changing the parent's permissions changes
+	// the file's. It is always false on Unix.
+	Inherited bool
+
+	// Warnings describes risky settings, such as a world-writable file or
+	// a setuid binary.
+	Warnings []string
+}
+
+// DescribePerm describes the permissions recorded in info.
+//
+// On Unix the report is complete. On Windows the mode bits of a FileInfo
+// only carry the read-only attribute, so only ReadOnly is set; use
+// DescribePermPath, which reads the ACL. Symbolic links have no
+// permissions of their own and get an empty report.
+func DescribePerm(info os.FileInfo) PermReport {
+	var r PermReport
+	if info.Mode()&os.ModeSymlink == 0 {
+		describeMode(info.Mode(), &r)
+	}
+	return r
+}
+
+// DescribePermPath describes the permissions of the file at path,
+// following symbolic links. On Windows it reads the file's ACL to fill in
+// every field.
+func DescribePermPath(path string) (PermReport, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return PermReport{}, err
+	}
+	r := DescribePerm(info)
+	if err := describeACL(path, info, &r); err != nil {
+		return PermReport{}, err
+	}
+	return r, nil
+}
+
+// String returns a short summary such as "owner-only, read-only" or
+// "group-readable, world-writable".
+func (r PermReport) String() string {
+	var parts []string
+	add := func(ok bool, s string) {
+		if ok {
+			parts = append(parts, s)
+		}
+	}
+	add(r.OwnerOnly, "owner-only")
+	add(r.GroupReadable && !r.GroupWritable, "group-readable")
+	add(r.GroupWritable, "group-writable")
+	add(r.WorldReadable && !r.WorldWritable, "world-readable")
+	add(r.WorldWritable, "world-writable")
+	add(r.ReadOnly, "read-only")
+	add(r.Inherited, "inherited ACL")
+	return strings.Join(parts, ", ")
+}
+
+// warnWorldWritable adds the warning for a file any user can modify.
+func (r *PermReport) warnWorldWritable(dir, sticky bool) {
+	switch {
+	case !dir:
+		r.Warnings = append(r.Warnings, "world-writable: any user can modify the file")
+	case !sticky:
+		r.Warnings = append(r.Warnings, "world-writable directory without the sticky bit: any user can delete or replace its files")
+	}
+}
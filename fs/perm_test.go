@@ -0,0 +1,74 @@
+package fs_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/grokify/oscompat/fs"
+)
+
+func TestDescribePerm(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config")
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS == "windows" {
+		r, err := fs.DescribePermPath(path)
+		if err != nil {
+			t.Fatalf("DescribePermPath() error: %v", err)
+		}
+		if r.ReadOnly || r.WorldWritable {
+			t.Errorf("DescribePermPath() = %+v for a new file", r)
+		}
+		return
+	}
+
+	tests := []struct {
+		mode     os.FileMode
+		want     string
+		warnings int
+	}{
+		{0o600, "owner-only", 0},
+		{0o400, "owner-only, read-only", 0},
+		{0o640, "group-readable", 0},
+		{0o664, "group-writable, world-readable", 1},
+		{0o666, "group-writable, world-writable", 1},
+		{0o755 | os.ModeSetuid, "group-readable, world-readable", 1},
+	}
+	for _, tt := range tests {
+		if err := os.Chmod(path, tt.mode); err != nil {
+			t.Fatal(err)
+		}
+		r, err := fs.DescribePermPath(path)
+		if err != nil {
+			t.Fatalf("DescribePermPath() error: %v", err)
+		}
+		if got := r.String(); got != tt.want {
+			t.Errorf("mode %o: String() = %q, want %q", tt.mode, got, tt.want)
+		}
+		if len(r.Warnings) != tt.warnings {
+			t.Errorf("mode %o: Warnings = %q, want %d", tt.mode, r.Warnings, tt.warnings)
+		}
+	}
+
+	sub := filepath.Join(dir, "shared")
+	if err := os.Mkdir(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, mode := range []os.FileMode{0o777, 0o777 | os.ModeSticky} {
+		if err := os.Chmod(sub, mode); err != nil {
+			t.Fatal(err)
+		}
+		info, err := os.Stat(sub)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := fs.DescribePerm(info)
+		if want := mode&os.ModeSticky == 0; !r.WorldWritable || (len(r.Warnings) > 0) != want {
+			t.Errorf("directory mode %v: %+v", mode, r)
+		}
+	}
+}
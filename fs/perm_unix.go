@@ -0,0 +1,36 @@
+//go:build !windows
+
+package fs
+
+import "os"
+
+// describeMode fills r from the Unix permission bits.
+func describeMode(mode os.FileMode, r *PermReport) {
+	perm := mode.Perm()
+	r.OwnerOnly = perm&0o077 == 0
+	r.GroupReadable = perm&0o040 != 0
+	r.GroupWritable = perm&0o020 != 0
+	r.WorldReadable = perm&0o004 != 0
+	r.WorldWritable = perm&0o002 != 0
+	r.ReadOnly = perm&0o200 == 0
+
+	if r.WorldWritable {
+		r.warnWorldWritable(mode.IsDir(), mode&os.ModeSticky != 0)
+	}
+	if mode.IsRegular() && perm&0o111 != 0 {
+		if mode&os.ModeSetuid != 0 {
+			r.Warnings = append(r.Warnings, "setuid: runs with the owner's privileges")
+		}
+		if mode&os.ModeSetgid != 0 {
+			r.Warnings = append(r.Warnings, "setgid: runs with the group's privileges")
+		}
+	}
+	if r.GroupWritable && !r.WorldWritable && !mode.IsDir() {
+		r.Warnings = append(r.Warnings, "group-writable: other members of the group can modify the file")
+	}
+}
+
+// describeACL adds nothing on Unix, where the mode bits are complete.
+func describeACL(path string, info os.FileInfo, r *PermReport) error {
+	return nil
+}
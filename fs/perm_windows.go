@@ -0,0 +1,159 @@
+//go:build windows
+
+package fs
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	advapi32                         = syscall.NewLazyDLL("advapi32.dll")
+	procGetNamedSecurityInfoW        = advapi32.NewProc("GetNamedSecurityInfoW")
+	procGetSecurityDescriptorControl = advapi32.NewProc("GetSecurityDescriptorControl")
+	procGetAce                       = advapi32.NewProc("GetAce")
+	procEqualSid                     = advapi32.NewProc("EqualSid")
+)
+
+const (
+	seFileObject             = 1
+	ownerSecurityInformation = 0x1
+	daclSecurityInformation  = 0x4
+	seDaclProtected          = 0x1000
+	accessAllowedAceType     = 0
+	inheritOnlyAce           = 0x08
+	inheritedAce             = 0x10
+	fileReadData             = 0x1
+	fileWriteData            = 0x2
+	fileAppendData           = 0x4
+	writeDAC                 = 0x40000
+	writeOwner               = 0x80000
+	genericAll               = 0x10000000
+	genericWrite             = 0x40000000
+	genericRead              = 0x80000000
+)
+
+// Access rights that count as reading or writing the file.
+const (
+	aceReadMask  uint32 = fileReadData | genericRead | genericAll
+	aceWriteMask uint32 = fileWriteData | fileAppendData | writeDAC | writeOwner | genericWrite | genericAll
+)
+
+// aclHeader mirrors ACL.
+type aclHeader struct {
+	AclRevision byte
+	Sbz1        byte
+	AclSize     uint16
+	AceCount    uint16
+	Sbz2        uint16
+}
+
+// accessAllowedAce mirrors ACCESS_ALLOWED_ACE up to SidStart.
+type accessAllowedAce struct {
+	AceType  byte
+	AceFlags byte
+	AceSize  uint16
+	Mask     uint32
+	SidStart uint32
+}
+
+// describeMode only knows the read-only attribute on Windows.
+func describeMode(mode os.FileMode, r *PermReport) {
+	r.ReadOnly = !mode.IsDir() && mode.Perm()&0o200 == 0
+}
+
+// describeACL fills r from the file's owner and DACL. Deny entries are
+// ignored, so access may be overstated, never understated.
+func describeACL(path string, info os.FileInfo, r *PermReport) error {
+	p, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return err
+	}
+	var owner *syscall.SID
+	var dacl *aclHeader
+	var sd uintptr
+	if rc, _, _ := procGetNamedSecurityInfoW.Call(uintptr(unsafe.Pointer(p)), seFileObject,
+		ownerSecurityInformation|daclSecurityInformation,
+		uintptr(unsafe.Pointer(&owner)), 0, uintptr(unsafe.Pointer(&dacl)), 0,
+		uintptr(unsafe.Pointer(&sd))); rc != 0 {
+		return &os.PathError{Op: "GetNamedSecurityInfo", Path: path, Err: syscall.Errno(rc)}
+	}
+	defer func() { _, _ = syscall.LocalFree(syscall.Handle(sd)) }()
+
+	var control uint16
+	var revision uint32
+	if ok, _, err := procGetSecurityDescriptorControl.Call(sd, uintptr(unsafe.Pointer(&control)), uintptr(unsafe.Pointer(&revision))); ok == 0 {
+		return &os.PathError{Op: "GetSecurityDescriptorControl", Path: path, Err: err}
+	}
+
+	if dacl == nil {
+		// A NULL DACL grants everyone full access.
+		r.WorldReadable, r.WorldWritable = true, true
+		r.warnWorldWritable(info.IsDir(), false)
+		return nil
+	}
+
+	world := wellKnownSIDs("S-1-1-0", "S-1-5-11", "S-1-5-32-545")   // Everyone, Authenticated Users, Users
+	trusted := wellKnownSIDs("S-1-5-18", "S-1-5-32-544", "S-1-3-0") // SYSTEM, Administrators, CREATOR OWNER
+	r.OwnerOnly = true
+	for i := range uint32(dacl.AceCount) {
+		var ace *accessAllowedAce
+		if rc, _, _ := procGetAce.Call(uintptr(unsafe.Pointer(dacl)), uintptr(i), uintptr(unsafe.Pointer(&ace))); rc == 0 {
+			continue
+		}
+		if ace.AceFlags&inheritedAce != 0 {
+			r.Inherited = true
+		}
+		if ace.AceType != accessAllowedAceType || ace.AceFlags&inheritOnlyAce != 0 {
+			continue
+		}
+		sid := (*syscall.SID)(unsafe.Pointer(&ace.SidStart))
+		read, write := ace.Mask&aceReadMask != 0, ace.Mask&aceWriteMask != 0
+		switch {
+		case !read && !write, equalSID(sid, owner), containsSID(trusted, sid):
+		case containsSID(world, sid):
+			r.OwnerOnly = false
+			r.WorldReadable = r.WorldReadable || read
+			r.WorldWritable = r.WorldWritable || write
+		default:
+			r.OwnerOnly = false
+			r.GroupReadable = r.GroupReadable || read
+			r.GroupWritable = r.GroupWritable || write
+		}
+	}
+	// Entries only count as inherited while the DACL is not protected.
+	r.Inherited = r.Inherited && control&seDaclProtected == 0
+	if r.WorldWritable {
+		r.warnWorldWritable(info.IsDir(), false)
+	}
+	return nil
+}
+
+// wellKnownSIDs converts SID strings, skipping any that fail.
+func wellKnownSIDs(ss ...string) []*syscall.SID {
+	var sids []*syscall.SID
+	for _, s := range ss {
+		if sid, err := syscall.StringToSid(s); err == nil {
+			sids = append(sids, sid)
+		}
+	}
+	return sids
+}
+
+func containsSID(sids []*syscall.SID, sid *syscall.SID) bool {
+	for _, s := range sids {
+		if equalSID(s, sid) {
+			return true
+		}
+	}
+	return false
+}
+
+func equalSID(a, b *syscall.SID) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	rc, _, _ := procEqualSid.Call(uintptr(unsafe.Pointer(a)), uintptr(unsafe.Pointer(b)))
+	return rc != 0
+}
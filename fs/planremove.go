@@ -0,0 +1,241 @@
+package fs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// Reasons recorded in SkippedPath.Reason.
+const (
+	SkipOtherDevice = "other device"
+	SkipDepthLimit  = "depth limit"
+	SkipCycle       = "cycle"
+	SkipUnreadable  = "unreadable"
+)
+
+// RemoveOptions controls PlanRemove. The zero value stays on the device
+// of the root and has no depth limit.
+type RemoveOptions struct {
+	// MaxDepth limits how deep below the root the plan goes; directories
+	// at that depth with contents are skipped. Zero means no limit.
+	MaxDepth int
+
+	// CrossDevices lets the plan descend into directories on another
+	// filesystem, such as mount points below the root.
+	CrossDevices bool
+}
+
+// SkippedPath is a path the plan leaves in place, with the reason.
+type SkippedPath struct {
+	Path   string
+	Reason string
+	Err    error // set for SkipUnreadable
+}
+
+// RemovePlan lists what removing a tree would delete. Build one with
+// PlanRemove, show it to the user, then carry it out with Remove.
+type RemovePlan struct {
+	// Root is the cleaned path the plan starts from.
+	Root string
+
+	// Files are regular files and other non-directories to delete.
+	Files []string
+
+	// Links are symbolic links and, on Windows, junctions and other
+	// reparse points. Only the link itself is deleted; its target is
+	// never visited.
+	Links []string
+
+	// Dirs are the directories to delete, deepest first. A directory
+	// holding a skipped path is not listed, since it cannot be emptied.
+	Dirs []string
+
+	// Bytes is the total size of Files.
+	Bytes int64
+
+	// Skipped lists the paths left in place, with their contents.
+	Skipped []SkippedPath
+}
+
+// Complete reports whether carrying out the plan removes root entirely.
+func (p *RemovePlan) Complete() bool {
+	return len(p.Skipped) == 0
+}
+
+// String summarizes the plan for a confirmation prompt, for example
+// "12 files, 2 links, 3 directories, 40960 bytes; 1 skipped".
+func (p *RemovePlan) String() string {
+	s := fmt.Sprintf("%d files, %d links, %d directories, %d bytes",
+		len(p.Files), len(p.Links), len(p.Dirs), p.Bytes)
+	if len(p.Skipped) > 0 {
+		s += fmt.Sprintf("; %d skipped", len(p.Skipped))
+	}
+	return s
+}
+
+// PlanRemove walks the tree at root, without changing it, and returns
+// what removing it would delete. It is the audit step for CLI tools that
+// delete paths given by the user: print the plan, ask for confirmation,
+// then call Remove.
+//
+// Symbolic links and junctions are listed for removal but never
+// followed, so the plan cannot leave the tree through them or loop. A
+// directory on another device (a mount point below root) is skipped
+// unless opts.CrossDevices is set, and a directory already visited
+// through a bind mount or a directory hard link is skipped as a cycle.
+// If root is itself a link, the plan removes only the link. A nil opts
+// is the zero RemoveOptions.
+func PlanRemove(root string, opts *RemoveOptions) (*RemovePlan, error) {
+	if opts == nil {
+		opts = &RemoveOptions{}
+	}
+	root = filepath.Clean(root)
+	info, err := os.Lstat(root)
+	if err != nil {
+		return nil, err
+	}
+	w := &planWalker{
+		plan:    &RemovePlan{Root: root},
+		opts:    opts,
+		visited: make(map[fileKey]bool),
+	}
+	if info.IsDir() {
+		key, err := statKey(root, info)
+		if err != nil {
+			return nil, err
+		}
+		w.dev = key.dev
+	}
+	w.visit(root, info, 0)
+	return w.plan, nil
+}
+
+// Remove deletes the paths of the plan: files and links first, then the
+// directories deepest first. Paths that are already gone are ignored.
+// It does not walk the tree again, so anything created since PlanRemove
+// ran keeps its directory from being removed, and that error is
+// returned along with any others.
+//
+// Paths are removed through an os.Root opened on Root, so a directory
+// replaced with a symbolic link since PlanRemove ran cannot lead Remove
+// outside the tree.
+func (p *RemovePlan) Remove() error {
+	info, err := os.Lstat(longPath(p.Root))
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		// The plan holds only Root, a file or link, or Root has been
+		// replaced by one; either way only Root itself goes.
+		if err := os.Remove(longPath(p.Root)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	root, err := os.OpenRoot(p.Root)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = root.Close() }()
+	if opened, err := root.Stat("."); err != nil {
+		return err
+	} else if !os.SameFile(info, opened) {
+		return fmt.Errorf("oscompat/fs: %s was replaced during removal", p.Root)
+	}
+
+	var errs []error
+	for _, path := range slices.Concat(p.Files, p.Links, p.Dirs) {
+		rel, err := filepath.Rel(p.Root, path)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if rel == "." {
+			continue // removed last, below
+		}
+		if err := root.Remove(rel); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, err)
+		}
+	}
+	if slices.Contains(p.Dirs, p.Root) {
+		if err := os.Remove(longPath(p.Root)); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// fileKey identifies a directory: its device and its file number.
+type fileKey struct {
+	dev, ino uint64
+}
+
+// planWalker carries the state of PlanRemove.
+type planWalker struct {
+	plan    *RemovePlan
+	opts    *RemoveOptions
+	dev     uint64
+	visited map[fileKey]bool
+}
+
+// visit adds path to the plan and reports whether it will be removed.
+func (w *planWalker) visit(path string, info os.FileInfo, depth int) bool {
+	switch {
+	case info.Mode()&(os.ModeSymlink|os.ModeIrregular) != 0:
+		w.plan.Links = append(w.plan.Links, path)
+		return true
+	case !info.IsDir():
+		w.plan.Files = append(w.plan.Files, path)
+		w.plan.Bytes += info.Size()
+		return true
+	}
+
+	key, err := statKey(path, info)
+	if err != nil {
+		return w.skip(path, SkipUnreadable, err)
+	}
+	if key.dev != w.dev && !w.opts.CrossDevices {
+		return w.skip(path, SkipOtherDevice, nil)
+	}
+	if w.visited[key] {
+		return w.skip(path, SkipCycle, nil)
+	}
+	w.visited[key] = true
+
+	entries, err := os.ReadDir(longPath(path))
+	if err != nil {
+		return w.skip(path, SkipUnreadable, err)
+	}
+	if len(entries) > 0 && w.opts.MaxDepth > 0 && depth >= w.opts.MaxDepth {
+		return w.skip(path, SkipDepthLimit, nil)
+	}
+	removable := true
+	for _, e := range entries {
+		child := filepath.Join(path, e.Name())
+		ci, err := e.Info()
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			removable = w.skip(child, SkipUnreadable, err) && removable
+			continue
+		}
+		removable = w.visit(child, ci, depth+1) && removable
+	}
+	if removable {
+		w.plan.Dirs = append(w.plan.Dirs, path)
+	}
+	return removable
+}
+
+// skip records path as left in place and returns false.
+func (w *planWalker) skip(path, reason string, err error) bool {
+	w.plan.Skipped = append(w.plan.Skipped, SkippedPath{Path: path, Reason: reason, Err: err})
+	return false
+}
@@ -0,0 +1,106 @@
+package fs_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+
+	"github.com/grokify/oscompat/fs"
+)
+
+func TestPlanRemove(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	keep := filepath.Join(outside, "keep.txt")
+	for path, data := range map[string]string{
+		filepath.Join(root, "a.txt"):             "hello",
+		filepath.Join(root, "sub", "b.txt"):      "hi",
+		filepath.Join(root, "sub", "d", "c.txt"): "x",
+		keep:                                     "keep",
+	} {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	hasLink := os.Symlink(outside, filepath.Join(root, "out")) == nil
+	if !hasLink && runtime.GOOS != "windows" {
+		t.Fatal("symlink failed")
+	}
+
+	plan, err := fs.PlanRemove(root, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Files) != 3 || plan.Bytes != 8 {
+		t.Errorf("Files = %v, Bytes = %d", plan.Files, plan.Bytes)
+	}
+	if hasLink && !slices.Equal(plan.Links, []string{filepath.Join(root, "out")}) {
+		t.Errorf("Links = %v", plan.Links)
+	}
+	if len(plan.Dirs) != 3 || plan.Dirs[len(plan.Dirs)-1] != root {
+		t.Errorf("Dirs = %v, want root last", plan.Dirs)
+	}
+	if !plan.Complete() {
+		t.Errorf("Skipped = %v", plan.Skipped)
+	}
+
+	t.Run("depth limit", func(t *testing.T) {
+		plan, err := fs.PlanRemove(root, &fs.RemoveOptions{MaxDepth: 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []fs.SkippedPath{{Path: filepath.Join(root, "sub", "d"), Reason: fs.SkipDepthLimit}}
+		if !slices.Equal(plan.Skipped, want) {
+			t.Errorf("Skipped = %v, want %v", plan.Skipped, want)
+		}
+		if slices.Contains(plan.Dirs, root) || slices.Contains(plan.Dirs, filepath.Join(root, "sub")) {
+			t.Errorf("Dirs = %v includes an ancestor of a skipped path", plan.Dirs)
+		}
+	})
+
+	if err := plan.Remove(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Lstat(root); !os.IsNotExist(err) {
+		t.Errorf("root still exists: %v", err)
+	}
+	if _, err := os.Stat(keep); err != nil {
+		t.Errorf("link target removed: %v", err)
+	}
+}
+
+func TestRemovePlanSwappedDir(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+	sub := filepath.Join(root, "sub")
+	if err := os.Mkdir(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, dir := range []string{sub, outside} {
+		if err := os.WriteFile(filepath.Join(dir, "f.txt"), []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	plan, err := fs.PlanRemove(root, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Replace the planned directory with a link out of the tree.
+	if err := os.RemoveAll(sub); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, sub); err != nil {
+		t.Skipf("symlink: %v", err)
+	}
+
+	_ = plan.Remove()
+	if _, err := os.Stat(filepath.Join(outside, "f.txt")); err != nil {
+		t.Errorf("Remove followed a swapped-in link: %v", err)
+	}
+}
@@ -0,0 +1,17 @@
+//go:build !windows
+
+package fs
+
+import (
+	"os"
+	"syscall"
+)
+
+// statKey returns the device and inode number from the stat data.
+func statKey(_ string, info os.FileInfo) (fileKey, error) {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fileKey{}, ErrUnsupported
+	}
+	return fileKey{dev: uint64(st.Dev), ino: uint64(st.Ino)}, nil
+}
@@ -0,0 +1,32 @@
+//go:build windows
+
+package fs
+
+import (
+	"os"
+	"syscall"
+)
+
+// statKey returns the volume serial number and file index of path,
+// which the attribute data in info does not carry.
+func statKey(path string, _ os.FileInfo) (fileKey, error) {
+	p, err := syscall.UTF16PtrFromString(longPath(path))
+	if err != nil {
+		return fileKey{}, err
+	}
+	h, err := syscall.CreateFile(p, 0,
+		syscall.FILE_SHARE_READ|syscall.FILE_SHARE_WRITE|syscall.FILE_SHARE_DELETE, nil,
+		syscall.OPEN_EXISTING, syscall.FILE_FLAG_BACKUP_SEMANTICS|syscall.FILE_FLAG_OPEN_REPARSE_POINT, 0)
+	if err != nil {
+		return fileKey{}, &os.PathError{Op: "open", Path: path, Err: err}
+	}
+	defer func() { _ = syscall.CloseHandle(h) }()
+	var d syscall.ByHandleFileInformation
+	if err := syscall.GetFileInformationByHandle(h, &d); err != nil {
+		return fileKey{}, &os.PathError{Op: "GetFileInformationByHandle", Path: path, Err: err}
+	}
+	return fileKey{
+		dev: uint64(d.VolumeSerialNumber),
+		ino: uint64(d.FileIndexHigh)<<32 | uint64(d.FileIndexLow),
+	}, nil
+}
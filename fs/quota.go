@@ -0,0 +1,200 @@
+package fs
+
+import (
+	"cmp"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/grokify/oscompat/paths"
+	"github.com/grokify/oscompat/tsync"
+)
+
+// EvictionPolicy selects which files EnforceQuota removes first.
+type EvictionPolicy int
+
+const (
+	// EvictOldestModified removes the least recently written files first.
+	EvictOldestModified EvictionPolicy = iota
+
+	// EvictOldestAccessed removes the least recently read files first.
+	// Access times are often updated lazily or not at all (relatime and
+	// noatime mounts, NTFS by default); files without one are ordered by
+	// modification time.
+	EvictOldestAccessed
+
+	// EvictLargest removes the largest files first.
+	EvictLargest
+)
+
+// Quota limits the size of a directory tree. A zero limit is no limit.
+type Quota struct {
+	// MaxBytes limits the total size of the files.
+	MaxBytes int64
+
+	// MaxFiles limits the number of files.
+	MaxFiles int
+
+	// Policy orders the files for eviction.
+	Policy EvictionPolicy
+
+	// MinAge protects files written more recently than this from
+	// eviction, so that entries another process is still filling are
+	// left alone. The tree may then stay over quota.
+	MinAge time.Duration
+
+	// Tolerance is the timestamp resolution the order uses: files whose
+	// times fall in the same interval are treated as equally old, and the
+	// larger is evicted first. Zero means tsync.DefaultTolerance, which
+	// also covers the 2-second FAT timestamps of removable drives.
+	Tolerance time.Duration
+}
+
+// QuotaReport describes a directory tree after EnforceQuota.
+type QuotaReport struct {
+	// Files and Bytes are the usage left after eviction.
+	Files int
+	Bytes int64
+
+	// Evicted lists the removed files, in the order they were removed.
+	Evicted []string
+
+	// EvictedBytes is the total size of the removed files.
+	EvictedBytes int64
+}
+
+// Over reports whether the usage still exceeds q.
+func (r *QuotaReport) Over(q Quota) bool {
+	return (q.MaxBytes > 0 && r.Bytes > q.MaxBytes) || (q.MaxFiles > 0 && r.Files > q.MaxFiles)
+}
+
+// EnforceQuota measures the files below dir and removes them in the
+// order of q.Policy until the tree is within q. Directories left empty
+// by eviction are removed too, but never dir itself. Symbolic links are
+// counted as small files and never followed. It works the same on every
+// platform and is meant for cache directories; see EnforceAppCacheQuota.
+//
+// Files that vanish during the walk, as they do when another process
+// prunes the same cache, are not errors. Failures to remove a file are
+// collected and returned after the rest of the eviction, with the report.
+func EnforceQuota(dir string, q Quota) (*QuotaReport, error) {
+	if q.Tolerance <= 0 {
+		q.Tolerance = tsync.DefaultTolerance
+	}
+	var files []quotaFile
+	r := &QuotaReport{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		files = append(files, quotaFile{path: path, info: info})
+		r.Files++
+		r.Bytes += info.Size()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !r.Over(q) {
+		return r, nil
+	}
+
+	slices.SortFunc(files, q.compare)
+	cutoff := time.Now().Add(-q.MinAge)
+	dirs := make(map[string]bool)
+	var errs []error
+	for _, f := range files {
+		if !r.Over(q) {
+			break
+		}
+		if q.MinAge > 0 && f.info.ModTime().After(cutoff) {
+			continue
+		}
+		if err := os.Remove(longPath(f.path)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			errs = append(errs, err)
+			continue
+		}
+		r.Files--
+		r.Bytes -= f.info.Size()
+		r.Evicted = append(r.Evicted, f.path)
+		r.EvictedBytes += f.info.Size()
+		dirs[filepath.Dir(f.path)] = true
+	}
+	removeEmptyDirs(dir, dirs)
+	return r, errors.Join(errs...)
+}
+
+// EnforceAppCacheQuota applies q to the cache directory of appName, as
+// returned by paths.AppCache.
+func EnforceAppCacheQuota(appName string, q Quota) (*QuotaReport, error) {
+	dir, err := paths.AppCache(appName)
+	if err != nil {
+		return nil, err
+	}
+	return EnforceQuota(dir, q)
+}
+
+// quotaFile is a file EnforceQuota may evict.
+type quotaFile struct {
+	path string
+	info os.FileInfo
+}
+
+// compare orders a before b if a is to be evicted first.
+func (q Quota) compare(a, b quotaFile) int {
+	if q.Policy == EvictLargest {
+		return cmp.Or(
+			cmp.Compare(b.info.Size(), a.info.Size()),
+			q.compareTime(a.info.ModTime(), b.info.ModTime()),
+			cmp.Compare(a.path, b.path))
+	}
+	ta, tb := a.info.ModTime(), b.info.ModTime()
+	if q.Policy == EvictOldestAccessed {
+		ta, tb = accessTime(a.info), accessTime(b.info)
+	}
+	return cmp.Or(
+		q.compareTime(ta, tb),
+		cmp.Compare(b.info.Size(), a.info.Size()),
+		cmp.Compare(a.path, b.path))
+}
+
+// compareTime compares the Tolerance intervals of a and b. Truncating
+// keeps the order transitive, which comparing within a tolerance is not.
+func (q Quota) compareTime(a, b time.Time) int {
+	return tsync.Truncate(a, q.Tolerance).Compare(tsync.Truncate(b, q.Tolerance))
+}
+
+// accessTime returns the access time of info, or its modification time
+// where none is recorded.
+func accessTime(info os.FileInfo) time.Time {
+	if t := fileTimes(info).Accessed; !t.IsZero() {
+		return t
+	}
+	return info.ModTime()
+}
+
+// removeEmptyDirs removes the directories in dirs, and then their
+// parents, below root while they are empty.
+func removeEmptyDirs(root string, dirs map[string]bool) {
+	root = filepath.Clean(root)
+	for d := range dirs {
+		for d != root && PathHasPrefix(d, root) {
+			if os.Remove(longPath(d)) != nil {
+				break
+			}
+			d = filepath.Dir(d)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package fs_test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/grokify/oscompat/fs"
+)
+
+func TestEnforceQuota(t *testing.T) {
+	dir := t.TempDir()
+	base := time.Now().Add(-time.Hour).Truncate(time.Minute)
+	write := func(name string, size int, age time.Duration) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, make([]byte, size), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		mtime := base.Add(-age)
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	oldest := write(filepath.Join("a", "oldest"), 10, 3*time.Hour)
+	// Within the tolerance of each other: the larger goes first.
+	small := write("small", 10, 2*time.Hour)
+	large := write("large", 30, 2*time.Hour-100*time.Millisecond)
+	write("newest", 10, 0)
+
+	r, err := fs.EnforceQuota(dir, fs.Quota{MaxBytes: 40, Tolerance: 10 * time.Second})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{oldest, large}; !slices.Equal(r.Evicted, want) {
+		t.Errorf("Evicted = %v, want %v", r.Evicted, want)
+	}
+	if r.Files != 2 || r.Bytes != 20 || r.EvictedBytes != 40 {
+		t.Errorf("report = %+v", r)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "a")); !os.IsNotExist(err) {
+		t.Errorf("emptied directory kept: %v", err)
+	}
+	if _, err := os.Stat(small); err != nil {
+		t.Error(err)
+	}
+
+	r, err = fs.EnforceQuota(dir, fs.Quota{MaxFiles: 1, MinAge: 24 * time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Evicted) != 0 || !r.Over(fs.Quota{MaxFiles: 1}) {
+		t.Errorf("MinAge did not protect files: %+v", r)
+	}
+
+	r, err = fs.EnforceQuota(dir, fs.Quota{MaxFiles: 1, Policy: fs.EvictLargest})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Evicted) != 1 || r.Files != 1 {
+		t.Errorf("report = %+v", r)
+	}
+	if _, err := os.Stat(dir); err != nil {
+		t.Errorf("root removed: %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package fs
+
+import (
+	"errors"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Times holds the timestamps of a file. Fields the platform or
+// filesystem does not record are zero.
+type Times struct {
+	// Modified is when the content last changed.
+	Modified time.Time
+
+	// Accessed is when the file was last read. Many systems update it
+	// lazily or not at all (relatime and noatime mounts; NTFS by
+	// default), so treat it as a hint.
+	Accessed time.Time
+
+	// Created is the birth time: available on macOS, FreeBSD, NetBSD,
+	// and Windows.
+	Created time.Time
+
+	// Changed is when the content or metadata last changed (ctime): Unix
+	// only.
+	Changed time.Time
+}
+
+// DirEntryInfo describes one entry returned by ReadDirInfo.
+type DirEntryInfo struct {
+	// Name is the entry name in Unicode NFC; see NormalizeName.
+	Name string
+
+	// OSName is the name as stored on disk, to use when opening the
+	// entry. It differs from Name only for decomposed names, typically
+	// from macOS.
+	OSName string
+
+	// Path is dir joined with Name, using forward slashes on every
+	// platform.
+	Path string
+
+	Size  int64
+	Mode  os.FileMode
+	Times Times
+
+	// Hidden reports whether file managers hide the entry by default: a
+	// name starting with "." on every platform, the hidden attribute on
+	// Windows, or the UF_HIDDEN flag on macOS and FreeBSD.
+	Hidden bool
+}
+
+// ReadDirInfo reads the directory dir and returns its entries with
+// metadata in the same shape on every platform, sorted by Name. The
+// metadata comes from the directory listing where the platform provides
+// it, as Windows does, so that comparing two trees does not take a stat
+// per entry there. Symbolic links are described, not followed.
+//
+// Entries removed while the directory is being read are left out.
+func ReadDirInfo(dir string) ([]DirEntryInfo, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	base := NormalizePath(dir)
+	infos := make([]DirEntryInfo, 0, len(entries))
+	for _, e := range entries {
+		info, err := e.Info()
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		name := NormalizeName(e.Name())
+		infos = append(infos, DirEntryInfo{
+			Name:   name,
+			OSName: e.Name(),
+			Path:   path.Join(base, name),
+			Size:   info.Size(),
+			Mode:   info.Mode(),
+			Times:  fileTimes(info),
+			Hidden: strings.HasPrefix(name, ".") || hiddenAttr(info),
+		})
+	}
+	slices.SortFunc(infos, func(a, b DirEntryInfo) int { return strings.Compare(a.Name, b.Name) })
+	return infos, nil
+}
+
+// TimesOf returns the timestamps recorded in info, which must come from
+// this package's platform, as from os.Stat or os.Lstat.
+func TimesOf(info os.FileInfo) Times {
+	return fileTimes(info)
+}
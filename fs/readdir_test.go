@@ -0,0 +1,87 @@
+package fs_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/grokify/oscompat/fs"
+)
+
+func TestNormalizeName(t *testing.T) {
+	tests := map[string]string{
+		"plain.txt":          "plain.txt",
+		"cafe\u0301":         "caf\u00e9",
+		"a\u0323\u0302":      "\u1ead", // dot below, then circumflex
+		"A\u030a\u0301":      "\u01fa", // ring above, then acute
+		"\u1100\u1161\u11a8": "\uac01", // conjoining jamo
+		"\u00e9":             "\u00e9",
+		"x\u0301":            "x\u0301",      // no precomposed form
+		"\u0915\u093c":       "\u0915\u093c", // composition exclusion
+		"e\u0301\u0301":      "\u00e9\u0301",
+	}
+	for in, want := range tests {
+		if got := fs.NormalizeName(in); got != want {
+			t.Errorf("NormalizeName(%+q) = %+q, want %+q", in, got, want)
+		}
+	}
+}
+
+func TestReadDirInfo(t *testing.T) {
+	dir := t.TempDir()
+	decomposed := "café.txt"
+	for _, name := range []string{decomposed, ".hidden", "b.txt"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("data"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := fs.ReadDirInfo(dir)
+	if err != nil {
+		t.Fatalf("ReadDirInfo() error: %v", err)
+	}
+	if len(entries) != 4 {
+		t.Fatalf("ReadDirInfo() returned %d entries", len(entries))
+	}
+	byName := map[string]fs.DirEntryInfo{}
+	for i, e := range entries {
+		if i > 0 && entries[i-1].Name >= e.Name {
+			t.Errorf("entries not sorted: %q before %q", entries[i-1].Name, e.Name)
+		}
+		if e.Path != fs.NormalizePath(dir)+"/"+e.Name {
+			t.Errorf("Path = %q", e.Path)
+		}
+		if e.Times.Modified.IsZero() {
+			t.Errorf("%s: zero modification time", e.Name)
+		}
+		byName[e.Name] = e
+	}
+
+	cafe, ok := byName["caf\u00e9.txt"]
+	if !ok {
+		t.Fatalf("no NFC entry for café.txt in %v", entries)
+	}
+	// macOS may store the name either way; elsewhere it is kept as written.
+	if runtime.GOOS != "darwin" && cafe.OSName != decomposed {
+		t.Errorf("OSName = %+q, want %+q", cafe.OSName, decomposed)
+	}
+	if _, err := os.Stat(filepath.Join(dir, cafe.OSName)); err != nil {
+		t.Errorf("cannot stat by OSName: %v", err)
+	}
+	if cafe.Size != 4 || cafe.Hidden {
+		t.Errorf("café.txt = %+v", cafe)
+	}
+	if !byName[".hidden"].Hidden {
+		t.Error(".hidden is not Hidden")
+	}
+	if !byName["sub"].Mode.IsDir() {
+		t.Error("sub is not a directory")
+	}
+	if runtime.GOOS == "linux" && cafe.Times.Changed.IsZero() {
+		t.Error("zero change time on Linux")
+	}
+}
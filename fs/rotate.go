@@ -0,0 +1,169 @@
+package fs
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"strconv"
+)
+
+// RotateOptions controls RotateFile.
+type RotateOptions struct {
+	// MaxSize rotates only files of at least this many bytes. Zero
+	// rotates any non-empty file.
+	MaxSize int64
+
+	// MaxBackups is the number of rotated files to keep; older ones are
+	// removed. Zero keeps them all.
+	MaxBackups int
+
+	// Compress gzips each rotated file to name.1.gz and so on.
+	Compress bool
+}
+
+// RotateFile rotates the file name by renaming it to name.1, after
+// shifting existing backups to name.2, name.3 and so on. It reports
+// whether the file was rotated; a missing, empty or small file is left
+// alone. opts may be nil.
+//
+// The writer should reopen name after a rotation, for example with
+// OpenAppend. On Unix it can keep writing to the renamed file until then.
+// Windows refuses to rename a file that another handle has open without
+// FILE_SHARE_DELETE, which includes files opened by os.OpenFile, so there
+// RotateFile retries briefly and then falls back to copying the file to
+// name.1 and truncating it in place. Writers that append, as Appender
+// does, carry on at the new end; bytes written between the copy and the
+// truncation are lost.
+func RotateFile(name string, opts *RotateOptions) (bool, error) {
+	var o RotateOptions
+	if opts != nil {
+		o = *opts
+	}
+	info, err := os.Stat(name)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	if info.Size() == 0 || info.Size() < o.MaxSize {
+		return false, nil
+	}
+
+	if err := shiftBackups(name, o.MaxBackups); err != nil {
+		return false, err
+	}
+	first := backupName(name, 1)
+	if err := renameRetry(name, first); err != nil {
+		if !isSharingError(err) {
+			return false, err
+		}
+		if err := copyTruncate(name, first, info.Mode().Perm()); err != nil {
+			return false, err
+		}
+	}
+	if o.Compress {
+		if err := gzipFile(first, info.Mode().Perm()); err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+// backupName returns the name of the n-th backup of name.
+func backupName(name string, n int) string {
+	return name + "." + strconv.Itoa(n)
+}
+
+// shiftBackups renames name.N[.gz] to name.N+1[.gz], from the oldest
+// down, removing those beyond maxBackups.
+func shiftBackups(name string, maxBackups int) error {
+	last := 0
+	for n := 1; ; n++ {
+		if !exists(backupName(name, n)) && !exists(backupName(name, n)+".gz") {
+			break
+		}
+		last = n
+	}
+	for n := last; n >= 1; n-- {
+		for _, ext := range []string{"", ".gz"} {
+			from := backupName(name, n) + ext
+			if !exists(from) {
+				continue
+			}
+			if maxBackups > 0 && n >= maxBackups {
+				if err := os.Remove(from); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := renameRetry(from, backupName(name, n+1)+ext); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// exists reports whether name exists, following no symlinks.
+func exists(name string) bool {
+	_, err := os.Lstat(name)
+	return err == nil
+}
+
+// copyTruncate copies src to dst and truncates src to zero length.
+func copyTruncate(src, dst string, perm os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+	if err := writeFrom(dst, perm, func(w io.Writer) error {
+		_, err := io.Copy(w, in)
+		return err
+	}); err != nil {
+		return err
+	}
+	return os.Truncate(src, 0)
+}
+
+// gzipFile compresses name to name.gz and removes name.
+func gzipFile(name string, perm os.FileMode) error {
+	in, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	err = writeFrom(name+".gz", perm, func(w io.Writer) error {
+		zw := gzip.NewWriter(w)
+		if _, err := io.Copy(zw, in); err != nil {
+			return err
+		}
+		return zw.Close()
+	})
+	_ = in.Close()
+	if err != nil {
+		return err
+	}
+	return os.Remove(name)
+}
+
+// writeFrom creates name through a temporary file filled by fill, so a
+// failure never leaves a partial file behind.
+func writeFrom(name string, perm os.FileMode, fill func(io.Writer) error) error {
+	tmp := name + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	err = fill(f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, name)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+	}
+	return err
+}
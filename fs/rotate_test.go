@@ -0,0 +1,80 @@
+package fs_test
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/grokify/oscompat/fs"
+)
+
+func TestRotateFile(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "app.log")
+	if rotated, err := fs.RotateFile(name, nil); err != nil || rotated {
+		t.Fatalf("missing file: rotated=%v, err=%v", rotated, err)
+	}
+
+	opts := &fs.RotateOptions{MaxSize: 4, MaxBackups: 2}
+	for _, content := range []string{"first", "second", "third"} {
+		if err := os.WriteFile(name, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if rotated, err := fs.RotateFile(name, opts); err != nil || !rotated {
+			t.Fatalf("rotate %q: rotated=%v, err=%v", content, rotated, err)
+		}
+	}
+	for file, want := range map[string]string{".1": "third", ".2": "second"} {
+		got, err := os.ReadFile(name + file)
+		if err != nil || string(got) != want {
+			t.Errorf("%s = %q, %v; want %q", file, got, err, want)
+		}
+	}
+	if _, err := os.Stat(name + ".3"); !os.IsNotExist(err) {
+		t.Errorf("backup beyond MaxBackups kept: %v", err)
+	}
+
+	if err := os.WriteFile(name, []byte("abc"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if rotated, err := fs.RotateFile(name, opts); err != nil || rotated {
+		t.Errorf("small file: rotated=%v, err=%v", rotated, err)
+	}
+}
+
+func TestRotateFileCompressOpen(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "app.log")
+	a, err := fs.OpenAppend(name, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := a.Close(); err != nil {
+			t.Error(err)
+		}
+	}()
+	if _, err := a.WriteString("before\n"); err != nil {
+		t.Fatal(err)
+	}
+	if rotated, err := fs.RotateFile(name, &fs.RotateOptions{Compress: true}); err != nil || !rotated {
+		t.Fatalf("rotated=%v, err=%v", rotated, err)
+	}
+
+	f, err := os.Open(name + ".1.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := io.ReadAll(zr)
+	if err != nil || string(got) != "before\n" {
+		t.Errorf("backup = %q, %v", got, err)
+	}
+	if _, err := os.Stat(name + ".1"); !os.IsNotExist(err) {
+		t.Errorf("uncompressed backup kept: %v", err)
+	}
+}
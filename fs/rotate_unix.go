@@ -0,0 +1,16 @@
+//go:build !windows
+
+package fs
+
+import "os"
+
+// renameRetry renames from to to. Unix renames open files freely.
+func renameRetry(from, to string) error {
+	return os.Rename(from, to)
+}
+
+// isSharingError reports whether err means another process has the file
+// open; that never stops a rename on Unix.
+func isSharingError(error) bool {
+	return false
+}
@@ -0,0 +1,43 @@
+//go:build windows
+
+package fs
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"time"
+)
+
+const (
+	errorSharingViolation syscall.Errno = 32
+	errorLockViolation    syscall.Errno = 33
+)
+
+// renameRetries and renameBackoff bound how long renameRetry waits for
+// another handle, such as a virus scanner's, to close.
+const (
+	renameRetries = 5
+	renameBackoff = 20 * time.Millisecond
+)
+
+// renameRetry renames from to to, which os.Rename does with
+// MOVEFILE_REPLACE_EXISTING, retrying while the file is in use.
+func renameRetry(from, to string) error {
+	var err error
+	for i := range renameRetries {
+		if err = os.Rename(from, to); err == nil || !isSharingError(err) {
+			return err
+		}
+		time.Sleep(renameBackoff << i)
+	}
+	return err
+}
+
+// isSharingError reports whether err means another handle has the file
+// open without sharing delete access.
+func isSharingError(err error) bool {
+	return errors.Is(err, errorSharingViolation) ||
+		errors.Is(err, errorLockViolation) ||
+		errors.Is(err, syscall.ERROR_ACCESS_DENIED)
+}
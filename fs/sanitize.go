@@ -0,0 +1,214 @@
+package fs
+
+import (
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// maxNameUTF16 is the longest file name NTFS, exFAT, and most other
+// filesystems accept, in UTF-16 code units.
+const maxNameUTF16 = 255
+
+// maxNameBytes is NAME_MAX on Unix filesystems such as ext4 and APFS.
+const maxNameBytes = 255
+
+// NameIssue is a set of reasons a file name is not portable, as reported
+// by NeedsEscaping.
+type NameIssue uint
+
+const (
+	// NameInvalidChar means the name contains a character Windows forbids
+	// (< > " / \ | ? *) or a control character.
+	NameInvalidChar NameIssue = 1 << iota
+
+	// NameStreamSeparator means the name contains ':', which NTFS reads
+	// as the start of an alternate data stream, so "a:b" writes stream
+	// "b" of file "a".
+	NameStreamSeparator
+
+	// NameTrailingDotSpace means the name ends with a dot or space, which
+	// Windows strips silently, so the file cannot be opened by its name.
+	NameTrailingDotSpace
+
+	// NameReserved means the name is a device name such as CON or
+	// COM1.txt.
+	NameReserved
+
+	// NameTooLong means the name exceeds 255 UTF-16 code units.
+	NameTooLong
+
+	// NameEmpty means the name is "", ".", or "..".
+	NameEmpty
+)
+
+var nameIssueText = []string{
+	"invalid character",
+	"stream separator",
+	"trailing dot or space",
+	"reserved name",
+	"too long",
+	"empty",
+}
+
+// String lists the issues, separated by commas.
+func (i NameIssue) String() string {
+	var list []string
+	for bit, text := range nameIssueText {
+		if i&(1<<bit) != 0 {
+			list = append(list, text)
+		}
+	}
+	return strings.Join(list, ", ")
+}
+
+// NeedsEscaping reports why name, a single path component, could not be
+// used as is on Windows filesystems such as NTFS and exFAT, or 0 if it
+// can. SanitizeName repairs every issue it reports.
+func NeedsEscaping(name string) NameIssue {
+	var issues NameIssue
+	switch name {
+	case "", ".", "..":
+		return NameEmpty
+	}
+	for _, r := range name {
+		switch {
+		case r == ':':
+			issues |= NameStreamSeparator
+		case r < 0x20 || r == 0x7f || strings.ContainsRune(`<>"/\|?*`, r):
+			issues |= NameInvalidChar
+		}
+	}
+	if strings.HasSuffix(name, ".") || strings.HasSuffix(name, " ") {
+		issues |= NameTrailingDotSpace
+	}
+	base, _, _ := strings.Cut(name, ".")
+	if windowsReserved[strings.ToUpper(strings.TrimRight(base, " "))] {
+		issues |= NameReserved
+	}
+	if utf16Len(name) > maxNameUTF16 {
+		issues |= NameTooLong
+	}
+	return issues
+}
+
+// utf16Len returns the length of s in UTF-16 code units.
+func utf16Len(s string) int {
+	n := 0
+	for _, r := range s {
+		n += utf16.RuneLen(r)
+	}
+	return n
+}
+
+// windowsReserved holds the device names Windows reserves in every
+// directory, with or without an extension.
+var windowsReserved = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true,
+	"COM6": true, "COM7": true, "COM8": true, "COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true,
+	"LPT6": true, "LPT7": true, "LPT8": true, "LPT9": true,
+}
+
+// SanitizeName returns a file name, a single path component, that is
+// valid on every platform:
+//   - Characters Windows forbids (< > : " / \ | ? *) and control
+//     characters are replaced with '_'.
+//   - Trailing dots and spaces, which Windows strips silently, are
+//     removed.
+//   - Reserved device names such as CON and COM1.txt get a '_' appended
+//     to the base name.
+//   - Names over 255 UTF-16 code units are shortened, keeping the
+//     extension.
+//   - "", ".", and ".." become "_".
+//
+// Names that are already valid are returned unchanged.
+func SanitizeName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || strings.ContainsRune(`<>:"/\|?*`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+	name = strings.TrimRight(name, ". ")
+	if name == "" {
+		return "_"
+	}
+	base, ext, _ := strings.Cut(name, ".")
+	if windowsReserved[strings.ToUpper(strings.TrimRight(base, " "))] {
+		name = base + "_"
+		if ext != "" {
+			name += "." + ext
+		}
+	}
+	return truncateName(name, maxNameUTF16, utf16.RuneLen)
+}
+
+// truncateName shortens name to limit units as measured by size, cutting
+// the base name so that a short extension survives.
+func truncateName(name string, limit int, size func(rune) int) string {
+	length := func(s string) int {
+		n := 0
+		for _, r := range s {
+			n += size(r)
+		}
+		return n
+	}
+	if length(name) <= limit {
+		return name
+	}
+	ext := ""
+	if i := strings.LastIndexByte(name, '.'); i > 0 && len(name)-i <= 32 {
+		name, ext = name[:i], name[i:]
+	}
+	limit -= length(ext)
+	n := 0
+	for i, r := range name {
+		if n+size(r) > limit {
+			name = name[:i]
+			break
+		}
+		n += size(r)
+	}
+	return strings.TrimRight(name, ". ") + ext
+}
+
+// windowsFSTypes are filesystem types, as reported in Volume.FSType, that
+// follow Windows naming rules.
+var windowsFSTypes = map[string]bool{
+	"ntfs": true, "ntfs3": true, "exfat": true, "vfat": true, "msdos": true,
+	"fat": true, "fat32": true, "refs": true, "cifs": true, "smb3": true, "smbfs": true,
+}
+
+// SanitizeNameFor repairs name for a filesystem of the given type, as
+// reported in Volume.FSType: names headed for NTFS, exFAT, FAT, or an SMB
+// share get SanitizeName, while other filesystems, which only forbid '/'
+// and NUL, keep every other character and are limited to 255 bytes. Copy tools can use it to name
+// files on the destination volume.
+func SanitizeNameFor(name, fsType string) string {
+	if windowsFSTypes[strings.ToLower(fsType)] {
+		return SanitizeName(name)
+	}
+	name = strings.Map(func(r rune) rune {
+		if r == '/' || r == 0 {
+			return '_'
+		}
+		return r
+	}, name)
+	switch name {
+	case "", ".", "..":
+		return "_"
+	}
+	return truncateName(name, maxNameBytes, utf8.RuneLen)
+}
+
+// LongPath returns p in a form that Windows APIs accept beyond the
+// MAX_PATH limit of 260 characters: absolute paths get the \\?\ prefix
+// (\\?\UNC\ for network paths). Use it for paths passed to tools and
+// system calls outside the os package, which applies the prefix itself.
+// On other platforms, and for short or relative paths, p is returned
+// unchanged.
+func LongPath(p string) string {
+	return longPath(p)
+}
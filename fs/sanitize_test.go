@@ -0,0 +1,64 @@
+package fs_test
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/grokify/oscompat/fs"
+)
+
+func TestNeedsEscaping(t *testing.T) {
+	long := strings.Repeat("é", 200) + strings.Repeat("😀", 30) + ".txt" // 264 UTF-16 units
+	tests := map[string]fs.NameIssue{
+		"report.pdf":  0,
+		"über":        0,
+		"file:stream": fs.NameStreamSeparator,
+		"a?b":         fs.NameInvalidChar,
+		"trailing.":   fs.NameTrailingDotSpace,
+		"trailing ":   fs.NameTrailingDotSpace,
+		"NUL.txt":     fs.NameReserved,
+		"..":          fs.NameEmpty,
+		"c:on.":       fs.NameStreamSeparator | fs.NameTrailingDotSpace,
+		long:          fs.NameTooLong,
+		"aux . ":      fs.NameReserved | fs.NameTrailingDotSpace,
+	}
+	for name, want := range tests {
+		got := fs.NeedsEscaping(name)
+		if got != want {
+			t.Errorf("NeedsEscaping(%q) = %v, want %v", name, got, want)
+		}
+		if fixed := fs.SanitizeName(name); fs.NeedsEscaping(fixed) != 0 {
+			t.Errorf("SanitizeName(%q) = %q still needs escaping: %v", name, fixed, fs.NeedsEscaping(fixed))
+		}
+	}
+
+	fixed := fs.SanitizeName(long)
+	if n := len(utf16.Encode([]rune(fixed))); n > 255 || !strings.HasSuffix(fixed, ".txt") {
+		t.Errorf("SanitizeName(long) = %d units, %q", n, fixed[len(fixed)-8:])
+	}
+	if got := (fs.NameStreamSeparator | fs.NameTooLong).String(); got != "stream separator, too long" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestSanitizeNameFor(t *testing.T) {
+	tests := []struct {
+		name, fsType, want string
+	}{
+		{"a:b?.txt", "NTFS", "a_b_.txt"},
+		{"a:b?.txt", "exfat", "a_b_.txt"},
+		{"a:b?.txt", "ext4", "a:b?.txt"},
+		{"a/b", "apfs", "a_b"},
+		{"..", "ext4", "_"},
+	}
+	for _, tt := range tests {
+		if got := fs.SanitizeNameFor(tt.name, tt.fsType); got != tt.want {
+			t.Errorf("SanitizeNameFor(%q, %q) = %q, want %q", tt.name, tt.fsType, got, tt.want)
+		}
+	}
+	long := strings.Repeat("é", 200) + ".txt"
+	if got := fs.SanitizeNameFor(long, "ext4"); len(got) > 255 || !strings.HasSuffix(got, ".txt") {
+		t.Errorf("SanitizeNameFor(long, ext4) is %d bytes", len(got))
+	}
+}
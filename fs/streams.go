@@ -0,0 +1,63 @@
+package fs
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// StreamInfo describes a named data stream of a file.
+type StreamInfo struct {
+	// Name is the stream name, without the colons and $DATA type of its
+	// NTFS form ":name:$DATA".
+	Name string
+
+	// Size is the length of the stream's data.
+	Size int64
+}
+
+// ListStreams returns the alternate data streams of path, such as the
+// Zone.Identifier stream of downloaded files, sorted by name. The
+// unnamed stream holding the file's ordinary content is not listed.
+//
+// Only NTFS and ReFS on Windows have alternate data streams; on other
+// filesystems and platforms the list is empty once path is found to
+// exist. Backup and security tools can use it to copy streams along with
+// a file, or to find and strip them.
+func ListStreams(path string) ([]StreamInfo, error) {
+	if _, err := os.Lstat(path); err != nil {
+		return nil, err
+	}
+	return listStreams(path)
+}
+
+// OpenStream opens the alternate data stream name of path with the
+// given flags and permissions, as os.OpenFile does; with os.O_CREATE it
+// creates the stream. It returns ErrUnsupported on platforms without
+// alternate data streams, and ErrInvalidName for an empty name or one
+// containing a colon, a path separator, or a NUL.
+func OpenStream(path, name string, flag int, perm os.FileMode) (*os.File, error) {
+	if err := validStreamName(name); err != nil {
+		return nil, err
+	}
+	return openStream(path, name, flag, perm)
+}
+
+// RemoveStream deletes the alternate data stream name of path. It
+// returns an error matching os.ErrNotExist if the stream does not exist,
+// and ErrUnsupported on platforms without alternate data streams.
+func RemoveStream(path, name string) error {
+	if err := validStreamName(name); err != nil {
+		return err
+	}
+	return removeStream(path, name)
+}
+
+// validStreamName rejects names that would address another stream type
+// or another file.
+func validStreamName(name string) error {
+	if name == "" || strings.ContainsAny(name, ":/\\\x00") {
+		return fmt.Errorf("%w: stream %q", ErrInvalidName, name)
+	}
+	return nil
+}
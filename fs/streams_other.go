@@ -0,0 +1,19 @@
+//go:build !windows
+
+package fs
+
+import "os"
+
+// listStreams finds none: only Windows filesystems have alternate data
+// streams.
+func listStreams(string) ([]StreamInfo, error) {
+	return nil, nil
+}
+
+func openStream(string, string, int, os.FileMode) (*os.File, error) {
+	return nil, ErrUnsupported
+}
+
+func removeStream(string, string) error {
+	return ErrUnsupported
+}
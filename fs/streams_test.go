@@ -0,0 +1,61 @@
+package fs_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/grokify/oscompat/fs"
+)
+
+func TestStreams(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "file.txt")
+	if err := os.WriteFile(path, []byte("main content"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fs.ListStreams(path + ".missing"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("ListStreams(missing) error = %v, want ErrNotExist", err)
+	}
+	for _, name := range []string{"", "a:b", `a\b`, "a/b"} {
+		if _, err := fs.OpenStream(path, name, os.O_RDONLY, 0); !errors.Is(err, fs.ErrInvalidName) {
+			t.Errorf("OpenStream(%q) error = %v, want ErrInvalidName", name, err)
+		}
+	}
+
+	f, err := fs.OpenStream(path, "notes", os.O_WRONLY|os.O_CREATE, 0o644)
+	if errors.Is(err, fs.ErrUnsupported) {
+		streams, err := fs.ListStreams(path)
+		if err != nil || len(streams) != 0 {
+			t.Errorf("ListStreams() = %v, %v; want none", streams, err)
+		}
+		return
+	} else if err != nil {
+		t.Fatal(err)
+	}
+	_, err = f.WriteString("hidden")
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	streams, err := fs.ListStreams(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []fs.StreamInfo{{Name: "notes", Size: 6}}; !slices.Equal(streams, want) {
+		t.Errorf("ListStreams() = %v, want %v", streams, want)
+	}
+	if err := fs.RemoveStream(path, "notes"); err != nil {
+		t.Fatal(err)
+	}
+	if err := fs.RemoveStream(path, "notes"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("second RemoveStream() error = %v, want ErrNotExist", err)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "main content" {
+		t.Errorf("main stream = %q, %v", data, err)
+	}
+}
@@ -0,0 +1,68 @@
+//go:build windows
+
+package fs
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	procFindFirstStreamW = kernel32.NewProc("FindFirstStreamW")
+	procFindNextStreamW  = kernel32.NewProc("FindNextStreamW")
+)
+
+// errorInvalidParameter is returned by FindFirstStreamW on filesystems
+// without streams, such as FAT.
+const errorInvalidParameter syscall.Errno = 87
+
+// win32FindStreamData mirrors WIN32_FIND_STREAM_DATA.
+type win32FindStreamData struct {
+	StreamSize int64
+	StreamName [syscall.MAX_PATH + 36]uint16
+}
+
+// listStreams enumerates the $DATA streams of path with FindFirstStreamW.
+func listStreams(path string) ([]StreamInfo, error) {
+	p, err := syscall.UTF16PtrFromString(longPath(path))
+	if err != nil {
+		return nil, &os.PathError{Op: "FindFirstStreamW", Path: path, Err: err}
+	}
+	var data win32FindStreamData
+	// FindStreamInfoStandard is 0.
+	h, _, err := procFindFirstStreamW.Call(uintptr(unsafe.Pointer(p)), 0, uintptr(unsafe.Pointer(&data)), 0)
+	if syscall.Handle(h) == syscall.InvalidHandle {
+		if err == errorHandleEOF || err == errorInvalidParameter {
+			return nil, nil
+		}
+		return nil, &os.PathError{Op: "FindFirstStreamW", Path: path, Err: err}
+	}
+	defer func() { _ = syscall.FindClose(syscall.Handle(h)) }()
+
+	var streams []StreamInfo
+	for {
+		name := syscall.UTF16ToString(data.StreamName[:])
+		if name, ok := strings.CutSuffix(strings.TrimPrefix(name, ":"), ":$DATA"); ok && name != "" {
+			streams = append(streams, StreamInfo{Name: name, Size: data.StreamSize})
+		}
+		if r, _, err := procFindNextStreamW.Call(h, uintptr(unsafe.Pointer(&data))); r == 0 {
+			if err != errorHandleEOF {
+				return nil, &os.PathError{Op: "FindNextStreamW", Path: path, Err: err}
+			}
+			break
+		}
+	}
+	slices.SortFunc(streams, func(a, b StreamInfo) int { return strings.Compare(a.Name, b.Name) })
+	return streams, nil
+}
+
+func openStream(path, name string, flag int, perm os.FileMode) (*os.File, error) {
+	return os.OpenFile(longPath(path)+":"+name, flag, perm)
+}
+
+func removeStream(path, name string) error {
+	return os.Remove(longPath(path) + ":" + name)
+}
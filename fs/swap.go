@@ -0,0 +1,102 @@
+package fs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/grokify/oscompat/id"
+)
+
+// SwapDirs exchanges the directories a and b, which must both exist on
+// the same filesystem: afterwards a holds what b held and vice versa.
+//
+// On Linux this is a single atomic renameat2(RENAME_EXCHANGE), so readers
+// always see one complete tree or the other. Where that is unavailable
+// (other platforms, and Linux filesystems without support, such as some
+// network filesystems) it takes three renames through a temporary name
+// next to a; between them a briefly does not exist. If a step fails, the
+// completed renames are rolled back before the error is returned.
+func SwapDirs(a, b string) error {
+	for _, dir := range []string{a, b} {
+		info, err := os.Stat(dir)
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("oscompat/fs: %s is not a directory", dir)
+		}
+	}
+	if err := exchange(a, b); !errors.Is(err, errors.ErrUnsupported) {
+		return err
+	}
+
+	tmp, err := siblingName(a, "swap")
+	if err != nil {
+		return err
+	}
+	if err := renameRetry(a, tmp); err != nil {
+		return err
+	}
+	if err := renameRetry(b, a); err != nil {
+		return rollback(err, tmp, a)
+	}
+	if err := renameRetry(tmp, b); err != nil {
+		return rollback(err, a, b, tmp, a)
+	}
+	return nil
+}
+
+// ReplaceDir replaces the directory old with staged, a fully prepared
+// directory on the same filesystem, and then removes the previous
+// contents of old. If old does not exist, staged is simply renamed to
+// it. This is the publish step of a deployment that builds each release
+// in a staging directory.
+//
+// On Linux the switch is atomic, as with SwapDirs. Elsewhere old is moved
+// aside and staged moved into place, so old is briefly missing; if the
+// second rename fails, old is restored. An error removing the previous
+// contents is returned after the new directory is in place.
+func ReplaceDir(old, staged string) error {
+	if _, err := os.Stat(old); errors.Is(err, os.ErrNotExist) {
+		return renameRetry(staged, old)
+	}
+	if err := exchange(old, staged); err == nil {
+		return os.RemoveAll(staged)
+	} else if !errors.Is(err, errors.ErrUnsupported) {
+		return err
+	}
+
+	backup, err := siblingName(old, "old")
+	if err != nil {
+		return err
+	}
+	if err := renameRetry(old, backup); err != nil {
+		return err
+	}
+	if err := renameRetry(staged, old); err != nil {
+		return rollback(err, backup, old)
+	}
+	return os.RemoveAll(backup)
+}
+
+// siblingName returns an unused name in the directory of path.
+func siblingName(path, purpose string) (string, error) {
+	suffix, err := id.GenerateE(4)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+"."+purpose+"-"+suffix), nil
+}
+
+// rollback undoes renames given as from, to pairs, in order, and returns
+// err annotated if a rollback step fails too.
+func rollback(err error, pairs ...string) error {
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if rbErr := renameRetry(pairs[i], pairs[i+1]); rbErr != nil {
+			return fmt.Errorf("%w (rollback failed: %v)", err, rbErr)
+		}
+	}
+	return err
+}
@@ -0,0 +1,60 @@
+//go:build linux
+
+package fs
+
+import (
+	"errors"
+	"os"
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+const (
+	atFDCWD        = -100
+	renameExchange = 0x2
+)
+
+// sysRenameat2 holds the renameat2 syscall number, which the syscall
+// package does not export.
+var sysRenameat2 = map[string]uintptr{
+	"386":     353,
+	"amd64":   316,
+	"arm":     382,
+	"arm64":   276,
+	"loong64": 276,
+	"riscv64": 276,
+	"ppc64":   357,
+	"ppc64le": 357,
+	"s390x":   347,
+}
+
+// exchange swaps a and b atomically with renameat2(RENAME_EXCHANGE),
+// returning errors.ErrUnsupported on kernels older than 3.15 and
+// filesystems that do not support it.
+func exchange(a, b string) error {
+	trap, ok := sysRenameat2[runtime.GOARCH]
+	if !ok {
+		return errors.ErrUnsupported
+	}
+	pa, err := syscall.BytePtrFromString(a)
+	if err != nil {
+		return err
+	}
+	pb, err := syscall.BytePtrFromString(b)
+	if err != nil {
+		return err
+	}
+	fd := atFDCWD
+	_, _, errno := syscall.Syscall6(trap,
+		uintptr(fd), uintptr(unsafe.Pointer(pa)),
+		uintptr(fd), uintptr(unsafe.Pointer(pb)),
+		renameExchange, 0)
+	switch errno {
+	case 0:
+		return nil
+	case syscall.ENOSYS, syscall.EINVAL:
+		return errors.ErrUnsupported
+	}
+	return &os.LinkError{Op: "renameat2", Old: a, New: b, Err: errno}
+}
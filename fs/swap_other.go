@@ -0,0 +1,10 @@
+//go:build !linux
+
+package fs
+
+import "errors"
+
+// exchange is unavailable; callers fall back to a sequence of renames.
+func exchange(_, _ string) error {
+	return errors.ErrUnsupported
+}
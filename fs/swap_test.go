@@ -0,0 +1,90 @@
+package fs_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/grokify/oscompat/fs"
+)
+
+// mkTree creates dir containing a single file with the given content.
+func mkTree(t *testing.T, dir, content string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "version"), []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func version(t *testing.T, dir string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, "version"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// entries returns the names in dir, to check nothing was left behind.
+func entries(t *testing.T, dir string) int {
+	t.Helper()
+	list, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(list)
+}
+
+func TestSwapDirs(t *testing.T) {
+	root := t.TempDir()
+	a, b := filepath.Join(root, "a"), filepath.Join(root, "b")
+	mkTree(t, a, "one")
+	mkTree(t, b, "two")
+
+	if err := fs.SwapDirs(a, b); err != nil {
+		t.Fatalf("SwapDirs() error: %v", err)
+	}
+	if got := version(t, a); got != "two" {
+		t.Errorf("a = %q, want two", got)
+	}
+	if got := version(t, b); got != "one" {
+		t.Errorf("b = %q, want one", got)
+	}
+	if n := entries(t, root); n != 2 {
+		t.Errorf("%d entries in parent, want 2", n)
+	}
+
+	if err := fs.SwapDirs(a, filepath.Join(root, "missing")); err == nil {
+		t.Error("SwapDirs() with a missing directory should fail")
+	}
+	if got := version(t, a); got != "two" {
+		t.Errorf("a changed by failed swap: %q", got)
+	}
+}
+
+func TestReplaceDir(t *testing.T) {
+	root := t.TempDir()
+	current, staged := filepath.Join(root, "current"), filepath.Join(root, "staging")
+
+	mkTree(t, staged, "v1")
+	if err := fs.ReplaceDir(current, staged); err != nil {
+		t.Fatalf("ReplaceDir() into missing dir error: %v", err)
+	}
+	if got := version(t, current); got != "v1" {
+		t.Errorf("current = %q, want v1", got)
+	}
+
+	mkTree(t, staged, "v2")
+	if err := fs.ReplaceDir(current, staged); err != nil {
+		t.Fatalf("ReplaceDir() error: %v", err)
+	}
+	if got := version(t, current); got != "v2" {
+		t.Errorf("current = %q, want v2", got)
+	}
+	if n := entries(t, root); n != 1 {
+		t.Errorf("%d entries in parent, want only current", n)
+	}
+}
@@ -0,0 +1,30 @@
+//go:build darwin || freebsd || netbsd
+
+package fs
+
+import (
+	"os"
+	"syscall"
+
+	"github.com/grokify/oscompat/tsync"
+)
+
+// ufHidden is UF_HIDDEN, the flag chflags hidden sets.
+const ufHidden = 0x00008000
+
+// fileTimes reads atime, ctime, and the birth time from the stat data.
+func fileTimes(info os.FileInfo) Times {
+	t := Times{Modified: info.ModTime()}
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		t.Accessed = tsync.FromTimespec(st.Atimespec)
+		t.Changed = tsync.FromTimespec(st.Ctimespec)
+		t.Created = tsync.FromTimespec(st.Birthtimespec)
+	}
+	return t
+}
+
+// hiddenAttr reports whether the UF_HIDDEN flag is set.
+func hiddenAttr(info os.FileInfo) bool {
+	st, ok := info.Sys().(*syscall.Stat_t)
+	return ok && st.Flags&ufHidden != 0
+}
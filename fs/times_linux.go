@@ -0,0 +1,26 @@
+//go:build linux
+
+package fs
+
+import (
+	"os"
+	"syscall"
+
+	"github.com/grokify/oscompat/tsync"
+)
+
+// fileTimes reads atime and ctime from the stat data. Linux reports the
+// birth time only through statx, which would take a call per file.
+func fileTimes(info os.FileInfo) Times {
+	t := Times{Modified: info.ModTime()}
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		t.Accessed = tsync.FromTimespec(st.Atim)
+		t.Changed = tsync.FromTimespec(st.Ctim)
+	}
+	return t
+}
+
+// hiddenAttr reports false: Linux hides files by name only.
+func hiddenAttr(os.FileInfo) bool {
+	return false
+}
@@ -0,0 +1,15 @@
+//go:build !linux && !darwin && !freebsd && !netbsd && !windows
+
+package fs
+
+import "os"
+
+// fileTimes returns only the modification time.
+func fileTimes(info os.FileInfo) Times {
+	return Times{Modified: info.ModTime()}
+}
+
+// hiddenAttr reports false: files are hidden by name only.
+func hiddenAttr(os.FileInfo) bool {
+	return false
+}
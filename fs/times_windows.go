@@ -0,0 +1,26 @@
+//go:build windows
+
+package fs
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// fileTimes reads the times from the attribute data, which the directory
+// listing already includes.
+func fileTimes(info os.FileInfo) Times {
+	t := Times{Modified: info.ModTime()}
+	if data, ok := info.Sys().(*syscall.Win32FileAttributeData); ok {
+		t.Accessed = time.Unix(0, data.LastAccessTime.Nanoseconds())
+		t.Created = time.Unix(0, data.CreationTime.Nanoseconds())
+	}
+	return t
+}
+
+// hiddenAttr reports whether FILE_ATTRIBUTE_HIDDEN is set.
+func hiddenAttr(info os.FileInfo) bool {
+	data, ok := info.Sys().(*syscall.Win32FileAttributeData)
+	return ok && data.FileAttributes&syscall.FILE_ATTRIBUTE_HIDDEN != 0
+}
@@ -0,0 +1,65 @@
+package fs
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrUnsupported is returned by functions not implemented on this
+// platform.
+var ErrUnsupported = fmt.Errorf("oscompat/fs: %w", errors.ErrUnsupported)
+
+// Volume describes a mounted filesystem or Windows drive.
+type Volume struct {
+	// Path is where the volume is mounted, such as "/", "/media/usb", or
+	// `D:\`.
+	Path string
+
+	// Device identifies the backing device or share, such as "/dev/sdb1",
+	// "//server/share", or `\\?\Volume{...}\`. It may be empty.
+	Device string
+
+	// Label is the volume label or name, if any.
+	Label string
+
+	// FSType is the filesystem type, such as "ext4", "apfs", or "NTFS".
+	FSType string
+
+	// Total is the size of the volume and Free the space available to
+	// the calling user, in bytes. Both are 0 if the volume could not be
+	// queried.
+	Total uint64
+	Free  uint64
+
+	ReadOnly  bool
+	Removable bool // a USB drive, memory card, or optical disc
+	Network   bool // a network share such as NFS or SMB
+}
+
+// ListVolumes returns the volumes a user would consider as a place to
+// store files, such as a backup destination, sorted by Path.
+//
+// Platform behavior:
+//   - Linux: mounts from /proc/self/mountinfo backed by a block device or
+//     network filesystem, with space from statfs, labels from
+//     /dev/disk/by-label, and removable media detected through sysfs.
+//     Pseudo filesystems, snaps, and repeated mounts of one device are
+//     left out.
+//   - macOS and FreeBSD: getfsstat, skipping pseudo filesystems and, on
+//     macOS, volumes hidden from the Finder. Volumes under /Volumes are
+//     reported as removable.
+//   - Windows: each drive letter from GetLogicalDriveStrings, with
+//     GetVolumeInformation and GetDiskFreeSpaceEx. Drives without media,
+//     such as an empty card reader, are left out.
+//
+// Querying a stale network mount can block until its server responds.
+func ListVolumes() ([]Volume, error) {
+	return listVolumes()
+}
+
+// sortVolumes sorts vols by Path.
+func sortVolumes(vols []Volume) {
+	slices.SortFunc(vols, func(a, b Volume) int { return strings.Compare(a.Path, b.Path) })
+}
@@ -0,0 +1,79 @@
+//go:build darwin || freebsd
+
+package fs
+
+import (
+	"strings"
+	"syscall"
+)
+
+const (
+	mntRdonly = 0x1
+	mntLocal  = 0x1000
+	mntNowait = 2
+)
+
+// pseudoFSTypes are filesystems that hold no user files.
+var pseudoFSTypes = map[string]bool{
+	"devfs": true, "autofs": true, "fdescfs": true, "procfs": true,
+	"linprocfs": true, "linsysfs": true, "tmpfs": true, "nullfs": true,
+}
+
+// listVolumes calls getfsstat.
+func listVolumes() ([]Volume, error) {
+	n, err := syscall.Getfsstat(nil, mntNowait)
+	if err != nil {
+		return nil, err
+	}
+	buf := make([]syscall.Statfs_t, n)
+	n, err = syscall.Getfsstat(buf, mntNowait)
+	if err != nil {
+		return nil, err
+	}
+
+	var vols []Volume
+	for _, st := range buf[:n] {
+		fsType := int8String(st.Fstypename[:])
+		path := int8String(st.Mntonname[:])
+		flags := uint64(st.Flags)
+		if pseudoFSTypes[fsType] || (flags&mntHidden != 0 && path != "/") {
+			continue
+		}
+		vols = append(vols, Volume{
+			Path:      path,
+			Device:    int8String(st.Mntfromname[:]),
+			Label:     volumeLabel(path),
+			FSType:    fsType,
+			Total:     uint64(st.Blocks) * uint64(st.Bsize),
+			Free:      uint64(max(st.Bavail, 0)) * uint64(st.Bsize),
+			ReadOnly:  flags&mntRdonly != 0,
+			Removable: flags&mntLocal != 0 && isRemovableMount(path),
+			Network:   flags&mntLocal == 0,
+		})
+	}
+	sortVolumes(vols)
+	return vols, nil
+}
+
+// int8String converts a NUL-terminated C char array.
+func int8String(a []int8) string {
+	b := make([]byte, 0, len(a))
+	for _, c := range a {
+		if c == 0 {
+			break
+		}
+		b = append(b, byte(c))
+	}
+	return string(b)
+}
+
+// volumeLabel returns the name of a volume mounted under /Volumes or
+// /media, which is its label.
+func volumeLabel(path string) string {
+	for _, dir := range []string{"/Volumes/", "/media/"} {
+		if name, ok := strings.CutPrefix(path, dir); ok && !strings.Contains(name, "/") {
+			return name
+		}
+	}
+	return ""
+}
@@ -0,0 +1,14 @@
+//go:build darwin
+
+package fs
+
+import "strings"
+
+// mntHidden is MNT_DONTBROWSE, set on system volumes the Finder hides.
+const mntHidden = 0x00100000
+
+// isRemovableMount reports whether a local volume is mounted where macOS
+// puts external disks and disk images.
+func isRemovableMount(path string) bool {
+	return strings.HasPrefix(path, "/Volumes/")
+}
@@ -0,0 +1,14 @@
+//go:build freebsd
+
+package fs
+
+import "strings"
+
+// mntHidden is unused on FreeBSD, which has no equivalent of MNT_DONTBROWSE.
+const mntHidden = 0
+
+// isRemovableMount reports whether a local volume is mounted where
+// automounters put removable media.
+func isRemovableMount(path string) bool {
+	return strings.HasPrefix(path, "/media/")
+}
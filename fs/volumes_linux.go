@@ -0,0 +1,151 @@
+//go:build linux
+
+package fs
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// networkFSTypes are Linux filesystem types served over the network.
+var networkFSTypes = map[string]bool{
+	"nfs": true, "nfs4": true, "cifs": true, "smb3": true, "smbfs": true,
+	"ncpfs": true, "afs": true, "ceph": true, "glusterfs": true, "9p": true,
+	"davfs": true, "fuse.sshfs": true, "fuse.rclone": true,
+}
+
+// listVolumes parses /proc/self/mountinfo.
+func listVolumes() ([]Volume, error) {
+	f, err := os.Open("/proc/self/mountinfo")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	labels := diskLabels()
+	seen := make(map[string]bool)
+	var vols []Volume
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		// id parent major:minor root mountpoint options [optional...] - fstype source superoptions
+		pre, post, ok := strings.Cut(sc.Text(), " - ")
+		if !ok {
+			continue
+		}
+		a, b := strings.Fields(pre), strings.Fields(post)
+		if len(a) < 6 || len(b) < 2 {
+			continue
+		}
+		mountPoint, fsType, source := unescapeMount(a[4]), b[0], unescapeMount(b[1])
+		network := networkFSTypes[fsType]
+		if !network && !strings.HasPrefix(source, "/dev/") && mountPoint != "/" {
+			continue
+		}
+		if fsType == "squashfs" || strings.HasPrefix(source, "/dev/loop") {
+			continue // snaps and other read-only images
+		}
+		// A device mounted more than once, as with bind mounts and
+		// btrfs subvolumes, is listed at its first mount point.
+		if seen[a[2]] {
+			continue
+		}
+		seen[a[2]] = true
+
+		v := Volume{
+			Path:     mountPoint,
+			Device:   source,
+			FSType:   fsType,
+			ReadOnly: slices.Contains(strings.Split(a[5], ","), "ro"),
+			Network:  network,
+		}
+		if dev, err := filepath.EvalSymlinks(source); err == nil {
+			v.Label = labels[dev]
+			v.Removable = isRemovableDevice(dev)
+		}
+		var st syscall.Statfs_t
+		if err := syscall.Statfs(mountPoint, &st); err == nil {
+			v.Total = st.Blocks * uint64(st.Bsize)
+			v.Free = st.Bavail * uint64(st.Bsize)
+		}
+		vols = append(vols, v)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	sortVolumes(vols)
+	return vols, nil
+}
+
+// unescapeMount decodes the octal escapes, such as \040 for a space, that
+// the kernel uses in mount tables.
+func unescapeMount(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+3 < len(s) {
+			if n, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(n))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// diskLabels maps device paths to the labels udev links them by.
+func diskLabels() map[string]string {
+	const dir = "/dev/disk/by-label"
+	labels := make(map[string]string)
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		dev, err := filepath.EvalSymlinks(filepath.Join(dir, e.Name()))
+		if err == nil {
+			// udev escapes unsafe characters the way mount tables do,
+			// but in hex: \x20 for a space.
+			labels[dev] = unescapeUdev(e.Name())
+		}
+	}
+	return labels
+}
+
+// unescapeUdev decodes \xNN escapes.
+func unescapeUdev(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+3 < len(s) && s[i+1] == 'x' {
+			if n, err := strconv.ParseUint(s[i+2:i+4], 16, 8); err == nil {
+				b.WriteByte(byte(n))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// isRemovableDevice reports whether the disk holding dev is marked
+// removable, as memory cards and optical drives are, or sits on a USB
+// bus, as USB sticks and external disks do.
+func isRemovableDevice(dev string) bool {
+	sys, err := filepath.EvalSymlinks(filepath.Join("/sys/class/block", filepath.Base(dev)))
+	if err != nil {
+		return false
+	}
+	if _, err := os.Stat(filepath.Join(sys, "partition")); err == nil {
+		sys = filepath.Dir(sys)
+	}
+	if data, err := os.ReadFile(filepath.Join(sys, "removable")); err == nil && strings.TrimSpace(string(data)) == "1" {
+		return true
+	}
+	return strings.Contains(sys, "/usb")
+}
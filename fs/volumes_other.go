@@ -0,0 +1,7 @@
+//go:build !linux && !darwin && !freebsd && !windows
+
+package fs
+
+func listVolumes() ([]Volume, error) {
+	return nil, ErrUnsupported
+}
@@ -0,0 +1,52 @@
+package fs_test
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/grokify/oscompat/fs"
+)
+
+func TestListVolumes(t *testing.T) {
+	vols, err := fs.ListVolumes()
+	if errors.Is(err, fs.ErrUnsupported) {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatalf("ListVolumes() error: %v", err)
+	}
+	if len(vols) == 0 {
+		t.Fatal("ListVolumes() returned no volumes")
+	}
+	if !slices.IsSortedFunc(vols, func(a, b fs.Volume) int { return strings.Compare(a.Path, b.Path) }) {
+		t.Error("volumes are not sorted by path")
+	}
+	for _, v := range vols {
+		t.Logf("%+v", v)
+		if v.Path == "" || v.FSType == "" {
+			t.Errorf("incomplete volume %+v", v)
+		}
+		if v.Free > v.Total {
+			t.Errorf("%s: free %d exceeds total %d", v.Path, v.Free, v.Total)
+		}
+	}
+}
+
+func TestIsRemovableAndEject(t *testing.T) {
+	dir := t.TempDir()
+	removable, err := fs.IsRemovable(dir)
+	if errors.Is(err, fs.ErrUnsupported) {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatalf("IsRemovable() error: %v", err)
+	}
+	if removable {
+		t.Skip("temporary directory is on removable media")
+	}
+	if err := fs.Eject(dir); !errors.Is(err, fs.ErrNotRemovable) {
+		t.Errorf("Eject() error = %v, want ErrNotRemovable", err)
+	}
+}
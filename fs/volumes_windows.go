@@ -0,0 +1,124 @@
+//go:build windows
+
+package fs
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+var (
+	procGetLogicalDriveStringsW           = kernel32.NewProc("GetLogicalDriveStringsW")
+	procGetDriveTypeW                     = kernel32.NewProc("GetDriveTypeW")
+	procGetVolumeInformationW             = kernel32.NewProc("GetVolumeInformationW")
+	procGetDiskFreeSpaceExW               = kernel32.NewProc("GetDiskFreeSpaceExW")
+	procGetVolumeNameForVolumeMountPointW = kernel32.NewProc("GetVolumeNameForVolumeMountPointW")
+	mpr                                   = syscall.NewLazyDLL("mpr.dll")
+	procWNetGetConnectionW                = mpr.NewProc("WNetGetConnectionW")
+)
+
+const (
+	driveRemovable = 2
+	driveRemote    = 4
+	driveCDROM     = 5
+
+	fileReadOnlyVolume = 0x00080000
+)
+
+// listVolumes queries each drive letter.
+func listVolumes() ([]Volume, error) {
+	buf := make([]uint16, 256)
+	n, _, err := procGetLogicalDriveStringsW.Call(uintptr(len(buf)), uintptr(unsafe.Pointer(&buf[0])))
+	if n == 0 {
+		return nil, err
+	}
+	if int(n) > len(buf) {
+		buf = make([]uint16, n)
+		if n, _, err = procGetLogicalDriveStringsW.Call(uintptr(len(buf)), uintptr(unsafe.Pointer(&buf[0]))); n == 0 {
+			return nil, err
+		}
+	}
+
+	var vols []Volume
+	for _, root := range splitMultiSZ(buf[:n]) {
+		if v, ok := driveVolume(root); ok {
+			vols = append(vols, v)
+		}
+	}
+	sortVolumes(vols)
+	return vols, nil
+}
+
+// driveVolume describes the drive with the given root, such as `C:\`. It
+// reports false for drives without media.
+func driveVolume(root string) (Volume, bool) {
+	root16, err := syscall.UTF16PtrFromString(root)
+	if err != nil {
+		return Volume{}, false
+	}
+	var label, fsName [syscall.MAX_PATH + 1]uint16
+	var flags uint32
+	r, _, _ := procGetVolumeInformationW.Call(
+		uintptr(unsafe.Pointer(root16)),
+		uintptr(unsafe.Pointer(&label[0])), uintptr(len(label)),
+		0, 0, uintptr(unsafe.Pointer(&flags)),
+		uintptr(unsafe.Pointer(&fsName[0])), uintptr(len(fsName)))
+	if r == 0 {
+		return Volume{}, false // ERROR_NOT_READY: no disc or card inserted
+	}
+
+	driveType, _, _ := procGetDriveTypeW.Call(uintptr(unsafe.Pointer(root16)))
+	v := Volume{
+		Path:      root,
+		Label:     syscall.UTF16ToString(label[:]),
+		FSType:    syscall.UTF16ToString(fsName[:]),
+		ReadOnly:  flags&fileReadOnlyVolume != 0,
+		Removable: driveType == driveRemovable || driveType == driveCDROM,
+		Network:   driveType == driveRemote,
+	}
+	if v.Network {
+		v.Device = networkShare(root)
+	} else {
+		var name [50]uint16 // \\?\Volume{GUID}\ and a NUL
+		if r, _, _ := procGetVolumeNameForVolumeMountPointW.Call(
+			uintptr(unsafe.Pointer(root16)), uintptr(unsafe.Pointer(&name[0])), uintptr(len(name))); r != 0 {
+			v.Device = syscall.UTF16ToString(name[:])
+		}
+	}
+	var free, total uint64
+	if r, _, _ := procGetDiskFreeSpaceExW.Call(uintptr(unsafe.Pointer(root16)),
+		uintptr(unsafe.Pointer(&free)), uintptr(unsafe.Pointer(&total)), 0); r != 0 {
+		v.Total, v.Free = total, free
+	}
+	return v, true
+}
+
+// networkShare returns the UNC path a mapped drive such as `Z:\` refers to.
+func networkShare(root string) string {
+	local, err := syscall.UTF16PtrFromString(root[:2])
+	if err != nil {
+		return ""
+	}
+	buf := make([]uint16, syscall.MAX_PATH)
+	size := uint32(len(buf))
+	if r, _, _ := procWNetGetConnectionW.Call(uintptr(unsafe.Pointer(local)),
+		uintptr(unsafe.Pointer(&buf[0])), uintptr(unsafe.Pointer(&size))); r != 0 {
+		return ""
+	}
+	return syscall.UTF16ToString(buf)
+}
+
+// splitMultiSZ splits a list of NUL-terminated strings ending with an
+// empty one.
+func splitMultiSZ(buf []uint16) []string {
+	var list []string
+	for start, i := 0, 0; i < len(buf); i++ {
+		if buf[i] == 0 {
+			if i > start {
+				list = append(list, syscall.UTF16ToString(buf[start:i]))
+			}
+			start = i + 1
+		}
+	}
+	return list
+}
@@ -0,0 +1,47 @@
+package id
+
+import (
+	"errors"
+	"strings"
+	"sync"
+)
+
+// ErrBootIDNotFound is returned when the platform's boot identifier cannot
+// be determined.
+var ErrBootIDNotFound = errors.New("oscompat/id: boot id not found")
+
+// bootID reads the platform boot ID once per process.
+var bootID = sync.OnceValues(func() (string, error) {
+	raw, err := readBootID()
+	if err != nil {
+		return "", err
+	}
+	raw = strings.ToLower(strings.TrimSpace(raw))
+	if raw == "" {
+		return "", ErrBootIDNotFound
+	}
+	return raw, nil
+})
+
+// processID is generated on first use and never changes for the lifetime
+// of the process.
+var processID = sync.OnceValue(Generate32)
+
+// BootID returns an identifier that is unique to the current boot of the
+// host. It changes on every restart of the operating system, which makes it
+// useful for correlating logs and detecting reboots.
+//
+// Platform sources:
+//   - Linux: /proc/sys/kernel/random/boot_id
+//   - macOS: kern.bootsessionuuid
+//   - Windows: hash of the boot counter (or boot time) and MachineGuid
+func BootID() (string, error) {
+	return bootID()
+}
+
+// ProcessID returns a 32-character hex identifier generated randomly once
+// per process. Unlike the OS process ID, it is never reused, so it can
+// distinguish runs of the same program across restarts.
+func ProcessID() string {
+	return processID()
+}
@@ -0,0 +1,14 @@
+//go:build darwin
+
+package id
+
+import "syscall"
+
+// readBootID reads the boot session UUID maintained by the kernel.
+func readBootID() (string, error) {
+	uuid, err := syscall.Sysctl("kern.bootsessionuuid")
+	if err != nil {
+		return "", ErrBootIDNotFound
+	}
+	return uuid, nil
+}
@@ -0,0 +1,14 @@
+//go:build linux
+
+package id
+
+import "os"
+
+// readBootID reads the kernel's random boot ID.
+func readBootID() (string, error) {
+	data, err := os.ReadFile("/proc/sys/kernel/random/boot_id")
+	if err != nil {
+		return "", ErrBootIDNotFound
+	}
+	return string(data), nil
+}
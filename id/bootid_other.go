@@ -0,0 +1,8 @@
+//go:build !darwin && !windows && !linux
+
+package id
+
+// readBootID reports that no boot ID is available on this platform.
+func readBootID() (string, error) {
+	return "", ErrBootIDNotFound
+}
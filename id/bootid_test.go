@@ -0,0 +1,56 @@
+package id_test
+
+import (
+	"regexp"
+	"runtime"
+	"testing"
+
+	"github.com/grokify/oscompat/id"
+)
+
+func TestBootID(t *testing.T) {
+	bid, err := id.BootID()
+	if err == id.ErrBootIDNotFound {
+		t.Skip("boot ID not available in this environment")
+	}
+	if err != nil {
+		t.Fatalf("BootID() error: %v", err)
+	}
+	if bid == "" {
+		t.Error("BootID() returned empty string")
+	}
+
+	again, err := id.BootID()
+	if err != nil || again != bid {
+		t.Errorf("BootID() not stable: %q then %q (%v)", bid, again, err)
+	}
+}
+
+func TestBootIDFormat(t *testing.T) {
+	bid, err := id.BootID()
+	if err == id.ErrBootIDNotFound {
+		t.Skip("boot ID not available in this environment")
+	}
+	if err != nil {
+		t.Fatalf("BootID() error: %v", err)
+	}
+
+	// Linux and macOS report a UUID; Windows hashes to 16 bytes of hex.
+	format := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+	if runtime.GOOS == "windows" {
+		format = regexp.MustCompile(`^[0-9a-f]{32}$`)
+	}
+	if !format.MatchString(bid) {
+		t.Errorf("BootID() = %q, want match for %s", bid, format)
+	}
+}
+
+func TestProcessID(t *testing.T) {
+	pid := id.ProcessID()
+	if len(pid) != 32 {
+		t.Errorf("ProcessID() returned length %d, want 32", len(pid))
+	}
+	if again := id.ProcessID(); again != pid {
+		t.Errorf("ProcessID() not stable: %q then %q", pid, again)
+	}
+}
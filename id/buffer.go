@@ -0,0 +1,58 @@
+package id
+
+import (
+	"bufio"
+	"crypto/rand"
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+// bufferSize is the number of random bytes each pooled reader fetches from
+// crypto/rand at a time.
+const bufferSize = 4096
+
+var (
+	buffered atomic.Bool
+
+	readerPool = sync.Pool{
+		New: func() any {
+			return bufio.NewReaderSize(rand.Reader, bufferSize)
+		},
+	}
+)
+
+// EnableBuffering makes all generators in this package read randomness
+// through a pool of buffered readers over crypto/rand instead of calling
+// crypto/rand for every ID. This amortizes the syscall cost across many
+// IDs, which helps hot paths generating small IDs at high rates.
+//
+// Buffering is off by default. While enabled, up to a few kilobytes of
+// not-yet-used random bytes are held in process memory per pooled reader;
+// deployments that must not retain key material in memory should leave
+// buffering disabled.
+//
+// It is safe to call EnableBuffering and DisableBuffering concurrently with
+// ID generation.
+func EnableBuffering() {
+	buffered.Store(true)
+}
+
+// DisableBuffering restores the default of reading directly from crypto/rand
+// for every ID.
+func DisableBuffering() {
+	buffered.Store(false)
+}
+
+// BufferingEnabled reports whether EnableBuffering is in effect.
+func BufferingEnabled() bool {
+	return buffered.Load()
+}
+
+// readBuffered fills b from a pooled buffered reader.
+func readBuffered(b []byte) error {
+	r := readerPool.Get().(*bufio.Reader)
+	defer readerPool.Put(r)
+	_, err := io.ReadFull(r, b)
+	return err
+}
@@ -0,0 +1,101 @@
+package id_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/grokify/oscompat/id"
+)
+
+// withBuffering enables buffering for the duration of a test or benchmark.
+func withBuffering(tb testing.TB) {
+	tb.Helper()
+	id.EnableBuffering()
+	tb.Cleanup(id.DisableBuffering)
+}
+
+func TestEnableBuffering(t *testing.T) {
+	if id.BufferingEnabled() {
+		t.Fatal("buffering should be disabled by default")
+	}
+
+	withBuffering(t)
+	if !id.BufferingEnabled() {
+		t.Fatal("BufferingEnabled() = false after EnableBuffering()")
+	}
+
+	const count = 10000
+	seen := make(map[string]struct{}, count)
+	for i := 0; i < count; i++ {
+		s := id.Generate16()
+		if len(s) != 16 {
+			t.Fatalf("Generate16() returned length %d, want 16", len(s))
+		}
+		if _, exists := seen[s]; exists {
+			t.Fatalf("duplicate ID generated with buffering at iteration %d: %s", i, s)
+		}
+		seen[s] = struct{}{}
+	}
+}
+
+func TestBufferingConcurrent(t *testing.T) {
+	withBuffering(t)
+
+	const (
+		goroutines = 50
+		perRoutine = 200
+	)
+
+	var (
+		mu  sync.Mutex
+		ids = make(map[string]struct{}, goroutines*perRoutine)
+		wg  sync.WaitGroup
+	)
+
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			local := make([]string, perRoutine)
+			for j := range local {
+				local[j] = id.Generate16()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			for _, s := range local {
+				if _, exists := ids[s]; exists {
+					t.Errorf("duplicate ID generated: %s", s)
+					return
+				}
+				ids[s] = struct{}{}
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+func BenchmarkGenerate16Buffered(b *testing.B) {
+	withBuffering(b)
+	for i := 0; i < b.N; i++ {
+		id.Generate16()
+	}
+}
+
+func BenchmarkGenerate16BufferedParallel(b *testing.B) {
+	withBuffering(b)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			id.Generate16()
+		}
+	})
+}
+
+func BenchmarkGenerate16Parallel(b *testing.B) {
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			id.Generate16()
+		}
+	})
+}
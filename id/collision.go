@@ -0,0 +1,45 @@
+package id
+
+import "math"
+
+// CollisionProbability returns the probability that at least two of count
+// IDs of byteLen random bytes are equal, by the birthday bound
+// 1 - exp(-count*(count-1) / 2^(8*byteLen+1)). It is the probability for
+// IDs from Generate, GenerateEncoded, Token, and Filename, whose encoding
+// does not change the number of random bits.
+//
+// For example, a billion 8-byte IDs collide with probability of about
+// 2.7%, while a billion 16-byte IDs collide with probability of about
+// 1.5e-21.
+func CollisionProbability(byteLen, count int) float64 {
+	if count < 2 {
+		return 0
+	}
+	if byteLen <= 0 {
+		return 1
+	}
+	n := float64(count)
+	pairs := n * (n - 1) / 2
+	// Expm1 keeps precision for the tiny probabilities of long IDs.
+	return -math.Expm1(-pairs / math.Ldexp(1, 8*byteLen))
+}
+
+// RecommendedLength returns the smallest number of random bytes for which
+// count IDs collide with probability at most maxProb, according to
+// CollisionProbability. No length makes collisions impossible, so if
+// maxProb is not positive it returns 32 bytes (256 bits), which no
+// practical number of IDs comes close to colliding at.
+//
+// A common choice of maxProb is 1e-12, below the rate of undetected
+// hardware errors; for a million IDs this gives 10 bytes, and for a
+// trillion 15 bytes.
+func RecommendedLength(count int, maxProb float64) int {
+	if maxProb <= 0 {
+		return 32
+	}
+	n := 1
+	for CollisionProbability(n, count) > maxProb {
+		n++
+	}
+	return n
+}
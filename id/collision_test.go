@@ -0,0 +1,52 @@
+package id_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/grokify/oscompat/id"
+)
+
+func TestCollisionProbability(t *testing.T) {
+	tests := []struct {
+		byteLen, count int
+		want           float64
+	}{
+		{8, 1e9, 0.02674},
+		{16, 1e9, 1.469e-21},
+		{1, 2, 0.0038986}, // the approximation; exactly 1/256
+		{4, 1, 0},
+		{0, 2, 1},
+		{200, 1e9, 0},
+	}
+	for _, tt := range tests {
+		got := id.CollisionProbability(tt.byteLen, tt.count)
+		if math.Abs(got-tt.want) > tt.want*1e-3+1e-300 {
+			t.Errorf("CollisionProbability(%d, %d) = %g, want %g", tt.byteLen, tt.count, got, tt.want)
+		}
+	}
+}
+
+func TestRecommendedLength(t *testing.T) {
+	tests := []struct {
+		count   int
+		maxProb float64
+		want    int
+	}{
+		{1e6, 1e-12, 10},
+		{1e12, 1e-12, 15},
+		{1e9, 0.5, 8},
+		{1, 1e-12, 1},
+		{1e6, 0, 32},
+		{1e6, -1, 32},
+	}
+	for _, tt := range tests {
+		got := id.RecommendedLength(tt.count, tt.maxProb)
+		if got != tt.want {
+			t.Errorf("RecommendedLength(%d, %g) = %d, want %d", tt.count, tt.maxProb, got, tt.want)
+		}
+		if tt.maxProb > 0 && id.CollisionProbability(got, tt.count) > tt.maxProb {
+			t.Errorf("RecommendedLength(%d, %g) = %d exceeds maxProb", tt.count, tt.maxProb, got)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package id
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// Well-known UUIDv5 namespaces from RFC 9562.
+const (
+	NamespaceDNS  = "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	NamespaceURL  = "6ba7b811-9dad-11d1-80b4-00c04fd430c8"
+	NamespaceOID  = "6ba7b812-9dad-11d1-80b4-00c04fd430c8"
+	NamespaceX500 = "6ba7b814-9dad-11d1-80b4-00c04fd430c8"
+)
+
+// ErrInvalidUUID is returned when a namespace is not a valid UUID string.
+var ErrInvalidUUID = errors.New("oscompat/id: invalid uuid")
+
+// Derive returns a deterministic 32-character hex ID for name within
+// namespace, computed with SHA-256. The same inputs always produce the same
+// ID on every platform, which makes it suitable for idempotent resource
+// creation.
+//
+// The namespace is length-prefixed before hashing, so ("ab", "c") and
+// ("a", "bc") produce different IDs.
+//
+// Example:
+//
+//	id.Derive("buckets", "photos") // same value on every call and host
+func Derive(namespace, name string) string {
+	var n [8]byte
+	binary.BigEndian.PutUint64(n[:], uint64(len(namespace)))
+
+	h := sha256.New()
+	h.Write(n[:])
+	h.Write([]byte(namespace))
+	h.Write([]byte(name))
+	return hex.EncodeToString(h.Sum(nil)[:16])
+}
+
+// DeriveUUID returns the RFC 9562 version 5 UUID for name within the given
+// namespace UUID, such as NamespaceDNS or NamespaceURL. Use it when
+// deterministic IDs must interoperate with other UUIDv5 implementations.
+func DeriveUUID(namespace, name string) (string, error) {
+	ns, err := parseUUID(namespace)
+	if err != nil {
+		return "", err
+	}
+
+	h := sha1.New() // UUIDv5 is defined in terms of SHA-1
+	h.Write(ns)
+	h.Write([]byte(name))
+	u := h.Sum(nil)[:16]
+
+	u[6] = (u[6] & 0x0f) | 0x50 // version 5
+	u[8] = (u[8] & 0x3f) | 0x80 // RFC 9562 variant
+	return formatUUID(u), nil
+}
+
+// parseUUID parses the canonical 8-4-4-4-12 hex form of a UUID.
+func parseUUID(s string) ([]byte, error) {
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return nil, ErrInvalidUUID
+	}
+	b, err := hex.DecodeString(strings.ReplaceAll(s, "-", ""))
+	if err != nil {
+		return nil, ErrInvalidUUID
+	}
+	return b, nil
+}
+
+// formatUUID formats 16 bytes in the canonical lowercase 8-4-4-4-12 form.
+func formatUUID(u []byte) string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], u[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], u[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], u[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], u[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], u[10:16])
+	return string(buf[:])
+}
@@ -0,0 +1,53 @@
+package id_test
+
+import (
+	"testing"
+
+	"github.com/grokify/oscompat/id"
+)
+
+func TestDerive(t *testing.T) {
+	a := id.Derive("buckets", "photos")
+	if len(a) != 32 {
+		t.Errorf("Derive() returned length %d, want 32", len(a))
+	}
+	if again := id.Derive("buckets", "photos"); again != a {
+		t.Errorf("Derive() not deterministic: %q then %q", a, again)
+	}
+	if other := id.Derive("buckets", "videos"); other == a {
+		t.Error("Derive() returned same ID for different names")
+	}
+	if id.Derive("ab", "c") == id.Derive("a", "bc") {
+		t.Error("Derive() is ambiguous across the namespace/name boundary")
+	}
+}
+
+func TestDeriveUUID(t *testing.T) {
+	tests := []struct {
+		namespace string
+		name      string
+		want      string
+	}{
+		// Reference values from RFC 9562 and Python's uuid.uuid5.
+		{id.NamespaceDNS, "www.example.com", "2ed6657d-e927-568b-95e1-2665a8aea6a2"},
+		{id.NamespaceDNS, "python.org", "886313e1-3b8a-5372-9b90-0c9aee199e5d"},
+	}
+
+	for _, tt := range tests {
+		got, err := id.DeriveUUID(tt.namespace, tt.name)
+		if err != nil {
+			t.Fatalf("DeriveUUID(%q) error: %v", tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("DeriveUUID(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDeriveUUIDInvalidNamespace(t *testing.T) {
+	for _, ns := range []string{"", "not-a-uuid", "6ba7b8109dad11d180b400c04fd430c8"} {
+		if _, err := id.DeriveUUID(ns, "x"); err != id.ErrInvalidUUID {
+			t.Errorf("DeriveUUID(%q) = %v, want ErrInvalidUUID", ns, err)
+		}
+	}
+}
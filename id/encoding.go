@@ -0,0 +1,217 @@
+package id
+
+import (
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"strconv"
+)
+
+// Encoding selects how random bytes are rendered as a string.
+type Encoding int
+
+// Supported encodings.
+const (
+	// Hex is lowercase hexadecimal (2 characters per byte).
+	Hex Encoding = iota
+
+	// Base32Lower is RFC 4648 base32 with a lowercase alphabet and no padding
+	// (8 characters per 5 bytes). It is case-insensitive-safe, making it a good
+	// fit for DNS labels and filenames on case-insensitive filesystems.
+	Base32Lower
+
+	// Base58 uses the Bitcoin alphabet, which omits the easily confused
+	// characters 0, O, I, and l.
+	Base58
+
+	// Base62 uses digits and upper- and lowercase ASCII letters. It is the
+	// densest encoding that is URL-safe without escaping, but it is
+	// case-sensitive.
+	Base62
+)
+
+// ErrInvalidEncoding is returned when a string is not valid for an encoding
+// or an unknown Encoding is used.
+var ErrInvalidEncoding = errors.New("oscompat/id: invalid encoding")
+
+const (
+	base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+)
+
+// base32Lower is RFC 4648 base32 with a lowercase alphabet and no padding.
+var base32Lower = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+// String returns the name of the encoding.
+func (e Encoding) String() string {
+	switch e {
+	case Hex:
+		return "hex"
+	case Base32Lower:
+		return "base32lower"
+	case Base58:
+		return "base58"
+	case Base62:
+		return "base62"
+	default:
+		return "Encoding(" + strconv.Itoa(int(e)) + ")"
+	}
+}
+
+// Encode encodes b as a string. It panics if e is not a supported encoding.
+func (e Encoding) Encode(b []byte) string {
+	switch e {
+	case Hex:
+		return hex.EncodeToString(b)
+	case Base32Lower:
+		return base32Lower.EncodeToString(b)
+	case Base58:
+		return encodeBaseX(b, base58Alphabet)
+	case Base62:
+		return encodeBaseX(b, base62Alphabet)
+	default:
+		panic(ErrInvalidEncoding.Error() + ": " + e.String())
+	}
+}
+
+// Decode decodes s, which must have been produced by Encode with the same
+// encoding. It returns ErrInvalidEncoding if s is malformed.
+func (e Encoding) Decode(s string) ([]byte, error) {
+	var (
+		b   []byte
+		err error
+	)
+	switch e {
+	case Hex:
+		if !isLowerHex(s) {
+			return nil, ErrInvalidEncoding
+		}
+		b, err = hex.DecodeString(s)
+	case Base32Lower:
+		b, err = base32Lower.DecodeString(s)
+	case Base58:
+		b, err = decodeBaseX(s, base58Alphabet)
+	case Base62:
+		b, err = decodeBaseX(s, base62Alphabet)
+	default:
+		return nil, ErrInvalidEncoding
+	}
+	if err != nil {
+		return nil, ErrInvalidEncoding
+	}
+	return b, nil
+}
+
+// GenerateBytes returns n cryptographically random bytes.
+//
+// This function panics if crypto/rand fails, which should never happen
+// on a properly functioning system.
+func GenerateBytes(n int) []byte {
+	b := make([]byte, n)
+	readRandom(b)
+	return b
+}
+
+// GenerateEncoded returns a cryptographically random ID of byteLen random
+// bytes rendered with the given encoding. For the same entropy, Base62 and
+// Base58 produce strings roughly 30% shorter than Hex.
+//
+// Example:
+//
+//	id.GenerateEncoded(16, id.Base62)      // ~22 characters
+//	id.GenerateEncoded(10, id.Base32Lower) // 16 characters
+func GenerateEncoded(byteLen int, enc Encoding) string {
+	return enc.Encode(GenerateBytes(byteLen))
+}
+
+// isLowerHex reports whether s contains only lowercase hex digits.
+func isLowerHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !(c >= '0' && c <= '9') && !(c >= 'a' && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// encodeBaseX encodes b as a big-endian number in the alphabet's base.
+// Leading zero bytes are preserved as leading zero digits so that the
+// byte length survives a round trip, as in Bitcoin's base58.
+func encodeBaseX(b []byte, alphabet string) string {
+	base := len(alphabet)
+
+	zeros := 0
+	for zeros < len(b) && b[zeros] == 0 {
+		zeros++
+	}
+
+	// Repeated division of the byte string by the base, collecting remainders.
+	digits := make([]byte, 0, len(b)*138/100+1)
+	num := append([]byte(nil), b[zeros:]...)
+	for len(num) > 0 {
+		var rem int
+		quotient := num[:0]
+		for _, d := range num {
+			acc := rem<<8 | int(d)
+			q := acc / base
+			rem = acc % base
+			if len(quotient) > 0 || q != 0 {
+				quotient = append(quotient, byte(q))
+			}
+		}
+		digits = append(digits, alphabet[rem])
+		num = quotient
+	}
+
+	out := make([]byte, zeros+len(digits))
+	for i := 0; i < zeros; i++ {
+		out[i] = alphabet[0]
+	}
+	for i, d := range digits {
+		out[len(out)-1-i] = d
+	}
+	return string(out)
+}
+
+// decodeBaseX is the inverse of encodeBaseX.
+func decodeBaseX(s string, alphabet string) ([]byte, error) {
+	base := len(alphabet)
+
+	var index [256]int
+	for i := range index {
+		index[i] = -1
+	}
+	for i := 0; i < base; i++ {
+		index[alphabet[i]] = i
+	}
+
+	zeros := 0
+	for zeros < len(s) && s[zeros] == alphabet[0] {
+		zeros++
+	}
+
+	// Little-endian accumulator of the decoded number.
+	var num []byte
+	for i := zeros; i < len(s); i++ {
+		carry := index[s[i]]
+		if carry < 0 {
+			return nil, ErrInvalidEncoding
+		}
+		for j := range num {
+			carry += int(num[j]) * base
+			num[j] = byte(carry)
+			carry >>= 8
+		}
+		for carry > 0 {
+			num = append(num, byte(carry))
+			carry >>= 8
+		}
+	}
+
+	out := make([]byte, zeros+len(num))
+	for i, d := range num {
+		out[len(out)-1-i] = d
+	}
+	return out, nil
+}
@@ -0,0 +1,97 @@
+package id_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/grokify/oscompat/id"
+)
+
+var allEncodings = []id.Encoding{id.Hex, id.Base32Lower, id.Base58, id.Base62}
+
+func TestGenerateBytes(t *testing.T) {
+	for _, n := range []int{0, 1, 8, 16, 32} {
+		if got := id.GenerateBytes(n); len(got) != n {
+			t.Errorf("GenerateBytes(%d) returned length %d", n, len(got))
+		}
+	}
+}
+
+func TestEncodingRoundTrip(t *testing.T) {
+	inputs := [][]byte{
+		{},
+		{0},
+		{0, 0, 1},
+		{0xff},
+		{0x00, 0xff, 0x10, 0x00},
+		bytes.Repeat([]byte{0xff}, 32),
+		id.GenerateBytes(16),
+	}
+
+	for _, enc := range allEncodings {
+		t.Run(enc.String(), func(t *testing.T) {
+			for _, in := range inputs {
+				s := enc.Encode(in)
+				got, err := enc.Decode(s)
+				if err != nil {
+					t.Fatalf("Decode(%q) error: %v", s, err)
+				}
+				if !bytes.Equal(got, in) {
+					t.Errorf("round trip of %x via %q = %x", in, s, got)
+				}
+			}
+		})
+	}
+}
+
+func TestEncodingKnownValues(t *testing.T) {
+	tests := []struct {
+		enc  id.Encoding
+		in   []byte
+		want string
+	}{
+		{id.Hex, []byte("hello"), "68656c6c6f"},
+		{id.Base32Lower, []byte("hello"), "nbswy3dp"},
+		{id.Base58, []byte("hello world"), "StV1DL6CwTryKyV"},
+		{id.Base58, []byte{0, 0, 0x28, 0x7f, 0xb4, 0xcd}, "11233QC4"},
+		{id.Base62, []byte{0xff}, "47"},
+		{id.Base62, []byte{0, 61}, "0z"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.enc.Encode(tt.in); got != tt.want {
+			t.Errorf("%v.Encode(%x) = %q, want %q", tt.enc, tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestEncodingDecodeInvalid(t *testing.T) {
+	tests := []struct {
+		enc id.Encoding
+		in  string
+	}{
+		{id.Hex, "zz"},
+		{id.Hex, "ABCD"},
+		{id.Base32Lower, "NBSWY3DP"},
+		{id.Base58, "0OIl"},
+		{id.Base62, "abc-"},
+		{id.Encoding(99), "abc"},
+	}
+
+	for _, tt := range tests {
+		if _, err := tt.enc.Decode(tt.in); err != id.ErrInvalidEncoding {
+			t.Errorf("%v.Decode(%q) = %v, want ErrInvalidEncoding", tt.enc, tt.in, err)
+		}
+	}
+}
+
+func TestGenerateEncoded(t *testing.T) {
+	if got := id.GenerateEncoded(10, id.Base32Lower); len(got) != 16 {
+		t.Errorf("GenerateEncoded(10, Base32Lower) returned length %d, want 16", len(got))
+	}
+
+	// Base62 of 16 bytes is at most 22 characters.
+	if got := id.GenerateEncoded(16, id.Base62); len(got) > 22 {
+		t.Errorf("GenerateEncoded(16, Base62) returned length %d, want <= 22", len(got))
+	}
+}
@@ -8,6 +8,8 @@ package id
 import (
 	"crypto/rand"
 	"encoding/hex"
+	"fmt"
+	"io"
 )
 
 // Generate returns a cryptographically random ID encoded as a hex string.
@@ -22,11 +24,7 @@ import (
 //	id.Generate(8)  // returns 16-character hex string like "a1b2c3d4e5f67890"
 //	id.Generate(16) // returns 32-character hex string
 func Generate(byteLen int) string {
-	b := make([]byte, byteLen)
-	if _, err := rand.Read(b); err != nil {
-		panic("oscompat/id: crypto/rand failed: " + err.Error())
-	}
-	return hex.EncodeToString(b)
+	return hex.EncodeToString(GenerateBytes(byteLen))
 }
 
 // Generate16 returns a 16-character hex string (8 random bytes).
@@ -42,3 +40,76 @@ func Generate16() string {
 func Generate32() string {
 	return Generate(16)
 }
+
+// GenerateE is like Generate but returns an error instead of panicking if
+// the entropy source fails. Use it in library code that cannot tolerate a
+// panic, such as in FIPS-constrained or unusual environments where
+// crypto/rand.Reader has been replaced.
+func GenerateE(byteLen int) (string, error) {
+	b := make([]byte, byteLen)
+	if err := readRandomE(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Generate16E is like Generate16 but returns an error instead of panicking.
+func Generate16E() (string, error) {
+	return GenerateE(8)
+}
+
+// Generate32E is like Generate32 but returns an error instead of panicking.
+func Generate32E() (string, error) {
+	return GenerateE(16)
+}
+
+// MustGenerate is an alias for Generate that makes the panic on entropy
+// failure explicit at the call site.
+func MustGenerate(byteLen int) string {
+	return Generate(byteLen)
+}
+
+// GenerateN returns count cryptographically random hex IDs of byteLen
+// bytes each. All randomness is read from crypto/rand in a single call and
+// sliced, which is much cheaper than calling Generate in a loop when
+// generating thousands of IDs.
+//
+// This function panics if crypto/rand fails.
+func GenerateN(count, byteLen int) []string {
+	if count <= 0 {
+		return []string{}
+	}
+	buf := make([]byte, count*byteLen)
+	readRandom(buf)
+
+	ids := make([]string, count)
+	for i := range ids {
+		ids[i] = hex.EncodeToString(buf[i*byteLen : (i+1)*byteLen])
+	}
+	return ids
+}
+
+// readRandom fills b from crypto/rand, panicking on failure.
+func readRandom(b []byte) {
+	if err := readRandomE(b); err != nil {
+		panic(err.Error())
+	}
+}
+
+// readRandomE fills b from crypto/rand.Reader.
+// If EnableBuffering is in effect, b is filled from a pooled buffered reader.
+//
+// crypto/rand.Read is not used because since Go 1.24 it crashes the program
+// instead of returning an error when a replaced Reader fails.
+func readRandomE(b []byte) error {
+	var err error
+	if buffered.Load() {
+		err = readBuffered(b)
+	} else {
+		_, err = io.ReadFull(rand.Reader, b)
+	}
+	if err != nil {
+		return fmt.Errorf("oscompat/id: crypto/rand failed: %w", err)
+	}
+	return nil
+}
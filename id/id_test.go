@@ -1,6 +1,8 @@
 package id_test
 
 import (
+	"crypto/rand"
+	"errors"
 	"sync"
 	"testing"
 
@@ -108,6 +110,97 @@ func TestGenerateHexFormat(t *testing.T) {
 	}
 }
 
+func TestGenerateN(t *testing.T) {
+	ids := id.GenerateN(1000, 8)
+	if len(ids) != 1000 {
+		t.Fatalf("GenerateN(1000, 8) returned %d IDs, want 1000", len(ids))
+	}
+
+	seen := make(map[string]struct{}, len(ids))
+	for _, s := range ids {
+		if len(s) != 16 {
+			t.Errorf("GenerateN ID %q has length %d, want 16", s, len(s))
+		}
+		if _, exists := seen[s]; exists {
+			t.Fatalf("GenerateN produced duplicate ID: %s", s)
+		}
+		seen[s] = struct{}{}
+	}
+}
+
+func TestGenerateNEmpty(t *testing.T) {
+	if got := id.GenerateN(0, 8); len(got) != 0 {
+		t.Errorf("GenerateN(0, 8) returned %d IDs, want 0", len(got))
+	}
+	if got := id.GenerateN(-1, 8); len(got) != 0 {
+		t.Errorf("GenerateN(-1, 8) returned %d IDs, want 0", len(got))
+	}
+}
+
+// failingReader is an entropy source that always fails.
+type failingReader struct{}
+
+var errNoEntropy = errors.New("no entropy")
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errNoEntropy
+}
+
+// withFailingRand replaces crypto/rand.Reader for the duration of a test.
+func withFailingRand(t *testing.T) {
+	t.Helper()
+	orig := rand.Reader
+	rand.Reader = failingReader{}
+	t.Cleanup(func() { rand.Reader = orig })
+}
+
+func TestGenerateE(t *testing.T) {
+	tests := []struct {
+		name    string
+		fn      func() (string, error)
+		wantLen int
+	}{
+		{"GenerateE(4)", func() (string, error) { return id.GenerateE(4) }, 8},
+		{"Generate16E", id.Generate16E, 16},
+		{"Generate32E", id.Generate32E, 32},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.fn()
+			if err != nil {
+				t.Fatalf("%s error: %v", tt.name, err)
+			}
+			if len(got) != tt.wantLen {
+				t.Errorf("%s returned length %d, want %d", tt.name, len(got), tt.wantLen)
+			}
+		})
+	}
+}
+
+func TestGenerateEFailure(t *testing.T) {
+	withFailingRand(t)
+
+	got, err := id.Generate16E()
+	if !errors.Is(err, errNoEntropy) {
+		t.Errorf("Generate16E() error = %v, want %v", err, errNoEntropy)
+	}
+	if got != "" {
+		t.Errorf("Generate16E() = %q on failure, want empty", got)
+	}
+}
+
+func TestMustGeneratePanics(t *testing.T) {
+	withFailingRand(t)
+
+	defer func() {
+		if recover() == nil {
+			t.Error("MustGenerate did not panic on entropy failure")
+		}
+	}()
+	id.MustGenerate(8)
+}
+
 func BenchmarkGenerate8(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		id.Generate(8)
@@ -125,3 +218,17 @@ func BenchmarkGenerate32(b *testing.B) {
 		id.Generate32()
 	}
 }
+
+func BenchmarkGenerateN1000(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		id.GenerateN(1000, 8)
+	}
+}
+
+func BenchmarkGenerate8Loop1000(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < 1000; j++ {
+			id.Generate(8)
+		}
+	}
+}
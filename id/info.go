@@ -0,0 +1,126 @@
+package id
+
+import (
+	"encoding/binary"
+	"time"
+)
+
+// Details describes an identifier as reported by Info.
+type Details struct {
+	// Prefix is the prefix of a prefixed ID (see WithPrefix), or "".
+	Prefix string
+
+	// Encoding is the encoding of the random payload. UUIDs report Hex.
+	Encoding Encoding
+
+	// ByteLen is the number of decoded payload bytes.
+	ByteLen int
+
+	// UUIDVersion is the RFC 9562 version for UUIDs, or 0.
+	UUIDVersion int
+
+	// Time is the timestamp embedded in time-ordered formats (UUID versions
+	// 1, 6, and 7), or the zero time if the format has none.
+	Time time.Time
+}
+
+// Validate reports whether s is a hex ID of byteLen random bytes, as
+// produced by Generate(byteLen). It returns ErrInvalidID otherwise.
+//
+// Use Parser for prefixed IDs and Encoding.Decode for other encodings.
+func Validate(s string, byteLen int) error {
+	if byteLen <= 0 || len(s) != 2*byteLen {
+		return ErrInvalidID
+	}
+	if _, err := Hex.Decode(s); err != nil {
+		return ErrInvalidID
+	}
+	return nil
+}
+
+// Info inspects s and reports its format. It recognizes canonical UUIDs,
+// prefixed IDs, and payloads in any Encoding of this package. Because a
+// bare string can be valid in several encodings, the first match in the
+// order Hex, Base32Lower, Base58, Base62 is reported.
+//
+// IDs from a Sequencer are integers; decode them with SequenceParts.
+//
+// Info returns ErrInvalidID if s is not recognized.
+func Info(s string) (Details, error) {
+	if u, err := parseUUID(s); err == nil {
+		return uuidDetails(u), nil
+	}
+
+	var d Details
+	payload := s
+	if prefix, rest, ok := cutPrefix(s); ok {
+		d.Prefix = prefix
+		payload = rest
+	}
+	if payload == "" {
+		return Details{}, ErrInvalidID
+	}
+
+	for _, enc := range []Encoding{Hex, Base32Lower, Base58, Base62} {
+		if enc == Hex && len(payload)%2 != 0 {
+			continue
+		}
+		b, err := enc.Decode(payload)
+		if err != nil || len(b) == 0 {
+			continue
+		}
+		d.Encoding = enc
+		d.ByteLen = len(b)
+		return d, nil
+	}
+	return Details{}, ErrInvalidID
+}
+
+// cutPrefix splits a prefixed ID if s starts with a valid prefix.
+func cutPrefix(s string) (prefix, rest string, ok bool) {
+	for i := 0; i < len(s) && i <= MaxPrefixLen; i++ {
+		if s[i] == Separator[0] {
+			if ValidatePrefix(s[:i]) != nil {
+				return "", "", false
+			}
+			return s[:i], s[i+1:], true
+		}
+	}
+	return "", "", false
+}
+
+// uuidDetails reports the version and embedded time of a parsed UUID.
+func uuidDetails(u []byte) Details {
+	d := Details{Encoding: Hex, ByteLen: len(u), UUIDVersion: int(u[6] >> 4)}
+
+	switch d.UUIDVersion {
+	case 1:
+		// 60-bit count of 100ns intervals since 1582-10-15, split as
+		// time_low, time_mid, time_hi (low 12 bits).
+		ts := uint64(binary.BigEndian.Uint16(u[6:8])&0x0fff)<<48 |
+			uint64(binary.BigEndian.Uint16(u[4:6]))<<32 |
+			uint64(binary.BigEndian.Uint32(u[0:4]))
+		d.Time = gregorianTime(ts)
+	case 6:
+		// Same 60-bit timestamp as version 1, stored most significant first.
+		ts := uint64(binary.BigEndian.Uint32(u[0:4]))<<28 |
+			uint64(binary.BigEndian.Uint16(u[4:6]))<<12 |
+			uint64(binary.BigEndian.Uint16(u[6:8])&0x0fff)
+		d.Time = gregorianTime(ts)
+	case 7:
+		// 48-bit Unix timestamp in milliseconds.
+		ms := int64(binary.BigEndian.Uint64(u[0:8]) >> 16)
+		d.Time = time.UnixMilli(ms).UTC()
+	}
+	return d
+}
+
+// gregorianTime converts 100ns intervals since the Gregorian reform
+// (the UUID v1/v6 epoch) to a time.Time.
+func gregorianTime(ts uint64) time.Time {
+	// Seconds between 1582-10-15 and 1970-01-01.
+	const gregorianToUnix = 12219292800
+	sec := int64(ts/10_000_000) - gregorianToUnix
+	nsec := int64(ts%10_000_000) * 100
+	return time.Unix(sec, nsec).UTC()
+}
@@ -0,0 +1,91 @@
+package id_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/grokify/oscompat/id"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		byteLen int
+		wantErr error
+	}{
+		{"generated", id.Generate(8), 8, nil},
+		{"wrong length", id.Generate(8), 16, id.ErrInvalidID},
+		{"uppercase", "A1B2C3D4E5F67890", 8, id.ErrInvalidID},
+		{"non-hex", "zzzzzzzzzzzzzzzz", 8, id.ErrInvalidID},
+		{"empty", "", 8, id.ErrInvalidID},
+		{"zero length", "", 0, id.ErrInvalidID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := id.Validate(tt.s, tt.byteLen); err != tt.wantErr {
+				t.Errorf("Validate(%q, %d) = %v, want %v", tt.s, tt.byteLen, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestInfo(t *testing.T) {
+	tests := []struct {
+		name        string
+		s           string
+		wantPrefix  string
+		wantEnc     id.Encoding
+		wantByteLen int
+		wantVersion int
+		wantTime    time.Time
+	}{
+		{"hex", "a1b2c3d4e5f67890", "", id.Hex, 8, 0, time.Time{}},
+		{"base32", "nbswy3dp", "", id.Base32Lower, 5, 0, time.Time{}},
+		{"base58", "StV1DL6CwTryKyV", "", id.Base58, 11, 0, time.Time{}},
+		{"base62 only", "0AbC", "", id.Base62, 3, 0, time.Time{}},
+		{"prefixed", "req_a1b2c3d4", "req", id.Hex, 4, 0, time.Time{}},
+		{"uuid v4", "f47ac10b-58cc-4372-a567-0e02b2c3d479", "", id.Hex, 16, 4, time.Time{}},
+		{"uuid v5", "2ed6657d-e927-568b-95e1-2665a8aea6a2", "", id.Hex, 16, 5, time.Time{}},
+		// Example timestamps from RFC 9562 Appendix A.
+		{"uuid v1", "c232ab00-9414-11ec-b3c8-9f6bdeced846", "", id.Hex, 16, 1,
+			time.Date(2022, 2, 22, 19, 22, 22, 0, time.UTC)},
+		{"uuid v6", "1ec9414c-232a-6b00-b3c8-9f6bdeced846", "", id.Hex, 16, 6,
+			time.Date(2022, 2, 22, 19, 22, 22, 0, time.UTC)},
+		{"uuid v7", "017f22e2-79b0-7cc3-98c4-dc0c0c07398f", "", id.Hex, 16, 7,
+			time.Date(2022, 2, 22, 19, 22, 22, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := id.Info(tt.s)
+			if err != nil {
+				t.Fatalf("Info(%q) error: %v", tt.s, err)
+			}
+			if d.Prefix != tt.wantPrefix {
+				t.Errorf("Info(%q).Prefix = %q, want %q", tt.s, d.Prefix, tt.wantPrefix)
+			}
+			if d.Encoding != tt.wantEnc {
+				t.Errorf("Info(%q).Encoding = %v, want %v", tt.s, d.Encoding, tt.wantEnc)
+			}
+			if d.ByteLen != tt.wantByteLen {
+				t.Errorf("Info(%q).ByteLen = %d, want %d", tt.s, d.ByteLen, tt.wantByteLen)
+			}
+			if d.UUIDVersion != tt.wantVersion {
+				t.Errorf("Info(%q).UUIDVersion = %d, want %d", tt.s, d.UUIDVersion, tt.wantVersion)
+			}
+			if !d.Time.Equal(tt.wantTime) {
+				t.Errorf("Info(%q).Time = %v, want %v", tt.s, d.Time, tt.wantTime)
+			}
+		})
+	}
+}
+
+func TestInfoInvalid(t *testing.T) {
+	for _, s := range []string{"", "req_", "!!!", "has space"} {
+		if _, err := id.Info(s); err != id.ErrInvalidID {
+			t.Errorf("Info(%q) = %v, want ErrInvalidID", s, err)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package id
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"sync"
+)
+
+// ErrMachineIDNotFound is returned when the platform's machine identifier
+// cannot be read or the platform has none.
+var ErrMachineIDNotFound = errors.New("oscompat/id: machine id not found")
+
+// machineID reads the platform machine ID once per process.
+var machineID = sync.OnceValues(func() (string, error) {
+	raw, err := readMachineID()
+	if err != nil {
+		return "", err
+	}
+	raw = strings.ToLower(strings.TrimSpace(raw))
+	if raw == "" {
+		return "", ErrMachineIDNotFound
+	}
+	return raw, nil
+})
+
+// MachineID returns a stable identifier for the current host, as recorded
+// by the operating system. The value survives reboots but typically changes
+// when the OS is reinstalled.
+//
+// Platform sources:
+//   - Linux: /etc/machine-id (or /var/lib/dbus/machine-id)
+//   - macOS: IOPlatformUUID from the IOPlatformExpertDevice registry entry
+//   - Windows: MachineGuid under HKLM\SOFTWARE\Microsoft\Cryptography
+//   - FreeBSD: kern.hostuuid
+//
+// The raw machine ID should be treated as confidential: it lets any two
+// applications correlate the same host. Prefer MachineIDFor when the ID
+// leaves the machine (telemetry, licensing).
+func MachineID() (string, error) {
+	return machineID()
+}
+
+// MachineIDFor returns a 32-character hex identifier derived from the
+// machine ID and appName using HMAC-SHA256. It is stable for a given host
+// and app, but IDs for different apps cannot be correlated with each other
+// or with the raw machine ID.
+func MachineIDFor(appName string) (string, error) {
+	raw, err := MachineID()
+	if err != nil {
+		return "", err
+	}
+	mac := hmac.New(sha256.New, []byte(raw))
+	mac.Write([]byte(appName))
+	return hex.EncodeToString(mac.Sum(nil)[:16]), nil
+}
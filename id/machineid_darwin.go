@@ -0,0 +1,35 @@
+//go:build darwin
+
+package id
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// readMachineID reads IOPlatformUUID via ioreg, which avoids a cgo
+// dependency on IOKit.
+func readMachineID() (string, error) {
+	out, err := exec.Command("ioreg", "-rd1", "-c", "IOPlatformExpertDevice").Output()
+	if err != nil {
+		return "", ErrMachineIDNotFound
+	}
+	return parseIOPlatformUUID(string(out))
+}
+
+// parseIOPlatformUUID extracts the value from a line like:
+//
+//	"IOPlatformUUID" = "01234567-89AB-CDEF-0123-456789ABCDEF"
+func parseIOPlatformUUID(out string) (string, error) {
+	for _, line := range strings.Split(out, "\n") {
+		if !strings.Contains(line, `"IOPlatformUUID"`) {
+			continue
+		}
+		_, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		return strings.Trim(strings.TrimSpace(value), `"`), nil
+	}
+	return "", ErrMachineIDNotFound
+}
@@ -0,0 +1,14 @@
+//go:build freebsd
+
+package id
+
+import "syscall"
+
+// readMachineID reads the host UUID maintained by the kernel.
+func readMachineID() (string, error) {
+	uuid, err := syscall.Sysctl("kern.hostuuid")
+	if err != nil {
+		return "", ErrMachineIDNotFound
+	}
+	return uuid, nil
+}
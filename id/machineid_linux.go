@@ -0,0 +1,15 @@
+//go:build linux
+
+package id
+
+import "os"
+
+// readMachineID reads the systemd/D-Bus machine ID.
+func readMachineID() (string, error) {
+	for _, path := range []string{"/etc/machine-id", "/var/lib/dbus/machine-id"} {
+		if data, err := os.ReadFile(path); err == nil && len(data) > 0 {
+			return string(data), nil
+		}
+	}
+	return "", ErrMachineIDNotFound
+}
@@ -0,0 +1,8 @@
+//go:build !darwin && !windows && !linux && !freebsd
+
+package id
+
+// readMachineID reports that no machine ID is available on this platform.
+func readMachineID() (string, error) {
+	return "", ErrMachineIDNotFound
+}
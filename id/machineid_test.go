@@ -0,0 +1,52 @@
+package id_test
+
+import (
+	"testing"
+
+	"github.com/grokify/oscompat/id"
+)
+
+func TestMachineID(t *testing.T) {
+	mid, err := id.MachineID()
+	if err == id.ErrMachineIDNotFound {
+		t.Skip("machine ID not available in this environment")
+	}
+	if err != nil {
+		t.Fatalf("MachineID() error: %v", err)
+	}
+	if mid == "" {
+		t.Error("MachineID() returned empty string")
+	}
+
+	again, err := id.MachineID()
+	if err != nil || again != mid {
+		t.Errorf("MachineID() not stable: %q then %q (%v)", mid, again, err)
+	}
+}
+
+func TestMachineIDFor(t *testing.T) {
+	if _, err := id.MachineID(); err == id.ErrMachineIDNotFound {
+		t.Skip("machine ID not available in this environment")
+	}
+
+	a1, err := id.MachineIDFor("app-a")
+	if err != nil {
+		t.Fatalf("MachineIDFor() error: %v", err)
+	}
+	a2, _ := id.MachineIDFor("app-a")
+	b, _ := id.MachineIDFor("app-b")
+	mid, _ := id.MachineID()
+
+	if len(a1) != 32 {
+		t.Errorf("MachineIDFor() returned length %d, want 32", len(a1))
+	}
+	if a1 != a2 {
+		t.Errorf("MachineIDFor() not stable: %q vs %q", a1, a2)
+	}
+	if a1 == b {
+		t.Error("MachineIDFor() returned same ID for different apps")
+	}
+	if a1 == mid {
+		t.Error("MachineIDFor() leaked the raw machine ID")
+	}
+}
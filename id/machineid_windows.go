@@ -0,0 +1,50 @@
+//go:build windows
+
+package id
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// keyWow64_64Key forces the 64-bit registry view so 32-bit processes read
+// the same MachineGuid as 64-bit ones.
+const keyWow64_64Key = 0x0100
+
+// readMachineID reads MachineGuid from the registry.
+func readMachineID() (string, error) {
+	return readRegistryString(`SOFTWARE\Microsoft\Cryptography`, "MachineGuid")
+}
+
+// readRegistryString reads a REG_SZ value from HKEY_LOCAL_MACHINE.
+func readRegistryString(path, name string) (string, error) {
+	pathPtr, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return "", err
+	}
+	namePtr, err := syscall.UTF16PtrFromString(name)
+	if err != nil {
+		return "", err
+	}
+
+	var key syscall.Handle
+	if err := syscall.RegOpenKeyEx(syscall.HKEY_LOCAL_MACHINE, pathPtr, 0,
+		syscall.KEY_READ|keyWow64_64Key, &key); err != nil {
+		return "", ErrMachineIDNotFound
+	}
+	defer func() { _ = syscall.RegCloseKey(key) }()
+
+	var valType, size uint32
+	if err := syscall.RegQueryValueEx(key, namePtr, nil, &valType, nil, &size); err != nil || size == 0 {
+		return "", ErrMachineIDNotFound
+	}
+	if valType != syscall.REG_SZ {
+		return "", ErrMachineIDNotFound
+	}
+	buf := make([]uint16, size/2)
+	if err := syscall.RegQueryValueEx(key, namePtr, nil, &valType,
+		(*byte)(unsafe.Pointer(&buf[0])), &size); err != nil {
+		return "", ErrMachineIDNotFound
+	}
+	return syscall.UTF16ToString(buf), nil
+}
@@ -0,0 +1,130 @@
+package id
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// Separator separates the prefix from the random payload in prefixed IDs.
+const Separator = "_"
+
+// MaxPrefixLen is the maximum length of an ID prefix.
+const MaxPrefixLen = 32
+
+// Errors returned when generating or parsing prefixed IDs.
+var (
+	// ErrInvalidPrefix is returned when a prefix does not follow the prefix rules.
+	ErrInvalidPrefix = errors.New("oscompat/id: invalid prefix")
+
+	// ErrInvalidID is returned when an ID is malformed or has the wrong
+	// prefix or length.
+	ErrInvalidID = errors.New("oscompat/id: invalid id")
+)
+
+// ValidatePrefix reports whether prefix can be used with WithPrefix.
+// A prefix must be 1 to MaxPrefixLen characters, start with a lowercase
+// ASCII letter, and contain only lowercase ASCII letters and digits.
+// This keeps prefixed IDs safe in URLs, filenames, and DNS labels and
+// guarantees the Separator unambiguously ends the prefix.
+func ValidatePrefix(prefix string) error {
+	if prefix == "" || len(prefix) > MaxPrefixLen {
+		return ErrInvalidPrefix
+	}
+	for i := 0; i < len(prefix); i++ {
+		c := prefix[i]
+		isLower := c >= 'a' && c <= 'z'
+		isDigit := c >= '0' && c <= '9'
+		if !isLower && !(isDigit && i > 0) {
+			return ErrInvalidPrefix
+		}
+	}
+	return nil
+}
+
+// WithPrefix returns a Stripe-style identifier consisting of prefix,
+// Separator, and byteLen random bytes encoded as hex.
+//
+// This function panics if the prefix is invalid (see ValidatePrefix) or if
+// crypto/rand fails. Prefixes are normally constants, so an invalid prefix
+// is a programming error.
+//
+// Example:
+//
+//	id.WithPrefix("req", 8) // returns "req_a1b2c3d4e5f67890"
+func WithPrefix(prefix string, byteLen int) string {
+	if err := ValidatePrefix(prefix); err != nil {
+		panic(err.Error() + ": " + prefix)
+	}
+	return prefix + Separator + Generate(byteLen)
+}
+
+// Parser validates and decodes prefixed identifiers.
+// The zero value accepts any valid prefix and any payload length.
+type Parser struct {
+	// Prefix is the required prefix. If empty, any valid prefix is accepted.
+	Prefix string
+
+	// ByteLen is the required number of decoded payload bytes.
+	// If zero, any non-empty payload is accepted.
+	ByteLen int
+}
+
+// Parse validates s and returns its prefix and decoded payload bytes.
+// The payload may be lowercase hex (as produced by WithPrefix) or lowercase
+// unpadded base32. All failures return ErrInvalidID, except a malformed
+// prefix, which returns ErrInvalidPrefix.
+func (p Parser) Parse(s string) (prefix string, payload []byte, err error) {
+	prefix, encoded, ok := strings.Cut(s, Separator)
+	if !ok || encoded == "" {
+		return "", nil, ErrInvalidID
+	}
+	if err := ValidatePrefix(prefix); err != nil {
+		return "", nil, err
+	}
+	if p.Prefix != "" && prefix != p.Prefix {
+		return "", nil, ErrInvalidID
+	}
+
+	payload, err = decodePayload(encoded, p.ByteLen)
+	if err != nil {
+		return "", nil, err
+	}
+	return prefix, payload, nil
+}
+
+// Validate reports whether s is a valid identifier for this parser.
+func (p Parser) Validate(s string) error {
+	_, _, err := p.Parse(s)
+	return err
+}
+
+// decodePayload decodes a hex or base32 payload. When byteLen is known, the
+// encoded length selects the encoding; otherwise hex is preferred.
+func decodePayload(encoded string, byteLen int) ([]byte, error) {
+	hexLen := hex.EncodedLen(byteLen)
+	b32Len := base32Lower.EncodedLen(byteLen)
+
+	tryHex := byteLen == 0 || len(encoded) == hexLen
+	tryB32 := byteLen == 0 || len(encoded) == b32Len
+
+	if tryHex {
+		if b, err := Hex.Decode(encoded); err == nil {
+			return checkLen(b, byteLen)
+		}
+	}
+	if tryB32 {
+		if b, err := Base32Lower.Decode(encoded); err == nil {
+			return checkLen(b, byteLen)
+		}
+	}
+	return nil, ErrInvalidID
+}
+
+// checkLen verifies the decoded payload length.
+func checkLen(b []byte, byteLen int) ([]byte, error) {
+	if len(b) == 0 || (byteLen > 0 && len(b) != byteLen) {
+		return nil, ErrInvalidID
+	}
+	return b, nil
+}
@@ -0,0 +1,94 @@
+package id_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grokify/oscompat/id"
+)
+
+func TestWithPrefix(t *testing.T) {
+	got := id.WithPrefix("req", 8)
+	if !strings.HasPrefix(got, "req_") {
+		t.Errorf("WithPrefix() = %q, want prefix %q", got, "req_")
+	}
+	if len(got) != len("req_")+16 {
+		t.Errorf("WithPrefix() returned length %d, want %d", len(got), len("req_")+16)
+	}
+
+	p := id.Parser{Prefix: "req", ByteLen: 8}
+	if err := p.Validate(got); err != nil {
+		t.Errorf("Validate(%q) = %v, want nil", got, err)
+	}
+}
+
+func TestWithPrefixInvalidPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("WithPrefix with invalid prefix did not panic")
+		}
+	}()
+	id.WithPrefix("Bad-Prefix", 8)
+}
+
+func TestValidatePrefix(t *testing.T) {
+	tests := []struct {
+		prefix  string
+		wantErr error
+	}{
+		{"req", nil},
+		{"cus", nil},
+		{"v2key", nil},
+		{"", id.ErrInvalidPrefix},
+		{"2fa", id.ErrInvalidPrefix},
+		{"Req", id.ErrInvalidPrefix},
+		{"req_x", id.ErrInvalidPrefix},
+		{"req-x", id.ErrInvalidPrefix},
+		{strings.Repeat("a", id.MaxPrefixLen+1), id.ErrInvalidPrefix},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.prefix, func(t *testing.T) {
+			if err := id.ValidatePrefix(tt.prefix); err != tt.wantErr {
+				t.Errorf("ValidatePrefix(%q) = %v, want %v", tt.prefix, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParserParse(t *testing.T) {
+	tests := []struct {
+		name        string
+		parser      id.Parser
+		input       string
+		wantPrefix  string
+		wantPayload int
+		wantErr     error
+	}{
+		{"hex payload", id.Parser{Prefix: "req", ByteLen: 4}, "req_a1b2c3d4", "req", 4, nil},
+		{"base32 payload", id.Parser{Prefix: "req", ByteLen: 5}, "req_mfrggzdf", "req", 5, nil},
+		{"any prefix", id.Parser{}, "cus_00ff", "cus", 2, nil},
+		{"wrong prefix", id.Parser{Prefix: "req"}, "cus_a1b2c3d4", "", 0, id.ErrInvalidID},
+		{"wrong length", id.Parser{Prefix: "req", ByteLen: 8}, "req_a1b2c3d4", "", 0, id.ErrInvalidID},
+		{"missing separator", id.Parser{}, "reqa1b2c3d4", "", 0, id.ErrInvalidID},
+		{"empty payload", id.Parser{}, "req_", "", 0, id.ErrInvalidID},
+		{"uppercase hex", id.Parser{ByteLen: 4}, "req_A1B2C3D4", "", 0, id.ErrInvalidID},
+		{"bad characters", id.Parser{}, "req_!!!!", "", 0, id.ErrInvalidID},
+		{"bad prefix", id.Parser{}, "Req_a1b2", "", 0, id.ErrInvalidPrefix},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prefix, payload, err := tt.parser.Parse(tt.input)
+			if err != tt.wantErr {
+				t.Fatalf("Parse(%q) error = %v, want %v", tt.input, err, tt.wantErr)
+			}
+			if prefix != tt.wantPrefix {
+				t.Errorf("Parse(%q) prefix = %q, want %q", tt.input, prefix, tt.wantPrefix)
+			}
+			if len(payload) != tt.wantPayload {
+				t.Errorf("Parse(%q) payload length = %d, want %d", tt.input, len(payload), tt.wantPayload)
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package id
+
+import "strings"
+
+// crockford is Douglas Crockford's base32 alphabet, which leaves out I,
+// L, O, and U so that codes cannot be misread as 1 or 0, or spell words.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// readableGroup is the number of characters between dashes.
+const readableGroup = 4
+
+// Readable returns a random code of the given number of dash-separated
+// groups of four Crockford base32 characters, such as "F7Q2-9KTD-3M1Z",
+// for pairing and activation codes that users read aloud or type. Each
+// group carries 20 bits of randomness, so three groups give 60 bits.
+// groups is at least 1.
+//
+// Compare codes entered by users with ParseReadable, which forgives case,
+// missing dashes, and the letters O, I, and L typed for 0 and 1.
+//
+// This function panics if crypto/rand fails.
+func Readable(groups int) string {
+	groups = max(groups, 1)
+	b := GenerateBytes(groups * readableGroup)
+	var sb strings.Builder
+	for i, c := range b {
+		if i > 0 && i%readableGroup == 0 {
+			sb.WriteByte('-')
+		}
+		// 256 is a multiple of 32, so the low bits are unbiased.
+		sb.WriteByte(crockford[c&31])
+	}
+	return sb.String()
+}
+
+// ParseReadable returns the canonical form of a code produced by
+// Readable as typed by a user: upper case, with O read as 0, I and L as
+// 1, and spaces and dashes regrouped. It returns ErrInvalidEncoding for
+// any other character, or if the code does not divide into whole groups.
+func ParseReadable(s string) (string, error) {
+	var chars []byte
+	for _, r := range strings.ToUpper(s) {
+		switch {
+		case r == '-' || r == ' ':
+			continue
+		case r == 'O':
+			r = '0'
+		case r == 'I' || r == 'L':
+			r = '1'
+		case r > 0x7f || !strings.ContainsRune(crockford, r):
+			return "", ErrInvalidEncoding
+		}
+		chars = append(chars, byte(r))
+	}
+	if len(chars) == 0 || len(chars)%readableGroup != 0 {
+		return "", ErrInvalidEncoding
+	}
+	var sb strings.Builder
+	for i, c := range chars {
+		if i > 0 && i%readableGroup == 0 {
+			sb.WriteByte('-')
+		}
+		sb.WriteByte(c)
+	}
+	return sb.String(), nil
+}
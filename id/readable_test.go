@@ -0,0 +1,49 @@
+package id_test
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+
+	"github.com/grokify/oscompat/id"
+)
+
+func TestReadable(t *testing.T) {
+	format := regexp.MustCompile(`^[0-9A-HJKMNP-TV-Z]{4}(-[0-9A-HJKMNP-TV-Z]{4}){2}$`)
+	seen := map[string]bool{}
+	for range 100 {
+		code := id.Readable(3)
+		if !format.MatchString(code) {
+			t.Fatalf("Readable(3) = %q, want XXXX-XXXX-XXXX in Crockford base32", code)
+		}
+		if seen[code] {
+			t.Fatalf("Readable(3) repeated %q", code)
+		}
+		seen[code] = true
+		if got, err := id.ParseReadable(code); err != nil || got != code {
+			t.Errorf("ParseReadable(%q) = %q, %v", code, got, err)
+		}
+	}
+	if got := id.Readable(0); len(got) != 4 {
+		t.Errorf("Readable(0) = %q, want one group", got)
+	}
+}
+
+func TestParseReadable(t *testing.T) {
+	tests := map[string]string{
+		"f7q2-9ktd-3m1z":   "F7Q2-9KTD-3M1Z",
+		"F7Q29KTD3M1Z":     "F7Q2-9KTD-3M1Z",
+		" f7q2 9ktd 3mlz ": "F7Q2-9KTD-3M1Z",
+		"OIL0":             "0110",
+	}
+	for in, want := range tests {
+		if got, err := id.ParseReadable(in); err != nil || got != want {
+			t.Errorf("ParseReadable(%q) = %q, %v, want %q", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "ABC", "UUUU", "AB*D", "ÄBCD"} {
+		if _, err := id.ParseReadable(in); !errors.Is(err, id.ErrInvalidEncoding) {
+			t.Errorf("ParseReadable(%q) error = %v, want ErrInvalidEncoding", in, err)
+		}
+	}
+}
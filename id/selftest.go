@@ -0,0 +1,157 @@
+package id
+
+import (
+	"bytes"
+	"crypto/fips140"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+	"runtime"
+	"time"
+)
+
+// ErrSelfTest is returned by SelfTest when crypto/rand is unresponsive or
+// its output fails a sanity check.
+var ErrSelfTest = errors.New("oscompat/id: entropy self-test failed")
+
+// SelfTestTimeout is how long SelfTest waits for crypto/rand.
+const SelfTestTimeout = 5 * time.Second
+
+// selfTestBytes is the sample size of the statistical checks: the 20,000
+// bits of the FIPS 140-2 power-up tests.
+const selfTestBytes = 2500
+
+// SelfTestResult reports a passed SelfTest.
+type SelfTestResult struct {
+	// Source describes the random source; see Source.
+	Source string
+
+	// Duration is how long reading the samples took.
+	Duration time.Duration
+
+	// Ones is the number of set bits in the 20,000-bit sample.
+	Ones int
+}
+
+// SelfTest reads from crypto/rand, directly and not through the buffer
+// of EnableBuffering, and checks that the source responds within
+// SelfTestTimeout and that its output looks random: two samples differ,
+// and the first passes the monobit, poker, and long-run tests of FIPS
+// 140-2. A healthy source fails these tests with a probability of about
+// one in a million, so a failure is worth logging and retrying once
+// before refusing to start.
+//
+// Deployments that must record their entropy source can call it at
+// startup and log the result. A failed test returns an error wrapping
+// ErrSelfTest.
+func SelfTest() (SelfTestResult, error) {
+	res := SelfTestResult{Source: Source()}
+	start := time.Now()
+	done := make(chan error, 1)
+	a, b := make([]byte, selfTestBytes), make([]byte, selfTestBytes)
+	go func() {
+		_, err := io.ReadFull(rand.Reader, a)
+		if err == nil {
+			_, err = io.ReadFull(rand.Reader, b)
+		}
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			return res, fmt.Errorf("%w: %w", ErrSelfTest, err)
+		}
+	case <-time.After(SelfTestTimeout):
+		return res, fmt.Errorf("%w: no output from %s after %v", ErrSelfTest, res.Source, SelfTestTimeout)
+	}
+	res.Duration = time.Since(start)
+
+	if bytes.Equal(a, b) {
+		return res, fmt.Errorf("%w: repeated output", ErrSelfTest)
+	}
+	for _, c := range a {
+		res.Ones += bits.OnesCount8(c)
+	}
+	if res.Ones <= 9725 || res.Ones >= 10275 {
+		return res, fmt.Errorf("%w: monobit test: %d of 20000 bits set", ErrSelfTest, res.Ones)
+	}
+	if x := pokerStatistic(a); x <= 2.16 || x >= 46.17 {
+		return res, fmt.Errorf("%w: poker test: X = %.2f", ErrSelfTest, x)
+	}
+	if n := longestRun(a); n >= 26 {
+		return res, fmt.Errorf("%w: long run test: run of %d bits", ErrSelfTest, n)
+	}
+	return res, nil
+}
+
+// pokerStatistic returns the FIPS 140-2 poker test statistic of b: how
+// far the counts of its 4-bit values are from uniform.
+func pokerStatistic(b []byte) float64 {
+	var counts [16]int
+	for _, c := range b {
+		counts[c>>4]++
+		counts[c&0x0f]++
+	}
+	var sum int
+	for _, n := range counts {
+		sum += n * n
+	}
+	k := float64(2 * len(b))
+	return 16/k*float64(sum) - k
+}
+
+// longestRun returns the length of the longest run of equal bits in b.
+func longestRun(b []byte) int {
+	longest, run, last := 0, 0, -1
+	for _, c := range b {
+		for i := 7; i >= 0; i-- {
+			bit := int(c>>i) & 1
+			if bit == last {
+				run++
+			} else {
+				run, last = 1, bit
+			}
+			longest = max(longest, run)
+		}
+	}
+	return longest
+}
+
+// Source describes the operating system random source crypto/rand reads
+// on this platform, such as "getrandom(2)", "/dev/urandom",
+// "arc4random_buf(3)", or "ProcessPrng". Windows has used ProcessPrng,
+// the generator behind BCryptGenRandom, since Go 1.22. In FIPS 140-3
+// mode the output passes through a DRBG, and the description says so.
+func Source() string {
+	s := osRandSource()
+	if fips140.Enabled() {
+		s += " via FIPS 140-3 DRBG"
+	}
+	return s
+}
+
+// osRandSource returns the source crypto/rand documents for GOOS.
+func osRandSource() string {
+	switch runtime.GOOS {
+	case "linux":
+		if legacyKernel() {
+			return "/dev/urandom"
+		}
+		return "getrandom(2)"
+	case "freebsd", "dragonfly", "solaris", "illumos":
+		return "getrandom(2)"
+	case "darwin", "ios", "openbsd":
+		return "arc4random_buf(3)"
+	case "netbsd":
+		return "kern.arandom sysctl"
+	case "windows":
+		return "ProcessPrng"
+	case "js":
+		return "Web Crypto getRandomValues"
+	case "wasip1":
+		return "WASI random_get"
+	}
+	return "crypto/rand"
+}
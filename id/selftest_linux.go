@@ -0,0 +1,30 @@
+//go:build linux
+
+package id
+
+import (
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// legacyKernel reports whether the kernel predates getrandom(2), added
+// in Linux 3.17, so that crypto/rand falls back to /dev/urandom.
+func legacyKernel() bool {
+	var uts syscall.Utsname
+	if syscall.Uname(&uts) != nil {
+		return false
+	}
+	var release strings.Builder
+	for _, c := range uts.Release {
+		if c == 0 {
+			break
+		}
+		release.WriteByte(byte(c))
+	}
+	major, rest, _ := strings.Cut(release.String(), ".")
+	minor, _, _ := strings.Cut(rest, ".")
+	x, err1 := strconv.Atoi(major)
+	y, err2 := strconv.Atoi(minor)
+	return err1 == nil && err2 == nil && (x < 3 || x == 3 && y < 17)
+}
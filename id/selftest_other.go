@@ -0,0 +1,8 @@
+//go:build !linux
+
+package id
+
+// legacyKernel is Linux only.
+func legacyKernel() bool {
+	return false
+}
@@ -0,0 +1,23 @@
+package id_test
+
+import (
+	"testing"
+
+	"github.com/grokify/oscompat/id"
+)
+
+func TestSelfTest(t *testing.T) {
+	res, err := id.SelfTest()
+	if err != nil {
+		// A healthy source fails about once in a million runs.
+		if res, err = id.SelfTest(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if res.Source == "" || res.Source != id.Source() {
+		t.Errorf("Source = %q, want %q", res.Source, id.Source())
+	}
+	if res.Ones == 0 || res.Duration <= 0 {
+		t.Errorf("result = %+v", res)
+	}
+}
@@ -0,0 +1,143 @@
+package id
+
+import (
+	"errors"
+	"hash/fnv"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Bit layout of Sequencer IDs, most significant first:
+//
+//	1 bit unused (always 0, so IDs are positive int64 values)
+//	41 bits milliseconds since 2024-01-01 UTC (~69 years)
+//	10 bits node ID
+//	12 bits per-millisecond sequence
+const (
+	nodeBits     = 10
+	sequenceBits = 12
+
+	// MaxNodeID is the largest node ID a Sequencer accepts.
+	MaxNodeID = 1<<nodeBits - 1
+
+	maxSequence = 1<<sequenceBits - 1
+
+	// maxLead is how many milliseconds timestamps may run ahead of the
+	// clock when the sequence is exhausted, enough to cover one tick of a
+	// coarse clock.
+	maxLead = 16
+)
+
+// epoch is the reference time for Sequencer timestamps.
+var epoch = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// ErrInvalidNodeID is returned when a node ID is outside [0, MaxNodeID].
+var ErrInvalidNodeID = errors.New("oscompat/id: invalid node id")
+
+// Sequencer generates 64-bit, time-ordered, snowflake-style IDs.
+//
+// IDs from one Sequencer are strictly increasing. IDs from Sequencers with
+// different node IDs never collide. Up to 4096 IDs are issued per
+// millisecond per node; when the sequence is exhausted, Next moves on to the
+// next millisecond, running at most 16ms ahead of the clock and waiting for it
+// beyond that. This keeps IDs unique even where the system clock is coarse
+// (Windows historically ticks every ~15.6ms) and never steps the timestamp
+// backwards if the wall clock is adjusted.
+//
+// The zero value is ready to use and takes its node ID from DefaultNodeID.
+// A Sequencer is safe for concurrent use.
+type Sequencer struct {
+	mu      sync.Mutex
+	node    int64
+	nodeSet bool
+	last    int64 // milliseconds since epoch of the last ID
+	seq     int64
+}
+
+// NewSequencer returns a Sequencer with the given node ID. Each process
+// generating IDs concurrently should use a distinct node ID.
+func NewSequencer(nodeID int) (*Sequencer, error) {
+	if nodeID < 0 || nodeID > MaxNodeID {
+		return nil, ErrInvalidNodeID
+	}
+	return &Sequencer{node: int64(nodeID), nodeSet: true}, nil
+}
+
+// DefaultNodeID returns a node ID derived from a hash of the machine ID (or
+// hostname if unavailable) and the current process ID. Distinct processes
+// are likely but not guaranteed to get distinct node IDs; deployments with
+// many generators should assign node IDs explicitly.
+func DefaultNodeID() int {
+	host, err := MachineID()
+	if err != nil {
+		host, _ = os.Hostname()
+	}
+	h := fnv.New32a()
+	h.Write([]byte(host))
+	h.Write([]byte{0})
+	h.Write([]byte(strconv.Itoa(os.Getpid())))
+	return int(h.Sum32() % (MaxNodeID + 1))
+}
+
+// NodeID returns the node ID embedded in IDs from this Sequencer.
+func (s *Sequencer) NodeID() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.initNode()
+	return int(s.node)
+}
+
+// Next returns the next ID.
+func (s *Sequencer) Next() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.initNode()
+
+	now := sinceEpoch()
+	if now < s.last {
+		// The wall clock went backwards; keep issuing from the last timestamp.
+		now = s.last
+	}
+
+	if now == s.last {
+		s.seq = (s.seq + 1) & maxSequence
+		if s.seq == 0 {
+			// Sequence exhausted for this millisecond: move on to the next
+			// one, waiting for the clock once that would put IDs more than
+			// maxLead ahead of it.
+			now++
+			for now-sinceEpoch() > maxLead {
+				time.Sleep(time.Millisecond)
+			}
+		}
+	} else {
+		s.seq = 0
+	}
+	s.last = now
+
+	return now<<(nodeBits+sequenceBits) | s.node<<sequenceBits | s.seq
+}
+
+// initNode assigns the default node ID to a zero-value Sequencer.
+func (s *Sequencer) initNode() {
+	if !s.nodeSet {
+		s.node = int64(DefaultNodeID())
+		s.nodeSet = true
+	}
+}
+
+// SequenceParts decomposes an ID produced by a Sequencer into its
+// timestamp, node ID, and sequence number.
+func SequenceParts(v int64) (t time.Time, nodeID int, seq int) {
+	ms := v >> (nodeBits + sequenceBits)
+	nodeID = int(v>>sequenceBits) & MaxNodeID
+	seq = int(v & maxSequence)
+	return epoch.Add(time.Duration(ms) * time.Millisecond), nodeID, seq
+}
+
+// sinceEpoch returns the current time in milliseconds since epoch.
+func sinceEpoch() int64 {
+	return time.Since(epoch).Milliseconds()
+}
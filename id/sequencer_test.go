@@ -0,0 +1,131 @@
+package id_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grokify/oscompat/id"
+)
+
+func TestNewSequencerInvalidNode(t *testing.T) {
+	for _, node := range []int{-1, id.MaxNodeID + 1} {
+		if _, err := id.NewSequencer(node); err != id.ErrInvalidNodeID {
+			t.Errorf("NewSequencer(%d) = %v, want ErrInvalidNodeID", node, err)
+		}
+	}
+}
+
+func TestSequencerMonotonic(t *testing.T) {
+	seq, err := id.NewSequencer(42)
+	if err != nil {
+		t.Fatalf("NewSequencer() error: %v", err)
+	}
+
+	// Generate more than one millisecond's worth of sequence numbers to
+	// exercise rollover.
+	const count = 20000
+	prev := seq.Next()
+	for i := 1; i < count; i++ {
+		next := seq.Next()
+		if next <= prev {
+			t.Fatalf("Next() not increasing at %d: %d then %d", i, prev, next)
+		}
+		prev = next
+	}
+}
+
+func TestSequencerLead(t *testing.T) {
+	seq, err := id.NewSequencer(3)
+	if err != nil {
+		t.Fatalf("NewSequencer() error: %v", err)
+	}
+
+	// Exhaust the sequence for many milliseconds in a row; timestamps may
+	// run slightly ahead of the clock but must not drift without bound.
+	var v int64
+	for range 200000 {
+		v = seq.Next()
+	}
+	ts, _, _ := id.SequenceParts(v)
+	if limit := time.Now().Add(20 * time.Millisecond); ts.After(limit) {
+		t.Errorf("SequenceParts() time = %v, more than 20ms after %v", ts, limit.Add(-20*time.Millisecond))
+	}
+}
+
+func TestSequencerParts(t *testing.T) {
+	seq, err := id.NewSequencer(7)
+	if err != nil {
+		t.Fatalf("NewSequencer() error: %v", err)
+	}
+
+	before := time.Now().Add(-time.Millisecond)
+	v := seq.Next()
+	after := time.Now().Add(time.Millisecond)
+
+	ts, node, _ := id.SequenceParts(v)
+	if node != 7 {
+		t.Errorf("SequenceParts() node = %d, want 7", node)
+	}
+	if ts.Before(before) || ts.After(after) {
+		t.Errorf("SequenceParts() time = %v, want between %v and %v", ts, before, after)
+	}
+}
+
+func TestSequencerZeroValue(t *testing.T) {
+	var seq id.Sequencer
+	if got, want := seq.NodeID(), id.DefaultNodeID(); got != want {
+		t.Errorf("zero Sequencer NodeID() = %d, want DefaultNodeID() %d", got, want)
+	}
+	if v := seq.Next(); v <= 0 {
+		t.Errorf("zero Sequencer Next() = %d, want positive", v)
+	}
+}
+
+func TestSequencerConcurrent(t *testing.T) {
+	seq, err := id.NewSequencer(1)
+	if err != nil {
+		t.Fatalf("NewSequencer() error: %v", err)
+	}
+
+	const (
+		goroutines = 20
+		perRoutine = 1000
+	)
+
+	var (
+		mu  sync.Mutex
+		ids = make(map[int64]struct{}, goroutines*perRoutine)
+		wg  sync.WaitGroup
+	)
+
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			local := make([]int64, perRoutine)
+			for j := range local {
+				local[j] = seq.Next()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			for _, v := range local {
+				if _, exists := ids[v]; exists {
+					t.Errorf("duplicate ID generated: %d", v)
+					return
+				}
+				ids[v] = struct{}{}
+			}
+		}()
+	}
+
+	wg.Wait()
+}
+
+func BenchmarkSequencerNext(b *testing.B) {
+	seq, _ := id.NewSequencer(1)
+	for i := 0; i < b.N; i++ {
+		seq.Next()
+	}
+}
@@ -0,0 +1,44 @@
+package id
+
+import "encoding/base64"
+
+// Bounds on the number of random bytes used by Filename.
+const (
+	// FilenameMinBytes guarantees at least 7 characters, which rules out
+	// every Windows reserved device name (CON, NUL, COM1, LPT1, ...).
+	FilenameMinBytes = 4
+
+	// FilenameMaxBytes keeps names at or below 205 characters, well inside
+	// the 255 character component limit of NTFS, APFS, and ext4, leaving
+	// room for a caller-supplied prefix or extension.
+	FilenameMaxBytes = 128
+)
+
+// Token returns n cryptographically random bytes encoded as RFC 4648
+// base64url without padding. The result is safe in URLs, cookies, and
+// HTTP headers without escaping, making it suitable for CSRF and session
+// tokens.
+//
+// This function panics if crypto/rand fails.
+//
+// Example:
+//
+//	id.Token(32) // returns a 43-character string like "hT3k...Qw"
+func Token(n int) string {
+	return base64.RawURLEncoding.EncodeToString(GenerateBytes(n))
+}
+
+// Filename returns a random string of n bytes that is safe to use as a
+// file or directory name on every supported platform.
+//
+// The name uses lowercase base32 (a-z, 2-7), so it contains no separators,
+// no characters reserved on Windows, no leading dot or dash, and cannot
+// collide with another generated name on case-insensitive filesystems.
+// n is clamped to [FilenameMinBytes, FilenameMaxBytes] so the name can never
+// match a Windows reserved device name and its length stays bounded.
+//
+// This function panics if crypto/rand fails.
+func Filename(n int) string {
+	n = max(FilenameMinBytes, min(n, FilenameMaxBytes))
+	return Base32Lower.Encode(GenerateBytes(n))
+}
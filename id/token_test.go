@@ -0,0 +1,61 @@
+package id_test
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/grokify/oscompat/id"
+)
+
+func TestToken(t *testing.T) {
+	got := id.Token(32)
+	if len(got) != 43 {
+		t.Errorf("Token(32) returned length %d, want 43", len(got))
+	}
+	if strings.ContainsAny(got, "+/=") {
+		t.Errorf("Token(32) = %q contains non-URL-safe characters", got)
+	}
+	b, err := base64.RawURLEncoding.DecodeString(got)
+	if err != nil {
+		t.Fatalf("Token(32) is not base64url: %v", err)
+	}
+	if len(b) != 32 {
+		t.Errorf("Token(32) decoded to %d bytes, want 32", len(b))
+	}
+}
+
+func TestFilename(t *testing.T) {
+	reserved := []string{"con", "prn", "aux", "nul"}
+
+	tests := []struct {
+		name    string
+		n       int
+		wantLen int
+	}{
+		{"typical", 10, 16},
+		{"clamped up", 0, 7},
+		{"clamped down", 1000, 205},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := id.Filename(tt.n)
+			if len(got) != tt.wantLen {
+				t.Errorf("Filename(%d) returned length %d, want %d", tt.n, len(got), tt.wantLen)
+			}
+			for _, c := range got {
+				isLower := c >= 'a' && c <= 'z'
+				isDigit := c >= '2' && c <= '7'
+				if !isLower && !isDigit {
+					t.Errorf("Filename(%d) = %q contains %q", tt.n, got, c)
+				}
+			}
+			for _, r := range reserved {
+				if got == r {
+					t.Errorf("Filename(%d) = reserved name %q", tt.n, got)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,617 @@
+// Package dbus is a minimal D-Bus client, used to call freedesktop
+// services on Linux and BSD without cgo or external tools.
+//
+// It implements EXTERNAL authentication, method calls, signal reception,
+// and decoding of messages with basic, string, and array values. See
+// https://dbus.freedesktop.org/doc/dbus-specification.html.
+package dbus
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// D-Bus message types.
+const (
+	msgMethodCall   = 1
+	msgMethodReturn = 2
+	msgError        = 3
+	msgSignal       = 4
+)
+
+// D-Bus header field codes.
+const (
+	fieldPath        = 1
+	fieldInterface   = 2
+	fieldMember      = 3
+	fieldErrorName   = 4
+	fieldReplySerial = 5
+	fieldDestination = 6
+	fieldSignature   = 8
+)
+
+// timeout bounds every bus operation.
+const timeout = 5 * time.Second
+
+// systemBusAddress is the well-known address of the system bus.
+const systemBusAddress = "unix:path=/var/run/dbus/system_bus_socket"
+
+// ErrNoBus is returned when there is no bus to connect to.
+var ErrNoBus = errors.New("oscompat/dbus: no bus")
+
+// Conn is an authenticated bus connection. It is not safe for concurrent
+// use, except that Close may be called to interrupt Signal.
+type Conn struct {
+	conn   net.Conn
+	r      *bufio.Reader
+	serial uint32
+}
+
+// parseAddress returns the first Unix socket in a D-Bus address list.
+func parseAddress(addrs string) (network, address string, err error) {
+	// The list may hold several addresses separated by semicolons.
+	for _, addr := range strings.Split(addrs, ";") {
+		transport, params, ok := strings.Cut(addr, ":")
+		if !ok || transport != "unix" {
+			continue
+		}
+		for _, kv := range strings.Split(params, ",") {
+			key, value, _ := strings.Cut(kv, "=")
+			switch key {
+			case "path":
+				return "unix", unescapeAddress(value), nil
+			case "abstract":
+				return "unix", "@" + unescapeAddress(value), nil
+			}
+		}
+	}
+	return "", "", ErrNoBus
+}
+
+// unescapeAddress decodes %xx escapes in a D-Bus address value.
+func unescapeAddress(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '%' && i+2 < len(s) {
+			if v, err := strconv.ParseUint(s[i+1:i+3], 16, 8); err == nil {
+				b.WriteByte(byte(v))
+				i += 2
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+	return b.String()
+}
+
+// SessionBus connects and authenticates to the session bus.
+func SessionBus() (*Conn, error) {
+	addrs := os.Getenv("DBUS_SESSION_BUS_ADDRESS")
+	if addrs == "" {
+		if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
+			addrs = "unix:path=" + dir + "/bus"
+		}
+	}
+	return dial(addrs)
+}
+
+// SystemBus connects and authenticates to the system bus.
+func SystemBus() (*Conn, error) {
+	addrs := os.Getenv("DBUS_SYSTEM_BUS_ADDRESS")
+	if addrs == "" {
+		addrs = systemBusAddress
+	}
+	return dial(addrs)
+}
+
+// dial connects to the first usable address in addrs, authenticates, and
+// registers with the bus.
+func dial(addrs string) (*Conn, error) {
+	network, address, err := parseAddress(addrs)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.DialTimeout(network, address, timeout)
+	if err != nil {
+		return nil, ErrNoBus
+	}
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	c := &Conn{conn: conn, r: bufio.NewReader(conn)}
+
+	uid := hex.EncodeToString([]byte(strconv.Itoa(os.Getuid())))
+	if _, err := io.WriteString(conn, "\x00AUTH EXTERNAL "+uid+"\r\n"); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	if !strings.HasPrefix(line, "OK ") {
+		_ = conn.Close()
+		return nil, fmt.Errorf("oscompat/dbus: authentication failed: %s", strings.TrimSpace(line))
+	}
+	if _, err := io.WriteString(conn, "BEGIN\r\n"); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	if _, err := c.callBus("Hello", ""); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+// Close closes the connection.
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
+
+// Error is an error reply, such as
+// org.freedesktop.DBus.Error.ServiceUnknown.
+type Error struct {
+	Name    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return "oscompat/dbus: " + e.Name + ": " + e.Message
+}
+
+// Call invokes a method and returns the reply body. args must match
+// signature; see encoder.value for the Go types accepted. Reply values
+// are bool, byte, int32, uint32, string, and []any for arrays.
+func (c *Conn) Call(dest, path, iface, member, signature string, args ...any) ([]any, error) {
+	if err := c.conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return nil, err
+	}
+	c.serial++
+	serial := c.serial
+
+	var b encoder
+	for _, v := range args {
+		b.value(v)
+	}
+
+	var h encoder
+	h.byte('l')
+	h.byte(msgMethodCall)
+	h.byte(0)
+	h.byte(1)
+	h.uint32(uint32(len(b.buf)))
+	h.uint32(serial)
+	fields := []struct {
+		code byte
+		sig  string
+		val  any
+	}{
+		{fieldPath, "o", ObjectPath(path)},
+		{fieldInterface, "s", iface},
+		{fieldMember, "s", member},
+		{fieldDestination, "s", dest},
+	}
+	if signature != "" {
+		fields = append(fields, struct {
+			code byte
+			sig  string
+			val  any
+		}{fieldSignature, "g", Signature(signature)})
+	}
+	h.array(8, func() {
+		for _, f := range fields {
+			h.align(8)
+			h.byte(f.code)
+			h.signature(f.sig)
+			h.value(f.val)
+		}
+	})
+	h.align(8)
+
+	if _, err := c.conn.Write(append(h.buf, b.buf...)); err != nil {
+		return nil, err
+	}
+
+	for {
+		msg, err := readMessage(c.r)
+		if err != nil {
+			return nil, err
+		}
+		if msg.replySerial != serial {
+			continue // signals are dropped while waiting for a reply
+		}
+		switch msg.typ {
+		case msgMethodReturn:
+			return msg.body, nil
+		case msgError:
+			e := &Error{Name: msg.errorName}
+			if len(msg.body) > 0 {
+				e.Message, _ = msg.body[0].(string)
+			}
+			return nil, e
+		}
+	}
+}
+
+// Signal is a received signal.
+type Signal struct {
+	Path      string
+	Interface string
+	Member    string
+	Body      []any
+}
+
+// AddMatch asks the bus to deliver the signals matching rule, such as
+// "type='signal',interface='org.freedesktop.login1.Manager'".
+func (c *Conn) AddMatch(rule string) error {
+	_, err := c.callBus("AddMatch", "s", rule)
+	return err
+}
+
+// Signal blocks until the next signal arrives. Close the connection to
+// interrupt it.
+func (c *Conn) Signal() (*Signal, error) {
+	if err := c.conn.SetDeadline(time.Time{}); err != nil {
+		return nil, err
+	}
+	for {
+		msg, err := readMessage(c.r)
+		if err != nil {
+			return nil, err
+		}
+		if msg.typ == msgSignal {
+			return &Signal{Path: msg.path, Interface: msg.iface, Member: msg.member, Body: msg.body}, nil
+		}
+	}
+}
+
+// callBus calls a method of the bus itself.
+func (c *Conn) callBus(member, signature string, args ...any) ([]any, error) {
+	return c.Call("org.freedesktop.DBus", "/org/freedesktop/DBus", "org.freedesktop.DBus", member, signature, args...)
+}
+
+// HasService reports whether a service with the well-known name is
+// running on the bus or can be started by it.
+func (c *Conn) HasService(name string) bool {
+	reply, err := c.callBus("NameHasOwner", "s", name)
+	if err == nil && len(reply) > 0 && reply[0] == true {
+		return true
+	}
+	reply, err = c.callBus("ListActivatableNames", "")
+	return err == nil && len(reply) > 0 && hasString(reply[0], name)
+}
+
+// ObjectPath and Signature mark strings marshaled as D-Bus types o and g.
+type (
+	ObjectPath string
+	Signature  string
+)
+
+// ByteDict is an a{sv} dictionary whose values are byte variants, such as
+// the hints of a notification.
+type ByteDict map[string]byte
+
+// encoder marshals little-endian D-Bus values. Alignment is relative to
+// the start of buf, which is the start of the message or of the body.
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) align(n int) {
+	for len(e.buf)%n != 0 {
+		e.buf = append(e.buf, 0)
+	}
+}
+
+func (e *encoder) byte(b byte) {
+	e.buf = append(e.buf, b)
+}
+
+func (e *encoder) uint32(v uint32) {
+	e.align(4)
+	e.buf = binary.LittleEndian.AppendUint32(e.buf, v)
+}
+
+func (e *encoder) string(s string) {
+	e.uint32(uint32(len(s)))
+	e.buf = append(e.buf, s...)
+	e.buf = append(e.buf, 0)
+}
+
+func (e *encoder) signature(s string) {
+	e.buf = append(e.buf, byte(len(s)))
+	e.buf = append(e.buf, s...)
+	e.buf = append(e.buf, 0)
+}
+
+// array writes an array whose elements have the given alignment.
+func (e *encoder) array(elemAlign int, elems func()) {
+	e.uint32(0)
+	lenPos := len(e.buf) - 4
+	e.align(elemAlign)
+	start := len(e.buf)
+	elems()
+	binary.LittleEndian.PutUint32(e.buf[lenPos:], uint32(len(e.buf)-start))
+}
+
+// value marshals a Go value: string (s), ObjectPath (o), Signature (g),
+// uint32 (u), int32 (i), []string (as), and ByteDict (a{sv}).
+func (e *encoder) value(v any) {
+	switch v := v.(type) {
+	case string:
+		e.string(v)
+	case ObjectPath:
+		e.string(string(v))
+	case Signature:
+		e.signature(string(v))
+	case uint32:
+		e.uint32(v)
+	case int32:
+		e.uint32(uint32(v))
+	case []string:
+		e.array(4, func() {
+			for _, s := range v {
+				e.string(s)
+			}
+		})
+	case ByteDict:
+		e.array(8, func() {
+			for k, b := range v {
+				e.align(8)
+				e.string(k)
+				e.signature("y")
+				e.byte(b)
+			}
+		})
+	default:
+		panic(fmt.Sprintf("oscompat/dbus: cannot marshal %T", v))
+	}
+}
+
+// message is a decoded incoming message.
+type message struct {
+	typ         byte
+	path        string
+	iface       string
+	member      string
+	replySerial uint32
+	errorName   string
+	body        []any
+}
+
+// readMessage reads one message, decoding the header fields and body
+// values that call needs.
+func readMessage(r *bufio.Reader) (*message, error) {
+	var fixed [16]byte
+	if _, err := io.ReadFull(r, fixed[:]); err != nil {
+		return nil, err
+	}
+	var order binary.ByteOrder = binary.LittleEndian
+	if fixed[0] == 'B' {
+		order = binary.BigEndian
+	}
+	bodyLen := order.Uint32(fixed[4:8])
+	fieldsLen := order.Uint32(fixed[12:16])
+	headerLen := 16 + int(fieldsLen)
+	padded := (headerLen + 7) &^ 7
+	if fieldsLen > 1<<26 || bodyLen > 1<<27 {
+		return nil, errors.New("oscompat/dbus: message too large")
+	}
+
+	rest := make([]byte, padded-16+int(bodyLen))
+	if _, err := io.ReadFull(r, rest); err != nil {
+		return nil, err
+	}
+	data := append(fixed[:], rest...)
+
+	msg := &message{typ: fixed[1]}
+	d := &decoder{data: data[:headerLen], pos: 16, order: order}
+	var bodySig string
+	for d.pos < len(d.data) {
+		d.align(8)
+		if d.pos >= len(d.data) {
+			break
+		}
+		code := d.byte()
+		s := d.signature()
+		v, err := d.value(s)
+		if err != nil {
+			return nil, err
+		}
+		switch code {
+		case fieldPath:
+			msg.path, _ = v.(string)
+		case fieldInterface:
+			msg.iface, _ = v.(string)
+		case fieldMember:
+			msg.member, _ = v.(string)
+		case fieldReplySerial:
+			msg.replySerial, _ = v.(uint32)
+		case fieldErrorName:
+			msg.errorName, _ = v.(string)
+		case fieldSignature:
+			bodySig, _ = v.(string)
+		}
+	}
+
+	d = &decoder{data: data[padded:], order: order}
+	for sigs := bodySig; sigs != ""; {
+		one, rest := nextType(sigs)
+		sigs = rest
+		v, err := d.value(one)
+		if err != nil {
+			// Leave types this decoder does not know undecoded.
+			break
+		}
+		msg.body = append(msg.body, v)
+	}
+	return msg, nil
+}
+
+// nextType splits the first complete type off a signature.
+func nextType(s string) (string, string) {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case 'a':
+			continue
+		case '(', '{':
+			depth++
+		case ')', '}':
+			depth--
+		}
+		if depth == 0 {
+			return s[:i+1], s[i+1:]
+		}
+	}
+	return s, ""
+}
+
+// decoder unmarshals D-Bus values.
+type decoder struct {
+	data  []byte
+	pos   int
+	order binary.ByteOrder
+}
+
+var errShort = errors.New("oscompat/dbus: truncated message")
+
+func (d *decoder) align(n int) {
+	d.pos = (d.pos + n - 1) &^ (n - 1)
+}
+
+func (d *decoder) byte() byte {
+	if d.pos >= len(d.data) {
+		d.pos++
+		return 0
+	}
+	b := d.data[d.pos]
+	d.pos++
+	return b
+}
+
+func (d *decoder) signature() string {
+	n := int(d.byte())
+	if d.pos+n+1 > len(d.data) {
+		d.pos = len(d.data) + 1
+		return ""
+	}
+	s := string(d.data[d.pos : d.pos+n])
+	d.pos += n + 1
+	return s
+}
+
+// value decodes one value of the single complete type t. Supported are
+// y, b, u, i, s, o, g, v, and arrays of those.
+func (d *decoder) value(t string) (any, error) {
+	if t == "" {
+		return nil, errShort
+	}
+	switch t[0] {
+	case 'y':
+		if d.pos >= len(d.data) {
+			return nil, errShort
+		}
+		return d.byte(), nil
+	case 'b', 'u', 'i':
+		d.align(4)
+		if d.pos+4 > len(d.data) {
+			return nil, errShort
+		}
+		v := d.order.Uint32(d.data[d.pos:])
+		d.pos += 4
+		switch t[0] {
+		case 'b':
+			return v != 0, nil
+		case 'i':
+			return int32(v), nil
+		}
+		return v, nil
+	case 's', 'o':
+		d.align(4)
+		if d.pos+4 > len(d.data) {
+			return nil, errShort
+		}
+		n := int(d.order.Uint32(d.data[d.pos:]))
+		d.pos += 4
+		if n < 0 || d.pos+n+1 > len(d.data) {
+			return nil, errShort
+		}
+		s := string(d.data[d.pos : d.pos+n])
+		d.pos += n + 1
+		return s, nil
+	case 'g':
+		s := d.signature()
+		if d.pos > len(d.data) {
+			return nil, errShort
+		}
+		return s, nil
+	case 'v':
+		s := d.signature()
+		return d.value(s)
+	case 'a':
+		d.align(4)
+		if d.pos+4 > len(d.data) {
+			return nil, errShort
+		}
+		n := int(d.order.Uint32(d.data[d.pos:]))
+		d.pos += 4
+		elem := t[1:]
+		if elem != "" && (elem[0] == '(' || elem[0] == '{') {
+			return nil, fmt.Errorf("oscompat/dbus: unsupported type %q", t)
+		}
+		d.align(alignOf(elem))
+		end := d.pos + n
+		if end > len(d.data) {
+			return nil, errShort
+		}
+		var out []any
+		for d.pos < end {
+			v, err := d.value(elem)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+		}
+		return out, nil
+	}
+	return nil, fmt.Errorf("oscompat/dbus: unsupported type %q", t)
+}
+
+// alignOf returns the alignment of the type t.
+func alignOf(t string) int {
+	if t == "" {
+		return 1
+	}
+	switch t[0] {
+	case 'y', 'g', 'v':
+		return 1
+	case 'x', 't', 'd', '(', '{':
+		return 8
+	}
+	return 4
+}
+
+// hasString reports whether any element of an array value is s.
+func hasString(v any, s string) bool {
+	list, _ := v.([]any)
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,178 @@
+package keyring
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	oscfs "github.com/grokify/oscompat/fs"
+	"github.com/grokify/oscompat/id"
+	"github.com/grokify/oscompat/paths"
+)
+
+const (
+	fileApp   = "oscompat-keyring"
+	keyFile   = "keyring.key"
+	storeFile = "keyring.enc"
+	keySize   = 32
+)
+
+// fileMu serializes access to the encrypted file within the process.
+var fileMu sync.Mutex
+
+// fileSecrets maps service to account to secret.
+type fileSecrets map[string]map[string]string
+
+func fileSet(service, account, secret string) error {
+	fileMu.Lock()
+	defer fileMu.Unlock()
+	dir, aead, err := openFile(true)
+	if err != nil {
+		return err
+	}
+	secrets, err := readFile(dir, aead)
+	if err != nil {
+		return err
+	}
+	if secrets[service] == nil {
+		secrets[service] = make(map[string]string)
+	}
+	secrets[service][account] = secret
+	return writeFile(dir, aead, secrets)
+}
+
+func fileGet(service, account string) (string, error) {
+	fileMu.Lock()
+	defer fileMu.Unlock()
+	dir, aead, err := openFile(false)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	secrets, err := readFile(dir, aead)
+	if err != nil {
+		return "", err
+	}
+	secret, ok := secrets[service][account]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return secret, nil
+}
+
+func fileDelete(service, account string) error {
+	fileMu.Lock()
+	defer fileMu.Unlock()
+	dir, aead, err := openFile(false)
+	if errors.Is(err, fs.ErrNotExist) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	secrets, err := readFile(dir, aead)
+	if err != nil {
+		return err
+	}
+	if _, ok := secrets[service][account]; !ok {
+		return ErrNotFound
+	}
+	delete(secrets[service], account)
+	if len(secrets[service]) == 0 {
+		delete(secrets, service)
+	}
+	return writeFile(dir, aead, secrets)
+}
+
+// openFile returns the keyring directory and the cipher for the file,
+// creating the key if create is set. Without create, a missing key
+// yields an error matching fs.ErrNotExist.
+func openFile(create bool) (string, cipher.AEAD, error) {
+	dir, err := paths.AppData(fileApp)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := os.Chmod(dir, oscfs.PrivateDirPerm); err != nil {
+		return "", nil, err
+	}
+
+	keyPath := filepath.Join(dir, keyFile)
+	raw, err := os.ReadFile(keyPath)
+	if errors.Is(err, fs.ErrNotExist) && create {
+		raw = make([]byte, keySize)
+		if _, err := rand.Read(raw); err != nil {
+			return "", nil, err
+		}
+		err = oscfs.WriteFilePrivate(keyPath, raw)
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	if len(raw) != keySize {
+		return "", nil, errors.New("oscompat/keyring: corrupt key file " + keyPath)
+	}
+
+	// Bind the key to this machine, so that copying the directory to
+	// another machine does not reveal the secrets.
+	h := sha256.New()
+	h.Write(raw)
+	if mid, err := id.MachineID(); err == nil {
+		h.Write([]byte(mid))
+	}
+	block, err := aes.NewCipher(h.Sum(nil))
+	if err != nil {
+		return "", nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return "", nil, err
+	}
+	return dir, aead, nil
+}
+
+// readFile decrypts the secrets file, returning an empty set if it does
+// not exist.
+func readFile(dir string, aead cipher.AEAD) (fileSecrets, error) {
+	data, err := os.ReadFile(filepath.Join(dir, storeFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return make(fileSecrets), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	n := aead.NonceSize()
+	if len(data) < n {
+		return nil, errors.New("oscompat/keyring: corrupt secrets file")
+	}
+	plain, err := aead.Open(nil, data[:n], data[n:], nil)
+	if err != nil {
+		return nil, errors.New("oscompat/keyring: cannot decrypt secrets file")
+	}
+	secrets := make(fileSecrets)
+	if err := json.Unmarshal(plain, &secrets); err != nil {
+		return nil, err
+	}
+	return secrets, nil
+}
+
+// writeFile encrypts secrets with a fresh nonce and writes the file.
+func writeFile(dir string, aead cipher.AEAD, secrets fileSecrets) error {
+	plain, err := json.Marshal(secrets)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	return oscfs.WriteFilePrivate(filepath.Join(dir, storeFile), aead.Seal(nonce, nonce, plain, nil))
+}
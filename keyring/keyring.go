@@ -0,0 +1,96 @@
+// Package keyring stores secrets, such as API tokens, in the operating
+// system's credential store.
+//
+// Platform behavior:
+//   - Windows: Credential Manager, as generic credentials named
+//     "<service>:<account>".
+//   - macOS: the login keychain, as generic passwords, through the
+//     security tool. Secrets are passed on its standard input, never on
+//     the command line.
+//   - Linux and BSD: the Secret Service (GNOME Keyring, KWallet) through
+//     libsecret's secret-tool, with the attributes "service" and
+//     "account".
+//
+// Where no credential store is available, such as on a headless server
+// or in a container, secrets are kept in an encrypted file instead; see
+// Set. Secrets are looked up in that file whenever the credential store
+// does not have them, so that they stay readable after the store
+// appears.
+package keyring
+
+import "errors"
+
+var (
+	// ErrNotFound is returned when no secret is stored for the service
+	// and account.
+	ErrNotFound = errors.New("oscompat/keyring: secret not found")
+
+	// ErrInvalidName is returned for an empty service or account.
+	ErrInvalidName = errors.New("oscompat/keyring: service and account must not be empty")
+
+	// ErrTooLong is returned for secrets larger than the credential store
+	// accepts: 2560 bytes on Windows.
+	ErrTooLong = errors.New("oscompat/keyring: secret too long")
+)
+
+// errNoStore is returned by the platform functions when there is no
+// credential store to use.
+var errNoStore = errors.New("oscompat/keyring: no credential store")
+
+// Set stores secret for the service and account, replacing any previous
+// secret.
+//
+// Without a credential store, the secret is written to an AES-GCM
+// encrypted file under paths.AppData("oscompat-keyring"), with a key kept
+// in a private file next to it and bound to the machine ID. This keeps
+// secrets out of plain configuration files and backups taken from other
+// machines, but does not protect them from programs running as the same
+// user.
+func Set(service, account, secret string) error {
+	if service == "" || account == "" {
+		return ErrInvalidName
+	}
+	err := set(service, account, secret)
+	if errors.Is(err, errNoStore) {
+		return fileSet(service, account, secret)
+	}
+	if err != nil {
+		return err
+	}
+	// Drop a copy written while the credential store was unavailable.
+	_ = fileDelete(service, account)
+	return nil
+}
+
+// Get returns the secret stored for the service and account, or
+// ErrNotFound.
+func Get(service, account string) (string, error) {
+	if service == "" || account == "" {
+		return "", ErrInvalidName
+	}
+	secret, err := get(service, account)
+	if err == nil {
+		return secret, nil
+	}
+	if !errors.Is(err, errNoStore) && !errors.Is(err, ErrNotFound) {
+		return "", err
+	}
+	return fileGet(service, account)
+}
+
+// Delete removes the secret stored for the service and account. It
+// returns ErrNotFound if there is none.
+func Delete(service, account string) error {
+	if service == "" || account == "" {
+		return ErrInvalidName
+	}
+	err := del(service, account)
+	if err != nil && !errors.Is(err, errNoStore) && !errors.Is(err, ErrNotFound) {
+		return err
+	}
+	fileErr := fileDelete(service, account)
+	if err == nil || fileErr == nil {
+		return nil
+	}
+	return fileErr
+}
@@ -0,0 +1,78 @@
+//go:build darwin
+
+package keyring
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// errSecItemNotFound is the exit status of security when no item
+// matches.
+const errSecItemNotFound = 44
+
+func set(service, account, secret string) error {
+	if _, err := exec.LookPath("security"); err != nil {
+		return errNoStore
+	}
+	// In interactive mode, security reads the command from standard
+	// input, which keeps the secret out of the process list. -X takes the
+	// secret hex-encoded, so it needs no quoting.
+	command := fmt.Sprintf("add-generic-password -U -s %s -a %s -X %s\n",
+		securityQuote(service), securityQuote(account), hex.EncodeToString([]byte(secret)))
+	_, err := security(command, "-i")
+	return err
+}
+
+func get(service, account string) (string, error) {
+	if _, err := exec.LookPath("security"); err != nil {
+		return "", errNoStore
+	}
+	out, err := security("", "find-generic-password", "-s", service, "-a", account, "-w")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+func del(service, account string) error {
+	if _, err := exec.LookPath("security"); err != nil {
+		return errNoStore
+	}
+	_, err := security("", "delete-generic-password", "-s", service, "-a", account)
+	return err
+}
+
+// security runs the security tool with stdin as its standard input.
+func security(stdin string, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("security", args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	msg := strings.TrimSpace(stderr.String())
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == errSecItemNotFound {
+		return nil, ErrNotFound
+	}
+	// Interactive mode exits 0 even when the command fails.
+	if err == nil && (args[0] != "-i" || msg == "") {
+		return stdout.Bytes(), nil
+	}
+	if msg != "" {
+		return nil, fmt.Errorf("oscompat/keyring: security: %s", msg)
+	}
+	return nil, fmt.Errorf("oscompat/keyring: security: %w", err)
+}
+
+// securityQuote quotes s for the command parser of security -i.
+func securityQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
@@ -0,0 +1,77 @@
+package keyring_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/grokify/oscompat/keyring"
+)
+
+// useFile points the encrypted-file fallback at a temporary directory
+// and skips the test where a credential store would be used instead.
+func useFile(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		t.Skip("the credential store is always used on this platform")
+	}
+	if _, err := exec.LookPath("secret-tool"); err == nil {
+		t.Skip("secret-tool installed; the Secret Service may be used")
+	}
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+}
+
+func TestFileFallback(t *testing.T) {
+	useFile(t)
+
+	if _, err := keyring.Get("oscompat-test", "alice"); !errors.Is(err, keyring.ErrNotFound) {
+		t.Errorf("Get() before Set error = %v, want ErrNotFound", err)
+	}
+	if err := keyring.Set("oscompat-test", "alice", "s3cret ü"); err != nil {
+		t.Fatalf("Set() error: %v", err)
+	}
+	if err := keyring.Set("oscompat-test", "bob", "other"); err != nil {
+		t.Fatalf("Set() error: %v", err)
+	}
+	if got, err := keyring.Get("oscompat-test", "alice"); err != nil || got != "s3cret ü" {
+		t.Errorf("Get() = %q, %v, want %q", got, err, "s3cret ü")
+	}
+
+	if err := keyring.Delete("oscompat-test", "alice"); err != nil {
+		t.Fatalf("Delete() error: %v", err)
+	}
+	if err := keyring.Delete("oscompat-test", "alice"); !errors.Is(err, keyring.ErrNotFound) {
+		t.Errorf("second Delete() error = %v, want ErrNotFound", err)
+	}
+	if got, err := keyring.Get("oscompat-test", "bob"); err != nil || got != "other" {
+		t.Errorf("Get(bob) = %q, %v, want %q", got, err, "other")
+	}
+}
+
+func TestFileEncrypted(t *testing.T) {
+	useFile(t)
+	const secret = "plaintext-must-not-appear"
+	if err := keyring.Set("oscompat-test", "alice", secret); err != nil {
+		t.Fatalf("Set() error: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(os.Getenv("XDG_DATA_HOME"), "oscompat-keyring", "keyring.enc"))
+	if err != nil {
+		t.Fatalf("reading secrets file: %v", err)
+	}
+	if bytes.Contains(data, []byte(secret)) {
+		t.Error("secrets file contains the secret in plain text")
+	}
+}
+
+func TestInvalidName(t *testing.T) {
+	if err := keyring.Set("", "alice", "x"); !errors.Is(err, keyring.ErrInvalidName) {
+		t.Errorf("Set() error = %v, want ErrInvalidName", err)
+	}
+	if _, err := keyring.Get("svc", ""); !errors.Is(err, keyring.ErrInvalidName) {
+		t.Errorf("Get() error = %v, want ErrInvalidName", err)
+	}
+}
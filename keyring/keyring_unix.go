@@ -0,0 +1,84 @@
+//go:build !windows && !darwin
+
+package keyring
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/grokify/oscompat/internal/dbus"
+)
+
+// secretsService is the D-Bus name of the Secret Service.
+const secretsService = "org.freedesktop.secrets"
+
+// available reports whether secret-tool is installed and a Secret
+// Service is running or can be started on the session bus.
+func available() bool {
+	if _, err := exec.LookPath("secret-tool"); err != nil {
+		return false
+	}
+	c, err := dbus.SessionBus()
+	if err != nil {
+		return false
+	}
+	defer func() { _ = c.Close() }()
+	return c.HasService(secretsService)
+}
+
+func set(service, account, secret string) error {
+	if !available() {
+		return errNoStore
+	}
+	label := fmt.Sprintf("Password for '%s' on '%s'", account, service)
+	_, err := secretTool(secret, "store", "--label="+label, "service", service, "account", account)
+	return err
+}
+
+func get(service, account string) (string, error) {
+	if !available() {
+		return "", errNoStore
+	}
+	out, err := secretTool("", "lookup", "service", service, "account", account)
+	if err != nil {
+		return "", err
+	}
+	if out == nil {
+		return "", ErrNotFound
+	}
+	return string(out), nil
+}
+
+func del(service, account string) error {
+	if _, err := get(service, account); err != nil {
+		return err
+	}
+	_, err := secretTool("", "clear", "service", service, "account", account)
+	return err
+}
+
+// secretTool runs secret-tool with stdin as its standard input. It
+// returns nil output, and no error, when lookup finds nothing.
+func secretTool(stdin string, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("secret-tool", args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if err == nil {
+		return stdout.Bytes(), nil
+	}
+	msg := strings.TrimSpace(stderr.String())
+	var exitErr *exec.ExitError
+	if args[0] == "lookup" && msg == "" && errors.As(err, &exitErr) {
+		return nil, nil
+	}
+	if msg != "" {
+		return nil, fmt.Errorf("oscompat/keyring: secret-tool %s: %s", args[0], msg)
+	}
+	return nil, fmt.Errorf("oscompat/keyring: secret-tool %s: %w", args[0], err)
+}
@@ -9,8 +9,11 @@
 package localnet
 
 import (
+	"context"
 	"errors"
 	"net"
+	"sort"
+	"sync"
 )
 
 // Common errors.
@@ -21,24 +24,137 @@ var (
 	// ErrSocketExists is returned when trying to create a listener
 	// but a socket file already exists (Unix only).
 	ErrSocketExists = errors.New("oscompat/localnet: socket already exists")
+
+	// ErrUntrusted is returned by DialConfig.Dial with AllSessions set
+	// when the endpoint is not owned by a privileged user.
+	ErrUntrusted = errors.New("oscompat/localnet: endpoint is not owned by a privileged user")
 )
 
 // Listener wraps a net.Listener with cleanup functionality.
 type Listener struct {
 	net.Listener
 	name    string
+	addrs   []net.Addr
 	cleanup func() error
+
+	closeOnce sync.Once
+	closeErr  error
+
+	track   bool // wrap accepted connections for Shutdown
+	mu      sync.Mutex
+	conns   map[*conn]struct{}
+	drained chan struct{} // closed when conns empties during Shutdown
+}
+
+// Accept waits for and returns the next connection. With
+// ListenConfig.TrackConnections the connection is wrapped so that
+// Shutdown can track it, so it is not a *net.UnixConn or *net.TCPConn
+// itself; use Unwrap to get the underlying connection.
+func (l *Listener) Accept() (net.Conn, error) {
+	c, err := l.Listener.Accept()
+	if err != nil || !l.track {
+		return c, err
+	}
+	tc := &conn{Conn: c, l: l}
+	l.mu.Lock()
+	if l.conns == nil {
+		l.conns = make(map[*conn]struct{})
+	}
+	l.conns[tc] = struct{}{}
+	l.mu.Unlock()
+	return tc, nil
 }
 
 // Close closes the listener and performs any necessary cleanup.
+// Connections already accepted stay open; see Shutdown.
 func (l *Listener) Close() error {
-	err := l.Listener.Close()
-	if l.cleanup != nil {
-		cleanupErr := l.cleanup()
-		if err == nil {
-			err = cleanupErr
+	l.closeOnce.Do(func() {
+		l.closeErr = l.Listener.Close()
+		if l.cleanup != nil {
+			if err := l.cleanup(); l.closeErr == nil {
+				l.closeErr = err
+			}
 		}
+	})
+	return l.closeErr
+}
+
+// Shutdown gracefully shuts the listener down, like http.Server.Shutdown:
+// it stops accepting connections and removes the socket or port file at
+// once, so new clients fail fast, then waits until every accepted
+// connection has been closed. If ctx ends first, Shutdown closes the
+// remaining connections and returns ctx.Err().
+//
+// Only listeners created with ListenConfig.TrackConnections know their
+// connections; for others Shutdown returns as soon as the listener is
+// closed.
+//
+// Call Shutdown on SIGTERM so that clients are not cut off in the middle
+// of a message; the handlers must close their connections when done.
+func (l *Listener) Shutdown(ctx context.Context) error {
+	err := l.Close()
+
+	l.mu.Lock()
+	if l.drained == nil {
+		l.drained = make(chan struct{})
+		if len(l.conns) == 0 {
+			close(l.drained)
+		}
+	}
+	drained := l.drained
+	l.mu.Unlock()
+
+	select {
+	case <-drained:
+		return err
+	case <-ctx.Done():
+	}
+	l.mu.Lock()
+	for c := range l.conns {
+		_ = c.Conn.Close()
+	}
+	l.mu.Unlock()
+	return ctx.Err()
+}
+
+// conn is a connection tracked by its Listener.
+type conn struct {
+	net.Conn
+	l    *Listener
+	once sync.Once
+}
+
+// NetConn returns the underlying connection.
+func (c *conn) NetConn() net.Conn {
+	return c.Conn
+}
+
+// Unwrap returns the connection underlying c if c was returned by
+// Listener.Accept, and c itself otherwise. Use it before asserting a
+// concrete type such as *net.UnixConn. Closing the underlying connection
+// directly hides it from Shutdown, which then waits for its deadline.
+func Unwrap(c net.Conn) net.Conn {
+	if tc, ok := c.(*conn); ok {
+		return tc.Conn
 	}
+	return c
+}
+
+func (c *conn) Close() error {
+	err := c.Conn.Close()
+	c.once.Do(func() {
+		l := c.l
+		l.mu.Lock()
+		delete(l.conns, c)
+		if l.drained != nil && len(l.conns) == 0 {
+			select {
+			case <-l.drained:
+			default:
+				close(l.drained)
+			}
+		}
+		l.mu.Unlock()
+	})
 	return err
 }
 
@@ -53,25 +169,27 @@ func (l *Listener) Name() string {
 // location (e.g., /tmp/<name>.sock or $XDG_RUNTIME_DIR/<name>.sock).
 //
 // On Windows, this creates a TCP listener on localhost with an ephemeral port,
-// storing the port in a file for clients to discover.
+// storing the port in a file for clients to discover. Use ListenConfig to
+// control the addresses it binds.
 //
 // The returned Listener's Close method will clean up any socket files.
 func Listen(name string) (*Listener, error) {
 	if name == "" {
 		return nil, ErrInvalidName
 	}
-	return listen(name)
+	return listen(name, &ListenConfig{})
 }
 
-// Dial connects to a local IPC endpoint.
+// Dial connects to a local IPC endpoint of the current user.
 //
 // On Unix systems, this connects to the Unix domain socket for the given name.
 // On Windows, this reads the port file and connects via TCP to localhost.
+// Use DialConfig to reach an endpoint created with ListenConfig.AllSessions.
 func Dial(name string) (net.Conn, error) {
 	if name == "" {
 		return nil, ErrInvalidName
 	}
-	return dial(name)
+	return dial(context.Background(), name, &DialConfig{})
 }
 
 // SocketPath returns the path or address that would be used for the given name.
@@ -92,3 +210,46 @@ func Cleanup(name string) error {
 	}
 	return cleanup(name)
 }
+
+// Endpoint describes a local IPC endpoint created by this package.
+type Endpoint struct {
+	// Name is the name passed to Listen.
+	Name string
+
+	// Path is the socket file (Unix) or port file (Windows) backing the endpoint.
+	Path string
+
+	// PID is the process ID of the listener that created the endpoint,
+	// or 0 if it could not be determined.
+	PID int
+
+	// Alive reports whether the endpoint accepted a connection when probed.
+	Alive bool
+}
+
+// List returns the endpoints created by this package that are present on
+// this machine for the current user, sorted by name.
+//
+// On Unix systems, this scans the socket directory for sockets that have
+// a matching PID file written by Listen. On Windows, this scans the port
+// file directory.
+//
+// Each endpoint is probed with a connection attempt to determine whether it
+// is alive. Endpoints that are not alive are typically left over from a
+// process that crashed and can be removed with Cleanup.
+func List() ([]Endpoint, error) {
+	endpoints, err := list()
+	if err != nil {
+		return nil, err
+	}
+	for i := range endpoints {
+		if conn, err := dial(context.Background(), endpoints[i].Name, &DialConfig{}); err == nil {
+			_ = conn.Close()
+			endpoints[i].Alive = true
+		}
+	}
+	sort.Slice(endpoints, func(i, j int) bool {
+		return endpoints[i].Name < endpoints[j].Name
+	})
+	return endpoints, nil
+}
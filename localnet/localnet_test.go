@@ -1,7 +1,12 @@
 package localnet_test
 
 import (
+	"context"
+	"errors"
 	"io"
+	"net"
+	"os"
+	"runtime"
 	"testing"
 	"time"
 
@@ -148,3 +153,278 @@ func TestListenerClose(t *testing.T) {
 		t.Error("Dial() after Close() should fail")
 	}
 }
+
+func TestList(t *testing.T) {
+	name := "oscompat-list-test-" + time.Now().Format("20060102150405")
+
+	// Cleanup before test (ignore error - may not exist)
+	_ = localnet.Cleanup(name)
+
+	listener, err := localnet.Listen(name)
+	if err != nil {
+		t.Fatalf("Listen() error: %v", err)
+	}
+
+	endpoints, err := localnet.List()
+	if err != nil {
+		t.Fatalf("List() error: %v", err)
+	}
+
+	var found *localnet.Endpoint
+	for i := range endpoints {
+		if endpoints[i].Name == name {
+			found = &endpoints[i]
+		}
+	}
+	if found == nil {
+		t.Fatalf("List() did not include %q", name)
+	}
+	if found.PID != os.Getpid() {
+		t.Errorf("List() PID = %d, want %d", found.PID, os.Getpid())
+	}
+	if !found.Alive {
+		t.Error("List() reported live endpoint as not alive")
+	}
+
+	if err := listener.Close(); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+
+	endpoints, err = localnet.List()
+	if err != nil {
+		t.Fatalf("List() error: %v", err)
+	}
+	for _, ep := range endpoints {
+		if ep.Name == name {
+			t.Errorf("List() still includes %q after Close()", name)
+		}
+	}
+}
+
+func TestListenConfig(t *testing.T) {
+	name := "oscompat-test-lc-" + time.Now().Format("20060102150405")
+	_ = localnet.Cleanup(name)
+
+	lc := &localnet.ListenConfig{LoopbackOnly: true, IPv6: true}
+	listener, err := lc.Listen(name)
+	if err != nil {
+		t.Fatalf("ListenConfig.Listen() error: %v", err)
+	}
+	defer func() { _ = listener.Close() }()
+
+	addrs := listener.Addrs()
+	if len(addrs) == 0 || addrs[0].String() != listener.Addr().String() {
+		t.Fatalf("Addrs() = %v, Addr() = %v", addrs, listener.Addr())
+	}
+	if runtime.GOOS == "windows" {
+		for _, addr := range addrs {
+			tcp, ok := addr.(*net.TCPAddr)
+			if !ok || !tcp.IP.IsLoopback() {
+				t.Errorf("bound to non-loopback address %v", addr)
+			}
+		}
+	}
+
+	go func() {
+		if conn, err := listener.Accept(); err == nil {
+			_ = conn.Close()
+		}
+	}()
+	conn, err := localnet.Dial(name)
+	if err != nil {
+		t.Fatalf("Dial() error: %v", err)
+	}
+	_ = conn.Close()
+
+	if _, err := lc.Listen(""); err != localnet.ErrInvalidName {
+		t.Errorf("Listen('') expected ErrInvalidName, got: %v", err)
+	}
+}
+
+func TestListenAllSessions(t *testing.T) {
+	if runtime.GOOS != "windows" && os.Geteuid() != 0 {
+		t.Skip("AllSessions endpoints require root")
+	}
+	name := "oscompat-test-shared-" + time.Now().Format("20060102150405")
+	_ = localnet.Cleanup(name)
+	if runtime.GOOS != "windows" {
+		// Keep the per-user directory apart from the shared one.
+		t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	}
+
+	lc := &localnet.ListenConfig{AllSessions: true}
+	listener, err := lc.Listen(name)
+	if err != nil {
+		t.Fatalf("Listen(AllSessions) error: %v", err)
+	}
+	defer func() { _ = listener.Close() }()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			_ = conn.Close()
+		}
+	}()
+
+	if _, err := os.Stat(localnet.SocketPath(name)); err == nil {
+		t.Error("AllSessions endpoint created in the per-user directory")
+	}
+	if conn, err := localnet.Dial(name); err == nil {
+		_ = conn.Close()
+		t.Error("Dial() found an AllSessions endpoint without DialConfig.AllSessions")
+	}
+	dc := &localnet.DialConfig{AllSessions: true}
+	conn, err := dc.Dial(name)
+	if runtime.GOOS == "windows" && errors.Is(err, localnet.ErrUntrusted) {
+		t.Skip("not running as SYSTEM or an administrator")
+	}
+	if err != nil {
+		t.Fatalf("DialConfig.Dial() of shared endpoint error: %v", err)
+	}
+	_ = conn.Close()
+
+	if runtime.GOOS != "windows" {
+		path := listener.Addr().String()
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if perm := info.Mode().Perm(); perm != 0o666 {
+			t.Errorf("shared socket mode = %v, want 0666", perm)
+		}
+
+		// A socket owned by an ordinary user is refused.
+		if err := os.Lchown(path, 65534, 65534); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := dc.Dial(name); !errors.Is(err, localnet.ErrUntrusted) {
+			t.Errorf("DialConfig.Dial() of a socket not owned by root = %v, want ErrUntrusted", err)
+		}
+	}
+}
+
+func TestListenerShutdown(t *testing.T) {
+	name := "oscompat-test-shutdown-" + time.Now().Format("20060102150405")
+	_ = localnet.Cleanup(name)
+
+	listen := func() (*localnet.Listener, net.Conn, net.Conn) {
+		t.Helper()
+		lc := &localnet.ListenConfig{TrackConnections: true}
+		listener, err := lc.Listen(name)
+		if err != nil {
+			t.Fatalf("Listen() error: %v", err)
+		}
+		accepted := make(chan net.Conn, 1)
+		go func() {
+			if conn, err := listener.Accept(); err == nil {
+				accepted <- conn
+			}
+			close(accepted)
+		}()
+		client, err := localnet.Dial(name)
+		if err != nil {
+			t.Fatalf("Dial() error: %v", err)
+		}
+		server := <-accepted
+		if server == nil {
+			t.Fatal("Accept() failed")
+		}
+		return listener, server, client
+	}
+
+	t.Run("unwrap", func(t *testing.T) {
+		listener, server, client := listen()
+		defer func() { _ = listener.Close() }()
+		defer func() { _ = server.Close() }()
+		defer func() { _ = client.Close() }()
+
+		raw := localnet.Unwrap(server)
+		if raw == server {
+			t.Error("Unwrap() returned the tracked connection")
+		}
+		if _, ok := raw.(*net.UnixConn); !ok && runtime.GOOS != "windows" {
+			t.Errorf("Unwrap() = %T, want *net.UnixConn", raw)
+		}
+		if localnet.Unwrap(client) != client {
+			t.Error("Unwrap() changed a connection Listen did not accept")
+		}
+	})
+
+	t.Run("untracked", func(t *testing.T) {
+		listener, err := localnet.Listen(name)
+		if err != nil {
+			t.Fatalf("Listen() error: %v", err)
+		}
+		defer func() { _ = listener.Close() }()
+		accepted := make(chan net.Conn, 1)
+		go func() {
+			if conn, err := listener.Accept(); err == nil {
+				accepted <- conn
+			}
+			close(accepted)
+		}()
+		client, err := localnet.Dial(name)
+		if err != nil {
+			t.Fatalf("Dial() error: %v", err)
+		}
+		defer func() { _ = client.Close() }()
+		server := <-accepted
+		if server == nil {
+			t.Fatal("Accept() failed")
+		}
+		defer func() { _ = server.Close() }()
+
+		// Without tracking, Accept returns the connection itself.
+		switch server.(type) {
+		case *net.UnixConn, *net.TCPConn:
+		default:
+			t.Errorf("Accept() = %T, want *net.UnixConn or *net.TCPConn", server)
+		}
+		if err := listener.Shutdown(context.Background()); err != nil {
+			t.Errorf("Shutdown() error: %v", err)
+		}
+	})
+
+	t.Run("drain", func(t *testing.T) {
+		listener, server, client := listen()
+		defer func() { _ = client.Close() }()
+
+		done := make(chan error, 1)
+		go func() { done <- listener.Shutdown(context.Background()) }()
+		select {
+		case err := <-done:
+			t.Fatalf("Shutdown() returned %v with a connection open", err)
+		case <-time.After(100 * time.Millisecond):
+		}
+		if _, err := localnet.Dial(name); err == nil {
+			t.Error("Dial() succeeded during Shutdown")
+		}
+
+		_ = server.Close()
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Errorf("Shutdown() error: %v", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("Shutdown() did not return after the connection closed")
+		}
+	})
+
+	t.Run("deadline", func(t *testing.T) {
+		listener, _, client := listen()
+		defer func() { _ = client.Close() }()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		if err := listener.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Shutdown() error = %v, want DeadlineExceeded", err)
+		}
+		_ = client.SetReadDeadline(time.Now().Add(5 * time.Second))
+		if _, err := client.Read(make([]byte, 1)); err != io.EOF {
+			t.Errorf("client Read() after forced close = %v, want EOF", err)
+		}
+	})
+}
@@ -3,10 +3,14 @@
 package localnet
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
 )
 
 // socketDir returns the directory for socket files.
@@ -19,14 +23,40 @@ func socketDir() string {
 	return "/tmp"
 }
 
+// sharedSocketDir holds the sockets of endpoints created with
+// ListenConfig.AllSessions. Only root can create it, and Dial refuses it
+// unless it is owned by root and writable by no one else, so another
+// user cannot plant a socket there.
+const sharedSocketDir = "/var/run/oscompat"
+
 // socketPath returns the full path to the socket file.
 func socketPath(name string) string {
 	return filepath.Join(socketDir(), name+".sock")
 }
 
-// listen creates a Unix domain socket listener.
-func listen(name string) (*Listener, error) {
-	path := socketPath(name)
+// sharedSocketPath returns the socket path used with AllSessions.
+func sharedSocketPath(name string) string {
+	return filepath.Join(sharedSocketDir, name+".sock")
+}
+
+// pidPath returns the path to the file recording the listener's PID.
+func pidPath(name string) string {
+	return socketPath(name) + ".pid"
+}
+
+// listen creates a Unix domain socket listener; only AllSessions of lc
+// applies to it.
+func listen(name string, lc *ListenConfig) (*Listener, error) {
+	path, perm := socketPath(name), os.FileMode(0700)
+	if lc.AllSessions {
+		if err := os.Mkdir(sharedSocketDir, 0755); err != nil && !os.IsExist(err) {
+			return nil, fmt.Errorf("oscompat/localnet: failed to create shared socket directory: %w", err)
+		}
+		if err := checkRootOwned(sharedSocketDir); err != nil {
+			return nil, err
+		}
+		path, perm = sharedSocketPath(name), 0666
+	}
 
 	// Remove existing socket if present
 	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
@@ -39,42 +69,115 @@ func listen(name string) (*Listener, error) {
 		return nil, fmt.Errorf("oscompat/localnet: failed to listen: %w", err)
 	}
 
-	// Set permissions to owner-only for security
-	if err := os.Chmod(path, 0700); err != nil {
+	// Set permissions to owner-only for security, unless the endpoint
+	// is meant for all users
+	if err := os.Chmod(path, perm); err != nil {
 		_ = l.Close()
 		_ = os.Remove(path)
 		return nil, fmt.Errorf("oscompat/localnet: failed to set socket permissions: %w", err)
 	}
 
+	// Record our PID so List can report the owner of the socket
+	pidFile := path + ".pid"
+	if err := os.WriteFile(pidFile, []byte(strconv.Itoa(os.Getpid())), 0600); err != nil {
+		_ = l.Close()
+		_ = os.Remove(path)
+		return nil, fmt.Errorf("oscompat/localnet: failed to write pid file: %w", err)
+	}
+
 	return &Listener{
 		Listener: l,
 		name:     name,
 		cleanup: func() error {
-			err := os.Remove(path)
-			if os.IsNotExist(err) {
-				return nil // Already cleaned up
+			err := removeIfExists(path)
+			if pidErr := removeIfExists(pidFile); err == nil {
+				err = pidErr
 			}
 			return err
 		},
 	}, nil
 }
 
-// dial connects to a Unix domain socket.
-func dial(name string) (net.Conn, error) {
+// dial connects to a Unix domain socket, in the shared socket directory
+// if dc.AllSessions is set.
+func dial(ctx context.Context, name string, dc *DialConfig) (net.Conn, error) {
 	path := socketPath(name)
-	conn, err := net.Dial("unix", path)
+	if dc.AllSessions {
+		path = sharedSocketPath(name)
+		if err := checkRootOwned(sharedSocketDir); err != nil {
+			return nil, err
+		}
+		if err := checkRootOwned(path); err != nil {
+			return nil, err
+		}
+	}
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "unix", path)
 	if err != nil {
 		return nil, fmt.Errorf("oscompat/localnet: failed to connect: %w", err)
 	}
 	return conn, nil
 }
 
-// cleanup removes the socket file.
+// cleanup removes the socket file and its PID file, in both the
+// per-user and the shared directory.
 func cleanup(name string) error {
-	path := socketPath(name)
+	var err error
+	for _, path := range []string{socketPath(name), sharedSocketPath(name)} {
+		for _, p := range []string{path, path + ".pid"} {
+			if rmErr := removeIfExists(p); err == nil {
+				err = rmErr
+			}
+		}
+	}
+	return err
+}
+
+// checkRootOwned returns ErrUntrusted unless path is owned by root and
+// not writable by group or others. Symbolic links are not followed.
+func checkRootOwned(path string) error {
+	info, err := os.Lstat(path)
+	if err != nil {
+		return fmt.Errorf("oscompat/localnet: %w", err)
+	}
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok || st.Uid != 0 || info.Mode()&os.ModeSymlink != 0 ||
+		(info.IsDir() && info.Mode().Perm()&0022 != 0) {
+		return fmt.Errorf("%w: %s", ErrUntrusted, path)
+	}
+	return nil
+}
+
+// removeIfExists removes a file, ignoring the error if it does not exist.
+func removeIfExists(path string) error {
 	err := os.Remove(path)
 	if os.IsNotExist(err) {
 		return nil // Already cleaned up
 	}
 	return err
 }
+
+// list scans the socket directory for sockets with a PID file.
+func list() ([]Endpoint, error) {
+	entries, err := os.ReadDir(socketDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("oscompat/localnet: failed to read socket directory: %w", err)
+	}
+
+	var endpoints []Endpoint
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".sock.pid")
+		if !ok || name == "" || entry.IsDir() {
+			continue
+		}
+		ep := Endpoint{Name: name, Path: socketPath(name)}
+		if data, err := os.ReadFile(pidPath(name)); err == nil {
+			ep.PID, _ = strconv.Atoi(strings.TrimSpace(string(data)))
+		}
+		endpoints = append(endpoints, ep)
+	}
+	return endpoints, nil
+}
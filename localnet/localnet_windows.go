@@ -3,6 +3,7 @@
 package localnet
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"os"
@@ -32,8 +33,16 @@ func socketPath(name string) string {
 	return portFilePath(name)
 }
 
-// listen creates a TCP listener on localhost and stores the port in a file.
-func listen(name string) (*Listener, error) {
+// listen creates a TCP listener on localhost and stores the port in a
+// file, or a named pipe if lc.AllSessions is set.
+func listen(name string, lc *ListenConfig) (*Listener, error) {
+	if lc.AllSessions {
+		l, err := listenPipe(name)
+		if err != nil {
+			return nil, err
+		}
+		return &Listener{Listener: l, name: name}, nil
+	}
 	portFile := portFilePath(name)
 
 	// Ensure directory exists
@@ -46,7 +55,7 @@ func listen(name string) (*Listener, error) {
 	os.Remove(portFile)
 
 	// Listen on localhost with any available port
-	l, err := net.Listen("tcp", "127.0.0.1:0")
+	l, addrs, err := listenLoopback(lc)
 	if err != nil {
 		return nil, fmt.Errorf("oscompat/localnet: failed to listen: %w", err)
 	}
@@ -55,8 +64,9 @@ func listen(name string) (*Listener, error) {
 	addr := l.Addr().(*net.TCPAddr)
 	port := addr.Port
 
-	// Write port to file
-	if err := os.WriteFile(portFile, []byte(strconv.Itoa(port)), 0600); err != nil {
+	// Write port and owner PID to file
+	content := strconv.Itoa(port) + "\n" + strconv.Itoa(os.Getpid()) + "\n"
+	if err := os.WriteFile(portFile, []byte(content), 0600); err != nil {
 		l.Close()
 		return nil, fmt.Errorf("oscompat/localnet: failed to write port file: %w", err)
 	}
@@ -64,6 +74,7 @@ func listen(name string) (*Listener, error) {
 	return &Listener{
 		Listener: l,
 		name:     name,
+		addrs:    addrs,
 		cleanup: func() error {
 			err := os.Remove(portFile)
 			if os.IsNotExist(err) {
@@ -74,27 +85,28 @@ func listen(name string) (*Listener, error) {
 	}, nil
 }
 
-// dial reads the port file and connects via TCP to localhost.
-func dial(name string) (net.Conn, error) {
-	portFile := portFilePath(name)
-
-	// Read port from file
-	data, err := os.ReadFile(portFile)
+// dial reads the port file and connects via TCP to localhost, or
+// connects to the named pipe if dc.AllSessions is set.
+func dial(ctx context.Context, name string, dc *DialConfig) (net.Conn, error) {
+	if dc.AllSessions {
+		return dialPipe(ctx, name)
+	}
+	port, _, err := readPortFile(portFilePath(name))
 	if err != nil {
 		return nil, fmt.Errorf("oscompat/localnet: failed to read port file: %w", err)
 	}
 
-	port := strings.TrimSpace(string(data))
-
 	// Connect to localhost on the specified port
-	conn, err := net.Dial("tcp", "127.0.0.1:"+port)
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", "127.0.0.1:"+port)
 	if err != nil {
 		return nil, fmt.Errorf("oscompat/localnet: failed to connect: %w", err)
 	}
 	return conn, nil
 }
 
-// cleanup removes the port file.
+// cleanup removes the port file. Named pipes vanish with their last
+// handle and need no cleanup.
 func cleanup(name string) error {
 	portFile := portFilePath(name)
 	err := os.Remove(portFile)
@@ -103,3 +115,43 @@ func cleanup(name string) error {
 	}
 	return err
 }
+
+// readPortFile parses a port file. The first line holds the port; the
+// optional second line holds the PID of the listener.
+func readPortFile(path string) (port string, pid int, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", 0, err
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	port = strings.TrimSpace(lines[0])
+	if len(lines) > 1 {
+		pid, _ = strconv.Atoi(strings.TrimSpace(lines[1]))
+	}
+	return port, pid, nil
+}
+
+// list scans the port file directory.
+func list() ([]Endpoint, error) {
+	entries, err := os.ReadDir(portFileDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("oscompat/localnet: failed to read port file directory: %w", err)
+	}
+
+	var endpoints []Endpoint
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), ".port")
+		if !ok || name == "" || entry.IsDir() {
+			continue
+		}
+		ep := Endpoint{Name: name, Path: portFilePath(name)}
+		if _, pid, err := readPortFile(ep.Path); err == nil {
+			ep.PID = pid
+		}
+		endpoints = append(endpoints, ep)
+	}
+	return endpoints, nil
+}
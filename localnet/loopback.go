@@ -0,0 +1,217 @@
+package localnet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+)
+
+// ErrNotLoopback is returned by ListenConfig.Listen with LoopbackOnly set
+// when a TCP listener ends up bound to an address other than loopback.
+var ErrNotLoopback = errors.New("oscompat/localnet: listener is not bound to a loopback address")
+
+// ListenConfig controls how Listen creates an endpoint. Most fields
+// concern the TCP endpoint used on Windows; Unix domain sockets never
+// touch the network and ignore them.
+//
+// Binding to a loopback literal rather than a wildcard address keeps the
+// Windows Defender Firewall from prompting the user.
+type ListenConfig struct {
+	// LoopbackOnly checks every address the listener bound after the
+	// fact and fails with ErrNotLoopback if one is not loopback, guarding
+	// against a resolver or network stack that maps the address
+	// unexpectedly.
+	LoopbackOnly bool
+
+	// IPv6 also binds ::1 on the same port, for clients that connect to
+	// "localhost" and resolve it to ::1. It is off by default because some
+	// corporate firewalls prompt for IPv6 listeners even on loopback. If
+	// ::1 is unavailable the listener quietly uses IPv4 only; Addrs tells
+	// which addresses were bound.
+	IPv6 bool
+
+	// AllSessions makes the endpoint reachable from every login session
+	// and user on the machine, such as between a Windows service running
+	// as SYSTEM, or a root daemon, and per-user agents. Clients must dial
+	// it with DialConfig.AllSessions; it is a separate namespace, which
+	// Dial never looks in.
+	//
+	// On Unix the socket is created in /var/run/oscompat, a directory
+	// only root can write, so the listener must run as root. On Windows
+	// the endpoint is a named pipe whose security descriptor lets
+	// authenticated users connect but only SYSTEM and Administrators
+	// create instances, so the listener must run as one of them;
+	// LoopbackOnly and IPv6 do not apply to it.
+	//
+	// Any local user can then connect, so the server must authenticate
+	// its clients.
+	AllSessions bool
+
+	// TrackConnections makes the listener keep track of the connections
+	// it accepts, so that Listener.Shutdown can wait for them and close
+	// the remaining ones. Accept then returns a wrapper rather than the
+	// *net.UnixConn or *net.TCPConn itself; Unwrap returns the latter.
+	TrackConnections bool
+}
+
+// Listen creates a local listener for IPC, like the package-level Listen.
+func (lc *ListenConfig) Listen(name string) (*Listener, error) {
+	if name == "" {
+		return nil, ErrInvalidName
+	}
+	l, err := listen(name, lc)
+	if err != nil {
+		return nil, err
+	}
+	l.track = lc.TrackConnections
+	return l, nil
+}
+
+// DialConfig controls how Dial connects to an endpoint.
+type DialConfig struct {
+	// AllSessions connects to an endpoint created with
+	// ListenConfig.AllSessions instead of one of the current user. The
+	// connection is refused with ErrUntrusted unless the endpoint is
+	// owned by root on Unix, or by SYSTEM or Administrators on Windows,
+	// so an ordinary user cannot impersonate the service.
+	AllSessions bool
+
+	// TrackConnections makes the listener keep track of the connections
+	// it accepts, so that Listener.Shutdown can wait for them and close
+	// the remaining ones. Accept then returns a wrapper rather than the
+	// *net.UnixConn or *net.TCPConn itself; Unwrap returns the latter.
+	TrackConnections bool
+}
+
+// Dial connects to a local IPC endpoint, like the package-level Dial.
+func (dc *DialConfig) Dial(name string) (net.Conn, error) {
+	return dc.DialContext(context.Background(), name)
+}
+
+// DialContext connects to a local IPC endpoint like Dial, giving up when
+// ctx is done.
+func (dc *DialConfig) DialContext(ctx context.Context, name string) (net.Conn, error) {
+	if name == "" {
+		return nil, ErrInvalidName
+	}
+	return dial(ctx, name, dc)
+}
+
+// Addrs returns every address the listener is bound to: the socket path
+// on Unix, and 127.0.0.1 followed by ::1 when ListenConfig.IPv6 took
+// effect on Windows. Addr returns the first of them.
+func (l *Listener) Addrs() []net.Addr {
+	if l.addrs == nil {
+		return []net.Addr{l.Addr()}
+	}
+	return l.addrs
+}
+
+// listenLoopback listens on an ephemeral port of 127.0.0.1 and, if
+// requested, the same port of ::1.
+func listenLoopback(lc *ListenConfig) (net.Listener, []net.Addr, error) {
+	l4, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		return nil, nil, err
+	}
+	ls := []net.Listener{l4}
+	if lc.IPv6 {
+		port := strconv.Itoa(l4.Addr().(*net.TCPAddr).Port)
+		if l6, err := net.Listen("tcp6", net.JoinHostPort("::1", port)); err == nil {
+			ls = append(ls, l6)
+		}
+	}
+
+	addrs := make([]net.Addr, len(ls))
+	for i, l := range ls {
+		addrs[i] = l.Addr()
+	}
+	if lc.LoopbackOnly {
+		for _, addr := range addrs {
+			if tcp, ok := addr.(*net.TCPAddr); !ok || !tcp.IP.IsLoopback() {
+				for _, l := range ls {
+					_ = l.Close()
+				}
+				return nil, nil, fmt.Errorf("%w: %s", ErrNotLoopback, addr)
+			}
+		}
+	}
+	if len(ls) == 1 {
+		return l4, addrs, nil
+	}
+	return newMultiListener(ls), addrs, nil
+}
+
+// multiListener accepts connections from several listeners.
+type multiListener struct {
+	ls    []net.Listener
+	conns chan accepted
+	done  chan struct{}
+	once  sync.Once
+}
+
+type accepted struct {
+	conn net.Conn
+	err  error
+}
+
+func newMultiListener(ls []net.Listener) *multiListener {
+	m := &multiListener{
+		ls:    ls,
+		conns: make(chan accepted),
+		done:  make(chan struct{}),
+	}
+	for _, l := range ls {
+		go m.serve(l)
+	}
+	return m
+}
+
+// serve forwards connections from l until it fails or m is closed.
+func (m *multiListener) serve(l net.Listener) {
+	for {
+		conn, err := l.Accept()
+		select {
+		case m.conns <- accepted{conn, err}:
+		case <-m.done:
+			if conn != nil {
+				_ = conn.Close()
+			}
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+func (m *multiListener) Accept() (net.Conn, error) {
+	select {
+	case a := <-m.conns:
+		return a.conn, a.err
+	case <-m.done:
+		return nil, net.ErrClosed
+	}
+}
+
+func (m *multiListener) Close() error {
+	err := net.ErrClosed
+	m.once.Do(func() {
+		err = nil
+		close(m.done)
+		for _, l := range m.ls {
+			if closeErr := l.Close(); err == nil {
+				err = closeErr
+			}
+		}
+	})
+	return err
+}
+
+// Addr returns the address of the first listener.
+func (m *multiListener) Addr() net.Addr {
+	return m.ls[0].Addr()
+}
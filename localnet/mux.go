@@ -0,0 +1,600 @@
+package localnet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrStreamReset is returned by Stream operations after the peer reset
+	// the stream, or refused to open it.
+	ErrStreamReset = errors.New("oscompat/localnet: stream reset by peer")
+
+	// ErrMuxProtocol is returned when the peer sends a malformed frame;
+	// the Mux is closed.
+	ErrMuxProtocol = errors.New("oscompat/localnet: mux protocol error")
+)
+
+// Frame layout: version, type, flags, stream ID (4 bytes), and length
+// (4 bytes), big-endian. For data frames the length is that of the
+// payload that follows; for window frames it is the window increment.
+const (
+	muxVersion    = 0
+	muxHeaderSize = 11
+
+	frameData   = 0
+	frameWindow = 1
+
+	flagSYN = 1 << 0 // opens a stream
+	flagFIN = 1 << 1 // the sender will write no more
+	flagRST = 1 << 2 // the stream is abandoned
+
+	// muxWindow is the number of unread bytes a stream buffers before its
+	// writer blocks, so that one slow reader does not stall the others.
+	muxWindow = 256 << 10
+
+	// muxMaxFrame bounds data frames, so that streams interleave.
+	muxMaxFrame = 16 << 10
+
+	// muxBacklog is the number of opened streams waiting for Accept;
+	// further streams are reset.
+	muxBacklog = 64
+
+	// muxMaxResets bounds the resets waiting to be written; a peer that
+	// provokes more without reading them is a protocol error.
+	muxMaxResets = 1024
+)
+
+// Mux carries many independent, bidirectional streams over one
+// connection, in the manner of yamux. Opening a named pipe or socket per
+// channel costs different amounts and hits different limits on each
+// platform; a Mux over one Dial'ed connection gives an agent dozens of
+// logical channels at the cost of two goroutines.
+//
+// Either side can Open streams; the other side Accepts them. Streams
+// have independent flow control, so a stream that is not being read
+// only blocks its own writer. Mux implements net.Listener, so it can be
+// passed to http.Serve or a gRPC server on the accepting side.
+type Mux struct {
+	conn    net.Conn
+	writeMu sync.Mutex
+
+	mu      sync.Mutex
+	streams map[uint32]*Stream
+	nextID  uint32
+	accept  chan *Stream
+
+	// Resets provoked by incoming frames are written by writeResets, not
+	// by readLoop, so that reading never waits on a full connection.
+	resetMu    sync.Mutex
+	resets     []uint32
+	resetReady chan struct{}
+
+	done    chan struct{}
+	errOnce sync.Once
+	err     error
+}
+
+// NewMux starts multiplexing over conn, which the Mux then owns. The two
+// ends must pass different values of server: typically the side that
+// called Listen passes true and the side that called Dial passes false.
+func NewMux(conn net.Conn, server bool) *Mux {
+	m := &Mux{
+		conn:       conn,
+		streams:    make(map[uint32]*Stream),
+		nextID:     1,
+		accept:     make(chan *Stream, muxBacklog),
+		resetReady: make(chan struct{}, 1),
+		done:       make(chan struct{}),
+	}
+	if server {
+		m.nextID = 2
+	}
+	go m.readLoop()
+	go m.writeResets()
+	return m
+}
+
+// Open opens a new stream. It does not wait for the peer to accept it;
+// writes are buffered by the peer until it does.
+func (m *Mux) Open() (*Stream, error) {
+	m.mu.Lock()
+	select {
+	case <-m.done:
+		m.mu.Unlock()
+		return nil, m.err
+	default:
+	}
+	id := m.nextID
+	if id+2 < id {
+		m.mu.Unlock()
+		return nil, errors.New("oscompat/localnet: mux stream IDs exhausted")
+	}
+	m.nextID += 2
+	s := m.newStream(id)
+	m.mu.Unlock()
+
+	if err := m.writeFrame(frameWindow, flagSYN, id, nil, 0); err != nil {
+		m.remove(id)
+		return nil, err
+	}
+	return s, nil
+}
+
+// AcceptStream waits for the peer to open a stream.
+func (m *Mux) AcceptStream() (*Stream, error) {
+	select {
+	case s := <-m.accept:
+		return s, nil
+	case <-m.done:
+		return nil, m.err
+	}
+}
+
+// Accept waits for the peer to open a stream and returns it as a
+// net.Conn; it is a *Stream.
+func (m *Mux) Accept() (net.Conn, error) {
+	s, err := m.AcceptStream()
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Addr returns the local address of the underlying connection.
+func (m *Mux) Addr() net.Addr {
+	return m.conn.LocalAddr()
+}
+
+// NumStreams returns the number of open streams.
+func (m *Mux) NumStreams() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.streams)
+}
+
+// Done returns a channel that is closed when the Mux stops, because it
+// was closed or the connection failed; Err then reports why.
+func (m *Mux) Done() <-chan struct{} {
+	return m.done
+}
+
+// Err returns nil while the Mux runs, net.ErrClosed after Close, and
+// otherwise the error that stopped it.
+func (m *Mux) Err() error {
+	select {
+	case <-m.done:
+		return m.err
+	default:
+		return nil
+	}
+}
+
+// Close closes the connection and with it every stream.
+func (m *Mux) Close() error {
+	m.fail(net.ErrClosed)
+	return nil
+}
+
+// fail stops the Mux with err, once.
+func (m *Mux) fail(err error) {
+	m.errOnce.Do(func() {
+		m.err = err
+		close(m.done)
+		_ = m.conn.Close()
+	})
+}
+
+// newStream registers a stream; m.mu must be held.
+func (m *Mux) newStream(id uint32) *Stream {
+	s := &Stream{
+		id:         id,
+		m:          m,
+		sendWindow: muxWindow,
+		recvAvail:  muxWindow,
+		readReady:  make(chan struct{}, 1),
+		writeReady: make(chan struct{}, 1),
+		closed:     make(chan struct{}),
+	}
+	m.streams[id] = s
+	return s
+}
+
+// remove forgets the stream id.
+func (m *Mux) remove(id uint32) {
+	m.mu.Lock()
+	delete(m.streams, id)
+	m.mu.Unlock()
+}
+
+// writeFrame sends a data frame with payload, or a window frame with the
+// increment n.
+func (m *Mux) writeFrame(typ, flags byte, id uint32, payload []byte, n uint32) error {
+	if typ == frameData {
+		n = uint32(len(payload))
+	}
+	hdr := make([]byte, muxHeaderSize)
+	hdr[0], hdr[1], hdr[2] = muxVersion, typ, flags
+	binary.BigEndian.PutUint32(hdr[3:], id)
+	binary.BigEndian.PutUint32(hdr[7:], n)
+
+	m.writeMu.Lock()
+	defer m.writeMu.Unlock()
+	select {
+	case <-m.done:
+		return m.err
+	default:
+	}
+	bufs := net.Buffers{hdr, payload}
+	if _, err := bufs.WriteTo(m.conn); err != nil {
+		m.fail(fmt.Errorf("oscompat/localnet: mux connection lost: %w", err))
+		return m.err
+	}
+	return nil
+}
+
+// readLoop reads frames and dispatches them until the connection fails.
+func (m *Mux) readLoop() {
+	hdr := make([]byte, muxHeaderSize)
+	for {
+		if _, err := io.ReadFull(m.conn, hdr); err != nil {
+			m.fail(fmt.Errorf("oscompat/localnet: mux connection lost: %w", err))
+			return
+		}
+		typ, flags := hdr[1], hdr[2]
+		id := binary.BigEndian.Uint32(hdr[3:])
+		length := binary.BigEndian.Uint32(hdr[7:])
+		if hdr[0] != muxVersion || typ > frameWindow || id == 0 ||
+			(typ == frameData && length > muxMaxFrame) {
+			m.fail(fmt.Errorf("%w: bad frame header %x", ErrMuxProtocol, hdr))
+			return
+		}
+		var payload []byte
+		if typ == frameData && length > 0 {
+			payload = make([]byte, length)
+			if _, err := io.ReadFull(m.conn, payload); err != nil {
+				m.fail(fmt.Errorf("oscompat/localnet: mux connection lost: %w", err))
+				return
+			}
+		}
+		if err := m.dispatch(typ, flags, id, length, payload); err != nil {
+			m.fail(err)
+			return
+		}
+	}
+}
+
+// dispatch applies one frame to its stream.
+func (m *Mux) dispatch(typ, flags byte, id, length uint32, payload []byte) error {
+	m.mu.Lock()
+	s := m.streams[id]
+	if flags&flagSYN != 0 {
+		if id%2 == m.nextID%2 {
+			m.mu.Unlock()
+			return fmt.Errorf("%w: stream %d opened with an ID of this side", ErrMuxProtocol, id)
+		}
+		if s != nil {
+			m.mu.Unlock()
+			return fmt.Errorf("%w: stream %d opened twice", ErrMuxProtocol, id)
+		}
+		s = m.newStream(id)
+		select {
+		case m.accept <- s:
+		default:
+			// Backlog full: refuse the stream.
+			delete(m.streams, id)
+			m.mu.Unlock()
+			return m.queueReset(id)
+		}
+	}
+	m.mu.Unlock()
+
+	if s == nil {
+		// The stream was closed here; tell a peer still writing to it.
+		if len(payload) > 0 && flags&flagRST == 0 {
+			return m.queueReset(id)
+		}
+		return nil
+	}
+
+	s.mu.Lock()
+	if typ == frameData {
+		if length > s.recvAvail {
+			s.mu.Unlock()
+			return fmt.Errorf("%w: stream %d exceeded its window", ErrMuxProtocol, id)
+		}
+		s.recvAvail -= length
+		s.buf.Write(payload)
+	} else {
+		s.sendWindow += length
+		signal(s.writeReady)
+	}
+	if flags&flagFIN != 0 {
+		s.recvFin = true
+	}
+	if flags&flagRST != 0 {
+		s.reset = true
+		s.closeOnce.Do(func() { close(s.closed) })
+	}
+	done := s.reset || (s.recvFin && s.sentFin)
+	s.mu.Unlock()
+	signal(s.readReady)
+	if done {
+		m.remove(id)
+	}
+	return nil
+}
+
+// queueReset has writeResets reset the stream id.
+func (m *Mux) queueReset(id uint32) error {
+	m.resetMu.Lock()
+	defer m.resetMu.Unlock()
+	if len(m.resets) >= muxMaxResets {
+		return fmt.Errorf("%w: too many resets pending", ErrMuxProtocol)
+	}
+	m.resets = append(m.resets, id)
+	signal(m.resetReady)
+	return nil
+}
+
+// writeResets writes queued resets until the Mux stops.
+func (m *Mux) writeResets() {
+	for {
+		select {
+		case <-m.resetReady:
+		case <-m.done:
+			return
+		}
+		m.resetMu.Lock()
+		ids := m.resets
+		m.resets = nil
+		m.resetMu.Unlock()
+		for _, id := range ids {
+			if err := m.writeFrame(frameWindow, flagRST, id, nil, 0); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// signal wakes one waiter on ch without blocking.
+func signal(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
+// Stream is one logical connection of a Mux. It implements net.Conn,
+// including deadlines and, with CloseWrite, half-close.
+type Stream struct {
+	id uint32
+	m  *Mux
+
+	mu         sync.Mutex
+	buf        bytes.Buffer
+	recvAvail  uint32 // bytes the peer may still send
+	consumed   uint32 // bytes read since the last window update
+	sendWindow uint32 // bytes this side may still send
+	recvFin    bool
+	sentFin    bool
+	reset      bool
+	rdeadline  time.Time
+	wdeadline  time.Time
+
+	readReady  chan struct{}
+	writeReady chan struct{}
+	closed     chan struct{} // closed by Close or a reset
+	closeOnce  sync.Once
+}
+
+// ID returns the stream identifier: odd for streams the client side
+// opened, even for the server side's.
+func (s *Stream) ID() uint32 {
+	return s.id
+}
+
+// Read reads data the peer wrote. It returns io.EOF after the peer
+// closed the stream or called CloseWrite, and the buffered data is read.
+func (s *Stream) Read(p []byte) (int, error) {
+	for {
+		s.mu.Lock()
+		if s.buf.Len() > 0 {
+			n, _ := s.buf.Read(p)
+			s.consumed += uint32(n)
+			var update uint32
+			if s.consumed >= muxWindow/2 && !s.recvFin {
+				update, s.consumed = s.consumed, 0
+				s.recvAvail += update
+			}
+			s.mu.Unlock()
+			if update > 0 {
+				_ = s.m.writeFrame(frameWindow, 0, s.id, nil, update)
+			}
+			return n, nil
+		}
+		err := s.stateErr()
+		if err == nil && s.recvFin {
+			err = io.EOF
+		}
+		deadline := s.rdeadline
+		s.mu.Unlock()
+		if err != nil {
+			signal(s.readReady) // wake any other reader
+			return 0, err
+		}
+		if err := s.wait(s.readReady, deadline); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// Write writes p to the stream, blocking while the peer's window for
+// the stream is full.
+func (s *Stream) Write(p []byte) (int, error) {
+	var written int
+	for written < len(p) {
+		s.mu.Lock()
+		err := s.stateErr()
+		if err == nil && s.sentFin {
+			err = net.ErrClosed
+		}
+		if err != nil {
+			s.mu.Unlock()
+			return written, err
+		}
+		if s.sendWindow == 0 {
+			deadline := s.wdeadline
+			s.mu.Unlock()
+			if err := s.wait(s.writeReady, deadline); err != nil {
+				return written, err
+			}
+			continue
+		}
+		n := min(len(p)-written, int(s.sendWindow), muxMaxFrame)
+		s.sendWindow -= uint32(n)
+		if s.sendWindow > 0 {
+			signal(s.writeReady) // wake any other writer
+		}
+		s.mu.Unlock()
+
+		if err := s.m.writeFrame(frameData, 0, s.id, p[written:written+n], 0); err != nil {
+			return written, err
+		}
+		written += n
+	}
+	return written, nil
+}
+
+// stateErr returns the error for a closed or reset stream; s.mu must be
+// held.
+func (s *Stream) stateErr() error {
+	select {
+	case <-s.m.done:
+		return s.m.err
+	default:
+	}
+	switch {
+	case s.reset:
+		return ErrStreamReset
+	case isClosed(s.closed):
+		return net.ErrClosed
+	}
+	return nil
+}
+
+// wait blocks until ch is signalled, the stream or Mux closes, or the
+// deadline passes.
+func (s *Stream) wait(ch chan struct{}, deadline time.Time) error {
+	var timeout <-chan time.Time
+	if !deadline.IsZero() {
+		d := time.Until(deadline)
+		if d <= 0 {
+			return os.ErrDeadlineExceeded
+		}
+		t := time.NewTimer(d)
+		defer t.Stop()
+		timeout = t.C
+	}
+	select {
+	case <-ch:
+	case <-s.closed:
+	case <-s.m.done:
+	case <-timeout:
+		return os.ErrDeadlineExceeded
+	}
+	return nil
+}
+
+// CloseWrite tells the peer this side will write no more; the peer's
+// reads return io.EOF. The stream can still be read.
+func (s *Stream) CloseWrite() error {
+	s.mu.Lock()
+	if err := s.stateErr(); err != nil || s.sentFin {
+		s.mu.Unlock()
+		return err
+	}
+	s.sentFin = true
+	done := s.recvFin
+	s.mu.Unlock()
+	err := s.m.writeFrame(frameWindow, flagFIN, s.id, nil, 0)
+	if done {
+		s.m.remove(s.id)
+	}
+	return err
+}
+
+// Close closes both directions of the stream. Data the peer sends
+// afterwards is discarded and its writes fail with ErrStreamReset.
+func (s *Stream) Close() error {
+	s.mu.Lock()
+	if isClosed(s.closed) {
+		s.mu.Unlock()
+		return nil
+	}
+	s.closeOnce.Do(func() { close(s.closed) })
+	sendFin := !s.sentFin && !s.reset
+	s.sentFin = true
+	s.mu.Unlock()
+
+	s.m.remove(s.id)
+	if sendFin {
+		return s.m.writeFrame(frameWindow, flagFIN, s.id, nil, 0)
+	}
+	return nil
+}
+
+// LocalAddr returns the local address of the Mux connection.
+func (s *Stream) LocalAddr() net.Addr {
+	return s.m.conn.LocalAddr()
+}
+
+// RemoteAddr returns the remote address of the Mux connection.
+func (s *Stream) RemoteAddr() net.Addr {
+	return s.m.conn.RemoteAddr()
+}
+
+// SetDeadline sets the read and write deadlines.
+func (s *Stream) SetDeadline(t time.Time) error {
+	if err := s.SetReadDeadline(t); err != nil {
+		return err
+	}
+	return s.SetWriteDeadline(t)
+}
+
+// SetReadDeadline sets the deadline for Read calls, including blocked
+// ones.
+func (s *Stream) SetReadDeadline(t time.Time) error {
+	s.mu.Lock()
+	s.rdeadline = t
+	s.mu.Unlock()
+	signal(s.readReady)
+	return nil
+}
+
+// SetWriteDeadline sets the deadline for Write calls, including blocked
+// ones.
+func (s *Stream) SetWriteDeadline(t time.Time) error {
+	s.mu.Lock()
+	s.wdeadline = t
+	s.mu.Unlock()
+	signal(s.writeReady)
+	return nil
+}
+
+// isClosed reports whether ch is closed.
+func isClosed(ch chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,192 @@
+package localnet_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grokify/oscompat/localnet"
+)
+
+// muxPair returns the two ends of a Mux over an in-memory connection.
+func muxPair(t *testing.T) (client, server *localnet.Mux) {
+	t.Helper()
+	a, b := net.Pipe()
+	client, server = localnet.NewMux(a, false), localnet.NewMux(b, true)
+	t.Cleanup(func() {
+		_ = client.Close()
+		_ = server.Close()
+	})
+	return client, server
+}
+
+// echo serves streams accepted from m by copying their input back.
+func echo(m *localnet.Mux) {
+	for {
+		s, err := m.AcceptStream()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer func() { _ = s.Close() }()
+			_, _ = io.Copy(s, s)
+			_ = s.CloseWrite()
+		}()
+	}
+}
+
+func TestMux(t *testing.T) {
+	client, server := muxPair(t)
+	go echo(server)
+
+	t.Run("concurrent streams", func(t *testing.T) {
+		// Each payload exceeds the window, so flow control must work.
+		payload := bytes.Repeat([]byte("0123456789abcdef"), 40<<10)
+		var wg sync.WaitGroup
+		for range 20 {
+			wg.Go(func() {
+				s, err := client.Open()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				defer func() { _ = s.Close() }()
+				go func() {
+					_, _ = s.Write(payload)
+					_ = s.CloseWrite()
+				}()
+				got, err := io.ReadAll(s)
+				if err != nil {
+					t.Error(err)
+				} else if !bytes.Equal(got, payload) {
+					t.Errorf("stream %d echoed %d bytes, want %d", s.ID(), len(got), len(payload))
+				}
+			})
+		}
+		wg.Wait()
+	})
+
+	t.Run("stalled stream", func(t *testing.T) {
+		// Fill a stream that the server never reads.
+		stalled, err := server.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = stalled.Close() }()
+		_ = stalled.SetWriteDeadline(time.Now().Add(200 * time.Millisecond))
+		if _, err := stalled.Write(make([]byte, 1<<20)); !errors.Is(err, os.ErrDeadlineExceeded) {
+			t.Fatalf("Write() to full window = %v, want ErrDeadlineExceeded", err)
+		}
+
+		s, err := client.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = s.Close() }()
+		if _, err := s.Write([]byte("ping")); err != nil {
+			t.Fatal(err)
+		}
+		_ = s.SetReadDeadline(time.Now().Add(5 * time.Second))
+		buf := make([]byte, 4)
+		if _, err := io.ReadFull(s, buf); err != nil || string(buf) != "ping" {
+			t.Errorf("Read() = %q, %v", buf, err)
+		}
+	})
+
+	t.Run("read deadline", func(t *testing.T) {
+		s, err := client.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer func() { _ = s.Close() }()
+		_ = s.SetReadDeadline(time.Now().Add(50 * time.Millisecond))
+		if _, err := s.Read(make([]byte, 1)); !errors.Is(err, os.ErrDeadlineExceeded) {
+			t.Errorf("Read() = %v, want ErrDeadlineExceeded", err)
+		}
+	})
+
+	t.Run("close", func(t *testing.T) {
+		if err := client.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := client.Open(); !errors.Is(err, net.ErrClosed) {
+			t.Errorf("Open() after Close = %v, want net.ErrClosed", err)
+		}
+		select {
+		case <-server.Done():
+			if server.Err() == nil {
+				t.Error("peer Err() = nil after the connection closed")
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("peer Mux did not stop")
+		}
+		if _, err := server.Accept(); err == nil {
+			t.Error("peer Accept() succeeded after the connection closed")
+		}
+	})
+}
+
+// rawFrame encodes a mux frame header followed by payload.
+func rawFrame(typ, flags byte, id uint32, payload []byte) []byte {
+	hdr := []byte{0, typ, flags, 0, 0, 0, 0, 0, 0, 0, 0}
+	binary.BigEndian.PutUint32(hdr[3:], id)
+	binary.BigEndian.PutUint32(hdr[7:], uint32(len(payload)))
+	return append(hdr, payload...)
+}
+
+func TestMuxPeerFrames(t *testing.T) {
+	const (
+		frameData   = 0
+		frameWindow = 1
+		flagSYN     = 1
+	)
+
+	t.Run("resets do not block reading", func(t *testing.T) {
+		// The peer never reads, so a reset written from the read loop
+		// would block it and the stream opened after never arrives.
+		a, b := net.Pipe()
+		m := localnet.NewMux(a, true)
+		t.Cleanup(func() { _ = m.Close(); _ = b.Close() })
+		go func() {
+			_, _ = b.Write(rawFrame(frameData, 0, 5, []byte("x")))
+			_, _ = b.Write(rawFrame(frameWindow, flagSYN, 7, nil))
+		}()
+
+		accepted := make(chan error, 1)
+		go func() {
+			_, err := m.AcceptStream()
+			accepted <- err
+		}()
+		select {
+		case err := <-accepted:
+			if err != nil {
+				t.Errorf("AcceptStream() error: %v", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("stream not accepted while a reset was pending")
+		}
+	})
+
+	t.Run("wrong parity", func(t *testing.T) {
+		a, b := net.Pipe()
+		m := localnet.NewMux(a, true)
+		t.Cleanup(func() { _ = m.Close(); _ = b.Close() })
+		// Stream IDs of the server side are even.
+		go func() { _, _ = b.Write(rawFrame(frameWindow, flagSYN, 2, nil)) }()
+
+		select {
+		case <-m.Done():
+			if !errors.Is(m.Err(), localnet.ErrMuxProtocol) {
+				t.Errorf("Err() = %v, want ErrMuxProtocol", m.Err())
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("Mux accepted a stream with its own ID parity")
+		}
+	})
+}
@@ -0,0 +1,269 @@
+//go:build windows
+
+package localnet
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+var (
+	advapi32 = syscall.NewLazyDLL("advapi32.dll")
+
+	procCreateNamedPipeW = kernel32.NewProc("CreateNamedPipeW")
+	procConnectNamedPipe = kernel32.NewProc("ConnectNamedPipe")
+	procWaitNamedPipeW   = kernel32.NewProc("WaitNamedPipeW")
+	procCreateEventW     = kernel32.NewProc("CreateEventW")
+	procGetOverlapped    = kernel32.NewProc("GetOverlappedResult")
+
+	procConvertSDDL     = advapi32.NewProc("ConvertStringSecurityDescriptorToSecurityDescriptorW")
+	procGetSecurityInfo = advapi32.NewProc("GetSecurityInfo")
+)
+
+const (
+	pipeAccessDuplex          = 0x00000003
+	fileFlagFirstPipeInstance = 0x00080000
+	pipeRejectRemoteClients   = 0x00000008
+	pipeUnlimitedInstances    = 255
+	pipeBufferSize            = 64 << 10
+
+	securitySQOSPresent    = 0x00100000
+	securityIdentification = 0x00010000
+	seFileObject           = 1
+	ownerSecurityInfo      = 0x00000001
+	sddlRevision1          = 1
+	errorPipeBusy          = syscall.Errno(231)
+	errorPipeConnected     = syscall.Errno(535)
+	pipeBusyTimeoutMillis  = 5000
+	pipeClientAccess       = 0x00120183 // read and write data and attributes, READ_CONTROL, SYNCHRONIZE
+)
+
+// pipeSDDL is the security descriptor of AllSessions pipes: SYSTEM and
+// Administrators have full access; other authenticated users may read,
+// write, and inspect the pipe but not create instances of it, which
+// FILE_CREATE_PIPE_INSTANCE (part of GENERIC_WRITE) would allow.
+const pipeSDDL = "D:P(A;;GA;;;SY)(A;;GA;;;BA)(A;;0x120183;;;AU)"
+
+// trustedPipeOwners are the SIDs of SYSTEM and Administrators, the
+// owners DialConfig.AllSessions accepts.
+var trustedPipeOwners = []string{"S-1-5-18", "S-1-5-32-544"}
+
+// pipePath returns the named pipe used for name with AllSessions. Pipe
+// names are global, so every session sees the same one.
+func pipePath(name string) string {
+	return `\\.\pipe\oscompat-localnet-` + name
+}
+
+// pipeAddr is the address of a named pipe endpoint.
+type pipeAddr string
+
+func (a pipeAddr) Network() string { return "pipe" }
+func (a pipeAddr) String() string  { return string(a) }
+
+// pipeConn is a connected named pipe. The handle is opened for
+// overlapped I/O, so os.File supports deadlines on it.
+type pipeConn struct {
+	*os.File
+	addr pipeAddr
+}
+
+func (c *pipeConn) LocalAddr() net.Addr  { return c.addr }
+func (c *pipeConn) RemoteAddr() net.Addr { return c.addr }
+
+// pipeListener accepts connections on a named pipe. One instance of the
+// pipe always waits for the next client.
+type pipeListener struct {
+	path   string
+	path16 *uint16
+	sa     syscall.SecurityAttributes
+
+	acceptMu sync.Mutex // serializes Accept
+	mu       sync.Mutex // guards next and closed
+	next     syscall.Handle
+	closed   bool
+}
+
+// listenPipe creates the first instance of the pipe for name. It fails
+// if the pipe already exists, so a squatter cannot pose as the server.
+func listenPipe(name string) (*pipeListener, error) {
+	path := pipePath(name)
+	path16, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return nil, err
+	}
+	sddl, err := syscall.UTF16PtrFromString(pipeSDDL)
+	if err != nil {
+		return nil, err
+	}
+	var sd uintptr
+	r, _, err := procConvertSDDL.Call(uintptr(unsafe.Pointer(sddl)), sddlRevision1, uintptr(unsafe.Pointer(&sd)), 0)
+	if r == 0 {
+		return nil, fmt.Errorf("oscompat/localnet: failed to create security descriptor: %w", err)
+	}
+	l := &pipeListener{path: path, path16: path16}
+	l.sa = syscall.SecurityAttributes{
+		Length:             uint32(unsafe.Sizeof(l.sa)),
+		SecurityDescriptor: sd,
+	}
+	l.next, err = l.create(true)
+	if err != nil {
+		_, _ = syscall.LocalFree(syscall.Handle(sd))
+		return nil, err
+	}
+	return l, nil
+}
+
+// create creates an instance of the pipe.
+func (l *pipeListener) create(first bool) (syscall.Handle, error) {
+	mode := uintptr(pipeAccessDuplex | syscall.FILE_FLAG_OVERLAPPED)
+	if first {
+		mode |= fileFlagFirstPipeInstance
+	}
+	r, _, err := procCreateNamedPipeW.Call(uintptr(unsafe.Pointer(l.path16)), mode,
+		pipeRejectRemoteClients, pipeUnlimitedInstances, pipeBufferSize, pipeBufferSize, 0,
+		uintptr(unsafe.Pointer(&l.sa)))
+	if syscall.Handle(r) == syscall.InvalidHandle {
+		return 0, fmt.Errorf("oscompat/localnet: failed to create named pipe: %w", err)
+	}
+	return syscall.Handle(r), nil
+}
+
+// Accept waits for a client to connect to the waiting instance, then
+// creates the next one.
+func (l *pipeListener) Accept() (net.Conn, error) {
+	l.acceptMu.Lock()
+	defer l.acceptMu.Unlock()
+
+	r, _, err := procCreateEventW.Call(0, 1, 0, 0)
+	if r == 0 {
+		return nil, fmt.Errorf("oscompat/localnet: failed to create event: %w", err)
+	}
+	event := syscall.Handle(r)
+	defer func() { _ = syscall.CloseHandle(event) }()
+
+	// Start the wait while holding mu, so that Close either sees it
+	// pending and cancels it, or comes first and is seen here.
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return nil, net.ErrClosed
+	}
+	h := l.next
+	ov := syscall.Overlapped{HEvent: event}
+	r, _, err = procConnectNamedPipe.Call(uintptr(h), uintptr(unsafe.Pointer(&ov)))
+	l.mu.Unlock()
+
+	if r == 0 && err == syscall.ERROR_IO_PENDING {
+		var n uint32
+		r, _, err = procGetOverlapped.Call(uintptr(h), uintptr(unsafe.Pointer(&ov)), uintptr(unsafe.Pointer(&n)), 1)
+	}
+	if r == 0 && err != errorPipeConnected {
+		if err == syscall.ERROR_OPERATION_ABORTED {
+			return nil, net.ErrClosed
+		}
+		return nil, fmt.Errorf("oscompat/localnet: failed to accept: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return nil, net.ErrClosed
+	}
+	next, err := l.create(false)
+	if err != nil {
+		return nil, err
+	}
+	l.next = next
+	return &pipeConn{File: os.NewFile(uintptr(h), l.path), addr: pipeAddr(l.path)}, nil
+}
+
+// Close cancels a pending Accept and closes the waiting instance.
+// Connections already accepted stay open.
+func (l *pipeListener) Close() error {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return net.ErrClosed
+	}
+	l.closed = true
+	_ = syscall.CancelIoEx(l.next, nil)
+	l.mu.Unlock()
+
+	l.acceptMu.Lock()
+	defer l.acceptMu.Unlock()
+	err := syscall.CloseHandle(l.next)
+	_, _ = syscall.LocalFree(syscall.Handle(l.sa.SecurityDescriptor))
+	return err
+}
+
+// Addr returns the path of the pipe.
+func (l *pipeListener) Addr() net.Addr {
+	return pipeAddr(l.path)
+}
+
+// dialPipe connects to the pipe for name and checks that a trusted
+// account owns it.
+func dialPipe(ctx context.Context, name string) (net.Conn, error) {
+	path := pipePath(name)
+	path16, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return nil, err
+	}
+	var h syscall.Handle
+	for {
+		// SECURITY_IDENTIFICATION lets the server learn who the client
+		// is but not act as the client.
+		h, err = syscall.CreateFile(path16, pipeClientAccess, 0, nil, syscall.OPEN_EXISTING,
+			syscall.FILE_FLAG_OVERLAPPED|securitySQOSPresent|securityIdentification, 0)
+		if err != errorPipeBusy {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("oscompat/localnet: failed to connect: %w", err)
+		}
+		timeout := time.Duration(pipeBusyTimeoutMillis) * time.Millisecond
+		if deadline, ok := ctx.Deadline(); ok {
+			timeout = max(min(timeout, time.Until(deadline)), time.Millisecond)
+		}
+		if r, _, err := procWaitNamedPipeW.Call(uintptr(unsafe.Pointer(path16)), uintptr(timeout.Milliseconds())); r == 0 {
+			return nil, fmt.Errorf("oscompat/localnet: failed to connect: %w", err)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("oscompat/localnet: failed to connect: %w", err)
+	}
+	if err := checkPipeOwner(h, path); err != nil {
+		_ = syscall.CloseHandle(h)
+		return nil, err
+	}
+	return &pipeConn{File: os.NewFile(uintptr(h), path), addr: pipeAddr(path)}, nil
+}
+
+// checkPipeOwner returns ErrUntrusted unless the owner of the pipe h is
+// SYSTEM or Administrators.
+func checkPipeOwner(h syscall.Handle, path string) error {
+	var owner *syscall.SID
+	var sd uintptr
+	r, _, _ := procGetSecurityInfo.Call(uintptr(h), seFileObject, ownerSecurityInfo,
+		uintptr(unsafe.Pointer(&owner)), 0, 0, 0, uintptr(unsafe.Pointer(&sd)))
+	if r != 0 {
+		return fmt.Errorf("oscompat/localnet: failed to read pipe owner: %w", syscall.Errno(r))
+	}
+	defer func() { _, _ = syscall.LocalFree(syscall.Handle(sd)) }()
+	sid, err := owner.String()
+	if err != nil {
+		return err
+	}
+	for _, trusted := range trustedPipeOwners {
+		if sid == trusted {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s is owned by %s", ErrUntrusted, path, sid)
+}
@@ -0,0 +1,129 @@
+package localnet
+
+import (
+	"errors"
+	"strings"
+	"sync"
+)
+
+// ErrInvalidSize is returned when a shared memory segment is requested
+// with a size less than or equal to zero.
+var ErrInvalidSize = errors.New("oscompat/localnet: size must be positive")
+
+// ErrInvalidSegmentName is returned when a shared memory segment name
+// contains a path separator, a NUL byte, or "..", any of which could make
+// the segment resolve outside its directory or namespace.
+var ErrInvalidSegmentName = errors.New("oscompat/localnet: invalid shared memory name")
+
+// ErrSegmentNotOwned is returned on Unix when the backing file of a
+// shared memory segment is a link, belongs to another user, or is
+// accessible to other users.
+var ErrSegmentNotOwned = errors.New("oscompat/localnet: shared memory is not owned by the current user")
+
+// Segment is a named shared memory segment mapped into the current process.
+//
+// A Segment is backed by shm_open-style storage on Unix (a file in /dev/shm
+// on Linux, or in the socket directory elsewhere) mapped with mmap, and by a
+// named file mapping (CreateFileMapping) on Windows. Access from multiple
+// processes is coordinated with Lock and Unlock.
+type Segment struct {
+	name string
+	data []byte
+	mu   sync.Mutex // serializes Lock within this process
+	seg  segment
+}
+
+// SharedMem creates or opens the named shared memory segment and maps
+// size bytes of it into the current process.
+//
+// Processes that call SharedMem with the same name share the same memory.
+// The name must not contain "/", "\\", NUL, or "..".
+// If the segment already exists and is smaller than size, it is grown on
+// Unix; on Windows the size of an existing segment cannot change, so size
+// must not exceed the size it was created with.
+//
+// Call Close to unmap the segment. On Unix the backing storage persists until
+// CleanupSharedMem is called; on Windows it is released when the last process
+// closes it.
+func SharedMem(name string, size int) (*Segment, error) {
+	if name == "" {
+		return nil, ErrInvalidName
+	}
+	if !validSegmentName(name) {
+		return nil, ErrInvalidSegmentName
+	}
+	if size <= 0 {
+		return nil, ErrInvalidSize
+	}
+	seg, data, err := openSegment(name, size)
+	if err != nil {
+		return nil, err
+	}
+	return &Segment{name: name, data: data, seg: seg}, nil
+}
+
+// CleanupSharedMem removes the backing storage for the named segment.
+// Processes that still have the segment mapped keep their mapping.
+// It is a no-op on Windows, where segments are reference counted by the OS.
+func CleanupSharedMem(name string) error {
+	if name == "" {
+		return ErrInvalidName
+	}
+	if !validSegmentName(name) {
+		return ErrInvalidSegmentName
+	}
+	return cleanupSegment(name)
+}
+
+// validSegmentName reports whether name can be used as a segment name
+// on every platform: as a file name on Unix and as a kernel object name,
+// where a backslash separates namespaces, on Windows.
+func validSegmentName(name string) bool {
+	return !strings.ContainsAny(name, "/\\\x00") && !strings.Contains(name, "..")
+}
+
+// Name returns the name used to create this segment.
+func (s *Segment) Name() string {
+	return s.name
+}
+
+// Bytes returns the mapped memory. The slice is only valid until Close.
+func (s *Segment) Bytes() []byte {
+	return s.data
+}
+
+// Size returns the number of mapped bytes.
+func (s *Segment) Size() int {
+	return len(s.data)
+}
+
+// Lock acquires the cross-process lock associated with the segment,
+// blocking until it is available. The lock also excludes other goroutines
+// in the current process.
+//
+// On Windows the lock is a named mutex owned by an OS thread, so Unlock must
+// be called from the same goroutine that called Lock.
+func (s *Segment) Lock() error {
+	s.mu.Lock()
+	if err := s.seg.lock(); err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// Unlock releases the lock acquired by Lock.
+func (s *Segment) Unlock() error {
+	err := s.seg.unlock()
+	s.mu.Unlock()
+	return err
+}
+
+// Close unmaps the segment from the current process.
+func (s *Segment) Close() error {
+	if s.data == nil {
+		return nil
+	}
+	s.data = nil
+	return s.seg.close()
+}
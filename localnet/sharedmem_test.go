@@ -0,0 +1,98 @@
+package localnet_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grokify/oscompat/localnet"
+)
+
+func TestSharedMem(t *testing.T) {
+	name := "oscompat-shm-test-" + time.Now().Format("20060102150405")
+	defer func() { _ = localnet.CleanupSharedMem(name) }()
+
+	writer, err := localnet.SharedMem(name, 4096)
+	if err != nil {
+		t.Fatalf("SharedMem() error: %v", err)
+	}
+	defer func() { _ = writer.Close() }()
+
+	if writer.Name() != name {
+		t.Errorf("Name() = %q, want %q", writer.Name(), name)
+	}
+	if writer.Size() != 4096 {
+		t.Errorf("Size() = %d, want 4096", writer.Size())
+	}
+
+	message := "hello from shared memory"
+	copy(writer.Bytes(), message)
+
+	// A second mapping of the same name sees the same memory
+	reader, err := localnet.SharedMem(name, 4096)
+	if err != nil {
+		t.Fatalf("SharedMem() second open error: %v", err)
+	}
+	defer func() { _ = reader.Close() }()
+
+	if got := string(reader.Bytes()[:len(message)]); got != message {
+		t.Errorf("reader saw %q, want %q", got, message)
+	}
+}
+
+func TestSharedMemLock(t *testing.T) {
+	name := "oscompat-shm-lock-test-" + time.Now().Format("20060102150405")
+	defer func() { _ = localnet.CleanupSharedMem(name) }()
+
+	seg, err := localnet.SharedMem(name, 8)
+	if err != nil {
+		t.Fatalf("SharedMem() error: %v", err)
+	}
+	defer func() { _ = seg.Close() }()
+
+	const (
+		goroutines = 10
+		increments = 100
+	)
+
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < increments; j++ {
+				if err := seg.Lock(); err != nil {
+					t.Errorf("Lock() error: %v", err)
+					return
+				}
+				seg.Bytes()[0]++
+				if err := seg.Unlock(); err != nil {
+					t.Errorf("Unlock() error: %v", err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got, want := int(seg.Bytes()[0]), (goroutines*increments)%256; got != want {
+		t.Errorf("counter = %d, want %d", got, want)
+	}
+}
+
+func TestSharedMemInvalid(t *testing.T) {
+	if _, err := localnet.SharedMem("", 16); err != localnet.ErrInvalidName {
+		t.Errorf("SharedMem('') = %v, want ErrInvalidName", err)
+	}
+	if _, err := localnet.SharedMem("oscompat-shm-invalid", 0); err != localnet.ErrInvalidSize {
+		t.Errorf("SharedMem(size 0) = %v, want ErrInvalidSize", err)
+	}
+	for _, name := range []string{"../escape", "a/b", `a\b`, "..", "a\x00b"} {
+		if _, err := localnet.SharedMem(name, 16); err != localnet.ErrInvalidSegmentName {
+			t.Errorf("SharedMem(%q) = %v, want ErrInvalidSegmentName", name, err)
+		}
+		if err := localnet.CleanupSharedMem(name); err != localnet.ErrInvalidSegmentName {
+			t.Errorf("CleanupSharedMem(%q) = %v, want ErrInvalidSegmentName", name, err)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+//go:build !windows
+
+package localnet
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"syscall"
+)
+
+// segment holds the Unix state for a shared memory segment.
+type segment struct {
+	file *os.File
+	data []byte
+}
+
+// shmDir returns the directory for shared memory backing files.
+func shmDir() string {
+	// /dev/shm is what shm_open uses on Linux (tmpfs, never hits disk)
+	if runtime.GOOS == "linux" {
+		if info, err := os.Stat("/dev/shm"); err == nil && info.IsDir() {
+			return "/dev/shm"
+		}
+	}
+	return socketDir()
+}
+
+// shmPath returns the path to the backing file for a segment.
+func shmPath(name string) string {
+	return filepath.Join(shmDir(), name+".shm")
+}
+
+// openSegment opens or creates the backing file and maps it. /dev/shm is
+// shared by all users, so a link or a file another user created first is
+// refused with ErrSegmentNotOwned.
+func openSegment(name string, size int) (segment, []byte, error) {
+	path := shmPath(name)
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|syscall.O_NOFOLLOW, 0600)
+	if err != nil {
+		if errors.Is(err, syscall.ELOOP) {
+			return segment{}, nil, fmt.Errorf("%w: %s", ErrSegmentNotOwned, path)
+		}
+		return segment{}, nil, fmt.Errorf("oscompat/localnet: failed to open shared memory: %w", err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return segment{}, nil, fmt.Errorf("oscompat/localnet: failed to open shared memory: %w", err)
+	}
+	st, ok := info.Sys().(*syscall.Stat_t)
+	if !ok || int(st.Uid) != os.Geteuid() || info.Mode().Perm()&0077 != 0 {
+		_ = f.Close()
+		return segment{}, nil, fmt.Errorf("%w: %s", ErrSegmentNotOwned, path)
+	}
+	if info.Size() < int64(size) {
+		if err := f.Truncate(int64(size)); err != nil {
+			_ = f.Close()
+			return segment{}, nil, fmt.Errorf("oscompat/localnet: failed to size shared memory: %w", err)
+		}
+	}
+
+	data, err := syscall.Mmap(int(f.Fd()), 0, size, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+	if err != nil {
+		_ = f.Close()
+		return segment{}, nil, fmt.Errorf("oscompat/localnet: failed to map shared memory: %w", err)
+	}
+	return segment{file: f, data: data}, data, nil
+}
+
+// cleanupSegment removes the backing file.
+func cleanupSegment(name string) error {
+	return removeIfExists(shmPath(name))
+}
+
+// lock takes an exclusive flock on the backing file.
+func (s *segment) lock() error {
+	return flock(s.file, syscall.LOCK_EX)
+}
+
+// unlock releases the flock on the backing file.
+func (s *segment) unlock() error {
+	return flock(s.file, syscall.LOCK_UN)
+}
+
+// close unmaps the memory and closes the backing file.
+func (s *segment) close() error {
+	err := syscall.Munmap(s.data)
+	if closeErr := s.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// flock applies a flock operation, retrying when interrupted by a signal.
+func flock(f *os.File, how int) error {
+	for {
+		err := syscall.Flock(int(f.Fd()), how)
+		if err != syscall.EINTR {
+			return err
+		}
+	}
+}
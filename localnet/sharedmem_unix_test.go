@@ -0,0 +1,48 @@
+//go:build !windows
+
+package localnet_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/grokify/oscompat/localnet"
+)
+
+func TestSharedMemNotOwned(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("backing file location is only known on Linux")
+	}
+	if info, err := os.Stat("/dev/shm"); err != nil || !info.IsDir() {
+		t.Skip("/dev/shm not available")
+	}
+
+	name := "oscompat-shm-notowned"
+	path := filepath.Join("/dev/shm", name+".shm")
+	t.Cleanup(func() { _ = os.Remove(path) })
+
+	// A link planted by someone else must not be followed.
+	if err := os.Symlink(filepath.Join(t.TempDir(), "target"), path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := localnet.SharedMem(name, 16); !errors.Is(err, localnet.ErrSegmentNotOwned) {
+		t.Errorf("SharedMem(link) = %v, want ErrSegmentNotOwned", err)
+	}
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+
+	// Nor a file other users can read.
+	if err := os.WriteFile(path, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(path, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := localnet.SharedMem(name, 16); !errors.Is(err, localnet.ErrSegmentNotOwned) {
+		t.Errorf("SharedMem(0644 file) = %v, want ErrSegmentNotOwned", err)
+	}
+}
@@ -0,0 +1,110 @@
+//go:build windows
+
+package localnet
+
+import (
+	"fmt"
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	kernel32         = syscall.NewLazyDLL("kernel32.dll")
+	procCreateMutexW = kernel32.NewProc("CreateMutexW")
+	procReleaseMutex = kernel32.NewProc("ReleaseMutex")
+)
+
+const waitAbandoned = 0x00000080
+
+// segment holds the Windows state for a shared memory segment.
+type segment struct {
+	mapping syscall.Handle
+	mutex   syscall.Handle
+	addr    uintptr
+}
+
+// segmentObjectName returns the kernel object name for a segment.
+// Objects in the Local namespace are scoped to the current session.
+func segmentObjectName(name, suffix string) string {
+	return `Local\oscompat-localnet-` + name + suffix
+}
+
+// openSegment creates or opens a named file mapping backed by the page file.
+func openSegment(name string, size int) (segment, []byte, error) {
+	mapName, err := syscall.UTF16PtrFromString(segmentObjectName(name, ""))
+	if err != nil {
+		return segment{}, nil, err
+	}
+	mutexName, err := syscall.UTF16PtrFromString(segmentObjectName(name, "-mutex"))
+	if err != nil {
+		return segment{}, nil, err
+	}
+
+	size64 := uint64(size)
+	mapping, err := syscall.CreateFileMapping(syscall.InvalidHandle, nil, syscall.PAGE_READWRITE,
+		uint32(size64>>32), uint32(size64), mapName)
+	if mapping == 0 {
+		return segment{}, nil, fmt.Errorf("oscompat/localnet: failed to create shared memory: %w", err)
+	}
+
+	addr, err := syscall.MapViewOfFile(mapping, syscall.FILE_MAP_READ|syscall.FILE_MAP_WRITE, 0, 0, uintptr(size))
+	if addr == 0 {
+		_ = syscall.CloseHandle(mapping)
+		return segment{}, nil, fmt.Errorf("oscompat/localnet: failed to map shared memory: %w", err)
+	}
+
+	r, _, err := procCreateMutexW.Call(0, 0, uintptr(unsafe.Pointer(mutexName)))
+	if r == 0 {
+		_ = syscall.UnmapViewOfFile(addr)
+		_ = syscall.CloseHandle(mapping)
+		return segment{}, nil, fmt.Errorf("oscompat/localnet: failed to create shared memory mutex: %w", err)
+	}
+
+	data := unsafe.Slice((*byte)(*(*unsafe.Pointer)(unsafe.Pointer(&addr))), size)
+	return segment{mapping: mapping, mutex: syscall.Handle(r), addr: addr}, data, nil
+}
+
+// cleanupSegment is a no-op on Windows; the mapping is released by the OS
+// when its last handle is closed.
+func cleanupSegment(name string) error {
+	return nil
+}
+
+// lock waits for the named mutex. The calling goroutine is wired to its OS
+// thread until unlock because Windows mutexes are owned by threads.
+func (s *segment) lock() error {
+	runtime.LockOSThread()
+	event, err := syscall.WaitForSingleObject(s.mutex, syscall.INFINITE)
+	switch event {
+	case syscall.WAIT_OBJECT_0, waitAbandoned:
+		// An abandoned mutex means its previous owner exited while holding
+		// it; ownership still passes to us.
+		return nil
+	default:
+		runtime.UnlockOSThread()
+		return fmt.Errorf("oscompat/localnet: failed to lock shared memory: %w", err)
+	}
+}
+
+// unlock releases the named mutex.
+func (s *segment) unlock() error {
+	defer runtime.UnlockOSThread()
+	r, _, err := procReleaseMutex.Call(uintptr(s.mutex))
+	if r == 0 {
+		return fmt.Errorf("oscompat/localnet: failed to unlock shared memory: %w", err)
+	}
+	return nil
+}
+
+// close unmaps the view and closes the mapping and mutex handles.
+func (s *segment) close() error {
+	err := syscall.UnmapViewOfFile(s.addr)
+	if closeErr := syscall.CloseHandle(s.mapping); err == nil {
+		err = closeErr
+	}
+	if closeErr := syscall.CloseHandle(s.mutex); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,53 @@
+//go:build darwin
+
+package netiface
+
+import (
+	"bufio"
+	"bytes"
+	"os/exec"
+	"strings"
+)
+
+// annotate sets display names from the hardware ports known to
+// networksetup, such as "Wi-Fi" for en0.
+func annotate(list []Interface) {
+	out, err := exec.Command("networksetup", "-listallhardwareports").Output()
+	if err != nil {
+		return
+	}
+	ports := parseHardwarePorts(out)
+	for i := range list {
+		if port, ok := ports[list[i].Name]; ok {
+			list[i].DisplayName = port
+		}
+	}
+}
+
+// parseHardwarePorts maps device names to hardware port names in the
+// output of networksetup -listallhardwareports, which holds blocks like:
+//
+//	Hardware Port: Wi-Fi
+//	Device: en0
+//	Ethernet Address: 3c:22:fb:00:00:00
+func parseHardwarePorts(out []byte) map[string]string {
+	ports := make(map[string]string)
+	port := ""
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		key, value, ok := strings.Cut(sc.Text(), ": ")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "Hardware Port":
+			port = value
+		case "Device":
+			if port != "" {
+				ports[value] = port
+			}
+			port = ""
+		}
+	}
+	return ports
+}
@@ -0,0 +1,6 @@
+//go:build !windows && !darwin
+
+package netiface
+
+// annotate leaves the kernel names as display names and IDs.
+func annotate([]Interface) {}
@@ -0,0 +1,104 @@
+//go:build windows
+
+package netiface
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+var (
+	iphlpapi                 = syscall.NewLazyDLL("iphlpapi.dll")
+	procGetAdaptersAddresses = iphlpapi.NewProc("GetAdaptersAddresses")
+)
+
+const (
+	gaaFlagSkipUnicast   = 0x1
+	gaaFlagSkipAnycast   = 0x2
+	gaaFlagSkipMulticast = 0x4
+	gaaFlagSkipDNSServer = 0x8
+
+	errorBufferOverflow syscall.Errno = 111
+)
+
+// adapterAddresses mirrors the leading fields of IP_ADAPTER_ADDRESSES_LH
+// from <iptypes.h>, up to Ipv6IfIndex. The buffer is allocated in full by
+// the caller, so the fields after it need not be declared.
+type adapterAddresses struct {
+	Length                uint32
+	IfIndex               uint32
+	Next                  *adapterAddresses
+	AdapterName           *byte
+	FirstUnicastAddress   uintptr
+	FirstAnycastAddress   uintptr
+	FirstMulticastAddress uintptr
+	FirstDNSServerAddress uintptr
+	DNSSuffix             *uint16
+	Description           *uint16
+	FriendlyName          *uint16
+	PhysicalAddress       [8]byte
+	PhysicalAddressLength uint32
+	Flags                 uint32
+	MTU                   uint32
+	IfType                uint32
+	OperStatus            uint32
+	Ipv6IfIndex           uint32
+}
+
+// annotate sets IDs to adapter GUIDs from GetAdaptersAddresses.
+func annotate(list []Interface) {
+	guids := adapterGUIDs()
+	for i := range list {
+		if guid, ok := guids[list[i].Index]; ok {
+			list[i].ID = guid
+		}
+	}
+}
+
+// adapterGUIDs maps interface indexes to adapter names, which are GUIDs
+// such as "{4D36E972-E325-11CE-BFC1-08002BE10318}".
+func adapterGUIDs() map[int]string {
+	flags := uint32(gaaFlagSkipUnicast | gaaFlagSkipAnycast | gaaFlagSkipMulticast | gaaFlagSkipDNSServer)
+	size := uint32(15000)
+	var buf []byte
+	for range 3 {
+		buf = make([]byte, size)
+		r, _, _ := procGetAdaptersAddresses.Call(syscall.AF_UNSPEC, uintptr(flags), 0,
+			uintptr(unsafe.Pointer(&buf[0])), uintptr(unsafe.Pointer(&size)))
+		if r == 0 {
+			break
+		}
+		if syscall.Errno(r) != errorBufferOverflow {
+			return nil
+		}
+		buf = nil
+	}
+	if buf == nil {
+		return nil
+	}
+
+	guids := make(map[int]string)
+	for aa := (*adapterAddresses)(unsafe.Pointer(&buf[0])); aa != nil; aa = aa.Next {
+		guid := cString(aa.AdapterName)
+		// IPv6-only adapters have no IPv4 index.
+		if aa.IfIndex != 0 {
+			guids[int(aa.IfIndex)] = guid
+		}
+		if aa.Ipv6IfIndex != 0 {
+			guids[int(aa.Ipv6IfIndex)] = guid
+		}
+	}
+	return guids
+}
+
+// cString converts a NUL-terminated byte string to a string.
+func cString(p *byte) string {
+	if p == nil {
+		return ""
+	}
+	var b []byte
+	for ; *p != 0; p = (*byte)(unsafe.Add(unsafe.Pointer(p), 1)) {
+		b = append(b, *p)
+	}
+	return string(b)
+}
@@ -0,0 +1,168 @@
+// Package netiface enumerates network interfaces and their addresses with
+// the same names and identifiers users and platform tools see.
+//
+// The standard library's net.Interfaces reports Windows adapters by
+// friendly name ("Ethernet") and Unix interfaces by kernel name ("eth0",
+// "en0"), with no link to the adapter GUIDs Windows uses elsewhere, and
+// mixes IPv4 and IPv6 addresses in one list. Interface gives every
+// platform a Name for networking tools, a DisplayName for people, and a
+// stable ID, with addresses split by family.
+package netiface
+
+import (
+	"errors"
+	"net"
+	"net/netip"
+	"slices"
+)
+
+// ErrNoPrimary is returned by Primary when no interface has a route to
+// the internet.
+var ErrNoPrimary = errors.New("oscompat/netiface: no primary interface")
+
+// Interface describes a network interface.
+type Interface struct {
+	// Index is the interface index, as in net.Interface.
+	Index int
+
+	// Name is the name used by the OS networking APIs and tools: "eth0"
+	// on Linux, "en0" on macOS, and the friendly name ("Ethernet") on
+	// Windows.
+	Name string
+
+	// DisplayName is the name shown in the OS settings: the hardware
+	// port name ("Wi-Fi") on macOS, and Name elsewhere.
+	DisplayName string
+
+	// ID is a stable identifier: the adapter GUID on Windows, which does
+	// not change when the user renames the adapter, and Name elsewhere.
+	ID string
+
+	// HardwareAddr is the MAC address, if any.
+	HardwareAddr net.HardwareAddr
+
+	// MTU is the maximum transmission unit.
+	MTU int
+
+	// Up reports whether the interface is administratively up.
+	Up bool
+
+	// Loopback reports whether the interface is a loopback interface.
+	Loopback bool
+
+	// IPv4 and IPv6 are the addresses of the interface with their
+	// prefix lengths.
+	IPv4 []netip.Prefix
+	IPv6 []netip.Prefix
+}
+
+// List returns the network interfaces, ordered by index.
+func List() ([]Interface, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+	list := make([]Interface, 0, len(ifaces))
+	for _, ni := range ifaces {
+		iface := Interface{
+			Index:        ni.Index,
+			Name:         ni.Name,
+			DisplayName:  ni.Name,
+			ID:           ni.Name,
+			HardwareAddr: ni.HardwareAddr,
+			MTU:          ni.MTU,
+			Up:           ni.Flags&net.FlagUp != 0,
+			Loopback:     ni.Flags&net.FlagLoopback != 0,
+		}
+		addrs, err := ni.Addrs()
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range addrs {
+			ipnet, ok := a.(*net.IPNet)
+			if !ok {
+				continue
+			}
+			addr, ok := netip.AddrFromSlice(ipnet.IP)
+			if !ok {
+				continue
+			}
+			ones, _ := ipnet.Mask.Size()
+			prefix := netip.PrefixFrom(addr.Unmap(), ones)
+			if prefix.Addr().Is4() {
+				iface.IPv4 = append(iface.IPv4, prefix)
+			} else {
+				iface.IPv6 = append(iface.IPv6, prefix)
+			}
+		}
+		list = append(list, iface)
+	}
+	annotate(list)
+	slices.SortFunc(list, func(a, b Interface) int {
+		return a.Index - b.Index
+	})
+	return list, nil
+}
+
+// Primary returns the interface of the default route: the one outgoing
+// internet traffic uses. It sends no packets.
+func Primary() (*Interface, error) {
+	list, err := List()
+	if err != nil {
+		return nil, err
+	}
+	for _, target := range []string{"192.0.2.1:9", "[2001:db8::1]:9"} {
+		local, ok := routeSource(target)
+		if !ok {
+			continue
+		}
+		for i := range list {
+			if hasAddr(list[i], local) {
+				return &list[i], nil
+			}
+		}
+	}
+	return nil, ErrNoPrimary
+}
+
+// routeSource returns the local address the OS would use to reach target.
+// Connecting a UDP socket only selects a route; nothing is sent.
+func routeSource(target string) (netip.Addr, bool) {
+	conn, err := net.Dial("udp", target)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	defer func() { _ = conn.Close() }()
+	ap, err := netip.ParseAddrPort(conn.LocalAddr().String())
+	if err != nil || ap.Addr().IsUnspecified() {
+		return netip.Addr{}, false
+	}
+	return ap.Addr().Unmap().WithZone(""), true
+}
+
+// hasAddr reports whether iface has the address addr.
+func hasAddr(iface Interface, addr netip.Addr) bool {
+	for _, p := range slices.Concat(iface.IPv4, iface.IPv6) {
+		if p.Addr().WithZone("") == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// FreePort returns a TCP port on the loopback interface that was free
+// when checked. Another process may take it before it is used, so prefer
+// listening on port 0 wherever the listener can report its port; use
+// FreePort when a port must be chosen before the listener starts, such
+// as for a child process's command line.
+func FreePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	port := l.Addr().(*net.TCPAddr).Port
+	if err := l.Close(); err != nil {
+		return 0, err
+	}
+	return port, nil
+}
@@ -0,0 +1,76 @@
+package netiface_test
+
+import (
+	"errors"
+	"net"
+	"strconv"
+	"testing"
+
+	"github.com/grokify/oscompat/netiface"
+)
+
+func TestList(t *testing.T) {
+	list, err := netiface.List()
+	if err != nil {
+		t.Fatalf("List() error: %v", err)
+	}
+	var loopback bool
+	for i, iface := range list {
+		if iface.Name == "" || iface.DisplayName == "" || iface.ID == "" {
+			t.Errorf("interface %d has empty names: %+v", iface.Index, iface)
+		}
+		if i > 0 && list[i-1].Index >= iface.Index {
+			t.Errorf("List() not ordered by index")
+		}
+		for _, p := range iface.IPv4 {
+			if !p.Addr().Is4() {
+				t.Errorf("%s: IPv4 holds %v", iface.Name, p)
+			}
+		}
+		for _, p := range iface.IPv6 {
+			if !p.Addr().Is6() {
+				t.Errorf("%s: IPv6 holds %v", iface.Name, p)
+			}
+		}
+		if iface.Loopback {
+			for _, p := range iface.IPv4 {
+				if p.Addr().IsLoopback() {
+					loopback = true
+				}
+			}
+		}
+	}
+	if !loopback {
+		t.Error("List() has no loopback interface with an IPv4 loopback address")
+	}
+}
+
+func TestPrimary(t *testing.T) {
+	iface, err := netiface.Primary()
+	if errors.Is(err, netiface.ErrNoPrimary) {
+		t.Skip("no default route")
+	}
+	if err != nil {
+		t.Fatalf("Primary() error: %v", err)
+	}
+	if iface.Loopback {
+		t.Errorf("Primary() = loopback interface %s", iface.Name)
+	}
+	if len(iface.IPv4)+len(iface.IPv6) == 0 {
+		t.Errorf("Primary() = %s with no addresses", iface.Name)
+	}
+}
+
+func TestFreePort(t *testing.T) {
+	port, err := netiface.FreePort()
+	if err != nil {
+		t.Fatalf("FreePort() error: %v", err)
+	}
+	l, err := net.Listen("tcp", "127.0.0.1:"+strconv.Itoa(port))
+	if err != nil {
+		t.Fatalf("listening on free port %d: %v", port, err)
+	}
+	if err := l.Close(); err != nil {
+		t.Error(err)
+	}
+}
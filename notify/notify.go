@@ -0,0 +1,65 @@
+// Package notify provides cross-platform desktop notifications.
+//
+// Platform behavior:
+//   - Windows: toast notifications, shown through PowerShell and the
+//     Windows Runtime notification API (Windows 10 and later).
+//   - macOS: Notification Center through osascript's "display
+//     notification". Notifications appear under Script Editor; an app
+//     bundle with its own identity needs the UserNotifications framework,
+//     which requires cgo.
+//   - Linux and BSD: the org.freedesktop.Notifications D-Bus service,
+//     spoken to directly over the session bus.
+//
+// Notifications are best effort: a user may have disabled them, and
+// headless sessions have no notification server. Check Supported before
+// relying on them.
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrUnsupported is returned when notifications cannot be shown in the
+// current session. It matches errors.ErrUnsupported.
+var ErrUnsupported = fmt.Errorf("oscompat/notify: %w", errors.ErrUnsupported)
+
+// Options customizes a notification. The zero value is valid.
+type Options struct {
+	// AppName identifies the sender to the Linux notification server.
+	AppName string
+
+	// AppID is the Windows AppUserModelID the toast is shown under. It
+	// must belong to an installed app (a Start menu shortcut carrying the
+	// ID). The default is Windows PowerShell's ID.
+	AppID string
+
+	// Icon is an icon name from the freedesktop icon theme or an absolute
+	// path to an image. It is used on Linux only.
+	Icon string
+
+	// Urgent asks the notification server to keep the notification on
+	// screen until dismissed. It is used on Linux only.
+	Urgent bool
+
+	// Timeout is how long the notification stays on screen. Zero leaves
+	// it to the notification server. It is used on Linux only.
+	Timeout time.Duration
+}
+
+// Supported reports whether notifications can be shown in the current
+// session.
+func Supported() bool {
+	return supported()
+}
+
+// Send shows a notification with the given title and body. opts may be
+// nil. Title and body are passed as data, never interpreted by a shell
+// or script.
+func Send(title, body string, opts *Options) error {
+	if opts == nil {
+		opts = &Options{}
+	}
+	return send(title, body, opts)
+}
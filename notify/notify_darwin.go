@@ -0,0 +1,36 @@
+//go:build darwin
+
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// notifyScript shows a notification with the title and body passed as
+// arguments, so that neither is parsed as AppleScript.
+const notifyScript = `on run argv
+	display notification (item 2 of argv) with title (item 1 of argv)
+end run`
+
+// supported reports whether osascript is available.
+func supported() bool {
+	_, err := exec.LookPath("osascript")
+	return err == nil
+}
+
+// send runs notifyScript with osascript.
+func send(title, body string, opts *Options) error {
+	var stderr bytes.Buffer
+	cmd := exec.Command("osascript", "-e", notifyScript, title, body)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("oscompat/notify: osascript: %s", msg)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package notify_test
+
+import (
+	"bufio"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/grokify/oscompat/notify"
+)
+
+// startBus starts a private session bus with no notification server.
+func startBus(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		t.Skip("D-Bus is used on Linux and BSD only")
+	}
+	if _, err := exec.LookPath("dbus-daemon"); err != nil {
+		t.Skip("dbus-daemon not installed")
+	}
+	cmd := exec.Command("dbus-daemon", "--session", "--nofork", "--print-address")
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Skipf("cannot start dbus-daemon: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	})
+	addr, err := bufio.NewReader(out).ReadString('\n')
+	if err != nil {
+		t.Fatalf("reading bus address: %v", err)
+	}
+	t.Setenv("DBUS_SESSION_BUS_ADDRESS", strings.TrimSpace(addr))
+}
+
+func TestNoServer(t *testing.T) {
+	startBus(t)
+	if notify.Supported() {
+		t.Error("Supported() = true on a bus without a notification server")
+	}
+	err := notify.Send("title", "body", &notify.Options{Urgent: true})
+	if err == nil || !strings.Contains(err.Error(), "ServiceUnknown") {
+		t.Errorf("Send() error = %v, want ServiceUnknown", err)
+	}
+}
+
+func TestNoBus(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		t.Skip("D-Bus is used on Linux and BSD only")
+	}
+	t.Setenv("DBUS_SESSION_BUS_ADDRESS", "unix:path="+t.TempDir()+"/missing")
+	if notify.Supported() {
+		t.Error("Supported() = true without a session bus")
+	}
+	if err := notify.Send("title", "body", nil); err == nil {
+		t.Error("Send() without a session bus should return error")
+	}
+}
+
+func TestSend(t *testing.T) {
+	// Notifications interrupt whoever runs the tests; send one only when
+	// asked.
+	if os.Getenv("OSCOMPAT_TEST_NOTIFY") != "1" {
+		t.Skip("set OSCOMPAT_TEST_NOTIFY=1 to show a real notification")
+	}
+	if !notify.Supported() {
+		t.Skip("notifications not supported")
+	}
+	err := notify.Send(`oscompat "test"`, "héllo 世界 $(echo injected) 'quoted'", &notify.Options{AppName: "oscompat"})
+	if err != nil {
+		t.Fatalf("Send() error: %v", err)
+	}
+}
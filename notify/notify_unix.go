@@ -0,0 +1,54 @@
+//go:build !windows && !darwin
+
+package notify
+
+import (
+	"errors"
+
+	"github.com/grokify/oscompat/internal/dbus"
+)
+
+const (
+	notifyService   = "org.freedesktop.Notifications"
+	notifyPath      = "/org/freedesktop/Notifications"
+	notifyInterface = "org.freedesktop.Notifications"
+)
+
+// urgencyCritical is the value of the "urgency" hint for notifications
+// that stay until dismissed.
+const urgencyCritical = 2
+
+// supported reports whether a notification server is running on the
+// session bus or can be started by it.
+func supported() bool {
+	c, err := dbus.SessionBus()
+	if err != nil {
+		return false
+	}
+	defer func() { _ = c.Close() }()
+	return c.HasService(notifyService)
+}
+
+// send calls Notify on the notification server.
+func send(title, body string, opts *Options) error {
+	c, err := dbus.SessionBus()
+	if errors.Is(err, dbus.ErrNoBus) {
+		return ErrUnsupported
+	}
+	if err != nil {
+		return err
+	}
+	defer func() { _ = c.Close() }()
+
+	hints := dbus.ByteDict{}
+	if opts.Urgent {
+		hints["urgency"] = urgencyCritical
+	}
+	timeout := int32(-1) // server default
+	if opts.Timeout > 0 {
+		timeout = int32(opts.Timeout.Milliseconds())
+	}
+	_, err = c.Call(notifyService, notifyPath, notifyInterface, "Notify", "susssasa{sv}i",
+		opts.AppName, uint32(0), opts.Icon, title, body, []string{}, hints, timeout)
+	return err
+}
@@ -0,0 +1,58 @@
+//go:build windows
+
+package notify
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// powerShellAppID is the AppUserModelID of Windows PowerShell, which is
+// registered on every Windows 10 and later system.
+const powerShellAppID = `{1AC14E77-02E7-4E5D-B744-2EB1AE5198B7}\WindowsPowerShell\v1.0\powershell.exe`
+
+// toastScript shows a ToastText02 toast. The title, body, and app ID are
+// read from environment variables so that they are never parsed as
+// PowerShell.
+const toastScript = `$ErrorActionPreference = 'Stop'
+[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] | Out-Null
+[Windows.Data.Xml.Dom.XmlDocument, Windows.Data.Xml.Dom.XmlDocument, ContentType = WindowsRuntime] | Out-Null
+$xml = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$text = $xml.GetElementsByTagName('text')
+$text.Item(0).AppendChild($xml.CreateTextNode($env:OSCOMPAT_NOTIFY_TITLE)) | Out-Null
+$text.Item(1).AppendChild($xml.CreateTextNode($env:OSCOMPAT_NOTIFY_BODY)) | Out-Null
+$toast = [Windows.UI.Notifications.ToastNotification]::new($xml)
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier($env:OSCOMPAT_NOTIFY_APPID).Show($toast)`
+
+// supported reports whether PowerShell is available.
+func supported() bool {
+	_, err := exec.LookPath("powershell.exe")
+	return err == nil
+}
+
+// send runs toastScript with PowerShell.
+func send(title, body string, opts *Options) error {
+	appID := opts.AppID
+	if appID == "" {
+		appID = powerShellAppID
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.Command("powershell.exe", "-NoProfile", "-NonInteractive", "-ExecutionPolicy", "Bypass", "-Command", toastScript)
+	cmd.Env = append(os.Environ(),
+		"OSCOMPAT_NOTIFY_TITLE="+title,
+		"OSCOMPAT_NOTIFY_BODY="+body,
+		"OSCOMPAT_NOTIFY_APPID="+appID,
+	)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("oscompat/notify: powershell: %s", msg)
+		}
+		return err
+	}
+	return nil
+}
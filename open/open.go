@@ -0,0 +1,106 @@
+// Package open opens URLs, files, and folders with the user's default
+// application, as double-clicking them would.
+//
+// Platform behavior:
+//   - Windows: ShellExecuteW for URLs and files, and
+//     SHOpenFolderAndSelectItems to reveal a file in Explorer.
+//   - macOS: open(1); FileInFolder uses "open -R" to reveal the file in
+//     Finder.
+//   - Linux and BSD: xdg-open, or "gio open" where xdg-open is missing.
+//     FileInFolder asks the file manager over D-Bus
+//     (org.freedesktop.FileManager1) to select the file, and falls back
+//     to opening the containing folder.
+//
+// Arguments are passed directly to the opener, never through a shell.
+// URLs must carry a scheme and files must exist, so neither can be
+// mistaken for a command-line option or a program to run. URL accepts
+// only web and mail links, since a file: URL would run a program just
+// as File does.
+package open
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+var (
+	// ErrUnsupported is returned when no opener is available, such as on
+	// a headless Linux system without xdg-open. It matches
+	// errors.ErrUnsupported.
+	ErrUnsupported = fmt.Errorf("oscompat/open: %w", errors.ErrUnsupported)
+
+	// ErrNoHandler is returned when no application is registered for the
+	// URL scheme or file type.
+	ErrNoHandler = errors.New("oscompat/open: no application for this type")
+
+	// ErrInvalidURL is returned by URL for strings without a scheme.
+	ErrInvalidURL = errors.New("oscompat/open: invalid URL")
+
+	// ErrSchemeNotAllowed is returned by URL and URLWithSchemes for a URL
+	// whose scheme is not allowed.
+	ErrSchemeNotAllowed = errors.New("oscompat/open: URL scheme not allowed")
+)
+
+// defaultSchemes are the URL schemes URL accepts.
+var defaultSchemes = []string{"http", "https", "mailto"}
+
+// URL opens u with the default handler for its scheme, usually the web
+// browser. Only http, https, and mailto URLs are accepted; others, such
+// as file: URLs, which the default handler may execute, return
+// ErrSchemeNotAllowed. Use URLWithSchemes for other schemes.
+func URL(u string) error {
+	return URLWithSchemes(u, defaultSchemes...)
+}
+
+// URLWithSchemes opens u like URL, accepting the given schemes instead,
+// such as the custom scheme of an application. Schemes are matched
+// without regard to case.
+func URLWithSchemes(u string, schemes ...string) error {
+	parsed, err := url.Parse(u)
+	// Require a scheme of at least two letters, so that a Windows drive
+	// letter is not taken for one.
+	if err != nil || len(parsed.Scheme) < 2 {
+		return fmt.Errorf("%w: %q", ErrInvalidURL, u)
+	}
+	if !slices.ContainsFunc(schemes, func(s string) bool { return strings.EqualFold(s, parsed.Scheme) }) {
+		return fmt.Errorf("%w: %q", ErrSchemeNotAllowed, parsed.Scheme)
+	}
+	return openURL(u)
+}
+
+// File opens the file or folder at path with its default application.
+func File(path string) error {
+	abs, err := existing(path)
+	if err != nil {
+		return err
+	}
+	return openFile(abs)
+}
+
+// FileInFolder shows the folder containing path in the file manager,
+// with path selected where the file manager supports it.
+func FileInFolder(path string) error {
+	abs, err := existing(path)
+	if err != nil {
+		return err
+	}
+	return reveal(abs)
+}
+
+// existing returns the absolute form of path, or an error if it does not
+// exist.
+func existing(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(abs); err != nil {
+		return "", err
+	}
+	return abs, nil
+}
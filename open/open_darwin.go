@@ -0,0 +1,43 @@
+//go:build darwin
+
+package open
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// openURL runs open with the URL.
+func openURL(u string) error {
+	return run(u)
+}
+
+// openFile runs open with the path.
+func openFile(path string) error {
+	return run(path)
+}
+
+// reveal runs open -R, which selects path in Finder.
+func reveal(path string) error {
+	return run("-R", path)
+}
+
+// run runs open(1) and converts its failures to errors.
+func run(args ...string) error {
+	var stderr bytes.Buffer
+	cmd := exec.Command("open", args...)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if strings.Contains(msg, "No application") {
+			return fmt.Errorf("%w: %s", ErrNoHandler, msg)
+		}
+		if msg != "" {
+			return fmt.Errorf("oscompat/open: open: %s", msg)
+		}
+		return fmt.Errorf("oscompat/open: open: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package open_test
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"testing"
+
+	"github.com/grokify/oscompat/open"
+)
+
+func TestURLInvalid(t *testing.T) {
+	for _, u := range []string{"", "example.com", "-x", `C:\Windows\notepad.exe`, "calc.exe"} {
+		if err := open.URL(u); !errors.Is(err, open.ErrInvalidURL) {
+			t.Errorf("URL(%q) error = %v, want ErrInvalidURL", u, err)
+		}
+	}
+}
+
+func TestURLScheme(t *testing.T) {
+	for _, u := range []string{"file:///C:/Windows/System32/calc.exe", "file:///usr/bin/xterm", "javascript:alert(1)", "ms-settings:"} {
+		if err := open.URL(u); !errors.Is(err, open.ErrSchemeNotAllowed) {
+			t.Errorf("URL(%q) error = %v, want ErrSchemeNotAllowed", u, err)
+		}
+	}
+	if err := open.URLWithSchemes("https://example.com", "myapp"); !errors.Is(err, open.ErrSchemeNotAllowed) {
+		t.Errorf("URLWithSchemes() error = %v, want ErrSchemeNotAllowed", err)
+	}
+}
+
+func TestFileMissing(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.txt")
+	if err := open.File(missing); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("File() error = %v, want ErrNotExist", err)
+	}
+	if err := open.FileInFolder(missing); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("FileInFolder() error = %v, want ErrNotExist", err)
+	}
+}
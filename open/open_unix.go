@@ -0,0 +1,96 @@
+//go:build !windows && !darwin
+
+package open
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/url"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/grokify/oscompat/internal/dbus"
+)
+
+// xdg-open exit statuses.
+const (
+	xdgNotFound     = 2
+	xdgToolNotFound = 3
+	xdgFailed       = 4
+)
+
+const (
+	fileManagerService   = "org.freedesktop.FileManager1"
+	fileManagerPath      = "/org/freedesktop/FileManager1"
+	fileManagerInterface = "org.freedesktop.FileManager1"
+)
+
+// openURL opens u with xdg-open.
+func openURL(u string) error {
+	return run(u)
+}
+
+// openFile opens path with xdg-open.
+func openFile(path string) error {
+	return run(path)
+}
+
+// reveal asks the file manager to select path, or opens its folder when
+// no file manager implements org.freedesktop.FileManager1.
+func reveal(path string) error {
+	if err := showItems(path); err == nil {
+		return nil
+	}
+	return run(filepath.Dir(path))
+}
+
+// showItems calls ShowItems on the file manager.
+func showItems(path string) error {
+	c, err := dbus.SessionBus()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = c.Close() }()
+	if !c.HasService(fileManagerService) {
+		return ErrUnsupported
+	}
+	uri := (&url.URL{Scheme: "file", Path: path}).String()
+	_, err = c.Call(fileManagerService, fileManagerPath, fileManagerInterface, "ShowItems", "ass",
+		[]string{uri}, "")
+	return err
+}
+
+// run opens target with xdg-open, or gio where xdg-open is missing.
+func run(target string) error {
+	var cmd *exec.Cmd
+	if _, err := exec.LookPath("xdg-open"); err == nil {
+		cmd = exec.Command("xdg-open", target)
+	} else if _, err := exec.LookPath("gio"); err == nil {
+		cmd = exec.Command("gio", "open", target)
+	} else {
+		return ErrUnsupported
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if err == nil {
+		return nil
+	}
+	msg := strings.TrimSpace(stderr.String())
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && cmd.Args[0] == "xdg-open" {
+		switch exitErr.ExitCode() {
+		case xdgToolNotFound:
+			return ErrUnsupported
+		case xdgNotFound, xdgFailed:
+			return fmt.Errorf("%w: %s", ErrNoHandler, msg)
+		}
+	}
+	if msg != "" {
+		return fmt.Errorf("oscompat/open: %s: %s", cmd.Args[0], msg)
+	}
+	return fmt.Errorf("oscompat/open: %s: %w", cmd.Args[0], err)
+}
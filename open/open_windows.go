@@ -0,0 +1,111 @@
+//go:build windows
+
+package open
+
+import (
+	"fmt"
+	"runtime"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	shell32                        = syscall.NewLazyDLL("shell32.dll")
+	ole32                          = syscall.NewLazyDLL("ole32.dll")
+	procShellExecuteW              = shell32.NewProc("ShellExecuteW")
+	procILCreateFromPathW          = shell32.NewProc("ILCreateFromPathW")
+	procILFree                     = shell32.NewProc("ILFree")
+	procSHOpenFolderAndSelectItems = shell32.NewProc("SHOpenFolderAndSelectItems")
+	procCoInitializeEx             = ole32.NewProc("CoInitializeEx")
+	procCoUninitialize             = ole32.NewProc("CoUninitialize")
+)
+
+const (
+	swShowNormal = 1
+
+	coinitApartmentThreaded = 0x2
+	coinitDisableOLE1DDE    = 0x4
+
+	// ShellExecute results at or below this value are errors.
+	seErrMax = 32
+
+	seErrAssocIncomplete = 27
+	seErrNoAssoc         = 31
+
+	errNotEnoughMemory syscall.Errno = 8
+)
+
+// openURL opens u with ShellExecuteW.
+func openURL(u string) error {
+	return shellExecute(u)
+}
+
+// openFile opens path with ShellExecuteW.
+func openFile(path string) error {
+	return shellExecute(path)
+}
+
+// shellExecute runs the default verb on target, as Explorer does on
+// double-click.
+func shellExecute(target string) error {
+	p, err := syscall.UTF16PtrFromString(target)
+	if err != nil {
+		return err
+	}
+	release := comInit()
+	defer release()
+
+	r, _, _ := procShellExecuteW.Call(0, 0, uintptr(unsafe.Pointer(p)), 0, 0, swShowNormal)
+	switch {
+	case r > seErrMax:
+		return nil
+	case r == seErrNoAssoc, r == seErrAssocIncomplete:
+		return ErrNoHandler
+	case r == 0:
+		return fmt.Errorf("oscompat/open: ShellExecute: %w", errNotEnoughMemory)
+	default:
+		// The remaining codes are Win32 error numbers, such as
+		// ERROR_FILE_NOT_FOUND and ERROR_ACCESS_DENIED.
+		return fmt.Errorf("oscompat/open: ShellExecute: %w", syscall.Errno(r))
+	}
+}
+
+// reveal opens an Explorer window on the folder of path with path
+// selected.
+func reveal(path string) error {
+	p, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return err
+	}
+	release := comInit()
+	defer release()
+
+	pidl, _, _ := procILCreateFromPathW.Call(uintptr(unsafe.Pointer(p)))
+	if pidl == 0 {
+		return fmt.Errorf("oscompat/open: cannot resolve %s", path)
+	}
+	defer func() { _, _, _ = procILFree.Call(pidl) }()
+
+	hr, _, _ := procSHOpenFolderAndSelectItems.Call(pidl, 0, 0, 0)
+	if hr != 0 {
+		return fmt.Errorf("oscompat/open: SHOpenFolderAndSelectItems: HRESULT %#x", uint32(hr))
+	}
+	return nil
+}
+
+// comInit initializes COM on the current OS thread, which shell calls
+// require, and returns a function that undoes it. The goroutine stays on
+// its thread until then.
+func comInit() func() {
+	runtime.LockOSThread()
+	hr, _, _ := procCoInitializeEx.Call(0, coinitApartmentThreaded|coinitDisableOLE1DDE)
+	// S_OK and S_FALSE must be balanced by CoUninitialize;
+	// RPC_E_CHANGED_MODE means COM is already usable on this thread.
+	if int32(hr) >= 0 {
+		return func() {
+			_, _, _ = procCoUninitialize.Call()
+			runtime.UnlockOSThread()
+		}
+	}
+	return runtime.UnlockOSThread
+}
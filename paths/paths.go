@@ -37,18 +37,7 @@ func Home() (string, error) {
 //   - macOS: ~/Library/Application Support/<appName>
 //   - Windows: %APPDATA%\<appName>
 func AppConfig(appName string) (string, error) {
-	if appName == "" {
-		return "", ErrInvalidAppName
-	}
-	base, err := UserConfig()
-	if err != nil {
-		return "", err
-	}
-	dir := filepath.Join(base, appName)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return "", err
-	}
-	return dir, nil
+	return Resolver{}.AppConfig(appName)
 }
 
 // AppData returns the app-specific data directory, creating it if needed.
@@ -57,18 +46,7 @@ func AppConfig(appName string) (string, error) {
 //   - macOS: ~/Library/Application Support/<appName>
 //   - Windows: %LOCALAPPDATA%\<appName>
 func AppData(appName string) (string, error) {
-	if appName == "" {
-		return "", ErrInvalidAppName
-	}
-	base, err := UserData()
-	if err != nil {
-		return "", err
-	}
-	dir := filepath.Join(base, appName)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return "", err
-	}
-	return dir, nil
+	return Resolver{}.AppData(appName)
 }
 
 // AppCache returns the app-specific cache directory, creating it if needed.
@@ -77,18 +55,16 @@ func AppData(appName string) (string, error) {
 //   - macOS: ~/Library/Caches/<appName>
 //   - Windows: %LOCALAPPDATA%\<appName>\cache
 func AppCache(appName string) (string, error) {
-	if appName == "" {
-		return "", ErrInvalidAppName
-	}
-	base, err := UserCache()
-	if err != nil {
-		return "", err
-	}
-	dir := filepath.Join(base, appName)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return "", err
-	}
-	return dir, nil
+	return Resolver{}.AppCache(appName)
+}
+
+// AppLogs returns the app-specific log directory, creating it if needed.
+// This is for log files and diagnostic output such as crash dumps.
+//   - Unix/Linux: ~/.local/state/<appName>
+//   - macOS: ~/Library/Logs/<appName>
+//   - Windows: %LOCALAPPDATA%\logs\<appName>
+func AppLogs(appName string) (string, error) {
+	return Resolver{}.AppLogs(appName)
 }
 
 // AppRuntime returns the app-specific runtime directory, creating it if needed.
@@ -97,18 +73,7 @@ func AppCache(appName string) (string, error) {
 //   - macOS: ~/Library/Application Support/<appName>/run
 //   - Windows: %LOCALAPPDATA%\<appName>\run
 func AppRuntime(appName string) (string, error) {
-	if appName == "" {
-		return "", ErrInvalidAppName
-	}
-	base, err := UserRuntime()
-	if err != nil {
-		return "", err
-	}
-	dir := filepath.Join(base, appName)
-	if err := os.MkdirAll(dir, 0700); err != nil { // More restrictive for runtime
-		return "", err
-	}
-	return dir, nil
+	return Resolver{}.AppRuntime(appName)
 }
 
 // SystemAppConfig returns the system-wide app configuration directory.
@@ -2,66 +2,50 @@
 
 package paths
 
-import (
-	"os"
-	"path/filepath"
-)
+// homeVar is the variable os.UserHomeDir reads.
+const homeVar = "HOME"
 
 // UserConfig returns the user-specific configuration directory.
 // macOS: ~/Library/Application Support (Apple's recommended location)
 // Also respects XDG_CONFIG_HOME for cross-platform tools.
 func UserConfig() (string, error) {
-	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
-		return dir, nil
-	}
-	home, err := Home()
-	if err != nil {
-		return "", err
-	}
-	return filepath.Join(home, "Library", "Application Support"), nil
+	return resolve(Config, nativeChain(Config))
 }
 
 // UserData returns the user-specific data directory.
 // macOS: ~/Library/Application Support (same as config on macOS)
 // Also respects XDG_DATA_HOME for cross-platform tools.
 func UserData() (string, error) {
-	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
-		return dir, nil
-	}
-	home, err := Home()
-	if err != nil {
-		return "", err
-	}
-	return filepath.Join(home, "Library", "Application Support"), nil
+	return resolve(Data, nativeChain(Data))
 }
 
 // UserCache returns the user-specific cache directory.
 // macOS: ~/Library/Caches
 // Also respects XDG_CACHE_HOME for cross-platform tools.
 func UserCache() (string, error) {
-	if dir := os.Getenv("XDG_CACHE_HOME"); dir != "" {
-		return dir, nil
-	}
-	home, err := Home()
-	if err != nil {
-		return "", err
-	}
-	return filepath.Join(home, "Library", "Caches"), nil
+	return resolve(Cache, nativeChain(Cache))
+}
+
+// UserLogs returns the user-specific log directory.
+// macOS: ~/Library/Logs
+// Also respects XDG_STATE_HOME for cross-platform tools.
+func UserLogs() (string, error) {
+	return resolve(Logs, nativeChain(Logs))
 }
 
 // UserRuntime returns the user-specific runtime directory.
 // macOS: ~/Library/Application Support (no separate runtime dir on macOS)
 // Respects XDG_RUNTIME_DIR if set.
 func UserRuntime() (string, error) {
-	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
-		return dir, nil
-	}
-	home, err := Home()
-	if err != nil {
-		return "", err
-	}
-	// macOS doesn't have a standard runtime directory, use Application Support
-	return filepath.Join(home, "Library", "Application Support"), nil
+	return resolve(Runtime, nativeChain(Runtime))
+}
+
+// UserBin returns the directory for per-user executables, which is not
+// created and may not be on PATH.
+// macOS: ~/.local/bin (macOS has no native per-user bin directory)
+// Respects XDG_BIN_HOME if set.
+func UserBin() (string, error) {
+	return resolve(Bin, nativeChain(Bin))
 }
 
 // SystemConfig returns the system-wide configuration directory.
@@ -69,3 +53,24 @@ func UserRuntime() (string, error) {
 func SystemConfig() (string, error) {
 	return "/etc", nil
 }
+
+// nativeChain returns the ~/Library locations, each overridable by the
+// corresponding XDG variable.
+func nativeChain(kind Kind) []source {
+	switch kind {
+	case Config:
+		return []source{env("XDG_CONFIG_HOME"), env(homeVar, "Library", "Application Support")}
+	case Data:
+		return []source{env("XDG_DATA_HOME"), env(homeVar, "Library", "Application Support")}
+	case Cache:
+		return []source{env("XDG_CACHE_HOME"), env(homeVar, "Library", "Caches")}
+	case Logs:
+		return []source{env("XDG_STATE_HOME"), env(homeVar, "Library", "Logs")}
+	case Runtime:
+		// macOS doesn't have a standard runtime directory, use Application Support
+		return []source{env("XDG_RUNTIME_DIR"), env(homeVar, "Library", "Application Support")}
+	case Bin:
+		return []source{env("XDG_BIN_HOME"), env(homeVar, ".local", "bin")}
+	}
+	return nil
+}
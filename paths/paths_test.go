@@ -1,9 +1,11 @@
 package paths_test
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 	"testing"
 
@@ -209,6 +211,41 @@ func TestAppCacheEmptyName(t *testing.T) {
 	}
 }
 
+func TestAppLogs(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	switch runtime.GOOS {
+	case "windows":
+		t.Setenv("LOCALAPPDATA", tmpDir)
+	default:
+		t.Setenv("XDG_STATE_HOME", tmpDir)
+	}
+
+	dir, err := paths.AppLogs("testapp")
+	if err != nil {
+		t.Fatalf("AppLogs() error: %v", err)
+	}
+
+	if !strings.HasSuffix(dir, "testapp") {
+		t.Errorf("AppLogs() should end with app name, got: %s", dir)
+	}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		t.Fatalf("AppLogs() directory not created: %v", err)
+	}
+	if !info.IsDir() {
+		t.Error("AppLogs() did not create a directory")
+	}
+}
+
+func TestAppLogsEmptyName(t *testing.T) {
+	_, err := paths.AppLogs("")
+	if err != paths.ErrInvalidAppName {
+		t.Errorf("AppLogs('') expected ErrInvalidAppName, got: %v", err)
+	}
+}
+
 func TestAppRuntime(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -279,7 +316,9 @@ func TestXDGOverrides(t *testing.T) {
 		{"XDG_CONFIG_HOME", paths.UserConfig},
 		{"XDG_DATA_HOME", paths.UserData},
 		{"XDG_CACHE_HOME", paths.UserCache},
+		{"XDG_STATE_HOME", paths.UserLogs},
 		{"XDG_RUNTIME_DIR", paths.UserRuntime},
+		{"XDG_BIN_HOME", paths.UserBin},
 	}
 
 	for _, tt := range tests {
@@ -297,3 +336,104 @@ func TestXDGOverrides(t *testing.T) {
 		})
 	}
 }
+
+func TestResolverStrategy(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	for _, env := range []string{"XDG_CONFIG_HOME", "XDG_DATA_HOME", "XDG_CACHE_HOME", "XDG_STATE_HOME"} {
+		t.Setenv(env, "")
+	}
+
+	// The zero Resolver matches the package-level functions.
+	native, err := paths.Resolver{}.UserConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want, _ := paths.UserConfig(); native != want {
+		t.Errorf("Resolver{}.UserConfig() = %s, want %s", native, want)
+	}
+
+	xdg := paths.Resolver{Strategy: paths.XDGOnMac}
+	tests := []struct {
+		fn   func() (string, error)
+		want string
+	}{
+		{xdg.UserConfig, filepath.Join(home, ".config")},
+		{xdg.UserData, filepath.Join(home, ".local", "share")},
+		{xdg.UserCache, filepath.Join(home, ".cache")},
+		{xdg.UserLogs, filepath.Join(home, ".local", "state")},
+	}
+	for _, tt := range tests {
+		got, err := tt.fn()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if runtime.GOOS == "windows" {
+			continue // XDGOnMac has no effect on Windows
+		}
+		if got != tt.want {
+			t.Errorf("XDGOnMac: got %s, want %s", got, tt.want)
+		}
+	}
+
+	dir, err := xdg.AppConfig("myapp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "windows" && dir != filepath.Join(home, ".config", "myapp") {
+		t.Errorf("XDGOnMac AppConfig = %s", dir)
+	}
+	if _, err := xdg.AppConfig(""); err != paths.ErrInvalidAppName {
+		t.Errorf("AppConfig('') expected ErrInvalidAppName, got: %v", err)
+	}
+}
+
+func TestResolveError(t *testing.T) {
+	for _, env := range []string{"HOME", "USERPROFILE", "APPDATA", "LOCALAPPDATA",
+		"XDG_CONFIG_HOME", "XDG_DATA_HOME", "XDG_CACHE_HOME", "XDG_STATE_HOME"} {
+		t.Setenv(env, "")
+	}
+
+	_, err := paths.UserConfig()
+	if !errors.Is(err, paths.ErrNoAppData) || !errors.Is(err, paths.ErrHomeNotFound) {
+		t.Fatalf("UserConfig() error = %v, want ErrNoAppData and ErrHomeNotFound", err)
+	}
+	var re *paths.ResolveError
+	if !errors.As(err, &re) {
+		t.Fatalf("UserConfig() error %T is not *ResolveError", err)
+	}
+	if re.Kind != paths.Config || len(re.Vars) != 2 {
+		t.Errorf("ResolveError = %+v", re)
+	}
+	for _, v := range re.Vars {
+		if !strings.Contains(err.Error(), v) {
+			t.Errorf("error %q does not name %s", err, v)
+		}
+	}
+
+	if _, err := paths.AppData("myapp"); !errors.Is(err, paths.ErrNoAppData) {
+		t.Errorf("AppData() error = %v, want ErrNoAppData", err)
+	}
+	if runtime.GOOS == "darwin" || runtime.GOOS == "windows" {
+		t.Setenv("XDG_RUNTIME_DIR", "")
+		if _, err := paths.UserRuntime(); !errors.Is(err, paths.ErrNoRuntimeDir) {
+			t.Errorf("UserRuntime() error = %v, want ErrNoRuntimeDir", err)
+		}
+	}
+}
+
+func TestExplain(t *testing.T) {
+	for _, kind := range []paths.Kind{paths.Config, paths.Data, paths.Cache, paths.Logs, paths.Runtime, paths.Bin} {
+		chain := paths.Explain(kind)
+		if len(chain) < 2 {
+			t.Errorf("Explain(%s) = %q", kind, chain)
+		}
+	}
+	if runtime.GOOS == "linux" {
+		want := []string{"$XDG_CONFIG_HOME", filepath.Join("$HOME", ".config")}
+		if got := paths.Explain(paths.Config); !slices.Equal(got, want) {
+			t.Errorf("Explain(Config) = %q, want %q", got, want)
+		}
+	}
+}
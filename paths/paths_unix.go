@@ -5,56 +5,46 @@ package paths
 import (
 	"fmt"
 	"os"
-	"path/filepath"
 )
 
+// homeVar is the variable os.UserHomeDir reads.
+const homeVar = "HOME"
+
 // UserConfig returns the user-specific configuration directory.
 // Follows XDG Base Directory Specification: $XDG_CONFIG_HOME or ~/.config
 func UserConfig() (string, error) {
-	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
-		return dir, nil
-	}
-	home, err := Home()
-	if err != nil {
-		return "", err
-	}
-	return filepath.Join(home, ".config"), nil
+	return resolve(Config, nativeChain(Config))
 }
 
 // UserData returns the user-specific data directory.
 // Follows XDG Base Directory Specification: $XDG_DATA_HOME or ~/.local/share
 func UserData() (string, error) {
-	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
-		return dir, nil
-	}
-	home, err := Home()
-	if err != nil {
-		return "", err
-	}
-	return filepath.Join(home, ".local", "share"), nil
+	return resolve(Data, nativeChain(Data))
 }
 
 // UserCache returns the user-specific cache directory.
 // Follows XDG Base Directory Specification: $XDG_CACHE_HOME or ~/.cache
 func UserCache() (string, error) {
-	if dir := os.Getenv("XDG_CACHE_HOME"); dir != "" {
-		return dir, nil
-	}
-	home, err := Home()
-	if err != nil {
-		return "", err
-	}
-	return filepath.Join(home, ".cache"), nil
+	return resolve(Cache, nativeChain(Cache))
+}
+
+// UserLogs returns the user-specific log directory.
+// Follows XDG Base Directory Specification: $XDG_STATE_HOME or ~/.local/state
+func UserLogs() (string, error) {
+	return resolve(Logs, nativeChain(Logs))
 }
 
 // UserRuntime returns the user-specific runtime directory.
 // Follows XDG Base Directory Specification: $XDG_RUNTIME_DIR or /tmp/<user>-runtime
 func UserRuntime() (string, error) {
-	if dir := os.Getenv("XDG_RUNTIME_DIR"); dir != "" {
-		return dir, nil
-	}
-	// Fallback to /tmp with user ID for uniqueness
-	return fmt.Sprintf("/tmp/runtime-%d", os.Getuid()), nil
+	return resolve(Runtime, nativeChain(Runtime))
+}
+
+// UserBin returns the directory for per-user executables, which is not
+// created and may not be on PATH.
+// Follows XDG Base Directory Specification: $XDG_BIN_HOME or ~/.local/bin
+func UserBin() (string, error) {
+	return resolve(Bin, nativeChain(Bin))
 }
 
 // SystemConfig returns the system-wide configuration directory.
@@ -62,3 +52,9 @@ func UserRuntime() (string, error) {
 func SystemConfig() (string, error) {
 	return "/etc", nil
 }
+
+// nativeChain returns the XDG chain, with a per-user directory under /tmp
+// standing in for a missing XDG_RUNTIME_DIR.
+func nativeChain(kind Kind) []source {
+	return xdgChain(kind, fmt.Sprintf("/tmp/runtime-%d", os.Getuid()))
+}
@@ -2,57 +2,47 @@
 
 package paths
 
-import (
-	"os"
-	"path/filepath"
-)
+import "os"
+
+// homeVar is the variable os.UserHomeDir reads.
+const homeVar = "USERPROFILE"
 
 // UserConfig returns the user-specific configuration directory.
 // Windows: %APPDATA% (typically C:\Users\<user>\AppData\Roaming)
 func UserConfig() (string, error) {
-	if dir := os.Getenv("APPDATA"); dir != "" {
-		return dir, nil
-	}
-	// Fallback to home directory
-	home, err := Home()
-	if err != nil {
-		return "", err
-	}
-	return filepath.Join(home, "AppData", "Roaming"), nil
+	return resolve(Config, nativeChain(Config))
 }
 
 // UserData returns the user-specific data directory.
 // Windows: %LOCALAPPDATA% (typically C:\Users\<user>\AppData\Local)
 func UserData() (string, error) {
-	if dir := os.Getenv("LOCALAPPDATA"); dir != "" {
-		return dir, nil
-	}
-	// Fallback to home directory
-	home, err := Home()
-	if err != nil {
-		return "", err
-	}
-	return filepath.Join(home, "AppData", "Local"), nil
+	return resolve(Data, nativeChain(Data))
 }
 
 // UserCache returns the user-specific cache directory.
 // Windows: %LOCALAPPDATA%\cache
 func UserCache() (string, error) {
-	base, err := UserData()
-	if err != nil {
-		return "", err
-	}
-	return filepath.Join(base, "cache"), nil
+	return resolve(Cache, nativeChain(Cache))
+}
+
+// UserLogs returns the user-specific log directory.
+// Windows: %LOCALAPPDATA%\logs
+func UserLogs() (string, error) {
+	return resolve(Logs, nativeChain(Logs))
 }
 
 // UserRuntime returns the user-specific runtime directory.
 // Windows: %LOCALAPPDATA%\run (Windows doesn't have a standard runtime dir)
 func UserRuntime() (string, error) {
-	base, err := UserData()
-	if err != nil {
-		return "", err
-	}
-	return filepath.Join(base, "run"), nil
+	return resolve(Runtime, nativeChain(Runtime))
+}
+
+// UserBin returns the directory for per-user executables, which is not
+// created and may not be on PATH.
+// Windows: %USERPROFILE%\.local\bin (no standard exists; this is the
+// location cross-platform installers such as pipx and uv use)
+func UserBin() (string, error) {
+	return resolve(Bin, nativeChain(Bin))
 }
 
 // SystemConfig returns the system-wide configuration directory.
@@ -64,3 +54,24 @@ func SystemConfig() (string, error) {
 	// Fallback
 	return `C:\ProgramData`, nil
 }
+
+// nativeChain returns the known folder variables, falling back to their
+// default locations under the profile directory.
+func nativeChain(kind Kind) []source {
+	switch kind {
+	case Config:
+		return []source{env("APPDATA"), env(homeVar, "AppData", "Roaming")}
+	case Data:
+		return []source{env("LOCALAPPDATA"), env(homeVar, "AppData", "Local")}
+	case Cache:
+		return []source{env("LOCALAPPDATA", "cache"), env(homeVar, "AppData", "Local", "cache")}
+	case Logs:
+		return []source{env("LOCALAPPDATA", "logs"), env(homeVar, "AppData", "Local", "logs")}
+	case Runtime:
+		// Windows doesn't have a standard runtime directory
+		return []source{env("LOCALAPPDATA", "run"), env(homeVar, "AppData", "Local", "run")}
+	case Bin:
+		return []source{env(homeVar, ".local", "bin")}
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package paths
+
+import "path/filepath"
+
+// IsRoaming reports whether dir is in the part of a roaming user profile
+// that Windows copies to a server at logoff and back at logon: under the
+// profile directory, including %APPDATA%, but outside AppData\Local and
+// AppData\LocalLow. Databases and caches there slow down every logon and
+// can be corrupted by the copy; keep them in UserData or UserCache.
+//
+// It is always false on other platforms and for users without a roaming
+// profile. dir need not exist.
+func IsRoaming(dir string) bool {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	return isRoaming(abs)
+}
+
+// IsRedirected reports whether dir is on a network share rather than a
+// local disk, as Documents and AppData are when an administrator
+// redirects them to a server with folder redirection. Files there are
+// slow to open and lock unreliably, so apps should not keep databases
+// on them, and may want to warn the user.
+//
+// Platform behavior:
+//   - Windows: dir is a UNC path or on a mapped network drive.
+//   - Linux: dir is on NFS, SMB/CIFS, AFS, Ceph, 9P, or a similar
+//     network filesystem, as home directories are in many labs.
+//   - macOS and FreeBSD: dir is on a filesystem not marked local.
+//   - Elsewhere: always false.
+//
+// dir need not exist; its nearest existing ancestor is checked. The check
+// can block while a network server does not respond.
+func IsRedirected(dir string) bool {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	// An unreachable share fails with an error other than ErrNotExist;
+	// its path can still be checked.
+	if existing, err := existingDir(abs); err == nil {
+		abs = existing
+	}
+	return isNetworkDir(abs)
+}
@@ -0,0 +1,17 @@
+//go:build darwin || freebsd
+
+package paths
+
+import "syscall"
+
+// mntLocal is MNT_LOCAL, set for filesystems stored on local disks.
+const mntLocal = 0x1000
+
+// isNetworkDir checks the mount flags of the filesystem holding dir.
+func isNetworkDir(dir string) bool {
+	var st syscall.Statfs_t
+	if syscall.Statfs(dir, &st) != nil {
+		return false
+	}
+	return uint64(st.Flags)&mntLocal == 0
+}
@@ -0,0 +1,28 @@
+//go:build linux
+
+package paths
+
+import "syscall"
+
+// networkFSMagic are the statfs magic numbers of Linux network
+// filesystems.
+var networkFSMagic = map[int64]bool{
+	0x6969:     true, // NFS
+	0x517b:     true, // SMB
+	0xff534d42: true, // CIFS
+	0xfe534d42: true, // SMB2
+	0x564c:     true, // NCP
+	0x5346414f: true, // AFS
+	0x00c36400: true, // Ceph
+	0x01021997: true, // 9P, also used for Windows drives under WSL 2
+	0x73757245: true, // Coda
+}
+
+// isNetworkDir checks the filesystem type of dir.
+func isNetworkDir(dir string) bool {
+	var st syscall.Statfs_t
+	if syscall.Statfs(dir, &st) != nil {
+		return false
+	}
+	return networkFSMagic[int64(st.Type)&0xffffffff]
+}
@@ -0,0 +1,8 @@
+//go:build !linux && !darwin && !freebsd && !windows
+
+package paths
+
+// isNetworkDir is not implemented on this platform.
+func isNetworkDir(string) bool {
+	return false
+}
@@ -0,0 +1,20 @@
+package paths_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/grokify/oscompat/paths"
+)
+
+func TestIsRedirected(t *testing.T) {
+	// The temporary directory is on a local disk, and under
+	// AppData\Local on Windows.
+	dir := filepath.Join(t.TempDir(), "not", "created")
+	if paths.IsRedirected(dir) {
+		t.Errorf("IsRedirected(%q) = true", dir)
+	}
+	if paths.IsRoaming(dir) {
+		t.Errorf("IsRoaming(%q) = true", dir)
+	}
+}
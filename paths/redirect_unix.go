@@ -0,0 +1,8 @@
+//go:build !windows
+
+package paths
+
+// isRoaming is false: only Windows has roaming profiles.
+func isRoaming(string) bool {
+	return false
+}
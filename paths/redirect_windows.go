@@ -0,0 +1,78 @@
+//go:build windows
+
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	userenv            = syscall.NewLazyDLL("userenv.dll")
+	procGetProfileType = userenv.NewProc("GetProfileType")
+	procGetDriveTypeW  = kernel32.NewProc("GetDriveTypeW")
+)
+
+const (
+	ptRoaming            = 0x2
+	ptRoamingPreexisting = 0x8
+
+	driveRemote = 4
+)
+
+// isRoaming checks the profile type of the user and where dir lies in
+// the profile.
+func isRoaming(dir string) bool {
+	var flags uint32
+	if r, _, _ := procGetProfileType.Call(uintptr(unsafe.Pointer(&flags))); r == 0 {
+		return false
+	}
+	if flags&(ptRoaming|ptRoamingPreexisting) == 0 {
+		return false
+	}
+	profile := os.Getenv(homeVar)
+	if profile == "" || !within(dir, profile) {
+		return false
+	}
+	local := os.Getenv("LOCALAPPDATA")
+	if local == "" {
+		local = filepath.Join(profile, "AppData", "Local")
+	}
+	return !within(dir, local) && !within(dir, filepath.Join(filepath.Dir(local), "LocalLow"))
+}
+
+// isNetworkDir reports whether the volume holding dir is a UNC share or
+// a mapped network drive.
+func isNetworkDir(dir string) bool {
+	if isUNC(dir) {
+		return true
+	}
+	root := volumeRoot(dir)
+	if root == "" {
+		return false
+	}
+	if isUNC(root) {
+		return true
+	}
+	p, err := syscall.UTF16PtrFromString(root)
+	if err != nil {
+		return false
+	}
+	t, _, _ := procGetDriveTypeW.Call(uintptr(unsafe.Pointer(p)))
+	return t == driveRemote
+}
+
+// isUNC reports whether path is a UNC path such as `\\server\share`,
+// but not a local device path such as `\\?\C:\`.
+func isUNC(path string) bool {
+	return strings.HasPrefix(path, `\\`) && !strings.HasPrefix(path, `\\?\`) && !strings.HasPrefix(path, `\\.\`)
+}
+
+// within reports whether path is dir or below it, ignoring case.
+func within(path, dir string) bool {
+	rel, err := filepath.Rel(strings.ToLower(dir), strings.ToLower(path))
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, `..\`)
+}
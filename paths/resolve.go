@@ -0,0 +1,153 @@
+package paths
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// ErrNoAppData is returned when a per-user configuration, data, cache,
+// log, or executable directory cannot be resolved because none of the environment
+// variables consulted is set. Use errors.As with *ResolveError to learn
+// which variables those were.
+var ErrNoAppData = errors.New("oscompat/paths: no per-user data directory")
+
+// ErrNoRuntimeDir is returned when the per-user runtime directory cannot
+// be resolved.
+var ErrNoRuntimeDir = errors.New("oscompat/paths: no per-user runtime directory")
+
+// Kind identifies a per-user directory.
+type Kind int
+
+const (
+	Config  Kind = iota // UserConfig
+	Data                // UserData
+	Cache               // UserCache
+	Logs                // UserLogs
+	Runtime             // UserRuntime
+	Bin                 // UserBin
+)
+
+// String returns the lower-case name of the kind, such as "config".
+func (k Kind) String() string {
+	switch k {
+	case Config:
+		return "config"
+	case Data:
+		return "data"
+	case Cache:
+		return "cache"
+	case Logs:
+		return "logs"
+	case Runtime:
+		return "runtime"
+	case Bin:
+		return "bin"
+	default:
+		return fmt.Sprintf("Kind(%d)", int(k))
+	}
+}
+
+// ResolveError reports a directory that could not be resolved and the
+// environment variables that were consulted, in order. It matches
+// ErrNoRuntimeDir or ErrNoAppData, and ErrHomeNotFound when the home
+// directory was among the candidates.
+type ResolveError struct {
+	Kind Kind
+	Vars []string
+}
+
+func (e *ResolveError) Error() string {
+	return fmt.Sprintf("oscompat/paths: cannot resolve %s directory: set %s",
+		e.Kind, strings.Join(e.Vars, " or "))
+}
+
+func (e *ResolveError) Unwrap() []error {
+	errs := []error{ErrNoAppData}
+	if e.Kind == Runtime {
+		errs[0] = ErrNoRuntimeDir
+	}
+	if slices.Contains(e.Vars, homeVar) {
+		errs = append(errs, ErrHomeNotFound)
+	}
+	return errs
+}
+
+// Explain describes how the directory of the given kind is resolved on
+// this platform, one candidate per line in the order they are tried, for
+// example "$XDG_CONFIG_HOME" then "$HOME/.config".
+func Explain(kind Kind) []string {
+	return Resolver{}.Explain(kind)
+}
+
+// Explain describes how r resolves the directory of the given kind.
+func (r Resolver) Explain(kind Kind) []string {
+	chain := r.chain(kind)
+	lines := make([]string, len(chain))
+	for i, s := range chain {
+		lines[i] = s.String()
+	}
+	return lines
+}
+
+// source is one step of a resolution chain: the value of an environment
+// variable with elem appended, or elem alone when env is empty.
+type source struct {
+	env  string
+	elem []string
+}
+
+func env(name string, elem ...string) source { return source{env: name, elem: elem} }
+func fixed(dir string) source                { return source{elem: []string{dir}} }
+
+func (s source) String() string {
+	if s.env == "" {
+		return filepath.Join(s.elem...)
+	}
+	v := "$" + s.env
+	if runtime.GOOS == "windows" {
+		v = "%" + s.env + "%"
+	}
+	return filepath.Join(append([]string{v}, s.elem...)...)
+}
+
+// resolve returns the first candidate in chain that is available.
+func resolve(kind Kind, chain []source) (string, error) {
+	var vars []string
+	for _, s := range chain {
+		if s.env == "" {
+			return filepath.Join(s.elem...), nil
+		}
+		if v := os.Getenv(s.env); v != "" {
+			return filepath.Join(append([]string{v}, s.elem...)...), nil
+		}
+		vars = append(vars, s.env)
+	}
+	return "", &ResolveError{Kind: kind, Vars: vars}
+}
+
+// xdgChain returns the XDG Base Directory chain for kind, falling back to
+// runtimeDir for Runtime, which has no default in the specification.
+func xdgChain(kind Kind, runtimeDir string) []source {
+	switch kind {
+	case Config:
+		return []source{env("XDG_CONFIG_HOME"), env(homeVar, ".config")}
+	case Data:
+		return []source{env("XDG_DATA_HOME"), env(homeVar, ".local", "share")}
+	case Cache:
+		return []source{env("XDG_CACHE_HOME"), env(homeVar, ".cache")}
+	case Logs:
+		return []source{env("XDG_STATE_HOME"), env(homeVar, ".local", "state")}
+	case Runtime:
+		return []source{env("XDG_RUNTIME_DIR"), fixed(runtimeDir)}
+	case Bin:
+		// The specification names ~/.local/bin without a variable;
+		// XDG_BIN_HOME is a common extension.
+		return []source{env("XDG_BIN_HOME"), env(homeVar, ".local", "bin")}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ShellProfile is an initialization file of a shell.
+type ShellProfile struct {
+	// Shell is the shell that reads the file: "sh", "bash", "zsh",
+	// "fish", "pwsh" (PowerShell 7) or "powershell" (Windows
+	// PowerShell 5.1).
+	Shell string
+
+	// Path is the absolute path of the file.
+	Path string
+
+	// Login reports that the shell reads the file only as a login shell,
+	// as opposed to every interactive shell. Terminal windows on macOS
+	// start login shells; those on Linux usually do not.
+	Login bool
+
+	// Exists reports whether the file exists.
+	Exists bool
+
+	// Current reports that Shell is the user's shell according to
+	// $SHELL, which Windows only sets inside Git Bash and MSYS2.
+	Current bool
+}
+
+// ShellProfiles returns the current user's shell initialization files,
+// whether or not they exist, so that installers adding PATH entries or
+// completions can edit the files of the shells actually in use. Files
+// of the current shell come first.
+//
+// Platform behavior:
+//   - Unix: ~/.profile, ~/.bashrc, ~/.bash_profile, .zshrc and .zprofile
+//     in $ZDOTDIR or ~, fish's config.fish, and PowerShell's profile
+//     under $XDG_CONFIG_HOME
+//   - Windows: the PowerShell 7 and Windows PowerShell profiles in the
+//     Documents folder (which may be redirected to OneDrive), and Git
+//     Bash's ~/.bashrc and ~/.bash_profile
+func ShellProfiles() ([]ShellProfile, error) {
+	home, err := Home()
+	if err != nil {
+		return nil, err
+	}
+	profiles := shellProfiles(home)
+
+	current := strings.TrimSuffix(filepath.Base(os.Getenv("SHELL")), ".exe")
+	var first, rest []ShellProfile
+	for _, p := range profiles {
+		_, err := os.Stat(p.Path)
+		p.Exists = err == nil
+		p.Current = p.Shell == current
+		if p.Current {
+			first = append(first, p)
+		} else {
+			rest = append(rest, p)
+		}
+	}
+	return append(first, rest...), nil
+}
+
+// configHome returns $XDG_CONFIG_HOME, or ~/.config when it is unset or
+// relative.
+func configHome(home string) string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); filepath.IsAbs(dir) {
+		return dir
+	}
+	return filepath.Join(home, ".config")
+}
@@ -0,0 +1,62 @@
+package paths_test
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/grokify/oscompat/paths"
+)
+
+func TestShellProfiles(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("ZDOTDIR", "")
+	t.Setenv("SHELL", "/bin/bash")
+	if err := os.WriteFile(filepath.Join(home, ".bashrc"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	profiles, err := paths.ShellProfiles()
+	if err != nil {
+		t.Fatalf("ShellProfiles() error: %v", err)
+	}
+	if len(profiles) == 0 || profiles[0].Shell != "bash" || !profiles[0].Current {
+		t.Fatalf("ShellProfiles() = %+v, want the current shell's files first", profiles)
+	}
+	var found bool
+	for _, p := range profiles {
+		if !filepath.IsAbs(p.Path) {
+			t.Errorf("%s profile %q is not absolute", p.Shell, p.Path)
+		}
+		if p.Current != (p.Shell == "bash") {
+			t.Errorf("%s profile Current = %v", p.Shell, p.Current)
+		}
+		if p.Path == filepath.Join(home, ".bashrc") {
+			found = true
+			if !p.Exists {
+				t.Error(".bashrc Exists = false")
+			}
+		} else if p.Exists && strings.HasPrefix(p.Path, home) {
+			// Documents on Windows is outside the fake home.
+			t.Errorf("%s Exists = true", p.Path)
+		}
+	}
+	if !found {
+		t.Error("~/.bashrc not listed")
+	}
+
+	if runtime.GOOS != "windows" {
+		t.Setenv("ZDOTDIR", filepath.Join(home, "zsh"))
+		profiles, _ = paths.ShellProfiles()
+		for _, p := range profiles {
+			if p.Shell == "zsh" && filepath.Dir(p.Path) != filepath.Join(home, "zsh") {
+				t.Errorf("zsh profile %q ignores ZDOTDIR", p.Path)
+			}
+		}
+	}
+}
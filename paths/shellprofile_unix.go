@@ -0,0 +1,26 @@
+//go:build !windows
+
+package paths
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// shellProfiles lists the initialization files of the common shells.
+func shellProfiles(home string) []ShellProfile {
+	zdot := home
+	if dir := os.Getenv("ZDOTDIR"); filepath.IsAbs(dir) {
+		zdot = dir
+	}
+	config := configHome(home)
+	return []ShellProfile{
+		{Shell: "sh", Path: filepath.Join(home, ".profile"), Login: true},
+		{Shell: "bash", Path: filepath.Join(home, ".bashrc")},
+		{Shell: "bash", Path: filepath.Join(home, ".bash_profile"), Login: true},
+		{Shell: "zsh", Path: filepath.Join(zdot, ".zshrc")},
+		{Shell: "zsh", Path: filepath.Join(zdot, ".zprofile"), Login: true},
+		{Shell: "fish", Path: filepath.Join(config, "fish", "config.fish")},
+		{Shell: "pwsh", Path: filepath.Join(config, "powershell", "Microsoft.PowerShell_profile.ps1")},
+	}
+}
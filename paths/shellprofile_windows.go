@@ -0,0 +1,53 @@
+//go:build windows
+
+package paths
+
+import (
+	"path/filepath"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	shell32                  = syscall.NewLazyDLL("shell32.dll")
+	ole32                    = syscall.NewLazyDLL("ole32.dll")
+	procSHGetKnownFolderPath = shell32.NewProc("SHGetKnownFolderPath")
+	procCoTaskMemFree        = ole32.NewProc("CoTaskMemFree")
+)
+
+// folderIDDocuments is FOLDERID_Documents.
+var folderIDDocuments = syscall.GUID{
+	Data1: 0xFDD39AD0, Data2: 0x238F, Data3: 0x46AF,
+	Data4: [8]byte{0xAD, 0xB4, 0x6C, 0x85, 0x48, 0x03, 0x69, 0xC7},
+}
+
+// shellProfiles lists the PowerShell profiles and those of Git Bash,
+// which uses the profile directory as its home.
+func shellProfiles(home string) []ShellProfile {
+	docs := documentsDir(home)
+	return []ShellProfile{
+		{Shell: "pwsh", Path: filepath.Join(docs, "PowerShell", "Microsoft.PowerShell_profile.ps1")},
+		{Shell: "powershell", Path: filepath.Join(docs, "WindowsPowerShell", "Microsoft.PowerShell_profile.ps1")},
+		{Shell: "bash", Path: filepath.Join(home, ".bashrc")},
+		{Shell: "bash", Path: filepath.Join(home, ".bash_profile"), Login: true},
+	}
+}
+
+// documentsDir returns the Documents known folder, which folder
+// redirection and OneDrive backup can move, falling back to
+// %USERPROFILE%\Documents.
+func documentsDir(home string) string {
+	var p *uint16
+	hr, _, _ := procSHGetKnownFolderPath.Call(uintptr(unsafe.Pointer(&folderIDDocuments)), 0, 0, uintptr(unsafe.Pointer(&p)))
+	if p != nil {
+		defer func() { _, _, _ = procCoTaskMemFree.Call(uintptr(unsafe.Pointer(p))) }()
+	}
+	if hr != 0 || p == nil {
+		return filepath.Join(home, "Documents")
+	}
+	n := 0
+	for *(*uint16)(unsafe.Add(unsafe.Pointer(p), 2*n)) != 0 {
+		n++
+	}
+	return syscall.UTF16ToString(unsafe.Slice(p, n))
+}
@@ -0,0 +1,124 @@
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// Strategy selects which directory conventions a Resolver follows.
+type Strategy int
+
+const (
+	// NativeMac uses each platform's own conventions, including
+	// ~/Library on macOS. This is what the package-level functions do.
+	NativeMac Strategy = iota
+
+	// XDGOnMac uses the XDG Base Directory layout (~/.config,
+	// ~/.local/share, ~/.cache, ~/.local/state) on macOS as well, as many
+	// command-line tools prefer. Other platforms are unaffected.
+	XDGOnMac
+)
+
+// String returns the name of the strategy.
+func (s Strategy) String() string {
+	switch s {
+	case NativeMac:
+		return "NativeMac"
+	case XDGOnMac:
+		return "XDGOnMac"
+	default:
+		return "Strategy(?)"
+	}
+}
+
+// Resolver resolves directories using a chosen Strategy. Unlike setting
+// XDG_CONFIG_HOME and friends, it only affects the code that uses it. The
+// zero value behaves like the package-level functions.
+//
+// XDG environment variables are honored under either strategy.
+type Resolver struct {
+	Strategy Strategy
+}
+
+// chain returns the resolution chain r uses for kind.
+func (r Resolver) chain(kind Kind) []source {
+	if r.Strategy == XDGOnMac && runtime.GOOS == "darwin" {
+		return xdgChain(kind, os.TempDir())
+	}
+	return nativeChain(kind)
+}
+
+// UserConfig returns the user-specific configuration directory.
+func (r Resolver) UserConfig() (string, error) {
+	return resolve(Config, r.chain(Config))
+}
+
+// UserData returns the user-specific data directory.
+func (r Resolver) UserData() (string, error) {
+	return resolve(Data, r.chain(Data))
+}
+
+// UserCache returns the user-specific cache directory.
+func (r Resolver) UserCache() (string, error) {
+	return resolve(Cache, r.chain(Cache))
+}
+
+// UserLogs returns the user-specific log directory.
+func (r Resolver) UserLogs() (string, error) {
+	return resolve(Logs, r.chain(Logs))
+}
+
+// UserRuntime returns the user-specific runtime directory. macOS has no
+// XDG_RUNTIME_DIR by default, so XDGOnMac falls back to the per-user
+// temporary directory, which launchd cleans up.
+func (r Resolver) UserRuntime() (string, error) {
+	return resolve(Runtime, r.chain(Runtime))
+}
+
+// UserBin returns the directory for per-user executables.
+func (r Resolver) UserBin() (string, error) {
+	return resolve(Bin, r.chain(Bin))
+}
+
+// AppConfig returns the app-specific configuration directory, creating it if needed.
+func (r Resolver) AppConfig(appName string) (string, error) {
+	return appDir(r.UserConfig, appName, 0755)
+}
+
+// AppData returns the app-specific data directory, creating it if needed.
+func (r Resolver) AppData(appName string) (string, error) {
+	return appDir(r.UserData, appName, 0755)
+}
+
+// AppCache returns the app-specific cache directory, creating it if needed.
+func (r Resolver) AppCache(appName string) (string, error) {
+	return appDir(r.UserCache, appName, 0755)
+}
+
+// AppLogs returns the app-specific log directory, creating it if needed.
+func (r Resolver) AppLogs(appName string) (string, error) {
+	return appDir(r.UserLogs, appName, 0755)
+}
+
+// AppRuntime returns the app-specific runtime directory, creating it if needed.
+func (r Resolver) AppRuntime(appName string) (string, error) {
+	return appDir(r.UserRuntime, appName, 0700) // More restrictive for runtime
+}
+
+// appDir returns appName under the directory from base, creating it with
+// perm if needed.
+func appDir(base func() (string, error), appName string, perm os.FileMode) (string, error) {
+	if appName == "" {
+		return "", ErrInvalidAppName
+	}
+	dir, err := base()
+	if err != nil {
+		return "", err
+	}
+	dir = filepath.Join(dir, appName)
+	if err := os.MkdirAll(dir, perm); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
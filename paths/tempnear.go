@@ -0,0 +1,54 @@
+package paths
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+// TempNear returns a directory for temporary files that is on the same
+// volume as target, so that a file written there can be moved onto
+// target with an atomic rename. target need not exist.
+//
+// It returns os.TempDir() when that is on the same volume, and otherwise
+// the directory that holds target (or its nearest existing ancestor); the
+// caller should then give its temporary files hidden names, as
+// os.CreateTemp with a pattern like ".name-*" does.
+//
+// Platform behavior:
+//   - Unix: the devices must match, and a probe file is renamed from the
+//     temporary directory into target's directory, which also catches
+//     bind mounts of one filesystem, across which rename fails.
+//   - Windows: the volume roots from GetVolumePathName must match,
+//     which accounts for drive letters, UNC shares, and volumes mounted
+//     on folders.
+func TempNear(target string) (string, error) {
+	abs, err := filepath.Abs(target)
+	if err != nil {
+		return "", err
+	}
+	dir, err := existingDir(filepath.Dir(abs))
+	if err != nil {
+		return "", err
+	}
+	tmp := os.TempDir()
+	if sameVolume(tmp, dir) {
+		return tmp, nil
+	}
+	return dir, nil
+}
+
+// existingDir returns dir or its nearest ancestor that exists.
+func existingDir(dir string) (string, error) {
+	for {
+		_, err := os.Stat(dir)
+		if err == nil || !errors.Is(err, os.ErrNotExist) {
+			return dir, err
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", err
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,54 @@
+package paths_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/grokify/oscompat/paths"
+)
+
+// renameVia writes a temporary file in the directory TempNear returns and
+// renames it onto target.
+func renameVia(t *testing.T, target string) string {
+	t.Helper()
+	dir, err := paths.TempNear(target)
+	if err != nil {
+		t.Fatalf("TempNear(%q) error: %v", target, err)
+	}
+	f, err := os.CreateTemp(dir, ".tempnear-*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(f.Name(), target); err != nil {
+		_ = os.Remove(f.Name())
+		t.Errorf("rename from TempNear(%q) = %q failed: %v", target, dir, err)
+	}
+	return dir
+}
+
+func TestTempNear(t *testing.T) {
+	target := filepath.Join(t.TempDir(), "out.txt")
+	if dir := renameVia(t, target); dir != os.TempDir() {
+		t.Logf("TempNear(%q) = %q rather than the temp directory", target, dir)
+	}
+
+	// A missing parent resolves to the nearest existing ancestor.
+	root := t.TempDir()
+	dir, err := paths.TempNear(filepath.Join(root, "a", "b", "c.txt"))
+	if err != nil {
+		t.Fatalf("TempNear() error: %v", err)
+	}
+	if dir != os.TempDir() && dir != root {
+		t.Errorf("TempNear(missing parent) = %q, want %q or the temp directory", dir, root)
+	}
+
+	// /dev/shm is usually a separate tmpfs on Linux.
+	if shm, err := os.MkdirTemp("/dev/shm", "tempnear-"); err == nil {
+		defer func() { _ = os.RemoveAll(shm) }()
+		renameVia(t, filepath.Join(shm, "out.txt"))
+	}
+}
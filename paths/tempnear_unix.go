@@ -0,0 +1,32 @@
+//go:build !windows
+
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// sameVolume reports whether a file can be renamed from dir a into dir b.
+func sameVolume(a, b string) bool {
+	var sa, sb syscall.Stat_t
+	if syscall.Stat(a, &sa) != nil || syscall.Stat(b, &sb) != nil || sa.Dev != sb.Dev {
+		return false
+	}
+	f, err := os.CreateTemp(a, ".oscompat-probe-*")
+	if err != nil {
+		return false
+	}
+	if err := f.Close(); err != nil {
+		_ = os.Remove(f.Name())
+		return false
+	}
+	moved := filepath.Join(b, filepath.Base(f.Name()))
+	if err := os.Rename(f.Name(), moved); err != nil {
+		_ = os.Remove(f.Name())
+		return false
+	}
+	_ = os.Remove(moved)
+	return true
+}
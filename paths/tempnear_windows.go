@@ -0,0 +1,35 @@
+//go:build windows
+
+package paths
+
+import (
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	kernel32               = syscall.NewLazyDLL("kernel32.dll")
+	procGetVolumePathNameW = kernel32.NewProc("GetVolumePathNameW")
+)
+
+// sameVolume reports whether dirs a and b are on the same volume.
+func sameVolume(a, b string) bool {
+	ra, rb := volumeRoot(a), volumeRoot(b)
+	return ra != "" && strings.EqualFold(ra, rb)
+}
+
+// volumeRoot returns the mount point of the volume holding path, such as
+// `C:\`, `\\server\share\`, or a folder another volume is mounted on.
+func volumeRoot(path string) string {
+	p, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return ""
+	}
+	buf := make([]uint16, syscall.MAX_LONG_PATH)
+	r, _, _ := procGetVolumePathNameW.Call(uintptr(unsafe.Pointer(p)), uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf)))
+	if r == 0 {
+		return ""
+	}
+	return syscall.UTF16ToString(buf)
+}
@@ -0,0 +1,49 @@
+package power
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// clockInterval is how often watchClock compares the clocks.
+	clockInterval = 5 * time.Second
+
+	// clockThreshold is how far the wall clock must run ahead of the
+	// monotonic clock to count as sleep. It absorbs small clock
+	// adjustments.
+	clockThreshold = 5 * time.Second
+)
+
+// watchClock sends Suspend and Resume each time the wall clock advances
+// further than the monotonic clock, which does not run while the system
+// sleeps. It returns when ctx is done.
+func watchClock(ctx context.Context, ch chan<- Event) {
+	ticker := time.NewTicker(clockInterval)
+	defer ticker.Stop()
+	last := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		now := time.Now()
+		slept := now.Round(0).Sub(last.Round(0)) - now.Sub(last)
+		last = now
+		if slept > clockThreshold {
+			send(ctx, ch, Suspend)
+			send(ctx, ch, Resume)
+		}
+	}
+}
+
+// send delivers e unless the channel is full, so that a slow reader
+// cannot stall the watcher.
+func send(ctx context.Context, ch chan<- Event, e Event) {
+	select {
+	case ch <- e:
+	case <-ctx.Done():
+	default:
+	}
+}
@@ -0,0 +1,50 @@
+//go:build linux || darwin
+
+package power
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// startupGrace is how long holdProcess waits for the helper to fail, for
+// example when an inhibitor is refused.
+const startupGrace = 200 * time.Millisecond
+
+// holdProcess starts cmd, which must run until its standard input is
+// closed, and returns a function that closes it and waits. Because the
+// pipe also closes when this process exits, the helper never outlives it.
+func holdProcess(cmd *exec.Cmd) (func() error, error) {
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("oscompat/power: %w", err)
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+
+	select {
+	case err := <-done:
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" && err != nil {
+			msg = err.Error()
+		}
+		return nil, fmt.Errorf("oscompat/power: %s exited: %s", cmd.Args[0], msg)
+	case <-time.After(startupGrace):
+	}
+
+	return func() error {
+		err := stdin.Close()
+		<-done
+		return err
+	}, nil
+}
@@ -0,0 +1,114 @@
+// Package power keeps the system awake during long-running work and
+// reports when the system sleeps and wakes.
+//
+// Platform behavior:
+//   - Windows: power requests (PowerCreateRequest), listed by
+//     "powercfg /requests", and suspend/resume notifications from
+//     PowerRegisterSuspendResumeNotification.
+//   - macOS: a caffeinate process holds an idle-sleep assertion. Sleep is
+//     detected after wake, by comparing the wall clock with the monotonic
+//     clock, which stops while the system sleeps.
+//   - Linux: a systemd-inhibit process holds a logind sleep and idle
+//     inhibitor, listed by "systemd-inhibit --list". Suspend and resume
+//     are the logind PrepareForSleep signal on the system bus, with the
+//     clock comparison as a fallback.
+//
+// Inhibitors are tied to the process: they are released when it exits,
+// even if Release is never called.
+package power
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// ErrUnsupported is returned by Inhibit where sleep cannot be inhibited.
+// It matches errors.ErrUnsupported.
+var ErrUnsupported = fmt.Errorf("oscompat/power: %w", errors.ErrUnsupported)
+
+// Inhibitor keeps the system from sleeping until released.
+type Inhibitor struct {
+	once    sync.Once
+	err     error
+	release func() error
+}
+
+// Inhibit keeps the system from sleeping, including idle sleep, until
+// the returned Inhibitor is released. The display may still turn off.
+// reason is shown by the platform's tools; if empty, the executable name
+// is used.
+//
+// Example:
+//
+//	inh, err := power.Inhibit("uploading backup")
+//	if err == nil {
+//		defer inh.Release()
+//	}
+func Inhibit(reason string) (*Inhibitor, error) {
+	if reason == "" {
+		reason = appName()
+	}
+	release, err := inhibit(reason)
+	if err != nil {
+		return nil, err
+	}
+	return &Inhibitor{release: release}, nil
+}
+
+// Release lets the system sleep again. Calls after the first have no
+// effect and return the first call's result.
+func (i *Inhibitor) Release() error {
+	i.once.Do(func() {
+		i.err = i.release()
+	})
+	return i.err
+}
+
+// Event is a change in the system's power state.
+type Event int
+
+const (
+	// Suspend is sent when the system is about to sleep. Where sleep is
+	// only detected after wake, it is sent just before Resume.
+	Suspend Event = iota + 1
+
+	// Resume is sent after the system wakes.
+	Resume
+)
+
+// String returns the event name.
+func (e Event) String() string {
+	switch e {
+	case Suspend:
+		return "suspend"
+	case Resume:
+		return "resume"
+	}
+	return fmt.Sprintf("Event(%d)", int(e))
+}
+
+// OnSuspendResume returns a channel that receives Suspend and Resume
+// events. The channel is closed when ctx is done. Events may be dropped
+// if the channel is not drained.
+//
+// Wall-clock timestamps taken before a Suspend may be far in the past
+// after the matching Resume; re-check anything derived from them, such
+// as file modification times and lease expiries.
+func OnSuspendResume(ctx context.Context) <-chan Event {
+	ch := make(chan Event, 2)
+	onSuspendResume(ctx, ch)
+	return ch
+}
+
+// appName returns the base name of the executable.
+func appName() string {
+	exe, err := os.Executable()
+	if err != nil {
+		return "oscompat"
+	}
+	return filepath.Base(exe)
+}
@@ -0,0 +1,24 @@
+//go:build darwin
+
+package power
+
+import (
+	"context"
+	"os/exec"
+)
+
+// inhibit runs cat under caffeinate, which holds an idle-sleep assertion
+// for as long as cat runs. The assertion is named after caffeinate, so
+// reason is not shown.
+func inhibit(string) (func() error, error) {
+	return holdProcess(exec.Command("caffeinate", "-i", "cat"))
+}
+
+// onSuspendResume compares clocks; sleep notifications need IOKit, which
+// requires cgo.
+func onSuspendResume(ctx context.Context, ch chan Event) {
+	go func() {
+		defer close(ch)
+		watchClock(ctx, ch)
+	}()
+}
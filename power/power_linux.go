@@ -0,0 +1,68 @@
+//go:build linux
+
+package power
+
+import (
+	"context"
+	"os/exec"
+
+	"github.com/grokify/oscompat/internal/dbus"
+)
+
+// prepareForSleep matches the logind signal sent before sleep (true) and
+// after wake (false).
+const prepareForSleep = "type='signal',interface='org.freedesktop.login1.Manager',member='PrepareForSleep'"
+
+// inhibit runs cat under systemd-inhibit, which holds the inhibitor for
+// as long as cat runs.
+func inhibit(reason string) (func() error, error) {
+	if _, err := exec.LookPath("systemd-inhibit"); err != nil {
+		return nil, ErrUnsupported
+	}
+	return holdProcess(exec.Command("systemd-inhibit",
+		"--what=sleep:idle", "--who="+appName(), "--why="+reason, "--mode=block", "cat"))
+}
+
+// onSuspendResume listens for PrepareForSleep, or compares clocks where
+// there is no system bus.
+func onSuspendResume(ctx context.Context, ch chan Event) {
+	c, err := dbus.SystemBus()
+	if err == nil {
+		if err = c.AddMatch(prepareForSleep); err != nil {
+			_ = c.Close()
+		}
+	}
+	if err != nil {
+		go func() {
+			defer close(ch)
+			watchClock(ctx, ch)
+		}()
+		return
+	}
+
+	go func() {
+		<-ctx.Done()
+		_ = c.Close()
+	}()
+	go func() {
+		defer close(ch)
+		for {
+			sig, err := c.Signal()
+			if err != nil {
+				if ctx.Err() == nil {
+					// The bus went away; keep reporting sleep.
+					watchClock(ctx, ch)
+				}
+				return
+			}
+			if sig.Member != "PrepareForSleep" || len(sig.Body) == 0 {
+				continue
+			}
+			if sleeping, _ := sig.Body[0].(bool); sleeping {
+				send(ctx, ch, Suspend)
+			} else {
+				send(ctx, ch, Resume)
+			}
+		}
+	}()
+}
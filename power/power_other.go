@@ -0,0 +1,17 @@
+//go:build !windows && !darwin && !linux
+
+package power
+
+import "context"
+
+func inhibit(string) (func() error, error) {
+	return nil, ErrUnsupported
+}
+
+// onSuspendResume compares clocks.
+func onSuspendResume(ctx context.Context, ch chan Event) {
+	go func() {
+		defer close(ch)
+		watchClock(ctx, ch)
+	}()
+}
@@ -0,0 +1,103 @@
+package power_test
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grokify/oscompat/power"
+)
+
+func TestInhibit(t *testing.T) {
+	inh, err := power.Inhibit("oscompat test")
+	if err != nil {
+		t.Skipf("Inhibit() unavailable: %v", err)
+	}
+	if err := inh.Release(); err != nil {
+		t.Errorf("Release() error: %v", err)
+	}
+	if err := inh.Release(); err != nil {
+		t.Errorf("second Release() error: %v", err)
+	}
+}
+
+func TestEventString(t *testing.T) {
+	if power.Suspend.String() != "suspend" || power.Resume.String() != "resume" {
+		t.Errorf("String() = %q, %q", power.Suspend, power.Resume)
+	}
+}
+
+func TestOnSuspendResumeClosed(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := power.OnSuspendResume(ctx)
+	cancel()
+	select {
+	case _, ok := <-ch:
+		if ok {
+			t.Error("unexpected event")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("channel not closed after cancel")
+	}
+}
+
+// TestOnSuspendResumeLogind sends PrepareForSleep signals on a private
+// bus standing in for the system bus.
+func TestOnSuspendResumeLogind(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("logind is Linux only")
+	}
+	for _, tool := range []string{"dbus-daemon", "dbus-send"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			t.Skipf("%s not installed", tool)
+		}
+	}
+	daemon := exec.Command("dbus-daemon", "--session", "--nofork", "--print-address")
+	out, err := daemon.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := daemon.Start(); err != nil {
+		t.Skipf("cannot start dbus-daemon: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = daemon.Process.Kill()
+		_ = daemon.Wait()
+	})
+	addr, err := bufio.NewReader(out).ReadString('\n')
+	if err != nil {
+		t.Fatalf("reading bus address: %v", err)
+	}
+	addr = strings.TrimSpace(addr)
+	t.Setenv("DBUS_SYSTEM_BUS_ADDRESS", addr)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := power.OnSuspendResume(ctx)
+
+	for _, sleeping := range []string{"true", "false"} {
+		// With --address, dbus-send skips registering with the bus, which
+		// then drops its signals.
+		emit := exec.Command("dbus-send", "--session", "--type=signal",
+			"/org/freedesktop/login1", "org.freedesktop.login1.Manager.PrepareForSleep", "boolean:"+sleeping)
+		emit.Env = append(os.Environ(), "DBUS_SESSION_BUS_ADDRESS="+addr)
+		if out, err := emit.CombinedOutput(); err != nil {
+			t.Fatalf("dbus-send: %v: %s", err, out)
+		}
+	}
+	for _, want := range []power.Event{power.Suspend, power.Resume} {
+		select {
+		case got := <-ch:
+			if got != want {
+				t.Errorf("event = %v, want %v", got, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no %v event", want)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+//go:build windows
+
+package power
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	kernel32                                     = syscall.NewLazyDLL("kernel32.dll")
+	powrprof                                     = syscall.NewLazyDLL("powrprof.dll")
+	procPowerCreateRequest                       = kernel32.NewProc("PowerCreateRequest")
+	procPowerSetRequest                          = kernel32.NewProc("PowerSetRequest")
+	procPowerClearRequest                        = kernel32.NewProc("PowerClearRequest")
+	procPowerRegisterSuspendResumeNotification   = powrprof.NewProc("PowerRegisterSuspendResumeNotification")
+	procPowerUnregisterSuspendResumeNotification = powrprof.NewProc("PowerUnregisterSuspendResumeNotification")
+)
+
+const (
+	powerRequestContextSimpleString = 0x1
+	powerRequestSystemRequired      = 1
+
+	deviceNotifyCallback = 2
+
+	pbtAPMSuspend         = 0x4
+	pbtAPMResumeAutomatic = 0x12
+)
+
+// reasonContext mirrors REASON_CONTEXT with a simple reason string.
+type reasonContext struct {
+	Version uint32
+	Flags   uint32
+	Reason  *uint16
+}
+
+// deviceNotifySubscribeParameters mirrors DEVICE_NOTIFY_SUBSCRIBE_PARAMETERS.
+type deviceNotifySubscribeParameters struct {
+	Callback uintptr
+	Context  uintptr
+}
+
+// inhibit creates a power request that keeps the system awake.
+func inhibit(reason string) (func() error, error) {
+	r, err := syscall.UTF16PtrFromString(reason)
+	if err != nil {
+		return nil, err
+	}
+	rc := reasonContext{Flags: powerRequestContextSimpleString, Reason: r}
+	h, _, err := procPowerCreateRequest.Call(uintptr(unsafe.Pointer(&rc)))
+	if syscall.Handle(h) == syscall.InvalidHandle {
+		return nil, err
+	}
+	if ok, _, err := procPowerSetRequest.Call(h, powerRequestSystemRequired); ok == 0 {
+		_ = syscall.CloseHandle(syscall.Handle(h))
+		return nil, err
+	}
+	return func() error {
+		ok, _, err := procPowerClearRequest.Call(h, powerRequestSystemRequired)
+		closeErr := syscall.CloseHandle(syscall.Handle(h))
+		if ok == 0 {
+			return err
+		}
+		return closeErr
+	}, nil
+}
+
+// Suspend/resume callbacks are dispatched through one callback, since
+// syscall.NewCallback cannot free callbacks. The context value passed to
+// Windows is a key into watchers.
+var (
+	watchersMu  sync.Mutex
+	watchers    = make(map[uintptr]chan Event)
+	nextWatcher uintptr
+
+	suspendCallback = sync.OnceValue(func() uintptr {
+		return syscall.NewCallback(func(context, typ, setting uintptr) uintptr {
+			var e Event
+			switch typ {
+			case pbtAPMSuspend:
+				e = Suspend
+			case pbtAPMResumeAutomatic:
+				e = Resume
+			default:
+				return 0
+			}
+			watchersMu.Lock()
+			defer watchersMu.Unlock()
+			if ch, ok := watchers[context]; ok {
+				select {
+				case ch <- e:
+				default:
+				}
+			}
+			return 0
+		})
+	})
+)
+
+// onSuspendResume registers for suspend/resume notifications, or
+// compares clocks where registration is not available (before Windows 8).
+func onSuspendResume(ctx context.Context, ch chan Event) {
+	if procPowerRegisterSuspendResumeNotification.Find() != nil {
+		go func() {
+			defer close(ch)
+			watchClock(ctx, ch)
+		}()
+		return
+	}
+
+	watchersMu.Lock()
+	nextWatcher++
+	key := nextWatcher
+	watchers[key] = ch
+	watchersMu.Unlock()
+
+	params := &deviceNotifySubscribeParameters{Callback: suspendCallback(), Context: key}
+	var h uintptr
+	r, _, _ := procPowerRegisterSuspendResumeNotification.Call(deviceNotifyCallback,
+		uintptr(unsafe.Pointer(params)), uintptr(unsafe.Pointer(&h)))
+	if r != 0 {
+		watchersMu.Lock()
+		delete(watchers, key)
+		watchersMu.Unlock()
+		go func() {
+			defer close(ch)
+			watchClock(ctx, ch)
+		}()
+		return
+	}
+
+	go func() {
+		<-ctx.Done()
+		_, _, _ = procPowerUnregisterSuspendResumeNotification.Call(h)
+		runtime.KeepAlive(params)
+		watchersMu.Lock()
+		delete(watchers, key)
+		close(ch)
+		watchersMu.Unlock()
+	}()
+}
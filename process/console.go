@@ -0,0 +1,30 @@
+package process
+
+import "os/exec"
+
+// HideConsole configures cmd to start with its window hidden, as if run
+// with SW_HIDE: a console program still gets a console, and a GUI program
+// starts invisible until it shows itself. Call it before cmd.Start. It
+// sets only the fields of cmd.SysProcAttr it needs and is a no-op outside
+// Windows, where child processes never open windows of their own.
+func HideConsole(cmd *exec.Cmd) {
+	hideConsole(cmd)
+}
+
+// NewConsole configures cmd to run in a new, visible console window
+// rather than sharing the caller's console, or having none when the
+// caller is a GUI application (CREATE_NEW_CONSOLE). It clears the flags
+// set by SetNoWindow and StartDetached's DETACHED_PROCESS, which Windows
+// does not allow together with it. It is a no-op outside Windows.
+func NewConsole(cmd *exec.Cmd) {
+	newConsole(cmd)
+}
+
+// SetNoWindow configures cmd, a console program, to run with a console
+// that has no window (CREATE_NO_WINDOW). This is what a GUI application
+// should use to launch command-line helpers: no window flashes up, and
+// the helper's output can still be captured through cmd.Stdout. Windows
+// ignores the flag for GUI programs. It is a no-op outside Windows.
+func SetNoWindow(cmd *exec.Cmd) {
+	setNoWindow(cmd)
+}
@@ -0,0 +1,11 @@
+//go:build !windows
+
+package process
+
+import "os/exec"
+
+func hideConsole(*exec.Cmd) {}
+
+func newConsole(*exec.Cmd) {}
+
+func setNoWindow(*exec.Cmd) {}
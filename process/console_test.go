@@ -0,0 +1,27 @@
+package process_test
+
+import (
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/grokify/oscompat/process"
+)
+
+func TestSetNoWindowCapturesOutput(t *testing.T) {
+	cmd := exec.Command("sh", "-c", "echo hello")
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/c", "echo hello")
+	}
+	process.NewConsole(cmd)
+	process.SetNoWindow(cmd)
+	process.HideConsole(cmd)
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("Output() error: %v", err)
+	}
+	if strings.TrimSpace(string(out)) != "hello" {
+		t.Errorf("output = %q, want hello", out)
+	}
+}
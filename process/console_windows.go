@@ -0,0 +1,37 @@
+//go:build windows
+
+package process
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+const (
+	createNewConsole = 0x00000010
+	createNoWindow   = 0x08000000
+)
+
+// sysProcAttr returns cmd.SysProcAttr, allocating it if needed.
+func sysProcAttr(cmd *exec.Cmd) *syscall.SysProcAttr {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	return cmd.SysProcAttr
+}
+
+func hideConsole(cmd *exec.Cmd) {
+	sysProcAttr(cmd).HideWindow = true
+}
+
+func newConsole(cmd *exec.Cmd) {
+	attr := sysProcAttr(cmd)
+	attr.CreationFlags &^= createNoWindow | detachedProcess
+	attr.CreationFlags |= createNewConsole
+}
+
+func setNoWindow(cmd *exec.Cmd) {
+	attr := sysProcAttr(cmd)
+	attr.CreationFlags &^= createNewConsole | detachedProcess
+	attr.CreationFlags |= createNoWindow
+}
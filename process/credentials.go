@@ -0,0 +1,61 @@
+package process
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"strings"
+)
+
+// SetCredentials configures cmd to run as the named user. The caller must
+// be privileged (root on Unix, SYSTEM or an administrator holding
+// SeAssignPrimaryTokenPrivilege on Windows) for the command to start.
+//
+// HOME, USER, and LOGNAME in cmd.Env are set for the target user, starting
+// from the current environment if cmd.Env is nil.
+//
+// Platform behavior:
+//   - Unix: sets cmd.SysProcAttr.Credential to the user's UID, primary GID,
+//     and supplementary groups.
+//   - Windows: Windows has no way to switch users without a password, so
+//     the token of a process already running as the user (for example,
+//     their explorer.exe) is duplicated and set as cmd.SysProcAttr.Token.
+//     An error is returned if the user has no running process. The token
+//     handle remains open for the life of the caller.
+func SetCredentials(cmd *exec.Cmd, username string) error {
+	u, err := user.Lookup(username)
+	if err != nil {
+		return fmt.Errorf("oscompat/process: unknown user %q: %w", username, err)
+	}
+	if err := setCredentials(cmd, u); err != nil {
+		return err
+	}
+	setUserEnv(cmd, u)
+	return nil
+}
+
+// setUserEnv overrides the user-identifying variables in cmd.Env.
+func setUserEnv(cmd *exec.Cmd, u *user.User) {
+	env := cmd.Env
+	if env == nil {
+		env = os.Environ()
+	}
+	name := u.Username
+	if _, account, ok := strings.Cut(name, `\`); ok {
+		name = account
+	}
+	overrides := map[string]string{"HOME": u.HomeDir, "USER": name, "LOGNAME": name}
+
+	out := make([]string, 0, len(env)+len(overrides))
+	for _, kv := range env {
+		k, _, _ := strings.Cut(kv, "=")
+		if _, ok := overrides[k]; !ok {
+			out = append(out, kv)
+		}
+	}
+	for _, k := range []string{"HOME", "USER", "LOGNAME"} {
+		out = append(out, k+"="+overrides[k])
+	}
+	cmd.Env = out
+}
@@ -0,0 +1,53 @@
+package process_test
+
+import (
+	"os"
+	"os/exec"
+	"os/user"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/grokify/oscompat/process"
+)
+
+func TestSetCredentials(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires SYSTEM privileges on Windows")
+	}
+
+	target := "nobody"
+	if os.Geteuid() != 0 {
+		// Without root, only switching to ourselves is permitted.
+		u, err := user.Current()
+		if err != nil {
+			t.Skip("cannot determine current user")
+		}
+		target = u.Username
+	}
+	u, err := user.Lookup(target)
+	if err != nil {
+		t.Skipf("user %q not found", target)
+	}
+
+	cmd := exec.Command("sh", "-c", `id -u; echo "$USER"`)
+	if err := process.SetCredentials(cmd, target); err != nil {
+		t.Fatalf("SetCredentials() error: %v", err)
+	}
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("Output() error: %v", err)
+	}
+
+	lines := strings.Fields(string(out))
+	if len(lines) != 2 || lines[0] != u.Uid || lines[1] != target {
+		t.Errorf("child reported %q, want uid %s and user %s", out, u.Uid, target)
+	}
+}
+
+func TestSetCredentialsUnknownUser(t *testing.T) {
+	cmd := exec.Command("true")
+	if err := process.SetCredentials(cmd, "no-such-user-oscompat"); err == nil {
+		t.Error("SetCredentials() with unknown user should return error")
+	}
+}
@@ -0,0 +1,68 @@
+//go:build windows
+
+package process
+
+import (
+	"fmt"
+	"os/exec"
+	"os/user"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	advapi32             = syscall.NewLazyDLL("advapi32.dll")
+	procDuplicateTokenEx = advapi32.NewProc("DuplicateTokenEx")
+)
+
+const (
+	tokenAssignPrimary      = 0x0001
+	tokenDuplicate          = 0x0002
+	maximumAllowed          = 0x02000000
+	securityImpersonation   = 2
+	tokenPrimary            = 1
+	processQueryInformation = 0x0400
+)
+
+// setCredentials duplicates the primary token of a process owned by u.
+func setCredentials(cmd *exec.Cmd, u *user.User) error {
+	procs, err := Find(Filter{User: u.Uid})
+	if err != nil {
+		return err
+	}
+	for _, p := range procs {
+		token, err := duplicateProcessToken(p.PID)
+		if err != nil {
+			continue
+		}
+		if cmd.SysProcAttr == nil {
+			cmd.SysProcAttr = &syscall.SysProcAttr{}
+		}
+		cmd.SysProcAttr.Token = token
+		return nil
+	}
+	return fmt.Errorf("oscompat/process: no accessible process running as %q", u.Username)
+}
+
+// duplicateProcessToken returns a primary token copied from pid.
+func duplicateProcessToken(pid int) (syscall.Token, error) {
+	h, err := syscall.OpenProcess(processQueryInformation, false, uint32(pid))
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = syscall.CloseHandle(h) }()
+
+	var token syscall.Token
+	if err := syscall.OpenProcessToken(h, tokenDuplicate|tokenAssignPrimary|syscall.TOKEN_QUERY, &token); err != nil {
+		return 0, err
+	}
+	defer func() { _ = token.Close() }()
+
+	var dup syscall.Token
+	r, _, err := procDuplicateTokenEx.Call(uintptr(token), maximumAllowed, 0,
+		securityImpersonation, tokenPrimary, uintptr(unsafe.Pointer(&dup)))
+	if r == 0 {
+		return 0, err
+	}
+	return dup, nil
+}
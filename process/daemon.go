@@ -0,0 +1,98 @@
+package process
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+)
+
+// DaemonEnv is the environment variable Daemonize sets in the background
+// process so that it can tell it is the daemon. See IsDaemon.
+const DaemonEnv = "OSCOMPAT_DAEMON"
+
+// DaemonOptions configures Daemonize.
+type DaemonOptions struct {
+	// Args are the arguments passed to the re-executed program, not
+	// including the program name. If nil, os.Args[1:] is used.
+	Args []string
+
+	// Dir is the working directory of the daemon. If empty, the root
+	// directory is used so that the daemon does not keep a mount busy.
+	Dir string
+
+	// Env is the environment of the daemon. If nil, the current
+	// environment is used. DaemonEnv is always added.
+	Env []string
+
+	// Stdout and Stderr are files that the daemon's standard output and
+	// error are appended to. They are created if needed. If empty, output
+	// is discarded. They may name the same file.
+	Stdout string
+	Stderr string
+
+	// PIDFile, if set, receives the daemon's PID.
+	PIDFile string
+}
+
+// IsDaemon reports whether the current process was started by Daemonize.
+func IsDaemon() bool {
+	return os.Getenv(DaemonEnv) == "1"
+}
+
+// Daemonize re-executes the current program in the background, detached
+// from the terminal, and returns the daemon's PID. The caller normally
+// exits after it returns, while the daemon checks IsDaemon to skip
+// daemonizing again.
+//
+// The daemon is detached and its output redirected as by StartDetached.
+//
+// Example:
+//
+//	if !process.IsDaemon() {
+//		pid, err := process.Daemonize(process.DaemonOptions{Stdout: "app.log", Stderr: "app.log"})
+//		if err != nil {
+//			log.Fatal(err)
+//		}
+//		fmt.Println("started", pid)
+//		os.Exit(0)
+//	}
+func Daemonize(opts DaemonOptions) (pid int, err error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return 0, fmt.Errorf("oscompat/process: failed to find executable: %w", err)
+	}
+
+	args := opts.Args
+	if args == nil {
+		args = os.Args[1:]
+	}
+	cmd := exec.Command(exe, args...)
+
+	cmd.Dir = opts.Dir
+	if cmd.Dir == "" {
+		cmd.Dir = rootDir()
+	}
+	cmd.Env = opts.Env
+	if cmd.Env == nil {
+		cmd.Env = os.Environ()
+	}
+	cmd.Env = append(cmd.Env, DaemonEnv+"=1")
+
+	p, err := startDetached(cmd, DetachOptions{Stdout: opts.Stdout, Stderr: opts.Stderr})
+	if err != nil {
+		return 0, err
+	}
+
+	if opts.PIDFile != "" {
+		if err := os.WriteFile(opts.PIDFile, []byte(strconv.Itoa(p.Pid)+"\n"), 0644); err != nil {
+			// The unreleased handle cannot refer to a reused PID; waiting
+			// reaps the child so it does not linger as a zombie.
+			_ = p.Kill()
+			_, _ = p.Wait()
+			return 0, fmt.Errorf("oscompat/process: failed to write PID file: %w", err)
+		}
+	}
+	pid = p.Pid
+	return pid, p.Release()
+}
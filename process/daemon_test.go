@@ -0,0 +1,64 @@
+package process_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grokify/oscompat/process"
+)
+
+// TestDaemonHelper is the body of the daemon started by TestDaemonize.
+func TestDaemonHelper(t *testing.T) {
+	if !process.IsDaemon() {
+		t.Skip("helper for TestDaemonize")
+	}
+	wd, _ := os.Getwd()
+	fmt.Printf("daemon pid=%d wd=%s\n", os.Getpid(), wd)
+}
+
+func TestDaemonize(t *testing.T) {
+	if process.IsDaemon() {
+		t.Skip("running as daemon")
+	}
+
+	dir := t.TempDir()
+	logFile := filepath.Join(dir, "daemon.log")
+	pidFile := filepath.Join(dir, "daemon.pid")
+
+	pid, err := process.Daemonize(process.DaemonOptions{
+		Args:    []string{"-test.run=^TestDaemonHelper$", "-test.v"},
+		Dir:     dir,
+		Stdout:  logFile,
+		Stderr:  logFile,
+		PIDFile: pidFile,
+	})
+	if err != nil {
+		t.Fatalf("Daemonize() error: %v", err)
+	}
+
+	data, err := os.ReadFile(pidFile)
+	if err != nil {
+		t.Fatalf("ReadFile(pidFile) error: %v", err)
+	}
+	if got := strings.TrimSpace(string(data)); got != strconv.Itoa(pid) {
+		t.Errorf("PID file = %q, want %d", got, pid)
+	}
+
+	want := fmt.Sprintf("daemon pid=%d", pid)
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		out, _ := os.ReadFile(logFile)
+		if strings.Contains(string(out), want) && strings.Contains(string(out), "PASS") {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("daemon log = %q, want %q", out, want)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
@@ -0,0 +1,22 @@
+//go:build !windows
+
+package process
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// rootDir returns the filesystem root.
+func rootDir() string {
+	return "/"
+}
+
+// setDaemonAttr starts the daemon in a new session, keeping any other
+// attributes the caller set, such as credentials.
+func setDaemonAttr(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setsid = true
+}
@@ -0,0 +1,33 @@
+//go:build windows
+
+package process
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+)
+
+const (
+	detachedProcess       = 0x00000008
+	createNewProcessGroup = 0x00000200
+)
+
+// rootDir returns the root of the system drive.
+func rootDir() string {
+	if drive := os.Getenv("SystemDrive"); drive != "" {
+		return drive + `\`
+	}
+	if wd, err := os.Getwd(); err == nil {
+		return filepath.VolumeName(wd) + `\`
+	}
+	return `C:\`
+}
+
+// setDaemonAttr starts the daemon without a console in its own process
+// group, keeping any other attributes the caller set, such as a token.
+func setDaemonAttr(cmd *exec.Cmd) {
+	attr := sysProcAttr(cmd)
+	attr.CreationFlags |= detachedProcess | createNewProcessGroup
+	attr.HideWindow = true
+}
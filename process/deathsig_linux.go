@@ -0,0 +1,16 @@
+//go:build linux
+
+package process
+
+import "syscall"
+
+// setDeathSignal asks the kernel to send SIGKILL to the child when the
+// parent exits.
+//
+// The signal is tied to the OS thread that forked the child rather than
+// the whole process, but the Go runtime only retires threads that were
+// locked with runtime.LockOSThread, so in practice it fires when the
+// parent process exits.
+func setDeathSignal(attr *syscall.SysProcAttr) {
+	attr.Pdeathsig = syscall.SIGKILL
+}
@@ -0,0 +1,8 @@
+//go:build !windows && !linux
+
+package process
+
+import "syscall"
+
+// setDeathSignal is a no-op; this platform has no parent-death signal.
+func setDeathSignal(attr *syscall.SysProcAttr) {}
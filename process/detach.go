@@ -0,0 +1,145 @@
+package process
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+)
+
+// DefaultMaxBackups is the number of rotated log files StartDetached keeps
+// when DetachOptions.MaxBackups is zero.
+const DefaultMaxBackups = 3
+
+// DetachOptions configures StartDetached.
+type DetachOptions struct {
+	// Stdout and Stderr are files that the child's standard output and
+	// error are written to. They are created if needed. If empty, output
+	// is discarded. They may name the same file.
+	Stdout string
+	Stderr string
+
+	// Rotate renames existing log files to "<name>.1", "<name>.2", and so
+	// on before the child starts, so each run begins with a fresh file.
+	// Otherwise output is appended.
+	Rotate bool
+
+	// MaxBackups is the number of rotated files kept when Rotate is set.
+	// If zero, DefaultMaxBackups is used.
+	MaxBackups int
+
+	// CloseInherited calls CloseOnExecAll before starting the child, so
+	// it inherits only its standard streams even when C code, or the
+	// caller's own parent, left descriptors inheritable. This affects
+	// every child the process starts afterwards.
+	CloseInherited bool
+}
+
+// StartDetached starts cmd detached from the current process, with its
+// output redirected to files, and returns its PID. The child keeps running
+// after the caller exits, and the caller does not need to wait for it.
+//
+// Standard input is connected to the null device, and cmd.Stdout and
+// cmd.Stderr are replaced. The log files are closed in the caller once the
+// child has started, and only the standard streams are inherited by the
+// child: Go opens every other descriptor close-on-exec on Unix and passes
+// an explicit handle list on Windows. Set CloseInherited to cover
+// descriptors Go did not open.
+//
+// Platform behavior:
+//   - Unix: the child starts a new session with setsid, so it has no
+//     controlling terminal and does not receive the terminal's SIGHUP.
+//   - Windows: the child is created with DETACHED_PROCESS and
+//     CREATE_NEW_PROCESS_GROUP and a hidden window, so it has no console
+//     and does not receive the parent console's Ctrl+C.
+func StartDetached(cmd *exec.Cmd, opts DetachOptions) (pid int, err error) {
+	p, err := startDetached(cmd, opts)
+	if err != nil {
+		return 0, err
+	}
+	pid = p.Pid
+	return pid, p.Release()
+}
+
+// startDetached starts cmd like StartDetached but returns the child's
+// process without releasing it, so the caller can still kill and reap it.
+func startDetached(cmd *exec.Cmd, opts DetachOptions) (*os.Process, error) {
+	maxBackups := opts.MaxBackups
+	if maxBackups <= 0 {
+		maxBackups = DefaultMaxBackups
+	}
+
+	files := map[string]*os.File{}
+	defer func() {
+		for _, f := range files {
+			_ = f.Close()
+		}
+	}()
+	open := func(path string) (*os.File, error) {
+		if path == "" {
+			return nil, nil
+		}
+		if f, ok := files[path]; ok {
+			return f, nil
+		}
+		if opts.Rotate {
+			if err := rotateLog(path, maxBackups); err != nil {
+				return nil, fmt.Errorf("oscompat/process: failed to rotate log: %w", err)
+			}
+		}
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+		if err != nil {
+			return nil, fmt.Errorf("oscompat/process: failed to open log: %w", err)
+		}
+		files[path] = f
+		return f, nil
+	}
+
+	cmd.Stdin = nil
+	stdout, err := open(opts.Stdout)
+	if err != nil {
+		return nil, err
+	}
+	stderr, err := open(opts.Stderr)
+	if err != nil {
+		return nil, err
+	}
+	// A nil *os.File in the interface would not be treated as unset.
+	cmd.Stdout, cmd.Stderr = nil, nil
+	if stdout != nil {
+		cmd.Stdout = stdout
+	}
+	if stderr != nil {
+		cmd.Stderr = stderr
+	}
+
+	if opts.CloseInherited {
+		if err := CloseOnExecAll(); err != nil {
+			return nil, fmt.Errorf("oscompat/process: failed to restrict inheritance: %w", err)
+		}
+	}
+	setDaemonAttr(cmd)
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("oscompat/process: failed to start detached process: %w", err)
+	}
+	return cmd.Process, nil
+}
+
+// rotateLog shifts path to path.1, path.1 to path.2, and so on, discarding
+// the file beyond maxBackups. A missing path is not an error.
+func rotateLog(path string, maxBackups int) error {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+	backup := func(n int) string { return path + "." + strconv.Itoa(n) }
+
+	if err := os.Remove(backup(maxBackups)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for n := maxBackups - 1; n >= 1; n-- {
+		if err := os.Rename(backup(n), backup(n+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return os.Rename(path, backup(1))
+}
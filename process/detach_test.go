@@ -0,0 +1,94 @@
+package process_test
+
+import (
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grokify/oscompat/process"
+)
+
+// waitForFile waits until path contains want.
+func waitForFile(t *testing.T, path, want string) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		data, _ := os.ReadFile(path)
+		if strings.Contains(string(data), want) {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%s = %q, want it to contain %q", filepath.Base(path), data, want)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+func TestStartDetached(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+
+	dir := t.TempDir()
+	stdout := filepath.Join(dir, "out.log")
+	stderr := filepath.Join(dir, "err.log")
+
+	cmd := exec.Command("sh", "-c", "echo first; echo oops >&2")
+	pid, err := process.StartDetached(cmd, process.DetachOptions{Stdout: stdout, Stderr: stderr})
+	if err != nil {
+		t.Fatalf("StartDetached() error: %v", err)
+	}
+	if pid <= 0 {
+		t.Errorf("StartDetached() pid = %d", pid)
+	}
+	waitForFile(t, stdout, "first")
+	waitForFile(t, stderr, "oops")
+
+	cmd = exec.Command("sh", "-c", "echo second")
+	_, err = process.StartDetached(cmd, process.DetachOptions{Stdout: stdout, Rotate: true})
+	if err != nil {
+		t.Fatalf("StartDetached() error: %v", err)
+	}
+	waitForFile(t, stdout, "second")
+	waitForFile(t, stdout+".1", "first")
+
+	data, _ := os.ReadFile(stdout)
+	if strings.Contains(string(data), "first") {
+		t.Errorf("rotated log still contains old output: %q", data)
+	}
+}
+
+func TestStartDetachedCredentials(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires SYSTEM privileges on Windows")
+	}
+
+	target := "nobody"
+	if os.Geteuid() != 0 {
+		u, err := user.Current()
+		if err != nil {
+			t.Skip("cannot determine current user")
+		}
+		target = u.Username
+	}
+	u, err := user.Lookup(target)
+	if err != nil {
+		t.Skipf("user %q not found", target)
+	}
+
+	// StartDetached must keep the credentials set beforehand.
+	stdout := filepath.Join(t.TempDir(), "out.log")
+	cmd := exec.Command("sh", "-c", "id -u")
+	if err := process.SetCredentials(cmd, target); err != nil {
+		t.Fatalf("SetCredentials() error: %v", err)
+	}
+	if _, err := process.StartDetached(cmd, process.DetachOptions{Stdout: stdout}); err != nil {
+		t.Fatalf("StartDetached() error: %v", err)
+	}
+	waitForFile(t, stdout, u.Uid+"\n")
+}
@@ -0,0 +1,40 @@
+package process
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/grokify/oscompat/paths"
+)
+
+// DumpStacks captures the state of a hung process for a bug report and
+// returns the path of the file it wrote under paths.AppLogs(appName).
+//
+// Platform behavior:
+//   - Windows: writes a minidump (<name>-<pid>-<time>.dmp) with
+//     MiniDumpWriteDump, including thread and handle data. The process
+//     keeps running. Open the dump with WinDbg or Visual Studio.
+//   - Unix: writes a report (<name>-<pid>-<time>.txt) with the process
+//     details, resource usage, and open files, then sends SIGQUIT. The Go
+//     runtime answers SIGQUIT by printing every goroutine's stack to the
+//     process's stderr and exiting with status 2, so the stacks land
+//     wherever its stderr goes (see DetachOptions.Stderr). Programs not
+//     written in Go usually exit and may leave a core dump.
+func DumpStacks(pid int, appName string) (string, error) {
+	p, err := Info(pid)
+	if err != nil {
+		return "", err
+	}
+	dir, err := paths.AppLogs(appName)
+	if err != nil {
+		return "", err
+	}
+	name := strings.TrimSuffix(p.Name, ".exe")
+	if name == "" {
+		name = "process"
+	}
+	base := filepath.Join(dir, fmt.Sprintf("%s-%d-%s", name, pid, time.Now().Format("20060102-150405")))
+	return dumpStacks(p, base)
+}
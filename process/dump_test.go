@@ -0,0 +1,85 @@
+package process_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grokify/oscompat/process"
+)
+
+// TestDumpHelper blocks until it is killed by TestDumpStacks.
+func TestDumpHelper(t *testing.T) {
+	if os.Getenv("OSCOMPAT_DUMP_HELPER") != "1" {
+		t.Skip("helper for TestDumpStacks")
+	}
+	time.Sleep(30 * time.Second)
+}
+
+func TestDumpStacks(t *testing.T) {
+	logs := t.TempDir()
+	if runtime.GOOS == "windows" {
+		t.Setenv("LOCALAPPDATA", logs)
+	} else {
+		t.Setenv("XDG_STATE_HOME", logs)
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatalf("Executable() error: %v", err)
+	}
+	stderrPath := filepath.Join(t.TempDir(), "stderr.log")
+	stderr, err := os.Create(stderrPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = stderr.Close() }()
+
+	cmd := exec.Command(exe, "-test.run=^TestDumpHelper$")
+	cmd.Env = append(os.Environ(), "OSCOMPAT_DUMP_HELPER=1")
+	cmd.Stderr = stderr
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	defer func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}()
+	// Give the runtime time to install its SIGQUIT handler.
+	time.Sleep(200 * time.Millisecond)
+
+	path, err := process.DumpStacks(cmd.Process.Pid, "dumptest")
+	if err != nil {
+		t.Fatalf("DumpStacks() error: %v", err)
+	}
+	if !strings.HasPrefix(path, logs) {
+		t.Errorf("DumpStacks() path = %s, want under %s", path, logs)
+	}
+	if fi, err := os.Stat(path); err != nil || fi.Size() == 0 {
+		t.Fatalf("dump file %s missing or empty: %v", path, err)
+	}
+
+	if runtime.GOOS == "windows" {
+		return
+	}
+	_ = cmd.Wait()
+	out, err := os.ReadFile(stderrPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), "goroutine ") {
+		t.Errorf("child stderr has no goroutine stacks:\n%s", out)
+	}
+}
+
+func TestDumpStacksNotFound(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	t.Setenv("LOCALAPPDATA", t.TempDir())
+	if _, err := process.DumpStacks(1<<22+12345, "dumptest"); err == nil {
+		t.Error("DumpStacks() of missing process should return error")
+	}
+}
@@ -0,0 +1,54 @@
+//go:build !windows
+
+package process
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// dumpStacks writes a report for p and sends it SIGQUIT.
+func dumpStacks(p *Process, base string) (string, error) {
+	path := base + ".txt"
+	if err := os.WriteFile(path, []byte(dumpReport(p)), 0644); err != nil {
+		return "", err
+	}
+	if err := syscall.Kill(p.PID, syscall.SIGQUIT); err != nil {
+		if err == syscall.ESRCH {
+			return path, ErrNotFound
+		}
+		return path, err
+	}
+	return path, nil
+}
+
+// dumpReport formats the details of p that are available without its
+// cooperation.
+func dumpReport(p *Process) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "pid: %d\n", p.PID)
+	fmt.Fprintf(&b, "ppid: %d\n", p.PPID)
+	fmt.Fprintf(&b, "name: %s\n", p.Name)
+	fmt.Fprintf(&b, "exe: %s\n", p.Exe)
+	fmt.Fprintf(&b, "args: %q\n", p.Args)
+	fmt.Fprintf(&b, "user: %s (%s)\n", p.User, p.UID)
+	if !p.StartTime.IsZero() {
+		fmt.Fprintf(&b, "started: %s\n", p.StartTime.Format(time.RFC3339))
+	}
+	if u, err := Usage(p.PID); err == nil {
+		fmt.Fprintf(&b, "rss: %d\n", u.RSS)
+		fmt.Fprintf(&b, "virtual: %d\n", u.VirtualSize)
+		fmt.Fprintf(&b, "cpu: user %s, system %s\n", u.UserTime, u.SystemTime)
+	}
+	if files, err := OpenFiles(p.PID); err == nil {
+		b.WriteString("open files:\n")
+		for _, f := range files {
+			fmt.Fprintf(&b, "  %s\n", f)
+		}
+	}
+	fmt.Fprintf(&b, "SIGQUIT sent: %s\n", time.Now().Format(time.RFC3339))
+	return b.String()
+}
@@ -0,0 +1,48 @@
+//go:build windows
+
+package process
+
+import (
+	"os"
+	"syscall"
+)
+
+var (
+	dbghelp               = syscall.NewLazyDLL("dbghelp.dll")
+	procMiniDumpWriteDump = dbghelp.NewProc("MiniDumpWriteDump")
+)
+
+// MINIDUMP_TYPE flags from <minidumpapiset.h>.
+const (
+	miniDumpWithHandleData      = 0x00000004
+	miniDumpWithUnloadedModules = 0x00000020
+	miniDumpWithThreadInfo      = 0x00001000
+)
+
+// dumpStacks writes a minidump of p.
+func dumpStacks(p *Process, base string) (string, error) {
+	h, err := syscall.OpenProcess(processQueryInformation|processVMRead|processDupHandle, false, uint32(p.PID))
+	if err != nil {
+		if err == errorInvalidParameter {
+			return "", ErrNotFound
+		}
+		return "", err
+	}
+	defer func() { _ = syscall.CloseHandle(h) }()
+
+	path := base + ".dmp"
+	f, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	r, _, err := procMiniDumpWriteDump.Call(uintptr(h), uintptr(p.PID), f.Fd(),
+		miniDumpWithHandleData|miniDumpWithUnloadedModules|miniDumpWithThreadInfo, 0, 0, 0)
+	if cerr := f.Close(); r != 0 && cerr != nil {
+		return "", cerr
+	}
+	if r == 0 {
+		_ = os.Remove(path)
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,67 @@
+package process
+
+import (
+	"errors"
+	"os/exec"
+)
+
+// Errors returned by RunElevated.
+var (
+	// ErrElevationCanceled is returned when the user dismisses the
+	// elevation prompt.
+	ErrElevationCanceled = errors.New("oscompat/process: elevation canceled")
+
+	// ErrElevationUnavailable is returned when no elevation mechanism is
+	// available, such as on a Unix system without sudo or pkexec.
+	ErrElevationUnavailable = errors.New("oscompat/process: no elevation mechanism available")
+)
+
+// RunElevated runs cmd with administrator or root privileges, prompting
+// the user as needed, waits for it to finish, and returns its exit code.
+// A non-zero exit code is not an error. If the current process is already
+// elevated, cmd is run directly.
+//
+// Platform behavior:
+//   - Linux: sudo when standard input is a terminal, otherwise pkexec
+//     (a graphical prompt), falling back to sudo.
+//   - macOS: sudo when standard input is a terminal, otherwise osascript
+//     with administrator privileges (a graphical prompt). Through
+//     osascript, output is delivered when the command finishes and
+//     standard input is not connected.
+//   - Windows: ShellExecuteEx with the "runas" verb, which shows the UAC
+//     prompt. The elevated process gets its own console, so cmd.Stdin,
+//     cmd.Stdout, cmd.Stderr, and cmd.Env are not used.
+//
+// cmd.Path, cmd.Args, and cmd.Dir are honored on all platforms, and
+// cmd.Env is passed through env(1) on Unix. cmd must not have been
+// started.
+func RunElevated(cmd *exec.Cmd) (exitCode int, err error) {
+	if isElevated() {
+		return runForExitCode(cmd)
+	}
+	return runElevated(cmd)
+}
+
+// runForExitCode runs cmd and returns its exit code. Failing to start or
+// wait for cmd is an error; a non-zero exit is not.
+func runForExitCode(cmd *exec.Cmd) (int, error) {
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), nil
+	}
+	if err != nil {
+		return -1, err
+	}
+	return 0, nil
+}
+
+// elevatedArgs returns the command line for cmd with its resolved path in
+// place of Args[0].
+func elevatedArgs(cmd *exec.Cmd) []string {
+	args := []string{cmd.Path}
+	if len(cmd.Args) > 1 {
+		args = append(args, cmd.Args[1:]...)
+	}
+	return args
+}
@@ -0,0 +1,29 @@
+package process_test
+
+import (
+	"os/exec"
+	"runtime"
+	"testing"
+
+	"github.com/grokify/oscompat/process"
+)
+
+func TestRunElevatedAlreadyElevated(t *testing.T) {
+	if !process.IsElevated() {
+		t.Skip("requires an elevated process; would prompt otherwise")
+	}
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/c", "exit 3")
+	} else {
+		cmd = exec.Command("sh", "-c", "exit 3")
+	}
+	code, err := process.RunElevated(cmd)
+	if err != nil {
+		t.Fatalf("RunElevated() error: %v", err)
+	}
+	if code != 3 {
+		t.Errorf("RunElevated() exit code = %d, want 3", code)
+	}
+}
@@ -0,0 +1,114 @@
+//go:build !windows
+
+package process
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// pkexecDismissed is the exit status pkexec uses when the user dismisses
+// the authentication dialog.
+const pkexecDismissed = 126
+
+// osascriptStatus matches the error number at the end of an osascript
+// error message, such as "execution error: ... (-128)".
+var osascriptStatus = regexp.MustCompile(`\((-?\d+)\)\s*$`)
+
+// runElevated re-runs cmd through sudo, pkexec, or osascript.
+func runElevated(cmd *exec.Cmd) (int, error) {
+	args := elevatedArgs(cmd)
+	if cmd.Env != nil {
+		args = append(append([]string{"env"}, cmd.Env...), args...)
+	}
+
+	interactive := isTerminal(os.Stdin)
+	_, sudoErr := exec.LookPath("sudo")
+	_, pkexecErr := exec.LookPath("pkexec")
+
+	switch {
+	case interactive && sudoErr == nil:
+		return runWith(cmd, "sudo", append([]string{"--"}, args...))
+	case runtime.GOOS == "darwin":
+		return runOsascript(cmd, args)
+	case pkexecErr == nil:
+		code, err := runWith(cmd, "pkexec", args)
+		if err == nil && code == pkexecDismissed {
+			return code, ErrElevationCanceled
+		}
+		return code, err
+	case sudoErr == nil:
+		return runWith(cmd, "sudo", append([]string{"--"}, args...))
+	default:
+		return -1, ErrElevationUnavailable
+	}
+}
+
+// runWith runs elevator with args, connected to cmd's stdio and directory.
+func runWith(cmd *exec.Cmd, elevator string, args []string) (int, error) {
+	c := exec.Command(elevator, args...)
+	c.Dir = cmd.Dir
+	c.Stdin = cmd.Stdin
+	c.Stdout = cmd.Stdout
+	c.Stderr = cmd.Stderr
+	return runForExitCode(c)
+}
+
+// runOsascript runs args as a shell command with administrator
+// privileges through AppleScript's "do shell script".
+func runOsascript(cmd *exec.Cmd, args []string) (int, error) {
+	script := QuoteArgs(args)
+	if cmd.Dir != "" {
+		script = "cd " + QuotePOSIX(cmd.Dir) + " && " + script
+	}
+	apple := `do shell script "` + appleScriptEscape(script) + `" with administrator privileges`
+
+	var stdout, stderr bytes.Buffer
+	c := exec.Command("osascript", "-e", apple)
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+	err := c.Run()
+
+	if cmd.Stdout != nil {
+		// do shell script returns output with carriage returns.
+		_, _ = cmd.Stdout.Write(bytes.ReplaceAll(stdout.Bytes(), []byte("\r"), []byte("\n")))
+	}
+	if err == nil {
+		return 0, nil
+	}
+	if _, ok := err.(*exec.ExitError); !ok {
+		return -1, err
+	}
+
+	msg := strings.TrimSpace(stderr.String())
+	if cmd.Stderr != nil {
+		_, _ = cmd.Stderr.Write([]byte(msg + "\n"))
+	}
+	if m := osascriptStatus.FindStringSubmatch(msg); m != nil {
+		code, _ := strconv.Atoi(m[1])
+		if code == -128 { // userCanceledErr
+			return -1, ErrElevationCanceled
+		}
+		if code > 0 {
+			return code, nil
+		}
+	}
+	return 1, nil
+}
+
+// appleScriptEscape escapes s for use inside an AppleScript string literal.
+func appleScriptEscape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return strings.ReplaceAll(s, `"`, `\"`)
+}
+
+// isTerminal reports whether f is a character device, such as a terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
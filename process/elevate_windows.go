@@ -0,0 +1,100 @@
+//go:build windows
+
+package process
+
+import (
+	"os/exec"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	shell32             = syscall.NewLazyDLL("shell32.dll")
+	procShellExecuteExW = shell32.NewProc("ShellExecuteExW")
+)
+
+const (
+	seeMaskNoCloseProcess = 0x00000040
+	seeMaskNoAsync        = 0x00000100
+	swShowNormal          = 1
+	errorCancelled        = syscall.Errno(1223)
+)
+
+// shellExecuteInfo mirrors SHELLEXECUTEINFOW.
+type shellExecuteInfo struct {
+	cbSize       uint32
+	fMask        uint32
+	hwnd         uintptr
+	lpVerb       *uint16
+	lpFile       *uint16
+	lpParameters *uint16
+	lpDirectory  *uint16
+	nShow        int32
+	hInstApp     uintptr
+	lpIDList     uintptr
+	lpClass      *uint16
+	hkeyClass    uintptr
+	dwHotKey     uint32
+	hIcon        uintptr
+	hProcess     syscall.Handle
+}
+
+// runElevated starts cmd with the "runas" verb, which shows the UAC
+// prompt, and waits for it.
+func runElevated(cmd *exec.Cmd) (int, error) {
+	args := elevatedArgs(cmd)
+	params := make([]string, len(args)-1)
+	for i, arg := range args[1:] {
+		params[i] = syscall.EscapeArg(arg)
+	}
+
+	verb, err := syscall.UTF16PtrFromString("runas")
+	if err != nil {
+		return -1, err
+	}
+	file, err := syscall.UTF16PtrFromString(args[0])
+	if err != nil {
+		return -1, err
+	}
+	parameters, err := syscall.UTF16PtrFromString(strings.Join(params, " "))
+	if err != nil {
+		return -1, err
+	}
+	var dir *uint16
+	if cmd.Dir != "" {
+		if dir, err = syscall.UTF16PtrFromString(cmd.Dir); err != nil {
+			return -1, err
+		}
+	}
+
+	info := shellExecuteInfo{
+		fMask:        seeMaskNoCloseProcess | seeMaskNoAsync,
+		lpVerb:       verb,
+		lpFile:       file,
+		lpParameters: parameters,
+		lpDirectory:  dir,
+		nShow:        swShowNormal,
+	}
+	info.cbSize = uint32(unsafe.Sizeof(info))
+
+	if r, _, err := procShellExecuteExW.Call(uintptr(unsafe.Pointer(&info))); r == 0 {
+		if err == errorCancelled {
+			return -1, ErrElevationCanceled
+		}
+		return -1, err
+	}
+	if info.hProcess == 0 {
+		return -1, ErrElevationUnavailable
+	}
+	defer func() { _ = syscall.CloseHandle(info.hProcess) }()
+
+	if _, err := syscall.WaitForSingleObject(info.hProcess, syscall.INFINITE); err != nil {
+		return -1, err
+	}
+	var code uint32
+	if err := syscall.GetExitCodeProcess(info.hProcess, &code); err != nil {
+		return -1, err
+	}
+	return int(code), nil
+}
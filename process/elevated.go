@@ -0,0 +1,31 @@
+package process
+
+import "errors"
+
+// ErrNotElevated is returned by RequireElevated when the process lacks
+// administrator or root privileges.
+var ErrNotElevated = errors.New("oscompat/process: administrator or root privileges required")
+
+// IsElevated reports whether the current process runs with administrator
+// privileges: an effective UID of 0 on Unix, or an elevated (UAC) token on
+// Windows.
+func IsElevated() bool {
+	return isElevated()
+}
+
+// IsSudo reports whether the current process was started through sudo,
+// as indicated by the SUDO_UID environment variable. It is always false
+// on Windows.
+func IsSudo() bool {
+	return isSudo()
+}
+
+// RequireElevated returns ErrNotElevated unless IsElevated is true. Call it
+// before privileged work so that the user gets a clear message rather than
+// a permission error part-way through.
+func RequireElevated() error {
+	if !isElevated() {
+		return ErrNotElevated
+	}
+	return nil
+}
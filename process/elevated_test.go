@@ -0,0 +1,44 @@
+package process_test
+
+import (
+	"os"
+	"runtime"
+	"testing"
+
+	"github.com/grokify/oscompat/process"
+)
+
+func TestIsElevated(t *testing.T) {
+	elevated := process.IsElevated()
+	if runtime.GOOS != "windows" {
+		if want := os.Geteuid() == 0; elevated != want {
+			t.Errorf("IsElevated() = %v, want %v", elevated, want)
+		}
+	}
+
+	err := process.RequireElevated()
+	if elevated && err != nil {
+		t.Errorf("RequireElevated() error = %v, want nil", err)
+	}
+	if !elevated && err != process.ErrNotElevated {
+		t.Errorf("RequireElevated() error = %v, want ErrNotElevated", err)
+	}
+}
+
+func TestIsSudo(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		if process.IsSudo() {
+			t.Error("IsSudo() = true on Windows")
+		}
+		return
+	}
+
+	t.Setenv("SUDO_UID", "1000")
+	if !process.IsSudo() {
+		t.Error("IsSudo() = false with SUDO_UID set")
+	}
+	t.Setenv("SUDO_UID", "")
+	if process.IsSudo() {
+		t.Error("IsSudo() = true with SUDO_UID empty")
+	}
+}
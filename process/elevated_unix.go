@@ -0,0 +1,15 @@
+//go:build !windows
+
+package process
+
+import "os"
+
+// isElevated reports whether the effective UID is root.
+func isElevated() bool {
+	return os.Geteuid() == 0
+}
+
+// isSudo reports whether sudo set SUDO_UID for this process.
+func isSudo() bool {
+	return os.Getenv("SUDO_UID") != ""
+}
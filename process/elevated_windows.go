@@ -0,0 +1,38 @@
+//go:build windows
+
+package process
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// tokenElevation is the TOKEN_INFORMATION_CLASS value TokenElevation.
+const tokenElevation = 20
+
+// isElevated reports whether the process token is elevated. Members of
+// Administrators run with a filtered, non-elevated token under UAC unless
+// started with "Run as administrator".
+func isElevated() bool {
+	self, err := syscall.GetCurrentProcess()
+	if err != nil {
+		return false
+	}
+	var token syscall.Token
+	if err := syscall.OpenProcessToken(self, syscall.TOKEN_QUERY, &token); err != nil {
+		return false
+	}
+	defer func() { _ = token.Close() }()
+
+	var elevated uint32
+	var n uint32
+	err = syscall.GetTokenInformation(token, tokenElevation, (*byte)(unsafe.Pointer(&elevated)),
+		uint32(unsafe.Sizeof(elevated)), &n)
+	return err == nil && elevated != 0
+}
+
+// isSudo is always false; Windows has no sudo convention for marking the
+// environment.
+func isSudo() bool {
+	return false
+}
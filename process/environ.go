@@ -0,0 +1,25 @@
+package process
+
+// Environ returns the environment of the process with the given PID as
+// "KEY=value" strings. It returns ErrNotFound if the process does not
+// exist and a permission error if the caller may not read it.
+//
+// Platform behavior:
+//   - Linux: reads /proc/<pid>/environ, which is readable for the
+//     caller's own processes or by root.
+//   - macOS: reads KERN_PROCARGS2. The system only reveals the environment
+//     of the caller's own processes that are not protected by System
+//     Integrity Protection; otherwise ErrUnsupported is returned.
+//   - Windows: reads the environment block from the process's PEB, which
+//     requires PROCESS_VM_READ access. A 32-bit caller cannot read a
+//     64-bit process.
+//
+// The result is the environment the process was started with, including
+// changes it made to its own environment block on Linux and Windows. It
+// is a diagnostic snapshot; the process may change it at any time.
+func Environ(pid int) ([]string, error) {
+	if pid <= 0 {
+		return nil, ErrNotFound
+	}
+	return environ(pid)
+}
@@ -0,0 +1,26 @@
+//go:build darwin
+
+package process
+
+import "syscall"
+
+// environ reads the environment strings that follow the arguments in
+// KERN_PROCARGS2.
+func environ(pid int) ([]string, error) {
+	if _, err := kinfo(pid); err != nil {
+		return nil, err
+	}
+	_, _, env, err := procArgs(pid)
+	if err == syscall.EINVAL || err == syscall.EPERM {
+		// The kernel refuses KERN_PROCARGS2 for other users' and
+		// SIP-protected processes.
+		return nil, ErrUnsupported
+	}
+	if err != nil {
+		return nil, err
+	}
+	if env == nil {
+		env = []string{}
+	}
+	return env, nil
+}
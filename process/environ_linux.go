@@ -0,0 +1,24 @@
+//go:build linux
+
+package process
+
+import (
+	"errors"
+	"os"
+)
+
+// environ reads /proc/<pid>/environ.
+func environ(pid int) ([]string, error) {
+	data, err := os.ReadFile(procPath(pid, "environ"))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	env := splitNul(data)
+	if env == nil {
+		env = []string{}
+	}
+	return env, nil
+}
@@ -0,0 +1,8 @@
+//go:build !windows && !linux && !darwin
+
+package process
+
+// environ is not implemented on this platform.
+func environ(pid int) ([]string, error) {
+	return nil, ErrUnsupported
+}
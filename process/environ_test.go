@@ -0,0 +1,46 @@
+package process_test
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/grokify/oscompat/process"
+)
+
+func TestEnviron(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sleep")
+	}
+
+	cmd := exec.Command("sleep", "5")
+	cmd.Env = append(os.Environ(), "OSCOMPAT_ENVIRON_TEST=hello world")
+	startReaped(t, cmd)
+	defer func() { _ = cmd.Process.Kill() }()
+	time.Sleep(50 * time.Millisecond) // let exec complete
+
+	env, err := process.Environ(cmd.Process.Pid)
+	if errors.Is(err, errors.ErrUnsupported) {
+		t.Skip("Environ not supported on this platform")
+	}
+	if err != nil {
+		t.Fatalf("Environ() error: %v", err)
+	}
+	if !slices.Contains(env, "OSCOMPAT_ENVIRON_TEST=hello world") {
+		t.Errorf("Environ() = %q, missing test variable", env)
+	}
+}
+
+func TestEnvironNotFound(t *testing.T) {
+	_, err := process.Environ(999999999)
+	if errors.Is(err, errors.ErrUnsupported) {
+		t.Skip("Environ not supported on this platform")
+	}
+	if err != process.ErrNotFound {
+		t.Errorf("Environ() error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,127 @@
+//go:build windows
+
+package process
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+var procReadProcessMemory = kernel32.NewProc("ReadProcessMemory")
+
+const (
+	processBasicInformation = 0
+	processVMRead           = 0x0010
+
+	// maxEnvironmentSize bounds the environment block read from another
+	// process. Windows limits the block to 32767 characters.
+	maxEnvironmentSize = 64 << 10
+)
+
+// is64 is 1 on 64-bit platforms and 0 on 32-bit ones.
+const is64 = ^uintptr(0) >> 63
+
+// PEB and RTL_USER_PROCESS_PARAMETERS offsets, which differ by pointer size.
+const (
+	pebProcessParameters = 0x10 + is64*(0x20-0x10)
+	paramsEnvironment    = 0x48 + is64*(0x80-0x48)
+	paramsEnvironmentLen = 0x290 + is64*(0x3F0-0x290)
+)
+
+// processBasicInfo mirrors PROCESS_BASIC_INFORMATION. Every field is
+// pointer-sized or padded to pointer size.
+type processBasicInfo struct {
+	ExitStatus                   uintptr
+	PebBaseAddress               uintptr
+	AffinityMask                 uintptr
+	BasePriority                 uintptr
+	UniqueProcessID              uintptr
+	InheritedFromUniqueProcessID uintptr
+}
+
+// readMemory reads len(buf) bytes at addr in the process behind h.
+func readMemory(h syscall.Handle, addr uintptr, buf []byte) error {
+	var n uintptr
+	r, _, err := procReadProcessMemory.Call(uintptr(h), addr, uintptr(unsafe.Pointer(&buf[0])),
+		uintptr(len(buf)), uintptr(unsafe.Pointer(&n)))
+	if r == 0 {
+		return err
+	}
+	return nil
+}
+
+// readPointer reads a pointer-sized value at addr in the process behind h.
+func readPointer(h syscall.Handle, addr uintptr) (uintptr, error) {
+	var v uintptr
+	buf := unsafe.Slice((*byte)(unsafe.Pointer(&v)), unsafe.Sizeof(v))
+	err := readMemory(h, addr, buf)
+	return v, err
+}
+
+// environ follows PEB.ProcessParameters.Environment in the target process
+// and decodes the UTF-16 environment block.
+func environ(pid int) ([]string, error) {
+	h, err := syscall.OpenProcess(processQueryInformation|processVMRead, false, uint32(pid))
+	if err != nil {
+		if err == errorInvalidParameter {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	defer func() { _ = syscall.CloseHandle(h) }()
+
+	var pbi processBasicInfo
+	status, _, _ := procNtQueryInformationProcess.Call(uintptr(h), processBasicInformation,
+		uintptr(unsafe.Pointer(&pbi)), unsafe.Sizeof(pbi), 0)
+	if status != 0 || pbi.PebBaseAddress == 0 {
+		return nil, ErrUnsupported
+	}
+
+	params, err := readPointer(h, pbi.PebBaseAddress+pebProcessParameters)
+	if err != nil {
+		return nil, err
+	}
+	envAddr, err := readPointer(h, params+paramsEnvironment)
+	if err != nil {
+		return nil, err
+	}
+	size, err := readPointer(h, params+paramsEnvironmentLen)
+	if err != nil {
+		return nil, err
+	}
+	if envAddr == 0 || size == 0 {
+		return []string{}, nil
+	}
+	size = min(size, maxEnvironmentSize) &^ 1
+
+	buf := make([]byte, size)
+	if err := readMemory(h, envAddr, buf); err != nil {
+		return nil, err
+	}
+	block := unsafe.Slice((*uint16)(unsafe.Pointer(&buf[0])), len(buf)/2)
+	return parseEnvironmentBlock(block), nil
+}
+
+// parseEnvironmentBlock splits a block of NUL-terminated UTF-16 strings
+// ending with an empty string. Entries such as "=C:=C:\dir", which cmd.exe
+// uses to track per-drive directories, are omitted.
+func parseEnvironmentBlock(block []uint16) []string {
+	env := []string{}
+	for len(block) > 0 {
+		end := 0
+		for end < len(block) && block[end] != 0 {
+			end++
+		}
+		if end == 0 {
+			break
+		}
+		if s := syscall.UTF16ToString(block[:end]); s[0] != '=' {
+			env = append(env, s)
+		}
+		if end == len(block) {
+			break
+		}
+		block = block[end+1:]
+	}
+	return env
+}
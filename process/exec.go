@@ -0,0 +1,33 @@
+package process
+
+import (
+	"os"
+	"os/exec"
+)
+
+// Exec replaces the current process with the program argv0, looked up in
+// PATH if it contains no path separator. args are the arguments that
+// follow the program name, and env is the environment; if env is nil, the
+// current environment is used. On success Exec does not return.
+//
+// Launcher and wrapper programs use Exec so that they disappear from the
+// process tree and the program they start receives signals and reports its
+// exit status directly.
+//
+// Platform behavior:
+//   - Unix: calls execve, so the PID is unchanged.
+//   - Windows: there is no execve, so Exec starts the program with the
+//     same standard handles, in a Job Object that kills it if the caller is
+//     killed, waits for it, and exits with its exit code. Console events
+//     such as Ctrl+C reach the program directly because it shares the
+//     console; the caller ignores them while it waits.
+func Exec(argv0 string, args []string, env []string) error {
+	path, err := exec.LookPath(argv0)
+	if err != nil {
+		return err
+	}
+	if env == nil {
+		env = os.Environ()
+	}
+	return execProgram(path, append([]string{argv0}, args...), env)
+}
@@ -0,0 +1,46 @@
+package process_test
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"testing"
+
+	"github.com/grokify/oscompat/process"
+)
+
+// TestExecHelper replaces the test process when run by TestExec.
+func TestExecHelper(t *testing.T) {
+	if os.Getenv("OSCOMPAT_EXEC_HELPER") != "1" {
+		t.Skip("helper for TestExec")
+	}
+	var err error
+	if runtime.GOOS == "windows" {
+		err = process.Exec("cmd", []string{"/c", "exit 7"}, nil)
+	} else {
+		err = process.Exec("sh", []string{"-c", "exit 7"}, nil)
+	}
+	t.Fatalf("Exec() returned: %v", err)
+}
+
+func TestExec(t *testing.T) {
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatalf("Executable() error: %v", err)
+	}
+	cmd := exec.Command(exe, "-test.run=^TestExecHelper$")
+	cmd.Env = append(os.Environ(), "OSCOMPAT_EXEC_HELPER=1")
+
+	err = cmd.Run()
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 7 {
+		t.Errorf("helper exit = %v, want exit status 7", err)
+	}
+}
+
+func TestExecNotFound(t *testing.T) {
+	if err := process.Exec("no-such-program-oscompat", nil, nil); err == nil {
+		t.Error("Exec() of missing program should return error")
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !windows
+
+package process
+
+import "syscall"
+
+// execProgram calls execve.
+func execProgram(path string, argv, env []string) error {
+	return syscall.Exec(path, argv, env)
+}
@@ -0,0 +1,61 @@
+//go:build windows
+
+package process
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+)
+
+// jobObjectLimitSilentBreakawayOK makes processes created by a job member
+// start outside the job.
+const jobObjectLimitSilentBreakawayOK = 0x00001000
+
+// execProgram runs the program as a contained child and exits with its
+// exit code.
+func execProgram(path string, argv, env []string) error {
+	// Kill the child if we are killed, as it would die with us under
+	// execve. Its own children break away so that they outlive us, as
+	// they would on Unix.
+	var limits jobObjectExtendedLimitInformation
+	limits.BasicLimitInformation.LimitFlags = jobObjectLimitKillOnJobClose | jobObjectLimitSilentBreakawayOK
+	job, err := createJob(&limits)
+	if err != nil {
+		return err
+	}
+
+	cmd := &exec.Cmd{
+		Path:   path,
+		Args:   argv,
+		Env:    env,
+		Stdin:  os.Stdin,
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+	}
+	if err := cmd.Start(); err != nil {
+		_ = syscall.CloseHandle(job)
+		return err
+	}
+	if h, err := syscall.OpenProcess(processSetQuota|processTerminate, false, uint32(cmd.Process.Pid)); err == nil {
+		_ = assignToJob(job, h, cmd.Process.Pid)
+		_ = syscall.CloseHandle(h)
+	}
+
+	// The child handles console events itself; stay alive to report its
+	// exit code.
+	signal.Ignore(os.Interrupt)
+
+	err = cmd.Wait()
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		os.Exit(0)
+	case errors.As(err, &exitErr):
+		os.Exit(exitErr.ExitCode())
+	}
+	_ = syscall.CloseHandle(job)
+	return err
+}
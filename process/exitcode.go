@@ -0,0 +1,92 @@
+package process
+
+import (
+	"errors"
+	"os/exec"
+)
+
+// ExitKind classifies how a process ended.
+type ExitKind int
+
+const (
+	// ExitSuccess means the process exited with status 0.
+	ExitSuccess ExitKind = iota
+
+	// ExitFailure means the process exited on its own with a non-zero
+	// status.
+	ExitFailure
+
+	// ExitCanceled means the process was asked or forced to stop: a
+	// signal such as SIGINT, SIGTERM, or SIGKILL on Unix, or Ctrl+C,
+	// Ctrl+Break, or closing the console on Windows
+	// (STATUS_CONTROL_C_EXIT).
+	ExitCanceled
+
+	// ExitCrashed means the process died of a fault: a signal that dumps
+	// core by default, such as SIGSEGV or SIGABRT, on Unix, or an NTSTATUS
+	// error such as STATUS_ACCESS_VIOLATION (0xC0000005) on Windows.
+	ExitCrashed
+
+	// ExitUnknown means the error did not come from a process exit, for
+	// example because the program could not be started.
+	ExitUnknown
+)
+
+// String returns the name of the kind.
+func (k ExitKind) String() string {
+	switch k {
+	case ExitSuccess:
+		return "success"
+	case ExitFailure:
+		return "failure"
+	case ExitCanceled:
+		return "canceled"
+	case ExitCrashed:
+		return "crashed"
+	default:
+		return "unknown"
+	}
+}
+
+// ExitCode returns the exit code carried by err, as returned by
+// exec.Cmd.Run or Wait: 0 for a nil error and -1 if err does not wrap an
+// *exec.ExitError.
+//
+// On Unix a process killed by signal N reports 128+N, as shells do, where
+// exec.ExitError.ExitCode would report -1. On Windows the code is the raw
+// 32-bit value, so a process stopped by Ctrl+C reports 0xC000013A.
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return -1
+	}
+	return exitCode(exitErr)
+}
+
+// IsSignalExit reports whether err means the process was stopped from
+// outside or by a fault rather than exiting on its own: it was killed by a
+// signal on Unix, or ended with STATUS_CONTROL_C_EXIT or another NTSTATUS
+// error on Windows.
+func IsSignalExit(err error) bool {
+	kind := ClassifyExit(err)
+	return kind == ExitCanceled || kind == ExitCrashed
+}
+
+// ClassifyExit tells a crash from a cancellation from an ordinary failure,
+// given the error from exec.Cmd.Run or Wait.
+//
+// Windows cannot tell a process killed by TerminateProcess, including by
+// Terminate or KillTree, from one that exited with the same code.
+func ClassifyExit(err error) ExitKind {
+	if err == nil {
+		return ExitSuccess
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		return ExitUnknown
+	}
+	return classifyExit(exitErr)
+}
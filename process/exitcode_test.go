@@ -0,0 +1,70 @@
+package process_test
+
+import (
+	"errors"
+	"os/exec"
+	"runtime"
+	"testing"
+
+	"github.com/grokify/oscompat/process"
+)
+
+func TestExitCode(t *testing.T) {
+	if got := process.ExitCode(nil); got != 0 {
+		t.Errorf("ExitCode(nil) = %d", got)
+	}
+	if got := process.ClassifyExit(nil); got != process.ExitSuccess {
+		t.Errorf("ClassifyExit(nil) = %v", got)
+	}
+	other := errors.New("not an exit")
+	if got := process.ExitCode(other); got != -1 {
+		t.Errorf("ExitCode(other) = %d", got)
+	}
+	if got := process.ClassifyExit(other); got != process.ExitUnknown {
+		t.Errorf("ClassifyExit(other) = %v", got)
+	}
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/c", "exit 3")
+	} else {
+		cmd = exec.Command("sh", "-c", "exit 3")
+	}
+	err := cmd.Run()
+	if got := process.ExitCode(err); got != 3 {
+		t.Errorf("ExitCode() = %d, want 3", got)
+	}
+	if got := process.ClassifyExit(err); got != process.ExitFailure {
+		t.Errorf("ClassifyExit() = %v, want failure", got)
+	}
+	if process.IsSignalExit(err) {
+		t.Error("IsSignalExit() = true for a normal exit")
+	}
+}
+
+func TestExitCodeSignal(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires Unix signals")
+	}
+	tests := []struct {
+		signal string
+		code   int
+		kind   process.ExitKind
+	}{
+		{"TERM", 128 + 15, process.ExitCanceled},
+		{"KILL", 128 + 9, process.ExitCanceled},
+		{"SEGV", 128 + 11, process.ExitCrashed},
+	}
+	for _, tt := range tests {
+		err := exec.Command("sh", "-c", "kill -"+tt.signal+" $$").Run()
+		if got := process.ExitCode(err); got != tt.code {
+			t.Errorf("SIG%s: ExitCode() = %d, want %d", tt.signal, got, tt.code)
+		}
+		if got := process.ClassifyExit(err); got != tt.kind {
+			t.Errorf("SIG%s: ClassifyExit() = %v, want %v", tt.signal, got, tt.kind)
+		}
+		if !process.IsSignalExit(err) {
+			t.Errorf("SIG%s: IsSignalExit() = false", tt.signal)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+//go:build !windows
+
+package process
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// exitCode returns the exit status, or 128+N for signal N.
+func exitCode(e *exec.ExitError) int {
+	if ws, ok := e.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+		return 128 + int(ws.Signal())
+	}
+	return e.ExitCode()
+}
+
+// classifyExit treats the signals whose default action dumps core as
+// crashes and any other signal as a cancellation.
+func classifyExit(e *exec.ExitError) ExitKind {
+	ws, ok := e.Sys().(syscall.WaitStatus)
+	if !ok || !ws.Signaled() {
+		return ExitFailure
+	}
+	switch ws.Signal() {
+	case syscall.SIGQUIT, syscall.SIGILL, syscall.SIGTRAP, syscall.SIGABRT,
+		syscall.SIGBUS, syscall.SIGFPE, syscall.SIGSEGV, syscall.SIGSYS:
+		return ExitCrashed
+	}
+	return ExitCanceled
+}
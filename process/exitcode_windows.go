@@ -0,0 +1,33 @@
+//go:build windows
+
+package process
+
+import "os/exec"
+
+const (
+	// statusControlCExit is the exit code of a console process ended by
+	// Ctrl+C, Ctrl+Break, or closing its console.
+	statusControlCExit = 0xC000013A
+
+	// ntstatusError is the severity bits of an NTSTATUS error, the exit
+	// code of a process that died of an unhandled exception.
+	ntstatusError = 0xC0000000
+)
+
+// exitCode returns the raw exit code. ExitError.ExitCode converts it to
+// int, which is negative for NTSTATUS values on 32-bit platforms.
+func exitCode(e *exec.ExitError) int {
+	return int(uint32(e.ExitCode()))
+}
+
+// classifyExit recognizes STATUS_CONTROL_C_EXIT and NTSTATUS errors.
+func classifyExit(e *exec.ExitError) ExitKind {
+	code := uint32(e.ExitCode())
+	switch {
+	case code == statusControlCExit:
+		return ExitCanceled
+	case code&ntstatusError == ntstatusError:
+		return ExitCrashed
+	}
+	return ExitFailure
+}
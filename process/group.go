@@ -0,0 +1,99 @@
+package process
+
+import (
+	"errors"
+	"os/exec"
+	"sync"
+)
+
+// ErrGroupClosed is returned when starting a command in a closed Group.
+var ErrGroupClosed = errors.New("oscompat/process: group closed")
+
+// Group contains child processes so that they can be killed together and
+// do not outlive the parent.
+//
+// Platform behavior:
+//   - Windows: children are assigned to a Job Object created with
+//     JOB_OBJECT_LIMIT_KILL_ON_JOB_CLOSE. The kernel kills the whole job,
+//     including grandchildren, when the last handle to it is closed, which
+//     also happens if the parent crashes.
+//   - Linux: each child leads its own process group and is started with
+//     PR_SET_PDEATHSIG set to SIGKILL, so it dies when the parent exits.
+//     Grandchildren are reached through the process group by Kill and
+//     Close but are not covered by the death signal.
+//   - Other Unix: each child leads its own process group. There is no death
+//     signal, so children are only killed by Kill or Close.
+//
+// A Group must be created with NewGroup and is safe for concurrent use.
+type Group struct {
+	mu     sync.Mutex
+	closed bool
+	g      group
+}
+
+// NewGroup creates an empty process group.
+func NewGroup() (*Group, error) {
+	g, err := newGroup()
+	if err != nil {
+		return nil, err
+	}
+	return &Group{g: g}, nil
+}
+
+// Start starts cmd as a member of the group. It sets the platform fields
+// of cmd.SysProcAttr it needs and leaves the others untouched. If the
+// process cannot be added to the group, it is killed and an error is
+// returned.
+func (g *Group) Start(cmd *exec.Cmd) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.closed {
+		return ErrGroupClosed
+	}
+	g.g.prepare(cmd)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	if err := g.g.add(cmd.Process.Pid); err != nil {
+		_ = cmd.Process.Kill()
+		return err
+	}
+	return nil
+}
+
+// Forget stops tracking the member started with pid once it has exited
+// and been reaped, so that Kill and Close do not signal an ID the system
+// may since have reused. On Unix the member's process group stays tracked
+// while other processes remain in it. Forget is a no-op on Windows, where
+// the job tracks membership itself.
+func (g *Group) Forget(pid int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.closed {
+		return
+	}
+	g.g.forget(pid)
+}
+
+// Kill forcibly kills every process in the group. The group remains
+// usable.
+func (g *Group) Kill() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.closed {
+		return nil
+	}
+	return g.g.kill()
+}
+
+// Close kills every process in the group and releases its resources.
+// Subsequent calls to Start return ErrGroupClosed.
+func (g *Group) Close() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.closed {
+		return nil
+	}
+	g.closed = true
+	return g.g.close()
+}
@@ -0,0 +1,109 @@
+package process_test
+
+import (
+	"os/exec"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/grokify/oscompat/process"
+)
+
+// sleepCommand returns a command that runs for about 30 seconds.
+func sleepCommand() *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("ping", "-n", "30", "127.0.0.1")
+	}
+	return exec.Command("sleep", "30")
+}
+
+// waitExit waits for cmd to exit, failing the test after a timeout.
+func waitExit(t *testing.T, cmd *exec.Cmd) {
+	t.Helper()
+	done := make(chan struct{})
+	go func() {
+		_ = cmd.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		_ = cmd.Process.Kill()
+		t.Error("process still running")
+	}
+}
+
+func TestGroupKill(t *testing.T) {
+	g, err := process.NewGroup()
+	if err != nil {
+		t.Fatalf("NewGroup() error: %v", err)
+	}
+	defer func() { _ = g.Close() }()
+
+	cmds := []*exec.Cmd{sleepCommand(), sleepCommand()}
+	for _, cmd := range cmds {
+		if err := g.Start(cmd); err != nil {
+			t.Fatalf("Start() error: %v", err)
+		}
+	}
+
+	if err := g.Kill(); err != nil {
+		t.Fatalf("Kill() error: %v", err)
+	}
+	for _, cmd := range cmds {
+		waitExit(t, cmd)
+	}
+}
+
+func TestGroupClose(t *testing.T) {
+	g, err := process.NewGroup()
+	if err != nil {
+		t.Fatalf("NewGroup() error: %v", err)
+	}
+
+	cmd := sleepCommand()
+	if err := g.Start(cmd); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	if err := g.Close(); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+	waitExit(t, cmd)
+
+	if err := g.Start(sleepCommand()); err != process.ErrGroupClosed {
+		t.Errorf("Start() after Close error = %v, want ErrGroupClosed", err)
+	}
+	if err := g.Close(); err != nil {
+		t.Errorf("second Close() error: %v", err)
+	}
+}
+
+func TestGroupForget(t *testing.T) {
+	g, err := process.NewGroup()
+	if err != nil {
+		t.Fatalf("NewGroup() error: %v", err)
+	}
+
+	done := exec.Command("true")
+	if runtime.GOOS == "windows" {
+		done = exec.Command("cmd", "/c", "exit")
+	}
+	if err := g.Start(done); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	if err := done.Wait(); err != nil {
+		t.Fatalf("Wait() error: %v", err)
+	}
+	g.Forget(done.Process.Pid)
+
+	// Forgetting a running member must not stop Close from killing it.
+	cmd := sleepCommand()
+	if err := g.Start(cmd); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	g.Forget(cmd.Process.Pid)
+	if err := g.Close(); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+	waitExit(t, cmd)
+}
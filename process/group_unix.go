@@ -0,0 +1,76 @@
+//go:build !windows
+
+package process
+
+import (
+	"os/exec"
+	"slices"
+	"syscall"
+)
+
+// group tracks the process groups led by each member.
+type group struct {
+	pgids []int
+}
+
+// newGroup returns an empty group.
+func newGroup() (group, error) {
+	return group{}, nil
+}
+
+// prepare puts the child in its own process group and, where supported,
+// arranges for it to be killed when the parent exits.
+func (g *group) prepare(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+	cmd.SysProcAttr.Pgid = 0
+	setDeathSignal(cmd.SysProcAttr)
+}
+
+// add records the process group led by pid, first dropping groups that
+// have emptied so that restarted members do not accumulate.
+func (g *group) add(pid int) error {
+	g.pgids = slices.DeleteFunc(g.pgids, emptyGroup)
+	g.pgids = append(g.pgids, pid)
+	return nil
+}
+
+// forget drops the process group led by pid if it has emptied.
+func (g *group) forget(pid int) {
+	if emptyGroup(pid) {
+		g.pgids = slices.DeleteFunc(g.pgids, func(pgid int) bool { return pgid == pid })
+	}
+}
+
+// emptyGroup reports whether no process is left in the process group.
+func emptyGroup(pgid int) bool {
+	return syscall.Kill(-pgid, 0) == syscall.ESRCH
+}
+
+// kill sends SIGKILL to every member's process group and forgets groups
+// that no longer exist.
+func (g *group) kill() error {
+	var firstErr error
+	live := g.pgids[:0]
+	for _, pgid := range g.pgids {
+		err := syscall.Kill(-pgid, syscall.SIGKILL)
+		if err == syscall.ESRCH {
+			continue
+		}
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		live = append(live, pgid)
+	}
+	g.pgids = live
+	return firstErr
+}
+
+// close kills the members.
+func (g *group) close() error {
+	err := g.kill()
+	g.pgids = nil
+	return err
+}
@@ -0,0 +1,131 @@
+//go:build windows
+
+package process
+
+import (
+	"fmt"
+	"os/exec"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	procCreateJobObjectW         = kernel32.NewProc("CreateJobObjectW")
+	procSetInformationJobObject  = kernel32.NewProc("SetInformationJobObject")
+	procAssignProcessToJobObject = kernel32.NewProc("AssignProcessToJobObject")
+	procTerminateJobObject       = kernel32.NewProc("TerminateJobObject")
+)
+
+const (
+	jobObjectExtendedLimitInformationClass = 9
+	jobObjectLimitKillOnJobClose           = 0x00002000
+	processSetQuota                        = 0x0100
+)
+
+// jobObjectBasicLimitInformation mirrors JOBOBJECT_BASIC_LIMIT_INFORMATION.
+type jobObjectBasicLimitInformation struct {
+	PerProcessUserTimeLimit int64
+	PerJobUserTimeLimit     int64
+	LimitFlags              uint32
+	MinimumWorkingSetSize   uintptr
+	MaximumWorkingSetSize   uintptr
+	ActiveProcessLimit      uint32
+	Affinity                uintptr
+	PriorityClass           uint32
+	SchedulingClass         uint32
+}
+
+// ioCounters mirrors IO_COUNTERS.
+type ioCounters struct {
+	ReadOperationCount  uint64
+	WriteOperationCount uint64
+	OtherOperationCount uint64
+	ReadTransferCount   uint64
+	WriteTransferCount  uint64
+	OtherTransferCount  uint64
+}
+
+// jobObjectExtendedLimitInformation mirrors JOBOBJECT_EXTENDED_LIMIT_INFORMATION.
+type jobObjectExtendedLimitInformation struct {
+	BasicLimitInformation jobObjectBasicLimitInformation
+	IoInfo                ioCounters
+	ProcessMemoryLimit    uintptr
+	JobMemoryLimit        uintptr
+	PeakProcessMemoryUsed uintptr
+	PeakJobMemoryUsed     uintptr
+}
+
+// group wraps a Job Object handle.
+type group struct {
+	job syscall.Handle
+}
+
+// newGroup creates an anonymous Job Object that kills its processes when
+// the last handle to it is closed.
+func newGroup() (group, error) {
+	var limits jobObjectExtendedLimitInformation
+	limits.BasicLimitInformation.LimitFlags = jobObjectLimitKillOnJobClose
+	job, err := createJob(&limits)
+	if err != nil {
+		return group{}, err
+	}
+	return group{job: job}, nil
+}
+
+// createJob creates an anonymous Job Object with the given limits.
+func createJob(limits *jobObjectExtendedLimitInformation) (syscall.Handle, error) {
+	r, _, err := procCreateJobObjectW.Call(0, 0)
+	if r == 0 {
+		return 0, fmt.Errorf("oscompat/process: failed to create job object: %w", err)
+	}
+	job := syscall.Handle(r)
+
+	r, _, err = procSetInformationJobObject.Call(uintptr(job), jobObjectExtendedLimitInformationClass,
+		uintptr(unsafe.Pointer(limits)), unsafe.Sizeof(*limits))
+	if r == 0 {
+		_ = syscall.CloseHandle(job)
+		return 0, fmt.Errorf("oscompat/process: failed to configure job object: %w", err)
+	}
+	return job, nil
+}
+
+// assignToJob adds the process behind h to job.
+func assignToJob(job, h syscall.Handle, pid int) error {
+	r, _, err := procAssignProcessToJobObject.Call(uintptr(job), uintptr(h))
+	if r == 0 {
+		return fmt.Errorf("oscompat/process: failed to assign process %d to job: %w", pid, err)
+	}
+	return nil
+}
+
+// prepare is a no-op; membership is established after the process starts.
+func (g *group) prepare(cmd *exec.Cmd) {}
+
+// add assigns the process to the job. Processes the child creates before
+// the assignment completes are not part of the job, but children created
+// afterwards inherit it.
+func (g *group) add(pid int) error {
+	h, err := syscall.OpenProcess(processSetQuota|processTerminate, false, uint32(pid))
+	if err != nil {
+		return fmt.Errorf("oscompat/process: failed to open process %d: %w", pid, err)
+	}
+	defer func() { _ = syscall.CloseHandle(h) }()
+	return assignToJob(g.job, h, pid)
+}
+
+// forget is a no-op; processes leave the job when they exit.
+func (g *group) forget(pid int) {}
+
+// kill terminates every process in the job.
+func (g *group) kill() error {
+	r, _, err := procTerminateJobObject.Call(uintptr(g.job), 1)
+	if r == 0 {
+		return fmt.Errorf("oscompat/process: failed to terminate job: %w", err)
+	}
+	return nil
+}
+
+// close closes the job handle, which kills its processes.
+func (g *group) close() error {
+	return syscall.CloseHandle(g.job)
+}
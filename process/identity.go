@@ -0,0 +1,54 @@
+package process
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/grokify/oscompat/id"
+)
+
+// Identity returns an opaque token naming the process with the given PID
+// for as long as it runs: the PID together with the process's start time
+// and the boot ID. Unlike a bare PID, a token saved in a PID file, port
+// file or supervisor state cannot be mistaken for an unrelated process
+// that later reuses the PID, even across reboots. Check a saved token
+// with
+//
+//	cur, err := process.Identity(pid)
+//	alive := err == nil && process.SameIdentity(saved, cur)
+//
+// The start time is the kernel's own record, read without rounding: clock
+// ticks since boot on Linux, microseconds on macOS, and the 100 ns
+// creation time on Windows. It returns ErrNotFound if the process does
+// not exist.
+func Identity(pid int) (string, error) {
+	if pid <= 0 {
+		return "", ErrNotFound
+	}
+	start, err := startStamp(pid)
+	if err != nil {
+		return "", err
+	}
+	boot, _ := id.BootID()
+	return strconv.Itoa(pid) + ":" + start + ":" + boot, nil
+}
+
+// SameIdentity reports whether two tokens from Identity name the same
+// process. The boot IDs are only compared when both tokens have one.
+func SameIdentity(a, b string) bool {
+	pa, sa, ba, okA := parseIdentity(a)
+	pb, sb, bb, okB := parseIdentity(b)
+	if !okA || !okB || pa != pb || sa != sb {
+		return false
+	}
+	return ba == "" || bb == "" || ba == bb
+}
+
+// parseIdentity splits a token into its PID, start time and boot ID.
+func parseIdentity(token string) (pid, start, boot string, ok bool) {
+	parts := strings.SplitN(token, ":", 3)
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" {
+		return "", "", "", false
+	}
+	return parts[0], parts[1], parts[2], true
+}
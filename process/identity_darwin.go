@@ -0,0 +1,16 @@
+//go:build darwin
+
+package process
+
+import "strconv"
+
+// startStamp returns the start time of pid from kinfo_proc in
+// microseconds.
+func startStamp(pid int) (string, error) {
+	kp, err := kinfo(pid)
+	if err != nil {
+		return "", err
+	}
+	t := kp.Proc.Starttime
+	return strconv.FormatInt(int64(t.Sec)*1e6+int64(t.Usec), 10), nil
+}
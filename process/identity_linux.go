@@ -0,0 +1,16 @@
+//go:build linux
+
+package process
+
+import "strconv"
+
+// startStamp returns the start time of pid in clock ticks since boot,
+// which unlike the wall-clock StartTime does not move when the clock is
+// adjusted.
+func startStamp(pid int) (string, error) {
+	st, err := readProcStat(pid)
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatUint(st.startTick, 10), nil
+}
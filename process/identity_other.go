@@ -0,0 +1,8 @@
+//go:build !windows && !linux && !darwin
+
+package process
+
+// startStamp is not implemented on this platform.
+func startStamp(pid int) (string, error) {
+	return "", ErrUnsupported
+}
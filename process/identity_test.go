@@ -0,0 +1,71 @@
+package process_test
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"testing"
+
+	"github.com/grokify/oscompat/process"
+)
+
+func TestIdentity(t *testing.T) {
+	self, err := process.Identity(os.Getpid())
+	if errors.Is(err, errors.ErrUnsupported) {
+		t.Skip("Identity not supported on this platform")
+	}
+	if err != nil {
+		t.Fatalf("Identity() error: %v", err)
+	}
+	again, err := process.Identity(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !process.SameIdentity(self, again) {
+		t.Errorf("SameIdentity(%q, %q) = false for the same process", self, again)
+	}
+
+	cmd := exec.Command("sh", "-c", "exit 0")
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/c", "exit 0")
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	child, err := process.Identity(cmd.Process.Pid)
+	if err != nil {
+		t.Fatalf("Identity(child) error: %v", err)
+	}
+	_ = cmd.Wait()
+	if process.SameIdentity(self, child) {
+		t.Errorf("SameIdentity(%q, %q) = true for different processes", self, child)
+	}
+	if _, err := process.Identity(cmd.Process.Pid); !errors.Is(err, process.ErrNotFound) {
+		t.Errorf("Identity() of exited child error = %v, want ErrNotFound", err)
+	}
+
+	if _, err := process.Identity(0); !errors.Is(err, process.ErrNotFound) {
+		t.Errorf("Identity(0) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestSameIdentity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"42:1000:boot-a", "42:1000:boot-a", true},
+		{"42:1000:boot-a", "42:1000:", true},
+		{"42:1000:boot-a", "42:1000:boot-b", false},
+		{"42:1000:boot-a", "42:1001:boot-a", false},
+		{"42:1000:boot-a", "43:1000:boot-a", false},
+		{"", "", false},
+		{"42", "42", false},
+	}
+	for _, tt := range tests {
+		if got := process.SameIdentity(tt.a, tt.b); got != tt.want {
+			t.Errorf("SameIdentity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+//go:build windows
+
+package process
+
+import (
+	"strconv"
+	"syscall"
+)
+
+// startStamp returns the creation time of pid as a FILETIME.
+func startStamp(pid int) (string, error) {
+	h, err := syscall.OpenProcess(processQueryLimitedInformation, false, uint32(pid))
+	if err != nil {
+		if err == errorInvalidParameter {
+			return "", ErrNotFound
+		}
+		return "", err
+	}
+	defer func() { _ = syscall.CloseHandle(h) }()
+
+	var creation, exit, kernel, user syscall.Filetime
+	if err := syscall.GetProcessTimes(h, &creation, &exit, &kernel, &user); err != nil {
+		return "", err
+	}
+	return strconv.FormatUint(uint64(creation.HighDateTime)<<32|uint64(creation.LowDateTime), 10), nil
+}
@@ -0,0 +1,54 @@
+package process
+
+import "time"
+
+// Process describes a running process.
+//
+// Fields that the caller is not permitted to read (for example, the
+// executable path of another user's process) are left empty rather than
+// causing an error.
+type Process struct {
+	// PID is the process ID.
+	PID int
+
+	// PPID is the parent process ID.
+	PPID int
+
+	// Name is the executable name without directory, such as "sshd" or
+	// "notepad.exe".
+	Name string
+
+	// Exe is the absolute path of the executable.
+	Exe string
+
+	// Args is the command line, including the program name as Args[0].
+	Args []string
+
+	// StartTime is when the process started.
+	StartTime time.Time
+
+	// User is the name of the user running the process. On Windows this
+	// is in DOMAIN\user form.
+	User string
+
+	// UID is the numeric user ID on Unix or the SID string on Windows.
+	UID string
+}
+
+// Info returns details about the process with the given PID.
+// It returns ErrNotFound if the process does not exist.
+//
+// Platform sources:
+//   - Linux: /proc/<pid>
+//   - macOS: sysctl KERN_PROC and KERN_PROCARGS2
+//   - Windows: Toolhelp32 snapshot, QueryFullProcessImageName,
+//     NtQueryInformationProcess, and the process token
+//
+// Use Info to verify that a PID recorded earlier (in a PID file, for
+// example) still belongs to the expected executable before signaling it.
+func Info(pid int) (*Process, error) {
+	if pid <= 0 {
+		return nil, ErrNotFound
+	}
+	return info(pid)
+}
@@ -0,0 +1,100 @@
+//go:build darwin
+
+package process
+
+import (
+	"bytes"
+	"encoding/binary"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// kinfo returns the kinfo_proc entry for pid.
+func kinfo(pid int) (*kinfoProc, error) {
+	buf, err := sysctl(ctlKern, kernProc, kernProcPID, int32(pid))
+	if err != nil {
+		return nil, err
+	}
+	procs := kinfoProcs(buf)
+	if len(procs) == 0 {
+		return nil, ErrNotFound
+	}
+	return &procs[0], nil
+}
+
+// procArgs reads the executable path, arguments, and environment of pid
+// from KERN_PROCARGS2. The layout is: int32 argc, the executable path,
+// NUL padding, argc argument strings, then environment strings, all
+// NUL-terminated.
+func procArgs(pid int) (exe string, args, env []string, err error) {
+	buf, err := sysctl(ctlKern, kernProcArgs2, int32(pid))
+	if err != nil {
+		return "", nil, nil, err
+	}
+	if len(buf) < 4 {
+		return "", nil, nil, ErrNotFound
+	}
+	argc := int(binary.LittleEndian.Uint32(buf))
+	rest := buf[4:]
+
+	i := bytes.IndexByte(rest, 0)
+	if i < 0 {
+		return "", nil, nil, ErrNotFound
+	}
+	exe = string(rest[:i])
+	rest = bytes.TrimLeft(rest[i:], "\x00")
+
+	for len(rest) > 0 {
+		i := bytes.IndexByte(rest, 0)
+		if i < 0 {
+			i = len(rest)
+		}
+		s := string(rest[:i])
+		if len(args) < argc {
+			args = append(args, s)
+		} else if s == "" {
+			break
+		} else {
+			env = append(env, s)
+		}
+		if i == len(rest) {
+			break
+		}
+		rest = rest[i+1:]
+	}
+	return exe, args, env, nil
+}
+
+// processFromKinfo converts a kinfo_proc entry to a Process.
+func processFromKinfo(kp *kinfoProc) *Process {
+	uid := kp.Eproc.Ucred.UID
+	return &Process{
+		PID:       int(kp.Proc.Pid),
+		PPID:      int(kp.Eproc.Ppid),
+		Name:      cString(kp.Proc.Comm[:]),
+		StartTime: time.Unix(kp.Proc.Starttime.Sec, int64(kp.Proc.Starttime.Usec)*1000),
+		UID:       strconv.FormatUint(uint64(uid), 10),
+		User:      lookupUser(uid),
+	}
+}
+
+// info reads process details with sysctl.
+func info(pid int) (*Process, error) {
+	kp, err := kinfo(pid)
+	if err != nil {
+		return nil, err
+	}
+	p := processFromKinfo(kp)
+
+	// KERN_PROCARGS2 fails for processes of other users; that is not an error.
+	if exe, args, _, err := procArgs(pid); err == nil {
+		p.Exe = exe
+		p.Args = args
+		if exe != "" {
+			// p_comm is truncated to 16 bytes; prefer the real executable name.
+			p.Name = filepath.Base(exe)
+		}
+	}
+	return p, nil
+}
@@ -0,0 +1,161 @@
+//go:build linux
+
+package process
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// clockTicks is USER_HZ, the unit of /proc/<pid>/stat times. It is 100 on
+// every mainstream Linux architecture.
+const clockTicks = 100
+
+// bootTime reads the system boot time from /proc/stat once.
+var bootTime = sync.OnceValue(func() time.Time {
+	data, err := os.ReadFile("/proc/stat")
+	if err != nil {
+		return time.Time{}
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if v, ok := strings.CutPrefix(line, "btime "); ok {
+			sec, _ := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+			return time.Unix(sec, 0)
+		}
+	}
+	return time.Time{}
+})
+
+// procPath returns a path under /proc/<pid>.
+func procPath(pid int, name string) string {
+	return filepath.Join("/proc", strconv.Itoa(pid), name)
+}
+
+// procStat holds the fields of /proc/<pid>/stat that this package uses.
+type procStat struct {
+	comm      string
+	state     byte
+	ppid      int
+	pgrp      int
+	startTick uint64
+	utime     uint64
+	stime     uint64
+	vsize     uint64
+	rssPages  int64
+}
+
+// readProcStat parses /proc/<pid>/stat.
+func readProcStat(pid int) (procStat, error) {
+	data, err := os.ReadFile(procPath(pid, "stat"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return procStat{}, ErrNotFound
+		}
+		return procStat{}, err
+	}
+
+	// The command name is wrapped in parentheses and may itself contain
+	// spaces or parentheses, so split around the last ')'.
+	open := bytes.IndexByte(data, '(')
+	end := bytes.LastIndexByte(data, ')')
+	if open < 0 || end < open {
+		return procStat{}, ErrNotFound
+	}
+	fields := strings.Fields(string(data[end+1:]))
+	if len(fields) < 22 {
+		return procStat{}, ErrNotFound
+	}
+
+	// fields[i] is field i+3 in proc(5) numbering.
+	st := procStat{comm: string(data[open+1 : end]), state: fields[0][0]}
+	st.ppid, _ = strconv.Atoi(fields[1])
+	st.pgrp, _ = strconv.Atoi(fields[2])
+	st.utime, _ = strconv.ParseUint(fields[11], 10, 64)
+	st.stime, _ = strconv.ParseUint(fields[12], 10, 64)
+	st.startTick, _ = strconv.ParseUint(fields[19], 10, 64)
+	st.vsize, _ = strconv.ParseUint(fields[20], 10, 64)
+	st.rssPages, _ = strconv.ParseInt(fields[21], 10, 64)
+	return st, nil
+}
+
+// startTime converts a start tick count to wall-clock time.
+func (st procStat) startTime() time.Time {
+	boot := bootTime()
+	if boot.IsZero() {
+		return time.Time{}
+	}
+	return boot.Add(time.Duration(st.startTick) * time.Second / clockTicks)
+}
+
+// readProcUID returns the real UID from /proc/<pid>/status.
+func readProcUID(pid int) (uint32, bool) {
+	f, err := os.Open(procPath(pid, "status"))
+	if err != nil {
+		return 0, false
+	}
+	defer func() { _ = f.Close() }()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if v, ok := strings.CutPrefix(scanner.Text(), "Uid:"); ok {
+			fields := strings.Fields(v)
+			if len(fields) == 0 {
+				return 0, false
+			}
+			uid, err := strconv.ParseUint(fields[0], 10, 32)
+			return uint32(uid), err == nil
+		}
+	}
+	return 0, false
+}
+
+// readProcArgs returns the NUL-separated contents of /proc/<pid>/<name>.
+func readProcArgs(pid int, name string) []string {
+	data, err := os.ReadFile(procPath(pid, name))
+	if err != nil {
+		return nil
+	}
+	return splitNul(data)
+}
+
+// splitNul splits a list of NUL-terminated strings.
+func splitNul(data []byte) []string {
+	if len(data) == 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimRight(string(data), "\x00"), "\x00")
+}
+
+// info reads process details from /proc.
+func info(pid int) (*Process, error) {
+	st, err := readProcStat(pid)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Process{
+		PID:       pid,
+		PPID:      st.ppid,
+		Name:      st.comm,
+		StartTime: st.startTime(),
+		Args:      readProcArgs(pid, "cmdline"),
+	}
+
+	if exe, err := os.Readlink(procPath(pid, "exe")); err == nil {
+		p.Exe = strings.TrimSuffix(exe, " (deleted)")
+		// comm is truncated to 15 bytes; prefer the real executable name.
+		p.Name = filepath.Base(p.Exe)
+	}
+
+	if uid, ok := readProcUID(pid); ok {
+		p.UID = strconv.FormatUint(uint64(uid), 10)
+		p.User = lookupUser(uid)
+	}
+	return p, nil
+}
@@ -0,0 +1,8 @@
+//go:build !windows && !linux && !darwin
+
+package process
+
+// info is not implemented on this platform.
+func info(pid int) (*Process, error) {
+	return nil, ErrUnsupported
+}
@@ -0,0 +1,66 @@
+package process_test
+
+import (
+	"errors"
+	"os"
+	"os/user"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/grokify/oscompat/process"
+)
+
+func TestInfoSelf(t *testing.T) {
+	p, err := process.Info(os.Getpid())
+	if errors.Is(err, errors.ErrUnsupported) {
+		t.Skip("Info not supported on this platform")
+	}
+	if err != nil {
+		t.Fatalf("Info() error: %v", err)
+	}
+
+	if p.PID != os.Getpid() {
+		t.Errorf("PID = %d, want %d", p.PID, os.Getpid())
+	}
+	if p.PPID != os.Getppid() {
+		t.Errorf("PPID = %d, want %d", p.PPID, os.Getppid())
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatalf("os.Executable() error: %v", err)
+	}
+	exe, _ = filepath.EvalSymlinks(exe)
+	got, _ := filepath.EvalSymlinks(p.Exe)
+	if got != exe {
+		t.Errorf("Exe = %q, want %q", p.Exe, exe)
+	}
+	if p.Name != filepath.Base(p.Exe) {
+		t.Errorf("Name = %q, want %q", p.Name, filepath.Base(p.Exe))
+	}
+
+	if len(p.Args) != len(os.Args) {
+		t.Errorf("Args = %q, want %q", p.Args, os.Args)
+	}
+
+	if p.StartTime.IsZero() || p.StartTime.After(time.Now()) || time.Since(p.StartTime) > time.Hour {
+		t.Errorf("StartTime = %v, want a recent time", p.StartTime)
+	}
+
+	if u, err := user.Current(); err == nil && p.UID != u.Uid {
+		t.Errorf("UID = %q, want %q", p.UID, u.Uid)
+	}
+}
+
+func TestInfoNotFound(t *testing.T) {
+	for _, pid := range []int{-1, 0, 999999999} {
+		_, err := process.Info(pid)
+		if errors.Is(err, errors.ErrUnsupported) {
+			t.Skip("Info not supported on this platform")
+		}
+		if err != process.ErrNotFound {
+			t.Errorf("Info(%d) = %v, want ErrNotFound", pid, err)
+		}
+	}
+}
@@ -0,0 +1,26 @@
+//go:build !windows
+
+package process
+
+import (
+	"os/user"
+	"strconv"
+	"sync"
+)
+
+// userCache maps UIDs to usernames so that List does not repeat lookups.
+var userCache sync.Map
+
+// lookupUser resolves a numeric UID to a username, returning "" if the
+// user cannot be found.
+func lookupUser(uid uint32) string {
+	if name, ok := userCache.Load(uid); ok {
+		return name.(string)
+	}
+	var name string
+	if u, err := user.LookupId(strconv.FormatUint(uint64(uid), 10)); err == nil {
+		name = u.Username
+	}
+	userCache.Store(uid, name)
+	return name
+}
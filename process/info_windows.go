@@ -0,0 +1,179 @@
+//go:build windows
+
+package process
+
+import (
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+var (
+	ntdll = syscall.NewLazyDLL("ntdll.dll")
+
+	procQueryFullProcessImageNameW = kernel32.NewProc("QueryFullProcessImageNameW")
+	procNtQueryInformationProcess  = ntdll.NewProc("NtQueryInformationProcess")
+)
+
+// processCommandLineInformation is the PROCESSINFOCLASS value for reading a
+// process's command line (Windows 8.1 and later).
+const processCommandLineInformation = 60
+
+// unicodeString mirrors UNICODE_STRING.
+type unicodeString struct {
+	Length        uint16
+	MaximumLength uint16
+	Buffer        *uint16
+}
+
+// snapshotProcesses returns a Toolhelp32 snapshot of all processes.
+func snapshotProcesses() ([]syscall.ProcessEntry32, error) {
+	snap, err := syscall.CreateToolhelp32Snapshot(syscall.TH32CS_SNAPPROCESS, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = syscall.CloseHandle(snap) }()
+
+	var entries []syscall.ProcessEntry32
+	entry := syscall.ProcessEntry32{Size: uint32(unsafe.Sizeof(syscall.ProcessEntry32{}))}
+	for err = syscall.Process32First(snap, &entry); err == nil; err = syscall.Process32Next(snap, &entry) {
+		entries = append(entries, entry)
+	}
+	if err != syscall.ERROR_NO_MORE_FILES {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// findProcessEntry returns the snapshot entry for pid.
+func findProcessEntry(pid int) (*syscall.ProcessEntry32, error) {
+	entries, err := snapshotProcesses()
+	if err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		if int(entries[i].ProcessID) == pid {
+			return &entries[i], nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// processFromEntry converts a snapshot entry to a Process, filling in the
+// fields that require opening the process when permitted.
+func processFromEntry(entry *syscall.ProcessEntry32) *Process {
+	p := &Process{
+		PID:  int(entry.ProcessID),
+		PPID: int(entry.ParentProcessID),
+		Name: syscall.UTF16ToString(entry.ExeFile[:]),
+	}
+
+	h, err := syscall.OpenProcess(processQueryLimitedInformation, false, entry.ProcessID)
+	if err != nil {
+		return p // protected or system process
+	}
+	defer func() { _ = syscall.CloseHandle(h) }()
+
+	p.Exe = imagePath(h)
+	p.StartTime = creationTime(h)
+	p.Args = commandLine(h)
+	p.User, p.UID = tokenUser(h)
+	return p
+}
+
+// info reads process details with Toolhelp32 and the process handle.
+func info(pid int) (*Process, error) {
+	entry, err := findProcessEntry(pid)
+	if err != nil {
+		return nil, err
+	}
+	return processFromEntry(entry), nil
+}
+
+// imagePath returns the full executable path of the process.
+func imagePath(h syscall.Handle) string {
+	buf := make([]uint16, syscall.MAX_LONG_PATH)
+	size := uint32(len(buf))
+	r, _, _ := procQueryFullProcessImageNameW.Call(uintptr(h), 0,
+		uintptr(unsafe.Pointer(&buf[0])), uintptr(unsafe.Pointer(&size)))
+	if r == 0 {
+		return ""
+	}
+	return syscall.UTF16ToString(buf[:size])
+}
+
+// creationTime returns when the process was created.
+func creationTime(h syscall.Handle) time.Time {
+	var creation, exit, kernel, user syscall.Filetime
+	if err := syscall.GetProcessTimes(h, &creation, &exit, &kernel, &user); err != nil {
+		return time.Time{}
+	}
+	return time.Unix(0, creation.Nanoseconds())
+}
+
+// commandLine reads the process command line and splits it into arguments.
+func commandLine(h syscall.Handle) []string {
+	var size uint32
+	_, _, _ = procNtQueryInformationProcess.Call(uintptr(h), processCommandLineInformation, 0, 0,
+		uintptr(unsafe.Pointer(&size)))
+	if size < uint32(unsafe.Sizeof(unicodeString{})) {
+		return nil
+	}
+
+	// Allocate as []uintptr so the UNICODE_STRING header is pointer-aligned.
+	buf := make([]uintptr, (size+uint32(unsafe.Sizeof(uintptr(0)))-1)/uint32(unsafe.Sizeof(uintptr(0))))
+	status, _, _ := procNtQueryInformationProcess.Call(uintptr(h), processCommandLineInformation,
+		uintptr(unsafe.Pointer(&buf[0])), uintptr(size), uintptr(unsafe.Pointer(&size)))
+	if status != 0 {
+		return nil
+	}
+	us := (*unicodeString)(unsafe.Pointer(&buf[0]))
+	if us.Buffer == nil || us.Length == 0 {
+		return nil
+	}
+	line := syscall.UTF16ToString(unsafe.Slice(us.Buffer, us.Length/2))
+	return splitWindowsCommandLine(line)
+}
+
+// splitWindowsCommandLine splits a command line with CommandLineToArgvW.
+func splitWindowsCommandLine(line string) []string {
+	ptr, err := syscall.UTF16PtrFromString(line)
+	if err != nil {
+		return nil
+	}
+	var argc int32
+	argv, err := syscall.CommandLineToArgv(ptr, &argc)
+	if err != nil {
+		return nil
+	}
+	defer func() { _, _ = syscall.LocalFree(syscall.Handle(uintptr(unsafe.Pointer(argv)))) }()
+
+	args := make([]string, argc)
+	for i := range args {
+		args[i] = syscall.UTF16ToString(argv[i][:])
+	}
+	return args
+}
+
+// tokenUser returns the account name and SID of the process owner.
+func tokenUser(h syscall.Handle) (name, sid string) {
+	var token syscall.Token
+	if err := syscall.OpenProcessToken(h, syscall.TOKEN_QUERY, &token); err != nil {
+		return "", ""
+	}
+	defer func() { _ = token.Close() }()
+
+	tu, err := token.GetTokenUser()
+	if err != nil {
+		return "", ""
+	}
+	sid, _ = tu.User.Sid.String()
+	account, domain, _, err := tu.User.Sid.LookupAccount("")
+	if err != nil {
+		return "", sid
+	}
+	if domain != "" {
+		return domain + `\` + account, sid
+	}
+	return account, sid
+}
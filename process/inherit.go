@@ -0,0 +1,24 @@
+package process
+
+// CloseOnExecAll keeps every file descriptor or handle the process holds,
+// other than standard input, output, and error, from being inherited by
+// child processes started afterwards, however they are started. Call it
+// early in main of a program whose children must not keep its sockets
+// and files open, for example a server that is restarted while a helper
+// it spawned still runs.
+//
+// Go already opens its own files and sockets this way, and os/exec on
+// Windows passes children an explicit PROC_THREAD_ATTRIBUTE_HANDLE_LIST.
+// What leaks are descriptors opened by C libraries, handles created
+// inheritable by DLLs, and descriptors the process itself inherited, such
+// as systemd socket-activation listeners.
+//
+// Platform behavior:
+//   - Unix: sets FD_CLOEXEC on each descriptor above 2, listed from
+//     /proc/self/fd where available and otherwise every descriptor below
+//     the RLIMIT_NOFILE soft limit.
+//   - Windows: finds the process's handles in the system handle table and
+//     clears HANDLE_FLAG_INHERIT on each inheritable one.
+func CloseOnExecAll() error {
+	return closeOnExecAll()
+}
@@ -0,0 +1,35 @@
+//go:build !windows
+
+package process
+
+import (
+	"os"
+	"strconv"
+	"syscall"
+)
+
+// maxScanFD bounds the descriptor scan when /proc/self/fd is missing and
+// RLIMIT_NOFILE is very large or unlimited.
+const maxScanFD = 1 << 16
+
+func closeOnExecAll() error {
+	if entries, err := os.ReadDir("/proc/self/fd"); err == nil {
+		for _, e := range entries {
+			if fd, err := strconv.Atoi(e.Name()); err == nil && fd > 2 {
+				syscall.CloseOnExec(fd)
+			}
+		}
+		return nil
+	}
+
+	var lim syscall.Rlimit
+	if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &lim); err != nil {
+		return err
+	}
+	limit := int(min(uint64(lim.Cur), maxScanFD))
+	for fd := 3; fd < limit; fd++ {
+		// Fails harmlessly with EBADF for descriptors that are not open.
+		syscall.CloseOnExec(fd)
+	}
+	return nil
+}
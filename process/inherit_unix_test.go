@@ -0,0 +1,33 @@
+//go:build !windows
+
+package process_test
+
+import (
+	"os/exec"
+	"strconv"
+	"syscall"
+	"testing"
+
+	"github.com/grokify/oscompat/process"
+)
+
+func TestCloseOnExecAll(t *testing.T) {
+	// syscall.Dup does not set FD_CLOEXEC, like descriptors from C code.
+	fd, err := syscall.Dup(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = syscall.Close(fd) }()
+	inherited := func() bool {
+		return exec.Command("sh", "-c", "true >&"+strconv.Itoa(fd)).Run() == nil
+	}
+	if !inherited() {
+		t.Skip("shell cannot see the duplicated descriptor")
+	}
+	if err := process.CloseOnExecAll(); err != nil {
+		t.Fatalf("CloseOnExecAll() error: %v", err)
+	}
+	if inherited() {
+		t.Errorf("descriptor %d still inherited", fd)
+	}
+}
@@ -0,0 +1,34 @@
+//go:build windows
+
+package process
+
+import (
+	"os"
+	"syscall"
+)
+
+// objInherit is the OBJ_INHERIT bit of a handle table entry's attributes.
+const objInherit = 0x2
+
+func closeOnExecAll() error {
+	table, err := systemHandleTable()
+	if err != nil {
+		return err
+	}
+	std := map[syscall.Handle]bool{}
+	for _, n := range []int{syscall.STD_INPUT_HANDLE, syscall.STD_OUTPUT_HANDLE, syscall.STD_ERROR_HANDLE} {
+		if h, err := syscall.GetStdHandle(n); err == nil {
+			std[h] = true
+		}
+	}
+	pid := uintptr(os.Getpid())
+	for _, e := range table {
+		h := syscall.Handle(e.HandleValue)
+		if e.UniqueProcessID != pid || e.HandleAttributes&objInherit == 0 || std[h] {
+			continue
+		}
+		// Handles closed since the table was read fail harmlessly.
+		_ = syscall.SetHandleInformation(h, syscall.HANDLE_FLAG_INHERIT, 0)
+	}
+	return nil
+}
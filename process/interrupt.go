@@ -0,0 +1,15 @@
+package process
+
+// Interrupt asks the process with the given PID to stop the way a user
+// would by pressing Ctrl+C in its terminal.
+//
+// Platform behavior:
+//   - Unix: sends SIGINT.
+//   - Windows: temporarily attaches to the target's console and generates a
+//     CTRL_C_EVENT for it. The calling process detaches from its own console
+//     while doing so and re-attaches to its parent's console afterwards, so
+//     console output from the caller may be lost during the call. Only
+//     console processes can be interrupted this way.
+func Interrupt(pid int) error {
+	return interrupt(pid)
+}
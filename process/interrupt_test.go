@@ -0,0 +1,51 @@
+package process_test
+
+import (
+	"errors"
+	"os/exec"
+	"runtime"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/grokify/oscompat/process"
+)
+
+func TestInterrupt(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("console attachment is not available in CI")
+	}
+
+	cmd := exec.Command("sleep", "30")
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+
+	if err := process.Interrupt(cmd.Process.Pid); err != nil {
+		t.Fatalf("Interrupt() error: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+
+	select {
+	case err := <-done:
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			t.Fatalf("Wait() = %v, want exit error", err)
+		}
+		status, ok := exitErr.Sys().(syscall.WaitStatus)
+		if ok && (!status.Signaled() || status.Signal() != syscall.SIGINT) {
+			t.Errorf("process exited with %v, want SIGINT", status)
+		}
+	case <-time.After(5 * time.Second):
+		_ = cmd.Process.Kill()
+		t.Fatal("process did not exit after Interrupt()")
+	}
+}
+
+func TestInterruptNonExistentProcess(t *testing.T) {
+	if err := process.Interrupt(999999999); err == nil {
+		t.Error("Interrupt on non-existent PID should return error")
+	}
+}
@@ -0,0 +1,62 @@
+//go:build windows
+
+package process
+
+import (
+	"fmt"
+	"sync"
+	"syscall"
+	"time"
+)
+
+var (
+	procAttachConsole         = kernel32.NewProc("AttachConsole")
+	procFreeConsole           = kernel32.NewProc("FreeConsole")
+	procSetConsoleCtrlHandler = kernel32.NewProc("SetConsoleCtrlHandler")
+)
+
+const (
+	ctrlCEvent          = 0
+	attachParentProcess = ^uintptr(0) // ATTACH_PARENT_PROCESS (DWORD -1)
+
+	// ctrlDeliveryDelay gives the console time to deliver the event before
+	// we stop ignoring CTRL+C ourselves.
+	ctrlDeliveryDelay = 100 * time.Millisecond
+)
+
+// consoleMu serializes changes to the process-wide console attachment.
+var consoleMu sync.Mutex
+
+// interrupt delivers CTRL_C_EVENT to the console process pid.
+//
+// CTRL_C_EVENT can only be sent to every process attached to the caller's
+// console, so we detach from our console, attach to the target's, ignore
+// CTRL+C ourselves, raise the event, and then restore.
+func interrupt(pid int) error {
+	h, err := syscall.OpenProcess(processQueryLimitedInformation, false, uint32(pid))
+	if err != nil {
+		return err
+	}
+	_ = syscall.CloseHandle(h)
+
+	consoleMu.Lock()
+	defer consoleMu.Unlock()
+
+	_, _, _ = procFreeConsole.Call()
+	defer func() { _, _, _ = procAttachConsole.Call(attachParentProcess) }()
+
+	if r, _, err := procAttachConsole.Call(uintptr(pid)); r == 0 {
+		return fmt.Errorf("oscompat/process: failed to attach to console of %d: %w", pid, err)
+	}
+
+	_, _, _ = procSetConsoleCtrlHandler.Call(0, 1)
+	r, _, err := procGenerateConsoleCtrlEvent.Call(ctrlCEvent, 0)
+	_, _, _ = procFreeConsole.Call()
+	time.Sleep(ctrlDeliveryDelay)
+	_, _, _ = procSetConsoleCtrlHandler.Call(0, 0)
+
+	if r == 0 {
+		return fmt.Errorf("oscompat/process: failed to send CTRL_C_EVENT to %d: %w", pid, err)
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package process
+
+import (
+	"os/exec"
+	"time"
+)
+
+// Limits are resource limits for a child process. Zero fields are not
+// limited.
+type Limits struct {
+	// MaxMemory is the maximum memory in bytes. On Unix this limits the
+	// address space (RLIMIT_AS), which macOS does not enforce; on Windows
+	// it limits committed memory.
+	MaxMemory int64
+
+	// MaxCPUTime is the maximum CPU time. On Unix the process receives
+	// SIGXCPU and then SIGKILL when it is exceeded (RLIMIT_CPU, rounded up
+	// to whole seconds); on Windows it is terminated after this much user
+	// time.
+	MaxCPUTime time.Duration
+
+	// MaxOpenFiles is the maximum number of open file descriptors
+	// (RLIMIT_NOFILE). Windows has no equivalent limit, so it is ignored
+	// there.
+	MaxOpenFiles int
+}
+
+// StartWithLimits starts cmd with resource limits applied before it runs
+// any of its own code, so that plugins and other untrusted helpers behave
+// the same way on every platform. The limits are inherited by processes
+// the child starts.
+//
+// The limits cannot be set on an exec.Cmd ahead of time because neither
+// platform offers a hook between creating the process and running it, so
+// this function starts cmd itself:
+//   - Unix: the command is run through /bin/sh, which applies the limits
+//     with ulimit (setrlimit) and then execs the original program, so the
+//     PID and the final program are unchanged.
+//   - Windows: the process is created suspended, assigned to a Job Object
+//     carrying the limits, and then resumed.
+func StartWithLimits(cmd *exec.Cmd, l Limits) error {
+	return startWithLimits(cmd, l)
+}
+
+// cpuSeconds rounds d up to whole seconds.
+func cpuSeconds(d time.Duration) int64 {
+	return int64((d + time.Second - 1) / time.Second)
+}
@@ -0,0 +1,51 @@
+package process_test
+
+import (
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grokify/oscompat/process"
+)
+
+func TestStartWithLimits(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+
+	cmd := exec.Command("sh", "-c", "ulimit -n; ulimit -t")
+	var out strings.Builder
+	cmd.Stdout = &out
+
+	err := process.StartWithLimits(cmd, process.Limits{
+		MaxOpenFiles: 64,
+		MaxCPUTime:   1500 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("StartWithLimits() error: %v", err)
+	}
+	if err := cmd.Wait(); err != nil {
+		t.Fatalf("Wait() error: %v", err)
+	}
+
+	got := strings.Fields(out.String())
+	if len(got) != 2 || got[0] != "64" || got[1] != "2" {
+		t.Errorf("child limits = %q, want [64 2]", got)
+	}
+}
+
+func TestStartWithLimitsNone(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires true")
+	}
+
+	cmd := exec.Command("true")
+	if err := process.StartWithLimits(cmd, process.Limits{}); err != nil {
+		t.Fatalf("StartWithLimits() error: %v", err)
+	}
+	if err := cmd.Wait(); err != nil {
+		t.Errorf("Wait() error: %v", err)
+	}
+}
@@ -0,0 +1,41 @@
+//go:build !windows
+
+package process
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// startWithLimits rewrites cmd to apply the limits with ulimit before
+// exec'ing the original program, then starts it.
+func startWithLimits(cmd *exec.Cmd, l Limits) error {
+	if cmd.Err != nil {
+		return cmd.Err
+	}
+
+	var script []string
+	if l.MaxMemory > 0 {
+		kib := (l.MaxMemory + 1023) / 1024
+		script = append(script, "ulimit -v "+strconv.FormatInt(kib, 10))
+	}
+	if l.MaxCPUTime > 0 {
+		script = append(script, "ulimit -t "+strconv.FormatInt(cpuSeconds(l.MaxCPUTime), 10))
+	}
+	if l.MaxOpenFiles > 0 {
+		script = append(script, "ulimit -n "+strconv.Itoa(l.MaxOpenFiles))
+	}
+	if len(script) == 0 {
+		return cmd.Start()
+	}
+	script = append(script, `exec "$0" "$@"`)
+
+	args := []string{"sh", "-c", strings.Join(script, " && "), cmd.Path}
+	if len(cmd.Args) > 1 {
+		args = append(args, cmd.Args[1:]...)
+	}
+	cmd.Path = "/bin/sh"
+	cmd.Args = args
+	return cmd.Start()
+}
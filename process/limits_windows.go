@@ -0,0 +1,72 @@
+//go:build windows
+
+package process
+
+import (
+	"fmt"
+	"os/exec"
+	"syscall"
+)
+
+var procNtResumeProcess = ntdll.NewProc("NtResumeProcess")
+
+const (
+	createSuspended             = 0x00000004
+	processSuspendResume        = 0x0800
+	jobObjectLimitProcessTime   = 0x00000002
+	jobObjectLimitProcessMemory = 0x00000100
+)
+
+// startWithLimits creates cmd suspended, places it in a Job Object with
+// the limits, and resumes it.
+func startWithLimits(cmd *exec.Cmd, l Limits) error {
+	var limits jobObjectExtendedLimitInformation
+	if l.MaxMemory > 0 {
+		limits.BasicLimitInformation.LimitFlags |= jobObjectLimitProcessMemory
+		limits.ProcessMemoryLimit = uintptr(l.MaxMemory)
+	}
+	if l.MaxCPUTime > 0 {
+		limits.BasicLimitInformation.LimitFlags |= jobObjectLimitProcessTime
+		limits.BasicLimitInformation.PerProcessUserTimeLimit = cpuSeconds(l.MaxCPUTime) * 10_000_000
+	}
+	if limits.BasicLimitInformation.LimitFlags == 0 {
+		return cmd.Start()
+	}
+
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.CreationFlags |= createSuspended
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	pid := cmd.Process.Pid
+
+	fail := func(err error) error {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return err
+	}
+
+	h, err := syscall.OpenProcess(processSetQuota|processTerminate|processSuspendResume, false, uint32(pid))
+	if err != nil {
+		return fail(fmt.Errorf("oscompat/process: failed to open process %d: %w", pid, err))
+	}
+	defer func() { _ = syscall.CloseHandle(h) }()
+
+	// The job lives as long as a process is assigned to it, so our handle
+	// can be closed once the child is in it.
+	job, err := createJob(&limits)
+	if err != nil {
+		return fail(err)
+	}
+	defer func() { _ = syscall.CloseHandle(job) }()
+
+	if err := assignToJob(job, h, pid); err != nil {
+		return fail(err)
+	}
+	if r, _, _ := procNtResumeProcess.Call(uintptr(h)); r != 0 {
+		return fail(fmt.Errorf("oscompat/process: failed to resume process %d: NTSTATUS 0x%x", pid, r))
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package process
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Filter selects processes in Find. Empty fields match any process.
+type Filter struct {
+	// Name matches Process.Name. On Windows, the comparison is
+	// case-insensitive and the ".exe" extension is optional.
+	Name string
+
+	// User matches Process.User or Process.UID. On Windows, the user may
+	// be given with or without the DOMAIN\ prefix and is compared
+	// case-insensitively.
+	User string
+}
+
+// List returns all processes visible to the caller, sorted by PID.
+//
+// Processes that exit while the list is being built are omitted. As with
+// Info, fields the caller is not permitted to read are left empty.
+func List() ([]*Process, error) {
+	return list()
+}
+
+// Find returns the processes matching f, sorted by PID.
+func Find(f Filter) ([]*Process, error) {
+	procs, err := List()
+	if err != nil {
+		return nil, err
+	}
+	var matched []*Process
+	for _, p := range procs {
+		if f.matches(p) {
+			matched = append(matched, p)
+		}
+	}
+	return matched, nil
+}
+
+// FindByName returns the processes whose executable name is name.
+// It is shorthand for Find(Filter{Name: name}).
+func FindByName(name string) ([]*Process, error) {
+	return Find(Filter{Name: name})
+}
+
+// matches reports whether p satisfies the filter.
+func (f Filter) matches(p *Process) bool {
+	if f.Name != "" && !nameMatches(p.Name, f.Name) {
+		return false
+	}
+	if f.User != "" && !userMatches(p, f.User) {
+		return false
+	}
+	return true
+}
+
+// nameMatches compares executable names using platform conventions.
+func nameMatches(name, want string) bool {
+	if runtime.GOOS != "windows" {
+		return name == want
+	}
+	if strings.EqualFold(name, want) {
+		return true
+	}
+	if ext := filepath.Ext(name); strings.EqualFold(ext, ".exe") {
+		return strings.EqualFold(strings.TrimSuffix(name, ext), want)
+	}
+	return false
+}
+
+// userMatches compares the process owner using platform conventions.
+func userMatches(p *Process, want string) bool {
+	if p.UID == want || p.User == want {
+		return true
+	}
+	if runtime.GOOS != "windows" {
+		return false
+	}
+	if strings.EqualFold(p.User, want) {
+		return true
+	}
+	_, account, ok := strings.Cut(p.User, `\`)
+	return ok && strings.EqualFold(account, want)
+}
@@ -0,0 +1,34 @@
+//go:build darwin
+
+package process
+
+import (
+	"path/filepath"
+	"sort"
+)
+
+// list reads the process table with sysctl KERN_PROC_ALL.
+func list() ([]*Process, error) {
+	buf, err := sysctl(ctlKern, kernProc, kernProcAll)
+	if err != nil {
+		return nil, err
+	}
+	kps := kinfoProcs(buf)
+	procs := make([]*Process, 0, len(kps))
+	for i := range kps {
+		if kps[i].Proc.Pid == 0 {
+			continue // kernel_task
+		}
+		p := processFromKinfo(&kps[i])
+		if exe, args, _, err := procArgs(p.PID); err == nil {
+			p.Exe = exe
+			p.Args = args
+			if exe != "" {
+				p.Name = filepath.Base(exe)
+			}
+		}
+		procs = append(procs, p)
+	}
+	sort.Slice(procs, func(i, j int) bool { return procs[i].PID < procs[j].PID })
+	return procs, nil
+}
@@ -0,0 +1,31 @@
+//go:build linux
+
+package process
+
+import (
+	"os"
+	"sort"
+	"strconv"
+)
+
+// list reads every numeric entry under /proc.
+func list() ([]*Process, error) {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil, err
+	}
+	var procs []*Process
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil || !entry.IsDir() {
+			continue
+		}
+		p, err := info(pid)
+		if err != nil {
+			continue // exited while listing
+		}
+		procs = append(procs, p)
+	}
+	sort.Slice(procs, func(i, j int) bool { return procs[i].PID < procs[j].PID })
+	return procs, nil
+}
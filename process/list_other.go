@@ -0,0 +1,8 @@
+//go:build !windows && !linux && !darwin
+
+package process
+
+// list is not implemented on this platform.
+func list() ([]*Process, error) {
+	return nil, ErrUnsupported
+}
@@ -0,0 +1,85 @@
+package process_test
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/grokify/oscompat/process"
+)
+
+func TestList(t *testing.T) {
+	procs, err := process.List()
+	if errors.Is(err, errors.ErrUnsupported) {
+		t.Skip("List not supported on this platform")
+	}
+	if err != nil {
+		t.Fatalf("List() error: %v", err)
+	}
+
+	found := false
+	for i, p := range procs {
+		if i > 0 && procs[i-1].PID >= p.PID {
+			t.Errorf("List() not sorted by PID at index %d", i)
+		}
+		if p.PID == os.Getpid() {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("List() did not include the current process")
+	}
+}
+
+func TestFindByName(t *testing.T) {
+	self, err := process.Info(os.Getpid())
+	if errors.Is(err, errors.ErrUnsupported) {
+		t.Skip("Info not supported on this platform")
+	}
+	if err != nil {
+		t.Fatalf("Info() error: %v", err)
+	}
+
+	procs, err := process.FindByName(self.Name)
+	if err != nil {
+		t.Fatalf("FindByName() error: %v", err)
+	}
+	found := false
+	for _, p := range procs {
+		if p.Name != self.Name {
+			t.Errorf("FindByName(%q) returned %q", self.Name, p.Name)
+		}
+		if p.PID == self.PID {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("FindByName(%q) did not include the current process", self.Name)
+	}
+}
+
+func TestFindByUser(t *testing.T) {
+	self, err := process.Info(os.Getpid())
+	if errors.Is(err, errors.ErrUnsupported) {
+		t.Skip("Info not supported on this platform")
+	}
+	if err != nil {
+		t.Fatalf("Info() error: %v", err)
+	}
+
+	procs, err := process.Find(process.Filter{Name: self.Name, User: self.UID})
+	if err != nil {
+		t.Fatalf("Find() error: %v", err)
+	}
+	if len(procs) == 0 {
+		t.Error("Find() by name and user returned no processes")
+	}
+
+	procs, err = process.Find(process.Filter{Name: self.Name, User: "no-such-user-oscompat"})
+	if err != nil {
+		t.Fatalf("Find() error: %v", err)
+	}
+	if len(procs) != 0 {
+		t.Errorf("Find() with unknown user returned %d processes", len(procs))
+	}
+}
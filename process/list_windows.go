@@ -0,0 +1,22 @@
+//go:build windows
+
+package process
+
+import "sort"
+
+// list walks a Toolhelp32 process snapshot.
+func list() ([]*Process, error) {
+	entries, err := snapshotProcesses()
+	if err != nil {
+		return nil, err
+	}
+	procs := make([]*Process, 0, len(entries))
+	for i := range entries {
+		if entries[i].ProcessID == 0 {
+			continue // System Idle Process
+		}
+		procs = append(procs, processFromEntry(&entries[i]))
+	}
+	sort.Slice(procs, func(i, j int) bool { return procs[i].PID < procs[j].PID })
+	return procs, nil
+}
@@ -0,0 +1,82 @@
+package process
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// LookPath searches for an executable named name in the directories named
+// by the PATH environment variable, like exec.LookPath, and returns the
+// first match. On failure it returns an *exec.Error wrapping
+// exec.ErrNotFound.
+//
+// It differs from exec.LookPath in two ways:
+//   - On Windows, extensions are resolved the way cmd.exe does it: a name
+//     whose extension is listed in PATHEXT is looked up as is, and any
+//     other name is tried with each PATHEXT extension in order. So
+//     "python" finds python.exe or python.cmd, but "script.py" is never
+//     matched unless .PY is in PATHEXT.
+//   - Relative PATH entries, such as "." or an empty entry, are skipped on
+//     every platform rather than reported as exec.ErrDot, so the current
+//     directory is never searched implicitly.
+//
+// Names containing a path separator are not searched for in PATH; they
+// are checked directly, with the same extension rules.
+func LookPath(name string) (string, error) {
+	all, err := lookPath(name, true)
+	if err != nil {
+		return "", err
+	}
+	return all[0], nil
+}
+
+// LookPathAll is like LookPath but returns every match in PATH order, for
+// example to detect several installed versions of the same tool.
+// Duplicates caused by repeated PATH entries are removed.
+func LookPathAll(name string) ([]string, error) {
+	return lookPath(name, false)
+}
+
+// lookPath collects matches for name, stopping at the first if first is
+// set.
+func lookPath(name string, first bool) ([]string, error) {
+	notFound := &exec.Error{Name: name, Err: exec.ErrNotFound}
+	if name == "" {
+		return nil, notFound
+	}
+
+	var dirs []string
+	if strings.ContainsAny(name, pathSeparators) {
+		dirs = []string{""}
+	} else {
+		for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+			dir = strings.Trim(dir, `"`)
+			if filepath.IsAbs(dir) {
+				dirs = append(dirs, dir)
+			}
+		}
+	}
+
+	var found []string
+	seen := make(map[string]bool)
+	for _, dir := range dirs {
+		for _, candidate := range candidates(name) {
+			path := filepath.Join(dir, candidate)
+			if seen[path] || !isExecutable(path) {
+				continue
+			}
+			seen[path] = true
+			found = append(found, path)
+			if first {
+				return found, nil
+			}
+			break
+		}
+	}
+	if len(found) == 0 {
+		return nil, notFound
+	}
+	return found, nil
+}
@@ -0,0 +1,106 @@
+package process_test
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/grokify/oscompat/process"
+)
+
+// writeExecutable creates an executable stub named name in dir and
+// returns its path.
+func writeExecutable(t *testing.T, dir, name string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// exeName returns the platform file name for a command stub.
+func exeName(name string) string {
+	if runtime.GOOS == "windows" {
+		return name + ".exe"
+	}
+	return name
+}
+
+func TestLookPathAll(t *testing.T) {
+	dir1, dir2 := t.TempDir(), t.TempDir()
+	want1 := writeExecutable(t, dir1, exeName("oscompat-tool"))
+	want2 := writeExecutable(t, dir2, exeName("oscompat-tool"))
+	t.Setenv("PATH", dir1+string(os.PathListSeparator)+dir2+string(os.PathListSeparator)+dir1)
+
+	got, err := process.LookPath("oscompat-tool")
+	if err != nil || got != want1 {
+		t.Errorf("LookPath() = %q, %v; want %q", got, err, want1)
+	}
+
+	all, err := process.LookPathAll("oscompat-tool")
+	if err != nil {
+		t.Fatalf("LookPathAll() error: %v", err)
+	}
+	if len(all) != 2 || all[0] != want1 || all[1] != want2 {
+		t.Errorf("LookPathAll() = %q, want [%q %q]", all, want1, want2)
+	}
+}
+
+func TestLookPathSkipsCurrentDir(t *testing.T) {
+	dir := t.TempDir()
+	writeExecutable(t, dir, exeName("oscompat-tool"))
+	t.Chdir(dir)
+	t.Setenv("PATH", "."+string(os.PathListSeparator))
+
+	_, err := process.LookPath("oscompat-tool")
+	if !errors.Is(err, exec.ErrNotFound) {
+		t.Errorf("LookPath() via relative PATH entry error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestLookPathExplicitPath(t *testing.T) {
+	dir := t.TempDir()
+	want := writeExecutable(t, dir, exeName("oscompat-tool"))
+	t.Setenv("PATH", "")
+
+	got, err := process.LookPath(filepath.Join(dir, "oscompat-tool"))
+	if err != nil || got != want {
+		t.Errorf("LookPath() = %q, %v; want %q", got, err, want)
+	}
+}
+
+func TestLookPathNotExecutable(t *testing.T) {
+	dir := t.TempDir()
+	name := "oscompat-data"
+	if runtime.GOOS == "windows" {
+		name += ".py"
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir)
+	t.Setenv("PATHEXT", ".COM;.EXE")
+
+	if _, err := process.LookPath(name); !errors.Is(err, exec.ErrNotFound) {
+		t.Errorf("LookPath() of non-executable error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestLookPathPathExt(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		t.Skip("PATHEXT is Windows-only")
+	}
+	dir := t.TempDir()
+	want := writeExecutable(t, dir, "oscompat-tool.cmd")
+	t.Setenv("PATH", dir)
+	t.Setenv("PATHEXT", ".EXE;.CMD")
+
+	got, err := process.LookPath("oscompat-tool")
+	if err != nil || !filepath.IsAbs(got) || filepath.Base(got) != filepath.Base(want) {
+		t.Errorf("LookPath() = %q, %v; want %q", got, err, want)
+	}
+}
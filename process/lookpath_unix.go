@@ -0,0 +1,20 @@
+//go:build !windows
+
+package process
+
+import "os"
+
+// pathSeparators are the characters that make a command name a path.
+const pathSeparators = "/"
+
+// candidates returns the file names tried for name; Unix has no implicit
+// extensions.
+func candidates(name string) []string {
+	return []string{name}
+}
+
+// isExecutable reports whether path is a regular file with an execute bit.
+func isExecutable(path string) bool {
+	fi, err := os.Stat(path)
+	return err == nil && fi.Mode().IsRegular() && fi.Mode().Perm()&0111 != 0
+}
@@ -0,0 +1,57 @@
+//go:build windows
+
+package process
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// pathSeparators are the characters that make a command name a path.
+const pathSeparators = `\/:`
+
+// defaultPathExt is used when PATHEXT is unset.
+const defaultPathExt = ".COM;.EXE;.BAT;.CMD"
+
+// pathExts returns the lowercased extensions listed in PATHEXT.
+func pathExts() []string {
+	env := os.Getenv("PATHEXT")
+	if env == "" {
+		env = defaultPathExt
+	}
+	var exts []string
+	for _, e := range strings.Split(strings.ToLower(env), ";") {
+		if e == "" {
+			continue
+		}
+		if e[0] != '.' {
+			e = "." + e
+		}
+		exts = append(exts, e)
+	}
+	return exts
+}
+
+// candidates returns the file names tried for name: name itself if its
+// extension is executable, otherwise name with each PATHEXT extension.
+func candidates(name string) []string {
+	exts := pathExts()
+	if slices.Contains(exts, strings.ToLower(filepath.Ext(name))) {
+		return []string{name}
+	}
+	names := make([]string, len(exts))
+	for i, e := range exts {
+		names[i] = name + e
+	}
+	return names
+}
+
+// isExecutable reports whether path is an existing file. Executability
+// on Windows is decided by the extension, which candidates already
+// checked.
+func isExecutable(path string) bool {
+	fi, err := os.Stat(path)
+	return err == nil && !fi.IsDir()
+}
@@ -0,0 +1,87 @@
+package process
+
+import (
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// OpenFiles returns the paths of the regular files and directories held
+// open by the process with the given PID, sorted and without duplicates.
+// Sockets, pipes, and other non-file descriptors are omitted. It returns
+// ErrNotFound if the process does not exist and a permission error if the
+// caller may not inspect it.
+//
+// Platform behavior:
+//   - Linux: reads the links in /proc/<pid>/fd.
+//   - macOS: uses proc_pidinfo(PROC_PIDLISTFDS) and
+//     proc_pidfdinfo(PROC_PIDFDVNODEPATHINFO), as lsof does.
+//   - Windows: enumerates the system handle table, duplicates the
+//     process's disk file handles, and resolves them with
+//     GetFinalPathNameByHandle. This requires PROCESS_DUP_HANDLE access.
+func OpenFiles(pid int) ([]string, error) {
+	if pid <= 0 {
+		return nil, ErrNotFound
+	}
+	files, err := openFiles(pid)
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(files)
+	return slices.Compact(files), nil
+}
+
+// FileHolders returns the processes that hold path open, such as a
+// program locking a file that an installer needs to replace. Processes the
+// caller may not inspect are skipped.
+func FileHolders(path string) ([]*Process, error) {
+	target, err := canonicalPath(path)
+	if err != nil {
+		return nil, err
+	}
+	procs, err := List()
+	if err != nil {
+		return nil, err
+	}
+	list, err := fileLister()
+	if err != nil {
+		return nil, err
+	}
+
+	var holders []*Process
+	for _, p := range procs {
+		files, err := list(p.PID)
+		if err != nil {
+			continue
+		}
+		for _, f := range files {
+			if samePath(f, target) {
+				holders = append(holders, p)
+				break
+			}
+		}
+	}
+	return holders, nil
+}
+
+// canonicalPath returns the absolute path of path with symbolic links
+// resolved, so it can be compared with the paths reported by the system.
+func canonicalPath(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	if resolved, err := filepath.EvalSymlinks(abs); err == nil {
+		return resolved, nil
+	}
+	return abs, nil
+}
+
+// samePath compares paths using the platform's case sensitivity.
+func samePath(a, b string) bool {
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		return strings.EqualFold(a, b)
+	}
+	return a == b
+}
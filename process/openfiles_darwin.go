@@ -0,0 +1,70 @@
+//go:build darwin
+
+package process
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+const (
+	procInfoCallPIDFDInfo   = 3
+	procPIDListFDs          = 1
+	procPIDFDVnodePathInfo  = 2
+	proxFDTypeVnode         = 1
+	vnodeFDInfoWithPathSize = 1200
+	vnodeFDInfoPathOffset   = 176
+)
+
+// procFDInfo mirrors struct proc_fdinfo.
+type procFDInfo struct {
+	FD     int32
+	FDType uint32
+}
+
+// procInfo invokes the proc_info system call, returning the number of
+// bytes written to buf.
+func procInfo(call, pid, flavor int, arg uint64, buf unsafe.Pointer, size int) (int, error) {
+	n, _, errno := syscall.Syscall6(syscall.SYS_PROC_INFO, uintptr(call), uintptr(pid),
+		uintptr(flavor), uintptr(arg), uintptr(buf), uintptr(size))
+	if errno == syscall.ESRCH {
+		return 0, ErrNotFound
+	}
+	if errno != 0 {
+		return 0, errno
+	}
+	return int(n), nil
+}
+
+// openFiles lists the vnode descriptors of pid and resolves their paths.
+func openFiles(pid int) ([]string, error) {
+	n, err := procInfo(procInfoCallPIDInfo, pid, procPIDListFDs, 0, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	// Leave room for descriptors opened since the size query.
+	fds := make([]procFDInfo, n/int(unsafe.Sizeof(procFDInfo{}))+32)
+	n, err = procInfo(procInfoCallPIDInfo, pid, procPIDListFDs, 0,
+		unsafe.Pointer(&fds[0]), len(fds)*int(unsafe.Sizeof(procFDInfo{})))
+	if err != nil {
+		return nil, err
+	}
+	fds = fds[:n/int(unsafe.Sizeof(procFDInfo{}))]
+
+	var files []string
+	var buf [vnodeFDInfoWithPathSize]byte
+	for _, fd := range fds {
+		if fd.FDType != proxFDTypeVnode {
+			continue
+		}
+		n, err := procInfo(procInfoCallPIDFDInfo, pid, procPIDFDVnodePathInfo, uint64(fd.FD),
+			unsafe.Pointer(&buf[0]), len(buf))
+		if err != nil || n < len(buf) {
+			continue // closed since listing
+		}
+		if path := cString(buf[vnodeFDInfoPathOffset:]); path != "" {
+			files = append(files, path)
+		}
+	}
+	return files, nil
+}
@@ -0,0 +1,33 @@
+//go:build linux
+
+package process
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// openFiles reads the descriptor links in /proc/<pid>/fd. Links to
+// non-file objects look like "socket:[1234]" and are skipped.
+func openFiles(pid int) ([]string, error) {
+	dir := procPath(pid, "fd")
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, entry := range entries {
+		target, err := os.Readlink(filepath.Join(dir, entry.Name()))
+		if err != nil || !strings.HasPrefix(target, "/") {
+			continue
+		}
+		files = append(files, strings.TrimSuffix(target, " (deleted)"))
+	}
+	return files, nil
+}
@@ -0,0 +1,8 @@
+//go:build !windows && !linux && !darwin
+
+package process
+
+// openFiles is not implemented on this platform.
+func openFiles(pid int) ([]string, error) {
+	return nil, ErrUnsupported
+}
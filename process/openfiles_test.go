@@ -0,0 +1,71 @@
+package process_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/grokify/oscompat/process"
+)
+
+// openTempFile creates and opens a file, returning its canonical path.
+func openTempFile(t *testing.T) string {
+	t.Helper()
+	dir, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatalf("EvalSymlinks() error: %v", err)
+	}
+	path := filepath.Join(dir, "held.txt")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Create() error: %v", err)
+	}
+	t.Cleanup(func() { _ = f.Close() })
+	return path
+}
+
+func TestOpenFiles(t *testing.T) {
+	path := openTempFile(t)
+
+	files, err := process.OpenFiles(os.Getpid())
+	if errors.Is(err, errors.ErrUnsupported) {
+		t.Skip("OpenFiles not supported on this platform")
+	}
+	if err != nil {
+		t.Fatalf("OpenFiles() error: %v", err)
+	}
+	if !slices.ContainsFunc(files, func(f string) bool { return strings.EqualFold(f, path) }) {
+		t.Errorf("OpenFiles() = %q, missing %q", files, path)
+	}
+	if !slices.IsSorted(files) {
+		t.Error("OpenFiles() result not sorted")
+	}
+}
+
+func TestFileHolders(t *testing.T) {
+	path := openTempFile(t)
+
+	holders, err := process.FileHolders(path)
+	if errors.Is(err, errors.ErrUnsupported) {
+		t.Skip("FileHolders not supported on this platform")
+	}
+	if err != nil {
+		t.Fatalf("FileHolders() error: %v", err)
+	}
+	if !slices.ContainsFunc(holders, func(p *process.Process) bool { return p.PID == os.Getpid() }) {
+		t.Errorf("FileHolders() did not include the current process")
+	}
+}
+
+func TestOpenFilesNotFound(t *testing.T) {
+	_, err := process.OpenFiles(999999999)
+	if errors.Is(err, errors.ErrUnsupported) {
+		t.Skip("OpenFiles not supported on this platform")
+	}
+	if err != process.ErrNotFound {
+		t.Errorf("OpenFiles() error = %v, want ErrNotFound", err)
+	}
+}
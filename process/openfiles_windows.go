@@ -0,0 +1,140 @@
+//go:build windows
+
+package process
+
+import (
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	procNtQuerySystemInformation  = ntdll.NewProc("NtQuerySystemInformation")
+	procGetFinalPathNameByHandleW = kernel32.NewProc("GetFinalPathNameByHandleW")
+)
+
+const (
+	systemExtendedHandleInformation = 64
+	statusInfoLengthMismatch        = 0xC0000004
+	processDupHandle                = 0x0040
+	fileTypeDisk                    = 0x0001
+	maxHandleTableSize              = 256 << 20
+)
+
+// systemHandleEntry mirrors SYSTEM_HANDLE_TABLE_ENTRY_INFO_EX.
+type systemHandleEntry struct {
+	Object                uintptr
+	UniqueProcessID       uintptr
+	HandleValue           uintptr
+	GrantedAccess         uint32
+	CreatorBackTraceIndex uint16
+	ObjectTypeIndex       uint16
+	HandleAttributes      uint32
+	Reserved              uint32
+}
+
+// systemHandleTable reads the system-wide handle table.
+func systemHandleTable() ([]systemHandleEntry, error) {
+	size := uint32(1 << 20)
+	for {
+		// Allocate as []uintptr so the table is pointer-aligned.
+		buf := make([]uintptr, size/uint32(unsafe.Sizeof(uintptr(0))))
+		var needed uint32
+		status, _, _ := procNtQuerySystemInformation.Call(systemExtendedHandleInformation,
+			uintptr(unsafe.Pointer(&buf[0])), uintptr(size), uintptr(unsafe.Pointer(&needed)))
+		if status == statusInfoLengthMismatch && size < maxHandleTableSize {
+			size = max(size*2, needed+needed/8)
+			continue
+		}
+		if status != 0 {
+			return nil, ErrUnsupported
+		}
+
+		// SYSTEM_HANDLE_INFORMATION_EX: count, reserved, then entries.
+		count := buf[0]
+		first := unsafe.Pointer(&buf[2])
+		entries := unsafe.Slice((*systemHandleEntry)(first), count)
+		return append([]systemHandleEntry(nil), entries...), nil
+	}
+}
+
+// openFiles resolves the disk files held open by pid.
+func openFiles(pid int) ([]string, error) {
+	table, err := systemHandleTable()
+	if err != nil {
+		return nil, err
+	}
+	var entries []systemHandleEntry
+	for _, e := range table {
+		if e.UniqueProcessID == uintptr(pid) {
+			entries = append(entries, e)
+		}
+	}
+	return handleFiles(pid, entries)
+}
+
+// fileLister reads the handle table once and returns a function that
+// resolves the disk files of any process in it, so FileHolders does not
+// read the whole table again for every process.
+func fileLister() (func(pid int) ([]string, error), error) {
+	table, err := systemHandleTable()
+	if err != nil {
+		return nil, err
+	}
+	byPID := map[uintptr][]systemHandleEntry{}
+	for _, e := range table {
+		byPID[e.UniqueProcessID] = append(byPID[e.UniqueProcessID], e)
+	}
+	return func(pid int) ([]string, error) {
+		entries := byPID[uintptr(pid)]
+		if len(entries) == 0 {
+			return nil, nil
+		}
+		return handleFiles(pid, entries)
+	}, nil
+}
+
+// handleFiles duplicates each disk file handle in entries, which belong
+// to pid, and resolves its path. Only disk files are resolved because
+// querying a synchronous pipe handle can block indefinitely.
+func handleFiles(pid int, entries []systemHandleEntry) ([]string, error) {
+	h, err := syscall.OpenProcess(processDupHandle, false, uint32(pid))
+	if err != nil {
+		if err == errorInvalidParameter {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	defer func() { _ = syscall.CloseHandle(h) }()
+	self, _ := syscall.GetCurrentProcess()
+
+	var files []string
+	for _, e := range entries {
+		var dup syscall.Handle
+		err := syscall.DuplicateHandle(h, syscall.Handle(e.HandleValue), self, &dup, 0, false, syscall.DUPLICATE_SAME_ACCESS)
+		if err != nil {
+			continue
+		}
+		if t, _ := syscall.GetFileType(dup); t == fileTypeDisk {
+			if path := finalPath(dup); path != "" {
+				files = append(files, path)
+			}
+		}
+		_ = syscall.CloseHandle(dup)
+	}
+	return files, nil
+}
+
+// finalPath returns the DOS path of an open file handle.
+func finalPath(h syscall.Handle) string {
+	buf := make([]uint16, syscall.MAX_LONG_PATH)
+	n, _, _ := procGetFinalPathNameByHandleW.Call(uintptr(h), uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf)), 0)
+	if n == 0 || n > uintptr(len(buf)) {
+		return ""
+	}
+	path := syscall.UTF16ToString(buf[:n])
+	if rest, ok := strings.CutPrefix(path, `\\?\UNC\`); ok {
+		return `\\` + rest
+	}
+	return strings.TrimPrefix(path, `\\?\`)
+}
@@ -4,15 +4,29 @@
 //   - Signal handling (Windows lacks SIGTERM, uses Kill instead)
 //   - Process group management (Unix has Setpgid, Windows does not)
 //   - Daemon/service detachment patterns
+//   - Process inspection (/proc on Linux, sysctl on macOS, Win32 on Windows)
 //
-// Platform-specific implementations are in process_unix.go and process_windows.go.
+// Platform-specific implementations are in files with _unix, _linux,
+// _darwin, and _windows suffixes.
 package process
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"os/exec"
 )
 
+// Common errors.
+var (
+	// ErrNotFound is returned when no process exists with the given PID.
+	ErrNotFound = errors.New("oscompat/process: process not found")
+
+	// ErrUnsupported is returned when an operation is not available on the
+	// current platform. It matches errors.ErrUnsupported.
+	ErrUnsupported = fmt.Errorf("oscompat/process: %w", errors.ErrUnsupported)
+)
+
 // SetDetached configures a command to run detached from the parent process.
 // On Unix, this sets up a new process group. On Windows, this is a no-op
 // as basic detachment works differently.
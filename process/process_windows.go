@@ -5,6 +5,12 @@ package process
 import (
 	"os"
 	"os/exec"
+	"syscall"
+)
+
+var (
+	kernel32 = syscall.NewLazyDLL("kernel32.dll")
+	user32   = syscall.NewLazyDLL("user32.dll")
 )
 
 // setSysProcAttr sets Windows-specific process attributes for daemon detachment.
@@ -0,0 +1,109 @@
+// Package pty starts commands attached to a pseudo-terminal, so that
+// tools wrapping interactive programs, such as SSH-like remote shells
+// and REPL runners, see the same terminal behavior on every platform:
+// line editing, colors, and a window size the program can query.
+//
+// Each platform uses its own mechanism:
+//   - Linux, macOS, and FreeBSD: a Unix pseudo-terminal from /dev/ptmx
+//     (posix_openpt on FreeBSD). The command runs in a new session with
+//     the terminal as its controlling terminal.
+//   - Windows: a pseudo console (ConPTY), available since Windows 10
+//     version 1809.
+//   - Elsewhere: ErrUnsupported.
+//
+// Read returns what the command writes to the terminal, and Write sends
+// it keystrokes. In both cases the data is a stream of bytes with VT
+// escape sequences; ConPTY also translates the console API calls of
+// Windows programs into such sequences.
+package pty
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sync"
+)
+
+// ErrUnsupported is returned by Start on platforms without
+// pseudo-terminals, including Windows releases before ConPTY. It matches
+// errors.ErrUnsupported.
+var ErrUnsupported = fmt.Errorf("oscompat/process/pty: %w", errors.ErrUnsupported)
+
+// Size is a terminal window size in character cells.
+type Size struct {
+	Rows, Cols uint16
+}
+
+// DefaultSize is used by Start for a zero Size.
+var DefaultSize = Size{Rows: 24, Cols: 80}
+
+// PTY is the controlling side of the pseudo-terminal of a command.
+type PTY struct {
+	cmd *exec.Cmd
+	in  *os.File // writes reach the command as keyboard input
+	out *os.File // the command's terminal output
+	sys sysPTY
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// Start starts cmd with a new pseudo-terminal of the given size; a zero
+// size is DefaultSize. On Unix the terminal becomes the standard input,
+// output, and error that cmd does not already set. On Windows the
+// command always writes to the pseudo console and cmd's streams are not
+// used. Use the returned PTY's Wait instead of cmd.Wait, which on Windows
+// reports that the command was not started.
+//
+// The command's output must be read, typically by a goroutine copying
+// Read to the user's terminal, or it blocks once the terminal's buffer
+// fills.
+func Start(cmd *exec.Cmd, size Size) (*PTY, error) {
+	if cmd.Process != nil {
+		return nil, errors.New("oscompat/process/pty: command already started")
+	}
+	if cmd.Err != nil {
+		return nil, cmd.Err
+	}
+	if size.Rows == 0 || size.Cols == 0 {
+		size = DefaultSize
+	}
+	return start(cmd, size)
+}
+
+// Read reads output of the command. It returns io.EOF once the command
+// has exited and its output has been read.
+func (p *PTY) Read(b []byte) (int, error) {
+	return p.read(b)
+}
+
+// Write sends b to the command as if typed on its terminal.
+func (p *PTY) Write(b []byte) (int, error) {
+	return p.in.Write(b)
+}
+
+// Resize changes the window size of the terminal; the command receives
+// SIGWINCH on Unix or a buffer size event on Windows.
+func (p *PTY) Resize(size Size) error {
+	if size.Rows == 0 || size.Cols == 0 {
+		return fmt.Errorf("oscompat/process/pty: invalid size %dx%d", size.Cols, size.Rows)
+	}
+	return p.resize(size)
+}
+
+// Wait waits for the command to exit, like exec.Cmd.Wait, and sets
+// cmd.ProcessState. Output still buffered in the terminal can be read
+// after it returns.
+func (p *PTY) Wait() error {
+	return p.wait()
+}
+
+// Close closes the terminal. A command still running loses its terminal
+// and typically exits, with SIGHUP on Unix.
+func (p *PTY) Close() error {
+	p.closeOnce.Do(func() {
+		p.closeErr = p.close()
+	})
+	return p.closeErr
+}
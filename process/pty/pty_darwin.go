@@ -0,0 +1,39 @@
+//go:build darwin
+
+package pty
+
+import (
+	"bytes"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// openPTY opens /dev/ptmx, grants and unlocks the terminal side, and
+// returns its path, as posix_openpt, grantpt, unlockpt, and ptsname do.
+func openPTY() (*os.File, string, error) {
+	ptmx, err := os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		return nil, "", err
+	}
+	var name [128]byte
+	for _, c := range []struct {
+		op  string
+		req uintptr
+		arg uintptr
+	}{
+		{"TIOCPTYGRANT", syscall.TIOCPTYGRANT, 0},
+		{"TIOCPTYUNLK", syscall.TIOCPTYUNLK, 0},
+		{"TIOCPTYGNAME", syscall.TIOCPTYGNAME, uintptr(unsafe.Pointer(&name))},
+	} {
+		if err := ioctl(ptmx, c.req, c.arg); err != nil {
+			_ = ptmx.Close()
+			return nil, "", &os.PathError{Op: c.op, Path: ptmx.Name(), Err: err}
+		}
+	}
+	n := bytes.IndexByte(name[:], 0)
+	if n < 0 {
+		n = len(name)
+	}
+	return ptmx, string(name[:n]), nil
+}
@@ -0,0 +1,40 @@
+//go:build freebsd
+
+package pty
+
+import (
+	"bytes"
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// fiodgnameArg mirrors struct fiodgname_arg.
+type fiodgnameArg struct {
+	Len int32
+	Buf *byte
+}
+
+// fiodgname is _IOW('f', 120, struct fiodgname_arg).
+const fiodgname = 0x80000000 | uintptr(unsafe.Sizeof(fiodgnameArg{}))<<16 | 'f'<<8 | 120
+
+// openPTY opens a terminal pair with posix_openpt and returns the path
+// of the terminal side, which needs no grantpt or unlockpt on FreeBSD.
+func openPTY() (*os.File, string, error) {
+	fd, _, errno := syscall.Syscall(syscall.SYS_POSIX_OPENPT, uintptr(syscall.O_RDWR|syscall.O_NOCTTY|syscall.O_CLOEXEC), 0, 0)
+	if errno != 0 {
+		return nil, "", os.NewSyscallError("posix_openpt", errno)
+	}
+	ptmx := os.NewFile(fd, "/dev/ptmx")
+	var name [128]byte
+	arg := fiodgnameArg{Len: int32(len(name)), Buf: &name[0]}
+	if err := ioctl(ptmx, fiodgname, uintptr(unsafe.Pointer(&arg))); err != nil {
+		_ = ptmx.Close()
+		return nil, "", &os.PathError{Op: "FIODGNAME", Path: ptmx.Name(), Err: err}
+	}
+	n := bytes.IndexByte(name[:], 0)
+	if n < 0 {
+		n = len(name)
+	}
+	return ptmx, "/dev/" + string(name[:n]), nil
+}
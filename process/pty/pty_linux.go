@@ -0,0 +1,30 @@
+//go:build linux
+
+package pty
+
+import (
+	"os"
+	"strconv"
+	"syscall"
+	"unsafe"
+)
+
+// openPTY opens /dev/ptmx, unlocks the terminal side, and returns its
+// path, as posix_openpt, unlockpt, and ptsname do.
+func openPTY() (*os.File, string, error) {
+	ptmx, err := os.OpenFile("/dev/ptmx", os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		return nil, "", err
+	}
+	var unlock int32
+	if err := ioctl(ptmx, syscall.TIOCSPTLCK, uintptr(unsafe.Pointer(&unlock))); err != nil {
+		_ = ptmx.Close()
+		return nil, "", &os.PathError{Op: "TIOCSPTLCK", Path: ptmx.Name(), Err: err}
+	}
+	var n uint32
+	if err := ioctl(ptmx, syscall.TIOCGPTN, uintptr(unsafe.Pointer(&n))); err != nil {
+		_ = ptmx.Close()
+		return nil, "", &os.PathError{Op: "TIOCGPTN", Path: ptmx.Name(), Err: err}
+	}
+	return ptmx, "/dev/pts/" + strconv.FormatUint(uint64(n), 10), nil
+}
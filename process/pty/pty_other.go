@@ -0,0 +1,29 @@
+//go:build !linux && !darwin && !freebsd && !windows
+
+package pty
+
+import "os/exec"
+
+// sysPTY is empty; Start fails on this platform.
+type sysPTY struct{}
+
+// start is not implemented on this platform.
+func start(cmd *exec.Cmd, size Size) (*PTY, error) {
+	return nil, ErrUnsupported
+}
+
+func (p *PTY) read(b []byte) (int, error) {
+	return 0, ErrUnsupported
+}
+
+func (p *PTY) resize(size Size) error {
+	return ErrUnsupported
+}
+
+func (p *PTY) wait() error {
+	return ErrUnsupported
+}
+
+func (p *PTY) close() error {
+	return nil
+}
@@ -0,0 +1,75 @@
+package pty_test
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grokify/oscompat/process/pty"
+)
+
+func TestStart(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs a Unix shell")
+	}
+	cmd := exec.Command("sh", "-c", `stty size; read x; echo "got $x"; stty size`)
+	p, err := pty.Start(cmd, pty.Size{Rows: 30, Cols: 100})
+	if errors.Is(err, pty.ErrUnsupported) {
+		t.Skip(err)
+	} else if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = p.Close() }()
+
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		sc := bufio.NewScanner(p)
+		for sc.Scan() {
+			lines <- strings.TrimRight(sc.Text(), "\r")
+		}
+	}()
+	next := func() string {
+		t.Helper()
+		for {
+			select {
+			case line, ok := <-lines:
+				if !ok {
+					t.Fatal("output ended early")
+				}
+				// Skip the echo of the input line.
+				if line != "abc" {
+					return line
+				}
+			case <-time.After(10 * time.Second):
+				t.Fatal("timed out reading output")
+			}
+		}
+	}
+
+	if got := next(); got != "30 100" {
+		t.Errorf("initial size = %q, want %q", got, "30 100")
+	}
+	if err := p.Resize(pty.Size{Rows: 40, Cols: 120}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.WriteString(p, "abc\n"); err != nil {
+		t.Fatal(err)
+	}
+	if got := next(); got != "got abc" {
+		t.Errorf("output = %q, want %q", got, "got abc")
+	}
+	if got := next(); got != "40 120" {
+		t.Errorf("size after Resize = %q, want %q", got, "40 120")
+	}
+	if err := p.Wait(); err != nil {
+		t.Errorf("Wait() = %v", err)
+	}
+	for range lines {
+	}
+}
@@ -0,0 +1,111 @@
+//go:build linux || darwin || freebsd
+
+package pty
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"syscall"
+	"unsafe"
+)
+
+// sysPTY has no state beyond the file of the controlling side.
+type sysPTY struct{}
+
+// winsize mirrors struct winsize.
+type winsize struct {
+	Rows, Cols, Xpixel, Ypixel uint16
+}
+
+// start opens a terminal pair and starts cmd on the terminal side in a
+// new session.
+func start(cmd *exec.Cmd, size Size) (*PTY, error) {
+	ptmx, name, err := openPTY()
+	if err != nil {
+		return nil, err
+	}
+	p := &PTY{cmd: cmd, in: ptmx, out: ptmx}
+	if err := p.resize(size); err != nil {
+		_ = ptmx.Close()
+		return nil, err
+	}
+	tty, err := os.OpenFile(name, os.O_RDWR|syscall.O_NOCTTY, 0)
+	if err != nil {
+		_ = ptmx.Close()
+		return nil, err
+	}
+	defer func() { _ = tty.Close() }()
+
+	if cmd.Stdin == nil {
+		cmd.Stdin = tty
+	}
+	if cmd.Stdout == nil {
+		cmd.Stdout = tty
+	}
+	if cmd.Stderr == nil {
+		cmd.Stderr = tty
+	}
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setsid = true
+	if cmd.Stdin == tty {
+		// Ctty is a descriptor number in the child: its standard input.
+		cmd.SysProcAttr.Setctty = true
+		cmd.SysProcAttr.Ctty = 0
+	}
+	if err := cmd.Start(); err != nil {
+		_ = ptmx.Close()
+		return nil, err
+	}
+	return p, nil
+}
+
+// read maps EIO, which Linux returns once every holder of the terminal
+// side has closed it, to io.EOF.
+func (p *PTY) read(b []byte) (int, error) {
+	n, err := p.out.Read(b)
+	if errors.Is(err, syscall.EIO) {
+		err = io.EOF
+	}
+	return n, err
+}
+
+// resize sets the window size with TIOCSWINSZ.
+func (p *PTY) resize(size Size) error {
+	ws := winsize{Rows: size.Rows, Cols: size.Cols}
+	if err := ioctl(p.out, syscall.TIOCSWINSZ, uintptr(unsafe.Pointer(&ws))); err != nil {
+		return &os.PathError{Op: "TIOCSWINSZ", Path: p.out.Name(), Err: err}
+	}
+	return nil
+}
+
+// wait waits with cmd.Wait.
+func (p *PTY) wait() error {
+	return p.cmd.Wait()
+}
+
+// close closes the controlling side; the command gets SIGHUP.
+func (p *PTY) close() error {
+	return p.out.Close()
+}
+
+// ioctl calls ioctl(2) on f.
+func ioctl(f *os.File, req, arg uintptr) error {
+	conn, err := f.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var errno syscall.Errno
+	if err := conn.Control(func(fd uintptr) {
+		_, _, errno = syscall.Syscall(syscall.SYS_IOCTL, fd, req, arg)
+	}); err != nil {
+		return err
+	}
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
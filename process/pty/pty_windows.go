@@ -0,0 +1,229 @@
+//go:build windows
+
+package pty
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"syscall"
+	"unicode/utf16"
+	"unsafe"
+)
+
+var (
+	kernel32                              = syscall.NewLazyDLL("kernel32.dll")
+	procCreatePseudoConsole               = kernel32.NewProc("CreatePseudoConsole")
+	procResizePseudoConsole               = kernel32.NewProc("ResizePseudoConsole")
+	procClosePseudoConsole                = kernel32.NewProc("ClosePseudoConsole")
+	procInitializeProcThreadAttributeList = kernel32.NewProc("InitializeProcThreadAttributeList")
+	procUpdateProcThreadAttribute         = kernel32.NewProc("UpdateProcThreadAttribute")
+	procDeleteProcThreadAttributeList     = kernel32.NewProc("DeleteProcThreadAttributeList")
+)
+
+const (
+	procThreadAttributePseudoConsole = 0x00020016
+	extendedStartupInfoPresent       = 0x00080000
+	createUnicodeEnvironment         = 0x00000400
+)
+
+// sysPTY holds the pseudo console, closed when the command exits so
+// that reads of its output reach EOF, as on Unix.
+type sysPTY struct {
+	hpc     syscall.Handle
+	once    sync.Once
+	process *os.Process
+}
+
+// startupInfoEx mirrors STARTUPINFOEXW.
+type startupInfoEx struct {
+	syscall.StartupInfo
+	AttributeList *byte
+}
+
+// coord packs a size into a COORD passed by value.
+func coord(size Size) uintptr {
+	return uintptr(size.Cols) | uintptr(size.Rows)<<16
+}
+
+// start creates a pseudo console over two pipes and starts cmd attached
+// to it with CreateProcess, since exec.Cmd cannot pass the console
+// attribute. cmd.Process is set, but the streams of cmd are not used:
+// a console process writes to its console.
+func start(cmd *exec.Cmd, size Size) (*PTY, error) {
+	if err := procCreatePseudoConsole.Find(); err != nil {
+		return nil, ErrUnsupported
+	}
+	var inR, inW, outR, outW syscall.Handle
+	if err := syscall.CreatePipe(&inR, &inW, nil, 0); err != nil {
+		return nil, os.NewSyscallError("CreatePipe", err)
+	}
+	if err := syscall.CreatePipe(&outR, &outW, nil, 0); err != nil {
+		closeHandles(inR, inW)
+		return nil, os.NewSyscallError("CreatePipe", err)
+	}
+	var hpc syscall.Handle
+	r, _, _ := procCreatePseudoConsole.Call(coord(size), uintptr(inR), uintptr(outW), 0, uintptr(unsafe.Pointer(&hpc)))
+	// The console holds its own references to its ends of the pipes.
+	closeHandles(inR, outW)
+	if r != 0 {
+		closeHandles(inW, outR)
+		return nil, os.NewSyscallError("CreatePseudoConsole", syscall.Errno(r))
+	}
+	p := &PTY{
+		cmd: cmd,
+		in:  os.NewFile(uintptr(inW), "conpty-input"),
+		out: os.NewFile(uintptr(outR), "conpty-output"),
+		sys: sysPTY{hpc: hpc},
+	}
+	if err := p.createProcess(); err != nil {
+		_ = p.close()
+		return nil, err
+	}
+	return p, nil
+}
+
+// createProcess starts p.cmd with the pseudo console attribute.
+func (p *PTY) createProcess() error {
+	cmd := p.cmd
+	var n uintptr
+	_, _, _ = procInitializeProcThreadAttributeList.Call(0, 1, 0, uintptr(unsafe.Pointer(&n)))
+	list := make([]byte, n)
+	if r, _, err := procInitializeProcThreadAttributeList.Call(uintptr(unsafe.Pointer(&list[0])), 1, 0, uintptr(unsafe.Pointer(&n))); r == 0 {
+		return os.NewSyscallError("InitializeProcThreadAttributeList", err)
+	}
+	defer func() { _, _, _ = procDeleteProcThreadAttributeList.Call(uintptr(unsafe.Pointer(&list[0]))) }()
+	if r, _, err := procUpdateProcThreadAttribute.Call(uintptr(unsafe.Pointer(&list[0])), 0,
+		procThreadAttributePseudoConsole, uintptr(p.sys.hpc), unsafe.Sizeof(p.sys.hpc), 0, 0); r == 0 {
+		return os.NewSyscallError("UpdateProcThreadAttribute", err)
+	}
+
+	var si startupInfoEx
+	si.Cb = uint32(unsafe.Sizeof(si))
+	si.AttributeList = &list[0]
+
+	cmdline := cmdLine(cmd)
+	argv, err := syscall.UTF16PtrFromString(cmdline)
+	if err != nil {
+		return err
+	}
+	var dir *uint16
+	if cmd.Dir != "" {
+		if dir, err = syscall.UTF16PtrFromString(cmd.Dir); err != nil {
+			return err
+		}
+	}
+	env, err := envBlock(cmd.Environ())
+	if err != nil {
+		return err
+	}
+	var pi syscall.ProcessInformation
+	err = syscall.CreateProcess(nil, argv, nil, nil, false,
+		extendedStartupInfoPresent|createUnicodeEnvironment, &env[0], dir,
+		&si.StartupInfo, &pi)
+	if err != nil {
+		return &os.PathError{Op: "CreateProcess", Path: cmd.Path, Err: err}
+	}
+	defer closeHandles(pi.Thread, pi.Process)
+	// Holding pi.Process keeps the PID from being reused until the
+	// os.Process has its own handle.
+	proc, err := os.FindProcess(int(pi.ProcessId))
+	if err != nil {
+		return err
+	}
+	cmd.Process = proc
+	p.sys.process = proc
+	return nil
+}
+
+// cmdLine returns cmd.SysProcAttr.CmdLine or quotes the path and
+// arguments as os/exec does.
+func cmdLine(cmd *exec.Cmd) string {
+	if cmd.SysProcAttr != nil && cmd.SysProcAttr.CmdLine != "" {
+		return cmd.SysProcAttr.CmdLine
+	}
+	args := append([]string{cmd.Path}, cmd.Args[min(1, len(cmd.Args)):]...)
+	for i, a := range args {
+		args[i] = syscall.EscapeArg(a)
+	}
+	return strings.Join(args, " ")
+}
+
+// envBlock encodes env as a CreateProcess environment block.
+func envBlock(env []string) ([]uint16, error) {
+	var b []uint16
+	for _, kv := range env {
+		if strings.IndexByte(kv, 0) >= 0 {
+			return nil, errors.New("oscompat/process/pty: environment contains a NUL byte")
+		}
+		b = append(b, utf16.Encode([]rune(kv))...)
+		b = append(b, 0)
+	}
+	if len(b) == 0 {
+		b = append(b, 0)
+	}
+	return append(b, 0), nil
+}
+
+// read maps the broken pipe of a closed pseudo console to io.EOF.
+func (p *PTY) read(b []byte) (int, error) {
+	n, err := p.out.Read(b)
+	if errors.Is(err, syscall.ERROR_BROKEN_PIPE) {
+		err = io.EOF
+	}
+	return n, err
+}
+
+// resize calls ResizePseudoConsole.
+func (p *PTY) resize(size Size) error {
+	if r, _, _ := procResizePseudoConsole.Call(uintptr(p.sys.hpc), coord(size)); r != 0 {
+		return os.NewSyscallError("ResizePseudoConsole", syscall.Errno(r))
+	}
+	return nil
+}
+
+// wait waits for the process, then closes the pseudo console so that
+// the remaining output is flushed and reads reach EOF.
+func (p *PTY) wait() error {
+	if p.sys.process == nil {
+		return errors.New("oscompat/process/pty: not started")
+	}
+	state, err := p.sys.process.Wait()
+	if err != nil {
+		return err
+	}
+	p.cmd.ProcessState = state
+	p.closeConsole()
+	if !state.Success() {
+		return &exec.ExitError{ProcessState: state}
+	}
+	return nil
+}
+
+// closeConsole closes the pseudo console once. Closing it blocks until
+// its final output is read, so it runs on its own goroutine.
+func (p *PTY) closeConsole() {
+	p.sys.once.Do(func() {
+		go func() { _, _, _ = procClosePseudoConsole.Call(uintptr(p.sys.hpc)) }()
+	})
+}
+
+// close closes the pseudo console and the pipes.
+func (p *PTY) close() error {
+	p.closeConsole()
+	err := p.in.Close()
+	if outErr := p.out.Close(); err == nil {
+		err = outErr
+	}
+	return err
+}
+
+// closeHandles closes each handle, ignoring errors.
+func closeHandles(hs ...syscall.Handle) {
+	for _, h := range hs {
+		_ = syscall.CloseHandle(h)
+	}
+}
@@ -0,0 +1,131 @@
+package process
+
+import (
+	"runtime"
+	"strings"
+)
+
+// QuoteArg quotes s so that it is read back as a single argument: with
+// QuotePOSIX on Unix and QuoteWindows on Windows.
+func QuoteArg(s string) string {
+	if runtime.GOOS == "windows" {
+		return QuoteWindows(s)
+	}
+	return QuotePOSIX(s)
+}
+
+// QuoteArgs quotes each argument with QuoteArg and joins them with spaces.
+func QuoteArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = QuoteArg(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// QuoteShellArg quotes s for the shell ShellCommand runs: with
+// QuotePOSIX on Unix and QuoteCmd on Windows.
+func QuoteShellArg(s string) string {
+	if runtime.GOOS == "windows" {
+		return QuoteCmd(s)
+	}
+	return QuotePOSIX(s)
+}
+
+// QuoteShellArgs quotes each argument with QuoteShellArg and joins them
+// with spaces, for building a ShellCommand line from untrusted values.
+func QuoteShellArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = QuoteShellArg(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// QuotePOSIX quotes s for a POSIX shell. Strings made only of characters
+// that are never special to the shell are returned unchanged; anything
+// else is wrapped in single quotes.
+func QuotePOSIX(s string) string {
+	if s == "" {
+		return "''"
+	}
+	if strings.IndexFunc(s, func(r rune) bool { return !isShellSafe(r) }) < 0 {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// isShellSafe reports whether r never needs quoting in a POSIX shell.
+func isShellSafe(r rune) bool {
+	switch {
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		return true
+	}
+	return strings.ContainsRune("-_./:=@%+,", r)
+}
+
+// QuoteWindows quotes s for a Windows command line so that
+// CommandLineToArgvW, and the C and Go runtimes, which follow the same
+// rules, read it back as a single argument. Backslashes are doubled only
+// where they precede a double quote.
+//
+// The result is not safe to pass through cmd.exe, which has its own
+// metacharacters (such as & | < > ^ and %); use QuoteCmd when building
+// a line for ShellCommand on Windows.
+func QuoteWindows(s string) string {
+	if s == "" {
+		return `""`
+	}
+	if !strings.ContainsAny(s, " \t\n\v\"") {
+		return s
+	}
+
+	var b strings.Builder
+	b.WriteByte('"')
+	slashes := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch c {
+		case '\\':
+			slashes++
+			continue
+		case '"':
+			// Escape the preceding backslashes and the quote itself.
+			b.WriteString(strings.Repeat(`\`, 2*slashes+1))
+		default:
+			b.WriteString(strings.Repeat(`\`, slashes))
+		}
+		slashes = 0
+		b.WriteByte(c)
+	}
+	// Backslashes before the closing quote must be doubled.
+	b.WriteString(strings.Repeat(`\`, 2*slashes))
+	b.WriteByte('"')
+	return b.String()
+}
+
+// QuoteCmd quotes s for a command line run by cmd.exe, as ShellCommand
+// does on Windows: it quotes s with QuoteWindows for the program that
+// receives it, then escapes every cmd.exe metacharacter in the result,
+// quotes included, with a caret, so cmd.exe passes the argument through
+// literally instead of acting on & | < > or expanding %VAR%.
+//
+// cmd.exe ends a command at a line break, so a string containing one
+// cannot be passed through it intact.
+func QuoteCmd(s string) string {
+	q := QuoteWindows(s)
+	if !strings.ContainsAny(q, cmdMeta) {
+		return q
+	}
+	var b strings.Builder
+	for _, r := range q {
+		if strings.ContainsRune(cmdMeta, r) {
+			b.WriteByte('^')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// cmdMeta holds the characters cmd.exe treats specially on a command line.
+const cmdMeta = `()%!^"<>&|`
@@ -0,0 +1,89 @@
+package process_test
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/grokify/oscompat/process"
+)
+
+func TestQuotePOSIX(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"", "''"},
+		{"plain", "plain"},
+		{"a/b-c_d.e:f=g@h%i+j,k", "a/b-c_d.e:f=g@h%i+j,k"},
+		{"with space", "'with space'"},
+		{"it's", `'it'\''s'`},
+		{"$HOME", "'$HOME'"},
+		{"a*b", "'a*b'"},
+	}
+	for _, tt := range tests {
+		if got := process.QuotePOSIX(tt.in); got != tt.want {
+			t.Errorf("QuotePOSIX(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestQuoteWindows(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"", `""`},
+		{"plain", "plain"},
+		{`C:\Program Files\app`, `"C:\Program Files\app"`},
+		{`C:\dir\`, `C:\dir\`},
+		{`C:\my dir\`, `"C:\my dir\\"`},
+		{`say "hi"`, `"say \"hi\""`},
+		{`a\"b`, `"a\\\"b"`},
+		{`a\\b c`, `"a\\b c"`},
+	}
+	for _, tt := range tests {
+		if got := process.QuoteWindows(tt.in); got != tt.want {
+			t.Errorf("QuoteWindows(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestQuoteCmd(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"", `^"^"`},
+		{"plain", "plain"},
+		{"a&calc", "a^&calc"},
+		{`a" & calc & "`, `^"a\^" ^& calc ^& \^"^"`},
+		{"%PATH%", "^%PATH^%"},
+		{`C:\my dir\`, `^"C:\my dir\\^"`},
+		{"(x|y)>z", "^(x^|y^)^>z"},
+	}
+	for _, tt := range tests {
+		if got := process.QuoteCmd(tt.in); got != tt.want {
+			t.Errorf("QuoteCmd(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestQuoteArgsRoundTrip(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("round trip uses sh")
+	}
+	args := []string{"", "plain", "two words", `back\slash`, `"quoted"`, "it's", `trail\`}
+	cmd := process.ShellCommand(`printf '%s\n' ` + process.QuoteArgs(args))
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("Output() error: %v", err)
+	}
+	got := strings.Split(strings.TrimSuffix(string(out), "\n"), "\n")
+	if strings.Join(got, "|") != strings.Join(args, "|") {
+		t.Errorf("round trip = %q, want %q", got, args)
+	}
+}
+
+func TestShellCommand(t *testing.T) {
+	cmd := process.ShellCommand("echo one && echo two")
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("Output() error: %v", err)
+	}
+	got := strings.Fields(string(out))
+	if len(got) != 2 || got[0] != "one" || got[1] != "two" {
+		t.Errorf("ShellCommand output = %q, want one and two", out)
+	}
+}
@@ -0,0 +1,37 @@
+package process
+
+import "context"
+
+// ReapChildren collects every child process that has already exited,
+// without blocking, and returns how many were reaped.
+//
+// Programs running as PID 1 in a container, or as a subreaper (see
+// SetSubreaper), inherit orphaned descendants and must reap them or they
+// accumulate as zombies. ReapChildren waits for any child, so it also
+// consumes the exit status of children started with os/exec; do not use
+// it while exec.Cmd.Wait may still be called for a running command.
+//
+// On Windows there are no zombie processes and ReapChildren returns 0.
+func ReapChildren() (int, error) {
+	return reapChildren()
+}
+
+// RunReaper reaps exited children whenever SIGCHLD arrives until ctx is
+// done. It is meant for init-like container entrypoints; the caveats of
+// ReapChildren about os/exec apply.
+//
+// On Windows it blocks until ctx is done without doing anything.
+func RunReaper(ctx context.Context) {
+	runReaper(ctx)
+}
+
+// SetSubreaper marks the current process as a child subreaper, so
+// orphaned descendants are reparented to it rather than to init. Combine
+// with RunReaper to keep them from lingering as zombies.
+//
+// It is supported on Linux only and returns ErrUnsupported elsewhere. On
+// Windows, orphans are not reparented and leave no zombies; use a Group to
+// keep track of and clean up descendants instead.
+func SetSubreaper() error {
+	return setSubreaper()
+}
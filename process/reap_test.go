@@ -0,0 +1,90 @@
+package process_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/grokify/oscompat/process"
+)
+
+// TestReapHelper becomes a subreaper, orphans a grandchild, and reaps it.
+// It runs in its own process because reaping would steal exit statuses
+// from other tests' commands.
+func TestReapHelper(t *testing.T) {
+	if os.Getenv("OSCOMPAT_REAP_HELPER") != "1" {
+		t.Skip("helper for TestReapChildren")
+	}
+	if err := process.SetSubreaper(); err != nil {
+		t.Fatalf("SetSubreaper() error: %v", err)
+	}
+	// sh exits at once; its background sleep is reparented to us.
+	if err := exec.Command("sh", "-c", "sleep 0.1 &").Run(); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		n, err := process.ReapChildren()
+		if err != nil {
+			t.Fatalf("ReapChildren() error: %v", err)
+		}
+		if n > 0 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("ReapChildren() did not reap the orphaned grandchild")
+}
+
+func TestReapChildren(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("subreapers are Linux-only")
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		t.Fatalf("Executable() error: %v", err)
+	}
+	cmd := exec.Command(exe, "-test.run=^TestReapHelper$")
+	cmd.Env = append(os.Environ(), "OSCOMPAT_REAP_HELPER=1")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Errorf("helper failed: %v\n%s", err, out)
+	}
+}
+
+func TestReapChildrenNone(t *testing.T) {
+	if runtime.GOOS != "windows" {
+		t.Skip("Windows has no zombies")
+	}
+	if n, err := process.ReapChildren(); n != 0 || err != nil {
+		t.Errorf("ReapChildren() = %d, %v; want 0, nil", n, err)
+	}
+}
+
+func TestRunReaper(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	done := make(chan struct{})
+	go func() {
+		process.RunReaper(ctx)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("RunReaper() did not return after cancel")
+	}
+}
+
+func TestSetSubreaperUnsupported(t *testing.T) {
+	if runtime.GOOS == "linux" {
+		t.Skip("supported on Linux")
+	}
+	if err := process.SetSubreaper(); !errors.Is(err, errors.ErrUnsupported) {
+		t.Errorf("SetSubreaper() error = %v, want ErrUnsupported", err)
+	}
+}
@@ -0,0 +1,52 @@
+//go:build !windows
+
+package process
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// reapChildren calls wait4 with WNOHANG until no exited child remains.
+func reapChildren() (int, error) {
+	n := 0
+	for {
+		var status syscall.WaitStatus
+		pid, err := syscall.Wait4(-1, &status, syscall.WNOHANG, nil)
+		switch {
+		case errors.Is(err, syscall.EINTR):
+			continue
+		case errors.Is(err, syscall.ECHILD):
+			return n, nil
+		case err != nil:
+			return n, err
+		case pid <= 0:
+			return n, nil
+		}
+		n++
+	}
+}
+
+// runReaper reaps on every SIGCHLD. Signals are coalesced, so each wakeup
+// drains all exited children.
+func runReaper(ctx context.Context) {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGCHLD)
+	defer signal.Stop(sigs)
+
+	for {
+		// Children may have exited before the handler was installed.
+		if ctx.Err() != nil {
+			return
+		}
+		_, _ = reapChildren()
+		select {
+		case <-ctx.Done():
+			return
+		case <-sigs:
+		}
+	}
+}
@@ -0,0 +1,15 @@
+//go:build windows
+
+package process
+
+import "context"
+
+// reapChildren is a no-op; Windows has no zombie processes.
+func reapChildren() (int, error) {
+	return 0, nil
+}
+
+// runReaper waits for ctx; there is nothing to reap on Windows.
+func runReaper(ctx context.Context) {
+	<-ctx.Done()
+}
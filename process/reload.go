@@ -0,0 +1,67 @@
+package process
+
+import "context"
+
+// NotifyReload returns a channel that receives a value each time the
+// process is asked to reload its configuration. Requests that arrive
+// while a previous one is still pending are coalesced. The channel is
+// closed when ctx is done.
+//
+// Platform behavior:
+//   - Unix: reload is requested with SIGHUP. While any NotifyReload
+//     channel is active, SIGHUP no longer cancels NotifyShutdown contexts.
+//   - Windows: there is no SIGHUP, so reload is requested by signaling a
+//     named event derived from the PID. The event lives in the Local
+//     namespace and is only visible within the same login session.
+//
+// Example:
+//
+//	for range process.NotifyReload(ctx) {
+//		reloadConfig()
+//	}
+func NotifyReload(ctx context.Context) <-chan struct{} {
+	ch := make(chan struct{}, 1)
+	notifyReload(ctx, ch)
+	return ch
+}
+
+// RequestReload asks the process with the given PID to reload, as
+// received by NotifyReload in that process.
+//
+// On Windows, an error is returned if the process is not listening for
+// reload requests. On Unix, a process that does not handle SIGHUP is
+// terminated by it.
+func RequestReload(pid int) error {
+	if pid <= 0 {
+		return ErrNotFound
+	}
+	return requestReload(pid)
+}
+
+// RequestReloadByName sends a reload request to every process whose
+// executable name is name, as matched by FindByName. It returns
+// ErrNotFound if there are none, or the first error encountered.
+func RequestReloadByName(name string) error {
+	procs, err := FindByName(name)
+	if err != nil {
+		return err
+	}
+	if len(procs) == 0 {
+		return ErrNotFound
+	}
+	var firstErr error
+	for _, p := range procs {
+		if err := requestReload(p.PID); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// sendReload performs a non-blocking send, coalescing pending requests.
+func sendReload(ch chan<- struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,50 @@
+package process_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/grokify/oscompat/process"
+)
+
+func TestNotifyReload(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	shutdownCtx, stop := process.NotifyShutdown(context.Background())
+	defer stop()
+
+	reload := process.NotifyReload(ctx)
+	time.Sleep(50 * time.Millisecond) // let the listener start
+
+	if err := process.RequestReload(os.Getpid()); err != nil {
+		t.Fatalf("RequestReload() error: %v", err)
+	}
+
+	select {
+	case <-reload:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no reload request received")
+	}
+	if shutdownCtx.Err() != nil {
+		t.Error("reload request canceled the shutdown context")
+	}
+
+	cancel()
+	select {
+	case _, ok := <-reload:
+		if ok {
+			t.Error("unexpected reload after cancel")
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("channel not closed after cancel")
+	}
+}
+
+func TestRequestReloadNotFound(t *testing.T) {
+	if err := process.RequestReload(-1); err != process.ErrNotFound {
+		t.Errorf("RequestReload(-1) error = %v, want ErrNotFound", err)
+	}
+}
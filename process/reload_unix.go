@@ -0,0 +1,45 @@
+//go:build !windows
+
+package process
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+)
+
+// reloadListeners counts active NotifyReload channels. While it is
+// nonzero, SIGHUP means reload rather than shutdown.
+var reloadListeners atomic.Int32
+
+// notifyReload forwards SIGHUP to ch until ctx is done.
+func notifyReload(ctx context.Context, ch chan struct{}) {
+	sigs := make(chan os.Signal, 1)
+	reloadListeners.Add(1)
+	signal.Notify(sigs, syscall.SIGHUP)
+
+	go func() {
+		defer close(ch)
+		defer reloadListeners.Add(-1)
+		defer signal.Stop(sigs)
+		for {
+			select {
+			case <-sigs:
+				sendReload(ch)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// requestReload sends SIGHUP to pid.
+func requestReload(pid int) error {
+	err := syscall.Kill(pid, syscall.SIGHUP)
+	if err == syscall.ESRCH {
+		return ErrNotFound
+	}
+	return err
+}
@@ -0,0 +1,106 @@
+//go:build windows
+
+package process
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	procCreateEventW           = kernel32.NewProc("CreateEventW")
+	procOpenEventW             = kernel32.NewProc("OpenEventW")
+	procSetEvent               = kernel32.NewProc("SetEvent")
+	procWaitForMultipleObjects = kernel32.NewProc("WaitForMultipleObjects")
+)
+
+const eventModifyState = 0x0002
+
+// reloadEventName returns the name of the reload event for pid.
+func reloadEventName(pid int) string {
+	return `Local\oscompat-process-reload-` + strconv.Itoa(pid)
+}
+
+// createEvent creates an auto-reset event. An empty name creates an
+// anonymous event.
+func createEvent(name string) (syscall.Handle, error) {
+	var namePtr *uint16
+	if name != "" {
+		var err error
+		if namePtr, err = syscall.UTF16PtrFromString(name); err != nil {
+			return 0, err
+		}
+	}
+	r, _, err := procCreateEventW.Call(0, 0, 0, uintptr(unsafe.Pointer(namePtr)))
+	if r == 0 {
+		return 0, err
+	}
+	return syscall.Handle(r), nil
+}
+
+// notifyReload waits on the reload event for this process until ctx is
+// done. If the event cannot be created, ch is closed when ctx is done and
+// never receives.
+func notifyReload(ctx context.Context, ch chan struct{}) {
+	reload, err := createEvent(reloadEventName(syscall.Getpid()))
+	if err != nil {
+		go func() {
+			<-ctx.Done()
+			close(ch)
+		}()
+		return
+	}
+	done, err := createEvent("")
+	if err != nil {
+		_ = syscall.CloseHandle(reload)
+		go func() {
+			<-ctx.Done()
+			close(ch)
+		}()
+		return
+	}
+
+	go func() {
+		<-ctx.Done()
+		_, _, _ = procSetEvent.Call(uintptr(done))
+	}()
+
+	go func() {
+		defer close(ch)
+		defer func() { _ = syscall.CloseHandle(done) }()
+		defer func() { _ = syscall.CloseHandle(reload) }()
+		handles := [2]syscall.Handle{reload, done}
+		for {
+			r, _, _ := procWaitForMultipleObjects.Call(2, uintptr(unsafe.Pointer(&handles[0])), 0, syscall.INFINITE)
+			if r != syscall.WAIT_OBJECT_0 {
+				return
+			}
+			sendReload(ch)
+		}
+	}()
+}
+
+// requestReload signals the reload event of pid.
+func requestReload(pid int) error {
+	name, err := syscall.UTF16PtrFromString(reloadEventName(pid))
+	if err != nil {
+		return err
+	}
+	r, _, err := procOpenEventW.Call(eventModifyState, 0, uintptr(unsafe.Pointer(name)))
+	if r == 0 {
+		if err == syscall.ERROR_FILE_NOT_FOUND {
+			return fmt.Errorf("oscompat/process: process %d is not listening for reload requests", pid)
+		}
+		return err
+	}
+	h := syscall.Handle(r)
+	defer func() { _ = syscall.CloseHandle(h) }()
+
+	if r, _, err := procSetEvent.Call(uintptr(h)); r == 0 {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// LaunchdPlist returns the launchd property list that Install writes on
+// macOS. It is exported so that packagers can ship the plist themselves.
+func (cfg Config) LaunchdPlist() string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">` + "\n")
+	b.WriteString(`<plist version="1.0">` + "\n<dict>\n")
+
+	plistKey(&b, "Label")
+	plistString(&b, cfg.Name)
+
+	plistKey(&b, "ProgramArguments")
+	b.WriteString("\t<array>\n")
+	for _, arg := range append([]string{cfg.Executable}, cfg.Args...) {
+		b.WriteString("\t")
+		plistString(&b, arg)
+	}
+	b.WriteString("\t</array>\n")
+
+	if cfg.Dir != "" {
+		plistKey(&b, "WorkingDirectory")
+		plistString(&b, cfg.Dir)
+	}
+
+	if len(cfg.Env) > 0 {
+		plistKey(&b, "EnvironmentVariables")
+		b.WriteString("\t<dict>\n")
+		for _, kv := range cfg.Env {
+			k, v, _ := strings.Cut(kv, "=")
+			b.WriteString("\t")
+			plistKey(&b, k)
+			b.WriteString("\t")
+			plistString(&b, v)
+		}
+		b.WriteString("\t</dict>\n")
+	}
+
+	plistKey(&b, "RunAtLoad")
+	b.WriteString("\t<true/>\n")
+
+	// Restart after a crash but not after a clean exit.
+	plistKey(&b, "KeepAlive")
+	b.WriteString("\t<dict>\n\t\t<key>SuccessfulExit</key>\n\t\t<false/>\n\t</dict>\n")
+
+	b.WriteString("</dict>\n</plist>\n")
+	return b.String()
+}
+
+// plistKey writes a <key> element.
+func plistKey(b *strings.Builder, key string) {
+	b.WriteString("\t<key>")
+	_ = xml.EscapeText(b, []byte(key))
+	b.WriteString("</key>\n")
+}
+
+// plistString writes a <string> element.
+func plistString(b *strings.Builder, s string) {
+	b.WriteString("\t<string>")
+	_ = xml.EscapeText(b, []byte(s))
+	b.WriteString("</string>\n")
+}
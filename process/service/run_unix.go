@@ -0,0 +1,8 @@
+//go:build !windows
+
+package service
+
+// run runs h directly; systemd and launchd stop services with SIGTERM.
+func run(cfg Config, h Handler) error {
+	return runInteractive(h)
+}
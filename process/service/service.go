@@ -0,0 +1,219 @@
+// Package service installs, controls, and runs programs as native system
+// services.
+//
+// Each platform uses its own service manager:
+//   - Windows: the Service Control Manager (SCM)
+//   - Linux: systemd units, controlled with systemctl
+//   - macOS: launchd daemons and agents, controlled with launchctl
+//
+// Installing a system-wide service requires administrator or root
+// privileges. Set Config.User to install a per-user service instead on
+// Linux and macOS.
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/grokify/oscompat/process"
+)
+
+// Common errors.
+var (
+	// ErrNotInstalled is returned when the service is not installed.
+	ErrNotInstalled = errors.New("oscompat/process/service: service not installed")
+
+	// ErrAlreadyInstalled is returned by Install when the service exists.
+	ErrAlreadyInstalled = errors.New("oscompat/process/service: service already installed")
+
+	// ErrInvalidName is returned when Config.Name is not a valid service name.
+	ErrInvalidName = errors.New("oscompat/process/service: invalid service name")
+
+	// ErrUnsupported is returned when an operation is not available on the
+	// current platform. It matches errors.ErrUnsupported.
+	ErrUnsupported = fmt.Errorf("oscompat/process/service: %w", errors.ErrUnsupported)
+)
+
+// maxNameLen is the longest service name accepted, the SCM limit.
+const maxNameLen = 256
+
+// Config describes a service.
+type Config struct {
+	// Name identifies the service to the service manager. It may contain
+	// ASCII letters, digits, '.', '-', and '_' and must start with a
+	// letter or digit. On macOS it is the launchd label, conventionally in
+	// reverse-DNS form such as "com.example.agent".
+	Name string
+
+	// DisplayName is a human-readable name. It defaults to Name.
+	DisplayName string
+
+	// Description explains what the service does.
+	Description string
+
+	// Executable is the absolute path of the program to run. It defaults
+	// to the current executable.
+	Executable string
+
+	// Args are the arguments passed to Executable.
+	Args []string
+
+	// Dir is the working directory of the service.
+	Dir string
+
+	// Env lists additional environment variables in "KEY=value" form.
+	Env []string
+
+	// User installs a per-user service (a systemd user unit or a launchd
+	// agent) rather than a system-wide one. Windows services are always
+	// system-wide, so Install returns ErrUnsupported if User is set there.
+	User bool
+}
+
+// State is the run state of an installed service.
+type State int
+
+// Service states.
+const (
+	StateUnknown State = iota
+	StateStopped
+	StateStarting
+	StateRunning
+	StateStopping
+)
+
+// String returns the name of the state.
+func (s State) String() string {
+	switch s {
+	case StateUnknown:
+		return "unknown"
+	case StateStopped:
+		return "stopped"
+	case StateStarting:
+		return "starting"
+	case StateRunning:
+		return "running"
+	case StateStopping:
+		return "stopping"
+	default:
+		return "State(" + strconv.Itoa(int(s)) + ")"
+	}
+}
+
+// Handler is the body of a service. It should return when ctx is
+// canceled, which happens when the service manager asks the service to
+// stop.
+type Handler func(ctx context.Context) error
+
+// Install registers the service with the service manager and configures it
+// to start at boot (or at login for user services). It does not start the
+// service; call Start for that.
+func Install(cfg Config) error {
+	cfg, err := cfg.normalize()
+	if err != nil {
+		return err
+	}
+	return install(cfg)
+}
+
+// Uninstall stops the service if it is running and removes it from the
+// service manager.
+func Uninstall(cfg Config) error {
+	if err := validateName(cfg.Name); err != nil {
+		return err
+	}
+	return uninstall(cfg)
+}
+
+// Start starts an installed service. Starting a running service is not an
+// error.
+func Start(cfg Config) error {
+	if err := validateName(cfg.Name); err != nil {
+		return err
+	}
+	return start(cfg)
+}
+
+// Stop stops an installed service. Stopping a stopped service is not an
+// error.
+func Stop(cfg Config) error {
+	if err := validateName(cfg.Name); err != nil {
+		return err
+	}
+	return stop(cfg)
+}
+
+// Status returns the run state of an installed service.
+func Status(cfg Config) (State, error) {
+	if err := validateName(cfg.Name); err != nil {
+		return StateUnknown, err
+	}
+	return status(cfg)
+}
+
+// Run runs h as the body of the service and returns its error.
+//
+// On Windows, when the program was started by the SCM, Run connects to it,
+// reports the service as running, and cancels the context when the SCM
+// sends a stop or shutdown request. When started any other way, and on all
+// other platforms, Run calls h with a context from process.NotifyShutdown,
+// which is how systemd and launchd ask a service to stop.
+func Run(cfg Config, h Handler) error {
+	return run(cfg, h)
+}
+
+// runInteractive runs h until it returns, canceling its context on a
+// shutdown signal.
+func runInteractive(h Handler) error {
+	ctx, stop := process.NotifyShutdown(context.Background())
+	defer stop()
+	return h(ctx)
+}
+
+// normalize validates cfg and fills in defaults.
+func (cfg Config) normalize() (Config, error) {
+	if err := validateName(cfg.Name); err != nil {
+		return cfg, err
+	}
+	if strings.ContainsAny(cfg.Dir, "\r\n") {
+		return cfg, fmt.Errorf("oscompat/process/service: working directory %q contains a line break", cfg.Dir)
+	}
+	if cfg.DisplayName == "" {
+		cfg.DisplayName = cfg.Name
+	}
+	if cfg.Executable == "" {
+		exe, err := os.Executable()
+		if err != nil {
+			return cfg, fmt.Errorf("oscompat/process/service: failed to find executable: %w", err)
+		}
+		cfg.Executable = exe
+	}
+	exe, err := filepath.Abs(cfg.Executable)
+	if err != nil {
+		return cfg, err
+	}
+	cfg.Executable = exe
+	return cfg, nil
+}
+
+// validateName checks that name is safe to use as a service name and in
+// file names.
+func validateName(name string) error {
+	if name == "" || len(name) > maxNameLen {
+		return ErrInvalidName
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		isAlnum := c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+		isPunct := c == '.' || c == '-' || c == '_'
+		if !isAlnum && !(isPunct && i > 0) {
+			return ErrInvalidName
+		}
+	}
+	return nil
+}
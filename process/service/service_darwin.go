@@ -0,0 +1,127 @@
+//go:build darwin
+
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// launchctlPID matches the PID entry in launchctl list output.
+var launchctlPID = regexp.MustCompile(`"PID"\s*=\s*\d+;`)
+
+// plistPath returns the path of the launchd plist for cfg.
+func plistPath(cfg Config) (string, error) {
+	if !cfg.User {
+		return filepath.Join("/Library/LaunchDaemons", cfg.Name+".plist"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "Library", "LaunchAgents", cfg.Name+".plist"), nil
+}
+
+// installedPlistPath returns the plist path, or ErrNotInstalled if the
+// plist does not exist.
+func installedPlistPath(cfg Config) (string, error) {
+	path, err := plistPath(cfg)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return "", ErrNotInstalled
+	}
+	return path, nil
+}
+
+// launchctl runs launchctl and returns its trimmed standard output.
+func launchctl(args ...string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("launchctl", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	out := strings.TrimSpace(stdout.String())
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return out, fmt.Errorf("oscompat/process/service: launchctl %s: %s", strings.Join(args, " "), msg)
+	}
+	return out, nil
+}
+
+// install writes the plist. launchd loads it at the next boot or login;
+// Start loads it immediately.
+func install(cfg Config) error {
+	path, err := plistPath(cfg)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); err == nil {
+		return ErrAlreadyInstalled
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(cfg.LaunchdPlist()), 0644)
+}
+
+// uninstall unloads the job and removes its plist.
+func uninstall(cfg Config) error {
+	path, err := installedPlistPath(cfg)
+	if err != nil {
+		return err
+	}
+	// Best effort: the job may not be loaded.
+	_, _ = launchctl("unload", "-w", path)
+	return os.Remove(path)
+}
+
+// start loads the job, which runs it because of RunAtLoad.
+func start(cfg Config) error {
+	path, err := installedPlistPath(cfg)
+	if err != nil {
+		return err
+	}
+	if _, err := launchctl("list", cfg.Name); err == nil {
+		_, err = launchctl("start", cfg.Name)
+		return err
+	}
+	_, err = launchctl("load", "-w", path)
+	return err
+}
+
+// stop unloads the job. Stopping a loaded KeepAlive job would only make
+// launchd restart it.
+func stop(cfg Config) error {
+	path, err := installedPlistPath(cfg)
+	if err != nil {
+		return err
+	}
+	if _, err := launchctl("list", cfg.Name); err != nil {
+		return nil // not loaded
+	}
+	_, err = launchctl("unload", path)
+	return err
+}
+
+// status reports whether the job is loaded and has a PID.
+func status(cfg Config) (State, error) {
+	if _, err := installedPlistPath(cfg); err != nil {
+		return StateUnknown, err
+	}
+	out, err := launchctl("list", cfg.Name)
+	if err != nil || !launchctlPID.MatchString(out) {
+		return StateStopped, nil
+	}
+	return StateRunning, nil
+}
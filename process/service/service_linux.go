@@ -0,0 +1,142 @@
+//go:build linux
+
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// unitPath returns the path of the unit file for cfg.
+func unitPath(cfg Config) (string, error) {
+	if !cfg.User {
+		return filepath.Join("/etc/systemd/system", cfg.Name+".service"), nil
+	}
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "systemd", "user", cfg.Name+".service"), nil
+}
+
+// installedUnitPath returns the unit path, or ErrNotInstalled if the unit
+// file does not exist.
+func installedUnitPath(cfg Config) (string, error) {
+	path, err := unitPath(cfg)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return "", ErrNotInstalled
+	}
+	return path, nil
+}
+
+// systemctl runs systemctl, in user mode if requested, and returns its
+// trimmed standard output.
+func systemctl(user bool, args ...string) (string, error) {
+	if user {
+		args = append([]string{"--user"}, args...)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("systemctl", args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	out := strings.TrimSpace(stdout.String())
+	if err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return out, fmt.Errorf("oscompat/process/service: systemctl %s: %s", strings.Join(args, " "), msg)
+	}
+	return out, nil
+}
+
+// install writes the unit file and enables it.
+func install(cfg Config) error {
+	path, err := unitPath(cfg)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); err == nil {
+		return ErrAlreadyInstalled
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(cfg.SystemdUnit()), 0644); err != nil {
+		return err
+	}
+	if _, err := systemctl(cfg.User, "daemon-reload"); err != nil {
+		return err
+	}
+	_, err = systemctl(cfg.User, "enable", cfg.Name+".service")
+	return err
+}
+
+// uninstall stops and disables the unit and removes its file.
+func uninstall(cfg Config) error {
+	path, err := installedUnitPath(cfg)
+	if err != nil {
+		return err
+	}
+	// Best effort: the unit may already be stopped or disabled.
+	_, _ = systemctl(cfg.User, "disable", "--now", cfg.Name+".service")
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	_, err = systemctl(cfg.User, "daemon-reload")
+	return err
+}
+
+// start starts the unit.
+func start(cfg Config) error {
+	if _, err := installedUnitPath(cfg); err != nil {
+		return err
+	}
+	_, err := systemctl(cfg.User, "start", cfg.Name+".service")
+	return err
+}
+
+// stop stops the unit.
+func stop(cfg Config) error {
+	if _, err := installedUnitPath(cfg); err != nil {
+		return err
+	}
+	_, err := systemctl(cfg.User, "stop", cfg.Name+".service")
+	return err
+}
+
+// status maps the output of systemctl is-active to a State.
+func status(cfg Config) (State, error) {
+	if _, err := installedUnitPath(cfg); err != nil {
+		return StateUnknown, err
+	}
+	// is-active exits non-zero for inactive units but still prints the state.
+	out, err := systemctl(cfg.User, "is-active", cfg.Name+".service")
+	switch out {
+	case "active", "reloading":
+		return StateRunning, nil
+	case "activating":
+		return StateStarting, nil
+	case "deactivating":
+		return StateStopping, nil
+	case "inactive", "failed":
+		return StateStopped, nil
+	}
+	if err != nil {
+		return StateUnknown, err
+	}
+	return StateUnknown, nil
+}
@@ -0,0 +1,28 @@
+//go:build !windows && !linux && !darwin
+
+package service
+
+// install is not implemented on this platform.
+func install(cfg Config) error {
+	return ErrUnsupported
+}
+
+// uninstall is not implemented on this platform.
+func uninstall(cfg Config) error {
+	return ErrUnsupported
+}
+
+// start is not implemented on this platform.
+func start(cfg Config) error {
+	return ErrUnsupported
+}
+
+// stop is not implemented on this platform.
+func stop(cfg Config) error {
+	return ErrUnsupported
+}
+
+// status is not implemented on this platform.
+func status(cfg Config) (State, error) {
+	return StateUnknown, ErrUnsupported
+}
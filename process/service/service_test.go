@@ -0,0 +1,122 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/grokify/oscompat/process/service"
+)
+
+func TestInvalidName(t *testing.T) {
+	names := []string{"", "-leading", "has space", "slash/name", `back\slash`, strings.Repeat("a", 257)}
+	for _, name := range names {
+		cfg := service.Config{Name: name}
+		if err := service.Install(cfg); err != service.ErrInvalidName {
+			t.Errorf("Install(%q) error = %v, want ErrInvalidName", name, err)
+		}
+		if _, err := service.Status(cfg); err != service.ErrInvalidName {
+			t.Errorf("Status(%q) error = %v, want ErrInvalidName", name, err)
+		}
+	}
+}
+
+func TestStatusNotInstalled(t *testing.T) {
+	cfg := service.Config{Name: "oscompat-test-not-installed", User: true}
+	_, err := service.Status(cfg)
+	if errors.Is(err, errors.ErrUnsupported) {
+		t.Skip("services not supported on this platform")
+	}
+	if err != service.ErrNotInstalled {
+		t.Errorf("Status() error = %v, want ErrNotInstalled", err)
+	}
+}
+
+func TestSystemdUnit(t *testing.T) {
+	cfg := service.Config{
+		Name:        "myapp",
+		Description: "My app at 100%",
+		Executable:  "/opt/my app/bin/myapp",
+		Args:        []string{"serve", "--addr=:8080", "$HOME"},
+		Dir:         "/var/lib/my app$1",
+		Env:         []string{"MODE=prod", `GREETING=say "hi" for $5`},
+	}
+	unit := cfg.SystemdUnit()
+
+	wantLines := []string{
+		"Description=My app at 100%%",
+		`ExecStart="/opt/my app/bin/myapp" serve --addr=:8080 $$HOME`,
+		"WorkingDirectory=/var/lib/my app$1",
+		`Environment="MODE=prod"`,
+		`Environment="GREETING=say \"hi\" for $5"`,
+		"WantedBy=multi-user.target",
+	}
+	for _, want := range wantLines {
+		if !strings.Contains(unit, want+"\n") {
+			t.Errorf("SystemdUnit() missing %q:\n%s", want, unit)
+		}
+	}
+
+	cfg.User = true
+	if unit := cfg.SystemdUnit(); !strings.Contains(unit, "WantedBy=default.target\n") {
+		t.Errorf("user SystemdUnit() missing default.target:\n%s", unit)
+	}
+}
+
+func TestLaunchdPlist(t *testing.T) {
+	cfg := service.Config{
+		Name:       "com.example.myapp",
+		Executable: "/usr/local/bin/myapp",
+		Args:       []string{"--tag=<a&b>"},
+		Env:        []string{"MODE=prod"},
+	}
+	plist := cfg.LaunchdPlist()
+
+	wants := []string{
+		"<key>Label</key>",
+		"<string>com.example.myapp</string>",
+		"<string>/usr/local/bin/myapp</string>",
+		"<string>--tag=&lt;a&amp;b&gt;</string>",
+		"<key>MODE</key>",
+		"<string>prod</string>",
+		"<key>RunAtLoad</key>",
+	}
+	for _, want := range wants {
+		if !strings.Contains(plist, want) {
+			t.Errorf("LaunchdPlist() missing %q:\n%s", want, plist)
+		}
+	}
+}
+
+func TestStateString(t *testing.T) {
+	tests := []struct {
+		state service.State
+		want  string
+	}{
+		{service.StateUnknown, "unknown"},
+		{service.StateStopped, "stopped"},
+		{service.StateStarting, "starting"},
+		{service.StateRunning, "running"},
+		{service.StateStopping, "stopping"},
+		{service.State(99), "State(99)"},
+	}
+	for _, tt := range tests {
+		if got := tt.state.String(); got != tt.want {
+			t.Errorf("State(%d).String() = %q, want %q", tt.state, got, tt.want)
+		}
+	}
+}
+
+func TestRunInteractive(t *testing.T) {
+	wantErr := errors.New("handler failed")
+	err := service.Run(service.Config{Name: "oscompat-test"}, func(ctx context.Context) error {
+		if ctx.Err() != nil {
+			t.Error("context canceled before handler ran")
+		}
+		return wantErr
+	})
+	if err != wantErr {
+		t.Errorf("Run() error = %v, want %v", err, wantErr)
+	}
+}
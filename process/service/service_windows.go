@@ -0,0 +1,400 @@
+//go:build windows
+
+package service
+
+import (
+	"context"
+	"os"
+	"sync"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	advapi32                          = syscall.NewLazyDLL("advapi32.dll")
+	procOpenSCManagerW                = advapi32.NewProc("OpenSCManagerW")
+	procCreateServiceW                = advapi32.NewProc("CreateServiceW")
+	procOpenServiceW                  = advapi32.NewProc("OpenServiceW")
+	procDeleteService                 = advapi32.NewProc("DeleteService")
+	procCloseServiceHandle            = advapi32.NewProc("CloseServiceHandle")
+	procStartServiceW                 = advapi32.NewProc("StartServiceW")
+	procControlService                = advapi32.NewProc("ControlService")
+	procQueryServiceStatus            = advapi32.NewProc("QueryServiceStatus")
+	procChangeServiceConfig2W         = advapi32.NewProc("ChangeServiceConfig2W")
+	procStartServiceCtrlDispatcherW   = advapi32.NewProc("StartServiceCtrlDispatcherW")
+	procRegisterServiceCtrlHandlerExW = advapi32.NewProc("RegisterServiceCtrlHandlerExW")
+	procSetServiceStatus              = advapi32.NewProc("SetServiceStatus")
+	procRegSetValueExW                = advapi32.NewProc("RegSetValueExW")
+)
+
+const (
+	scManagerConnect       = 0x0001
+	scManagerCreateService = 0x0002
+
+	serviceQueryStatus = 0x0004
+	serviceStart       = 0x0010
+	serviceStop        = 0x0020
+	serviceAllAccess   = 0xF01FF
+	deleteAccess       = 0x00010000
+
+	serviceWin32OwnProcess   = 0x00000010
+	serviceAutoStart         = 0x00000002
+	serviceErrorNormal       = 0x00000001
+	serviceConfigDescription = 1
+
+	serviceControlStop        = 1
+	serviceControlInterrogate = 4
+	serviceControlShutdown    = 5
+	serviceAcceptStop         = 0x1
+	serviceAcceptShutdown     = 0x4
+
+	serviceStopped         = 1
+	serviceStartPending    = 2
+	serviceStopPending     = 3
+	serviceRunning         = 4
+	serviceContinuePending = 5
+	servicePausePending    = 6
+	servicePaused          = 7
+
+	errorServiceSpecificError           = 1066
+	errorServiceDoesNotExist            = syscall.Errno(1060)
+	errorServiceAlreadyRunning          = syscall.Errno(1056)
+	errorServiceNotActive               = syscall.Errno(1062)
+	errorServiceExists                  = syscall.Errno(1073)
+	errorFailedServiceControllerConnect = syscall.Errno(1063)
+	errorCallNotImplemented             = 120
+
+	regMultiSZ = 7
+)
+
+// serviceStatus mirrors SERVICE_STATUS.
+type serviceStatus struct {
+	ServiceType             uint32
+	CurrentState            uint32
+	ControlsAccepted        uint32
+	Win32ExitCode           uint32
+	ServiceSpecificExitCode uint32
+	CheckPoint              uint32
+	WaitHint                uint32
+}
+
+// serviceDescription mirrors SERVICE_DESCRIPTIONW.
+type serviceDescription struct {
+	Description *uint16
+}
+
+// serviceTableEntry mirrors SERVICE_TABLE_ENTRYW.
+type serviceTableEntry struct {
+	Name *uint16
+	Proc uintptr
+}
+
+// openSCManager connects to the local service control manager.
+func openSCManager(access uint32) (syscall.Handle, error) {
+	r, _, err := procOpenSCManagerW.Call(0, 0, uintptr(access))
+	if r == 0 {
+		return 0, err
+	}
+	return syscall.Handle(r), nil
+}
+
+// closeServiceHandle closes an SCM or service handle.
+func closeServiceHandle(h syscall.Handle) {
+	_, _, _ = procCloseServiceHandle.Call(uintptr(h))
+}
+
+// openService opens the named service, returning ErrNotInstalled if it
+// does not exist. The caller closes both returned handles.
+func openService(name string, access uint32) (scm, svc syscall.Handle, err error) {
+	namePtr, err := syscall.UTF16PtrFromString(name)
+	if err != nil {
+		return 0, 0, err
+	}
+	scm, err = openSCManager(scManagerConnect)
+	if err != nil {
+		return 0, 0, err
+	}
+	r, _, err := procOpenServiceW.Call(uintptr(scm), uintptr(unsafe.Pointer(namePtr)), uintptr(access))
+	if r == 0 {
+		closeServiceHandle(scm)
+		if err == errorServiceDoesNotExist {
+			return 0, 0, ErrNotInstalled
+		}
+		return 0, 0, err
+	}
+	return scm, syscall.Handle(r), nil
+}
+
+// install creates the service with CreateServiceW.
+func install(cfg Config) error {
+	if cfg.User {
+		return ErrUnsupported
+	}
+
+	cmdline := syscall.EscapeArg(cfg.Executable)
+	for _, arg := range cfg.Args {
+		cmdline += " " + syscall.EscapeArg(arg)
+	}
+	name, err := syscall.UTF16PtrFromString(cfg.Name)
+	if err != nil {
+		return err
+	}
+	display, err := syscall.UTF16PtrFromString(cfg.DisplayName)
+	if err != nil {
+		return err
+	}
+	binPath, err := syscall.UTF16PtrFromString(cmdline)
+	if err != nil {
+		return err
+	}
+
+	scm, err := openSCManager(scManagerConnect | scManagerCreateService)
+	if err != nil {
+		return err
+	}
+	defer closeServiceHandle(scm)
+
+	r, _, err := procCreateServiceW.Call(uintptr(scm), uintptr(unsafe.Pointer(name)), uintptr(unsafe.Pointer(display)),
+		serviceAllAccess, serviceWin32OwnProcess, serviceAutoStart, serviceErrorNormal,
+		uintptr(unsafe.Pointer(binPath)), 0, 0, 0, 0, 0)
+	if r == 0 {
+		if err == errorServiceExists {
+			return ErrAlreadyInstalled
+		}
+		return err
+	}
+	svc := syscall.Handle(r)
+	defer closeServiceHandle(svc)
+
+	if cfg.Description != "" {
+		desc, err := syscall.UTF16PtrFromString(cfg.Description)
+		if err != nil {
+			return err
+		}
+		sd := serviceDescription{Description: desc}
+		if r, _, err := procChangeServiceConfig2W.Call(uintptr(svc), serviceConfigDescription, uintptr(unsafe.Pointer(&sd))); r == 0 {
+			return err
+		}
+	}
+	if len(cfg.Env) > 0 {
+		return setServiceEnv(cfg.Name, cfg.Env)
+	}
+	return nil
+}
+
+// setServiceEnv stores env in the service's Environment registry value,
+// which the SCM merges into the service's environment at start.
+func setServiceEnv(name string, env []string) error {
+	path, err := syscall.UTF16PtrFromString(`SYSTEM\CurrentControlSet\Services\` + name)
+	if err != nil {
+		return err
+	}
+	var key syscall.Handle
+	if err := syscall.RegOpenKeyEx(syscall.HKEY_LOCAL_MACHINE, path, 0, syscall.KEY_SET_VALUE, &key); err != nil {
+		return err
+	}
+	defer func() { _ = syscall.RegCloseKey(key) }()
+
+	// REG_MULTI_SZ: NUL-terminated strings followed by an extra NUL.
+	var data []uint16
+	for _, kv := range env {
+		s, err := syscall.UTF16FromString(kv)
+		if err != nil {
+			return err
+		}
+		data = append(data, s...)
+	}
+	data = append(data, 0)
+	valueName, _ := syscall.UTF16PtrFromString("Environment")
+	r, _, _ := procRegSetValueExW.Call(uintptr(key), uintptr(unsafe.Pointer(valueName)), 0, regMultiSZ,
+		uintptr(unsafe.Pointer(&data[0])), uintptr(len(data)*2))
+	if r != 0 {
+		return syscall.Errno(r)
+	}
+	return nil
+}
+
+// uninstall stops and deletes the service.
+func uninstall(cfg Config) error {
+	scm, svc, err := openService(cfg.Name, deleteAccess|serviceStop|serviceQueryStatus)
+	if err != nil {
+		return err
+	}
+	defer closeServiceHandle(scm)
+	defer closeServiceHandle(svc)
+
+	var st serviceStatus
+	_, _, _ = procControlService.Call(uintptr(svc), serviceControlStop, uintptr(unsafe.Pointer(&st)))
+	if r, _, err := procDeleteService.Call(uintptr(svc)); r == 0 {
+		return err
+	}
+	return nil
+}
+
+// start starts the service.
+func start(cfg Config) error {
+	scm, svc, err := openService(cfg.Name, serviceStart)
+	if err != nil {
+		return err
+	}
+	defer closeServiceHandle(scm)
+	defer closeServiceHandle(svc)
+
+	r, _, err := procStartServiceW.Call(uintptr(svc), 0, 0)
+	if r == 0 && err != errorServiceAlreadyRunning {
+		return err
+	}
+	return nil
+}
+
+// stop sends SERVICE_CONTROL_STOP to the service.
+func stop(cfg Config) error {
+	scm, svc, err := openService(cfg.Name, serviceStop)
+	if err != nil {
+		return err
+	}
+	defer closeServiceHandle(scm)
+	defer closeServiceHandle(svc)
+
+	var st serviceStatus
+	r, _, err := procControlService.Call(uintptr(svc), serviceControlStop, uintptr(unsafe.Pointer(&st)))
+	if r == 0 && err != errorServiceNotActive {
+		return err
+	}
+	return nil
+}
+
+// status queries the service's current state.
+func status(cfg Config) (State, error) {
+	scm, svc, err := openService(cfg.Name, serviceQueryStatus)
+	if err != nil {
+		return StateUnknown, err
+	}
+	defer closeServiceHandle(scm)
+	defer closeServiceHandle(svc)
+
+	var st serviceStatus
+	if r, _, err := procQueryServiceStatus.Call(uintptr(svc), uintptr(unsafe.Pointer(&st))); r == 0 {
+		return StateUnknown, err
+	}
+	switch st.CurrentState {
+	case serviceStopped:
+		return StateStopped, nil
+	case serviceStartPending, serviceContinuePending:
+		return StateStarting, nil
+	case serviceStopPending, servicePausePending:
+		return StateStopping, nil
+	case serviceRunning, servicePaused:
+		return StateRunning, nil
+	default:
+		return StateUnknown, nil
+	}
+}
+
+// scmRun holds the state shared between run and the callbacks invoked by
+// the SCM on its own threads. Only one service can run per process.
+var scmRun struct {
+	cfg     Config
+	handler Handler
+	status  uintptr // SERVICE_STATUS_HANDLE
+	cancel  context.CancelFunc
+	err     error
+}
+
+// Callbacks are created once; Windows limits how many can exist.
+var (
+	callbacksOnce   sync.Once
+	serviceMainCb   uintptr
+	ctrlHandlerExCb uintptr
+)
+
+// run connects to the SCM, falling back to running interactively if the
+// program was not started as a service.
+func run(cfg Config, h Handler) error {
+	name, err := syscall.UTF16PtrFromString(cfg.Name)
+	if err != nil {
+		return err
+	}
+	callbacksOnce.Do(func() {
+		serviceMainCb = syscall.NewCallback(serviceMain)
+		ctrlHandlerExCb = syscall.NewCallback(ctrlHandlerEx)
+	})
+	scmRun.cfg = cfg
+	scmRun.handler = h
+
+	table := [2]serviceTableEntry{{Name: name, Proc: serviceMainCb}, {}}
+	r, _, err := procStartServiceCtrlDispatcherW.Call(uintptr(unsafe.Pointer(&table[0])))
+	if r == 0 {
+		if err == errorFailedServiceControllerConnect {
+			return runInteractive(h)
+		}
+		return err
+	}
+	return scmRun.err
+}
+
+// setStatus reports the service state to the SCM.
+func setStatus(state, exitCode uint32) {
+	st := serviceStatus{
+		ServiceType:  serviceWin32OwnProcess,
+		CurrentState: state,
+	}
+	if state == serviceRunning {
+		st.ControlsAccepted = serviceAcceptStop | serviceAcceptShutdown
+	}
+	if state == serviceStartPending || state == serviceStopPending {
+		st.WaitHint = 10000
+	}
+	if exitCode != 0 {
+		st.Win32ExitCode = errorServiceSpecificError
+		st.ServiceSpecificExitCode = exitCode
+	}
+	_, _, _ = procSetServiceStatus.Call(scmRun.status, uintptr(unsafe.Pointer(&st)))
+}
+
+// serviceMain is the ServiceMain callback. It runs the handler and reports
+// the service as stopped when the handler returns.
+func serviceMain(argc, argv uintptr) uintptr {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	scmRun.cancel = cancel
+
+	name, _ := syscall.UTF16PtrFromString(scmRun.cfg.Name)
+	r, _, err := procRegisterServiceCtrlHandlerExW.Call(uintptr(unsafe.Pointer(name)), ctrlHandlerExCb, 0)
+	if r == 0 {
+		scmRun.err = err
+		return 0
+	}
+	scmRun.status = r
+
+	setStatus(serviceStartPending, 0)
+
+	// Services start in the system directory; honor the configured one.
+	if scmRun.cfg.Dir != "" {
+		_ = os.Chdir(scmRun.cfg.Dir)
+	}
+
+	setStatus(serviceRunning, 0)
+	scmRun.err = scmRun.handler(ctx)
+
+	var exitCode uint32
+	if scmRun.err != nil {
+		exitCode = 1
+	}
+	setStatus(serviceStopped, exitCode)
+	return 0
+}
+
+// ctrlHandlerEx is the HandlerEx callback for SCM control requests.
+func ctrlHandlerEx(ctrl, eventType, eventData, context uintptr) uintptr {
+	switch ctrl {
+	case serviceControlStop, serviceControlShutdown:
+		setStatus(serviceStopPending, 0)
+		scmRun.cancel()
+		return 0
+	case serviceControlInterrogate:
+		return 0
+	default:
+		return errorCallNotImplemented
+	}
+}
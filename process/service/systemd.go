@@ -0,0 +1,81 @@
+package service
+
+import (
+	"strings"
+)
+
+// SystemdUnit returns the systemd unit file that Install writes on Linux.
+// It is exported so that packagers can ship the unit themselves. Dir
+// must not contain a line break; Install rejects one.
+func (cfg Config) SystemdUnit() string {
+	description := cfg.Description
+	if description == "" {
+		description = cfg.DisplayName
+	}
+	if description == "" {
+		description = cfg.Name
+	}
+
+	var b strings.Builder
+	b.WriteString("[Unit]\n")
+	b.WriteString("Description=" + systemdEscape(description) + "\n")
+	if !cfg.User {
+		b.WriteString("After=network.target\n")
+	}
+
+	b.WriteString("\n[Service]\n")
+	b.WriteString("Type=simple\n")
+	b.WriteString("ExecStart=" + systemdExecQuote(cfg.Executable))
+	for _, arg := range cfg.Args {
+		b.WriteString(" " + systemdExecQuote(arg))
+	}
+	b.WriteString("\n")
+	if cfg.Dir != "" {
+		// WorkingDirectory= takes the rest of the line as the path, with
+		// no unquoting or variable expansion.
+		b.WriteString("WorkingDirectory=" + strings.ReplaceAll(cfg.Dir, "%", "%%") + "\n")
+	}
+	for _, kv := range cfg.Env {
+		b.WriteString("Environment=" + systemdEnvQuote(kv) + "\n")
+	}
+	b.WriteString("Restart=on-failure\n")
+
+	b.WriteString("\n[Install]\n")
+	if cfg.User {
+		b.WriteString("WantedBy=default.target\n")
+	} else {
+		b.WriteString("WantedBy=multi-user.target\n")
+	}
+	return b.String()
+}
+
+// systemdEscape escapes the specifiers systemd expands in unit values
+// and folds line breaks.
+func systemdEscape(s string) string {
+	s = strings.ReplaceAll(s, "%", "%%")
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+// systemdExecQuote escapes s as a word of ExecStart=, which also expands
+// $VAR, and wraps it in double quotes if systemd would otherwise split or
+// unescape it.
+func systemdExecQuote(s string) string {
+	s = strings.ReplaceAll(systemdEscape(s), "$", "$$")
+	if s != "" && !strings.ContainsAny(s, " \t\"'\\;") {
+		return s
+	}
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
+
+// systemdEnvQuote quotes a KEY=value assignment for Environment=, which
+// splits on spaces and unescapes C-style escapes but expands no
+// variables.
+func systemdEnvQuote(kv string) string {
+	kv = strings.ReplaceAll(kv, "%", "%%")
+	kv = strings.ReplaceAll(kv, `\`, `\\`)
+	kv = strings.ReplaceAll(kv, `"`, `\"`)
+	kv = strings.ReplaceAll(kv, "\n", `\n`)
+	return `"` + kv + `"`
+}
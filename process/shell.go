@@ -0,0 +1,16 @@
+package process
+
+import "os/exec"
+
+// ShellCommand returns a command that runs cmdline with the platform
+// shell: "/bin/sh -c cmdline" on Unix and "cmd.exe /d /s /c "cmdline""
+// on Windows, using %ComSpec% when it is set.
+//
+// cmdline is passed to the shell verbatim. On Windows it bypasses the
+// argument escaping os/exec applies, which would otherwise mangle quotes
+// inside the line. Use QuoteShellArgs to build cmdline from untrusted
+// values; QuoteArgs does not escape cmd.exe metacharacters and is not
+// safe for it.
+func ShellCommand(cmdline string) *exec.Cmd {
+	return shellCommand(cmdline)
+}
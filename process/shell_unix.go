@@ -0,0 +1,10 @@
+//go:build !windows
+
+package process
+
+import "os/exec"
+
+// shellCommand runs cmdline with /bin/sh.
+func shellCommand(cmdline string) *exec.Cmd {
+	return exec.Command("/bin/sh", "-c", cmdline)
+}
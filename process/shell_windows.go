@@ -0,0 +1,24 @@
+//go:build windows
+
+package process
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// shellCommand runs cmdline with cmd.exe. The command line is set
+// directly because cmd.exe does not parse its arguments with
+// CommandLineToArgvW; /s makes it strip exactly the outer quotes.
+func shellCommand(cmdline string) *exec.Cmd {
+	shell := os.Getenv("ComSpec")
+	if shell == "" {
+		shell = `C:\Windows\System32\cmd.exe`
+	}
+	cmd := exec.Command(shell)
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		CmdLine: QuoteWindows(shell) + ` /d /s /c "` + cmdline + `"`,
+	}
+	return cmd
+}
@@ -0,0 +1,64 @@
+package process
+
+import (
+	"context"
+	"os"
+	"os/signal"
+)
+
+// SignalError is the cancellation cause of a context returned by
+// NotifyShutdown. Retrieve it with context.Cause.
+type SignalError struct {
+	Signal os.Signal
+}
+
+// Error implements the error interface.
+func (e *SignalError) Error() string {
+	return "oscompat/process: received " + e.Signal.String()
+}
+
+// NotifyShutdown returns a copy of ctx that is canceled when the process
+// is asked to shut down, giving main a single portable graceful-shutdown
+// hook. Once the context is canceled, the default signal behavior is
+// restored so that a second Ctrl+C terminates the process immediately.
+// Call stop to release resources once the context is no longer needed.
+//
+// Platform behavior:
+//   - Unix: SIGINT, SIGTERM, or SIGHUP. SIGHUP is ignored while a
+//     NotifyReload channel is active.
+//   - Windows: CTRL_C_EVENT and CTRL_BREAK_EVENT (delivered by Go as
+//     os.Interrupt) and CTRL_CLOSE_EVENT, CTRL_LOGOFF_EVENT, and
+//     CTRL_SHUTDOWN_EVENT (delivered as syscall.SIGTERM). Windows services
+//     receive stop requests from the service control manager rather than
+//     as console events; their handler should cancel the context itself.
+//
+// The cause of the cancellation, available through context.Cause, is a
+// *SignalError naming the signal received.
+//
+// Example:
+//
+//	ctx, stop := process.NotifyShutdown(context.Background())
+//	defer stop()
+//	<-ctx.Done()
+func NotifyShutdown(ctx context.Context) (_ context.Context, stop context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	ch := make(chan os.Signal, 1)
+	signal.Notify(ch, shutdownSignals...)
+
+	go func() {
+		defer signal.Stop(ch)
+		for {
+			select {
+			case sig := <-ch:
+				if ignoreShutdownSignal(sig) {
+					continue
+				}
+				cancel(&SignalError{Signal: sig})
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ctx, func() { cancel(context.Canceled) }
+}
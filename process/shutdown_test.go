@@ -0,0 +1,50 @@
+package process_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"runtime"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/grokify/oscompat/process"
+)
+
+func TestNotifyShutdown(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sending a signal to self")
+	}
+
+	ctx, stop := process.NotifyShutdown(context.Background())
+	defer stop()
+
+	self, _ := os.FindProcess(os.Getpid())
+	if err := self.Signal(syscall.SIGTERM); err != nil {
+		t.Fatalf("Signal() error: %v", err)
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("context not canceled after SIGTERM")
+	}
+
+	var sigErr *process.SignalError
+	if !errors.As(context.Cause(ctx), &sigErr) || sigErr.Signal != syscall.SIGTERM {
+		t.Errorf("Cause() = %v, want SIGTERM", context.Cause(ctx))
+	}
+}
+
+func TestNotifyShutdownStop(t *testing.T) {
+	ctx, stop := process.NotifyShutdown(context.Background())
+	stop()
+
+	if ctx.Err() != context.Canceled {
+		t.Errorf("Err() = %v, want context.Canceled", ctx.Err())
+	}
+	if context.Cause(ctx) != context.Canceled {
+		t.Errorf("Cause() = %v, want context.Canceled", context.Cause(ctx))
+	}
+}
@@ -0,0 +1,17 @@
+//go:build !windows
+
+package process
+
+import (
+	"os"
+	"syscall"
+)
+
+// shutdownSignals are the signals that request a graceful shutdown.
+var shutdownSignals = []os.Signal{syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP}
+
+// ignoreShutdownSignal reports whether sig should not trigger shutdown.
+// SIGHUP is claimed by NotifyReload while it is in use.
+func ignoreShutdownSignal(sig os.Signal) bool {
+	return sig == syscall.SIGHUP && reloadListeners.Load() > 0
+}
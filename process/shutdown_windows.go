@@ -0,0 +1,17 @@
+//go:build windows
+
+package process
+
+import (
+	"os"
+	"syscall"
+)
+
+// shutdownSignals are the console events, as mapped to signals by the Go
+// runtime, that request a graceful shutdown.
+var shutdownSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+// ignoreShutdownSignal reports whether sig should not trigger shutdown.
+func ignoreShutdownSignal(sig os.Signal) bool {
+	return false
+}
@@ -0,0 +1,183 @@
+package process
+
+import (
+	"errors"
+	"strings"
+)
+
+// Syntax selects the rules SplitCommandLine uses.
+type Syntax int
+
+// Command line syntaxes.
+const (
+	// SyntaxPOSIX splits like a POSIX shell: words are separated by
+	// blanks, and single quotes, double quotes, and backslashes quote as
+	// in sh. A # at the start of a word begins a comment. Nothing is
+	// expanded: $VAR, globs, and operators such as | and ; are ordinary
+	// characters.
+	SyntaxPOSIX Syntax = iota
+
+	// SyntaxWindows splits like CommandLineToArgvW and the Go and C
+	// runtimes on Windows: backslashes are literal unless they precede a
+	// double quote, and the first word (the program) ends at the first
+	// blank outside quotes with no escape processing.
+	SyntaxWindows
+)
+
+// ErrUnterminatedQuote is returned by SplitCommandLine when a POSIX
+// command line ends inside quotes or with an escaping backslash.
+var ErrUnterminatedQuote = errors.New("oscompat/process: unterminated quote or escape")
+
+// SplitCommandLine splits s into arguments using the given syntax, without
+// running a shell. It is the inverse of QuotePOSIX and QuoteWindows, so a
+// "command" string from a configuration file yields the same argv on every
+// OS as long as its syntax is fixed.
+func SplitCommandLine(s string, syntax Syntax) ([]string, error) {
+	if syntax == SyntaxWindows {
+		return splitWindows(s), nil
+	}
+	return splitPOSIX(s)
+}
+
+// splitPOSIX implements SyntaxPOSIX.
+func splitPOSIX(s string) ([]string, error) {
+	var (
+		args   []string
+		word   strings.Builder
+		inWord bool
+	)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n':
+			if inWord {
+				args = append(args, word.String())
+				word.Reset()
+				inWord = false
+			}
+		case c == '#' && !inWord:
+			// Comment to end of line.
+			for i < len(s) && s[i] != '\n' {
+				i++
+			}
+		case c == '\\':
+			if i+1 == len(s) {
+				return nil, ErrUnterminatedQuote
+			}
+			i++
+			if s[i] == '\n' {
+				continue // line continuation
+			}
+			word.WriteByte(s[i])
+			inWord = true
+		case c == '\'':
+			end := strings.IndexByte(s[i+1:], '\'')
+			if end < 0 {
+				return nil, ErrUnterminatedQuote
+			}
+			word.WriteString(s[i+1 : i+1+end])
+			i += 1 + end
+			inWord = true
+		case c == '"':
+			i++
+			for ; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' && i+1 < len(s) && strings.IndexByte("$`\"\\\n", s[i+1]) >= 0 {
+					i++
+					if s[i] == '\n' {
+						continue
+					}
+				}
+				word.WriteByte(s[i])
+			}
+			if i == len(s) {
+				return nil, ErrUnterminatedQuote
+			}
+			inWord = true
+		default:
+			word.WriteByte(c)
+			inWord = true
+		}
+	}
+	if inWord {
+		args = append(args, word.String())
+	}
+	return args, nil
+}
+
+// splitWindows implements SyntaxWindows. It follows the same rules as
+// CommandLineToArgvW, including the pre-2008 handling of "" inside quotes.
+func splitWindows(s string) []string {
+	s = strings.TrimLeft(s, " \t")
+	if s == "" {
+		return nil
+	}
+
+	// The program name: quotes delimit it, backslashes are literal.
+	var args []string
+	if s[0] == '"' {
+		end := strings.IndexByte(s[1:], '"')
+		if end < 0 {
+			return []string{s[1:]}
+		}
+		args = append(args, s[1:1+end])
+		s = s[2+end:]
+	} else {
+		end := strings.IndexAny(s, " \t")
+		if end < 0 {
+			return []string{s}
+		}
+		args = append(args, s[:end])
+		s = s[end:]
+	}
+
+	for {
+		s = strings.TrimLeft(s, " \t")
+		if s == "" {
+			return args
+		}
+		var arg string
+		arg, s = nextWindowsArg(s)
+		args = append(args, arg)
+	}
+}
+
+// nextWindowsArg reads one argument after the program name and returns
+// it with the rest of the command line.
+func nextWindowsArg(s string) (arg, rest string) {
+	var (
+		b       strings.Builder
+		inQuote bool
+		slashes int
+	)
+	for ; len(s) > 0; s = s[1:] {
+		c := s[0]
+		switch c {
+		case ' ', '\t':
+			if !inQuote {
+				b.WriteString(strings.Repeat(`\`, slashes))
+				return b.String(), s[1:]
+			}
+		case '\\':
+			slashes++
+			continue
+		case '"':
+			b.WriteString(strings.Repeat(`\`, slashes/2))
+			if slashes%2 == 1 {
+				b.WriteByte('"')
+			} else {
+				if inQuote && len(s) > 1 && s[1] == '"' {
+					b.WriteByte('"')
+					s = s[1:]
+				}
+				inQuote = !inQuote
+			}
+			slashes = 0
+			continue
+		}
+		b.WriteString(strings.Repeat(`\`, slashes))
+		slashes = 0
+		b.WriteByte(c)
+	}
+	b.WriteString(strings.Repeat(`\`, slashes))
+	return b.String(), ""
+}
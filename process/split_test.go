@@ -0,0 +1,85 @@
+package process_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/grokify/oscompat/process"
+)
+
+func TestSplitCommandLinePOSIX(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", nil},
+		{"  prog  a\tb\n", []string{"prog", "a", "b"}},
+		{`prog 'a b' "c d"`, []string{"prog", "a b", "c d"}},
+		{`prog '' ""`, []string{"prog", "", ""}},
+		{`prog a\ b`, []string{"prog", "a b"}},
+		{`prog "a \"b\" \$x \y"`, []string{"prog", `a "b" $x \y`}},
+		{`prog 'it'\''s'`, []string{"prog", "it's"}},
+		{"prog a \\\n b", []string{"prog", "a", "b"}},
+		{"prog a # comment\nb", []string{"prog", "a", "b"}},
+		{"prog a#b $HOME *.go |", []string{"prog", "a#b", "$HOME", "*.go", "|"}},
+	}
+	for _, tt := range tests {
+		got, err := process.SplitCommandLine(tt.in, process.SyntaxPOSIX)
+		if err != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("SplitCommandLine(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestSplitCommandLinePOSIXErrors(t *testing.T) {
+	for _, in := range []string{`prog 'a`, `prog "a`, `prog a\`} {
+		if _, err := process.SplitCommandLine(in, process.SyntaxPOSIX); !errors.Is(err, process.ErrUnterminatedQuote) {
+			t.Errorf("SplitCommandLine(%q) error = %v, want ErrUnterminatedQuote", in, err)
+		}
+	}
+}
+
+func TestSplitCommandLineWindows(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", nil},
+		{`prog a b`, []string{"prog", "a", "b"}},
+		{`"C:\Program Files\app.exe" x`, []string{`C:\Program Files\app.exe`, "x"}},
+		{`C:\dir\prog.exe "a b" c\d`, []string{`C:\dir\prog.exe`, "a b", `c\d`}},
+		{`prog a\\\"b`, []string{"prog", `a\"b`}},
+		{`prog "a\\" b`, []string{"prog", `a\`, "b"}},
+		{`prog "a""b c`, []string{"prog", `a"b`, "c"}},
+		{`prog ""`, []string{"prog", ""}},
+		{`prog a"b c"d`, []string{"prog", "ab cd"}},
+	}
+	for _, tt := range tests {
+		got, err := process.SplitCommandLine(tt.in, process.SyntaxWindows)
+		if err != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("SplitCommandLine(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestSplitCommandLineRoundTrip(t *testing.T) {
+	args := []string{"prog", "", "plain", "two words", `back\slash`, `"quoted"`, "it's", `trail\`, `a\"b`}
+
+	posix := ""
+	windows := "prog"
+	for i, arg := range args {
+		if i > 0 {
+			posix += " "
+			windows += " " + process.QuoteWindows(arg)
+		}
+		posix += process.QuotePOSIX(arg)
+	}
+
+	if got, err := process.SplitCommandLine(posix, process.SyntaxPOSIX); err != nil || !slices.Equal(got, args) {
+		t.Errorf("POSIX round trip = %q, %v; want %q", got, err, args)
+	}
+	if got, err := process.SplitCommandLine(windows, process.SyntaxWindows); err != nil || !slices.Equal(got, args) {
+		t.Errorf("Windows round trip = %q, %v; want %q", got, err, args)
+	}
+}
@@ -0,0 +1,17 @@
+//go:build linux
+
+package process
+
+import "syscall"
+
+// prSetChildSubreaper is PR_SET_CHILD_SUBREAPER from <linux/prctl.h>.
+const prSetChildSubreaper = 36
+
+// setSubreaper sets PR_SET_CHILD_SUBREAPER on the current process.
+func setSubreaper() error {
+	_, _, errno := syscall.RawSyscall(syscall.SYS_PRCTL, prSetChildSubreaper, 1, 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package process
+
+// setSubreaper is not supported on this platform.
+func setSubreaper() error {
+	return ErrUnsupported
+}
@@ -0,0 +1,307 @@
+package process
+
+import (
+	"context"
+	"os/exec"
+	"time"
+
+	"github.com/grokify/oscompat/localnet"
+)
+
+// Supervisor defaults.
+const (
+	DefaultMinBackoff     = 100 * time.Millisecond
+	DefaultMaxBackoff     = 30 * time.Second
+	DefaultStopTimeout    = 10 * time.Second
+	DefaultHealthInterval = 5 * time.Second
+	DefaultHealthFailures = 3
+)
+
+// RestartPolicy controls when a supervised child is restarted.
+type RestartPolicy int
+
+// Restart policies.
+const (
+	// RestartOnFailure restarts the child when it exits with an error or
+	// fails its health probe. This is the default.
+	RestartOnFailure RestartPolicy = iota
+
+	// RestartAlways restarts the child whenever it exits.
+	RestartAlways
+
+	// RestartNever leaves the child stopped once it exits.
+	RestartNever
+)
+
+// Child describes a process managed by a Supervisor.
+type Child struct {
+	// Name identifies the child in events.
+	Name string
+
+	// Command returns the command to start. It is called for every start
+	// because an exec.Cmd cannot be reused.
+	Command func() *exec.Cmd
+
+	// Restart is the restart policy.
+	Restart RestartPolicy
+
+	// Health, if set, is called every HealthInterval while the child runs.
+	// After HealthFailures consecutive errors the child is stopped and
+	// treated as failed. See LocalnetProbe.
+	Health func(ctx context.Context) error
+
+	// HealthInterval defaults to DefaultHealthInterval.
+	HealthInterval time.Duration
+
+	// HealthFailures defaults to DefaultHealthFailures.
+	HealthFailures int
+
+	// StopTimeout is the grace period given to the child to exit when it
+	// is stopped, as in Terminate. It defaults to DefaultStopTimeout.
+	StopTimeout time.Duration
+}
+
+// EventKind identifies a supervisor event.
+type EventKind int
+
+// Supervisor events.
+const (
+	// EventStarted is sent after a child starts.
+	EventStarted EventKind = iota
+
+	// EventExited is sent after a child exits; Err holds its exit error.
+	EventExited
+
+	// EventUnhealthy is sent when a child fails its health probe too many
+	// times in a row; Err holds the last probe error.
+	EventUnhealthy
+
+	// EventStartFailed is sent when a child cannot be started.
+	EventStartFailed
+)
+
+// Event reports a change in a supervised child.
+type Event struct {
+	Child string
+	Kind  EventKind
+	PID   int
+	Err   error
+}
+
+// Supervisor runs a set of child processes, restarting them with
+// exponential backoff when they crash and stopping them in reverse start
+// order on shutdown. Children are contained in a Group, so they do not
+// outlive the supervisor.
+type Supervisor struct {
+	// Children are started in order, each once the one before it has
+	// been started, and stopped in reverse order.
+	Children []Child
+
+	// MinBackoff and MaxBackoff bound the delay before a restart. The
+	// delay doubles after each consecutive failure and resets once a child
+	// has run for MaxBackoff. They default to DefaultMinBackoff and
+	// DefaultMaxBackoff.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// OnEvent, if set, is called for every event. It is called from
+	// several goroutines and must not block.
+	OnEvent func(Event)
+}
+
+// Run starts the children and supervises them until ctx is done, then
+// stops them in reverse order and returns. It also returns once every
+// child has exited and none will be restarted.
+func (s *Supervisor) Run(ctx context.Context) error {
+	group, err := NewGroup()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = group.Close() }()
+
+	var stops, dones []chan struct{}
+	for _, c := range s.Children {
+		if ctx.Err() != nil {
+			break
+		}
+		stop, done, started := make(chan struct{}), make(chan struct{}), make(chan struct{})
+		stops, dones = append(stops, stop), append(dones, done)
+		go func() {
+			defer close(done)
+			s.supervise(group, c, stop, started)
+		}()
+		select {
+		case <-started:
+		case <-done:
+		case <-ctx.Done():
+		}
+	}
+
+	allDone := make(chan struct{})
+	go func() {
+		for _, done := range dones {
+			<-done
+		}
+		close(allDone)
+	}()
+
+	select {
+	case <-ctx.Done():
+	case <-allDone:
+		return nil
+	}
+	for i := len(stops) - 1; i >= 0; i-- {
+		close(stops[i])
+		<-dones[i]
+	}
+	return nil
+}
+
+// emit delivers an event to OnEvent.
+func (s *Supervisor) emit(e Event) {
+	if s.OnEvent != nil {
+		s.OnEvent(e)
+	}
+}
+
+// supervise runs one child until it should no longer be restarted or stop
+// is closed. It closes started after the first attempt to start the child.
+func (s *Supervisor) supervise(group *Group, c Child, stop <-chan struct{}, started chan<- struct{}) {
+	minBackoff := durationOr(s.MinBackoff, DefaultMinBackoff)
+	maxBackoff := durationOr(s.MaxBackoff, DefaultMaxBackoff)
+	backoff := minBackoff
+
+	for {
+		startTime := time.Now()
+		failed, stopped := s.runOnce(group, c, stop, func() {
+			if started != nil {
+				close(started)
+				started = nil
+			}
+		})
+		if stopped {
+			return
+		}
+		switch c.Restart {
+		case RestartNever:
+			return
+		case RestartOnFailure:
+			if !failed {
+				return
+			}
+		}
+
+		if time.Since(startTime) >= maxBackoff {
+			backoff = minBackoff
+		}
+		timer := time.NewTimer(backoff)
+		select {
+		case <-stop:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// runOnce starts the child and waits for it to exit, fail its health
+// probe, or be stopped. It calls onStart once the start has been
+// attempted and reported. It reports whether the run counts as a failure and whether
+// the supervisor is stopping.
+func (s *Supervisor) runOnce(group *Group, c Child, stop <-chan struct{}, onStart func()) (failed, stopped bool) {
+	cmd := c.Command()
+	if err := group.Start(cmd); err != nil {
+		s.emit(Event{Child: c.Name, Kind: EventStartFailed, Err: err})
+		onStart()
+		return true, false
+	}
+	pid := cmd.Process.Pid
+	s.emit(Event{Child: c.Name, Kind: EventStarted, PID: pid})
+	onStart()
+
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+
+	healthCtx, cancelHealth := context.WithCancel(context.Background())
+	defer cancelHealth()
+	unhealthy := make(chan error, 1)
+	if c.Health != nil {
+		go probe(healthCtx, c, unhealthy)
+	}
+
+	var err error
+	select {
+	case err = <-exited:
+	case probeErr := <-unhealthy:
+		s.emit(Event{Child: c.Name, Kind: EventUnhealthy, PID: pid, Err: probeErr})
+		err = stopChild(c, pid, exited)
+		failed = true
+	case <-stop:
+		err = stopChild(c, pid, exited)
+		stopped = true
+	}
+	group.Forget(pid)
+	s.emit(Event{Child: c.Name, Kind: EventExited, PID: pid, Err: err})
+	return failed || err != nil, stopped
+}
+
+// stopChild terminates the child and waits for cmd.Wait to return.
+func stopChild(c Child, pid int, exited <-chan error) error {
+	grace := durationOr(c.StopTimeout, DefaultStopTimeout)
+	_, _ = Terminate(context.Background(), pid, grace)
+	return <-exited
+}
+
+// probe calls the child's health check until ctx is done, sending the
+// last error once the failure threshold is reached.
+func probe(ctx context.Context, c Child, unhealthy chan<- error) {
+	interval := durationOr(c.HealthInterval, DefaultHealthInterval)
+	threshold := c.HealthFailures
+	if threshold <= 0 {
+		threshold = DefaultHealthFailures
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	failures := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		probeCtx, cancel := context.WithTimeout(ctx, interval)
+		err := c.Health(probeCtx)
+		cancel()
+		if err == nil {
+			failures = 0
+			continue
+		}
+		if failures++; failures >= threshold {
+			unhealthy <- err
+			return
+		}
+	}
+}
+
+// LocalnetProbe returns a health probe that succeeds when a connection to
+// the localnet endpoint name can be made before the probe's deadline.
+func LocalnetProbe(name string) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		var dc localnet.DialConfig
+		conn, err := dc.DialContext(ctx, name)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+}
+
+// durationOr returns d, or def if d is not positive.
+func durationOr(d, def time.Duration) time.Duration {
+	if d <= 0 {
+		return def
+	}
+	return d
+}
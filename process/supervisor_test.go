@@ -0,0 +1,179 @@
+package process_test
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/grokify/oscompat/process"
+)
+
+// eventLog records supervisor events.
+type eventLog struct {
+	mu     sync.Mutex
+	events []process.Event
+}
+
+func (l *eventLog) add(e process.Event) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, e)
+}
+
+func (l *eventLog) count(child string, kind process.EventKind) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	n := 0
+	for _, e := range l.events {
+		if e.Child == child && e.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+func (l *eventLog) snapshot() []process.Event {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]process.Event(nil), l.events...)
+}
+
+func shellCommand(script string) func() *exec.Cmd {
+	return func() *exec.Cmd { return exec.Command("sh", "-c", script) }
+}
+
+func TestSupervisorRestartOnFailure(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+
+	var log eventLog
+	s := &process.Supervisor{
+		Children: []process.Child{
+			{Name: "crash", Command: shellCommand("exit 1")},
+			{Name: "clean", Command: shellCommand("exit 0")},
+		},
+		MinBackoff: 10 * time.Millisecond,
+		MaxBackoff: 20 * time.Millisecond,
+		OnEvent:    log.add,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	if err := s.Run(ctx); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+
+	if n := log.count("crash", process.EventStarted); n < 3 {
+		t.Errorf("crashing child started %d times, want restarts", n)
+	}
+	if n := log.count("clean", process.EventStarted); n != 1 {
+		t.Errorf("cleanly exiting child started %d times, want 1", n)
+	}
+}
+
+func TestSupervisorRestartNever(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+
+	s := &process.Supervisor{
+		Children: []process.Child{
+			{Name: "once", Command: shellCommand("exit 1"), Restart: process.RestartNever},
+		},
+	}
+	done := make(chan error, 1)
+	go func() { done <- s.Run(context.Background()) }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Run() error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run() did not return after all children exited")
+	}
+}
+
+func TestSupervisorStartStopOrder(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+
+	var log eventLog
+	s := &process.Supervisor{
+		Children: []process.Child{
+			{Name: "first", Command: shellCommand("exec sleep 30")},
+			{Name: "second", Command: shellCommand("exec sleep 30")},
+		},
+		OnEvent: log.add,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.Run(ctx) }()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for log.count("first", process.EventStarted)+log.count("second", process.EventStarted) < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("children did not start")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+
+	var starts, exits []string
+	for _, e := range log.snapshot() {
+		switch e.Kind {
+		case process.EventStarted:
+			starts = append(starts, e.Child)
+		case process.EventExited:
+			exits = append(exits, e.Child)
+		}
+	}
+	if len(starts) != 2 || starts[0] != "first" || starts[1] != "second" {
+		t.Errorf("start order = %v, want [first second]", starts)
+	}
+	if len(exits) != 2 || exits[0] != "second" || exits[1] != "first" {
+		t.Errorf("exit order = %v, want [second first]", exits)
+	}
+}
+
+func TestSupervisorHealth(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+
+	var log eventLog
+	s := &process.Supervisor{
+		Children: []process.Child{{
+			Name:           "sick",
+			Command:        shellCommand("exec sleep 30"),
+			Health:         func(context.Context) error { return errors.New("not ready") },
+			HealthInterval: 10 * time.Millisecond,
+			HealthFailures: 2,
+			StopTimeout:    time.Second,
+		}},
+		MinBackoff: 10 * time.Millisecond,
+		OnEvent:    log.add,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	if err := s.Run(ctx); err != nil {
+		t.Fatalf("Run() error: %v", err)
+	}
+	if n := log.count("sick", process.EventUnhealthy); n == 0 {
+		t.Error("no unhealthy events for failing probe")
+	}
+	if n := log.count("sick", process.EventStarted); n < 2 {
+		t.Errorf("unhealthy child started %d times, want a restart", n)
+	}
+}
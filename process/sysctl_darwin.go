@@ -0,0 +1,188 @@
+//go:build darwin
+
+package process
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// sysctl MIB identifiers from <sys/sysctl.h>.
+const (
+	ctlKern        = 1
+	kernProc       = 14
+	kernProcArgs2  = 49
+	kernProcAll    = 0
+	kernProcPID    = 1
+	kinfoProcSize  = 648
+	maxSysctlRetry = 5
+)
+
+// The structures below mirror struct kinfo_proc from <sys/sysctl.h> on
+// 64-bit macOS. Pointer fields are declared as uintptr.
+
+type timeval struct {
+	Sec  int64
+	Usec int32
+	_    [4]byte
+}
+
+type itimerval struct {
+	Interval timeval
+	Value    timeval
+}
+
+type externProc struct {
+	Starttime timeval
+	Vmspace   uintptr
+	Sigacts   uintptr
+	Flag      int32
+	Stat      int8
+	Pid       int32
+	Oppid     int32
+	Dupfd     int32
+	UserStack uintptr
+	ExitThrd  uintptr
+	Debugger  int32
+	Sigwait   int32
+	Estcpu    uint32
+	Cpticks   int32
+	Pctcpu    uint32
+	Wchan     uintptr
+	Wmesg     uintptr
+	Swtime    uint32
+	Slptime   uint32
+	Realtimer itimerval
+	Rtime     timeval
+	Uticks    uint64
+	Sticks    uint64
+	Iticks    uint64
+	Traceflag int32
+	Tracep    uintptr
+	Siglist   int32
+	Textvp    uintptr
+	Holdcnt   int32
+	Sigmask   uint32
+	Sigignore uint32
+	Sigcatch  uint32
+	Priority  uint8
+	Usrpri    uint8
+	Nice      int8
+	Comm      [17]byte
+	Pgrp      uintptr
+	Addr      uintptr
+	Xstat     uint16
+	Acflag    uint16
+	Ru        uintptr
+}
+
+type pcred struct {
+	Lock   [72]int8
+	Ucred  uintptr
+	Ruid   uint32
+	Svuid  uint32
+	Rgid   uint32
+	Svgid  uint32
+	Refcnt int32
+	_      [4]byte
+}
+
+type ucred struct {
+	Ref     int32
+	UID     uint32
+	Ngroups int16
+	Groups  [16]uint32
+}
+
+type vmspace struct {
+	Dummy  int32
+	Dummy2 uintptr
+	Dummy3 [5]int32
+	Dummy4 [3]uintptr
+}
+
+type eproc struct {
+	Paddr   uintptr
+	Sess    uintptr
+	Pcred   pcred
+	Ucred   ucred
+	Vm      vmspace
+	Ppid    int32
+	Pgid    int32
+	Jobc    int16
+	Tdev    int32
+	Tpgid   int32
+	Tsess   uintptr
+	Wmesg   [8]byte
+	Xsize   int32
+	Xrssize int16
+	Xccount int16
+	Xswrss  int16
+	Flag    int32
+	Login   [12]byte
+	Spare   [4]int32
+	_       [4]byte
+}
+
+type kinfoProc struct {
+	Proc  externProc
+	Eproc eproc
+}
+
+// Compile-time check that kinfoProc matches the C layout.
+var _ [kinfoProcSize - unsafe.Sizeof(kinfoProc{})]byte
+var _ [unsafe.Sizeof(kinfoProc{}) - kinfoProcSize]byte
+
+// sysctl reads a raw sysctl value. It retries if the value grows between
+// the size query and the read, which happens for process tables.
+func sysctl(mib ...int32) ([]byte, error) {
+	for i := 0; ; i++ {
+		var n uintptr
+		if err := rawSysctl(mib, nil, &n); err != nil {
+			return nil, err
+		}
+		if n == 0 {
+			return nil, nil
+		}
+		n += n / 8 // headroom for new entries
+		buf := make([]byte, n)
+		err := rawSysctl(mib, &buf[0], &n)
+		if err == syscall.ENOMEM && i < maxSysctlRetry {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		return buf[:n], nil
+	}
+}
+
+// rawSysctl invokes the __sysctl system call.
+func rawSysctl(mib []int32, old *byte, oldlen *uintptr) error {
+	_, _, errno := syscall.Syscall6(syscall.SYS___SYSCTL,
+		uintptr(unsafe.Pointer(&mib[0])), uintptr(len(mib)),
+		uintptr(unsafe.Pointer(old)), uintptr(unsafe.Pointer(oldlen)), 0, 0)
+	if errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// kinfoProcs decodes a buffer of kinfo_proc structures.
+func kinfoProcs(buf []byte) []kinfoProc {
+	procs := make([]kinfoProc, len(buf)/kinfoProcSize)
+	for i := range procs {
+		procs[i] = *(*kinfoProc)(unsafe.Pointer(&buf[i*kinfoProcSize]))
+	}
+	return procs
+}
+
+// cString converts a NUL-terminated byte array to a string.
+func cString(b []byte) string {
+	for i, c := range b {
+		if c == 0 {
+			return string(b[:i])
+		}
+	}
+	return string(b)
+}
@@ -0,0 +1,52 @@
+package process
+
+import (
+	"context"
+	"time"
+)
+
+// pollInterval is how often Terminate checks whether a process has exited.
+const pollInterval = 20 * time.Millisecond
+
+// Terminate asks the process with the given PID to shut down, waits up to
+// grace for it to exit, and force-kills it if it is still running.
+// It reports whether the process exited on its own within the grace period.
+//
+// Platform behavior:
+//   - Unix: sends SIGTERM, then SIGKILL after the grace period.
+//   - Windows: sends CTRL_BREAK_EVENT (received by console processes started
+//     with CREATE_NEW_PROCESS_GROUP that share our console) and posts WM_CLOSE
+//     to the process's top-level windows, then calls TerminateProcess after
+//     the grace period.
+//
+// If ctx is done before the grace period ends, the process is force-killed
+// immediately. An error is returned if the process does not exist or cannot
+// be signaled.
+func Terminate(ctx context.Context, pid int, grace time.Duration) (graceful bool, err error) {
+	return terminate(ctx, pid, grace)
+}
+
+// waitFor polls done until it returns true, the timeout elapses, or ctx is
+// done. It reports whether done returned true.
+func waitFor(ctx context.Context, timeout time.Duration, done func() bool) bool {
+	if done() {
+		return true
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return done()
+		case <-timer.C:
+			return done()
+		case <-ticker.C:
+			if done() {
+				return true
+			}
+		}
+	}
+}
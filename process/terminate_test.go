@@ -0,0 +1,68 @@
+package process_test
+
+import (
+	"context"
+	"os/exec"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/grokify/oscompat/process"
+)
+
+// startReaped starts cmd and reaps it in the background so that the exited
+// child does not linger as a zombie.
+func startReaped(t *testing.T, cmd *exec.Cmd) {
+	t.Helper()
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("Start() error: %v", err)
+	}
+	go func() { _ = cmd.Wait() }()
+}
+
+func TestTerminateGraceful(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires SIGTERM")
+	}
+
+	cmd := exec.Command("sleep", "30")
+	startReaped(t, cmd)
+
+	graceful, err := process.Terminate(context.Background(), cmd.Process.Pid, 5*time.Second)
+	if err != nil {
+		t.Fatalf("Terminate() error: %v", err)
+	}
+	if !graceful {
+		t.Error("Terminate() = false, want graceful exit on SIGTERM")
+	}
+}
+
+func TestTerminateForced(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires SIGTERM")
+	}
+
+	// The shell ignores SIGTERM, so only SIGKILL stops it.
+	cmd := exec.Command("sh", "-c", `trap "" TERM; while :; do sleep 0.05; done`)
+	startReaped(t, cmd)
+	time.Sleep(100 * time.Millisecond) // let the trap install
+
+	start := time.Now()
+	graceful, err := process.Terminate(context.Background(), cmd.Process.Pid, 200*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Terminate() error: %v", err)
+	}
+	if graceful {
+		t.Error("Terminate() = true, want forced kill")
+	}
+	if elapsed := time.Since(start); elapsed < 200*time.Millisecond {
+		t.Errorf("Terminate() returned after %v, before the grace period", elapsed)
+	}
+}
+
+func TestTerminateNonExistentProcess(t *testing.T) {
+	_, err := process.Terminate(context.Background(), 999999999, time.Second)
+	if err == nil {
+		t.Error("Terminate on non-existent PID should return error")
+	}
+}
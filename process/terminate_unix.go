@@ -0,0 +1,50 @@
+//go:build !windows
+
+package process
+
+import (
+	"context"
+	"errors"
+	"os"
+	"syscall"
+	"time"
+)
+
+// killWait is how long to wait for a process to disappear after SIGKILL.
+const killWait = 5 * time.Second
+
+// terminate sends SIGTERM, waits, then sends SIGKILL.
+func terminate(ctx context.Context, pid int, grace time.Duration) (bool, error) {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return false, err
+	}
+	if err := proc.Signal(syscall.SIGTERM); err != nil {
+		return false, err
+	}
+
+	exited := func() bool { return !isAlive(pid) }
+	if waitFor(ctx, grace, exited) {
+		return true, nil
+	}
+
+	if err := proc.Signal(syscall.SIGKILL); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		// The process may have exited between the last check and the kill.
+		if exited() {
+			return true, nil
+		}
+		return false, err
+	}
+	waitFor(context.Background(), killWait, exited)
+	return false, nil
+}
+
+// isAlive reports whether a process with the given PID exists and has not
+// exited. Zombies (exited but not yet reaped) are reported as not alive.
+func isAlive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	if err != nil && err != syscall.EPERM {
+		return false
+	}
+	return !isZombie(pid)
+}
@@ -0,0 +1,83 @@
+//go:build windows
+
+package process
+
+import (
+	"context"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+var (
+	procGenerateConsoleCtrlEvent = kernel32.NewProc("GenerateConsoleCtrlEvent")
+	procEnumWindows              = user32.NewProc("EnumWindows")
+	procGetWindowThreadProcessID = user32.NewProc("GetWindowThreadProcessId")
+	procPostMessageW             = user32.NewProc("PostMessageW")
+)
+
+const (
+	ctrlBreakEvent = 1
+	wmClose        = 0x0010
+
+	processTerminate               = 0x0001
+	processQueryLimitedInformation = 0x1000
+	synchronize                    = 0x00100000
+)
+
+// killWait is how long to wait for a process to disappear after TerminateProcess.
+const killWait = 5 * time.Second
+
+// terminate sends CTRL_BREAK and WM_CLOSE, waits, then calls TerminateProcess.
+func terminate(ctx context.Context, pid int, grace time.Duration) (bool, error) {
+	h, err := syscall.OpenProcess(synchronize|processTerminate|processQueryLimitedInformation, false, uint32(pid))
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = syscall.CloseHandle(h) }()
+
+	// Both requests are best-effort: a process may have no console or no
+	// windows. Errors are ignored and the grace period applies regardless.
+	_, _, _ = procGenerateConsoleCtrlEvent.Call(ctrlBreakEvent, uintptr(pid))
+	postCloseToWindows(uint32(pid))
+
+	exited := func() bool { return handleExited(h) }
+	if waitFor(ctx, grace, exited) {
+		return true, nil
+	}
+
+	if err := syscall.TerminateProcess(h, 1); err != nil {
+		if exited() {
+			return true, nil
+		}
+		return false, err
+	}
+	waitFor(context.Background(), killWait, exited)
+	return false, nil
+}
+
+// handleExited reports whether the process behind h has exited.
+func handleExited(h syscall.Handle) bool {
+	event, _ := syscall.WaitForSingleObject(h, 0)
+	return event == syscall.WAIT_OBJECT_0
+}
+
+// closeWindowCallback is the EnumWindows callback of postCloseToWindows,
+// created once because syscall.NewCallback cannot free callbacks. The PID
+// whose windows to close is passed as lParam.
+var closeWindowCallback = sync.OnceValue(func() uintptr {
+	return syscall.NewCallback(func(hwnd syscall.Handle, pid uintptr) uintptr {
+		var owner uint32
+		_, _, _ = procGetWindowThreadProcessID.Call(uintptr(hwnd), uintptr(unsafe.Pointer(&owner)))
+		if uintptr(owner) == pid {
+			_, _, _ = procPostMessageW.Call(uintptr(hwnd), wmClose, 0, 0)
+		}
+		return 1 // continue enumeration
+	})
+})
+
+// postCloseToWindows posts WM_CLOSE to every top-level window owned by pid.
+func postCloseToWindows(pid uint32) {
+	_, _, _ = procEnumWindows.Call(closeWindowCallback(), uintptr(pid))
+}
@@ -0,0 +1,117 @@
+package process
+
+import (
+	"context"
+	"time"
+)
+
+// KillTree forcibly kills the process with the given PID and all of its
+// descendants. It returns ErrNotFound if the process does not exist.
+//
+// Platform behavior:
+//   - Unix: the tree is stopped with SIGSTOP so that it cannot fork while
+//     it is being collected, then every member and, if pid leads a process
+//     group, the whole group receives SIGKILL. The group catches
+//     grandchildren whose parent has already exited.
+//   - Windows: descendants are found by parent-PID traversal of a process
+//     snapshot and terminated parent first with TerminateProcess.
+//
+// On platforms where List is unsupported, only pid and its process group
+// are killed.
+func KillTree(pid int) error {
+	if pid <= 0 || !isAlive(pid) {
+		return ErrNotFound
+	}
+	return signalTree(pid, freezeTree(pid), true)
+}
+
+// TerminateTree is like Terminate but applies to the process with the
+// given PID and all of its descendants. Every member of the tree is asked
+// to shut down; after grace, or when ctx is done, any that remain are
+// killed as by KillTree. It reports whether the whole tree exited on its
+// own within the grace period.
+func TerminateTree(ctx context.Context, pid int, grace time.Duration) (graceful bool, err error) {
+	if pid <= 0 || !isAlive(pid) {
+		return false, ErrNotFound
+	}
+	pids := collectTree(pid)
+	if err := signalTree(pid, pids, false); err != nil {
+		return false, err
+	}
+
+	exited := func() bool {
+		for _, p := range pids {
+			if isAlive(p) {
+				return false
+			}
+		}
+		return true
+	}
+	if waitFor(ctx, grace, exited) {
+		return true, nil
+	}
+
+	// Members may have spawned new children during the grace period.
+	if isAlive(pid) {
+		pids = mergePIDs(pids, freezeTree(pid))
+	}
+	if err := signalTree(pid, pids, true); err != nil && !exited() {
+		return false, err
+	}
+	waitFor(context.Background(), killWait, exited)
+	return false, nil
+}
+
+// collectTree returns pid followed by its descendants, parents before
+// children. If the process table cannot be read, only pid is returned.
+func collectTree(pid int) []int {
+	procs, err := List()
+	if err != nil {
+		return []int{pid}
+	}
+
+	byPID := make(map[int]*Process, len(procs))
+	children := make(map[int][]*Process)
+	for _, p := range procs {
+		byPID[p.PID] = p
+		children[p.PPID] = append(children[p.PPID], p)
+	}
+
+	tree := []int{pid}
+	seen := map[int]bool{pid: true}
+	for i := 0; i < len(tree); i++ {
+		parent := byPID[tree[i]]
+		for _, child := range children[tree[i]] {
+			if seen[child.PID] || !startedAfter(child, parent) {
+				continue
+			}
+			seen[child.PID] = true
+			tree = append(tree, child.PID)
+		}
+	}
+	return tree
+}
+
+// startedAfter guards against PID reuse: a process whose parent PID has
+// been recycled would otherwise appear to be a child of an unrelated,
+// younger process.
+func startedAfter(child, parent *Process) bool {
+	if parent == nil || child.StartTime.IsZero() || parent.StartTime.IsZero() {
+		return true
+	}
+	return !child.StartTime.Before(parent.StartTime.Truncate(time.Second))
+}
+
+// mergePIDs returns a followed by the elements of b not already in a.
+func mergePIDs(a, b []int) []int {
+	seen := make(map[int]bool, len(a))
+	for _, p := range a {
+		seen[p] = true
+	}
+	for _, p := range b {
+		if !seen[p] {
+			a = append(a, p)
+		}
+	}
+	return a
+}
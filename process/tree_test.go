@@ -0,0 +1,113 @@
+package process_test
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/grokify/oscompat/process"
+)
+
+// startTree starts a shell that runs two background sleeps and returns the
+// shell and the PIDs of its children once both are visible.
+func startTree(t *testing.T, script string) (*exec.Cmd, []int) {
+	t.Helper()
+	cmd := exec.Command("sh", "-c", script)
+	startReaped(t, cmd)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		procs, err := process.List()
+		if errors.Is(err, errors.ErrUnsupported) {
+			t.Skip("List not supported on this platform")
+		}
+		if err != nil {
+			t.Fatalf("List() error: %v", err)
+		}
+		var children []int
+		for _, p := range procs {
+			if p.PPID == cmd.Process.Pid && p.Name == "sleep" {
+				children = append(children, p.PID)
+			}
+		}
+		if len(children) == 2 {
+			return cmd, children
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatal("children did not start")
+	return nil, nil
+}
+
+// waitGone fails the test if any of pids is still running after a while.
+func waitGone(t *testing.T, pids []int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for _, pid := range pids {
+		for {
+			p, err := process.Info(pid)
+			if errors.Is(err, process.ErrNotFound) || (err == nil && p.Name != "sleep") {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Errorf("child %d still running", pid)
+				break
+			}
+			time.Sleep(20 * time.Millisecond)
+		}
+	}
+}
+
+func TestKillTree(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+
+	cmd, children := startTree(t, "sleep 30 & sleep 30 & wait")
+	if err := process.KillTree(cmd.Process.Pid); err != nil {
+		t.Fatalf("KillTree() error: %v", err)
+	}
+	waitGone(t, children)
+}
+
+func TestTerminateTree(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+
+	cmd, children := startTree(t, "sleep 30 & sleep 30 & wait")
+	graceful, err := process.TerminateTree(context.Background(), cmd.Process.Pid, 5*time.Second)
+	if err != nil {
+		t.Fatalf("TerminateTree() error: %v", err)
+	}
+	if !graceful {
+		t.Error("TerminateTree() = false, want graceful exit on SIGTERM")
+	}
+	waitGone(t, children)
+}
+
+func TestTerminateTreeForced(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sh")
+	}
+
+	// The children ignore SIGTERM, so only SIGKILL stops them.
+	cmd, children := startTree(t, `trap "" TERM; sleep 30 & sleep 30 & wait`)
+	graceful, err := process.TerminateTree(context.Background(), cmd.Process.Pid, 200*time.Millisecond)
+	if err != nil {
+		t.Fatalf("TerminateTree() error: %v", err)
+	}
+	if graceful {
+		t.Error("TerminateTree() = true, want forced kill")
+	}
+	waitGone(t, children)
+}
+
+func TestKillTreeNotFound(t *testing.T) {
+	if err := process.KillTree(999999999); err != process.ErrNotFound {
+		t.Errorf("KillTree() error = %v, want ErrNotFound", err)
+	}
+}
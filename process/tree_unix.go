@@ -0,0 +1,70 @@
+//go:build !windows
+
+package process
+
+import "syscall"
+
+// maxFreezeRounds bounds how often freezeTree re-reads the process table.
+const maxFreezeRounds = 8
+
+// freezeTree stops pid and its descendants with SIGSTOP, re-reading the
+// process table until no new members appear, and returns the tree.
+// Stopped processes cannot fork, so the result is complete unless a
+// member escaped between two reads.
+func freezeTree(pid int) []int {
+	stopped := map[int]bool{}
+	if isGroupLeader(pid) {
+		_ = syscall.Kill(-pid, syscall.SIGSTOP)
+	}
+
+	var pids []int
+	for round := 0; round < maxFreezeRounds; round++ {
+		pids = collectTree(pid)
+		added := false
+		for _, p := range pids {
+			if !stopped[p] {
+				_ = syscall.Kill(p, syscall.SIGSTOP)
+				stopped[p] = true
+				added = true
+			}
+		}
+		if !added {
+			break
+		}
+	}
+	return pids
+}
+
+// signalTree sends SIGKILL (force) or SIGTERM to pid's process group, if
+// it leads one, and to every process in pids. Processes that have already
+// exited are ignored; other failures are reported only for pid itself.
+func signalTree(pid int, pids []int, force bool) error {
+	sig := syscall.SIGTERM
+	if force {
+		sig = syscall.SIGKILL
+	}
+
+	if isGroupLeader(pid) {
+		_ = syscall.Kill(-pid, sig)
+	}
+
+	var rootErr error
+	for _, p := range pids {
+		err := syscall.Kill(p, sig)
+		if !force && err == nil {
+			// A stopped process does not act on SIGTERM until continued.
+			_ = syscall.Kill(p, syscall.SIGCONT)
+		}
+		if p == pid && err != nil && err != syscall.ESRCH {
+			rootErr = err
+		}
+	}
+	return rootErr
+}
+
+// isGroupLeader reports whether pid leads its own process group and that
+// group is not ours, so signaling the group cannot hit the caller.
+func isGroupLeader(pid int) bool {
+	pgid, err := syscall.Getpgid(pid)
+	return err == nil && pgid == pid && pgid != syscall.Getpgrp()
+}
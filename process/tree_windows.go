@@ -0,0 +1,54 @@
+//go:build windows
+
+package process
+
+import "syscall"
+
+// freezeTree returns pid and its descendants. Windows has no way to stop
+// a process from outside it, so signalTree compensates by terminating
+// parents before their children.
+func freezeTree(pid int) []int {
+	return collectTree(pid)
+}
+
+// signalTree terminates every process in pids (force) or asks each to
+// close with CTRL_BREAK and WM_CLOSE. Processes that have already exited
+// are ignored; other failures are reported only for pid itself.
+func signalTree(pid int, pids []int, force bool) error {
+	var rootErr error
+	for _, p := range pids {
+		var err error
+		if force {
+			err = terminatePID(p)
+		} else {
+			_, _, _ = procGenerateConsoleCtrlEvent.Call(ctrlBreakEvent, uintptr(p))
+			postCloseToWindows(uint32(p))
+		}
+		if p == pid && err != nil && isAlive(p) {
+			rootErr = err
+		}
+	}
+	return rootErr
+}
+
+// terminatePID calls TerminateProcess on the given PID.
+func terminatePID(pid int) error {
+	h, err := syscall.OpenProcess(processTerminate, false, uint32(pid))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = syscall.CloseHandle(h) }()
+	return syscall.TerminateProcess(h, 1)
+}
+
+// isAlive reports whether a process with the given PID exists and has not
+// exited.
+func isAlive(pid int) bool {
+	h, err := syscall.OpenProcess(synchronize|processQueryLimitedInformation, false, uint32(pid))
+	if err != nil {
+		// Access denied means the process exists but belongs to someone else.
+		return err == syscall.ERROR_ACCESS_DENIED
+	}
+	defer func() { _ = syscall.CloseHandle(h) }()
+	return !handleExited(h)
+}
@@ -0,0 +1,43 @@
+package process
+
+import (
+	"os"
+	"time"
+)
+
+// ResourceUsage is a sample of a process's memory and CPU consumption.
+type ResourceUsage struct {
+	// RSS is the resident set size in bytes: the physical memory in use.
+	// On Windows this is the working set.
+	RSS uint64
+
+	// VirtualSize is the virtual memory size in bytes. On Windows this is
+	// the private committed memory (the page file usage), since reserved
+	// address space is not tracked per process.
+	VirtualSize uint64
+
+	// UserTime is the cumulative CPU time spent in user mode.
+	UserTime time.Duration
+
+	// SystemTime is the cumulative CPU time spent in the kernel.
+	SystemTime time.Duration
+}
+
+// CPUTime returns the total cumulative CPU time.
+func (u *ResourceUsage) CPUTime() time.Duration {
+	return u.UserTime + u.SystemTime
+}
+
+// Usage returns the current resource usage of the process with the given
+// PID. It returns ErrNotFound if the process does not exist.
+func Usage(pid int) (*ResourceUsage, error) {
+	if pid <= 0 {
+		return nil, ErrNotFound
+	}
+	return usage(pid)
+}
+
+// Self returns the current resource usage of the calling process.
+func Self() (*ResourceUsage, error) {
+	return usage(os.Getpid())
+}
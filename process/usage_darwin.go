@@ -0,0 +1,85 @@
+//go:build darwin
+
+package process
+
+import (
+	"encoding/binary"
+	"math/bits"
+	"sync"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+const (
+	procInfoCallPIDInfo = 2
+	procPIDTaskInfo     = 4
+)
+
+// procTaskInfo mirrors struct proc_taskinfo from <sys/proc_info.h>.
+type procTaskInfo struct {
+	VirtualSize      uint64
+	ResidentSize     uint64
+	TotalUser        uint64 // Mach absolute time units
+	TotalSystem      uint64
+	ThreadsUser      uint64
+	ThreadsSystem    uint64
+	Policy           int32
+	Faults           int32
+	Pageins          int32
+	CowFaults        int32
+	MessagesSent     int32
+	MessagesReceived int32
+	SyscallsMach     int32
+	SyscallsUnix     int32
+	Csw              int32
+	Threadnum        int32
+	Numrunning       int32
+	Priority         int32
+}
+
+// Compile-time check that procTaskInfo matches the C layout.
+var _ [96 - unsafe.Sizeof(procTaskInfo{})]byte
+var _ [unsafe.Sizeof(procTaskInfo{}) - 96]byte
+
+// timebaseFrequency is the frequency of the Mach absolute time clock in
+// Hz: 1 GHz on Intel and 24 MHz on Apple silicon.
+var timebaseFrequency = sync.OnceValue(func() uint64 {
+	// hw.tbfrequency is a 64-bit integer; syscall.Sysctl strips one
+	// trailing NUL byte, so pad the value back to 8 bytes.
+	s, err := syscall.Sysctl("hw.tbfrequency")
+	if err != nil || len(s) > 8 {
+		return 1e9
+	}
+	var b [8]byte
+	copy(b[:], s)
+	if f := binary.LittleEndian.Uint64(b[:]); f != 0 {
+		return f
+	}
+	return 1e9
+})
+
+// machTime converts Mach absolute time units to a Duration.
+func machTime(ticks uint64) time.Duration {
+	hi, lo := bits.Mul64(ticks, uint64(time.Second))
+	ns, _ := bits.Div64(hi%timebaseFrequency(), lo, timebaseFrequency())
+	return time.Duration(ns)
+}
+
+// usage calls proc_pidinfo(PROC_PIDTASKINFO).
+func usage(pid int) (*ResourceUsage, error) {
+	var ti procTaskInfo
+	n, err := procInfo(procInfoCallPIDInfo, pid, procPIDTaskInfo, 0, unsafe.Pointer(&ti), int(unsafe.Sizeof(ti)))
+	if err != nil {
+		return nil, err
+	}
+	if n != int(unsafe.Sizeof(ti)) {
+		return nil, ErrNotFound
+	}
+	return &ResourceUsage{
+		RSS:         ti.ResidentSize,
+		VirtualSize: ti.VirtualSize,
+		UserTime:    machTime(ti.TotalUser),
+		SystemTime:  machTime(ti.TotalSystem),
+	}, nil
+}
@@ -0,0 +1,23 @@
+//go:build linux
+
+package process
+
+import (
+	"os"
+	"time"
+)
+
+// usage reads /proc/<pid>/stat.
+func usage(pid int) (*ResourceUsage, error) {
+	st, err := readProcStat(pid)
+	if err != nil {
+		return nil, err
+	}
+	tick := time.Second / clockTicks
+	return &ResourceUsage{
+		RSS:         uint64(max(st.rssPages, 0)) * uint64(os.Getpagesize()),
+		VirtualSize: st.vsize,
+		UserTime:    time.Duration(st.utime) * tick,
+		SystemTime:  time.Duration(st.stime) * tick,
+	}, nil
+}
@@ -0,0 +1,8 @@
+//go:build !windows && !linux && !darwin
+
+package process
+
+// usage is not implemented on this platform.
+func usage(pid int) (*ResourceUsage, error) {
+	return nil, ErrUnsupported
+}
@@ -0,0 +1,49 @@
+package process_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/grokify/oscompat/process"
+)
+
+func TestSelf(t *testing.T) {
+	before, err := process.Self()
+	if errors.Is(err, errors.ErrUnsupported) {
+		t.Skip("Usage not supported on this platform")
+	}
+	if err != nil {
+		t.Fatalf("Self() error: %v", err)
+	}
+	if before.RSS == 0 {
+		t.Error("Self().RSS = 0")
+	}
+	if before.VirtualSize == 0 {
+		t.Error("Self().VirtualSize = 0")
+	}
+
+	// Burn enough CPU to register at the coarsest clock resolution.
+	deadline := time.Now().Add(100 * time.Millisecond)
+	for n := 0; time.Now().Before(deadline); n++ {
+		_ = n * n
+	}
+
+	after, err := process.Self()
+	if err != nil {
+		t.Fatalf("Self() error: %v", err)
+	}
+	if after.CPUTime() <= before.CPUTime() {
+		t.Errorf("CPUTime() did not increase: before %v, after %v", before.CPUTime(), after.CPUTime())
+	}
+}
+
+func TestUsageNotFound(t *testing.T) {
+	_, err := process.Usage(999999999)
+	if errors.Is(err, errors.ErrUnsupported) {
+		t.Skip("Usage not supported on this platform")
+	}
+	if err != process.ErrNotFound {
+		t.Errorf("Usage() error = %v, want ErrNotFound", err)
+	}
+}
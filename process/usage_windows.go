@@ -0,0 +1,59 @@
+//go:build windows
+
+package process
+
+import (
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+var procGetProcessMemoryInfo = kernel32.NewProc("K32GetProcessMemoryInfo")
+
+// processMemoryCounters mirrors PROCESS_MEMORY_COUNTERS.
+type processMemoryCounters struct {
+	cb                         uint32
+	PageFaultCount             uint32
+	PeakWorkingSetSize         uintptr
+	WorkingSetSize             uintptr
+	QuotaPeakPagedPoolUsage    uintptr
+	QuotaPagedPoolUsage        uintptr
+	QuotaPeakNonPagedPoolUsage uintptr
+	QuotaNonPagedPoolUsage     uintptr
+	PagefileUsage              uintptr
+	PeakPagefileUsage          uintptr
+}
+
+// usage queries the memory counters and process times of pid.
+func usage(pid int) (*ResourceUsage, error) {
+	h, err := syscall.OpenProcess(processQueryLimitedInformation, false, uint32(pid))
+	if err != nil {
+		if err == errorInvalidParameter {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	defer func() { _ = syscall.CloseHandle(h) }()
+
+	var mc processMemoryCounters
+	mc.cb = uint32(unsafe.Sizeof(mc))
+	if r, _, err := procGetProcessMemoryInfo.Call(uintptr(h), uintptr(unsafe.Pointer(&mc)), uintptr(mc.cb)); r == 0 {
+		return nil, err
+	}
+
+	var creation, exit, kernel, user syscall.Filetime
+	if err := syscall.GetProcessTimes(h, &creation, &exit, &kernel, &user); err != nil {
+		return nil, err
+	}
+	return &ResourceUsage{
+		RSS:         uint64(mc.WorkingSetSize),
+		VirtualSize: uint64(mc.PagefileUsage),
+		UserTime:    filetimeDuration(user),
+		SystemTime:  filetimeDuration(kernel),
+	}, nil
+}
+
+// filetimeDuration converts a FILETIME interval in 100ns units.
+func filetimeDuration(ft syscall.Filetime) time.Duration {
+	return time.Duration(uint64(ft.HighDateTime)<<32|uint64(ft.LowDateTime)) * 100
+}
@@ -0,0 +1,33 @@
+package process
+
+import "context"
+
+// WaitExit waits for the process with the given PID to exit. Unlike
+// os.Process.Wait, it works for processes that are not children of the
+// caller, such as a previous instance of the program being replaced by an
+// update. It returns nil once the process has exited, or immediately if
+// no such process exists, and ctx.Err() if ctx is done first.
+//
+// Platform behavior:
+//   - Linux: waits on a pidfd, falling back to polling on kernels older
+//     than 5.3.
+//   - macOS and FreeBSD: waits for a kqueue EVFILT_PROC NOTE_EXIT event.
+//   - Windows: waits on a process handle with WaitForSingleObject.
+//   - Elsewhere: polls for the process.
+func WaitExit(ctx context.Context, pid int) error {
+	if pid <= 0 {
+		return ErrNotFound
+	}
+	return waitExit(ctx, pid)
+}
+
+// pollExit polls until pid is no longer alive or ctx is done.
+func pollExit(ctx context.Context, pid int) error {
+	exited := func() bool { return !isAlive(pid) }
+	for !waitFor(ctx, pollInterval*50, exited) {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+//go:build darwin || freebsd
+
+package process
+
+import (
+	"context"
+	"syscall"
+	"time"
+)
+
+// kqueueTimeout bounds each kevent call so that ctx is checked regularly.
+const kqueueTimeout = 100 * time.Millisecond
+
+// waitExit waits for a NOTE_EXIT event on pid.
+func waitExit(ctx context.Context, pid int) (err error) {
+	kq, err := syscall.Kqueue()
+	if err != nil {
+		return pollExit(ctx, pid)
+	}
+	defer func() {
+		if closeErr := syscall.Close(kq); err == nil {
+			err = closeErr
+		}
+	}()
+
+	var ev syscall.Kevent_t
+	syscall.SetKevent(&ev, pid, syscall.EVFILT_PROC, syscall.EV_ADD|syscall.EV_ONESHOT)
+	ev.Fflags = syscall.NOTE_EXIT
+	if _, err := syscall.Kevent(kq, []syscall.Kevent_t{ev}, nil, nil); err != nil {
+		if err == syscall.ESRCH {
+			return nil
+		}
+		return pollExit(ctx, pid)
+	}
+
+	timeout := syscall.NsecToTimespec(int64(kqueueTimeout))
+	events := make([]syscall.Kevent_t, 1)
+	for {
+		n, err := syscall.Kevent(kq, nil, events, &timeout)
+		if err != nil && err != syscall.EINTR {
+			return pollExit(ctx, pid)
+		}
+		if n > 0 {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
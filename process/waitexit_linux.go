@@ -0,0 +1,70 @@
+//go:build linux
+
+package process
+
+import (
+	"context"
+	"os"
+	"syscall"
+	"time"
+)
+
+// sysPidfdOpen is the pidfd_open system call number, which is the same on
+// all Linux architectures.
+const sysPidfdOpen = 434
+
+// waitExit waits for the pidfd of pid to become readable, which happens
+// when the process exits.
+func waitExit(ctx context.Context, pid int) (err error) {
+	fd, _, errno := syscall.Syscall(sysPidfdOpen, uintptr(pid), syscall.O_NONBLOCK, 0)
+	switch errno {
+	case 0:
+	case syscall.ESRCH:
+		return nil
+	default:
+		return pollExit(ctx, pid)
+	}
+
+	// A non-blocking descriptor passed to os.NewFile is registered with
+	// the runtime poller, so the wait does not occupy a thread.
+	f := os.NewFile(fd, "pidfd")
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	if isZombie(pid) {
+		return nil
+	}
+
+	rc, err := f.SyscallConn()
+	if err != nil {
+		return pollExit(ctx, pid)
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = f.SetReadDeadline(time.Unix(1, 0))
+		case <-done:
+		}
+	}()
+
+	waited := false
+	err = rc.Read(func(uintptr) bool {
+		// The first call happens before waiting; report "not ready" so the
+		// poller waits for the descriptor to become readable.
+		ready := waited
+		waited = true
+		return ready
+	})
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return pollExit(ctx, pid)
+	}
+	return nil
+}
@@ -0,0 +1,10 @@
+//go:build !windows && !linux && !darwin && !freebsd
+
+package process
+
+import "context"
+
+// waitExit polls for the process on platforms without a wait primitive.
+func waitExit(ctx context.Context, pid int) error {
+	return pollExit(ctx, pid)
+}
@@ -0,0 +1,50 @@
+package process_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/grokify/oscompat/process"
+)
+
+func TestWaitExit(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires sleep")
+	}
+
+	cmd := exec.Command("sleep", "0.2")
+	startReaped(t, cmd)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	start := time.Now()
+	if err := process.WaitExit(ctx, cmd.Process.Pid); err != nil {
+		t.Fatalf("WaitExit() error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("WaitExit() returned after %v, before the process exited", elapsed)
+	}
+}
+
+func TestWaitExitCanceled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	// The test process itself does not exit while we wait.
+	if err := process.WaitExit(ctx, os.Getpid()); err != context.DeadlineExceeded {
+		t.Errorf("WaitExit() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestWaitExitNotFound(t *testing.T) {
+	if err := process.WaitExit(context.Background(), 999999999); err != nil {
+		t.Errorf("WaitExit() for missing PID error = %v, want nil", err)
+	}
+	if err := process.WaitExit(context.Background(), 0); err != process.ErrNotFound {
+		t.Errorf("WaitExit(0) error = %v, want ErrNotFound", err)
+	}
+}
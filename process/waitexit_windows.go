@@ -0,0 +1,53 @@
+//go:build windows
+
+package process
+
+import (
+	"context"
+	"syscall"
+	"unsafe"
+)
+
+// errorInvalidParameter is returned by OpenProcess for a PID that does not
+// exist.
+const errorInvalidParameter = syscall.Errno(87)
+
+// waitExit waits on a handle to pid together with an event that is set
+// when ctx is done.
+func waitExit(ctx context.Context, pid int) error {
+	h, err := syscall.OpenProcess(synchronize, false, uint32(pid))
+	if err != nil {
+		if err == errorInvalidParameter {
+			return nil // no such process
+		}
+		return pollExit(ctx, pid)
+	}
+	defer func() { _ = syscall.CloseHandle(h) }()
+
+	canceled, err := createEvent("")
+	if err != nil {
+		return pollExit(ctx, pid)
+	}
+	defer func() { _ = syscall.CloseHandle(canceled) }()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_, _, _ = procSetEvent.Call(uintptr(canceled))
+		case <-done:
+		}
+	}()
+
+	handles := [2]syscall.Handle{h, canceled}
+	r, _, err := procWaitForMultipleObjects.Call(2, uintptr(unsafe.Pointer(&handles[0])), 0, syscall.INFINITE)
+	switch r {
+	case syscall.WAIT_OBJECT_0:
+		return nil
+	case syscall.WAIT_OBJECT_0 + 1:
+		return ctx.Err()
+	default:
+		return err
+	}
+}
@@ -0,0 +1,10 @@
+//go:build linux
+
+package process
+
+// isZombie reports whether the process is in the zombie state, according
+// to /proc/<pid>/stat.
+func isZombie(pid int) bool {
+	st, err := readProcStat(pid)
+	return err == nil && st.state == 'Z'
+}
@@ -0,0 +1,9 @@
+//go:build !windows && !linux
+
+package process
+
+// isZombie reports whether the process is in the zombie state.
+// Zombie detection is not implemented on this platform.
+func isZombie(pid int) bool {
+	return false
+}
@@ -0,0 +1,125 @@
+//go:build darwin
+
+package settings
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+// nativeBackend is the backend Native resolves to.
+const nativeBackend = Defaults
+
+// defaultsStore keeps settings in a user defaults domain through the
+// defaults tool, which also keeps cfprefsd's cache coherent.
+type defaultsStore struct {
+	domain string
+}
+
+func newDefaults(app string) (storage, error) {
+	if _, err := exec.LookPath("defaults"); err != nil {
+		return nil, ErrUnsupported
+	}
+	return &defaultsStore{domain: app}, nil
+}
+
+// all exports the domain as an XML property list. Strings, numbers,
+// dates, and booleans are returned as strings; data, arrays, and
+// dictionaries are skipped.
+func (s *defaultsStore) all() (map[string]string, error) {
+	out, err := s.run("export", s.domain, "-")
+	if err != nil {
+		if strings.Contains(err.Error(), "does not exist") {
+			return map[string]string{}, nil
+		}
+		return nil, err
+	}
+	return parsePlist(out)
+}
+
+func (s *defaultsStore) get(key string) (string, bool, error) {
+	all, err := s.all()
+	if err != nil {
+		return "", false, err
+	}
+	v, ok := all[key]
+	return v, ok, nil
+}
+
+func (s *defaultsStore) set(key, value string) error {
+	_, err := s.run("write", s.domain, key, "-string", value)
+	return err
+}
+
+func (s *defaultsStore) delete(key string) error {
+	_, ok, err := s.get(key)
+	if err != nil || !ok {
+		return err
+	}
+	_, err = s.run("delete", s.domain, key)
+	return err
+}
+
+// run runs defaults with args.
+func (s *defaultsStore) run(args ...string) ([]byte, error) {
+	var stderr bytes.Buffer
+	cmd := exec.Command("defaults", args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("oscompat/settings: defaults %s: %s", args[0], msg)
+		}
+		return nil, fmt.Errorf("oscompat/settings: defaults %s: %w", args[0], err)
+	}
+	return out, nil
+}
+
+// parsePlist reads the top-level dictionary of an XML property list.
+func parsePlist(data []byte) (map[string]string, error) {
+	all := make(map[string]string)
+	d := xml.NewDecoder(bytes.NewReader(data))
+	inDict := false
+	key := ""
+	for {
+		tok, err := d.Token()
+		if err == io.EOF {
+			return all, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("oscompat/settings: parsing defaults: %w", err)
+		}
+		se, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch name := se.Name.Local; {
+		case name == "plist":
+		case name == "dict" && !inDict:
+			inDict = true
+		case name == "key":
+			if err := d.DecodeElement(&key, &se); err != nil {
+				return nil, err
+			}
+		case name == "string" || name == "integer" || name == "real" || name == "date":
+			var v string
+			if err := d.DecodeElement(&v, &se); err != nil {
+				return nil, err
+			}
+			all[key] = v
+		case name == "true" || name == "false":
+			all[key] = name
+			if err := d.Skip(); err != nil {
+				return nil, err
+			}
+		default:
+			if err := d.Skip(); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
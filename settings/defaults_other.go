@@ -0,0 +1,7 @@
+//go:build !darwin
+
+package settings
+
+func newDefaults(string) (storage, error) {
+	return nil, ErrUnsupported
+}
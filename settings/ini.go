@@ -0,0 +1,153 @@
+package settings
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/grokify/oscompat/paths"
+)
+
+// iniFile is the file name of the INI backend.
+const iniFile = "settings.ini"
+
+// iniStore keeps settings in an INI file. Keys in a [section] are read as
+// "section.key". The file is rewritten on every change, without
+// sections or comments.
+type iniStore struct {
+	path string
+}
+
+func newINI(app string) (storage, error) {
+	dir, err := paths.AppConfig(app)
+	if err != nil {
+		return nil, err
+	}
+	return &iniStore{path: filepath.Join(dir, iniFile)}, nil
+}
+
+func (s *iniStore) all() (map[string]string, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return map[string]string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseINI(data), nil
+}
+
+func (s *iniStore) get(key string) (string, bool, error) {
+	all, err := s.all()
+	if err != nil {
+		return "", false, err
+	}
+	v, ok := all[key]
+	return v, ok, nil
+}
+
+func (s *iniStore) set(key, value string) error {
+	all, err := s.all()
+	if err != nil {
+		return err
+	}
+	if v, ok := all[key]; ok && v == value {
+		return nil
+	}
+	all[key] = value
+	return s.write(all)
+}
+
+func (s *iniStore) delete(key string) error {
+	all, err := s.all()
+	if err != nil {
+		return err
+	}
+	if _, ok := all[key]; !ok {
+		return nil
+	}
+	delete(all, key)
+	return s.write(all)
+}
+
+// write replaces the file through a temporary file, so that readers
+// never see it half written.
+func (s *iniStore) write(all map[string]string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), iniFile+".*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := tmp.Write(formatINI(all)); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+// parseINI parses key = value lines. Values may be double-quoted to keep
+// surrounding whitespace, and may use the escapes \\, \n, \r, and \t.
+func parseINI(data []byte) map[string]string {
+	all := make(map[string]string)
+	section := ""
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		switch {
+		case line == "" || line[0] == ';' || line[0] == '#':
+			continue
+		case line[0] == '[' && line[len(line)-1] == ']':
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		if section != "" {
+			key = section + "." + key
+		}
+		value = strings.TrimSpace(value)
+		if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+			value = value[1 : len(value)-1]
+		}
+		all[key] = unescapeINI(value)
+	}
+	return all
+}
+
+// formatINI formats settings as sorted key = value lines.
+func formatINI(all map[string]string) []byte {
+	var b bytes.Buffer
+	for _, key := range slices.Sorted(maps.Keys(all)) {
+		value := escapeINI(all[key])
+		if value != strings.TrimSpace(value) || strings.HasPrefix(value, `"`) {
+			value = `"` + value + `"`
+		}
+		b.WriteString(key + " = " + value + "\n")
+	}
+	return b.Bytes()
+}
+
+var (
+	iniEscaper   = strings.NewReplacer(`\`, `\\`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	iniUnescaper = strings.NewReplacer(`\\`, `\`, `\n`, "\n", `\r`, "\r", `\t`, "\t")
+)
+
+func escapeINI(s string) string {
+	return iniEscaper.Replace(s)
+}
+
+func unescapeINI(s string) string {
+	return iniUnescaper.Replace(s)
+}
@@ -0,0 +1,6 @@
+//go:build !windows && !darwin
+
+package settings
+
+// nativeBackend is the backend Native resolves to.
+const nativeBackend = INI
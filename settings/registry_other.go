@@ -0,0 +1,7 @@
+//go:build !windows
+
+package settings
+
+func newRegistry(string) (storage, error) {
+	return nil, ErrUnsupported
+}
@@ -0,0 +1,186 @@
+//go:build windows
+
+package settings
+
+import (
+	"encoding/binary"
+	"errors"
+	"strconv"
+	"syscall"
+	"unsafe"
+)
+
+// nativeBackend is the backend Native resolves to.
+const nativeBackend = Registry
+
+var (
+	advapi32            = syscall.NewLazyDLL("advapi32.dll")
+	procRegCreateKeyExW = advapi32.NewProc("RegCreateKeyExW")
+	procRegSetValueExW  = advapi32.NewProc("RegSetValueExW")
+	procRegDeleteValueW = advapi32.NewProc("RegDeleteValueW")
+	procRegEnumValueW   = advapi32.NewProc("RegEnumValueW")
+)
+
+const (
+	regQWORD = 11
+
+	errorNoMoreItems syscall.Errno = 259
+
+	// maxValueName is the longest registry value name, in characters.
+	maxValueName = 16383
+)
+
+// registryStore keeps settings as values of HKCU\Software\<app>.
+type registryStore struct {
+	key string
+}
+
+func newRegistry(app string) (storage, error) {
+	return &registryStore{key: `Software\` + app}, nil
+}
+
+// open opens the app's key, creating it when create is set. It returns
+// a zero handle and no error if the key does not exist.
+func (s *registryStore) open(create bool) (syscall.Handle, error) {
+	name, err := syscall.UTF16PtrFromString(s.key)
+	if err != nil {
+		return 0, err
+	}
+	var h syscall.Handle
+	if !create {
+		err := syscall.RegOpenKeyEx(syscall.HKEY_CURRENT_USER, name, 0, syscall.KEY_READ, &h)
+		if errors.Is(err, syscall.ERROR_FILE_NOT_FOUND) {
+			return 0, nil
+		}
+		return h, err
+	}
+	r, _, _ := procRegCreateKeyExW.Call(uintptr(syscall.HKEY_CURRENT_USER), uintptr(unsafe.Pointer(name)),
+		0, 0, 0, syscall.KEY_READ|syscall.KEY_WRITE, 0, uintptr(unsafe.Pointer(&h)), 0)
+	if r != 0 {
+		return 0, syscall.Errno(r)
+	}
+	return h, nil
+}
+
+func (s *registryStore) all() (map[string]string, error) {
+	all := make(map[string]string)
+	h, err := s.open(false)
+	if err != nil || h == 0 {
+		return all, err
+	}
+	defer func() { _ = syscall.RegCloseKey(h) }()
+
+	name := make([]uint16, maxValueName+1)
+	for i := uint32(0); ; i++ {
+		n := uint32(len(name))
+		r, _, _ := procRegEnumValueW.Call(uintptr(h), uintptr(i), uintptr(unsafe.Pointer(&name[0])),
+			uintptr(unsafe.Pointer(&n)), 0, 0, 0, 0)
+		if syscall.Errno(r) == errorNoMoreItems {
+			return all, nil
+		}
+		if r != 0 {
+			return nil, syscall.Errno(r)
+		}
+		key := syscall.UTF16ToString(name[:n])
+		if v, ok, err := query(h, key); err == nil && ok {
+			all[key] = v
+		}
+	}
+}
+
+func (s *registryStore) get(key string) (string, bool, error) {
+	h, err := s.open(false)
+	if err != nil || h == 0 {
+		return "", false, err
+	}
+	defer func() { _ = syscall.RegCloseKey(h) }()
+	return query(h, key)
+}
+
+func (s *registryStore) set(key, value string) error {
+	h, err := s.open(true)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = syscall.RegCloseKey(h) }()
+	name, err := syscall.UTF16PtrFromString(key)
+	if err != nil {
+		return err
+	}
+	data, err := syscall.UTF16FromString(value)
+	if err != nil {
+		return err
+	}
+	r, _, _ := procRegSetValueExW.Call(uintptr(h), uintptr(unsafe.Pointer(name)), 0, syscall.REG_SZ,
+		uintptr(unsafe.Pointer(&data[0])), uintptr(len(data)*2))
+	if r != 0 {
+		return syscall.Errno(r)
+	}
+	return nil
+}
+
+func (s *registryStore) delete(key string) error {
+	name, err := syscall.UTF16PtrFromString(s.key)
+	if err != nil {
+		return err
+	}
+	var h syscall.Handle
+	err = syscall.RegOpenKeyEx(syscall.HKEY_CURRENT_USER, name, 0, syscall.KEY_SET_VALUE, &h)
+	if errors.Is(err, syscall.ERROR_FILE_NOT_FOUND) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer func() { _ = syscall.RegCloseKey(h) }()
+	valueName, err := syscall.UTF16PtrFromString(key)
+	if err != nil {
+		return err
+	}
+	r, _, _ := procRegDeleteValueW.Call(uintptr(h), uintptr(unsafe.Pointer(valueName)))
+	if r != 0 && syscall.Errno(r) != syscall.ERROR_FILE_NOT_FOUND {
+		return syscall.Errno(r)
+	}
+	return nil
+}
+
+// query reads a value, converting string and integer types to strings.
+// Values of other types are reported as not set.
+func query(h syscall.Handle, key string) (string, bool, error) {
+	name, err := syscall.UTF16PtrFromString(key)
+	if err != nil {
+		return "", false, err
+	}
+	var typ, n uint32
+	err = syscall.RegQueryValueEx(h, name, nil, &typ, nil, &n)
+	if errors.Is(err, syscall.ERROR_FILE_NOT_FOUND) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	buf := make([]byte, n+2)
+	n = uint32(len(buf))
+	if err := syscall.RegQueryValueEx(h, name, nil, &typ, &buf[0], &n); err != nil {
+		return "", false, err
+	}
+	buf = buf[:n]
+
+	switch typ {
+	case syscall.REG_SZ, syscall.REG_EXPAND_SZ:
+		u := make([]uint16, len(buf)/2)
+		for i := range u {
+			u[i] = binary.LittleEndian.Uint16(buf[2*i:])
+		}
+		return syscall.UTF16ToString(u), true, nil
+	case syscall.REG_DWORD:
+		if len(buf) >= 4 {
+			return strconv.FormatUint(uint64(binary.LittleEndian.Uint32(buf)), 10), true, nil
+		}
+	case regQWORD:
+		if len(buf) >= 8 {
+			return strconv.FormatUint(binary.LittleEndian.Uint64(buf), 10), true, nil
+		}
+	}
+	return "", false, nil
+}
@@ -0,0 +1,247 @@
+// Package settings provides a key-value store for application settings
+// in the platform's native location.
+//
+// Backends:
+//   - Registry (Windows): REG_SZ values under HKEY_CURRENT_USER\Software\<app>.
+//   - Defaults (macOS): string values in the user defaults domain <app>,
+//     through the defaults(1) tool. Use a reverse-DNS app name such as
+//     "com.example.myapp" here.
+//   - INI (all platforms): a settings.ini file under paths.AppConfig(app).
+//
+// Native picks Registry on Windows, Defaults on macOS, and INI elsewhere.
+// Interoperating with other programs that read the same settings is the
+// main reason to choose a backend explicitly.
+//
+// Keys and values are strings. Keys must be non-empty, must not start or
+// end with whitespace, must not start with '-', '#', ';', or '[', and
+// must not contain '=' or control characters, so that they are valid in
+// every backend.
+package settings
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/grokify/oscompat/paths"
+)
+
+// DefaultWatchInterval is how often Watch polls when no interval is given.
+const DefaultWatchInterval = 2 * time.Second
+
+var (
+	// ErrNotFound is returned by Get for keys that are not set.
+	ErrNotFound = errors.New("oscompat/settings: key not found")
+
+	// ErrInvalidKey is returned for keys that are not valid in every
+	// backend.
+	ErrInvalidKey = errors.New("oscompat/settings: invalid key")
+
+	// ErrUnsupported is returned by Open for a backend that does not
+	// exist on this platform. It matches errors.ErrUnsupported.
+	ErrUnsupported = fmt.Errorf("oscompat/settings: %w", errors.ErrUnsupported)
+)
+
+// Backend selects where a Store keeps its values.
+type Backend int
+
+const (
+	// Native is the platform's usual settings location.
+	Native Backend = iota
+
+	// INI is a settings.ini file in the app's configuration directory.
+	INI
+
+	// Registry is the Windows registry.
+	Registry
+
+	// Defaults is the macOS user defaults system.
+	Defaults
+)
+
+// String returns the backend name.
+func (b Backend) String() string {
+	switch b {
+	case Native:
+		return "native"
+	case INI:
+		return "ini"
+	case Registry:
+		return "registry"
+	case Defaults:
+		return "defaults"
+	}
+	return fmt.Sprintf("Backend(%d)", int(b))
+}
+
+// storage is implemented by each backend.
+type storage interface {
+	all() (map[string]string, error)
+	get(key string) (string, bool, error)
+	set(key, value string) error
+	delete(key string) error
+}
+
+// Store holds the settings of one app. It is safe for concurrent use;
+// changes made by other processes are seen on the next read.
+type Store struct {
+	app     string
+	backend Backend
+	mu      sync.Mutex
+	b       storage
+}
+
+// Open returns the settings store of app in the given backend.
+func Open(app string, backend Backend) (*Store, error) {
+	if app == "" {
+		return nil, paths.ErrInvalidAppName
+	}
+	if backend == Native {
+		backend = nativeBackend
+	}
+	var b storage
+	var err error
+	switch backend {
+	case INI:
+		b, err = newINI(app)
+	case Registry:
+		b, err = newRegistry(app)
+	case Defaults:
+		b, err = newDefaults(app)
+	default:
+		return nil, fmt.Errorf("oscompat/settings: unknown backend %v", backend)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Store{app: app, backend: backend, b: b}, nil
+}
+
+// App returns the app name the store was opened with.
+func (s *Store) App() string {
+	return s.app
+}
+
+// Backend returns the backend in use, never Native.
+func (s *Store) Backend() Backend {
+	return s.backend
+}
+
+// Get returns the value of key, or ErrNotFound if it is not set.
+func (s *Store) Get(key string) (string, error) {
+	if !validKey(key) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok, err := s.b.get(key)
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return "", ErrNotFound
+	}
+	return v, nil
+}
+
+// GetOr returns the value of key, or def if it is not set or cannot be
+// read.
+func (s *Store) GetOr(key, def string) string {
+	v, err := s.Get(key)
+	if err != nil {
+		return def
+	}
+	return v
+}
+
+// Set sets key to value.
+func (s *Store) Set(key, value string) error {
+	if !validKey(key) {
+		return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.b.set(key, value)
+}
+
+// Delete removes key. Deleting a key that is not set is not an error.
+func (s *Store) Delete(key string) error {
+	if !validKey(key) {
+		return fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.b.delete(key)
+}
+
+// Keys returns the keys that are set, sorted.
+func (s *Store) Keys() ([]string, error) {
+	all, err := s.All()
+	if err != nil {
+		return nil, err
+	}
+	return slices.Sorted(maps.Keys(all)), nil
+}
+
+// All returns every setting.
+func (s *Store) All() (map[string]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.b.all()
+}
+
+// Watch returns a channel that receives a value each time the settings
+// change, whether through this Store or another process. Changes that
+// arrive while a previous one is still pending are coalesced. The
+// channel is closed when ctx is done.
+//
+// Watch polls every interval, or DefaultWatchInterval if interval is
+// zero or negative.
+func (s *Store) Watch(ctx context.Context, interval time.Duration) <-chan struct{} {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	ch := make(chan struct{}, 1)
+	go func() {
+		defer close(ch)
+		last, _ := s.All()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			cur, err := s.All()
+			if err != nil || maps.Equal(cur, last) {
+				continue
+			}
+			last = cur
+			select {
+			case ch <- struct{}{}:
+			default:
+			}
+		}
+	}()
+	return ch
+}
+
+// validKey reports whether key is usable in every backend.
+func validKey(key string) bool {
+	if key == "" || key != strings.TrimSpace(key) || strings.ContainsAny(key[:1], "-#;[") {
+		return false
+	}
+	for _, r := range key {
+		if r == '=' || unicode.IsControl(r) {
+			return false
+		}
+	}
+	return true
+}
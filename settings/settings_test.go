@@ -0,0 +1,139 @@
+package settings_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/grokify/oscompat/settings"
+)
+
+// openINI opens an INI store in a temporary configuration directory.
+func openINI(t *testing.T) (*settings.Store, string) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	t.Setenv("APPDATA", dir)
+	s, err := settings.Open("oscompat-test", settings.INI)
+	if err != nil {
+		t.Fatalf("Open() error: %v", err)
+	}
+	return s, filepath.Join(dir, "oscompat-test", "settings.ini")
+}
+
+func TestINI(t *testing.T) {
+	s, file := openINI(t)
+
+	if _, err := s.Get("missing"); !errors.Is(err, settings.ErrNotFound) {
+		t.Errorf("Get(missing) error = %v, want ErrNotFound", err)
+	}
+	if got := s.GetOr("missing", "def"); got != "def" {
+		t.Errorf("GetOr(missing) = %q, want def", got)
+	}
+
+	values := map[string]string{
+		"theme":        "dark",
+		"window.width": "1024",
+		"greeting":     "  héllo = world  ",
+		"multiline":    "a\nb\\n\tc",
+		"quoted":       `"x"`,
+		"empty":        "",
+	}
+	for k, v := range values {
+		if err := s.Set(k, v); err != nil {
+			t.Fatalf("Set(%q) error: %v", k, err)
+		}
+	}
+	for k, want := range values {
+		if got, err := s.Get(k); err != nil || got != want {
+			t.Errorf("Get(%q) = %q, %v, want %q", k, got, err, want)
+		}
+	}
+
+	keys, err := s.Keys()
+	if err != nil {
+		t.Fatalf("Keys() error: %v", err)
+	}
+	if want := []string{"empty", "greeting", "multiline", "quoted", "theme", "window.width"}; !slices.Equal(keys, want) {
+		t.Errorf("Keys() = %v, want %v", keys, want)
+	}
+
+	if err := s.Delete("theme"); err != nil {
+		t.Fatalf("Delete() error: %v", err)
+	}
+	if err := s.Delete("theme"); err != nil {
+		t.Errorf("Delete() of missing key error: %v", err)
+	}
+	if _, err := s.Get("theme"); !errors.Is(err, settings.ErrNotFound) {
+		t.Errorf("Get() after Delete error = %v, want ErrNotFound", err)
+	}
+
+	if _, err := os.Stat(file); err != nil {
+		t.Errorf("settings file: %v", err)
+	}
+}
+
+func TestINIHandWritten(t *testing.T) {
+	s, file := openINI(t)
+	data := "; comment\nname = top\n\n[window]\nwidth = 800\n# another\nheight=\"600\"\n"
+	if err := os.WriteFile(file, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"name": "top", "window.width": "800", "window.height": "600"}
+	for k, v := range want {
+		if got, err := s.Get(k); err != nil || got != v {
+			t.Errorf("Get(%q) = %q, %v, want %q", k, got, err, v)
+		}
+	}
+}
+
+func TestInvalidKey(t *testing.T) {
+	s, _ := openINI(t)
+	for _, key := range []string{"", " a", "a ", "-a", "#a", ";a", "[a", "a=b", "a\nb"} {
+		if err := s.Set(key, "v"); !errors.Is(err, settings.ErrInvalidKey) {
+			t.Errorf("Set(%q) error = %v, want ErrInvalidKey", key, err)
+		}
+	}
+}
+
+func TestOpen(t *testing.T) {
+	if _, err := settings.Open("", settings.Native); err == nil {
+		t.Error("Open() with empty app name should return error")
+	}
+	s, err := settings.Open("oscompat-test", settings.Native)
+	if err != nil {
+		t.Fatalf("Open(Native) error: %v", err)
+	}
+	if s.Backend() == settings.Native {
+		t.Error("Backend() = Native, want the resolved backend")
+	}
+}
+
+func TestWatch(t *testing.T) {
+	s, _ := openINI(t)
+	other, err := settings.Open("oscompat-test", settings.INI)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := s.Watch(ctx, 10*time.Millisecond)
+	time.Sleep(30 * time.Millisecond)
+
+	if err := other.Set("k", "v"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-ch:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Watch() did not report the change")
+	}
+
+	cancel()
+	for range ch {
+	}
+}
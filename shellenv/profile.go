@@ -0,0 +1,154 @@
+package shellenv
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grokify/oscompat/text"
+)
+
+// blockLines returns the first and last lines of a marked block.
+func blockLines(marker string) (begin, end string) {
+	return "# >>> " + marker + " >>>", "# <<< " + marker + " <<<"
+}
+
+// addBlock puts line in a block named marker in the profile at path,
+// replacing the block if it exists and otherwise adding it at the top
+// or the bottom of the file. The file keeps its encoding and line endings.
+func addBlock(path, marker, line string, top bool) error {
+	content, enc, eol, err := readProfile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	begin, end := blockLines(marker)
+	block := begin + "\n" + line + "\n" + end + "\n"
+
+	content, found := replaceBlock(content, marker, block)
+	if !found {
+		switch {
+		case content == "":
+			content = block
+		case top:
+			content = block + "\n" + content
+		default:
+			if !strings.HasSuffix(content, "\n") {
+				content += "\n"
+			}
+			content += "\n" + block
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return writeProfile(path, content, enc, eol)
+}
+
+// removeBlock deletes the block named marker, and the blank line that
+// addBlock put next to it, from the profile at path.
+func removeBlock(path, marker string) error {
+	content, enc, eol, err := readProfile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	content, found := replaceBlock(content, marker, "")
+	if !found {
+		return nil
+	}
+	return writeProfile(path, content, enc, eol)
+}
+
+// readProfile reads the profile at path as text with LF line endings,
+// and returns the encoding and line ending to write it back with.
+// Windows PowerShell 5.1 profiles are often UTF-16 or UTF-8 with a byte
+// order mark, which is decoded.
+func readProfile(path string) (content string, enc text.Encoding, eol text.EOL, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", text.UTF8, text.LF, err
+	}
+	switch {
+	case bytes.HasPrefix(data, []byte{0xEF, 0xBB, 0xBF}):
+		enc = text.UTF8BOM
+	case bytes.HasPrefix(data, []byte{0xFF, 0xFE}):
+		enc = text.UTF16LE
+	case bytes.HasPrefix(data, []byte{0xFE, 0xFF}):
+		enc = text.UTF16BE
+	}
+	decoded, err := io.ReadAll(text.NewReader(bytes.NewReader(data)))
+	if err != nil {
+		return "", enc, text.LF, err
+	}
+	eol = text.LF
+	if len(decoded) > 0 {
+		eol, _ = text.DetectEOL(bytes.NewReader(decoded))
+	}
+	return string(text.Convert(decoded, text.LF)), enc, eol, nil
+}
+
+// writeProfile replaces the profile at path through a temporary file in
+// the same directory, so that a crash or a full disk never leaves it
+// truncated. An existing profile keeps its mode, and a profile that is a
+// symbolic link, as dotfile managers create, is replaced at its target.
+func writeProfile(path, content string, enc text.Encoding, eol text.EOL) error {
+	mode := os.FileMode(0o644)
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	w := text.NewWriter(tmp, enc)
+	if _, err := w.Write(text.Convert([]byte(content), eol)); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// replaceBlock replaces the block named marker in content with block,
+// and reports whether it was found. An empty block also takes the
+// separating blank line with it.
+func replaceBlock(content, marker, block string) (string, bool) {
+	begin, end := blockLines(marker)
+	i := strings.Index(content, begin+"\n")
+	if i < 0 || (i > 0 && content[i-1] != '\n') {
+		return content, false
+	}
+	j := strings.Index(content[i:], end)
+	if j < 0 {
+		return content, false
+	}
+	before, after := content[:i], content[i+j+len(end):]
+	after = strings.TrimPrefix(after, "\n")
+	if block == "" {
+		if strings.HasSuffix(before, "\n\n") {
+			before = before[:len(before)-1]
+		} else if before == "" {
+			after = strings.TrimPrefix(after, "\n")
+		}
+	}
+	return before + block + after, true
+}
@@ -0,0 +1,223 @@
+// Package shellenv installs shell integration for command-line programs.
+//
+// Every shell looks for completion scripts somewhere else, and some only
+// through lines in the user's profile: bash-completion reads a per-user
+// directory, zsh searches $fpath, fish reads its completions directory,
+// and PowerShell only runs what its profile loads. InstallCompletion puts
+// a script where the given shell finds it in new sessions, without root
+// or administrator rights, and UninstallCompletion removes it again.
+package shellenv
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grokify/oscompat/paths"
+)
+
+// Shell names a shell. The values match paths.ShellProfile.Shell.
+type Shell string
+
+// Supported shells.
+const (
+	Bash Shell = "bash"
+	Zsh  Shell = "zsh"
+	Fish Shell = "fish"
+
+	// PowerShell is PowerShell 7 (pwsh) on any platform.
+	PowerShell Shell = "pwsh"
+
+	// WindowsPowerShell is Windows PowerShell 5.1.
+	WindowsPowerShell Shell = "powershell"
+)
+
+var (
+	// ErrUnsupportedShell is returned for a shell this package cannot
+	// install completions for.
+	ErrUnsupportedShell = errors.New("oscompat/shellenv: unsupported shell")
+
+	// ErrInvalidAppName is returned when the app name is empty or
+	// contains a path separator.
+	ErrInvalidAppName = errors.New("oscompat/shellenv: invalid app name")
+)
+
+// Current returns the user's shell according to $SHELL, which Windows
+// only sets inside Git Bash and MSYS2.
+func Current() (Shell, bool) {
+	name := strings.TrimSuffix(filepath.Base(os.Getenv("SHELL")), ".exe")
+	switch s := Shell(name); s {
+	case Bash, Zsh, Fish, PowerShell:
+		return s, true
+	}
+	return "", false
+}
+
+// CompletionPath returns the file InstallCompletion writes for appName
+// and shell:
+//   - bash: completions/<app> in $BASH_COMPLETION_USER_DIR or
+//     $XDG_DATA_HOME/bash-completion, read by bash-completion 2, which
+//     must be installed (it is by default on most Linux distributions,
+//     and from Homebrew on macOS)
+//   - zsh: _<app> in $XDG_DATA_HOME/zsh/site-functions
+//   - fish: completions/<app>.fish in fish's configuration directory
+//   - PowerShell: Completions/<app>.ps1 next to the profile
+func CompletionPath(appName string, shell Shell) (string, error) {
+	if appName == "" || strings.ContainsAny(appName, `/\`) {
+		return "", ErrInvalidAppName
+	}
+	switch shell {
+	case Bash:
+		dir := os.Getenv("BASH_COMPLETION_USER_DIR")
+		if !filepath.IsAbs(dir) {
+			data, err := dataHome()
+			if err != nil {
+				return "", err
+			}
+			dir = filepath.Join(data, "bash-completion")
+		}
+		return filepath.Join(dir, "completions", appName), nil
+	case Zsh:
+		dir, err := zshFunctionDir()
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(dir, "_"+appName), nil
+	case Fish, PowerShell, WindowsPowerShell:
+		profile, err := profilePath(shell)
+		if err != nil {
+			return "", err
+		}
+		if shell == Fish {
+			return filepath.Join(filepath.Dir(profile), "completions", appName+".fish"), nil
+		}
+		return filepath.Join(filepath.Dir(profile), "Completions", appName+".ps1"), nil
+	}
+	return "", ErrUnsupportedShell
+}
+
+// InstallCompletion writes script, the completion script the program
+// generates for shell, to CompletionPath and returns that path. It
+// replaces an earlier installation.
+//
+// For zsh, it also adds the site-functions directory to $fpath in a
+// marked block at the top of .zshrc, so that the directory is in place
+// before compinit runs. For PowerShell, it adds a marked line to the
+// profile that loads the script. Other shells load the file on their
+// own. The change applies to shells started afterwards.
+func InstallCompletion(appName string, shell Shell, script []byte) (string, error) {
+	path, err := CompletionPath(appName, shell)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, script, 0o644); err != nil {
+		return "", err
+	}
+
+	switch shell {
+	case Zsh:
+		profile, err := profilePath(Zsh)
+		if err != nil {
+			return "", err
+		}
+		line := "fpath=(" + zshQuote(filepath.Dir(path)) + " $fpath)"
+		err = addBlock(profile, zshMarker, line, true)
+		return path, err
+	case PowerShell, WindowsPowerShell:
+		profile, err := profilePath(shell)
+		if err != nil {
+			return "", err
+		}
+		line := "if (Test-Path " + psQuote(path) + ") { . " + psQuote(path) + " }"
+		err = addBlock(profile, appName+" completion", line, false)
+		return path, err
+	}
+	return path, nil
+}
+
+// UninstallCompletion removes the completion script for appName and
+// shell and the profile lines InstallCompletion added for it. Removing a
+// completion that is not installed is not an error.
+func UninstallCompletion(appName string, shell Shell) error {
+	path, err := CompletionPath(appName, shell)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	switch shell {
+	case Zsh:
+		// The fpath block is shared by all programs; drop it with the
+		// last of their scripts.
+		if entries, err := os.ReadDir(filepath.Dir(path)); err == nil && len(entries) > 0 {
+			return nil
+		}
+		profile, err := profilePath(Zsh)
+		if err != nil {
+			return err
+		}
+		return removeBlock(profile, zshMarker)
+	case PowerShell, WindowsPowerShell:
+		profile, err := profilePath(shell)
+		if err != nil {
+			return err
+		}
+		return removeBlock(profile, appName+" completion")
+	}
+	return nil
+}
+
+// zshMarker names the .zshrc block that extends $fpath.
+const zshMarker = "oscompat shellenv fpath"
+
+// dataHome returns $XDG_DATA_HOME or ~/.local/share, which bash-completion
+// and zsh use on every platform, including Git Bash on Windows.
+func dataHome() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); filepath.IsAbs(dir) {
+		return dir, nil
+	}
+	home, err := paths.Home()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "share"), nil
+}
+
+// zshFunctionDir returns the directory for zsh completion functions.
+func zshFunctionDir() (string, error) {
+	data, err := dataHome()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(data, "zsh", "site-functions"), nil
+}
+
+// profilePath returns the interactive initialization file of shell.
+func profilePath(shell Shell) (string, error) {
+	profiles, err := paths.ShellProfiles()
+	if err != nil {
+		return "", err
+	}
+	for _, p := range profiles {
+		if p.Shell == string(shell) && !p.Login {
+			return p.Path, nil
+		}
+	}
+	return "", ErrUnsupportedShell
+}
+
+// zshQuote single-quotes s for zsh.
+func zshQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// psQuote single-quotes s for PowerShell, which doubles quotes inside.
+func psQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
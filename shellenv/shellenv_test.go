@@ -0,0 +1,197 @@
+package shellenv_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/grokify/oscompat/shellenv"
+	"github.com/grokify/oscompat/text"
+)
+
+// fakeHome points the home and XDG directories at a temporary directory.
+func fakeHome(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	for _, v := range []string{"XDG_DATA_HOME", "XDG_CONFIG_HOME", "ZDOTDIR", "BASH_COMPLETION_USER_DIR"} {
+		t.Setenv(v, "")
+	}
+	return home
+}
+
+func TestInstallCompletion(t *testing.T) {
+	home := fakeHome(t)
+	script := []byte("complete -F _myapp myapp\n")
+
+	shells := []shellenv.Shell{shellenv.Bash, shellenv.Zsh, shellenv.Fish}
+	if runtime.GOOS != "windows" {
+		shells = append(shells, shellenv.PowerShell)
+	}
+	for _, shell := range shells {
+		t.Run(string(shell), func(t *testing.T) {
+			path, err := shellenv.InstallCompletion("myapp", shell, script)
+			if err != nil {
+				t.Fatalf("InstallCompletion() error: %v", err)
+			}
+			if !strings.HasPrefix(path, home) {
+				t.Errorf("path %q is outside the home directory", path)
+			}
+			if data, err := os.ReadFile(path); err != nil || string(data) != string(script) {
+				t.Errorf("script = %q, %v", data, err)
+			}
+			// Installing twice must not duplicate profile lines.
+			if _, err := shellenv.InstallCompletion("myapp", shell, script); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := shellenv.UninstallCompletion("myapp", shell); err != nil {
+				t.Fatalf("UninstallCompletion() error: %v", err)
+			}
+			if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("script still present: %v", err)
+			}
+			if err := shellenv.UninstallCompletion("myapp", shell); err != nil {
+				t.Errorf("second UninstallCompletion() error: %v", err)
+			}
+		})
+	}
+
+	if _, err := shellenv.InstallCompletion("../x", shellenv.Bash, script); !errors.Is(err, shellenv.ErrInvalidAppName) {
+		t.Errorf("InstallCompletion(../x) error = %v", err)
+	}
+	if _, err := shellenv.InstallCompletion("myapp", "tcsh", script); !errors.Is(err, shellenv.ErrUnsupportedShell) {
+		t.Errorf("InstallCompletion(tcsh) error = %v", err)
+	}
+}
+
+func TestInstallCompletionZshrc(t *testing.T) {
+	home := fakeHome(t)
+	zshrc := filepath.Join(home, ".zshrc")
+	orig := "autoload -Uz compinit\r\ncompinit\r\n"
+	if err := os.WriteFile(zshrc, []byte(orig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, app := range []string{"one", "two"} {
+		if _, err := shellenv.InstallCompletion(app, shellenv.Zsh, []byte("#compdef "+app+"\n")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, _ := os.ReadFile(zshrc)
+	got := string(data)
+	if strings.Count(got, "fpath=(") != 1 {
+		t.Errorf(".zshrc has %d fpath lines:\n%s", strings.Count(got, "fpath=("), got)
+	}
+	if strings.Index(got, "fpath=(") > strings.Index(got, "compinit") {
+		t.Errorf("fpath is set after compinit:\n%s", got)
+	}
+	if strings.Contains(strings.ReplaceAll(got, "\r\n", ""), "\n") {
+		t.Errorf(".zshrc lost its CRLF line endings: %q", got)
+	}
+
+	// The block stays until the last script is removed.
+	if err := shellenv.UninstallCompletion("one", shellenv.Zsh); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(zshrc); !strings.Contains(string(data), "fpath=(") {
+		t.Error("fpath block removed while a script remains")
+	}
+	if err := shellenv.UninstallCompletion("two", shellenv.Zsh); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(zshrc); string(data) != orig {
+		t.Errorf(".zshrc after uninstall = %q, want %q", data, orig)
+	}
+}
+
+func TestInstallCompletionPowerShell(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the profile is in the real Documents folder")
+	}
+	fakeHome(t)
+	path, err := shellenv.InstallCompletion("myapp", shellenv.PowerShell, []byte("Register-ArgumentCompleter\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	profile := filepath.Join(filepath.Dir(filepath.Dir(path)), "Microsoft.PowerShell_profile.ps1")
+	data, err := os.ReadFile(profile)
+	if err != nil {
+		t.Fatalf("profile not written: %v", err)
+	}
+	if !strings.Contains(string(data), ". '"+path+"'") {
+		t.Errorf("profile does not load the script:\n%s", data)
+	}
+	if err := shellenv.UninstallCompletion("myapp", shellenv.PowerShell); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(profile); len(data) != 0 {
+		t.Errorf("profile after uninstall = %q", data)
+	}
+}
+
+func TestInstallCompletionPowerShellUTF16(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the profile is in the real Documents folder")
+	}
+	fakeHome(t)
+	script := []byte("Register-ArgumentCompleter\n")
+	path, err := shellenv.InstallCompletion("myapp", shellenv.PowerShell, script)
+	if err != nil {
+		t.Fatal(err)
+	}
+	profile := filepath.Join(filepath.Dir(filepath.Dir(path)), "Microsoft.PowerShell_profile.ps1")
+
+	// A profile saved by Windows PowerShell 5.1 as "Unicode".
+	var buf bytes.Buffer
+	w := text.NewWriter(&buf, text.UTF16LE)
+	if _, err := w.Write([]byte("Set-Alias ll Get-ChildItem\r\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	orig := buf.Bytes()
+	if err := os.WriteFile(profile, orig, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(profile, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := shellenv.InstallCompletion("myapp", shellenv.PowerShell, script); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(profile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(data, []byte{0xFF, 0xFE}) {
+		t.Errorf("profile lost its UTF-16LE byte order mark: % x", data[:min(len(data), 4)])
+	}
+	decoded, err := io.ReadAll(text.NewReader(bytes.NewReader(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(decoded), "Set-Alias ll Get-ChildItem\r\n") ||
+		!strings.Contains(string(decoded), ". '"+path+"'") ||
+		!strings.Contains(string(decoded), "\r\n# <<< myapp completion <<<\r\n") {
+		t.Errorf("decoded profile = %q", decoded)
+	}
+	if info, err := os.Stat(profile); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("profile mode = %v, %v; want 0600", info.Mode().Perm(), err)
+	}
+
+	if err := shellenv.UninstallCompletion("myapp", shellenv.PowerShell); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(profile); !bytes.Equal(data, orig) {
+		t.Errorf("profile after uninstall = % x, want % x", data, orig)
+	}
+}
@@ -0,0 +1,83 @@
+package sysinfo
+
+import (
+	"context"
+	"time"
+)
+
+// Scheme is a light or dark appearance preference.
+type Scheme int
+
+const (
+	// SchemeUnknown means no preference could be read, such as in an
+	// SSH session or a desktop without the setting.
+	SchemeUnknown Scheme = iota
+
+	// SchemeLight means the user prefers dark text on light backgrounds.
+	SchemeLight
+
+	// SchemeDark means the user prefers light text on dark backgrounds.
+	SchemeDark
+)
+
+// String returns the name of the scheme.
+func (s Scheme) String() string {
+	switch s {
+	case SchemeLight:
+		return "Light"
+	case SchemeDark:
+		return "Dark"
+	default:
+		return "Unknown"
+	}
+}
+
+// ColorScheme returns the desktop's light or dark appearance preference,
+// so that tools can pick a matching default palette.
+//
+// Platform sources:
+//   - Windows: AppsUseLightTheme in the user's Personalize settings
+//   - macOS: AppleInterfaceStyle, which is "Dark" in dark mode and
+//     absent otherwise
+//   - Linux and BSD: the color-scheme setting of the freedesktop
+//     appearance portal, over the session bus
+func ColorScheme() Scheme {
+	return colorScheme()
+}
+
+// colorSchemePollInterval is how often WatchColorScheme reads the
+// preference where the platform sends no notification.
+const colorSchemePollInterval = 2 * time.Second
+
+// WatchColorScheme sends the color scheme on the returned channel each
+// time it changes, until ctx is canceled, when the channel is closed. It
+// listens for the portal's SettingChanged signal on Linux and BSD and
+// polls elsewhere.
+func WatchColorScheme(ctx context.Context) <-chan Scheme {
+	ch := make(chan Scheme)
+	watchColorScheme(ctx, ch)
+	return ch
+}
+
+// pollColorScheme sends the changes of ColorScheme until ctx is done and
+// then closes ch.
+func pollColorScheme(ctx context.Context, ch chan<- Scheme, last Scheme) {
+	defer close(ch)
+	ticker := time.NewTicker(colorSchemePollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if s := colorScheme(); s != last {
+			last = s
+			select {
+			case ch <- s:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,29 @@
+//go:build darwin
+
+package sysinfo
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+)
+
+// colorScheme reads AppleInterfaceStyle; defaults fails when the key is
+// absent, which means light mode.
+func colorScheme() Scheme {
+	out, err := exec.Command("defaults", "read", "-g", "AppleInterfaceStyle").Output()
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil && strings.TrimSpace(string(out)) == "Dark":
+		return SchemeDark
+	case err == nil, errors.As(err, &exitErr):
+		return SchemeLight
+	}
+	return SchemeUnknown
+}
+
+// watchColorScheme polls, since change notifications need Cocoa.
+func watchColorScheme(ctx context.Context, ch chan Scheme) {
+	go pollColorScheme(ctx, ch, colorScheme())
+}
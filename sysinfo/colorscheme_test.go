@@ -0,0 +1,46 @@
+package sysinfo_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/grokify/oscompat/sysinfo"
+)
+
+func TestColorScheme(t *testing.T) {
+	s := sysinfo.ColorScheme()
+	switch s {
+	case sysinfo.SchemeUnknown, sysinfo.SchemeLight, sysinfo.SchemeDark:
+	default:
+		t.Fatalf("ColorScheme() = %d", s)
+	}
+	t.Logf("ColorScheme() = %v", s)
+
+	for s, want := range map[sysinfo.Scheme]string{
+		sysinfo.SchemeUnknown: "Unknown",
+		sysinfo.SchemeLight:   "Light",
+		sysinfo.SchemeDark:    "Dark",
+	} {
+		if got := s.String(); got != want {
+			t.Errorf("String() = %q, want %q", got, want)
+		}
+	}
+}
+
+func TestWatchColorScheme(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := sysinfo.WatchColorScheme(ctx)
+	cancel()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case _, ok := <-ch:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatal("channel not closed after cancel")
+		}
+	}
+}
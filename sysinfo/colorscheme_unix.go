@@ -0,0 +1,86 @@
+//go:build !windows && !darwin
+
+package sysinfo
+
+import (
+	"context"
+
+	"github.com/grokify/oscompat/internal/dbus"
+)
+
+const (
+	portalDest     = "org.freedesktop.portal.Desktop"
+	portalPath     = "/org/freedesktop/portal/desktop"
+	portalSettings = "org.freedesktop.portal.Settings"
+	appearance     = "org.freedesktop.appearance"
+	settingChanged = "type='signal',interface='org.freedesktop.portal.Settings',member='SettingChanged',arg0='org.freedesktop.appearance',arg1='color-scheme'"
+)
+
+// colorScheme reads the portal's color-scheme setting.
+func colorScheme() Scheme {
+	c, err := dbus.SessionBus()
+	if err != nil {
+		return SchemeUnknown
+	}
+	defer func() { _ = c.Close() }()
+	reply, err := c.Call(portalDest, portalPath, portalSettings, "Read", "ss", appearance, "color-scheme")
+	if err != nil || len(reply) == 0 {
+		return SchemeUnknown
+	}
+	return portalScheme(reply[0])
+}
+
+// portalScheme maps the portal's value: 0 for no preference, 1 for dark,
+// and 2 for light.
+func portalScheme(v any) Scheme {
+	switch v {
+	case uint32(1):
+		return SchemeDark
+	case uint32(2):
+		return SchemeLight
+	}
+	return SchemeUnknown
+}
+
+// watchColorScheme listens for SettingChanged, or polls where there is
+// no session bus.
+func watchColorScheme(ctx context.Context, ch chan Scheme) {
+	c, err := dbus.SessionBus()
+	if err == nil {
+		if err = c.AddMatch(settingChanged); err != nil {
+			_ = c.Close()
+		}
+	}
+	if err != nil {
+		go pollColorScheme(ctx, ch, colorScheme())
+		return
+	}
+
+	go func() {
+		<-ctx.Done()
+		_ = c.Close()
+	}()
+	go func() {
+		defer close(ch)
+		for {
+			sig, err := c.Signal()
+			if err != nil {
+				return
+			}
+			if sig.Member != "SettingChanged" || len(sig.Body) < 3 {
+				continue
+			}
+			if ns, _ := sig.Body[0].(string); ns != appearance {
+				continue
+			}
+			if key, _ := sig.Body[1].(string); key != "color-scheme" {
+				continue
+			}
+			select {
+			case ch <- portalScheme(sig.Body[2]):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
@@ -0,0 +1,29 @@
+//go:build windows
+
+package sysinfo
+
+import (
+	"context"
+	"syscall"
+)
+
+// personalizeKey holds the user's light and dark mode settings.
+const personalizeKey = `Software\Microsoft\Windows\CurrentVersion\Themes\Personalize`
+
+// colorScheme reads AppsUseLightTheme, which Windows 10 1809 and later
+// write when the app mode is chosen.
+func colorScheme() Scheme {
+	v, ok := regDWORDIn(syscall.HKEY_CURRENT_USER, personalizeKey, "AppsUseLightTheme")
+	switch {
+	case !ok:
+		return SchemeUnknown
+	case v == 0:
+		return SchemeDark
+	}
+	return SchemeLight
+}
+
+// watchColorScheme polls the registry value.
+func watchColorScheme(ctx context.Context, ch chan Scheme) {
+	go pollColorScheme(ctx, ch, colorScheme())
+}
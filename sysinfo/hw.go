@@ -0,0 +1,43 @@
+package sysinfo
+
+import "time"
+
+// MemoryInfo describes physical memory.
+type MemoryInfo struct {
+	// Total is the installed physical memory in bytes.
+	Total uint64
+
+	// Available is an estimate, in bytes, of the memory that can be
+	// allocated without swapping, including reclaimable caches.
+	Available uint64
+}
+
+// CPUInfo describes the processors.
+type CPUInfo struct {
+	// Model is the processor brand string, such as "Intel(R) Core(TM)
+	// i7-9750H CPU @ 2.60GHz" or "Apple M2". It may be empty on some
+	// architectures.
+	Model string
+
+	// Cores is the number of physical cores.
+	Cores int
+
+	// Threads is the number of logical processors. Unlike
+	// runtime.NumCPU, it is not limited by the process's CPU affinity.
+	Threads int
+}
+
+// Memory returns the total and available physical memory.
+func Memory() (*MemoryInfo, error) {
+	return memory()
+}
+
+// CPU returns the processor model and core counts.
+func CPU() (*CPUInfo, error) {
+	return cpu()
+}
+
+// Uptime returns the time since the system booted.
+func Uptime() (time.Duration, error) {
+	return uptime()
+}
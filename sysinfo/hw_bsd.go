@@ -0,0 +1,44 @@
+//go:build darwin || freebsd
+
+package sysinfo
+
+import (
+	"encoding/binary"
+	"syscall"
+	"time"
+)
+
+// sysctlRaw returns the raw bytes of a sysctl value, padded to size.
+// syscall.Sysctl treats values as strings and drops a trailing zero byte,
+// which the padding restores.
+func sysctlRaw(name string, size int) ([]byte, error) {
+	s, err := syscall.Sysctl(name)
+	if err != nil {
+		return nil, err
+	}
+	b := []byte(s)
+	for len(b) < size {
+		b = append(b, 0)
+	}
+	return b, nil
+}
+
+// sysctlUint64 reads a 64-bit integer sysctl.
+func sysctlUint64(name string) (uint64, error) {
+	b, err := sysctlRaw(name, 8)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(b), nil
+}
+
+// uptime subtracts kern.boottime, a struct timeval, from the current time.
+func uptime() (time.Duration, error) {
+	b, err := sysctlRaw("kern.boottime", 16)
+	if err != nil {
+		return 0, err
+	}
+	sec := int64(binary.LittleEndian.Uint64(b[0:8]))
+	usec := int64(binary.LittleEndian.Uint32(b[8:12]))
+	return time.Since(time.Unix(sec, usec*1000)), nil
+}
@@ -0,0 +1,44 @@
+//go:build darwin
+
+package sysinfo
+
+import "syscall"
+
+// memory reads hw.memsize and estimates available memory from the free,
+// speculative, purgeable, and file-backed page counts, roughly what
+// Activity Monitor reports as not in use.
+func memory() (*MemoryInfo, error) {
+	total, err := sysctlUint64("hw.memsize")
+	if err != nil {
+		return nil, err
+	}
+	var pages uint64
+	for _, name := range []string{
+		"vm.page_free_count",
+		"vm.page_speculative_count",
+		"vm.page_purgeable_count",
+		"vm.page_pageable_external_count",
+	} {
+		if n, err := syscall.SysctlUint32(name); err == nil {
+			pages += uint64(n)
+		}
+	}
+	return &MemoryInfo{
+		Total:     total,
+		Available: min(pages*uint64(syscall.Getpagesize()), total),
+	}, nil
+}
+
+// cpu reads the brand string and core counts from sysctl.
+func cpu() (*CPUInfo, error) {
+	threads, err := syscall.SysctlUint32("hw.logicalcpu")
+	if err != nil {
+		return nil, err
+	}
+	cores, err := syscall.SysctlUint32("hw.physicalcpu")
+	if err != nil {
+		cores = threads
+	}
+	model, _ := syscall.Sysctl("machdep.cpu.brand_string")
+	return &CPUInfo{Model: model, Cores: int(cores), Threads: int(threads)}, nil
+}
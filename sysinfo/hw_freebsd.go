@@ -0,0 +1,38 @@
+//go:build freebsd
+
+package sysinfo
+
+import "syscall"
+
+// memory reads hw.physmem and counts free and inactive pages as
+// available.
+func memory() (*MemoryInfo, error) {
+	total, err := sysctlUint64("hw.physmem")
+	if err != nil {
+		return nil, err
+	}
+	var pages uint64
+	for _, name := range []string{"vm.stats.vm.v_free_count", "vm.stats.vm.v_inactive_count"} {
+		if n, err := syscall.SysctlUint32(name); err == nil {
+			pages += uint64(n)
+		}
+	}
+	return &MemoryInfo{
+		Total:     total,
+		Available: min(pages*uint64(syscall.Getpagesize()), total),
+	}, nil
+}
+
+// cpu reads hw.model and the CPU topology counts.
+func cpu() (*CPUInfo, error) {
+	threads, err := syscall.SysctlUint32("hw.ncpu")
+	if err != nil {
+		return nil, err
+	}
+	model, _ := syscall.Sysctl("hw.model")
+	cores := threads
+	if tpc, err := syscall.SysctlUint32("kern.smp.threads_per_core"); err == nil && tpc > 0 {
+		cores = threads / tpc
+	}
+	return &CPUInfo{Model: model, Cores: int(cores), Threads: int(threads)}, nil
+}
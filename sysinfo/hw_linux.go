@@ -0,0 +1,107 @@
+//go:build linux
+
+package sysinfo
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// memory reads /proc/meminfo.
+func memory() (*MemoryInfo, error) {
+	data, err := os.ReadFile("/proc/meminfo")
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]uint64)
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		key, value, ok := strings.Cut(sc.Text(), ":")
+		if !ok {
+			continue
+		}
+		kb, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimSpace(value), " kB"), 10, 64)
+		if err == nil {
+			fields[key] = kb * 1024
+		}
+	}
+
+	m := &MemoryInfo{Total: fields["MemTotal"]}
+	if avail, ok := fields["MemAvailable"]; ok {
+		m.Available = avail
+	} else {
+		// Kernels before 3.14 lack MemAvailable.
+		m.Available = fields["MemFree"] + fields["Buffers"] + fields["Cached"]
+	}
+	return m, nil
+}
+
+// cpu reads /proc/cpuinfo, counting distinct (physical id, core id)
+// pairs as cores.
+func cpu() (*CPUInfo, error) {
+	f, err := os.Open("/proc/cpuinfo")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	c := &CPUInfo{}
+	cores := make(map[[2]string]bool)
+	var physID, coreID string
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		key, value, ok := strings.Cut(sc.Text(), ":")
+		if !ok {
+			// A blank line ends each processor's block.
+			if coreID != "" {
+				cores[[2]string{physID, coreID}] = true
+			}
+			physID, coreID = "", ""
+			continue
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		switch key {
+		case "processor":
+			c.Threads++
+		case "model name", "Model", "cpu model":
+			if c.Model == "" {
+				c.Model = value
+			}
+		case "physical id":
+			physID = value
+		case "core id":
+			coreID = value
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if coreID != "" {
+		cores[[2]string{physID, coreID}] = true
+	}
+
+	c.Cores = len(cores)
+	if c.Cores == 0 {
+		// Some architectures, such as arm64, do not report cores.
+		c.Cores = c.Threads
+	}
+	return c, nil
+}
+
+// uptime reads /proc/uptime.
+func uptime() (time.Duration, error) {
+	data, err := os.ReadFile("/proc/uptime")
+	if err != nil {
+		return 0, err
+	}
+	secs, _, _ := strings.Cut(string(data), " ")
+	s, err := strconv.ParseFloat(secs, 64)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(s * float64(time.Second)), nil
+}
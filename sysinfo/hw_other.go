@@ -0,0 +1,20 @@
+//go:build !windows && !linux && !darwin && !freebsd
+
+package sysinfo
+
+import "time"
+
+// memory is not implemented on this platform.
+func memory() (*MemoryInfo, error) {
+	return nil, ErrUnsupported
+}
+
+// cpu is not implemented on this platform.
+func cpu() (*CPUInfo, error) {
+	return nil, ErrUnsupported
+}
+
+// uptime is not implemented on this platform.
+func uptime() (time.Duration, error) {
+	return 0, ErrUnsupported
+}
@@ -0,0 +1,50 @@
+package sysinfo_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/grokify/oscompat/sysinfo"
+)
+
+func TestMemory(t *testing.T) {
+	m, err := sysinfo.Memory()
+	if errors.Is(err, errors.ErrUnsupported) {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatalf("Memory() error: %v", err)
+	}
+	if m.Total == 0 {
+		t.Error("Memory().Total = 0")
+	}
+	if m.Available > m.Total {
+		t.Errorf("Memory().Available = %d > Total %d", m.Available, m.Total)
+	}
+}
+
+func TestCPU(t *testing.T) {
+	c, err := sysinfo.CPU()
+	if errors.Is(err, errors.ErrUnsupported) {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatalf("CPU() error: %v", err)
+	}
+	if c.Cores <= 0 || c.Threads < c.Cores {
+		t.Errorf("CPU() = %+v, want 0 < Cores <= Threads", c)
+	}
+}
+
+func TestUptime(t *testing.T) {
+	up, err := sysinfo.Uptime()
+	if errors.Is(err, errors.ErrUnsupported) {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatalf("Uptime() error: %v", err)
+	}
+	if up <= 0 {
+		t.Errorf("Uptime() = %v, want positive", up)
+	}
+}
@@ -0,0 +1,90 @@
+//go:build windows
+
+package sysinfo
+
+import (
+	"strings"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+var (
+	kernel32                             = syscall.NewLazyDLL("kernel32.dll")
+	procGlobalMemoryStatusEx             = kernel32.NewProc("GlobalMemoryStatusEx")
+	procGetLogicalProcessorInformationEx = kernel32.NewProc("GetLogicalProcessorInformationEx")
+	procGetActiveProcessorCount          = kernel32.NewProc("GetActiveProcessorCount")
+	procGetTickCount64                   = kernel32.NewProc("GetTickCount64")
+)
+
+const (
+	relationProcessorCore   = 0
+	allProcessorGroups      = 0xffff
+	errorInsufficientBuffer = syscall.Errno(122)
+)
+
+// cpuKey holds the description of the first processor.
+const cpuKey = `HARDWARE\DESCRIPTION\System\CentralProcessor\0`
+
+// memoryStatusEx mirrors MEMORYSTATUSEX.
+type memoryStatusEx struct {
+	Length               uint32
+	MemoryLoad           uint32
+	TotalPhys            uint64
+	AvailPhys            uint64
+	TotalPageFile        uint64
+	AvailPageFile        uint64
+	TotalVirtual         uint64
+	AvailVirtual         uint64
+	AvailExtendedVirtual uint64
+}
+
+// memory calls GlobalMemoryStatusEx.
+func memory() (*MemoryInfo, error) {
+	ms := memoryStatusEx{Length: uint32(unsafe.Sizeof(memoryStatusEx{}))}
+	if r, _, err := procGlobalMemoryStatusEx.Call(uintptr(unsafe.Pointer(&ms))); r == 0 {
+		return nil, err
+	}
+	return &MemoryInfo{Total: ms.TotalPhys, Available: ms.AvailPhys}, nil
+}
+
+// cpu counts processor cores with GetLogicalProcessorInformationEx and
+// reads the brand string from the registry.
+func cpu() (*CPUInfo, error) {
+	var size uint32
+	r, _, err := procGetLogicalProcessorInformationEx.Call(relationProcessorCore, 0, uintptr(unsafe.Pointer(&size)))
+	if r == 0 && err != errorInsufficientBuffer {
+		return nil, err
+	}
+	buf := make([]byte, size)
+	r, _, err = procGetLogicalProcessorInformationEx.Call(relationProcessorCore,
+		uintptr(unsafe.Pointer(&buf[0])), uintptr(unsafe.Pointer(&size)))
+	if r == 0 {
+		return nil, err
+	}
+
+	// Each SYSTEM_LOGICAL_PROCESSOR_INFORMATION_EX entry starts with its
+	// relationship and size; every entry returned here is one core.
+	cores := 0
+	for off := uint32(0); off+8 <= size; {
+		entrySize := *(*uint32)(unsafe.Pointer(&buf[off+4]))
+		if entrySize == 0 {
+			break
+		}
+		cores++
+		off += entrySize
+	}
+
+	threads, _, _ := procGetActiveProcessorCount.Call(allProcessorGroups)
+	return &CPUInfo{
+		Model:   strings.TrimSpace(regString(cpuKey, "ProcessorNameString")),
+		Cores:   cores,
+		Threads: int(threads),
+	}, nil
+}
+
+// uptime calls GetTickCount64.
+func uptime() (time.Duration, error) {
+	ms, _, _ := procGetTickCount64.Call()
+	return time.Duration(ms) * time.Millisecond, nil
+}
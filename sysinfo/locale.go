@@ -0,0 +1,190 @@
+package sysinfo
+
+import (
+	"os"
+	"strings"
+)
+
+// LocaleInfo describes the user's language and regional settings.
+type LocaleInfo struct {
+	// Tag is the BCP 47 language tag of the user's locale, such as
+	// "en-US", "pt-BR", or "sr-Latn-RS".
+	Tag string
+
+	// Language is the language subtag of Tag, such as "en".
+	Language string
+
+	// Region is the region subtag of Tag, such as "US", or "" if the
+	// locale names none.
+	Region string
+
+	// Languages lists the user's preferred display languages as BCP 47
+	// tags, most preferred first. It starts with Tag if nothing more
+	// specific is configured.
+	Languages []string
+
+	// DecimalSeparator and GroupSeparator are the separators of numbers
+	// such as 1,234.5. They are read from the system on Windows and
+	// derived from Tag elsewhere, so they are hints.
+	DecimalSeparator string
+	GroupSeparator   string
+
+	// DateOrder is the order of day, month and year in short dates:
+	// "MDY", "DMY", or "YMD". Like the separators, it is read from the
+	// system on Windows and derived from Tag elsewhere.
+	DateOrder string
+}
+
+// Locale returns the user's locale.
+//
+// Platform sources:
+//   - Unix: LC_ALL, LC_MESSAGES, and LANG, in that order, with LANGUAGE
+//     for the preferred languages; LC_NUMERIC and LC_TIME for the
+//     separators and date order. The C and POSIX locales report "en".
+//   - macOS: the same variables when set, and otherwise the AppleLocale
+//     and AppleLanguages preferences, which GUI apps see instead of LANG
+//   - Windows: GetUserDefaultLocaleName, GetUserPreferredUILanguages,
+//     and GetLocaleInfoEx; the separators and date order reflect the
+//     user's customizations in Region settings
+func Locale() (*LocaleInfo, error) {
+	return locale()
+}
+
+// newLocaleInfo fills a LocaleInfo for tag with hints derived from it.
+func newLocaleInfo(tag string) *LocaleInfo {
+	l := &LocaleInfo{Tag: tag}
+	l.Language, l.Region = splitTag(tag)
+	l.Languages = []string{tag}
+	l.DecimalSeparator, l.GroupSeparator = numberHints(l.Language, l.Region)
+	l.DateOrder = dateOrderHint(l.Language, l.Region)
+	return l
+}
+
+// splitTag returns the language and region subtags of a BCP 47 tag.
+func splitTag(tag string) (lang, region string) {
+	parts := strings.Split(tag, "-")
+	lang = strings.ToLower(parts[0])
+	for _, p := range parts[1:] {
+		if len(p) == 2 || (len(p) == 3 && p[0] >= '0' && p[0] <= '9') {
+			return lang, strings.ToUpper(p)
+		}
+	}
+	return lang, ""
+}
+
+// localeFromEnv builds a LocaleInfo from the POSIX locale variables, or
+// returns nil if none of them names a locale.
+func localeFromEnv() *LocaleInfo {
+	tag := posixToBCP47(envLocale("LC_ALL", "LC_MESSAGES", "LANG"))
+	if tag == "" {
+		return nil
+	}
+	l := newLocaleInfo(tag)
+	// GNU gettext ignores LANGUAGE in the C locale.
+	if list := os.Getenv("LANGUAGE"); list != "" && tag != "en" {
+		l.Languages = nil
+		for _, name := range strings.Split(list, ":") {
+			if t := posixToBCP47(name); t != "" {
+				l.Languages = append(l.Languages, t)
+			}
+		}
+	}
+	if t := posixToBCP47(envLocale("LC_ALL", "LC_NUMERIC", "LANG")); t != "" {
+		l.DecimalSeparator, l.GroupSeparator = numberHints(splitTag(t))
+	}
+	if t := posixToBCP47(envLocale("LC_ALL", "LC_TIME", "LANG")); t != "" {
+		l.DateOrder = dateOrderHint(splitTag(t))
+	}
+	return l
+}
+
+// envLocale returns the first of the variables that names a locale.
+func envLocale(names ...string) string {
+	for _, name := range names {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// posixToBCP47 converts a POSIX locale name such as "sr_RS.UTF-8@latin"
+// to a BCP 47 tag such as "sr-Latn-RS". It returns "" for an empty
+// name, and "en" for the C and POSIX locales.
+func posixToBCP47(name string) string {
+	name, modifier, _ := strings.Cut(name, "@")
+	name, _, _ = strings.Cut(name, ".")
+	switch name {
+	case "":
+		return ""
+	case "C", "POSIX":
+		return "en"
+	}
+	lang, region, _ := strings.Cut(name, "_")
+	tag := strings.ToLower(lang)
+	switch modifier {
+	case "latin":
+		tag += "-Latn"
+	case "cyrillic":
+		tag += "-Cyrl"
+	}
+	if region != "" {
+		tag += "-" + strings.ToUpper(region)
+	}
+	return tag
+}
+
+// Regions whose conventions differ from those of their language.
+var (
+	// dotDecimalRegions write 1,234.5 even where the language elsewhere
+	// uses a decimal comma.
+	dotDecimalRegions = map[string]bool{
+		"US": true, "GB": true, "IE": true, "AU": true, "NZ": true,
+		"IN": true, "PK": true, "CN": true, "HK": true, "TW": true, "SG": true,
+		"MY": true, "PH": true, "JP": true, "KR": true, "TH": true, "IL": true,
+		"MX": true, "GT": true, "DO": true, "PR": true, "PE": true,
+	}
+	// dotDecimalLanguages write 1,234.5 unless the region says otherwise.
+	dotDecimalLanguages = map[string]bool{
+		"en": true, "zh": true, "ja": true, "ko": true, "th": true, "he": true,
+		"hi": true, "ur": true, "ms": true, "fil": true, "ga": true,
+	}
+	// spaceGroupLanguages group digits with a no-break space, as in
+	// 1 234,5, rather than a period.
+	spaceGroupLanguages = map[string]bool{
+		"fr": true, "ru": true, "uk": true, "be": true, "pl": true, "cs": true,
+		"sk": true, "sv": true, "fi": true, "nb": true, "no": true, "nn": true,
+		"et": true, "lv": true, "lt": true, "hu": true, "bg": true, "kk": true,
+	}
+	// ymdRegions and ymdLanguages write short dates year first.
+	ymdRegions   = map[string]bool{"CA": true, "CN": true, "JP": true, "KR": true, "TW": true, "HU": true, "LT": true, "MN": true}
+	ymdLanguages = map[string]bool{"zh": true, "ja": true, "ko": true, "hu": true, "lt": true, "sv": true, "mn": true}
+	// mdyRegions write short dates month first.
+	mdyRegions = map[string]bool{"US": true, "PH": true, "PR": true, "FM": true, "MH": true, "PW": true}
+)
+
+// numberHints guesses the decimal and group separators of a locale.
+func numberHints(lang, region string) (decimal, group string) {
+	switch {
+	case region == "CH" && lang != "fr":
+		return ".", "’"
+	case region == "ZA":
+		return ",", "\u00a0"
+	case dotDecimalRegions[region], dotDecimalLanguages[lang]:
+		return ".", ","
+	case spaceGroupLanguages[lang]:
+		return ",", "\u00a0"
+	}
+	return ",", "."
+}
+
+// dateOrderHint guesses the order of short dates of a locale.
+func dateOrderHint(lang, region string) string {
+	switch {
+	case mdyRegions[region], region == "" && lang == "en":
+		return "MDY"
+	case ymdRegions[region], ymdLanguages[lang]:
+		return "YMD"
+	}
+	return "DMY"
+}
@@ -0,0 +1,56 @@
+//go:build darwin
+
+package sysinfo
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// locale prefers the POSIX locale variables, which Terminal sets from the
+// preferences, and otherwise reads the preferences themselves.
+func locale() (*LocaleInfo, error) {
+	if l := localeFromEnv(); l != nil {
+		return l, nil
+	}
+	// AppleLocale looks like "en_US" or "en_GB@rg=dezzzz" when the
+	// region format differs from the language's.
+	appleLocale := strings.TrimSpace(readDefault("AppleLocale"))
+	tag := posixToBCP47(appleLocale)
+	if tag == "" {
+		tag = "en"
+	}
+	l := newLocaleInfo(tag)
+	if _, mod, ok := strings.Cut(appleLocale, "@rg="); ok && len(mod) >= 2 {
+		region := strings.ToUpper(mod[:2])
+		l.DecimalSeparator, l.GroupSeparator = numberHints(l.Language, region)
+		l.DateOrder = dateOrderHint(l.Language, region)
+	}
+	if langs := parseDefaultsArray(readDefault("AppleLanguages")); len(langs) > 0 {
+		l.Languages = langs
+	}
+	return l, nil
+}
+
+// readDefault reads a key of the global preferences domain.
+func readDefault(key string) string {
+	out, err := exec.Command("defaults", "read", "-g", key).Output()
+	if err != nil {
+		return ""
+	}
+	return string(out)
+}
+
+// parseDefaultsArray parses an array printed by defaults read, such as
+// ("en-US", "de-DE").
+func parseDefaultsArray(s string) []string {
+	s = strings.TrimSpace(s)
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "("), ")")
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.Trim(strings.TrimSpace(item), `"`); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -0,0 +1,59 @@
+package sysinfo_test
+
+import (
+	"runtime"
+	"slices"
+	"testing"
+
+	"github.com/grokify/oscompat/sysinfo"
+)
+
+func TestLocale(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		l, err := sysinfo.Locale()
+		if err != nil {
+			t.Fatalf("Locale() error: %v", err)
+		}
+		if l.Tag == "" || l.Language == "" || l.DecimalSeparator == "" || l.DateOrder == "" {
+			t.Errorf("Locale() = %+v", l)
+		}
+		return
+	}
+
+	tests := []struct {
+		name      string
+		env       map[string]string
+		tag       string
+		region    string
+		languages []string
+		decimal   string
+		dateOrder string
+	}{
+		{"lang", map[string]string{"LANG": "de_DE.UTF-8"}, "de-DE", "DE", []string{"de-DE"}, ",", "DMY"},
+		{"us", map[string]string{"LANG": "en_US.UTF-8"}, "en-US", "US", []string{"en-US"}, ".", "MDY"},
+		{"lc_all wins", map[string]string{"LANG": "en_US.UTF-8", "LC_ALL": "sr_RS.UTF-8@latin"}, "sr-Latn-RS", "RS", []string{"sr-Latn-RS"}, ",", "DMY"},
+		{"c locale", map[string]string{"LANG": "C.UTF-8", "LANGUAGE": "fr"}, "en", "", []string{"en"}, ".", "MDY"},
+		{"language list", map[string]string{"LANG": "fr_CA.UTF-8", "LANGUAGE": "fr_CA:fr:en"}, "fr-CA", "CA", []string{"fr-CA", "fr", "en"}, ",", "YMD"},
+		{"lc_numeric", map[string]string{"LANG": "de_DE.UTF-8", "LC_NUMERIC": "en_GB.UTF-8", "LC_TIME": "ja_JP.UTF-8"}, "de-DE", "DE", []string{"de-DE"}, ".", "YMD"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range []string{"LANG", "LANGUAGE", "LC_ALL", "LC_MESSAGES", "LC_NUMERIC", "LC_TIME"} {
+				t.Setenv(name, tt.env[name])
+			}
+			l, err := sysinfo.Locale()
+			if err != nil {
+				t.Fatalf("Locale() error: %v", err)
+			}
+			if l.Tag != tt.tag || l.Region != tt.region {
+				t.Errorf("Tag, Region = %q, %q, want %q, %q", l.Tag, l.Region, tt.tag, tt.region)
+			}
+			if !slices.Equal(l.Languages, tt.languages) {
+				t.Errorf("Languages = %q, want %q", l.Languages, tt.languages)
+			}
+			if l.DecimalSeparator != tt.decimal || l.DateOrder != tt.dateOrder {
+				t.Errorf("DecimalSeparator, DateOrder = %q, %q, want %q, %q", l.DecimalSeparator, l.DateOrder, tt.decimal, tt.dateOrder)
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+//go:build !windows && !darwin
+
+package sysinfo
+
+// locale reads the POSIX locale variables. With none set, programs run
+// in the C locale.
+func locale() (*LocaleInfo, error) {
+	if l := localeFromEnv(); l != nil {
+		return l, nil
+	}
+	return newLocaleInfo("en"), nil
+}
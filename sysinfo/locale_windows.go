@@ -0,0 +1,105 @@
+//go:build windows
+
+package sysinfo
+
+import (
+	"strings"
+	"syscall"
+	"unicode/utf16"
+	"unsafe"
+)
+
+var (
+	procGetUserDefaultLocaleName    = kernel32.NewProc("GetUserDefaultLocaleName")
+	procGetUserPreferredUILanguages = kernel32.NewProc("GetUserPreferredUILanguages")
+	procGetLocaleInfoEx             = kernel32.NewProc("GetLocaleInfoEx")
+)
+
+const (
+	localeNameMaxLength = 85
+	muiLanguageName     = 0x8
+	localeSDecimal      = 0x0E
+	localeSThousand     = 0x0F
+	localeSShortDate    = 0x1F
+)
+
+// locale reads the user locale, which Windows names with BCP 47 tags,
+// and its number and date formats, including user customizations.
+func locale() (*LocaleInfo, error) {
+	buf := make([]uint16, localeNameMaxLength)
+	if r, _, err := procGetUserDefaultLocaleName.Call(uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf))); r == 0 {
+		return nil, err
+	}
+	l := newLocaleInfo(syscall.UTF16ToString(buf))
+	if langs := preferredUILanguages(); len(langs) > 0 {
+		l.Languages = langs
+	}
+	if s := localeInfo(&buf[0], localeSDecimal); s != "" {
+		l.DecimalSeparator = s
+	}
+	if s := localeInfo(&buf[0], localeSThousand); s != "" {
+		l.GroupSeparator = s
+	}
+	if order := dateOrder(localeInfo(&buf[0], localeSShortDate)); order != "" {
+		l.DateOrder = order
+	}
+	return l, nil
+}
+
+// preferredUILanguages returns the user's display languages.
+func preferredUILanguages() []string {
+	var num, size uint32
+	if r, _, _ := procGetUserPreferredUILanguages.Call(muiLanguageName, uintptr(unsafe.Pointer(&num)), 0, uintptr(unsafe.Pointer(&size))); r == 0 || size == 0 {
+		return nil
+	}
+	buf := make([]uint16, size)
+	if r, _, _ := procGetUserPreferredUILanguages.Call(muiLanguageName, uintptr(unsafe.Pointer(&num)),
+		uintptr(unsafe.Pointer(&buf[0])), uintptr(unsafe.Pointer(&size))); r == 0 {
+		return nil
+	}
+	// The names form a list of NUL-terminated strings ending in an
+	// empty one.
+	var langs []string
+	for _, name := range strings.Split(string(utf16.Decode(buf)), "\x00") {
+		if name != "" {
+			langs = append(langs, name)
+		}
+	}
+	return langs
+}
+
+// localeInfo returns a string property of the locale named by name.
+func localeInfo(name *uint16, lctype uint32) string {
+	buf := make([]uint16, 80)
+	if r, _, _ := procGetLocaleInfoEx.Call(uintptr(unsafe.Pointer(name)), uintptr(lctype),
+		uintptr(unsafe.Pointer(&buf[0])), uintptr(len(buf))); r == 0 {
+		return ""
+	}
+	return syscall.UTF16ToString(buf)
+}
+
+// dateOrder derives "MDY", "DMY", or "YMD" from a short date pattern
+// such as "M/d/yyyy".
+func dateOrder(pattern string) string {
+	var order []byte
+	for _, c := range pattern {
+		var part byte
+		switch c {
+		case 'd':
+			part = 'D'
+		case 'M':
+			part = 'M'
+		case 'y':
+			part = 'Y'
+		default:
+			continue
+		}
+		if len(order) == 0 || order[len(order)-1] != part {
+			order = append(order, part)
+		}
+	}
+	if len(order) != 3 {
+		return ""
+	}
+	return string(order)
+}
@@ -0,0 +1,28 @@
+//go:build freebsd || netbsd || openbsd || dragonfly
+
+package sysinfo
+
+import (
+	"runtime"
+	"syscall"
+)
+
+// detectOS reads the kernel name and release from sysctl.
+func detectOS() (*OSInfo, error) {
+	name, err := syscall.Sysctl("kern.ostype")
+	if err != nil {
+		return nil, err
+	}
+	release, err := syscall.Sysctl("kern.osrelease")
+	if err != nil {
+		return nil, err
+	}
+	info := &OSInfo{
+		ID:      runtime.GOOS,
+		Name:    name + " " + release,
+		Version: release,
+		Kernel:  release,
+	}
+	info.Major, info.Minor, info.Build = parseVersion(release)
+	return info, nil
+}
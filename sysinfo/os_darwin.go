@@ -0,0 +1,23 @@
+//go:build darwin
+
+package sysinfo
+
+import "syscall"
+
+// detectOS reads the product version and build from sysctl, which is
+// where sw_vers gets them.
+func detectOS() (*OSInfo, error) {
+	version, err := syscall.Sysctl("kern.osproductversion")
+	if err != nil {
+		return nil, err
+	}
+	info := &OSInfo{
+		ID:      "macos",
+		Name:    "macOS " + version,
+		Version: version,
+	}
+	info.BuildID, _ = syscall.Sysctl("kern.osversion")
+	info.Kernel, _ = syscall.Sysctl("kern.osrelease")
+	info.Major, info.Minor, info.Build = parseVersion(version)
+	return info, nil
+}
@@ -0,0 +1,85 @@
+//go:build linux
+
+package sysinfo
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// osReleasePaths are read in order, as specified by os-release(5).
+var osReleasePaths = []string{"/etc/os-release", "/usr/lib/os-release"}
+
+// detectOS reads os-release and the kernel release from uname.
+func detectOS() (*OSInfo, error) {
+	info := &OSInfo{Name: "Linux"}
+
+	var uts syscall.Utsname
+	if err := syscall.Uname(&uts); err != nil {
+		return nil, err
+	}
+	info.Kernel = utsString(uts.Release[:])
+	info.Major, info.Minor, info.Build = parseVersion(info.Kernel)
+
+	for _, path := range osReleasePaths {
+		fields, err := readOSRelease(path)
+		if err != nil {
+			continue
+		}
+		info.ID = fields["ID"]
+		info.Version = fields["VERSION_ID"]
+		info.BuildID = fields["BUILD_ID"]
+		switch {
+		case fields["PRETTY_NAME"] != "":
+			info.Name = fields["PRETTY_NAME"]
+		case fields["NAME"] != "":
+			info.Name = strings.TrimSpace(fields["NAME"] + " " + info.Version)
+		}
+		break
+	}
+	return info, nil
+}
+
+// readOSRelease parses the KEY=value lines of an os-release file.
+func readOSRelease(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	fields := make(map[string]string)
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		if unq, err := strconv.Unquote(value); err == nil {
+			value = unq
+		} else {
+			value = strings.Trim(value, `'"`)
+		}
+		fields[key] = value
+	}
+	return fields, sc.Err()
+}
+
+// utsString converts a NUL-terminated Utsname field.
+func utsString[T int8 | uint8](b []T) string {
+	buf := make([]byte, 0, len(b))
+	for _, c := range b {
+		if c == 0 {
+			break
+		}
+		buf = append(buf, byte(c))
+	}
+	return string(buf)
+}
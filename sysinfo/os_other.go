@@ -0,0 +1,11 @@
+//go:build !windows && !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly
+
+package sysinfo
+
+import "runtime"
+
+// detectOS reports only the GOOS name on platforms without a version
+// source.
+func detectOS() (*OSInfo, error) {
+	return &OSInfo{ID: runtime.GOOS, Name: runtime.GOOS}, nil
+}
@@ -0,0 +1,71 @@
+package sysinfo_test
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/grokify/oscompat/sysinfo"
+)
+
+func TestOS(t *testing.T) {
+	info, err := sysinfo.OS()
+	if err != nil {
+		t.Fatalf("OS() error: %v", err)
+	}
+	if info.ID == "" || info.Name == "" {
+		t.Errorf("OS() = %+v, want ID and Name", info)
+	}
+	switch runtime.GOOS {
+	case "linux", "darwin", "windows":
+		if info.Major == 0 || info.Kernel == "" {
+			t.Errorf("OS() = %+v, want a version and kernel", info)
+		}
+	}
+	if runtime.GOOS == "windows" && info.ID != "windows" {
+		t.Errorf("OS().ID = %q, want windows", info.ID)
+	}
+	if runtime.GOOS == "darwin" && info.ID != "macos" {
+		t.Errorf("OS().ID = %q, want macos", info.ID)
+	}
+
+	// The result is a copy.
+	info.Major = -1
+	if again, _ := sysinfo.OS(); again.Major == -1 {
+		t.Error("OS() returned shared state")
+	}
+}
+
+func TestAtLeast(t *testing.T) {
+	if !sysinfo.AtLeast(0, 0, 0) {
+		t.Error("AtLeast(0, 0, 0) = false")
+	}
+	if sysinfo.AtLeast(1000, 0, 0) {
+		t.Error("AtLeast(1000, 0, 0) = true")
+	}
+}
+
+func TestOSInfoAtLeast(t *testing.T) {
+	info := &sysinfo.OSInfo{Major: 10, Minor: 0, Build: 17763}
+	tests := []struct {
+		major, minor, build int
+		want                bool
+	}{
+		{10, 0, 17063, true},
+		{10, 0, 17763, true},
+		{10, 0, 22000, false},
+		{6, 3, 99999, true},
+		{11, 0, 0, false},
+		{10, 1, 0, false},
+	}
+	for _, tt := range tests {
+		if got := info.AtLeast(tt.major, tt.minor, tt.build); got != tt.want {
+			t.Errorf("AtLeast(%d, %d, %d) = %v, want %v", tt.major, tt.minor, tt.build, got, tt.want)
+		}
+	}
+}
+
+func TestIsWindowsServer(t *testing.T) {
+	if runtime.GOOS != "windows" && sysinfo.IsWindowsServer() {
+		t.Error("IsWindowsServer() = true outside Windows")
+	}
+}
@@ -0,0 +1,76 @@
+//go:build windows
+
+package sysinfo
+
+import (
+	"strconv"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	ntdll             = syscall.NewLazyDLL("ntdll.dll")
+	procRtlGetVersion = ntdll.NewProc("RtlGetVersion")
+)
+
+// verNTWorkstation is the OSVERSIONINFOEXW.wProductType of client
+// editions.
+const verNTWorkstation = 1
+
+// osVersionInfoEx mirrors OSVERSIONINFOEXW.
+type osVersionInfoEx struct {
+	OSVersionInfoSize uint32
+	MajorVersion      uint32
+	MinorVersion      uint32
+	BuildNumber       uint32
+	PlatformID        uint32
+	CSDVersion        [128]uint16
+	ServicePackMajor  uint16
+	ServicePackMinor  uint16
+	SuiteMask         uint16
+	ProductType       byte
+	Reserved          byte
+}
+
+// detectOS calls RtlGetVersion, which, unlike GetVersionEx, is not
+// subject to manifest-based version lies, and reads the marketing name
+// from the registry.
+func detectOS() (*OSInfo, error) {
+	var vi osVersionInfoEx
+	vi.OSVersionInfoSize = uint32(unsafe.Sizeof(vi))
+	if r, _, _ := procRtlGetVersion.Call(uintptr(unsafe.Pointer(&vi))); r != 0 {
+		return nil, syscall.Errno(r)
+	}
+
+	info := &OSInfo{
+		ID:     "windows",
+		Major:  int(vi.MajorVersion),
+		Minor:  int(vi.MinorVersion),
+		Build:  int(vi.BuildNumber),
+		Server: vi.ProductType != verNTWorkstation,
+	}
+	info.Version = strconv.Itoa(info.Major) + "." + strconv.Itoa(info.Minor) + "." + strconv.Itoa(info.Build)
+	info.Kernel = info.Version
+	info.BuildID = strconv.Itoa(info.Build)
+
+	name := regString(currentVersionKey, "ProductName")
+	if name == "" {
+		name = "Windows"
+		if info.Server {
+			name = "Windows Server"
+		}
+	}
+	// Windows 11 kept "Windows 10" in ProductName.
+	if !info.Server && info.Build >= 22000 {
+		name = strings.Replace(name, "Windows 10", "Windows 11", 1)
+	}
+	info.Name = name
+	if ubr, ok := regDWORD(currentVersionKey, "UBR"); ok {
+		info.BuildID += "." + strconv.FormatUint(uint64(ubr), 10)
+	}
+	return info, nil
+}
+
+// currentVersionKey is the registry key holding Windows version details.
+const currentVersionKey = `SOFTWARE\Microsoft\Windows NT\CurrentVersion`
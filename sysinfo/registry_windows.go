@@ -0,0 +1,64 @@
+//go:build windows
+
+package sysinfo
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// regString reads a REG_SZ value under HKEY_LOCAL_MACHINE, returning ""
+// if it does not exist.
+func regString(key, name string) string {
+	buf := make([]uint16, 256)
+	n := uint32(len(buf) * 2)
+	var typ uint32
+	if !regQuery(key, name, &typ, (*byte)(unsafe.Pointer(&buf[0])), &n) || typ != syscall.REG_SZ {
+		return ""
+	}
+	return syscall.UTF16ToString(buf)
+}
+
+// regDWORD reads a REG_DWORD value under HKEY_LOCAL_MACHINE.
+func regDWORD(key, name string) (uint32, bool) {
+	return regDWORDIn(syscall.HKEY_LOCAL_MACHINE, key, name)
+}
+
+// regDWORDIn reads a REG_DWORD value under root.
+func regDWORDIn(root syscall.Handle, key, name string) (uint32, bool) {
+	var v, typ uint32
+	n := uint32(4)
+	if !regQueryIn(root, key, name, &typ, (*byte)(unsafe.Pointer(&v)), &n) || typ != syscall.REG_DWORD {
+		return 0, false
+	}
+	return v, true
+}
+
+// regExists reports whether a value exists under HKEY_LOCAL_MACHINE.
+func regExists(key, name string) bool {
+	var typ, n uint32
+	return regQuery(key, name, &typ, nil, &n)
+}
+
+// regQuery reads a raw value under HKEY_LOCAL_MACHINE.
+func regQuery(key, name string, typ *uint32, buf *byte, n *uint32) bool {
+	return regQueryIn(syscall.HKEY_LOCAL_MACHINE, key, name, typ, buf, n)
+}
+
+// regQueryIn reads a raw value under root.
+func regQueryIn(root syscall.Handle, key, name string, typ *uint32, buf *byte, n *uint32) bool {
+	keyName, err := syscall.UTF16PtrFromString(key)
+	if err != nil {
+		return false
+	}
+	var h syscall.Handle
+	if syscall.RegOpenKeyEx(root, keyName, 0, syscall.KEY_READ, &h) != nil {
+		return false
+	}
+	defer func() { _ = syscall.RegCloseKey(h) }()
+	valueName, err := syscall.UTF16PtrFromString(name)
+	if err != nil {
+		return false
+	}
+	return syscall.RegQueryValueEx(h, valueName, nil, typ, buf, n) == nil
+}
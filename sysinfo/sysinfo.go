@@ -0,0 +1,132 @@
+// Package sysinfo provides cross-platform information about the operating
+// system and machine.
+//
+// Each platform reports its version differently: Linux distributions in
+// /etc/os-release, macOS through sysctl (the data behind sw_vers), and
+// Windows through RtlGetVersion, since GetVersionEx lies to programs
+// without a compatibility manifest. This package normalizes them into one
+// structure suitable for feature gating.
+package sysinfo
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ErrUnsupported is returned when information is not available on the
+// current platform. It matches errors.ErrUnsupported.
+var ErrUnsupported = fmt.Errorf("oscompat/sysinfo: %w", errors.ErrUnsupported)
+
+// OSInfo describes the running operating system.
+type OSInfo struct {
+	// ID is a lowercase identifier: the os-release ID on Linux (such as
+	// "ubuntu" or "alpine"), and "macos", "windows", or runtime.GOOS
+	// elsewhere.
+	ID string
+
+	// Name is a human-readable name including the version, such as
+	// "Ubuntu 22.04.3 LTS", "macOS 14.2.1", or "Windows 11 Pro".
+	Name string
+
+	// Version is the distribution or product version, such as "22.04",
+	// "14.2.1", or "10.0.22631".
+	Version string
+
+	// BuildID is the product build identifier where one exists, such as
+	// "23C71" on macOS or "22631.2861" on Windows.
+	BuildID string
+
+	// Kernel is the kernel release, such as "6.5.0-14-generic" on Linux
+	// or "23.2.0" (Darwin) on macOS.
+	Kernel string
+
+	// Major, Minor, and Build are the platform version compared by
+	// AtLeast: the kernel version on Linux and the BSDs, the product
+	// version on macOS (14.2.1), and the NT version and build number on
+	// Windows (10.0.22631).
+	Major, Minor, Build int
+
+	// Server reports whether this is a server edition. Only Windows
+	// distinguishes server editions; it is false elsewhere.
+	Server bool
+}
+
+// AtLeast reports whether the platform version is at least
+// major.minor.build.
+func (o *OSInfo) AtLeast(major, minor, build int) bool {
+	if o.Major != major {
+		return o.Major > major
+	}
+	if o.Minor != minor {
+		return o.Minor > minor
+	}
+	return o.Build >= build
+}
+
+// osInfo caches the result of detectOS; the OS does not change while the
+// program runs.
+var osInfo = sync.OnceValues(func() (*OSInfo, error) {
+	info, err := detectOS()
+	if err != nil {
+		return nil, err
+	}
+	if info.ID == "" {
+		info.ID = runtime.GOOS
+	}
+	return info, nil
+})
+
+// OS returns information about the running operating system.
+func OS() (*OSInfo, error) {
+	info, err := osInfo()
+	if err != nil {
+		return nil, err
+	}
+	c := *info
+	return &c, nil
+}
+
+// AtLeast reports whether the running platform version is at least
+// major.minor.build (see OSInfo). It returns false if the version cannot
+// be determined.
+//
+// Example:
+//
+//	// AF_UNIX sockets arrived in Windows 10 build 17063.
+//	if runtime.GOOS == "windows" && sysinfo.AtLeast(10, 0, 17063) { ... }
+func AtLeast(major, minor, build int) bool {
+	info, err := osInfo()
+	return err == nil && info.AtLeast(major, minor, build)
+}
+
+// IsWindowsServer reports whether the program runs on a Windows Server
+// edition.
+func IsWindowsServer() bool {
+	info, err := osInfo()
+	return err == nil && info.Server
+}
+
+// parseVersion extracts up to three leading numeric components from a
+// version string such as "6.5.0-14-generic" or "14.2".
+func parseVersion(s string) (major, minor, build int) {
+	var parts [3]int
+	for i := range parts {
+		end := strings.IndexFunc(s, func(r rune) bool { return r < '0' || r > '9' })
+		if end < 0 {
+			end = len(s)
+		}
+		if end == 0 {
+			break
+		}
+		parts[i], _ = strconv.Atoi(s[:end])
+		if end == len(s) || s[end] != '.' {
+			break
+		}
+		s = s[end+1:]
+	}
+	return parts[0], parts[1], parts[2]
+}
@@ -0,0 +1,81 @@
+package sysinfo
+
+import (
+	"strings"
+	"sync"
+)
+
+// Container runtimes reported by ContainerRuntime.
+const (
+	RuntimeDocker     = "docker"
+	RuntimePodman     = "podman"
+	RuntimeKubernetes = "kubernetes"
+	RuntimeContainerd = "containerd"
+	RuntimeLXC        = "lxc"
+	RuntimeNspawn     = "systemd-nspawn"
+	RuntimeJail       = "jail"
+
+	// RuntimeUnknown is reported for containers whose runtime cannot be
+	// identified, including Windows containers.
+	RuntimeUnknown = "container"
+)
+
+// containerRuntime caches the result of detectContainer.
+var containerRuntime = sync.OnceValue(detectContainer)
+
+// IsContainer reports whether the program runs inside a container (or a
+// FreeBSD jail).
+func IsContainer() bool {
+	return containerRuntime() != ""
+}
+
+// ContainerRuntime returns the container runtime the program runs under,
+// one of the Runtime constants, or "" outside a container. Detection is
+// heuristic: on Linux it checks /.dockerenv, /run/.containerenv, the
+// $container variable set by podman, LXC, and systemd-nspawn, Kubernetes
+// service variables, and the cgroup of the process.
+func ContainerRuntime() string {
+	return containerRuntime()
+}
+
+// isWSL caches the result of detectWSL.
+var isWSL = sync.OnceValue(detectWSL)
+
+// IsWSL reports whether the program is a Linux binary running under the
+// Windows Subsystem for Linux.
+func IsWSL() bool {
+	return isWSL()
+}
+
+// isVM caches the result of detectVM.
+var isVM = sync.OnceValue(detectVM)
+
+// IsVM reports whether the machine is a virtual machine, judged by the
+// hypervisor CPU flag or the SMBIOS vendor and product names. WSL 2 runs
+// in a lightweight VM and is reported as one. Containers share the host
+// kernel, so IsVM reports on the host.
+func IsVM() bool {
+	return isVM()
+}
+
+// hypervisorVendors are substrings of SMBIOS vendor and product names
+// that identify virtual machines.
+var hypervisorVendors = []string{
+	"qemu", "kvm", "vmware", "virtualbox", "innotek", "xen", "bochs",
+	"parallels", "bhyve", "amazon ec2", "google compute engine",
+	"virtual machine", "openstack", "hvm domu",
+}
+
+// isHypervisorVendor reports whether any of the SMBIOS strings names a
+// hypervisor.
+func isHypervisorVendor(fields ...string) bool {
+	for _, f := range fields {
+		f = strings.ToLower(f)
+		for _, v := range hypervisorVendors {
+			if strings.Contains(f, v) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,26 @@
+//go:build darwin
+
+package sysinfo
+
+import "syscall"
+
+// detectContainer reports no container; macOS has no native containers.
+func detectContainer() string {
+	return ""
+}
+
+// detectWSL is false outside Linux.
+func detectWSL() bool {
+	return false
+}
+
+// detectVM reads kern.hv_vmm_present, which the kernel sets when running
+// under a hypervisor.
+func detectVM() bool {
+	v, err := syscall.SysctlUint32("kern.hv_vmm_present")
+	if err == nil && v != 0 {
+		return true
+	}
+	model, _ := syscall.Sysctl("hw.model")
+	return isHypervisorVendor(model)
+}
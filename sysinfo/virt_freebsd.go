@@ -0,0 +1,24 @@
+//go:build freebsd
+
+package sysinfo
+
+import "syscall"
+
+// detectContainer reports jails.
+func detectContainer() string {
+	if v, err := syscall.SysctlUint32("security.jail.jailed"); err == nil && v != 0 {
+		return RuntimeJail
+	}
+	return ""
+}
+
+// detectWSL is false outside Linux.
+func detectWSL() bool {
+	return false
+}
+
+// detectVM reads kern.vm_guest, which names the detected hypervisor.
+func detectVM() bool {
+	guest, err := syscall.Sysctl("kern.vm_guest")
+	return err == nil && guest != "" && guest != "none"
+}
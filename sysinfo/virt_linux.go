@@ -0,0 +1,82 @@
+//go:build linux
+
+package sysinfo
+
+import (
+	"bytes"
+	"os"
+	"strings"
+)
+
+// detectContainer checks the markers container runtimes leave behind.
+func detectContainer() string {
+	switch {
+	case fileExists("/.dockerenv"):
+		return RuntimeDocker
+	case fileExists("/run/.containerenv"):
+		return RuntimePodman
+	}
+	switch c := os.Getenv("container"); c {
+	case "":
+	case "podman", "docker", "lxc":
+		return c
+	case "systemd-nspawn":
+		return RuntimeNspawn
+	default:
+		return RuntimeUnknown
+	}
+	if os.Getenv("KUBERNETES_SERVICE_HOST") != "" {
+		return RuntimeKubernetes
+	}
+
+	cgroup, _ := os.ReadFile("/proc/1/cgroup")
+	switch {
+	case bytes.Contains(cgroup, []byte("kubepods")):
+		return RuntimeKubernetes
+	case bytes.Contains(cgroup, []byte("docker")):
+		return RuntimeDocker
+	case bytes.Contains(cgroup, []byte("libpod")):
+		return RuntimePodman
+	case bytes.Contains(cgroup, []byte("containerd")):
+		return RuntimeContainerd
+	case bytes.Contains(cgroup, []byte("/lxc")):
+		return RuntimeLXC
+	}
+	return ""
+}
+
+// detectWSL looks for the Microsoft kernel build string.
+func detectWSL() bool {
+	if os.Getenv("WSL_DISTRO_NAME") != "" {
+		return true
+	}
+	release, err := os.ReadFile("/proc/sys/kernel/osrelease")
+	if err != nil {
+		return false
+	}
+	r := strings.ToLower(string(release))
+	return strings.Contains(r, "microsoft") || strings.Contains(r, "wsl")
+}
+
+// detectVM checks the hypervisor CPU flag and the DMI strings.
+func detectVM() bool {
+	if cpuinfo, err := os.ReadFile("/proc/cpuinfo"); err == nil {
+		for _, line := range strings.Split(string(cpuinfo), "\n") {
+			if strings.HasPrefix(line, "flags") && strings.Contains(line, " hypervisor") {
+				return true
+			}
+		}
+	}
+	var fields []string
+	for _, name := range []string{"sys_vendor", "product_name", "bios_vendor"} {
+		b, _ := os.ReadFile("/sys/class/dmi/id/" + name)
+		fields = append(fields, string(b))
+	}
+	return isHypervisorVendor(fields...)
+}
+
+// fileExists reports whether path exists.
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
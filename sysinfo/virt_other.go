@@ -0,0 +1,18 @@
+//go:build !windows && !linux && !darwin && !freebsd
+
+package sysinfo
+
+// detectContainer is not implemented on this platform.
+func detectContainer() string {
+	return ""
+}
+
+// detectWSL is false outside Linux.
+func detectWSL() bool {
+	return false
+}
+
+// detectVM is not implemented on this platform.
+func detectVM() bool {
+	return false
+}
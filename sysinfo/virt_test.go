@@ -0,0 +1,29 @@
+package sysinfo_test
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/grokify/oscompat/sysinfo"
+)
+
+func TestContainer(t *testing.T) {
+	rt := sysinfo.ContainerRuntime()
+	if sysinfo.IsContainer() != (rt != "") {
+		t.Errorf("IsContainer() = %v but ContainerRuntime() = %q", sysinfo.IsContainer(), rt)
+	}
+	t.Logf("container runtime: %q", rt)
+}
+
+func TestIsWSL(t *testing.T) {
+	if runtime.GOOS != "linux" && sysinfo.IsWSL() {
+		t.Error("IsWSL() = true outside Linux")
+	}
+}
+
+func TestIsVM(t *testing.T) {
+	// The answer depends on the machine; check that it is stable.
+	if sysinfo.IsVM() != sysinfo.IsVM() {
+		t.Error("IsVM() is not stable")
+	}
+}
@@ -0,0 +1,33 @@
+//go:build windows
+
+package sysinfo
+
+// Registry locations used for detection.
+const (
+	controlKey = `SYSTEM\CurrentControlSet\Control`
+	biosKey    = `HARDWARE\DESCRIPTION\System\BIOS`
+)
+
+// detectContainer checks the ContainerType value that Windows containers
+// (process and Hyper-V isolation) set.
+func detectContainer() string {
+	if regExists(controlKey, "ContainerType") {
+		return RuntimeUnknown
+	}
+	return ""
+}
+
+// detectWSL is false for Windows binaries, even on a machine with WSL
+// installed.
+func detectWSL() bool {
+	return false
+}
+
+// detectVM checks the SMBIOS strings the firmware reports.
+func detectVM() bool {
+	return isHypervisorVendor(
+		regString(biosKey, "SystemManufacturer"),
+		regString(biosKey, "SystemProductName"),
+		regString(biosKey, "BIOSVendor"),
+	)
+}
@@ -0,0 +1,64 @@
+package term
+
+import (
+	"os"
+	"strconv"
+)
+
+// ClearLine erases the line holding the cursor of the terminal f and
+// moves the cursor to its start, so a progress line can be redrawn.
+//
+// Like ClearScreen and MoveCursor, it writes ANSI escape sequences where
+// the terminal interprets them and falls back to the console API on
+// Windows consoles that are not in virtual terminal mode, where the
+// sequences would otherwise be printed. If f is not a terminal, or TERM
+// is "dumb", it does nothing, so redirected output stays free of control
+// codes.
+func ClearLine(f *os.File) error {
+	return clearLine(f)
+}
+
+// ClearScreen erases the visible screen of the terminal f and moves the
+// cursor to its top left corner. See ClearLine for when it does nothing.
+func ClearScreen(f *os.File) error {
+	return clearScreen(f)
+}
+
+// MoveCursor moves the cursor of the terminal f by dx columns (negative
+// is left) and dy rows (negative is up), stopping at the edges of the
+// screen. Moving up over the lines it printed lets a program redraw a
+// multi-line status. See ClearLine for when it does nothing.
+func MoveCursor(f *os.File, dx, dy int) error {
+	return moveCursor(f, dx, dy)
+}
+
+// ANSI control sequences.
+const (
+	ansiClearLine   = "\r\x1b[2K"
+	ansiClearScreen = "\x1b[2J\x1b[H"
+)
+
+// ansiMove returns the sequences moving the cursor by dx and dy.
+func ansiMove(dx, dy int) string {
+	var s string
+	move := func(n int, pos, neg byte) {
+		switch {
+		case n > 0:
+			s += "\x1b[" + strconv.Itoa(n) + string(pos)
+		case n < 0:
+			s += "\x1b[" + strconv.Itoa(-n) + string(neg)
+		}
+	}
+	move(dx, 'C', 'D')
+	move(dy, 'B', 'A')
+	return s
+}
+
+// writeANSI writes seq to f if it is a terminal that is not "dumb".
+func writeANSI(f *os.File, seq string) error {
+	if seq == "" || !IsTerminal(f.Fd()) || os.Getenv("TERM") == "dumb" {
+		return nil
+	}
+	_, err := f.WriteString(seq)
+	return err
+}
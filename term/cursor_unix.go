@@ -0,0 +1,17 @@
+//go:build !windows
+
+package term
+
+import "os"
+
+func clearLine(f *os.File) error {
+	return writeANSI(f, ansiClearLine)
+}
+
+func clearScreen(f *os.File) error {
+	return writeANSI(f, ansiClearScreen)
+}
+
+func moveCursor(f *os.File, dx, dy int) error {
+	return writeANSI(f, ansiMove(dx, dy))
+}
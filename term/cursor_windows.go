@@ -0,0 +1,104 @@
+//go:build windows
+
+package term
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	procGetConsoleScreenBufferInfo = kernel32.NewProc("GetConsoleScreenBufferInfo")
+	procSetConsoleCursorPosition   = kernel32.NewProc("SetConsoleCursorPosition")
+	procFillConsoleOutputCharacter = kernel32.NewProc("FillConsoleOutputCharacterW")
+	procFillConsoleOutputAttribute = kernel32.NewProc("FillConsoleOutputAttribute")
+)
+
+// coord mirrors COORD.
+type coord struct {
+	X, Y int16
+}
+
+// pack returns c as the 32-bit value of a COORD passed by value.
+func (c coord) pack() uintptr {
+	return uintptr(uint32(uint16(c.X)) | uint32(uint16(c.Y))<<16)
+}
+
+// consoleScreenBufferInfo mirrors CONSOLE_SCREEN_BUFFER_INFO.
+type consoleScreenBufferInfo struct {
+	Size              coord
+	CursorPosition    coord
+	Attributes        uint16
+	Window            struct{ Left, Top, Right, Bottom int16 }
+	MaximumWindowSize coord
+}
+
+// legacyConsole returns the handle and buffer state of f if it is a
+// console without virtual terminal processing. ok is false when f
+// should get ANSI sequences instead or is not a console at all.
+func legacyConsole(f *os.File) (h syscall.Handle, info consoleScreenBufferInfo, ok bool) {
+	h = syscall.Handle(f.Fd())
+	var mode uint32
+	if syscall.GetConsoleMode(h, &mode) != nil || mode&enableVirtualTerminalProcessing != 0 {
+		return h, info, false
+	}
+	r, _, _ := procGetConsoleScreenBufferInfo.Call(uintptr(h), uintptr(unsafe.Pointer(&info)))
+	return h, info, r != 0
+}
+
+// fill blanks n cells from start with the current attributes.
+func fill(h syscall.Handle, info *consoleScreenBufferInfo, start coord, n int) error {
+	var written uint32
+	if r, _, err := procFillConsoleOutputCharacter.Call(uintptr(h), ' ', uintptr(n), start.pack(), uintptr(unsafe.Pointer(&written))); r == 0 {
+		return err
+	}
+	if r, _, err := procFillConsoleOutputAttribute.Call(uintptr(h), uintptr(info.Attributes), uintptr(n), start.pack(), uintptr(unsafe.Pointer(&written))); r == 0 {
+		return err
+	}
+	return nil
+}
+
+func setCursor(h syscall.Handle, pos coord) error {
+	if r, _, err := procSetConsoleCursorPosition.Call(uintptr(h), pos.pack()); r == 0 {
+		return err
+	}
+	return nil
+}
+
+func clearLine(f *os.File) error {
+	h, info, ok := legacyConsole(f)
+	if !ok {
+		return writeANSI(f, ansiClearLine)
+	}
+	start := coord{0, info.CursorPosition.Y}
+	if err := fill(h, &info, start, int(info.Size.X)); err != nil {
+		return err
+	}
+	return setCursor(h, start)
+}
+
+func clearScreen(f *os.File) error {
+	h, info, ok := legacyConsole(f)
+	if !ok {
+		return writeANSI(f, ansiClearScreen)
+	}
+	w := info.Window
+	for y := w.Top; y <= w.Bottom; y++ {
+		if err := fill(h, &info, coord{w.Left, y}, int(w.Right-w.Left)+1); err != nil {
+			return err
+		}
+	}
+	return setCursor(h, coord{w.Left, w.Top})
+}
+
+func moveCursor(f *os.File, dx, dy int) error {
+	h, info, ok := legacyConsole(f)
+	if !ok {
+		return writeANSI(f, ansiMove(dx, dy))
+	}
+	pos := info.CursorPosition
+	x := min(max(int(pos.X)+dx, 0), int(info.Size.X)-1)
+	y := min(max(int(pos.Y)+dy, int(info.Window.Top)), int(info.Window.Bottom))
+	return setCursor(h, coord{int16(x), int16(y)})
+}
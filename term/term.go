@@ -0,0 +1,61 @@
+// Package term provides cross-platform terminal capability detection.
+//
+// CLIs need to know whether their output is a terminal and whether it
+// understands ANSI escape sequences. On Unix this is a matter of checking
+// the file descriptor and a few environment variables; on Windows the
+// console must additionally be switched into virtual terminal mode before
+// escape sequences are interpreted rather than printed.
+package term
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrUnsupported is returned when an operation is not available on the
+// current platform or console. It matches errors.ErrUnsupported.
+var ErrUnsupported = fmt.Errorf("oscompat/term: %w", errors.ErrUnsupported)
+
+// IsTerminal reports whether fd refers to a terminal (a Windows console on
+// Windows). Pass os.Stdout.Fd() and similar.
+func IsTerminal(fd uintptr) bool {
+	return isTerminal(fd)
+}
+
+// SupportsColor reports whether ANSI color output to stdout is likely to
+// be displayed correctly. The checks, in order:
+//   - NO_COLOR set to a non-empty value disables color (https://no-color.org).
+//   - FORCE_COLOR or CLICOLOR_FORCE set to anything but "0" or "false"
+//     enables color, even when stdout is not a terminal.
+//   - Otherwise stdout must be a terminal and TERM must not be "dumb".
+//   - On Windows, color is supported in Windows Terminal (WT_SESSION),
+//     ConEmu with ANSI enabled, ANSICON, terminals that set TERM (such as
+//     mintty), and consoles in virtual terminal mode; see
+//     EnableVirtualTerminal. On Unix, TERM must be set.
+func SupportsColor() bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	for _, name := range []string{"FORCE_COLOR", "CLICOLOR_FORCE"} {
+		if v, ok := os.LookupEnv(name); ok {
+			return v != "0" && v != "false"
+		}
+	}
+	if !IsTerminal(os.Stdout.Fd()) || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return supportsColor(os.Stdout.Fd())
+}
+
+// EnableVirtualTerminal turns on ENABLE_VIRTUAL_TERMINAL_PROCESSING for
+// the stdout and stderr consoles on Windows, so that ANSI escape
+// sequences are interpreted. Handles that are not consoles are skipped.
+// It returns ErrUnsupported on Windows versions without virtual terminal
+// support (before Windows 10 1511).
+//
+// On other platforms terminals interpret escape sequences natively and
+// EnableVirtualTerminal does nothing.
+func EnableVirtualTerminal() error {
+	return enableVirtualTerminal()
+}
@@ -0,0 +1,7 @@
+//go:build linux
+
+package term
+
+import "syscall"
+
+const ioctlReadTermios = syscall.TCGETS
@@ -0,0 +1,27 @@
+//go:build !windows && !linux && !darwin && !freebsd && !netbsd && !openbsd && !dragonfly
+
+package term
+
+import (
+	"os"
+	"syscall"
+)
+
+// isTerminal treats any character device as a terminal.
+func isTerminal(fd uintptr) bool {
+	var st syscall.Stat_t
+	if err := syscall.Fstat(int(fd), &st); err != nil {
+		return false
+	}
+	return st.Mode&syscall.S_IFMT == syscall.S_IFCHR
+}
+
+// supportsColor assumes any terminal with TERM set understands ANSI.
+func supportsColor(fd uintptr) bool {
+	return os.Getenv("TERM") != ""
+}
+
+// enableVirtualTerminal is a no-op; Unix terminals interpret ANSI natively.
+func enableVirtualTerminal() error {
+	return nil
+}
@@ -0,0 +1,93 @@
+package term_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/grokify/oscompat/term"
+)
+
+func TestIsTerminalFile(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "term")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+	if term.IsTerminal(f.Fd()) {
+		t.Error("IsTerminal() = true for a regular file")
+	}
+}
+
+func TestIsTerminalPipe(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = r.Close() }()
+	defer func() { _ = w.Close() }()
+	if term.IsTerminal(w.Fd()) {
+		t.Error("IsTerminal() = true for a pipe")
+	}
+}
+
+func TestSupportsColorEnv(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want bool
+	}{
+		{"no color wins", map[string]string{"NO_COLOR": "1", "FORCE_COLOR": "1"}, false},
+		{"force color", map[string]string{"NO_COLOR": "", "FORCE_COLOR": "1"}, true},
+		{"force color off", map[string]string{"NO_COLOR": "", "FORCE_COLOR": "0"}, false},
+		{"clicolor force", map[string]string{"NO_COLOR": "", "CLICOLOR_FORCE": "1"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range []string{"FORCE_COLOR", "CLICOLOR_FORCE"} {
+				t.Setenv(name, "") // restores the original value
+				if err := os.Unsetenv(name); err != nil {
+					t.Fatal(err)
+				}
+			}
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			if got := term.SupportsColor(); got != tt.want {
+				t.Errorf("SupportsColor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnableVirtualTerminal(t *testing.T) {
+	// Under go test, stdout is usually not a console; the call must
+	// succeed either way.
+	if err := term.EnableVirtualTerminal(); err != nil {
+		t.Errorf("EnableVirtualTerminal() error: %v", err)
+	}
+}
+
+func TestCursorNotTerminal(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "term")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = f.Close() }()
+
+	if err := term.ClearLine(f); err != nil {
+		t.Errorf("ClearLine() error: %v", err)
+	}
+	if err := term.ClearScreen(f); err != nil {
+		t.Errorf("ClearScreen() error: %v", err)
+	}
+	if err := term.MoveCursor(f, -3, 2); err != nil {
+		t.Errorf("MoveCursor() error: %v", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() != 0 {
+		t.Errorf("%d bytes of control codes written to a regular file", info.Size())
+	}
+}
@@ -0,0 +1,26 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package term
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// isTerminal reports whether the terminal attributes of fd can be read.
+func isTerminal(fd uintptr) bool {
+	var t syscall.Termios
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, fd, ioctlReadTermios, uintptr(unsafe.Pointer(&t)))
+	return errno == 0
+}
+
+// supportsColor assumes any terminal with TERM set understands ANSI.
+func supportsColor(fd uintptr) bool {
+	return os.Getenv("TERM") != ""
+}
+
+// enableVirtualTerminal is a no-op; Unix terminals interpret ANSI natively.
+func enableVirtualTerminal() error {
+	return nil
+}
@@ -0,0 +1,58 @@
+//go:build windows
+
+package term
+
+import (
+	"os"
+	"syscall"
+)
+
+var (
+	kernel32           = syscall.NewLazyDLL("kernel32.dll")
+	procSetConsoleMode = kernel32.NewProc("SetConsoleMode")
+)
+
+const enableVirtualTerminalProcessing = 0x0004
+
+// isTerminal reports whether fd is a console handle.
+func isTerminal(fd uintptr) bool {
+	var mode uint32
+	return syscall.GetConsoleMode(syscall.Handle(fd), &mode) == nil
+}
+
+// supportsColor checks for terminals known to interpret ANSI and for a
+// console already in virtual terminal mode.
+func supportsColor(fd uintptr) bool {
+	switch {
+	case os.Getenv("WT_SESSION") != "",
+		os.Getenv("ConEmuANSI") == "ON",
+		os.Getenv("ANSICON") != "",
+		os.Getenv("TERM") != "":
+		return true
+	}
+	var mode uint32
+	if err := syscall.GetConsoleMode(syscall.Handle(fd), &mode); err != nil {
+		return false
+	}
+	return mode&enableVirtualTerminalProcessing != 0
+}
+
+// enableVirtualTerminal sets ENABLE_VIRTUAL_TERMINAL_PROCESSING on the
+// stdout and stderr consoles.
+func enableVirtualTerminal() error {
+	for _, f := range []*os.File{os.Stdout, os.Stderr} {
+		h := syscall.Handle(f.Fd())
+		var mode uint32
+		if err := syscall.GetConsoleMode(h, &mode); err != nil {
+			continue // not a console
+		}
+		if mode&enableVirtualTerminalProcessing != 0 {
+			continue
+		}
+		r, _, _ := procSetConsoleMode.Call(uintptr(h), uintptr(mode|enableVirtualTerminalProcessing))
+		if r == 0 {
+			return ErrUnsupported
+		}
+	}
+	return nil
+}
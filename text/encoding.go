@@ -0,0 +1,270 @@
+package text
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Encoding is a Unicode text encoding.
+type Encoding int
+
+// Encodings.
+const (
+	// UTF8 is UTF-8 without a byte order mark.
+	UTF8 Encoding = iota
+
+	// UTF8BOM is UTF-8 with a byte order mark, as written by Windows
+	// PowerShell's -Encoding UTF8 and older Notepad.
+	UTF8BOM
+
+	// UTF16LE is little-endian UTF-16 with a byte order mark, the
+	// "Unicode" encoding of Windows tools and Windows PowerShell
+	// redirection.
+	UTF16LE
+
+	// UTF16BE is big-endian UTF-16 with a byte order mark.
+	UTF16BE
+)
+
+// String returns the name of the encoding.
+func (e Encoding) String() string {
+	switch e {
+	case UTF8:
+		return "UTF-8"
+	case UTF8BOM:
+		return "UTF-8 BOM"
+	case UTF16LE:
+		return "UTF-16LE"
+	case UTF16BE:
+		return "UTF-16BE"
+	default:
+		return "unknown"
+	}
+}
+
+// Byte order marks.
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// bom returns the byte order mark written for e.
+func (e Encoding) bom() []byte {
+	switch e {
+	case UTF8BOM:
+		return bomUTF8
+	case UTF16LE:
+		return bomUTF16LE
+	case UTF16BE:
+		return bomUTF16BE
+	}
+	return nil
+}
+
+// order returns the byte order of a UTF-16 encoding, or nil for UTF-8.
+func (e Encoding) order() binary.ByteOrder {
+	switch e {
+	case UTF16LE:
+		return binary.LittleEndian
+	case UTF16BE:
+		return binary.BigEndian
+	}
+	return nil
+}
+
+// NewReader returns a reader that decodes r to UTF-8. The encoding is
+// chosen by the byte order mark at the start of r, which is removed:
+// UTF-8, UTF-16LE, or UTF-16BE. Input without a BOM is assumed to be
+// UTF-8 and passed through unchanged. Unpaired UTF-16 surrogates and a
+// trailing odd byte decode to U+FFFD.
+func NewReader(r io.Reader) io.Reader {
+	return &decodeReader{r: bufio.NewReader(r)}
+}
+
+// decodeReader implements NewReader.
+type decodeReader struct {
+	r        *bufio.Reader
+	detected bool
+	order    binary.ByteOrder // nil for UTF-8
+	out      []byte           // decoded bytes not yet returned
+	buf      []byte           // backing storage for out
+}
+
+// Read implements io.Reader.
+func (d *decodeReader) Read(p []byte) (int, error) {
+	if !d.detected {
+		if err := d.detect(); err != nil {
+			return 0, err
+		}
+	}
+	if d.order == nil {
+		return d.r.Read(p)
+	}
+	for len(d.out) == 0 {
+		if err := d.decode(len(p)); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, d.out)
+	d.out = d.out[n:]
+	return n, nil
+}
+
+// detect consumes the byte order mark, if any.
+func (d *decodeReader) detect() error {
+	d.detected = true
+	head, _ := d.r.Peek(3)
+	bom := 0
+	switch {
+	case bytes.HasPrefix(head, bomUTF8):
+		bom = len(bomUTF8)
+	case bytes.HasPrefix(head, bomUTF16LE):
+		bom = len(bomUTF16LE)
+		d.order = binary.LittleEndian
+	case bytes.HasPrefix(head, bomUTF16BE):
+		bom = len(bomUTF16BE)
+		d.order = binary.BigEndian
+	}
+	_, err := d.r.Discard(bom)
+	return err
+}
+
+// decode converts UTF-16 code units to about n bytes of UTF-8.
+func (d *decodeReader) decode(n int) error {
+	d.buf = d.buf[:0]
+	var unit [2]byte
+	for len(d.buf) < max(n, utf8.UTFMax) {
+		k, err := io.ReadFull(d.r, unit[:])
+		if err == io.ErrUnexpectedEOF && k == 1 {
+			d.buf = utf8.AppendRune(d.buf, utf8.RuneError)
+			break
+		}
+		if err != nil {
+			if len(d.buf) > 0 {
+				break
+			}
+			return err
+		}
+		r := rune(d.order.Uint16(unit[:]))
+		if utf16.IsSurrogate(r) {
+			r = d.lowSurrogate(r)
+		}
+		d.buf = utf8.AppendRune(d.buf, r)
+		if d.r.Buffered() == 0 {
+			break // return what we have rather than block
+		}
+	}
+	d.out = d.buf
+	return nil
+}
+
+// lowSurrogate combines the high surrogate hi with the following code
+// unit, returning U+FFFD if it does not complete a pair. A non-matching
+// unit is left unread.
+func (d *decodeReader) lowSurrogate(hi rune) rune {
+	next, err := d.r.Peek(2)
+	if err != nil {
+		return utf8.RuneError
+	}
+	lo := rune(d.order.Uint16(next))
+	r := utf16.DecodeRune(hi, lo)
+	if r == utf8.RuneError {
+		return r
+	}
+	if _, err := d.r.Discard(2); err != nil {
+		return utf8.RuneError
+	}
+	return r
+}
+
+// NewWriter returns a writer that encodes UTF-8 written to it as enc and
+// writes the result to w, starting with the byte order mark for enc, if
+// any. Invalid UTF-8 is encoded as U+FFFD.
+//
+// Close must be called to write out an incomplete trailing UTF-8 sequence
+// and, if nothing was written, the byte order mark. It does not close w.
+func NewWriter(w io.Writer, enc Encoding) io.WriteCloser {
+	order, _ := enc.order().(binary.AppendByteOrder)
+	return &encodeWriter{w: w, enc: enc, order: order}
+}
+
+// encodeWriter implements NewWriter.
+type encodeWriter struct {
+	w       io.Writer
+	enc     Encoding
+	order   binary.AppendByteOrder // nil for UTF-8
+	started bool
+	pending []byte // incomplete UTF-8 sequence from the previous Write
+	buf     []byte
+}
+
+// Write implements io.Writer.
+func (e *encodeWriter) Write(p []byte) (int, error) {
+	if err := e.start(); err != nil {
+		return 0, err
+	}
+	if e.order == nil {
+		return e.w.Write(p)
+	}
+
+	data := p
+	if len(e.pending) > 0 {
+		data = append(e.pending, p...)
+		e.pending = nil
+	}
+	e.buf = e.buf[:0]
+	for len(data) > 0 {
+		if !utf8.FullRune(data) {
+			e.pending = append([]byte(nil), data...)
+			break
+		}
+		r, size := utf8.DecodeRune(data)
+		data = data[size:]
+		e.appendRune(r)
+	}
+	if _, err := e.w.Write(e.buf); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close writes any incomplete sequence as U+FFFD and, if nothing was
+// written, the byte order mark.
+func (e *encodeWriter) Close() error {
+	if err := e.start(); err != nil {
+		return err
+	}
+	if len(e.pending) == 0 {
+		return nil
+	}
+	e.pending = nil
+	e.buf = e.buf[:0]
+	e.appendRune(utf8.RuneError)
+	_, err := e.w.Write(e.buf)
+	return err
+}
+
+// start writes the byte order mark once.
+func (e *encodeWriter) start() error {
+	if e.started {
+		return nil
+	}
+	e.started = true
+	if bom := e.enc.bom(); bom != nil {
+		_, err := e.w.Write(bom)
+		return err
+	}
+	return nil
+}
+
+// appendRune appends r as UTF-16 code units to e.buf.
+func (e *encodeWriter) appendRune(r rune) {
+	for _, u := range utf16.AppendRune(nil, r) {
+		e.buf = e.order.AppendUint16(e.buf, u)
+	}
+}
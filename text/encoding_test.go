@@ -0,0 +1,92 @@
+package text_test
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"testing/iotest"
+
+	"github.com/grokify/oscompat/text"
+)
+
+const sample = "héllo, 世界 🌍\r\n"
+
+func TestNewWriterReaderRoundTrip(t *testing.T) {
+	for _, enc := range []text.Encoding{text.UTF8, text.UTF8BOM, text.UTF16LE, text.UTF16BE} {
+		t.Run(enc.String(), func(t *testing.T) {
+			var buf bytes.Buffer
+			w := text.NewWriter(&buf, enc)
+			// Byte-at-a-time writes split multi-byte sequences.
+			for i := 0; i < len(sample); i++ {
+				if _, err := w.Write([]byte{sample[i]}); err != nil {
+					t.Fatalf("Write() error: %v", err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatalf("Close() error: %v", err)
+			}
+
+			got, err := io.ReadAll(iotest.HalfReader(text.NewReader(&buf)))
+			if err != nil {
+				t.Fatalf("ReadAll() error: %v", err)
+			}
+			if string(got) != sample {
+				t.Errorf("round trip = %q, want %q", got, sample)
+			}
+		})
+	}
+}
+
+func TestNewWriterBytes(t *testing.T) {
+	tests := []struct {
+		enc  text.Encoding
+		want []byte
+	}{
+		{text.UTF8, []byte("A")},
+		{text.UTF8BOM, []byte{0xEF, 0xBB, 0xBF, 'A'}},
+		{text.UTF16LE, []byte{0xFF, 0xFE, 'A', 0}},
+		{text.UTF16BE, []byte{0xFE, 0xFF, 0, 'A'}},
+	}
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		w := text.NewWriter(&buf, tt.enc)
+		if _, err := w.Write([]byte("A")); err != nil {
+			t.Fatal(err)
+		}
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(buf.Bytes(), tt.want) {
+			t.Errorf("NewWriter(%v) wrote % x, want % x", tt.enc, buf.Bytes(), tt.want)
+		}
+	}
+}
+
+func TestNewWriterEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := text.NewWriter(&buf, text.UTF16LE).Close(); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), []byte{0xFF, 0xFE}) {
+		t.Errorf("empty UTF-16LE file = % x, want BOM only", buf.Bytes())
+	}
+}
+
+func TestNewReaderInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []byte
+		want string
+	}{
+		{"no BOM", []byte("plain"), "plain"},
+		{"odd trailing byte", []byte{0xFF, 0xFE, 'A', 0, 'B'}, "A�"},
+		{"unpaired high surrogate", []byte{0xFF, 0xFE, 0x3D, 0xD8, 'A', 0}, "�A"},
+		{"lone low surrogate", []byte{0xFE, 0xFF, 0xDC, 0x00}, "�"},
+	}
+	for _, tt := range tests {
+		got, err := io.ReadAll(text.NewReader(bytes.NewReader(tt.in)))
+		if err != nil || string(got) != tt.want {
+			t.Errorf("%s: NewReader() = %q, %v; want %q", tt.name, got, err, tt.want)
+		}
+	}
+}
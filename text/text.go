@@ -0,0 +1,170 @@
+// Package text provides cross-platform handling of text file conventions.
+//
+// Windows tools expect CRLF line endings and often read and write UTF-16,
+// while Unix tools expect LF and UTF-8. Files generated on one platform
+// and consumed on another (configuration, scripts, logs) break in subtle
+// ways: bash rejects scripts with CRLF endings, and older Notepad shows LF
+// files as one long line. This package detects and converts between these
+// conventions.
+package text
+
+import (
+	"bytes"
+	"io"
+	"runtime"
+)
+
+// EOL is a line ending sequence.
+type EOL string
+
+// Line endings.
+const (
+	// LF is the Unix line ending.
+	LF EOL = "\n"
+
+	// CRLF is the Windows line ending.
+	CRLF EOL = "\r\n"
+)
+
+// NativeEOL returns the platform's line ending: CRLF on Windows and LF
+// elsewhere.
+func NativeEOL() EOL {
+	if runtime.GOOS == "windows" {
+		return CRLF
+	}
+	return LF
+}
+
+// DetectEOL reads r to the end and returns its predominant line ending.
+// Ties go to LF. If r contains no line endings, DetectEOL returns
+// NativeEOL(). Wrap r with io.LimitReader to inspect only a prefix of
+// large inputs.
+func DetectEOL(r io.Reader) (EOL, error) {
+	var lf, crlf int
+	var prevCR bool
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := r.Read(buf)
+		for _, c := range buf[:n] {
+			if c == '\n' {
+				if prevCR {
+					crlf++
+				} else {
+					lf++
+				}
+			}
+			prevCR = c == '\r'
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	switch {
+	case lf == 0 && crlf == 0:
+		return NativeEOL(), nil
+	case crlf > lf:
+		return CRLF, nil
+	default:
+		return LF, nil
+	}
+}
+
+// ToLF returns a reader that converts CRLF line endings in r to LF. Lone
+// CR characters are passed through.
+func ToLF(r io.Reader) io.Reader {
+	return &eolReader{r: r, eol: LF}
+}
+
+// ToCRLF returns a reader that converts LF line endings in r to CRLF.
+// Existing CRLF line endings are left unchanged, so converting twice is
+// harmless.
+func ToCRLF(r io.Reader) io.Reader {
+	return &eolReader{r: r, eol: CRLF}
+}
+
+// Convert returns data with every line ending converted to eol. An empty
+// eol means NativeEOL().
+func Convert(data []byte, eol EOL) []byte {
+	var r io.Reader
+	switch eol {
+	case CRLF:
+		r = ToCRLF(bytes.NewReader(data))
+	case LF:
+		r = ToLF(bytes.NewReader(data))
+	default:
+		return Convert(data, NativeEOL())
+	}
+	out, _ := io.ReadAll(r) // reading from memory cannot fail
+	return out
+}
+
+// eolReader converts line endings while streaming.
+type eolReader struct {
+	r   io.Reader
+	eol EOL
+	in  []byte
+	out []byte // converted bytes not yet returned
+	buf []byte // backing storage for out
+
+	// cr records a CR at the end of the previous chunk. ToLF holds it
+	// back until it knows whether LF follows; ToCRLF needs it to leave
+	// CRLF split across chunks alone.
+	cr  bool
+	err error
+}
+
+// Read implements io.Reader.
+func (e *eolReader) Read(p []byte) (int, error) {
+	for len(e.out) == 0 {
+		if e.err != nil {
+			if e.cr && e.eol == LF {
+				e.cr = false
+				e.out = append(e.buf[:0], '\r')
+				continue
+			}
+			return 0, e.err
+		}
+		if e.in == nil {
+			e.in = make([]byte, 32*1024)
+		}
+		n, err := e.r.Read(e.in)
+		e.err = err
+		e.buf = e.convert(e.buf[:0], e.in[:n])
+		e.out = e.buf
+	}
+	n := copy(p, e.out)
+	e.out = e.out[n:]
+	return n, nil
+}
+
+// convert appends the converted form of src to dst.
+func (e *eolReader) convert(dst, src []byte) []byte {
+	for _, c := range src {
+		if e.eol == LF {
+			if e.cr {
+				e.cr = false
+				if c == '\n' {
+					dst = append(dst, '\n')
+					continue
+				}
+				dst = append(dst, '\r')
+			}
+			if c == '\r' {
+				e.cr = true
+				continue
+			}
+			dst = append(dst, c)
+			continue
+		}
+
+		if c == '\n' && !e.cr {
+			dst = append(dst, '\r')
+		}
+		dst = append(dst, c)
+		e.cr = c == '\r'
+	}
+	return dst
+}
@@ -0,0 +1,88 @@
+package text_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/grokify/oscompat/text"
+)
+
+func TestDetectEOL(t *testing.T) {
+	tests := []struct {
+		in   string
+		want text.EOL
+	}{
+		{"a\nb\n", text.LF},
+		{"a\r\nb\r\n", text.CRLF},
+		{"a\r\nb\r\nc\n", text.CRLF},
+		{"a\nb\nc\r\n", text.LF},
+		{"a\r\nb\n", text.LF},
+		{"no newline", text.NativeEOL()},
+		{"", text.NativeEOL()},
+	}
+	for _, tt := range tests {
+		// One byte at a time exercises CRLF split across reads.
+		got, err := text.DetectEOL(iotest.OneByteReader(strings.NewReader(tt.in)))
+		if err != nil || got != tt.want {
+			t.Errorf("DetectEOL(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestToLF(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"a\r\nb\r\n", "a\nb\n"},
+		{"a\nb", "a\nb"},
+		{"lone\rcr", "lone\rcr"},
+		{"trailing\r", "trailing\r"},
+		{"\r\r\n", "\r\n"},
+	}
+	for _, tt := range tests {
+		for _, r := range []io.Reader{strings.NewReader(tt.in), iotest.OneByteReader(strings.NewReader(tt.in))} {
+			got, err := io.ReadAll(text.ToLF(r))
+			if err != nil || string(got) != tt.want {
+				t.Errorf("ToLF(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+			}
+		}
+	}
+}
+
+func TestToCRLF(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"a\nb\n", "a\r\nb\r\n"},
+		{"a\r\nb", "a\r\nb"},
+		{"lone\rcr", "lone\rcr"},
+		{"\n\n", "\r\n\r\n"},
+	}
+	for _, tt := range tests {
+		for _, r := range []io.Reader{strings.NewReader(tt.in), iotest.OneByteReader(strings.NewReader(tt.in))} {
+			got, err := io.ReadAll(text.ToCRLF(r))
+			if err != nil || string(got) != tt.want {
+				t.Errorf("ToCRLF(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+			}
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	in := []byte("a\r\nb\nc")
+	if got := text.Convert(in, text.LF); !bytes.Equal(got, []byte("a\nb\nc")) {
+		t.Errorf("Convert(LF) = %q", got)
+	}
+	if got := text.Convert(in, text.CRLF); !bytes.Equal(got, []byte("a\r\nb\r\nc")) {
+		t.Errorf("Convert(CRLF) = %q", got)
+	}
+	if got, want := text.Convert(in, ""), text.Convert(in, text.NativeEOL()); !bytes.Equal(got, want) {
+		t.Errorf("Convert(\"\") = %q, want native %q", got, want)
+	}
+}
+
+func TestReaderErrors(t *testing.T) {
+	_, err := io.ReadAll(text.ToLF(iotest.ErrReader(io.ErrUnexpectedEOF)))
+	if err != io.ErrUnexpectedEOF {
+		t.Errorf("ToLF() error = %v, want ErrUnexpectedEOF", err)
+	}
+}
@@ -0,0 +1,227 @@
+package tsync
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"encoding/binary"
+	"math"
+	"time"
+)
+
+// Zip extra field IDs holding timestamps.
+const (
+	zipExtraNTFS     = 0x000a // Windows FILETIMEs, 100 ns, UTC
+	zipExtraExtTime  = 0x5455 // Info-ZIP extended timestamp, 1 s, UTC
+	zipExtraInfoUnix = 0x5855 // older Info-ZIP Unix field, 1 s, UTC
+)
+
+// filetimeEpoch is the Unix epoch in FILETIME units, 100 ns intervals
+// since 1601.
+const filetimeEpoch = 116444736000000000
+
+// ZipTime is a modification time as a zip file header stores it.
+type ZipTime struct {
+	// DOSDate and DOSTime are the header's MS-DOS date and time: local
+	// time of the machine that wrote the archive, with 2-second
+	// resolution and years 1980 to 2107.
+	DOSDate, DOSTime uint16
+
+	// Extra holds extra fields with the exact time: the NTFS field
+	// (100 ns, written by Windows tools) and the Info-ZIP extended
+	// timestamp (1 s, written by Unix tools and archive/zip).
+	Extra []byte
+}
+
+// ForZip encodes t for a zip header. Besides the DOS fields, it adds an
+// NTFS extra field, which keeps t to 100 ns, and an extended timestamp
+// for readers that only understand that one.
+func ForZip(t time.Time) ZipTime {
+	zt := ZipTime{}
+	zt.DOSDate, zt.DOSTime = dosTime(t.In(time.Local))
+
+	ft := uint64(t.UnixNano()/100 + filetimeEpoch)
+	ntfs := make([]byte, 36)
+	binary.LittleEndian.PutUint16(ntfs[0:], zipExtraNTFS)
+	binary.LittleEndian.PutUint16(ntfs[2:], 32)
+	binary.LittleEndian.PutUint16(ntfs[8:], 1)   // attribute 1: times
+	binary.LittleEndian.PutUint16(ntfs[10:], 24) // mtime, atime, ctime
+	for i := range 3 {
+		binary.LittleEndian.PutUint64(ntfs[12+8*i:], ft)
+	}
+	zt.Extra = ntfs
+
+	if sec := t.Unix(); sec >= 0 && sec <= math.MaxInt32 {
+		ext := make([]byte, 9)
+		binary.LittleEndian.PutUint16(ext[0:], zipExtraExtTime)
+		binary.LittleEndian.PutUint16(ext[2:], 5)
+		ext[4] = 1 // mtime present
+		binary.LittleEndian.PutUint32(ext[5:], uint32(sec))
+		zt.Extra = append(zt.Extra, ext...)
+	}
+	return zt
+}
+
+// FromZip decodes the modification time of a zip header, preferring the
+// NTFS extra field, then the Info-ZIP fields, then the DOS fields, which
+// are taken as local time.
+func FromZip(zt ZipTime) time.Time {
+	if t, ok := zipExtraTime(zt.Extra); ok {
+		return t
+	}
+	return fromDOSTime(zt.DOSDate, zt.DOSTime)
+}
+
+// Precision returns the resolution of the time FromZip decodes: 100 ns
+// with an NTFS field, a second with an Info-ZIP field, and two seconds
+// otherwise. Use it as the tolerance when comparing an extracted file
+// with its source.
+func (zt ZipTime) Precision() time.Duration {
+	switch zipExtraKind(zt.Extra) {
+	case zipExtraNTFS:
+		return 100 * time.Nanosecond
+	case zipExtraExtTime, zipExtraInfoUnix:
+		return time.Second
+	}
+	return FAT32Tolerance
+}
+
+// SetHeader stores zt in h. It clears h.Modified, whose own extended
+// timestamp would otherwise be added alongside.
+func (zt ZipTime) SetHeader(h *zip.FileHeader) {
+	h.Modified = time.Time{}
+	h.ModifiedDate, h.ModifiedTime = zt.DOSDate, zt.DOSTime
+	h.Extra = append(stripZipTimes(h.Extra), zt.Extra...)
+}
+
+// zipExtraKind returns the ID of the most precise timestamp field in
+// extra, or 0.
+func zipExtraKind(extra []byte) uint16 {
+	var kind uint16
+	for id, data := range zipExtraFields(extra) {
+		switch {
+		case id == zipExtraNTFS && ntfsMtime(data) != 0:
+			return id
+		case id == zipExtraExtTime && len(data) >= 5 && data[0]&1 != 0,
+			id == zipExtraInfoUnix && len(data) >= 8:
+			kind = id
+		}
+	}
+	return kind
+}
+
+// zipExtraTime decodes the most precise timestamp field in extra.
+func zipExtraTime(extra []byte) (time.Time, bool) {
+	var t time.Time
+	var found bool
+	for id, data := range zipExtraFields(extra) {
+		switch {
+		case id == zipExtraNTFS:
+			if ft := ntfsMtime(data); ft != 0 {
+				return time.Unix(0, (int64(ft)-filetimeEpoch)*100), true
+			}
+		case id == zipExtraExtTime && len(data) >= 5 && data[0]&1 != 0:
+			t, found = time.Unix(int64(int32(binary.LittleEndian.Uint32(data[1:]))), 0), true
+		case id == zipExtraInfoUnix && len(data) >= 8:
+			t, found = time.Unix(int64(int32(binary.LittleEndian.Uint32(data[4:]))), 0), true
+		}
+	}
+	return t, found
+}
+
+// ntfsMtime returns the mtime FILETIME of an NTFS extra field, or 0.
+func ntfsMtime(data []byte) uint64 {
+	if len(data) < 4 {
+		return 0
+	}
+	data = data[4:] // reserved
+	for len(data) >= 4 {
+		tag := binary.LittleEndian.Uint16(data)
+		size := int(binary.LittleEndian.Uint16(data[2:]))
+		data = data[4:]
+		if size > len(data) {
+			return 0
+		}
+		if tag == 1 && size >= 24 {
+			return binary.LittleEndian.Uint64(data)
+		}
+		data = data[size:]
+	}
+	return 0
+}
+
+// zipExtraFields iterates over the fields of a zip extra block.
+func zipExtraFields(extra []byte) func(yield func(uint16, []byte) bool) {
+	return func(yield func(uint16, []byte) bool) {
+		for len(extra) >= 4 {
+			id := binary.LittleEndian.Uint16(extra)
+			size := int(binary.LittleEndian.Uint16(extra[2:]))
+			if 4+size > len(extra) {
+				return
+			}
+			if !yield(id, extra[4:4+size]) {
+				return
+			}
+			extra = extra[4+size:]
+		}
+	}
+}
+
+// stripZipTimes returns extra without its timestamp fields.
+func stripZipTimes(extra []byte) []byte {
+	var out []byte
+	for id, data := range zipExtraFields(extra) {
+		if id == zipExtraNTFS || id == zipExtraExtTime || id == zipExtraInfoUnix {
+			continue
+		}
+		out = binary.LittleEndian.AppendUint16(out, id)
+		out = binary.LittleEndian.AppendUint16(out, uint16(len(data)))
+		out = append(out, data...)
+	}
+	return out
+}
+
+// dosTime encodes t as an MS-DOS date and time, rounding down to even
+// seconds like archive/zip and clamping to the years DOS can hold.
+func dosTime(t time.Time) (date, tm uint16) {
+	if t.Year() < 1980 {
+		return 1<<5 | 1, 0 // 1980-01-01 00:00:00
+	}
+	if t.Year() > 2107 {
+		return 127<<9 | 12<<5 | 31, 23<<11 | 59<<5 | 29 // 2107-12-31 23:59:58
+	}
+	date = uint16(t.Year()-1980)<<9 | uint16(t.Month())<<5 | uint16(t.Day())
+	tm = uint16(t.Hour())<<11 | uint16(t.Minute())<<5 | uint16(t.Second()/2)
+	return date, tm
+}
+
+// fromDOSTime decodes an MS-DOS date and time as local time.
+func fromDOSTime(date, tm uint16) time.Time {
+	return time.Date(
+		int(date>>9)+1980, time.Month(date>>5&0xf), int(date&0x1f),
+		int(tm>>11), int(tm>>5&0x3f), int(tm&0x1f)*2, 0,
+		time.Local,
+	)
+}
+
+// TarPrecision returns the modification time resolution of tar headers
+// in the given format. USTAR and GNU headers hold whole seconds; PAX
+// records hold any precision, and archive/tar writes nanoseconds. For
+// FormatUnknown, archive/tar rounds to whole seconds.
+func TarPrecision(format tar.Format) time.Duration {
+	if format&tar.FormatPAX != 0 {
+		return time.Nanosecond
+	}
+	return time.Second
+}
+
+// ForTar stores t as the modification time of h. If h's format cannot
+// hold t exactly, because t has a fractional second or lies outside the
+// range of the octal USTAR field, h is switched to the PAX format, which
+// GNU tar, bsdtar and 7-Zip all read.
+func ForTar(h *tar.Header, t time.Time) {
+	h.ModTime = t
+	const maxOctal = 1<<33 - 1 // 11 octal digits
+	if t.Nanosecond() != 0 || t.Unix() < 0 || t.Unix() > maxOctal {
+		h.Format = tar.FormatPAX
+	}
+}
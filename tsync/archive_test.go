@@ -0,0 +1,105 @@
+package tsync_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/grokify/oscompat/tsync"
+)
+
+func TestZipTime(t *testing.T) {
+	mtime := time.Date(2024, 3, 15, 10, 30, 45, 123456700, time.UTC)
+
+	zt := tsync.ForZip(mtime)
+	if got := tsync.FromZip(zt); !got.Equal(mtime) {
+		t.Errorf("FromZip(ForZip(t)) = %v, want %v", got, mtime)
+	}
+	if p := zt.Precision(); p != 100*time.Nanosecond {
+		t.Errorf("Precision() = %v", p)
+	}
+
+	// Without extra fields only the DOS time remains.
+	dos := tsync.ZipTime{DOSDate: zt.DOSDate, DOSTime: zt.DOSTime}
+	got := tsync.FromZip(dos)
+	if !tsync.EqualWithTolerance(got, mtime, dos.Precision()) || got.Second()%2 != 0 {
+		t.Errorf("FromZip(DOS only) = %v, want %v within 2s", got, mtime)
+	}
+
+	// Round trip through archive/zip.
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	h := &zip.FileHeader{Name: "f", Extra: []byte{0x99, 0x99, 1, 0, 7}}
+	zt.SetHeader(h)
+	if _, err := w.CreateHeader(h); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rh := r.File[0].FileHeader
+	read := tsync.ZipTime{DOSDate: rh.ModifiedDate, DOSTime: rh.ModifiedTime, Extra: rh.Extra}
+	if got := tsync.FromZip(read); !got.Equal(mtime) {
+		t.Errorf("FromZip after archive/zip = %v, want %v", got, mtime)
+	}
+	if !bytes.HasPrefix(rh.Extra, []byte{0x99, 0x99, 1, 0, 7}) {
+		t.Errorf("unrelated extra field lost: % x", rh.Extra)
+	}
+
+	t.Run("clamped", func(t *testing.T) {
+		old := tsync.ForZip(time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC))
+		if y := tsync.FromZip(tsync.ZipTime{DOSDate: old.DOSDate, DOSTime: old.DOSTime}).Year(); y != 1980 {
+			t.Errorf("year = %d, want 1980", y)
+		}
+	})
+}
+
+func TestForTar(t *testing.T) {
+	tests := []struct {
+		name  string
+		mtime time.Time
+		want  tar.Format
+	}{
+		{"whole second", time.Unix(1700000000, 0), tar.FormatUnknown},
+		{"fraction", time.Unix(1700000000, 5e8), tar.FormatPAX},
+		{"before 1970", time.Unix(-10, 0), tar.FormatPAX},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &tar.Header{Name: "f", Typeflag: tar.TypeReg}
+			tsync.ForTar(h, tt.mtime)
+			if h.Format != tt.want {
+				t.Errorf("Format = %v, want %v", h.Format, tt.want)
+			}
+
+			var buf bytes.Buffer
+			w := tar.NewWriter(&buf)
+			if err := w.WriteHeader(h); err != nil {
+				t.Fatal(err)
+			}
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+			rh, err := tar.NewReader(&buf).Next()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !rh.ModTime.Equal(tt.mtime) {
+				t.Errorf("ModTime = %v, want %v", rh.ModTime, tt.mtime)
+			}
+		})
+	}
+
+	if p := tsync.TarPrecision(tar.FormatUSTAR); p != time.Second {
+		t.Errorf("TarPrecision(USTAR) = %v", p)
+	}
+	if p := tsync.TarPrecision(tar.FormatPAX); p != time.Nanosecond {
+		t.Errorf("TarPrecision(PAX) = %v", p)
+	}
+}
@@ -0,0 +1,192 @@
+package tsync
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// DefaultPollInterval is the interval a Poller uses when given none.
+const DefaultPollInterval = 2 * time.Second
+
+// ChangeOp is the kind of change a Poller reports.
+type ChangeOp int
+
+const (
+	Created ChangeOp = iota + 1
+	Modified
+	Removed
+)
+
+// String returns the lower-case name of the operation.
+func (op ChangeOp) String() string {
+	switch op {
+	case Created:
+		return "created"
+	case Modified:
+		return "modified"
+	case Removed:
+		return "removed"
+	default:
+		return fmt.Sprintf("ChangeOp(%d)", int(op))
+	}
+}
+
+// Change is a modification detected by a Poller.
+type Change struct {
+	Path string
+	Op   ChangeOp
+}
+
+// Poller detects changes to a file, or to the entries of a directory, by
+// comparing size, mode, and modification time between scans. It is the
+// fallback for filesystems where native change notification is missing or
+// unreliable, such as NFS and SMB shares.
+//
+// Modification times are compared with a tolerance, so the precision loss
+// of FAT and network filesystems, which can make the same time read back
+// differently, is not reported as a change. The cost is that a rewrite
+// keeping the same size within the tolerance window goes unnoticed until
+// a later write.
+//
+// A directory is watched one level deep: its entries are created,
+// modified, or removed, but changes inside subdirectories are not seen.
+type Poller struct {
+	path      string
+	interval  time.Duration
+	tolerance time.Duration
+
+	mu   sync.Mutex
+	last map[string]stamp
+}
+
+// stamp is what a Poller compares between scans.
+type stamp struct {
+	size  int64
+	mode  os.FileMode
+	mtime time.Time
+}
+
+// NewPoller returns a Poller for path, which need not exist yet, and takes
+// its first snapshot. A zero or negative interval means
+// DefaultPollInterval, and a zero or negative tolerance means
+// DefaultTolerance; use FAT32Tolerance for FAT volumes.
+func NewPoller(path string, interval, tolerance time.Duration) *Poller {
+	if interval <= 0 {
+		interval = DefaultPollInterval
+	}
+	if tolerance <= 0 {
+		tolerance = DefaultTolerance
+	}
+	p := &Poller{path: path, interval: interval, tolerance: tolerance}
+	p.last, _ = p.scan()
+	return p
+}
+
+// Poll scans now and returns the changes since the previous scan, sorted
+// by path. A scan that fails, for example because a network share is
+// briefly unreachable, returns the error and keeps the previous snapshot,
+// so nothing is reported as removed.
+func (p *Poller) Poll() ([]Change, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	cur, err := p.scan()
+	if err != nil {
+		return nil, err
+	}
+	var changes []Change
+	for path, s := range cur {
+		old, ok := p.last[path]
+		switch {
+		case !ok:
+			changes = append(changes, Change{path, Created})
+		case s.size != old.size || s.mode != old.mode ||
+			!EqualWithTolerance(s.mtime, old.mtime, p.tolerance):
+			changes = append(changes, Change{path, Modified})
+		}
+	}
+	for path := range p.last {
+		if _, ok := cur[path]; !ok {
+			changes = append(changes, Change{path, Removed})
+		}
+	}
+	p.last = cur
+	slices.SortFunc(changes, func(a, b Change) int { return cmp.Compare(a.Path, b.Path) })
+	return changes, nil
+}
+
+// Watch polls every interval until ctx is done and sends each change on
+// the returned channel, which is then closed. Scan errors are skipped;
+// the next successful scan reports what changed in between.
+func (p *Poller) Watch(ctx context.Context) <-chan Change {
+	ch := make(chan Change, 16)
+	go func() {
+		defer close(ch)
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			changes, _ := p.Poll()
+			for _, c := range changes {
+				select {
+				case ch <- c:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return ch
+}
+
+// scan stats the path, or the entries of a directory. A missing path
+// yields an empty snapshot.
+func (p *Poller) scan() (map[string]stamp, error) {
+	snap := make(map[string]stamp)
+	info, err := os.Stat(p.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return snap, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		snap[p.path] = stampOf(info)
+		return snap, nil
+	}
+	entries, err := os.ReadDir(p.path)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		info, err := e.Info()
+		if errors.Is(err, os.ErrNotExist) {
+			continue // removed since ReadDir
+		}
+		if err != nil {
+			return nil, err
+		}
+		snap[filepath.Join(p.path, e.Name())] = stampOf(info)
+	}
+	return snap, nil
+}
+
+func stampOf(info os.FileInfo) stamp {
+	s := stamp{mode: info.Mode(), mtime: info.ModTime()}
+	// A directory's size is filesystem-specific and changes with its
+	// entries, which are not watched.
+	if !info.IsDir() {
+		s.size = info.Size()
+	}
+	return s
+}
@@ -0,0 +1,95 @@
+package tsync_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/grokify/oscompat/tsync"
+)
+
+func poll(t *testing.T, p *tsync.Poller) []tsync.Change {
+	t.Helper()
+	changes, err := p.Poll()
+	if err != nil {
+		t.Fatalf("Poll() error: %v", err)
+	}
+	return changes
+}
+
+// must fails the test if err is not nil.
+func must(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPollerFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "data.txt")
+	p := tsync.NewPoller(path, time.Hour, tsync.FAT32Tolerance)
+
+	must(t, os.WriteFile(path, []byte("one"), 0o644))
+	if got, want := poll(t, p), []tsync.Change{{Path: path, Op: tsync.Created}}; !slices.Equal(got, want) {
+		t.Errorf("after create: %v, want %v", got, want)
+	}
+	if got := poll(t, p); len(got) != 0 {
+		t.Errorf("unchanged file reported %v", got)
+	}
+
+	// Time jitter within the tolerance is precision loss, not a change.
+	info, _ := os.Stat(path)
+	must(t, os.Chtimes(path, info.ModTime(), info.ModTime().Add(1500*time.Millisecond)))
+	if got := poll(t, p); len(got) != 0 {
+		t.Errorf("mtime jitter reported %v", got)
+	}
+
+	must(t, os.Chtimes(path, info.ModTime(), info.ModTime().Add(time.Minute)))
+	if got := poll(t, p); len(got) != 1 || got[0].Op != tsync.Modified {
+		t.Errorf("after touch: %v, want modified", got)
+	}
+	must(t, os.WriteFile(path, []byte("longer"), 0o644))
+	if got := poll(t, p); len(got) != 1 || got[0].Op != tsync.Modified {
+		t.Errorf("after resize: %v, want modified", got)
+	}
+
+	must(t, os.Remove(path))
+	if got := poll(t, p); len(got) != 1 || got[0].Op != tsync.Removed {
+		t.Errorf("after remove: %v, want removed", got)
+	}
+}
+
+func TestPollerDir(t *testing.T) {
+	dir := t.TempDir()
+	must(t, os.WriteFile(filepath.Join(dir, "keep"), nil, 0o644))
+	must(t, os.WriteFile(filepath.Join(dir, "gone"), nil, 0o644))
+	p := tsync.NewPoller(dir, 10*time.Millisecond, 0)
+
+	must(t, os.Remove(filepath.Join(dir, "gone")))
+	must(t, os.WriteFile(filepath.Join(dir, "new"), nil, 0o644))
+	want := []tsync.Change{
+		{Path: filepath.Join(dir, "gone"), Op: tsync.Removed},
+		{Path: filepath.Join(dir, "new"), Op: tsync.Created},
+	}
+	if got := poll(t, p); !slices.Equal(got, want) {
+		t.Errorf("Poll() = %v, want %v", got, want)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := p.Watch(ctx)
+	must(t, os.WriteFile(filepath.Join(dir, "keep"), []byte("x"), 0o644))
+	select {
+	case c := <-ch:
+		if c.Path != filepath.Join(dir, "keep") || c.Op != tsync.Modified {
+			t.Errorf("Watch() sent %v", c)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Watch() sent nothing")
+	}
+	cancel()
+	for range ch {
+	}
+}
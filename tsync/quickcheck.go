@@ -0,0 +1,103 @@
+package tsync
+
+import (
+	"os"
+	"time"
+)
+
+// Confidence is the outcome of ContentLikelyEqual.
+type Confidence int
+
+const (
+	// Different means the contents differ.
+	Different Confidence = iota
+
+	// LikelySame means size and modification time match, which is
+	// rsync's "quick check": the contents are almost certainly the same,
+	// but nothing was read to prove it.
+	LikelySame
+
+	// Same means the contents are known to be the same, because both
+	// files are empty or the Hash callback said so.
+	Same
+)
+
+// String returns the name of the confidence level.
+func (c Confidence) String() string {
+	switch c {
+	case Different:
+		return "Different"
+	case LikelySame:
+		return "LikelySame"
+	case Same:
+		return "Same"
+	default:
+		return "Confidence(?)"
+	}
+}
+
+// QuickCheckOptions controls ContentLikelyEqual.
+type QuickCheckOptions struct {
+	// Tolerance is the allowed modification time difference. Zero means
+	// DefaultTolerance.
+	Tolerance time.Duration
+
+	// Hash, if set, compares the contents of the two files, typically by
+	// hashing them. It is only called once the sizes match, since that
+	// is the expensive step.
+	Hash func(a, b os.FileInfo) (bool, error)
+
+	// Verify calls Hash even when size and modification time match,
+	// turning LikelySame into Same or Different. Without it, Hash is
+	// only consulted when the times differ, to catch files that were
+	// touched or copied without preserving times.
+	Verify bool
+}
+
+// ContentLikelyEqual decides whether two files hold the same content,
+// checking the cheapest evidence first:
+//  1. Files of different types or sizes are Different.
+//  2. Two empty files are Same.
+//  3. Matching modification times, within tolerance, are LikelySame
+//     unless opts.Verify is set.
+//  4. Otherwise opts.Hash decides between Same and Different; without
+//     it, differing times mean Different, as rsync assumes.
+//
+// opts may be nil. Directories and other non-regular files are compared by
+// type and modification time only.
+func ContentLikelyEqual(a, b os.FileInfo, opts *QuickCheckOptions) (Confidence, error) {
+	var o QuickCheckOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Tolerance == 0 {
+		o.Tolerance = DefaultTolerance
+	}
+
+	if a.Mode().Type() != b.Mode().Type() {
+		return Different, nil
+	}
+	regular := a.Mode().IsRegular()
+	if regular && a.Size() != b.Size() {
+		return Different, nil
+	}
+	if regular && a.Size() == 0 {
+		return Same, nil
+	}
+
+	sameTime := EqualWithTolerance(a.ModTime(), b.ModTime(), o.Tolerance)
+	if !regular || o.Hash == nil || (sameTime && !o.Verify) {
+		if sameTime {
+			return LikelySame, nil
+		}
+		return Different, nil
+	}
+	same, err := o.Hash(a, b)
+	if err != nil {
+		return Different, err
+	}
+	if same {
+		return Same, nil
+	}
+	return Different, nil
+}
@@ -0,0 +1,84 @@
+package tsync_test
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/grokify/oscompat/tsync"
+)
+
+// fileInfo is a minimal os.FileInfo for tests.
+type fileInfo struct {
+	size  int64
+	mtime time.Time
+	mode  fs.FileMode
+}
+
+func (fi fileInfo) Name() string       { return "f" }
+func (fi fileInfo) Size() int64        { return fi.size }
+func (fi fileInfo) Mode() fs.FileMode  { return fi.mode }
+func (fi fileInfo) ModTime() time.Time { return fi.mtime }
+func (fi fileInfo) IsDir() bool        { return fi.mode.IsDir() }
+func (fi fileInfo) Sys() any           { return nil }
+
+func TestContentLikelyEqual(t *testing.T) {
+	base := time.Now()
+	file := func(size int64, mtime time.Time) os.FileInfo {
+		return fileInfo{size: size, mtime: mtime, mode: 0o644}
+	}
+	hashSame := func(os.FileInfo, os.FileInfo) (bool, error) { return true, nil }
+	hashDiff := func(os.FileInfo, os.FileInfo) (bool, error) { return false, nil }
+
+	tests := []struct {
+		name string
+		a, b os.FileInfo
+		opts *tsync.QuickCheckOptions
+		want tsync.Confidence
+	}{
+		{"same size and time", file(10, base), file(10, base.Add(500*time.Millisecond)), nil, tsync.LikelySame},
+		{"different size", file(10, base), file(11, base), nil, tsync.Different},
+		{"different time", file(10, base), file(10, base.Add(time.Hour)), nil, tsync.Different},
+		{"both empty", file(0, base), file(0, base.Add(time.Hour)), nil, tsync.Same},
+		{"custom tolerance", file(10, base), file(10, base.Add(2*time.Second)),
+			&tsync.QuickCheckOptions{Tolerance: tsync.FAT32Tolerance}, tsync.LikelySame},
+		{"hash rescues touched file", file(10, base), file(10, base.Add(time.Hour)),
+			&tsync.QuickCheckOptions{Hash: hashSame}, tsync.Same},
+		{"hash skipped when times match", file(10, base), file(10, base),
+			&tsync.QuickCheckOptions{Hash: hashDiff}, tsync.LikelySame},
+		{"verify", file(10, base), file(10, base),
+			&tsync.QuickCheckOptions{Hash: hashDiff, Verify: true}, tsync.Different},
+		{"file vs dir", file(10, base), fileInfo{size: 10, mtime: base, mode: fs.ModeDir | 0o755}, nil, tsync.Different},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tsync.ContentLikelyEqual(tt.a, tt.b, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("ContentLikelyEqual() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("hash not called on size mismatch", func(t *testing.T) {
+		opts := &tsync.QuickCheckOptions{Verify: true, Hash: func(os.FileInfo, os.FileInfo) (bool, error) {
+			t.Error("Hash called")
+			return false, nil
+		}}
+		if got, _ := tsync.ContentLikelyEqual(file(1, base), file(2, base), opts); got != tsync.Different {
+			t.Errorf("got %v", got)
+		}
+	})
+
+	t.Run("hash error", func(t *testing.T) {
+		errHash := errors.New("read failed")
+		opts := &tsync.QuickCheckOptions{Hash: func(os.FileInfo, os.FileInfo) (bool, error) { return false, errHash }}
+		if _, err := tsync.ContentLikelyEqual(file(1, base), file(1, base.Add(time.Hour)), opts); !errors.Is(err, errHash) {
+			t.Errorf("err = %v", err)
+		}
+	})
+}
@@ -0,0 +1,101 @@
+package user
+
+import (
+	"errors"
+	"fmt"
+	osuser "os/user"
+	"strings"
+)
+
+// ErrInvalidSID is returned when a string is not a SID, or is a SID that
+// cannot be mapped on this platform.
+var ErrInvalidSID = errors.New("oscompat/user: invalid SID")
+
+// Lookup returns the user with the given login name. On Windows the name
+// may be qualified as DOMAIN\user. It returns an error wrapping
+// ErrUnknownUser if there is no such user.
+func Lookup(name string) (*Principal, error) {
+	u, err := osuser.Lookup(name)
+	if err != nil {
+		if p, ok := currentIf(func(p *Principal) bool { return p.Username == name || p.String() == name }); ok {
+			return p, nil
+		}
+		return nil, lookupError(name, err)
+	}
+	return fromOSUser(u), nil
+}
+
+// LookupID returns the user with the given ID, which may be a numeric
+// UID (Unix only) or a SID (see FromSID). It returns an error wrapping
+// ErrUnknownUser if there is no such user.
+func LookupID(id string) (*Principal, error) {
+	if IsSID(id) {
+		return FromSID(id)
+	}
+	return lookupID(id)
+}
+
+// ToSID returns the SID of the user named by a login name, numeric UID,
+// or SID.
+//
+// On Windows this is the account SID. Unix has no SIDs; UIDs are mapped
+// to S-1-22-1-<uid>, the convention Samba and Windows use for Unix users,
+// so the result round-trips through FromSID.
+func ToSID(nameOrID string) (string, error) {
+	if IsSID(nameOrID) {
+		return nameOrID, nil
+	}
+	p, err := LookupID(nameOrID)
+	if err != nil {
+		p, err = Lookup(nameOrID)
+	}
+	if err != nil {
+		return "", err
+	}
+	return principalSID(p)
+}
+
+// FromSID returns the user with the given SID. On Unix only SIDs of the
+// form S-1-22-1-<uid> (see ToSID) can be resolved; others return an error
+// wrapping ErrInvalidSID.
+func FromSID(sid string) (*Principal, error) {
+	if !IsSID(sid) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidSID, sid)
+	}
+	return fromSID(sid)
+}
+
+// IsSID reports whether s has the syntax of a SID: S-1- followed by
+// dash-separated decimal numbers.
+func IsSID(s string) bool {
+	rest, ok := strings.CutPrefix(s, "S-1-")
+	if !ok || rest == "" {
+		return false
+	}
+	for _, part := range strings.Split(rest, "-") {
+		if part == "" || strings.Trim(part, "0123456789") != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// currentIf returns the current user if match accepts it. It covers users
+// that os/user cannot see, such as NSS users in builds without cgo.
+func currentIf(match func(*Principal) bool) (*Principal, bool) {
+	p, err := Current()
+	if err != nil || !match(p) {
+		return nil, false
+	}
+	return p, true
+}
+
+// lookupError wraps an os/user error for id.
+func lookupError(id string, err error) error {
+	var unknownUser osuser.UnknownUserError
+	var unknownID osuser.UnknownUserIdError
+	if errors.As(err, &unknownUser) || errors.As(err, &unknownID) {
+		return fmt.Errorf("%w: %q", ErrUnknownUser, id)
+	}
+	return fmt.Errorf("oscompat/user: looking up %q: %w", id, err)
+}
@@ -0,0 +1,96 @@
+package user_test
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+
+	"github.com/grokify/oscompat/user"
+)
+
+func TestLookup(t *testing.T) {
+	me, err := user.Current()
+	if err != nil {
+		t.Fatalf("Current() error: %v", err)
+	}
+	p, err := user.Lookup(me.String())
+	if err != nil {
+		t.Fatalf("Lookup(%q) error: %v", me.String(), err)
+	}
+	if p.UID != me.UID {
+		t.Errorf("Lookup(%q).UID = %q, want %q", me.String(), p.UID, me.UID)
+	}
+
+	p, err = user.LookupID(me.UID)
+	if err != nil {
+		t.Fatalf("LookupID(%q) error: %v", me.UID, err)
+	}
+	if p.Username != me.Username {
+		t.Errorf("LookupID(%q).Username = %q, want %q", me.UID, p.Username, me.Username)
+	}
+}
+
+func TestLookupUnknown(t *testing.T) {
+	_, err := user.Lookup("oscompat-no-such-user")
+	if !errors.Is(err, user.ErrUnknownUser) {
+		t.Errorf("Lookup(unknown) error = %v, want ErrUnknownUser", err)
+	}
+}
+
+func TestSIDRoundTrip(t *testing.T) {
+	me, err := user.Current()
+	if err != nil {
+		t.Fatalf("Current() error: %v", err)
+	}
+	sid, err := user.ToSID(me.Username)
+	if err != nil {
+		t.Fatalf("ToSID(%q) error: %v", me.Username, err)
+	}
+	if !user.IsSID(sid) {
+		t.Fatalf("ToSID(%q) = %q, not a SID", me.Username, sid)
+	}
+	if runtime.GOOS != "windows" && sid != "S-1-22-1-"+me.UID {
+		t.Errorf("ToSID(%q) = %q, want S-1-22-1-%s", me.Username, sid, me.UID)
+	}
+
+	p, err := user.FromSID(sid)
+	if err != nil {
+		t.Fatalf("FromSID(%q) error: %v", sid, err)
+	}
+	if p.UID != me.UID {
+		t.Errorf("FromSID(%q).UID = %q, want %q", sid, p.UID, me.UID)
+	}
+
+	if again, err := user.ToSID(sid); err != nil || again != sid {
+		t.Errorf("ToSID(%q) = %q, %v; want unchanged", sid, again, err)
+	}
+}
+
+func TestFromSIDInvalid(t *testing.T) {
+	if _, err := user.FromSID("alice"); !errors.Is(err, user.ErrInvalidSID) {
+		t.Errorf("FromSID(alice) error = %v, want ErrInvalidSID", err)
+	}
+	if runtime.GOOS != "windows" {
+		if _, err := user.FromSID("S-1-5-32-544"); !errors.Is(err, user.ErrInvalidSID) {
+			t.Errorf("FromSID(Administrators) error = %v, want ErrInvalidSID", err)
+		}
+	}
+}
+
+func TestIsSID(t *testing.T) {
+	tests := map[string]bool{
+		"S-1-5-32-544":        true,
+		"S-1-22-1-1000":       true,
+		"S-1-5-21-1-2-3-1001": true,
+		"S-1-":                false,
+		"S-1-5--1":            false,
+		"S-2-5":               false,
+		"1000":                false,
+		"S-1-5-x":             false,
+	}
+	for in, want := range tests {
+		if got := user.IsSID(in); got != want {
+			t.Errorf("IsSID(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
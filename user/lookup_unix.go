@@ -0,0 +1,43 @@
+//go:build !windows
+
+package user
+
+import (
+	"fmt"
+	osuser "os/user"
+	"strconv"
+	"strings"
+)
+
+// unixUserSIDPrefix prefixes the UID in the SIDs Samba assigns to Unix
+// users.
+const unixUserSIDPrefix = "S-1-22-1-"
+
+// lookupID looks up a numeric UID.
+func lookupID(id string) (*Principal, error) {
+	if _, err := strconv.ParseUint(id, 10, 32); err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownUser, id)
+	}
+	u, err := osuser.LookupId(id)
+	if err != nil {
+		if p, ok := currentIf(func(p *Principal) bool { return p.UID == id }); ok {
+			return p, nil
+		}
+		return nil, lookupError(id, err)
+	}
+	return fromOSUser(u), nil
+}
+
+// principalSID maps the UID of p to S-1-22-1-<uid>.
+func principalSID(p *Principal) (string, error) {
+	return unixUserSIDPrefix + p.UID, nil
+}
+
+// fromSID resolves S-1-22-1-<uid>.
+func fromSID(sid string) (*Principal, error) {
+	uid, ok := strings.CutPrefix(sid, unixUserSIDPrefix)
+	if !ok || strings.Contains(uid, "-") {
+		return nil, fmt.Errorf("%w: %q has no Unix mapping", ErrInvalidSID, sid)
+	}
+	return lookupID(uid)
+}
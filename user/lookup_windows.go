@@ -0,0 +1,27 @@
+//go:build windows
+
+package user
+
+import (
+	"fmt"
+	osuser "os/user"
+)
+
+// lookupID rejects numeric IDs; Windows identifies accounts by SID.
+func lookupID(id string) (*Principal, error) {
+	return nil, fmt.Errorf("%w: %q is not a SID", ErrUnknownUser, id)
+}
+
+// principalSID returns the account SID, which os/user reports as the UID.
+func principalSID(p *Principal) (string, error) {
+	return p.UID, nil
+}
+
+// fromSID looks up an account SID.
+func fromSID(sid string) (*Principal, error) {
+	u, err := osuser.LookupId(sid)
+	if err != nil {
+		return nil, lookupError(sid, err)
+	}
+	return fromOSUser(u), nil
+}
@@ -0,0 +1,110 @@
+// Package user provides cross-platform identity of the current user.
+//
+// The standard os/user package reports user and group IDs as numeric
+// strings on Unix and as SIDs on Windows, embeds the domain in Windows
+// usernames, and, when built without cgo, cannot see users and groups
+// served by NSS (LDAP, SSSD, systemd-homed). This package hides those
+// differences behind one Principal type and falls back to the process
+// credentials and environment when the user database has no entry.
+package user
+
+import (
+	"errors"
+	"fmt"
+	osuser "os/user"
+	"slices"
+)
+
+// ErrUnknownUser is returned when the current user cannot be determined.
+var ErrUnknownUser = errors.New("oscompat/user: unknown user")
+
+// Principal identifies a user.
+type Principal struct {
+	// Username is the login name, without any domain prefix.
+	Username string
+
+	// Domain is the Windows domain or computer name the account belongs
+	// to. It is empty on Unix.
+	Domain string
+
+	// Name is the display name, or Username if none is recorded.
+	Name string
+
+	// HomeDir is the home directory.
+	HomeDir string
+
+	// UID is the numeric user ID on Unix and the user SID on Windows.
+	UID string
+
+	// GID is the numeric primary group ID on Unix and the primary group
+	// SID on Windows.
+	GID string
+}
+
+// String returns the qualified username: DOMAIN\user on Windows when the
+// domain is known, and the username otherwise.
+func (p *Principal) String() string {
+	if p.Domain != "" {
+		return p.Domain + `\` + p.Username
+	}
+	return p.Username
+}
+
+// Group identifies a group.
+type Group struct {
+	// ID is the numeric group ID on Unix and the group SID on Windows.
+	ID string
+
+	// Name is the group name, or "" if it cannot be resolved.
+	Name string
+}
+
+// Current returns the user running the process.
+//
+// On Unix, if the user database has no entry for the process UID, the
+// Principal is built from the UID and GID, $USER or $LOGNAME, and $HOME
+// instead of failing.
+func Current() (*Principal, error) {
+	u, err := osuser.Current()
+	if err != nil {
+		return currentFallback(err)
+	}
+	return fromOSUser(u), nil
+}
+
+// Groups returns the groups of the user running the process, primary
+// group first. On Unix these are the process's real and supplementary
+// group IDs; on Windows they are the local groups of the user account.
+func Groups() ([]Group, error) {
+	ids, err := groupIDs()
+	if err != nil {
+		return nil, fmt.Errorf("oscompat/user: listing groups: %w", err)
+	}
+	groups := make([]Group, 0, len(ids))
+	for _, id := range ids {
+		g := Group{ID: id}
+		if og, err := osuser.LookupGroupId(id); err == nil {
+			g.Name = og.Name
+		}
+		groups = append(groups, g)
+	}
+	return groups, nil
+}
+
+// IsAdmin reports whether the current user has administrative rights:
+// root or a member of the sudo, wheel, or admin group on Unix, and a
+// member of the Administrators group on Windows.
+//
+// This is a property of the account, not of the running process. Under
+// Windows UAC or without sudo an administrator's processes are not
+// elevated; use process.IsElevated to check the process.
+func IsAdmin() (bool, error) {
+	if isRoot() {
+		return true, nil
+	}
+	groups, err := Groups()
+	if err != nil {
+		return false, err
+	}
+	return slices.ContainsFunc(groups, isAdminGroup), nil
+}
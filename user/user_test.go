@@ -0,0 +1,69 @@
+package user_test
+
+import (
+	"os"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"github.com/grokify/oscompat/user"
+)
+
+func TestCurrent(t *testing.T) {
+	p, err := user.Current()
+	if err != nil {
+		t.Fatalf("Current() error: %v", err)
+	}
+	if p.Username == "" || p.Name == "" || p.UID == "" {
+		t.Errorf("Current() = %+v, want username, name, and UID", p)
+	}
+	if runtime.GOOS == "windows" {
+		if len(p.UID) < 4 || p.UID[:4] != "S-1-" {
+			t.Errorf("Current().UID = %q, want a SID", p.UID)
+		}
+		return
+	}
+	if p.UID != strconv.Itoa(os.Getuid()) {
+		t.Errorf("Current().UID = %q, want %d", p.UID, os.Getuid())
+	}
+	if p.String() != p.Username {
+		t.Errorf("String() = %q, want %q", p.String(), p.Username)
+	}
+}
+
+func TestPrincipalString(t *testing.T) {
+	p := &user.Principal{Username: "alice", Domain: "CORP"}
+	if got := p.String(); got != `CORP\alice` {
+		t.Errorf("String() = %q, want CORP\\alice", got)
+	}
+}
+
+func TestGroups(t *testing.T) {
+	groups, err := user.Groups()
+	if err != nil {
+		t.Fatalf("Groups() error: %v", err)
+	}
+	if len(groups) == 0 {
+		t.Fatal("Groups() returned no groups")
+	}
+	if runtime.GOOS != "windows" && groups[0].ID != strconv.Itoa(os.Getgid()) {
+		t.Errorf("Groups()[0].ID = %q, want primary GID %d", groups[0].ID, os.Getgid())
+	}
+	seen := make(map[string]bool)
+	for _, g := range groups {
+		if seen[g.ID] {
+			t.Errorf("Groups() lists %q twice", g.ID)
+		}
+		seen[g.ID] = true
+	}
+}
+
+func TestIsAdmin(t *testing.T) {
+	admin, err := user.IsAdmin()
+	if err != nil {
+		t.Fatalf("IsAdmin() error: %v", err)
+	}
+	if runtime.GOOS != "windows" && os.Getuid() == 0 && !admin {
+		t.Error("IsAdmin() = false for root")
+	}
+}
@@ -0,0 +1,77 @@
+//go:build !windows
+
+package user
+
+import (
+	"fmt"
+	"os"
+	osuser "os/user"
+	"slices"
+	"strconv"
+	"syscall"
+)
+
+// adminGroups are the group names that grant sudo rights on common
+// distributions and macOS.
+var adminGroups = []string{"sudo", "wheel", "admin"}
+
+// fromOSUser converts an os/user entry.
+func fromOSUser(u *osuser.User) *Principal {
+	name := u.Name
+	if name == "" {
+		name = u.Username
+	}
+	return &Principal{
+		Username: u.Username,
+		Name:     name,
+		HomeDir:  u.HomeDir,
+		UID:      u.Uid,
+		GID:      u.Gid,
+	}
+}
+
+// currentFallback builds the Principal from the process credentials and
+// environment when the user database has no entry.
+func currentFallback(err error) (*Principal, error) {
+	username := os.Getenv("USER")
+	if username == "" {
+		username = os.Getenv("LOGNAME")
+	}
+	if username == "" {
+		return nil, fmt.Errorf("%w: %w", ErrUnknownUser, err)
+	}
+	home, _ := os.UserHomeDir()
+	return &Principal{
+		Username: username,
+		Name:     username,
+		HomeDir:  home,
+		UID:      strconv.Itoa(os.Getuid()),
+		GID:      strconv.Itoa(os.Getgid()),
+	}, nil
+}
+
+// groupIDs returns the real GID followed by the supplementary GIDs.
+func groupIDs() ([]string, error) {
+	gids, err := syscall.Getgroups()
+	if err != nil {
+		return nil, err
+	}
+	gid := os.Getgid()
+	ids := []string{strconv.Itoa(gid)}
+	for _, g := range gids {
+		if g != gid {
+			ids = append(ids, strconv.Itoa(g))
+		}
+	}
+	return ids, nil
+}
+
+// isRoot reports whether the process runs as UID 0.
+func isRoot() bool {
+	return os.Getuid() == 0
+}
+
+// isAdminGroup reports whether g grants sudo rights.
+func isAdminGroup(g Group) bool {
+	return slices.Contains(adminGroups, g.Name)
+}
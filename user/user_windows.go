@@ -0,0 +1,67 @@
+//go:build windows
+
+package user
+
+import (
+	osuser "os/user"
+	"strings"
+)
+
+// administratorsSID is the well-known SID of BUILTIN\Administrators.
+const administratorsSID = "S-1-5-32-544"
+
+// fromOSUser converts an os/user entry, splitting DOMAIN\user.
+func fromOSUser(u *osuser.User) *Principal {
+	domain, username, ok := strings.Cut(u.Username, `\`)
+	if !ok {
+		domain, username = "", u.Username
+	}
+	name := u.Name
+	if name == "" {
+		name = username
+	}
+	return &Principal{
+		Username: username,
+		Domain:   domain,
+		Name:     name,
+		HomeDir:  u.HomeDir,
+		UID:      u.Uid,
+		GID:      u.Gid,
+	}
+}
+
+// currentFallback reports the os/user error; Windows does not depend on
+// cgo to look up the current user, so there is nothing to fall back to.
+func currentFallback(err error) (*Principal, error) {
+	return nil, err
+}
+
+// groupIDs returns the SIDs of the user's local groups, primary group
+// first.
+func groupIDs() ([]string, error) {
+	u, err := osuser.Current()
+	if err != nil {
+		return nil, err
+	}
+	ids, err := u.GroupIds()
+	if err != nil {
+		return nil, err
+	}
+	out := []string{u.Gid}
+	for _, id := range ids {
+		if id != u.Gid {
+			out = append(out, id)
+		}
+	}
+	return out, nil
+}
+
+// isRoot is always false; Windows has no all-powerful account ID.
+func isRoot() bool {
+	return false
+}
+
+// isAdminGroup reports whether g is BUILTIN\Administrators.
+func isAdminGroup(g Group) bool {
+	return g.ID == administratorsSID
+}